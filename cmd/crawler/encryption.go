@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
+)
+
+// passphraseEnv holds the passphrase of an encrypted database for runs
+// without a terminal (cron, CI)
+const passphraseEnv = "CRAWLER_DB_PASSPHRASE"
+
+// setupPassphrase unlocks encrypted databases with passphraseEnv, or asks on
+// the terminal the first time one is opened
+func setupPassphrase() {
+	storage.SetPassphrase(os.Getenv(passphraseEnv))
+	storage.SetPassphrasePrompt(func(dbPath string) (string, error) {
+		return readPassphrase(fmt.Sprintf("🔐 Passphrase của %s: ", dbPath))
+	})
+}
+
+// readPassphrase asks for a passphrase on the terminal without echo
+func readPassphrase(prompt string) (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("%w: set %s", storage.ErrDatabaseLocked, passphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	value, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(value), nil
+}
+
+// readNewPassphrase asks for a new passphrase twice
+func readNewPassphrase() string {
+	value, err := readPassphrase("🔐 Passphrase mới: ")
+	if err != nil {
+		fatalf("❌ %v", err)
+	}
+	again, err := readPassphrase("🔐 Nhập lại passphrase: ")
+	if err != nil {
+		fatalf("❌ %v", err)
+	}
+	if value != again {
+		fatalf("❌ Hai passphrase không khớp")
+	}
+	return value
+}
+
+// runEncryption handles `crawler encryption [status] | enable | change | disable`
+func runEncryption(cfg models.Config, args []string, takeover bool) {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	if len(args) > 1 {
+		log.Fatalf("❌ Usage: crawler encryption [status] | enable | change | disable")
+	}
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	if action == "status" {
+		encrypted, err := storage.IsEncrypted(cfg.DBPath)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if encrypted {
+			fmt.Printf("🔐 Results trong %s được mã hoá\n", cfg.DBPath)
+		} else {
+			fmt.Printf("🔓 Results trong %s không mã hoá (bật bằng `crawler encryption enable`)\n", cfg.DBPath)
+		}
+		return
+	}
+
+	lock := acquireInstanceLock(cfg, takeover)
+	defer lock.Release()
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	switch action {
+	case "enable":
+		// Passphrase trong biến môi trường được dùng luôn, không hỏi lại
+		value := os.Getenv(passphraseEnv)
+		if value == "" {
+			value = readNewPassphrase()
+		}
+		count, err := emailStorage.EnableEncryption(value)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🔐 Đã mã hoá %d results. Không có passphrase thì không đọc lại được: hãy lưu nó cẩn thận\n", count)
+	case "change":
+		if !emailStorage.Encrypted() {
+			fatalf("❌ Database chưa mã hoá: dùng `crawler encryption enable`")
+		}
+		count, err := emailStorage.ChangePassphrase(readNewPassphrase())
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🔐 Đã đổi passphrase, mã hoá lại %d results\n", count)
+	case "disable":
+		count, err := emailStorage.DisableEncryption()
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🔓 Đã giải mã %d results, tắt mã hoá\n", count)
+	default:
+		fatalf("❌ Usage: crawler encryption [status] | enable | change | disable")
+	}
+}
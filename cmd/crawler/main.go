@@ -1,37 +1,295 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/x/term"
+
 	"linkedin-crawler/internal/config"
+	"linkedin-crawler/internal/crawler"
+	"linkedin-crawler/internal/integrations"
+	"linkedin-crawler/internal/licensing"
+	"linkedin-crawler/internal/models"
 	"linkedin-crawler/internal/orchestrator"
 	"linkedin-crawler/internal/storage"
 	"linkedin-crawler/internal/utils"
 )
 
 func main() {
+	// --progress=json: NDJSON progress trên stdout, mọi output khác chuyển sang stderr
+	args, progressJSON := extractProgressFlag(os.Args[1:])
+	progressOut := os.Stdout
+	if progressJSON {
+		os.Stdout = os.Stderr
+	}
+
+	// -q/--quiet chỉ in lỗi và cảnh báo (cron, CI); -v/-vv in thêm log từng email/request
+	args, level := extractVerbosityFlags(args)
+	utils.SetConsoleLevel(level)
+	if level == utils.ConsoleQuiet {
+		restoreConsole, err := utils.FilterQuietConsole()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		defer restoreConsole()
+	}
+
+	// Results được mã hoá: passphrase từ biến môi trường hoặc hỏi trên terminal
+	setupPassphrase()
+
 	fmt.Println("🚀 LinkedIn Auto Crawler - Refactored Version")
 	fmt.Println(strings.Repeat("=", 60))
 
 	// Load configuration
 	cfg := config.DefaultConfig()
 
+	// --takeover: chiếm lock của instance khác đang giữ emails.db/tokens.txt
+	args, takeover := extractFlag(args, "--takeover")
+
+	// --tui: giao diện terminal với progress bar, token, hit gần nhất và phím tắt
+	args, useTUI := extractFlag(args, "--tui")
+	if useTUI && (progressJSON || level == utils.ConsoleQuiet) {
+		log.Fatalf("❌ --tui không dùng chung được với --progress=json hoặc -q")
+	}
+
+	// --emails <file | - | https://...>: nguồn emails cho lần crawl, --sha256 kiểm tra checksum
+	args, emailsSource := extractValue(args, "--emails")
+	args, checksum := extractValue(args, "--sha256")
+
+	// --simulate: kết quả giả, không đăng nhập và không gọi mạng (demo, thử GUI)
+	args, hitRate := extractValue(args, "--simulate-hit-rate")
+	args, latency := extractValue(args, "--simulate-latency")
+	args, cfg.Simulate = extractFlag(args, "--simulate")
+	if hitRate != "" {
+		rate, err := strconv.ParseFloat(hitRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			log.Fatalf("❌ --simulate-hit-rate phải nằm trong khoảng 0-1")
+		}
+		cfg.SimulateHitRate = rate
+	}
+	if latency != "" {
+		duration, err := time.ParseDuration(latency)
+		if err != nil || duration < 0 {
+			log.Fatalf("❌ --simulate-latency không hợp lệ: %s", latency)
+		}
+		cfg.SimulateLatency = duration
+	}
+
+	// --extended-profile: lưu thêm headline và company (cần PRO license)
+	args, cfg.ExtendedProfile = extractFlag(args, "--extended-profile")
+
+	// --memory-limit <MB>: gần giới hạn thì dọn cache và giảm số worker
+	args, memoryLimit := extractValue(args, "--memory-limit")
+	if memoryLimit != "" {
+		limit, err := strconv.Atoi(memoryLimit)
+		if err != nil || (limit != 0 && limit < 128) {
+			log.Fatalf("❌ --memory-limit phải là 0 (không giới hạn) hoặc từ 128 MB trở lên")
+		}
+		cfg.MemoryLimitMB = limit
+	}
+
+	// --cache-ttl <duration>: dùng lại response 200 trong thời gian này khi chạy lại danh sách trùng
+	args, cacheTTL := extractValue(args, "--cache-ttl")
+	if cacheTTL != "" {
+		ttl, err := time.ParseDuration(cacheTTL)
+		if err != nil || ttl < 0 {
+			log.Fatalf("❌ --cache-ttl không hợp lệ: %s", cacheTTL)
+		}
+		cfg.ResponseCacheTTL = ttl
+	}
+
+	// --retention-days <n>: khi bắt đầu crawl xoá dữ liệu cũ hơn n ngày
+	args, retentionDays := extractValue(args, "--retention-days")
+	if retentionDays != "" {
+		days, err := strconv.Atoi(retentionDays)
+		if err != nil || days < 0 {
+			log.Fatalf("❌ --retention-days phải là số ngày >= 0")
+		}
+		cfg.RetentionDays = days
+	}
+
+	// --log-privacy mask|hash: che email trong console và log file, database giữ nguyên
+	args, logPrivacy := extractValue(args, "--log-privacy")
+	if logPrivacy != "" {
+		if !slices.Contains(models.LogPrivacyModes, logPrivacy) {
+			log.Fatalf("❌ --log-privacy phải là một trong: %s", strings.Join(models.LogPrivacyModes, ", "))
+		}
+		cfg.LogPrivacy = logPrivacy
+	}
+	// TUI tự che email: filter dòng sẽ làm hỏng màn hình của TUI
+	if cfg.LogPrivacy != models.LogPrivacyOff && !useTUI {
+		restorePrivate, err := utils.FilterPrivateConsole(cfg.LogPrivacy)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		defer restorePrivate()
+	}
+
+	// --token-sources file,accounts,manual: nguồn token theo thứ tự ưu tiên
+	args, tokenSources := extractValue(args, "--token-sources")
+	if tokenSources != "" {
+		if _, err := models.ParseTokenSources(tokenSources); err != nil {
+			log.Fatalf("❌ --token-sources: %v", err)
+		}
+		cfg.TokenSources = tokenSources
+	}
+
+	// --token-provider <url>: lấy tokens từ token farm, header xác thực trong
+	// CRAWLER_TOKEN_PROVIDER_AUTH; không có --token-sources thì hỏi farm sau tokens file
+	args, tokenProvider := extractValue(args, "--token-provider")
+	if tokenProvider != "" {
+		cfg.TokenProviderURL = tokenProvider
+		cfg.TokenProviderAuth = os.Getenv(tokenProviderAuthEnv)
+		if tokenSources == "" {
+			cfg.TokenSources = strings.Join([]string{models.TokenSourceFile, models.TokenSourceProvider, models.TokenSourceAccounts}, ",")
+		}
+	}
+
+	// --max-emails <n> / --max-accounts <n>: giới hạn của lần chạy này
+	args, maxEmails := extractValue(args, "--max-emails")
+	if maxEmails != "" {
+		n, err := strconv.Atoi(maxEmails)
+		if err != nil || n < 0 {
+			log.Fatalf("❌ --max-emails phải là số emails >= 0")
+		}
+		cfg.MaxEmailsPerRun = n
+	}
+	args, maxAccounts := extractValue(args, "--max-accounts")
+	if maxAccounts != "" {
+		n, err := strconv.Atoi(maxAccounts)
+		if err != nil || n < 0 {
+			log.Fatalf("❌ --max-accounts phải là số accounts >= 0")
+		}
+		cfg.MaxAccountsPerRun = n
+	}
+
+	// --no-sleep: thoát ngay khi đã lưu xong trạng thái, bỏ qua SleepDuration
+	args, noSleep := extractFlag(args, "--no-sleep")
+	if noSleep {
+		cfg.SleepDuration = 0
+	}
+
+	// --profile <name>: concurrency và requests/s từ profile đã lưu bằng `tune --save`
+	args, profileName := extractValue(args, "--profile")
+	if profileName != "" {
+		cfg = applyTuningProfile(cfg, profileName)
+	}
+
+	// Subcommands
+	if len(args) > 0 {
+		switch args[0] {
+		case "crawl":
+			// Giống chạy không có subcommand
+		case "enrich":
+			runEnrich(cfg, args[1:], takeover)
+			return
+		case "report":
+			runReport(cfg, args[1:])
+			return
+		case "db":
+			runDB(cfg, args[1:], takeover)
+			return
+		case "events":
+			runEvents(cfg, args[1:])
+			return
+		case "runs":
+			runRuns(cfg, args[1:])
+			return
+		case "merge":
+			runMerge(args[1:])
+			return
+		case "split":
+			runSplit(args[1:])
+			return
+		case "export-new":
+			runExportNew(cfg, args[1:])
+			return
+		case "license":
+			runLicense(args[1:])
+			return
+		case "imap":
+			runIMAP(cfg, args[1:])
+			return
+		case "add":
+			runAdd(cfg, args[1:])
+			return
+		case "doctor":
+			runDoctor(cfg, args[1:])
+			return
+		case "init":
+			runInit(args[1:])
+			return
+		case "tune":
+			runTune(cfg, args[1:], takeover)
+			return
+		case "campaigns":
+			runCampaigns(cfg, args[1:], takeover)
+			return
+		case "purge":
+			runPurge(cfg, args[1:], takeover)
+			return
+		case "encryption":
+			runEncryption(cfg, args[1:], takeover)
+			return
+		case "project":
+			runProject(cfg, args[1:])
+			return
+		}
+	}
+
+	if emailsSource != "" {
+		cfg = useEmailsSource(cfg, emailsSource, checksum)
+	}
+
+	checkConfig(cfg)
+
+	lock := acquireInstanceLock(cfg, takeover)
+	defer lock.Release()
+
+	// Output của crawler hiện trong TUI thay vì ghi đè lên nó
+	var console *consoleCapture
+	if useTUI {
+		var err error
+		if console, err = captureConsole(); err != nil {
+			fatalf("❌ %v", err)
+		}
+	}
+
 	// Create auto crawler
 	autoCrawler, err := orchestrator.New(cfg)
 	if err != nil {
-		log.Fatalf("❌ Lỗi khởi tạo auto crawler: %v", err)
+		if console != nil {
+			console.Restore()
+		}
+		fatalf("❌ Lỗi khởi tạo auto crawler: %v", err)
 	}
-	emailStorage, _, _ := autoCrawler.GetStorageServices()
-	if err := dropEmailsTable(emailStorage); err != nil {
-		log.Fatalf("❌ %v", err)
+	if progressJSON {
+		autoCrawler.SetProgressWriter(progressOut)
+	}
+	// Nguồn token manual hỏi trên terminal; TUI giữ bàn phím nên không hỏi được
+	if console == nil && term.IsTerminal(os.Stdin.Fd()) {
+		autoCrawler.GetBatchProcessor().SetTokenPrompt(promptTokens)
 	}
 	// Start crawling
 	startTime := time.Now()
-	err = autoCrawler.Run()
+	if console != nil {
+		err = runWithTUI(autoCrawler, console)
+		console.Restore()
+	} else {
+		err = autoCrawler.Run()
+	}
 	duration := time.Since(startTime)
 
 	if err != nil {
@@ -50,11 +308,1351 @@ func main() {
 	fmt.Println(strings.Repeat("=", 60))
 }
 
-func dropEmailsTable(es *storage.EmailStorage) error {
-	// Execute DROP TABLE IF EXISTS
-	if _, err := es.GetDB().Exec("DROP TABLE IF EXISTS emails"); err != nil {
-		return fmt.Errorf("failed to drop existing emails table: %w", err)
+// checkConfig prints every config problem with its suggested fix and exits
+// when any of them is an error
+func checkConfig(cfg models.Config) {
+	problems := config.Validate(cfg)
+	for _, p := range problems.Warnings() {
+		fmt.Printf("⚠️ %s\n", p)
+	}
+	errs := problems.Errors()
+	if len(errs) == 0 {
+		return
+	}
+	for _, p := range errs {
+		fmt.Printf("❌ %s\n", p)
+	}
+	log.Fatalf("❌ Config không hợp lệ: %d lỗi", len(errs))
+}
+
+// runEnrich re-crawls existing hits (a hit file, or the results table with
+// --results) and writes a diff report
+func runEnrich(cfg models.Config, args []string, takeover bool) {
+	args, fromResults := extractFlag(args, "--results")
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	checkConfig(cfg)
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	lock := acquireInstanceLock(cfg, takeover)
+	defer lock.Release()
+
+	enricher := orchestrator.NewEnricher(cfg)
+	defer enricher.Close()
+
+	var report *orchestrator.EnrichReport
+	if fromResults {
+		report, err = enricher.RunResults()
+	} else {
+		hitFile := cfg.OutputFilePath
+		if len(args) > 0 {
+			hitFile = args[0]
+		}
+		report, err = enricher.Run(hitFile)
+	}
+	if err != nil {
+		fatalf("❌ Enrich thất bại: %v", err)
+	}
+
+	fmt.Printf("📊 %d/%d profiles có thay đổi (%d field changes)\n", report.Changed, report.Total, len(report.Changes))
+}
+
+// runReport writes the per-domain summary as CSV and markdown, or the
+// session report with `report session`
+func runReport(cfg models.Config, args []string) {
+	if len(args) > 0 && args[0] == "session" {
+		runSessionReport(cfg, args[1:])
+		return
+	}
+
+	prefix := fmt.Sprintf("domain-report-%s", time.Now().Format("20060102-150405"))
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		log.Fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	stats, err := emailStorage.GetDomainStats()
+	if err != nil {
+		log.Fatalf("❌ Không thể tạo report: %v", err)
+	}
+	if hits, err := utils.ReadAllHitResults(cfg.OutputFilePath); err == nil {
+		utils.ApplyHitProfileStats(stats, hits)
+	}
+
+	if err := utils.WriteDomainReportCSV(prefix+".csv", stats); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if err := utils.WriteDomainReportMarkdown(prefix+".md", stats); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	fmt.Printf("📊 Domain report (%d domains): %s.csv, %s.md\n", len(stats), prefix, prefix)
+}
+
+// runSessionReport handles `report session [--pdf] [--run <id>] [file]`
+func runSessionReport(cfg models.Config, args []string) {
+	args, asPDF := extractFlag(args, "--pdf")
+	runID := ""
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--run" && i+1 < len(args) {
+			runID = args[i+1]
+			i++
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		log.Fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	report, err := emailStorage.GetSessionReport(runID, utils.SessionReportTopDomains, cfg.OutputFilePath)
+	if err != nil {
+		log.Fatalf("❌ Không thể tạo session report: %v", err)
+	}
+	licensing.NewLicensedCrawlerWrapper().FillSessionReport(&report)
+
+	ext := "html"
+	if asPDF || (len(rest) > 0 && strings.EqualFold(filepath.Ext(rest[0]), ".pdf")) {
+		ext = "pdf"
+	}
+	outPath := utils.SessionReportFileName(report, ext)
+	if len(rest) > 0 {
+		outPath = rest[0]
+	}
+
+	var content []byte
+	if ext == "pdf" {
+		content = utils.FormatSessionReportPDF(report)
+	} else {
+		html, err := utils.FormatSessionReportHTML(report)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		content = []byte(html)
+	}
+	if err := utils.WriteFileAtomic(outPath, content); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	fmt.Printf("📄 Session report (run %s, %d processed, hit rate %.1f%%): %s\n",
+		report.RunID, report.Processed, report.HitRate(), outPath)
+}
+
+// runDB handles `db backup [file]`, `db restore <file>`, `db vacuum`,
+// `db snapshot`, `db snapshots`, `db errors`, `db requeue` and `db clear-cache`
+func runDB(cfg models.Config, args []string, takeover bool) {
+	if len(args) == 0 {
+		log.Fatalf("❌ Usage: crawler db backup [file] | restore <file> | vacuum | snapshot | snapshots | errors | requeue <class,...> | clear-cache")
+	}
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	// Restore/vacuum ghi đè database, requeue đổi status → cần lock
+	if args[0] == "restore" || args[0] == "vacuum" || args[0] == "requeue" {
+		lock := acquireInstanceLock(cfg, takeover)
+		defer lock.Release()
+	}
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	switch args[0] {
+	case "backup":
+		dest := fmt.Sprintf("backups/emails-%s.db", time.Now().Format("20060102-150405"))
+		if len(args) > 1 {
+			dest = args[1]
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			fatalf("❌ %v", err)
+		}
+		if err := emailStorage.BackupDB(dest); err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("💾 Đã backup %s → %s\n", cfg.DBPath, dest)
+	case "restore":
+		if len(args) < 2 {
+			fatalf("❌ Usage: crawler db restore <file>")
+		}
+		if err := emailStorage.RestoreDB(args[1]); err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("♻️ Đã restore %s từ %s\n", cfg.DBPath, args[1])
+	case "vacuum":
+		before, after, err := emailStorage.VacuumDB()
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🧹 Vacuum xong: %d KB → %d KB\n", before/1024, after/1024)
+	case "snapshot":
+		snapshot, err := emailStorage.CreateSnapshot(cfg.BackupDir)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("💾 Snapshot %s (%d KB)\n", snapshot.Dir, snapshot.DBSize/1024)
+		if removed, err := storage.PruneSnapshots(cfg.BackupDir, cfg.BackupKeep); err != nil {
+			fatalf("❌ %v", err)
+		} else if removed > 0 {
+			fmt.Printf("🧹 Đã xóa %d snapshot cũ (giữ %d)\n", removed, cfg.BackupKeep)
+		}
+	case "snapshots":
+		snapshots, err := storage.ListSnapshots(cfg.BackupDir)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		if len(snapshots) == 0 {
+			fmt.Printf("📭 Chưa có snapshot trong %s\n", cfg.BackupDir)
+		}
+		for _, snapshot := range snapshots {
+			fmt.Printf("%s  %8d KB  %s\n", snapshot.CreatedAt.Format("2006-01-02 15:04:05"), snapshot.DBSize/1024, snapshot.DBPath())
+		}
+	case "errors":
+		classes, err := emailStorage.GetErrorClassStats()
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		if len(classes) == 0 {
+			fmt.Println("✅ Không có email failed")
+		}
+		for _, class := range models.EmailErrorClasses {
+			if classes[class] > 0 {
+				fmt.Printf("%-12s %8d  %s\n", class, classes[class], models.EmailErrorLabel(class))
+			}
+		}
+	case "requeue":
+		if len(args) < 2 {
+			fatalf("❌ Usage: crawler db requeue <class,...> (%s)", strings.Join(models.EmailErrorClasses, ", "))
+		}
+		classes := strings.Split(args[1], ",")
+		for _, class := range classes {
+			if !slices.Contains(models.EmailErrorClasses, class) {
+				fatalf("❌ Unknown error class: %s (%s)", class, strings.Join(models.EmailErrorClasses, ", "))
+			}
+		}
+		n, err := emailStorage.RequeueFailedByErrorClass(classes)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🔄 Đã đưa %d emails failed (%s) về pending\n", n, args[1])
+	case "clear-cache":
+		n, err := emailStorage.PurgeResponseCache(0)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🧹 Đã xoá %d response trong cache\n", n)
+	default:
+		fatalf("❌ Unknown db command: %s", args[0])
+	}
+}
+
+// runEvents prints the status history of one email from email_events
+func runEvents(cfg models.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatalf("❌ Usage: crawler events <email>")
+	}
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		log.Fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	events, err := emailStorage.GetEmailEvents(args[0])
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if len(events) == 0 {
+		fmt.Printf("📭 Không có lịch sử cho %s\n", args[0])
+		return
+	}
+
+	for _, e := range events {
+		fmt.Printf("%s  %-8s → %-8s  http=%d  token=%s  %s\n",
+			e.CreatedAt, e.OldStatus, e.NewStatus, e.HTTPStatus, e.TokenHash, e.Error)
+	}
+}
+
+// runRuns handles `runs [n]`: metrics of the latest runs, newest first
+func runRuns(cfg models.Config, args []string) {
+	limit := 20
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			log.Fatalf("❌ Usage: crawler runs [n]")
+		}
+		limit = n
+	}
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		log.Fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	runs, err := emailStorage.GetRuns(limit)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if len(runs) == 0 {
+		fmt.Println("📭 Chưa có lần chạy nào được ghi lại")
+		return
+	}
+
+	fmt.Printf("%-15s  %-16s  %9s  %9s  %10s  %8s  %11s  %6s\n",
+		"run id", "started", "duration", "processed", "emails/h", "hit rate", "tokens/acct", "429")
+	for _, r := range runs {
+		runID := r.RunID
+		if runID == "" {
+			runID = "-"
+		}
+		// Lần chạy chưa lưu lần cuối: đang chạy hoặc bị ngắt giữa chừng
+		unfinished := ""
+		if r.Unfinished {
+			unfinished = "  (unfinished)"
+		}
+		fmt.Printf("%-15s  %-16s  %9s  %9d  %10.0f  %7.1f%%  %11.2f  %5.1f%%%s\n",
+			runID, r.StartedAt.Format("2006-01-02 15:04"), r.Duration().Round(time.Minute), r.Processed,
+			r.EmailsPerHour(), r.HitRate(), r.TokensPerAccount(), r.RateLimitRate(), unfinished)
+	}
+}
+
+// runMerge handles `merge -o <out> [--by-url] <file>...`
+func runMerge(args []string) {
+	args, byURL := extractFlag(args, "--by-url")
+
+	outPath := fmt.Sprintf("merged-%s.txt", time.Now().Format("20060102-150405"))
+	var inputs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-o" && i+1 < len(args) {
+			outPath = args[i+1]
+			i++
+			continue
+		}
+		inputs = append(inputs, args[i])
+	}
+	if len(inputs) == 0 {
+		log.Fatalf("❌ Usage: crawler merge -o <out> [--by-url] <file1> <file2> ...")
+	}
+
+	report, err := utils.MergeHitFiles(inputs, outPath, utils.MergeOptions{DedupeByURL: byURL})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	reportPath := strings.TrimSuffix(outPath, filepath.Ext(outPath)) + "-report.md"
+	if err := utils.WriteFileAtomic(reportPath, []byte(utils.FormatMergeReport(report))); err != nil {
+		log.Fatalf("❌ %v", err)
 	}
-	fmt.Println("✅ Dropped existing emails table")
+
+	for _, f := range report.Files {
+		if f.Error != "" {
+			fmt.Printf("⚠️ %s: %s\n", f.Path, f.Error)
+			continue
+		}
+		fmt.Printf("📄 %s: %d đọc, %d giữ lại\n", f.Path, f.Read, f.Kept)
+	}
+	fmt.Printf("🔀 Merged %d entries → %s (trùng email: %d, trùng URL: %d, xung đột: %d)\n",
+		report.Output, outPath, report.EmailDuplicates, report.URLDuplicates, len(report.Conflicts))
+	fmt.Printf("📝 Report: %s\n", reportPath)
+}
+
+// runSplit handles `split <file> -n <shards> [--by-domain] [-o <dir>]`
+func runSplit(args []string) {
+	args, byDomain := extractFlag(args, "--by-domain")
+	args, shardsArg := extractValue(args, "-n")
+	args, outDir := extractValue(args, "-o")
+
+	shards, err := strconv.Atoi(shardsArg)
+	if len(args) != 1 || err != nil {
+		log.Fatalf("❌ Usage: crawler split <file> -n <shards> [--by-domain] [-o <dir>]")
+	}
+
+	report, err := utils.SplitEmailFile(args[0], utils.SplitOptions{Shards: shards, ByDomain: byDomain, OutDir: outDir})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	for _, shard := range report.Shards {
+		fmt.Printf("📄 %s: %d dòng, %d domains\n", shard.File, shard.Count, shard.Domains)
+	}
+	fmt.Printf("✂️ Đã chia %d dòng thành %d shards (bỏ %d dòng trùng)\n",
+		report.Read-report.Duplicates, len(report.Shards), report.Duplicates)
+}
+
+// runExportNew handles `export-new [file.csv|file.jsonl] [--reset] [--notes]`
+func runExportNew(cfg models.Config, args []string) {
+	args, reset := extractFlag(args, "--reset")
+	args, withNotes := extractFlag(args, "--notes")
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if reset {
+		state, err := utils.LoadExportState(cfg.OutputFilePath)
+		if err == nil {
+			err = state.Reset()
+		}
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Println("🔄 Export watermark reset")
+		if len(args) == 0 {
+			return
+		}
+	}
+
+	outPath := fmt.Sprintf("new-hits-%s.csv", time.Now().Format("20060102-150405"))
+	if len(args) > 0 {
+		outPath = args[0]
+	}
+
+	count, err := utils.ExportNewHits(cfg.OutputFilePath, outPath, annotateHits(cfg, withNotes))
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if count == 0 {
+		fmt.Println("📭 Không có kết quả mới kể từ lần export trước")
+		return
+	}
+	fmt.Printf("📤 Exported %d new hits → %s\n", count, outPath)
+}
+
+// annotateHits adds the workflow status, tags, notes (withNotes) and custom
+// input fields from the database to exported hits; without the database the
+// export still runs with the hit file columns
+func annotateHits(cfg models.Config, withNotes bool) func(entries []utils.HitResult) {
+	return func(entries []utils.HitResult) {
+		storage.SetDefaultDBPath(cfg.DBPath)
+		emailStorage := storage.NewEmailStorage()
+		if err := emailStorage.InitDB(); err != nil {
+			fmt.Printf("⚠️ Không thể mở database, export không có status/tags/custom fields: %v\n", err)
+			return
+		}
+		defer emailStorage.CloseDB()
+
+		if err := emailStorage.AnnotateHits(entries); err != nil {
+			fmt.Printf("⚠️ Export không có status/tags/custom fields: %v\n", err)
+		}
+		if !withNotes {
+			utils.DropNotes(entries)
+		}
+	}
+}
+
+// runIMAP handles `imap [--dry-run]`: harvest addresses from the configured
+// mailbox and append the new ones to the emails file
+func runIMAP(cfg models.Config, args []string) {
+	_, dryRun := extractFlag(args, "--dry-run")
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	fmt.Printf("📬 Đang đọc %s/%s (%d ngày gần đây)...\n", cfg.IMAPServer, cfg.IMAPMailbox, cfg.IMAPSinceDays)
+	harvest, err := integrations.HarvestIMAP(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	fmt.Printf("📨 %d messages, %d địa chỉ, %d bị lọc, %d emails duy nhất\n",
+		harvest.Messages, harvest.Addresses, harvest.Filtered, len(harvest.Emails))
+
+	if dryRun {
+		for _, email := range harvest.Emails {
+			fmt.Println(email)
+		}
+		return
+	}
+
+	added, err := utils.AppendNewEmails(cfg.EmailsFilePath, harvest.Emails)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	fmt.Printf("📥 Đã thêm %d emails mới vào %s (%d đã có sẵn)\n", added, cfg.EmailsFilePath, len(harvest.Emails)-added)
+}
+
+// runAdd handles `add <file|->`: insert the rows as pending so a running
+// crawl picks them up, and append new emails to the emails file for later runs
+func runAdd(cfg models.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatalf("❌ Usage: crawler add <file|->")
+	}
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	var data []byte
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		log.Fatalf("❌ Không thể đọc %s: %v", args[0], err)
+	}
+	lines := strings.Split(string(data), "\n")
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		log.Fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	added, invalid, err := emailStorage.ImportRows(lines)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	fmt.Printf("📥 Đã thêm %d dòng pending vào database (%d không hợp lệ), crawl đang chạy sẽ nhận trong vài giây\n", added, invalid)
+
+	var emails []string
+	for _, line := range lines {
+		emails = append(emails, utils.ExtractEmailsFromLine(line)...)
+	}
+	// File bị khoá (emails_file_edits=lock) khi đang crawl: emails vẫn nằm trong database
+	appended, err := utils.AppendNewEmails(cfg.EmailsFilePath, emails)
+	if err != nil {
+		fmt.Printf("⚠️ Không thể ghi vào %s: %v\n", cfg.EmailsFilePath, err)
+		return
+	}
+	fmt.Printf("📝 Đã thêm %d emails mới vào %s\n", appended, cfg.EmailsFilePath)
+}
+
+// runInit handles `init [dir] [--force]`: create a campaign folder with
+// commented sample accounts and input files
+func runInit(args []string) {
+	args, force := extractFlag(args, "--force")
+	if len(args) > 1 {
+		log.Fatalf("❌ Usage: crawler init [dir] [--force]")
+	}
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	layout, err := storage.InitCampaign(dir, force)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	for _, path := range layout.Created {
+		fmt.Printf("✅ Đã tạo %s\n", path)
+	}
+	for _, path := range layout.Skipped {
+		fmt.Printf("⏭️ Giữ nguyên %s (đã có, dùng --force để ghi đè)\n", path)
+	}
+	fmt.Printf("📁 Sửa accounts.txt và emails.txt rồi chạy crawler trong thư mục %s\n", dir)
+}
+
+// runTune handles `tune [-n <emails>] [--save <name>] | tune --list`: crawl a
+// calibration burst with the current settings, then recommend concurrency and
+// requests/sec and optionally save them as a profile for --profile
+func runTune(cfg models.Config, args []string, takeover bool) {
+	args, countArg := extractValue(args, "-n")
+	args, name := extractValue(args, "--save")
+	args, list := extractFlag(args, "--list")
+	if len(args) > 0 {
+		log.Fatalf("❌ Usage: crawler tune [-n <emails>] [--save <name>] | tune --list")
+	}
+
+	if list {
+		resolved, err := utils.ResolveConfigPaths(cfg)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		storage.SetDefaultDBPath(resolved.DBPath)
+		emailStorage := storage.NewEmailStorage()
+		defer emailStorage.CloseDB()
+		profiles, err := emailStorage.GetTuningProfiles()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if len(profiles) == 0 {
+			fmt.Println("Chưa có profile nào, chạy `crawler tune --save <name>`")
+			return
+		}
+		for _, p := range profiles {
+			fmt.Printf("%-20s concurrency %-3d %5.1f req/s | %d emails, 429 %d/%d, latency %s | %s\n",
+				p.Name, p.MaxConcurrency, p.RequestsPerSec, p.Emails, p.RateLimited, p.Requests,
+				p.AvgLatency, p.UpdatedAt.Local().Format("2006-01-02 15:04"))
+		}
+		return
+	}
+
+	emails := orchestrator.DefaultCalibrationEmails
+	if countArg != "" {
+		n, err := strconv.Atoi(countArg)
+		if err != nil || n < 1 {
+			log.Fatalf("❌ -n phải là số emails > 0")
+		}
+		emails = n
+	}
+
+	checkConfig(cfg)
+	lock := acquireInstanceLock(cfg, takeover)
+	defer lock.Release()
+
+	autoCrawler, err := orchestrator.New(cfg)
+	if err != nil {
+		fatalf("❌ Lỗi khởi tạo auto crawler: %v", err)
+	}
+	autoCrawler.SetEmailLimit(emails)
+	fmt.Printf("🎛️ Calibration: crawl %d emails với concurrency %d, %.1f req/s\n", emails, cfg.MaxConcurrency, cfg.RequestsPerSec)
+	if err := autoCrawler.Run(); err != nil {
+		log.Printf("❌ Lỗi trong quá trình chạy: %v", err)
+	}
+
+	calibration, err := autoCrawler.Calibration()
+	if err != nil {
+		fatalf("❌ %v", err)
+	}
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println(calibration.Summary())
+
+	if name == "" {
+		fmt.Println("💡 Lưu bằng --save <name>, rồi chạy crawler --profile <name>")
+		return
+	}
+	emailStorage, _, _ := autoCrawler.GetStorageServices()
+	if err := emailStorage.SaveTuningProfile(calibration.Profile(name)); err != nil {
+		fatalf("❌ %v", err)
+	}
+	fmt.Printf("💾 Đã lưu profile %q, dùng: crawler --profile %s\n", name, name)
+}
+
+// runProject handles `project export <bundle.zip> [--no-accounts]` and
+// `project import <bundle.zip> [dir] [--force]`: move a whole project
+// (config, database, emails, hits, logs and optionally accounts) to another
+// machine or send it to support
+func runProject(cfg models.Config, args []string) {
+	args, noAccounts := extractFlag(args, "--no-accounts")
+	args, force := extractFlag(args, "--force")
+	usage := "❌ Usage: crawler project export <bundle.zip> [--no-accounts] | project import <bundle.zip> [dir] [--force]"
+	if len(args) < 2 {
+		log.Fatal(usage)
+	}
+
+	switch {
+	case args[0] == "export" && len(args) == 2:
+		templates := cfg // Bundle giữ config như đã cấu hình
+		cfg, err := utils.ResolveConfigPaths(cfg)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		storage.SetDefaultDBPath(cfg.DBPath)
+
+		emailStorage := storage.NewEmailStorage()
+		if err := emailStorage.InitDB(); err != nil {
+			log.Fatalf("❌ Không thể mở database: %v", err)
+		}
+		defer emailStorage.CloseDB()
+
+		bundle, err := emailStorage.ExportProject(templates, args[1], !noAccounts)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Printf("📦 Đã export %d file vào %s (%d KB)\n", len(bundle.Files), bundle.Path, bundle.Size/1024)
+		if !bundle.Credentials {
+			fmt.Println("🔒 Không có accounts, tokens, mật khẩu và webhook URL trong bundle")
+		}
+		if bundle.Encrypted {
+			fmt.Println("🔐 Results trong database được mã hoá: máy nhận cần passphrase")
+		}
+	case args[0] == "import" && len(args) <= 3:
+		dir := "."
+		if len(args) == 3 {
+			dir = args[2]
+		}
+		bundle, err := storage.ReadProjectBundle(args[1])
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if conflicts := storage.ProjectConflicts(bundle, dir); len(conflicts) > 0 && !force {
+			for _, path := range conflicts {
+				fmt.Printf("⚠️ Đã có %s\n", path)
+			}
+			log.Fatalf("❌ %d file đã tồn tại trong %s: dùng --force để ghi đè", len(conflicts), dir)
+		}
+		bundle, imported, err := storage.ImportProject(args[1], dir, true)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		for _, f := range bundle.Files {
+			fmt.Printf("✅ %s\n", filepath.Join(dir, filepath.FromSlash(f.Name)))
+		}
+		fmt.Printf("📦 Đã import project từ %s (%s, %s)\n", bundle.Host, bundle.CreatedAt.Local().Format("2006-01-02 15:04"), imported.DBPath)
+		if !bundle.Credentials {
+			fmt.Println("🔒 Bundle không có accounts và tokens: thêm accounts.txt trước khi crawl")
+		}
+		fmt.Println("⚙️ Cấu hình trong bundle được áp dụng khi import bằng GUI (Config → Project Bundle)")
+	default:
+		log.Fatal(usage)
+	}
+}
+
+// runCampaigns handles `campaigns [list] | archive <name> [--vacuum] |
+// restore <archive.zip|name>`: move finished campaigns out of the database
+// into zip archives and back
+func runCampaigns(cfg models.Config, args []string, takeover bool) {
+	args, vacuum := extractFlag(args, "--vacuum")
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	templates := cfg // ArchiveCampaign tìm file theo template chưa expand
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+	archiveDir := storage.CampaignArchiveDir(cfg.DBPath)
+
+	// Archive/restore xoá và ghi lại dữ liệu → cần lock
+	if args[0] == "archive" || args[0] == "restore" {
+		lock := acquireInstanceLock(cfg, takeover)
+		defer lock.Release()
+	}
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		campaigns, err := emailStorage.GetCampaigns()
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Println("📁 Campaigns trong database:")
+		if len(campaigns) == 0 {
+			fmt.Println("   (không có)")
+		}
+		for _, c := range campaigns {
+			fmt.Printf("   %-24s %6d results %4d runs\n", campaignLabel(c.Name), c.Results, c.Runs)
+		}
+
+		archives, err := storage.ListCampaignArchives(archiveDir)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🗄️ Archives trong %s:\n", archiveDir)
+		if len(archives) == 0 {
+			fmt.Println("   (không có)")
+		}
+		for _, a := range archives {
+			fmt.Printf("   %-24s %6d results %4d runs %3d files  %s  %s (%d KB)\n", campaignLabel(a.Campaign), a.Results(), a.Runs(),
+				len(a.Files), a.ArchivedAt.Local().Format("2006-01-02 15:04"), filepath.Base(a.Path), a.Size/1024)
+		}
+	case args[0] == "archive" && len(args) == 2:
+		archive, err := emailStorage.ArchiveCampaign(templates, args[1])
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🗄️ Đã archive %s: %d results, %d runs, %d files → %s (%d KB)\n", campaignLabel(archive.Campaign),
+			archive.Results(), archive.Runs(), len(archive.Files), archive.Path, archive.Size/1024)
+		if vacuum {
+			before, after, err := emailStorage.VacuumDB()
+			if err != nil {
+				fatalf("❌ %v", err)
+			}
+			fmt.Printf("🧹 Vacuum xong: %d KB → %d KB\n", before/1024, after/1024)
+		}
+	case args[0] == "restore" && len(args) == 2:
+		path := args[1]
+		if filepath.Ext(path) != ".zip" {
+			// Tên campaign → archive mới nhất của campaign đó
+			archives, err := storage.ListCampaignArchives(archiveDir)
+			if err != nil {
+				fatalf("❌ %v", err)
+			}
+			path = ""
+			for _, a := range archives {
+				if a.Campaign == args[1] {
+					path = a.Path
+					break
+				}
+			}
+			if path == "" {
+				fatalf("❌ Không có archive của campaign %q trong %s", args[1], archiveDir)
+			}
+		}
+		archive, err := emailStorage.RestoreCampaignArchive(templates, path)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("♻️ Đã restore %s: %d results, %d runs, %d files từ %s\n", campaignLabel(archive.Campaign),
+			archive.Results(), archive.Runs(), len(archive.Files), path)
+	default:
+		fatalf("❌ Usage: crawler campaigns [list] | archive <name> [--vacuum] | restore <archive.zip|name>")
+	}
+}
+
+// runPurge handles `purge --email <address>[,...] [--email ...]` (erase every
+// trace of addresses and write an erasure report) and `purge --older-than
+// <days>` (apply the retention policy now)
+func runPurge(cfg models.Config, args []string, takeover bool) {
+	var emails []string
+	for {
+		var value string
+		if args, value = extractValue(args, "--email"); value == "" {
+			break
+		}
+		emails = append(emails, strings.Split(value, ",")...)
+	}
+	args, olderThan := extractValue(args, "--older-than")
+	if len(args) > 0 || (len(emails) == 0) == (olderThan == "") {
+		log.Fatalf("❌ Usage: crawler purge --email <address>[,...] | purge --older-than <days>")
+	}
+
+	templates := cfg // Hit file, log... của mọi campaign được tìm theo template
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(cfg.DBPath)
+
+	lock := acquireInstanceLock(cfg, takeover)
+	defer lock.Release()
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		fatalf("❌ Không thể mở database: %v", err)
+	}
+	defer emailStorage.CloseDB()
+
+	if olderThan != "" {
+		days, err := strconv.Atoi(olderThan)
+		if err != nil || days < 1 {
+			fatalf("❌ --older-than phải là số ngày > 0")
+		}
+		purged, logs, err := emailStorage.ApplyRetention(cfg, days)
+		if err != nil {
+			fatalf("❌ %v", err)
+		}
+		fmt.Printf("🧹 Đã xoá dữ liệu cũ hơn %d ngày: %s, %d run log\n", days, purged, logs)
+		return
+	}
+
+	report, err := emailStorage.EraseAddresses(templates, emails)
+	if report == nil {
+		fatalf("❌ %v", err)
+	}
+	reportPath := storage.ErasureReportPath(cfg.DBPath, report.At)
+	if writeErr := os.WriteFile(reportPath, []byte(report.String()), 0644); writeErr != nil {
+		log.Printf("❌ Không ghi được erasure report: %v", writeErr)
+	}
+	fmt.Print(report)
+	if err != nil {
+		fatalf("❌ Erasure chưa xong: %v", err)
+	}
+	fmt.Printf("📄 Erasure report: %s\n", reportPath)
+}
+
+// campaignLabel names campaign "" (results saved before campaigns were tracked)
+func campaignLabel(name string) string {
+	if name == "" {
+		return "(untracked)"
+	}
+	return name
+}
+
+// applyTuningProfile sets concurrency and requests/sec from a saved profile
+func applyTuningProfile(cfg models.Config, name string) models.Config {
+	resolved, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	storage.SetDefaultDBPath(resolved.DBPath)
+	emailStorage := storage.NewEmailStorage()
+	defer emailStorage.CloseDB()
+
+	profile, ok, err := emailStorage.GetTuningProfile(name)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if !ok {
+		log.Fatalf("❌ Không có profile %q (xem crawler tune --list)", name)
+	}
+	cfg.MaxConcurrency = profile.MaxConcurrency
+	cfg.RequestsPerSec = profile.RequestsPerSec
+	fmt.Printf("🎛️ Profile %s: concurrency %d, %.1f req/s\n", profile.Name, profile.MaxConcurrency, profile.RequestsPerSec)
+	return cfg
+}
+
+// runLicense handles `license activate <key> | status | remove [--json]`
+func runLicense(args []string) {
+	args, asJSON := extractFlag(args, "--json")
+	if len(args) == 0 {
+		log.Fatalf("❌ Usage: crawler license activate <key> | status | remove [--json]")
+	}
+
+	wrapper := licensing.NewLicensedCrawlerWrapper()
+
+	// report in JSON (cho script provisioning) hoặc dạng text; exit code 1 khi lỗi
+	report := func(action string, err error) {
+		switch {
+		case asJSON:
+			out := map[string]interface{}{
+				"action":  action,
+				"ok":      err == nil,
+				"license": wrapper.GetLicenseInfo(),
+			}
+			if err != nil {
+				out["error"] = err.Error()
+			}
+			data, _ := json.MarshalIndent(out, "", "  ")
+			fmt.Println(string(data))
+		case err != nil:
+			fmt.Printf("❌ %v\n", err)
+		case action == "remove":
+			fmt.Println("🗑️ License removed")
+		default:
+			wrapper.ShowLicenseStatus()
+		}
+
+		if err != nil {
+			os.Exit(1)
+		}
+	}
+
+	switch args[0] {
+	case "activate":
+		if len(args) < 2 {
+			log.Fatalf("❌ Usage: crawler license activate <key>")
+		}
+		report("activate", wrapper.ActivateLicense(strings.Join(args[1:], "")))
+	case "status":
+		var err error
+		if status, _ := wrapper.GetLicenseInfo()["status"].(string); status == "invalid" || status == "expired" {
+			err = fmt.Errorf("no valid license (%s)", status)
+		}
+		report("status", err)
+	case "remove":
+		report("remove", wrapper.RemoveLicense())
+	default:
+		log.Fatalf("❌ Unknown license command: %s", args[0])
+	}
+}
+
+// Exit codes of `doctor`: the first failing check decides the code, in this
+// order, so scripts can tell what to fix before a run
+const (
+	doctorExitOK       = 0
+	doctorExitConfig   = 2
+	doctorExitFiles    = 3
+	doctorExitDatabase = 4
+	doctorExitLicense  = 5
+	doctorExitCapacity = 6
+	doctorExitNetwork  = 7
+)
+
+// doctorCheck is the outcome of one `doctor` check
+type doctorCheck struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Detail   string `json:"detail"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// runDoctor handles `doctor [--min <n>] [--offline] [--json]`: checks the
+// config, files, database, license, tokens/accounts and network access, then
+// exits with the code of the first failed check
+func runDoctor(cfg models.Config, args []string) {
+	args, asJSON := extractFlag(args, "--json")
+	args, offline := extractFlag(args, "--offline")
+	args, minArg := extractValue(args, "--min")
+
+	minUsable := 1
+	if minArg != "" {
+		n, err := strconv.Atoi(minArg)
+		if err != nil || n < 0 {
+			log.Fatalf("❌ Usage: crawler doctor [--min <n>] [--offline] [--json]")
+		}
+		minUsable = n
+	}
+
+	var checks []doctorCheck
+	add := func(name string, exitCode int, err error, detail string) {
+		check := doctorCheck{Name: name, OK: err == nil, Detail: detail, ExitCode: doctorExitOK}
+		if err != nil {
+			check.Detail = err.Error()
+			check.ExitCode = exitCode
+		}
+		checks = append(checks, check)
+	}
+
+	resolved, err := doctorConfig(cfg)
+	add("config", doctorExitConfig, err, fmt.Sprintf("mode %s, concurrency %d, %.1f req/s", cfg.CrawlMode, cfg.MaxConcurrency, cfg.RequestsPerSec))
+	if err == nil {
+		cfg = resolved
+	}
+
+	add("files", doctorExitFiles, doctorFiles(cfg), "input files readable, output paths writable")
+
+	version, err := doctorDatabase(cfg)
+	add("database", doctorExitDatabase, err, fmt.Sprintf("%s opened, schema version %d", cfg.DBPath, version))
+
+	licenseInfo := licensing.NewLicensedCrawlerWrapper().GetLicenseInfo()
+	status, _ := licenseInfo["status"].(string)
+	err = nil
+	if status == "invalid" || status == "expired" {
+		err = fmt.Errorf("no valid license (%s)", status)
+		if reason, ok := licenseInfo["error"].(string); ok {
+			err = fmt.Errorf("no valid license: %s", reason)
+		}
+	}
+	add("license", doctorExitLicense, err, fmt.Sprintf("%s, %v days left", status, licenseInfo["days_left"]))
+
+	tokens, accounts := doctorCapacity(cfg)
+	err = nil
+	if tokens < minUsable && accounts < minUsable {
+		err = fmt.Errorf("only %d valid tokens and %d usable accounts, need %d", tokens, accounts, minUsable)
+	}
+	add("capacity", doctorExitCapacity, err, fmt.Sprintf("%d valid tokens, %d usable accounts", tokens, accounts))
+
+	if !offline {
+		endpoint, _ := crawler.EndpointFromConfig(cfg)
+		add("network", doctorExitNetwork, doctorNetwork(cfg, endpoint), "reached "+endpoint.ProfileURL())
+	}
+
+	exitCode := doctorExitOK
+	for _, check := range checks {
+		if !check.OK && exitCode == doctorExitOK {
+			exitCode = check.ExitCode
+		}
+	}
+
+	if asJSON {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"ok":        exitCode == doctorExitOK,
+			"exit_code": exitCode,
+			"checks":    checks,
+		}, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		for _, check := range checks {
+			icon := "✅"
+			if !check.OK {
+				icon = "❌"
+			}
+			fmt.Printf("%s %-9s %s\n", icon, check.Name, check.Detail)
+		}
+		if exitCode == doctorExitOK {
+			fmt.Println("🩺 Sẵn sàng để crawl")
+		} else {
+			fmt.Printf("🩺 Có lỗi, exit code %d\n", exitCode)
+		}
+	}
+
+	os.Exit(exitCode)
+}
+
+// doctorConfig resolves the output paths and validates the settings a crawl
+// would reject
+func doctorConfig(cfg models.Config) (models.Config, error) {
+	resolved, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		return cfg, err
+	}
+	if err := config.Validate(cfg).Err(); err != nil {
+		return cfg, err
+	}
+	if _, err := crawler.EndpointFromConfig(cfg); err != nil {
+		return cfg, err
+	}
+	return resolved, nil
+}
+
+// doctorFiles checks that the input files can be read and the output
+// locations written
+func doctorFiles(cfg models.Config) error {
+	inputs := []string{cfg.AccountsFilePath}
+	if cfg.EmailsDBDSN == "" {
+		inputs = append(inputs, cfg.EmailsFilePath)
+	}
+	for _, path := range inputs {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %w", path, err)
+		}
+		file.Close()
+	}
+
+	for _, path := range []string{cfg.EmailsFilePath, cfg.TokensFilePath, cfg.OutputFilePath, cfg.DBPath, cfg.LogFilePath} {
+		if err := checkWritable(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkWritable checks that path can be opened for writing, or created in its
+// directory when it does not exist yet
+func checkWritable(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return fmt.Errorf("%s is not writable: %w", path, err)
+		}
+		return file.Close()
+	}
+
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return fmt.Errorf("cannot create files in %s: %w", dir, err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// doctorDatabase opens the database, which applies pending migrations, and
+// returns its schema version
+func doctorDatabase(cfg models.Config) (int, error) {
+	storage.SetDefaultDBPath(cfg.DBPath)
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		return 0, err
+	}
+	defer emailStorage.CloseDB()
+
+	info, err := emailStorage.GetDatabaseInfo()
+	if err != nil {
+		return 0, err
+	}
+	version, _ := info["schema_version"].(int)
+	return version, nil
+}
+
+// doctorCapacity counts well-formed tokens and the accounts whose last login
+// did not fail for good (wrong password, locked, MFA)
+func doctorCapacity(cfg models.Config) (tokens, accounts int) {
+	if list, err := storage.NewTokenStorage().LoadTokensFromFile(cfg.TokensFilePath); err == nil {
+		tokens, _ = utils.ValidateTokenBatch(list)
+	}
+
+	list, err := storage.NewAccountStorage().LoadAccounts(cfg.AccountsFilePath)
+	if err != nil {
+		return tokens, 0
+	}
+	statuses, err := storage.LoadAccountStatusStore(cfg.AccountsFilePath)
+	for _, account := range list {
+		if err == nil {
+			if entry, ok := statuses.Get(account.Email); ok && models.IsBurnedAccountStatus(entry.Status) {
+				continue
+			}
+		}
+		accounts++
+	}
+	return tokens, accounts
+}
+
+// doctorNetwork checks that the LinkedIn API answers; any HTTP status counts,
+// since the request carries no token
+func doctorNetwork(cfg models.Config, endpoint crawler.Endpoint) error {
+	client := &http.Client{Timeout: cfg.RequestTimeout}
+	resp, err := client.Get(endpoint.ProfileURL())
+	if err != nil {
+		return fmt.Errorf("cannot reach the LinkedIn API: %w", err)
+	}
+	resp.Body.Close()
 	return nil
 }
+
+// extractValue removes "flag value" from args and returns the value, or ""
+func extractValue(args []string, flag string) ([]string, string) {
+	var rest []string
+	value := ""
+	for i := 0; i < len(args); i++ {
+		if args[i] == flag && i+1 < len(args) {
+			value = args[i+1]
+			i++
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	return rest, value
+}
+
+// extractVerbosityFlags removes -q/--quiet, -v and -vv from args and returns
+// the console level they select
+func extractVerbosityFlags(args []string) ([]string, int) {
+	args, quiet := extractFlag(args, "-q")
+	args, quietLong := extractFlag(args, "--quiet")
+	args, verbose := extractFlag(args, "-v")
+	args, debug := extractFlag(args, "-vv")
+
+	switch {
+	case quiet || quietLong:
+		if verbose || debug {
+			log.Fatalf("❌ -q và -v/-vv không dùng chung được")
+		}
+		return args, utils.ConsoleQuiet
+	case debug:
+		return args, utils.ConsoleDebug
+	case verbose:
+		return args, utils.ConsoleVerbose
+	}
+	return args, utils.ConsoleNormal
+}
+
+// extractProgressFlag removes --progress=json (or "--progress json") from args
+// and reports whether it was given
+func extractProgressFlag(args []string) ([]string, bool) {
+	var rest []string
+	value := ""
+	for i := 0; i < len(args); i++ {
+		switch {
+		case strings.HasPrefix(args[i], "--progress="):
+			value = strings.TrimPrefix(args[i], "--progress=")
+		case args[i] == "--progress" && i+1 < len(args):
+			value = args[i+1]
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	if value != "" && value != "json" {
+		log.Fatalf("❌ Usage: crawler [crawl] --progress=json")
+	}
+	return rest, value == "json"
+}
+
+// useEmailsSource points the crawl at --emails: a file is used as is, while
+// stdin ("-") and URLs are written to the configured emails file first
+func useEmailsSource(cfg models.Config, source, checksum string) models.Config {
+	if source != "-" && !integrations.IsEmailListURL(source) {
+		cfg.EmailsFilePath = source
+		return cfg
+	}
+
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	var data []byte
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("❌ Không thể đọc emails từ stdin: %v", err)
+		}
+		if checksum != "" {
+			if err := integrations.VerifySHA256(data, checksum); err != nil {
+				log.Fatalf("❌ stdin: %v", err)
+			}
+		}
+		fmt.Printf("📥 Đọc %d bytes emails từ stdin\n", len(data))
+	} else {
+		var verified bool
+		data, verified, err = integrations.FetchEmailList(source, checksum)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if verified {
+			fmt.Printf("📥 Đã tải %d bytes emails từ %s (sha256 OK)\n", len(data), source)
+		} else {
+			fmt.Printf("⚠️ Đã tải %d bytes emails từ %s nhưng không có checksum để kiểm tra (dùng --sha256 hoặc %s.sha256)\n",
+				len(data), source, source)
+		}
+	}
+
+	if err := utils.WriteFileAtomic(cfg.EmailsFilePath, data); err != nil {
+		log.Fatalf("❌ Không thể ghi %s: %v", cfg.EmailsFilePath, err)
+	}
+	fmt.Printf("📝 Emails đã được ghi vào %s\n", cfg.EmailsFilePath)
+	return cfg
+}
+
+// extractFlag removes flag from args and reports whether it was present
+func extractFlag(args []string, flag string) ([]string, bool) {
+	var rest []string
+	found := false
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// heldInstanceLock is the lock taken by acquireInstanceLock, released by fatalf
+var heldInstanceLock *storage.InstanceLock
+
+// fatalf is log.Fatalf for commands holding the instance lock: os.Exit skips
+// the deferred Release, so the lock is released before exiting
+func fatalf(format string, args ...interface{}) {
+	if heldInstanceLock != nil {
+		heldInstanceLock.Release()
+	}
+	log.Fatalf(format, args...)
+}
+
+// acquireInstanceLock exits when another instance holds the lock, unless takeover is set
+func acquireInstanceLock(cfg models.Config, takeover bool) *storage.InstanceLock {
+	cfg, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	lock, holder, err := storage.AcquireInstanceLock(storage.InstanceLockPath(cfg.DBPath), "cli", takeover)
+	if errors.Is(err, storage.ErrInstanceRunning) {
+		log.Fatalf("❌ Another instance is running: %s\n   Dừng instance đó hoặc chạy lại với --takeover", holder)
+	}
+	if err != nil {
+		log.Fatalf("❌ Không thể tạo instance lock: %v", err)
+	}
+	if takeover {
+		fmt.Println("⚠️ --takeover: đã chiếm instance lock")
+	}
+	heldInstanceLock = lock
+	return lock
+}
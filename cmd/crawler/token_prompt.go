@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tokenProviderAuthEnv holds the auth header for --token-provider, kept out
+// of the command line and shell history
+const tokenProviderAuthEnv = "CRAWLER_TOKEN_PROVIDER_AUTH"
+
+// promptTokens is the manual token source on the terminal: it reads pasted
+// tokens, one per line, until an empty line (an empty first line skips)
+func promptTokens(ctx context.Context) (string, error) {
+	fmt.Fprintln(os.Stderr, "✋ Các nguồn token khác đã hết: dán token (mỗi dòng một token), Enter ở dòng trống để kết thúc hoặc bỏ qua:")
+
+	var lines []string
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read tokens: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
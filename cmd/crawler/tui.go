@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"linkedin-crawler/internal/orchestrator"
+	"linkedin-crawler/internal/utils"
+)
+
+// tuiRefreshInterval is how often the TUI reads the crawler state
+const tuiRefreshInterval = 500 * time.Millisecond
+
+// tuiLogLines is how many console lines the TUI keeps
+const tuiLogLines = 8
+
+var (
+	tuiTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	tuiHeadStyle  = lipgloss.NewStyle().Bold(true)
+	tuiDimStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	tuiGoodStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	tuiBadStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	tuiWarnStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// consoleCapture redirects stdout and the log package into a pipe so the
+// crawler's console output shows inside the TUI instead of over it
+type consoleCapture struct {
+	stdout *os.File
+	reader *os.File
+	writer *os.File
+}
+
+// captureConsole starts redirecting stdout and log output
+func captureConsole() (*consoleCapture, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture console: %w", err)
+	}
+	capture := &consoleCapture{stdout: os.Stdout, reader: reader, writer: writer}
+	os.Stdout = writer
+	log.SetOutput(writer)
+	return capture, nil
+}
+
+// Restore puts stdout and log output back on the terminal
+func (c *consoleCapture) Restore() {
+	os.Stdout = c.stdout
+	log.SetOutput(os.Stderr)
+	c.writer.Close()
+}
+
+type (
+	tuiTickMsg    struct{}
+	tuiLogMsg     string
+	tuiRunDoneMsg struct{ err error }
+)
+
+// tuiModel is the bubbletea model of `crawl --tui`
+type tuiModel struct {
+	autoCrawler *orchestrator.AutoCrawler
+	progress    orchestrator.ProgressEvent
+	tokens      []orchestrator.TokenHealth
+	hits        []orchestrator.RecentHit
+	logs        []string
+	startedAt   time.Time
+	stopping    bool
+	width       int
+	err         error
+}
+
+// runWithTUI runs the crawl behind a terminal UI with a progress bar, token
+// health, recent hits and the crawler's console output. p pauses/resumes and
+// q or Ctrl+C stops.
+func runWithTUI(autoCrawler *orchestrator.AutoCrawler, console *consoleCapture) error {
+	model := &tuiModel{autoCrawler: autoCrawler, startedAt: time.Now(), width: 80}
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithOutput(console.stdout))
+
+	// Console bị chuyển vào TUI trước filter của --log-privacy nên che email tại đây
+	privacy := autoCrawler.GetConfig().LogPrivacy
+	go func() {
+		scanner := bufio.NewScanner(console.reader)
+		for scanner.Scan() {
+			program.Send(tuiLogMsg(utils.PrivateText(scanner.Text(), privacy)))
+		}
+	}()
+	go func() {
+		err := autoCrawler.Run()
+		program.Send(tuiRunDoneMsg{err: err})
+	}()
+
+	if _, err := program.Run(); err != nil {
+		// Không hiển thị được TUI thì vẫn dừng crawler cho sạch
+		autoCrawler.Stop()
+		return fmt.Errorf("TUI failed: %w", err)
+	}
+	return model.err
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return tuiTick()
+}
+
+// tuiTick schedules the next refresh
+func tuiTick() tea.Cmd {
+	return tea.Tick(tuiRefreshInterval, func(time.Time) tea.Msg { return tuiTickMsg{} })
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "p", " ":
+			if m.stopping {
+				break
+			}
+			if m.autoCrawler.IsPaused() {
+				m.autoCrawler.Resume()
+			} else {
+				m.autoCrawler.Pause()
+			}
+		case "q", "ctrl+c":
+			// Lần đầu dừng crawler, lần thứ hai thoát ngay
+			if m.stopping {
+				return m, tea.Quit
+			}
+			m.stopping = true
+			m.autoCrawler.Stop()
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case tuiTickMsg:
+		m.progress = m.autoCrawler.Progress()
+		m.tokens = m.autoCrawler.TokenHealth()
+		m.hits = m.autoCrawler.RecentHits()
+		return m, tuiTick()
+	case tuiLogMsg:
+		m.logs = append(m.logs, string(msg))
+		if len(m.logs) > tuiLogLines {
+			m.logs = m.logs[len(m.logs)-tuiLogLines:]
+		}
+	case tuiRunDoneMsg:
+		m.err = msg.err
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m *tuiModel) View() string {
+	var b strings.Builder
+	p := m.progress
+
+	state := tuiGoodStyle.Render("RUNNING")
+	switch {
+	case m.stopping:
+		state = tuiWarnStyle.Render("STOPPING")
+	case m.autoCrawler.IsPaused():
+		state = tuiWarnStyle.Render("PAUSED")
+	}
+	fmt.Fprintf(&b, "%s  %s  %s\n\n", tuiTitleStyle.Render("LinkedIn Crawler"), state,
+		tuiDimStyle.Render("run "+m.autoCrawler.GetRunID()))
+
+	percent := 0.0
+	if p.Total > 0 {
+		percent = float64(p.Processed) / float64(p.Total)
+	}
+	fmt.Fprintf(&b, "%s %5.1f%%  %d/%d\n", progressBar(percent, max(m.width-24, 10)), percent*100, p.Processed, p.Total)
+
+	eta := "-"
+	if p.ETASeconds >= 0 {
+		eta = utils.FormatDuration(time.Duration(p.ETASeconds) * time.Second)
+	}
+	fmt.Fprintf(&b, "✅ %d  🎯 %d hits  ❌ %d  |  %.1f emails/s  |  ETA %s  |  %s\n\n",
+		p.Success, p.Hits, p.Failed, p.Rate, eta, utils.FormatDuration(time.Since(m.startedAt)))
+
+	valid := 0
+	for _, token := range m.tokens {
+		if token.Valid {
+			valid++
+		}
+	}
+	b.WriteString(tuiHeadStyle.Render(fmt.Sprintf("Tokens (%d/%d valid)", valid, len(m.tokens))) + "\n")
+	for i, token := range m.tokens {
+		if i == 5 {
+			b.WriteString(tuiDimStyle.Render(fmt.Sprintf("  ... %d more", len(m.tokens)-i)) + "\n")
+			break
+		}
+		status := tuiGoodStyle.Render("ok     ")
+		if !token.Valid {
+			status = tuiBadStyle.Render("invalid")
+		}
+		fmt.Fprintf(&b, "  %s  %s  %d req  %d err  last %d\n", token.Fingerprint, status, token.Requests, token.Errors, token.LastStatus)
+	}
+	b.WriteString("\n")
+
+	b.WriteString(tuiHeadStyle.Render("Recent hits") + "\n")
+	if len(m.hits) == 0 {
+		b.WriteString(tuiDimStyle.Render("  none yet") + "\n")
+	}
+	for _, hit := range m.hits {
+		fmt.Fprintf(&b, "  %s  %s  %s\n", tuiDimStyle.Render(hit.Time.Format("15:04:05")), utils.PrivateEmail(hit.Email, m.autoCrawler.GetConfig().LogPrivacy), hit.Name)
+	}
+	b.WriteString("\n")
+
+	b.WriteString(tuiHeadStyle.Render("Log") + "\n")
+	for _, line := range m.logs {
+		b.WriteString("  " + truncate(line, m.width-2) + "\n")
+	}
+	b.WriteString("\n" + tuiDimStyle.Render("p pause/resume • q stop (twice to quit now)") + "\n")
+	return b.String()
+}
+
+// progressBar draws a bar of width cells filled to percent (0..1)
+func progressBar(percent float64, width int) string {
+	filled := int(percent * float64(width))
+	filled = min(max(filled, 0), width)
+	return tuiGoodStyle.Render(strings.Repeat("█", filled)) + tuiDimStyle.Render(strings.Repeat("░", width-filled))
+}
+
+// truncate cuts s to at most width runes
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -18,8 +19,10 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"linkedin-crawler/internal/auth"
+	"linkedin-crawler/internal/crawler"
 	"linkedin-crawler/internal/models"
 	storageInternal "linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
 )
 
 type AccountsTab struct {
@@ -28,10 +31,13 @@ type AccountsTab struct {
 	accounts     []models.Account
 	accountData  binding.StringList
 
-	importBtn     *widget.Button
-	cleanBtn      *widget.Button
-	startTokenBtn *widget.Button
-	stopTokenBtn  *widget.Button
+	importBtn      *widget.Button
+	pasteBtn       *widget.Button
+	cleanBtn       *widget.Button
+	startTokenBtn  *widget.Button
+	stopTokenBtn   *widget.Button
+	manualTokenBtn *widget.Button
+	verifyBtn      *widget.Button
 
 	totalLabel     *widget.Label
 	usedLabel      *widget.Label
@@ -43,29 +49,54 @@ type AccountsTab struct {
 	totalTokensLabel   *widget.Label
 	lastUpdateLabel    *widget.Label
 
-	logText   *widget.RichText
-	logBuffer []string
+	logView *LogView
 
 	selectedIndex int
 
+	// Trạng thái đăng nhập gần nhất của từng account (email lowercase → status)
+	accountStatus map[string]string
+
 	// Token extraction state
 	isTokenExtracting  int32 // atomic flag
 	tokenExtractCancel context.CancelFunc
 	tokenExtractor     *auth.TokenExtractor
 
-	// Token info refresh ticker
-	tokenInfoTicker *time.Ticker
+	// Token extraction progress
+	extractLimitEntry   *widget.Entry
+	extractProgress     *widget.ProgressBar
+	extractProgressText *widget.Label
+	extractStatusList   *widget.List
+	extractRows         []extractRow
+	extractRowIndex     map[string]int
+}
+
+// extractRow is the live token extraction status of one account
+type extractRow struct {
+	email  string
+	status string
 }
 
+// Token extraction row statuses
+const (
+	extractStatusPending   = "⏸️ Pending"
+	extractStatusRunning   = "⏳ Logging in..."
+	extractStatusSuccess   = "✅ Token extracted"
+	extractStatusCancelled = "🚫 Cancelled"
+	extractStatusSkipped   = "⏭️ Skipped (target reached)"
+	extractStatusDailyCap  = "⏭️ Skipped (daily cap)"
+)
+
 func NewAccountsTab(gui *CrawlerGUI) *AccountsTab {
 	tab := &AccountsTab{
 		gui:            gui,
 		accounts:       []models.Account{},
 		accountData:    binding.NewStringList(),
 		tokenExtractor: auth.NewTokenExtractor(),
+		accountStatus:  make(map[string]string),
 	}
 
 	tab.importBtn = widget.NewButtonWithIcon("Import", theme.FolderOpenIcon(), tab.ImportAccounts)
+	tab.pasteBtn = widget.NewButtonWithIcon("Paste", theme.ContentPasteIcon(), tab.PasteAccounts)
 	tab.cleanBtn = widget.NewButtonWithIcon("Clean All", theme.DeleteIcon(), tab.CleanAllAccounts)
 	tab.cleanBtn.Importance = widget.DangerImportance
 
@@ -73,10 +104,31 @@ func NewAccountsTab(gui *CrawlerGUI) *AccountsTab {
 	tab.stopTokenBtn = widget.NewButtonWithIcon("Stop Token Extract", theme.MediaStopIcon(), tab.StopTokenExtract)
 	tab.stopTokenBtn.Importance = widget.DangerImportance
 	tab.stopTokenBtn.Disable() // Initially disabled
+	tab.manualTokenBtn = widget.NewButtonWithIcon("Manual Token", theme.ContentPasteIcon(), tab.ManualTokenCapture)
+	tab.verifyBtn = widget.NewButtonWithIcon("Verify Accounts", theme.ConfirmIcon(), tab.VerifyAccounts)
+
+	tab.extractLimitEntry = widget.NewEntry()
+	tab.extractLimitEntry.SetPlaceHolder("All accounts")
+	tab.extractProgress = widget.NewProgressBar()
+	tab.extractProgressText = widget.NewLabel("Idle")
+	tab.extractRowIndex = make(map[string]int)
+	tab.extractStatusList = widget.NewList(
+		func() int { return len(tab.extractRows) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewLabel("Status"), widget.NewLabel("Email"))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(tab.extractRows) {
+				return
+			}
+			row := tab.extractRows[id]
+			objects := obj.(*fyne.Container).Objects
+			objects[0].(*widget.Label).SetText(row.email)
+			objects[1].(*widget.Label).SetText(row.status)
+		},
+	)
 
-	tab.logText = widget.NewRichText()
-	tab.logText.Wrapping = fyne.TextWrapWord
-	tab.logBuffer = []string{}
+	tab.logView = NewLogView(gui, "Token Extraction Log")
 
 	tab.totalLabel = widget.NewLabel("Total: 0")
 	tab.usedLabel = widget.NewLabel("Used: 0")
@@ -90,7 +142,7 @@ func NewAccountsTab(gui *CrawlerGUI) *AccountsTab {
 
 	tab.setupAccountsList()
 
-	// Start token info refresh ticker
+	// Start token info refresh
 	tab.startTokenInfoRefresh()
 
 	return tab
@@ -99,8 +151,10 @@ func NewAccountsTab(gui *CrawlerGUI) *AccountsTab {
 func (at *AccountsTab) CreateContent() fyne.CanvasObject {
 	fileButtons := container.NewHBox(
 		at.importBtn,
+		at.pasteBtn,
 		at.cleanBtn,
 		widget.NewButton("Refresh", at.RefreshAccountsList),
+		widget.NewButton("Usage Ledger", at.ShowAccountLedger),
 	)
 
 	statsGrid := container.NewHBox(
@@ -135,22 +189,36 @@ func (at *AccountsTab) CreateContent() fyne.CanvasObject {
 
 	// Control buttons
 	controlButtons := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Extract only N tokens:"), nil, at.extractLimitEntry),
 		at.startTokenBtn,
 		at.stopTokenBtn,
+		at.verifyBtn,
+		at.manualTokenBtn,
+		at.extractProgress,
+		at.extractProgressText,
 	)
 
 	// Log area - MỞ RỘNG XUỐNG DƯỚI
-	logScroll := container.NewScroll(at.logText)
 	logArea := container.NewBorder(
 		widget.NewLabel("Token Extraction Log:"), nil, nil, nil,
-		logScroll,
+		at.logView.CreateContent(),
+	)
+
+	statusArea := container.NewBorder(
+		widget.NewLabel("Account Status:"), nil, nil, nil,
+		at.extractStatusList,
+	)
+	centerSplit := container.NewVSplit(
+		widget.NewCard("Extraction", "", statusArea),
+		widget.NewCard("Logs", "", logArea), // Log area chiếm phần lớn không gian
 	)
+	centerSplit.SetOffset(0.35)
 
 	// Right panel with expanded log area
 	rightPanel := container.NewBorder(
 		widget.NewCard("Token Control", "", controlButtons),
 		nil, nil, nil,
-		widget.NewCard("Logs", "", logArea), // Log area chiếm phần lớn không gian
+		centerSplit,
 	)
 
 	content := container.NewHSplit(leftPanel, rightPanel)
@@ -185,8 +253,10 @@ func (at *AccountsTab) setupAccountsList() {
 					icon.SetResource(theme.ConfirmIcon())
 				case "Used":
 					icon.SetResource(theme.InfoIcon())
-				case "Failed":
+				case "Failed", "Bad Password", "Locked", "MFA Required":
 					icon.SetResource(theme.ErrorIcon())
+				case "Rate Limited", "Timeout":
+					icon.SetResource(theme.WarningIcon())
 				default:
 					icon.SetResource(theme.AccountIcon())
 				}
@@ -199,42 +269,19 @@ func (at *AccountsTab) setupAccountsList() {
 	at.selectedIndex = -1
 }
 
-// Start token info refresh ticker
+// startTokenInfoRefresh refreshes the token info on the token info interval
 func (at *AccountsTab) startTokenInfoRefresh() {
-	if at.tokenInfoTicker != nil {
-		at.tokenInfoTicker.Stop()
+	// Initial update
+	at.gui.updateUI <- func() {
+		at.updateTokenInfo()
 	}
-
-	at.tokenInfoTicker = time.NewTicker(10 * time.Second) // Update every 10 seconds
-	go func() {
-		// Initial update
-		at.gui.updateUI <- func() {
-			at.updateTokenInfo()
-		}
-
-		defer func() {
-			if at.tokenInfoTicker != nil {
-				at.tokenInfoTicker.Stop()
-			}
-		}()
-
-		for {
-			select {
-			case <-at.tokenInfoTicker.C:
-				at.gui.updateUI <- func() {
-					at.updateTokenInfo()
-				}
-			case <-at.gui.ctx.Done():
-				return
-			}
-		}
-	}()
+	at.gui.refreshScheduler.Schedule("token-info", RefreshTokenInfo, at.updateTokenInfo)
 }
 
 // Update token information from tokens.txt file
 func (at *AccountsTab) updateTokenInfo() {
 	tokenStorage := storageInternal.NewTokenStorage()
-	tokens, err := tokenStorage.LoadTokensFromFile("tokens.txt")
+	tokens, err := tokenStorage.LoadTokensFromFile(at.gui.configTab.ResolvedConfig().TokensFilePath)
 
 	if err != nil {
 		// No tokens file or error reading
@@ -320,13 +367,33 @@ func (at *AccountsTab) StartTokenExtract() {
 		return
 	}
 
+	// 0 hoặc để trống = extract tất cả accounts
+	target := 0
+	if text := strings.TrimSpace(at.extractLimitEntry.Text); text != "" {
+		val, err := strconv.Atoi(text)
+		if err != nil || val < 0 {
+			dialog.ShowError(fmt.Errorf("Extract only N tokens must be a non-negative number"), at.gui.window)
+			return
+		}
+		target = val
+	}
+
+	// Snapshot accounts để danh sách không thay đổi trong lúc extract
+	accounts := append([]models.Account(nil), at.accounts...)
+
 	// Set running state
 	atomic.StoreInt32(&at.isTokenExtracting, 1)
 	at.startTokenBtn.Disable()
+	at.verifyBtn.Disable()
 	at.stopTokenBtn.Enable()
+	at.resetExtractRows(accounts)
+	at.setExtractProgress(0, plannedAccounts(len(accounts), target, 0, 0), 0, 0)
 
 	at.addLog("🚀 Bắt đầu extract tokens từ accounts...")
-	at.addLog(fmt.Sprintf("📊 Tổng số accounts: %d", len(at.accounts)))
+	at.addLog(fmt.Sprintf("📊 Tổng số accounts: %d", len(accounts)))
+	if target > 0 {
+		at.addLog(fmt.Sprintf("🎯 Chỉ extract %d tokens", target))
+	}
 
 	// Create context for cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -339,14 +406,16 @@ func (at *AccountsTab) StartTokenExtract() {
 			atomic.StoreInt32(&at.isTokenExtracting, 0)
 			at.gui.updateUI <- func() {
 				at.startTokenBtn.Enable()
+				at.verifyBtn.Enable()
 				at.stopTokenBtn.Disable()
 				at.addLog("✅ Token extraction hoàn thành!")
 				// Update token info after extraction
 				at.updateTokenInfo()
+				at.loadAccountStatus()
 			}
 		}()
 
-		at.performTokenExtraction(ctx)
+		at.performTokenExtraction(ctx, accounts, target)
 	}()
 }
 
@@ -357,74 +426,99 @@ func (at *AccountsTab) StopTokenExtract() {
 		return
 	}
 
-	at.addLog("⏹️ Đang dừng token extraction...")
+	at.addLog("⏹️ Đang dừng token extraction - hủy các lần đăng nhập đang chạy...")
 
-	// Cancel the context
+	// Cancel the context; các lần đăng nhập đang chạy bị hủy, batch sau không
+	// bắt đầu. State được reset khi goroutine extraction kết thúc.
 	if at.tokenExtractCancel != nil {
 		at.tokenExtractCancel()
 	}
 
-	// Reset state immediately
-	atomic.StoreInt32(&at.isTokenExtracting, 0)
-	at.startTokenBtn.Enable()
 	at.stopTokenBtn.Disable()
-
-	at.addLog("🛑 Đã dừng token extraction!")
-	// Update token info after stopping
-	at.updateTokenInfo()
+	at.extractProgressText.SetText("⏹️ Cancelling in-flight logins...")
 }
 
-// performTokenExtraction thực hiện việc extract tokens
-func (at *AccountsTab) performTokenExtraction(ctx context.Context) {
+// performTokenExtraction thực hiện việc extract tokens; target > 0 dừng lại
+// khi đã có đủ target tokens
+func (at *AccountsTab) performTokenExtraction(ctx context.Context, accounts []models.Account, target int) {
 	successCount := 0
 	failCount := 0
-
-	// Process accounts in batches of 3
-	batchSize := 3
-	for i := 0; i < len(at.accounts); i += batchSize {
+	processed := 0
+	var allResults []models.TokenResult
+
+	// Mỗi batch đăng nhập song song tối đa LoginParallelism accounts
+	cfg := at.gui.configTab.ResolvedConfig()
+	at.tokenExtractor.SetParallelism(cfg.LoginParallelism)
+	at.tokenExtractor.SetLoginTimeout(cfg.LoginTimeout)
+	at.tokenExtractor.SetLoginMethod(cfg.LoginMethod)
+	at.tokenExtractor.SetDailyCaps(cfg.MaxAccountLoginsPerDay, cfg.MaxAccountTokensPerDay)
+	batchSize := at.tokenExtractor.Parallelism()
+	for processed < len(accounts) {
 		// Check if cancelled
 		select {
 		case <-ctx.Done():
 			at.gui.updateUI <- func() {
 				at.addLog("⚠️ Token extraction bị hủy bởi người dùng")
+				at.logFailureSummary(allResults)
+				at.markRemainingExtractRows(accounts[processed:], extractStatusCancelled)
+				at.extractProgressText.SetText(fmt.Sprintf("🛑 Stopped: %d/%d accounts | Success: %d | Fail: %d",
+					processed, len(accounts), successCount, failCount))
 			}
 			return
 		default:
 		}
 
-		end := i + batchSize
-		if end > len(at.accounts) {
-			end = len(at.accounts)
+		// Không extract nhiều hơn số token còn thiếu
+		size := batchSize
+		if target > 0 {
+			size = min(size, target-successCount)
 		}
+		end := min(processed+size, len(accounts))
 
-		batch := at.accounts[i:end]
+		batch := accounts[processed:end]
+		start := processed
 		at.gui.updateUI <- func() {
-			at.addLog(fmt.Sprintf("📦 Xử lý batch %d-%d (%d accounts)...", i+1, end, len(batch)))
+			at.addLog(fmt.Sprintf("📦 Xử lý batch %d-%d (%d accounts)...", start+1, end, len(batch)))
+			for _, acc := range batch {
+				at.setExtractRowStatus(acc.Email, extractStatusRunning)
+			}
 		}
 
-		// Extract tokens from batch
-		results := at.tokenExtractor.ExtractTokensBatch(batch, "accounts.txt")
-
+		// Extract tokens from batch; status từng account cập nhật ngay khi xong
 		var validTokens []string
-		for _, result := range results {
-			if result.Error != nil {
+		results := at.tokenExtractor.ExtractTokensBatchContext(ctx, batch, cfg.AccountsFilePath, func(result models.TokenResult) {
+			if result.Reason == models.AccountStatusDailyCap {
+				at.gui.updateUI <- func() {
+					at.addLog(fmt.Sprintf("⏭️ Bỏ qua account %s: %v", result.Account.Email, result.Error))
+					at.setExtractRowStatus(result.Account.Email, extractStatusDailyCap)
+				}
+			} else if result.Error != nil {
 				failCount++
+				status := "❌ Cancelled"
+				if result.Reason != "" {
+					status = "❌ " + models.AccountStatusLabel(result.Reason)
+				}
 				at.gui.updateUI <- func() {
 					at.addLog(fmt.Sprintf("❌ Lỗi account %s: %v", result.Account.Email, result.Error))
+					at.setExtractRowStatus(result.Account.Email, status)
 				}
 			} else if result.Token != "" {
 				successCount++
 				validTokens = append(validTokens, result.Token)
 				at.gui.updateUI <- func() {
 					at.addLog(fmt.Sprintf("✅ Thành công account %s", result.Account.Email))
+					at.setExtractRowStatus(result.Account.Email, extractStatusSuccess)
 				}
 			}
-		}
+		})
+		allResults = append(allResults, results...)
+		processed = end
+		at.recordLoginAttempts(results)
 
 		// Save tokens to file
 		if len(validTokens) > 0 {
 			tokenStorage := storageInternal.NewTokenStorage()
-			err := tokenStorage.SaveTokensToFile("tokens.txt", validTokens)
+			err := tokenStorage.SaveTokensToFile(cfg.TokensFilePath, validTokens)
 			if err != nil {
 				at.gui.updateUI <- func() {
 					at.addLog(fmt.Sprintf("⚠️ Lỗi lưu tokens: %v", err))
@@ -439,19 +533,20 @@ func (at *AccountsTab) performTokenExtraction(ctx context.Context) {
 		}
 
 		// Update progress
+		done, success, fail := processed, successCount, failCount
+		planned := plannedAccounts(len(accounts), target, done, success)
 		at.gui.updateUI <- func() {
 			at.addLog(fmt.Sprintf("📊 Tiến độ: %d/%d accounts | Success: %d | Fail: %d",
-				end, len(at.accounts), successCount, failCount))
+				done, len(accounts), success, fail))
+			at.setExtractProgress(done, planned, success, fail)
 		}
 
-		// Rest between batches (except last batch)
-		if end < len(at.accounts) {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(5 * time.Second):
-				// Continue to next batch
+		if target > 0 && successCount >= target {
+			at.gui.updateUI <- func() {
+				at.addLog(fmt.Sprintf("🎯 Đã đủ %d tokens, dừng extract", target))
+				at.markRemainingExtractRows(accounts[done:], extractStatusSkipped)
 			}
+			break
 		}
 	}
 
@@ -459,7 +554,9 @@ func (at *AccountsTab) performTokenExtraction(ctx context.Context) {
 	at.gui.updateUI <- func() {
 		at.addLog("🎉 HOÀN THÀNH TOKEN EXTRACTION!")
 		at.addLog(fmt.Sprintf("📈 Kết quả: Success: %d | Fail: %d | Total: %d",
-			successCount, failCount, len(at.accounts)))
+			successCount, failCount, processed))
+
+		at.logFailureSummary(allResults)
 
 		if successCount > 0 {
 			at.addLog("✅ Có thể bắt đầu crawl emails với tokens đã có!")
@@ -470,6 +567,216 @@ func (at *AccountsTab) performTokenExtraction(ctx context.Context) {
 	}
 }
 
+// logFailureSummary logs failed accounts grouped by reason with what to do next
+func (at *AccountsTab) logFailureSummary(results []models.TokenResult) {
+	lines := auth.FailureSummary(results)
+	if len(lines) == 0 {
+		return
+	}
+	at.addLog("📋 Tổng hợp lỗi đăng nhập:")
+	for _, line := range lines {
+		at.addLog("   • " + line)
+	}
+}
+
+// plannedAccounts returns how many accounts the run expects to process: all of
+// them, or with a target the ones done plus one per token still missing
+func plannedAccounts(total, target, done, success int) int {
+	if target <= 0 {
+		return total
+	}
+	return min(total, done+max(target-success, 0))
+}
+
+// resetExtractRows fills the status list with pending rows for accounts
+func (at *AccountsTab) resetExtractRows(accounts []models.Account) {
+	at.extractRows = make([]extractRow, 0, len(accounts))
+	at.extractRowIndex = make(map[string]int, len(accounts))
+	for _, acc := range accounts {
+		at.extractRowIndex[acc.Email] = len(at.extractRows)
+		at.extractRows = append(at.extractRows, extractRow{email: acc.Email, status: extractStatusPending})
+	}
+	at.extractStatusList.Refresh()
+}
+
+// setExtractRowStatus updates the status row of one account
+func (at *AccountsTab) setExtractRowStatus(email, status string) {
+	if idx, ok := at.extractRowIndex[email]; ok {
+		at.extractRows[idx].status = status
+		at.extractStatusList.RefreshItem(idx)
+	}
+}
+
+// markRemainingExtractRows sets status on accounts that were never attempted
+func (at *AccountsTab) markRemainingExtractRows(accounts []models.Account, status string) {
+	for _, acc := range accounts {
+		at.setExtractRowStatus(acc.Email, status)
+	}
+}
+
+// setExtractProgress updates the progress bar (accounts processed / planned)
+func (at *AccountsTab) setExtractProgress(done, planned, success, fail int) {
+	if planned > 0 {
+		at.extractProgress.SetValue(float64(done) / float64(planned))
+	} else {
+		at.extractProgress.SetValue(0)
+	}
+	at.extractProgressText.SetText(fmt.Sprintf("%d/%d accounts | Success: %d | Fail: %d",
+		done, planned, success, fail))
+}
+
+// ManualTokenCapture hướng dẫn user tự đăng nhập Teams trên browser của mình,
+// dán token, kiểm tra rồi thêm vào tokens file - dùng khi extract tự động bị chặn
+func (at *AccountsTab) ManualTokenCapture() {
+	steps := widget.NewRichTextFromMarkdown(`**Capture a token manually:**
+
+1. Click **Open Teams Login** and sign in with your account
+2. Open DevTools (F12) → **Console** and run: ` + "`sessionStorage.getItem(\"LokiAuthToken\")`" + `
+3. Or: DevTools → **Network**, filter ` + "`loki`" + `, pick a request and copy the **Authorization** header
+4. Paste the value below (raw token, "Bearer ..." or the whole header line)`)
+	steps.Wrapping = fyne.TextWrapWord
+
+	openBtn := widget.NewButtonWithIcon("Open Teams Login", theme.ComputerIcon(), func() {
+		if u, err := url.Parse(auth.ManualLoginURL); err == nil {
+			if err := at.gui.app.OpenURL(u); err != nil {
+				dialog.ShowError(fmt.Errorf("không mở được browser: %v", err), at.gui.window)
+			}
+		}
+	})
+
+	tokenEntry := widget.NewMultiLineEntry()
+	tokenEntry.SetPlaceHolder("Paste token here...")
+	tokenEntry.Wrapping = fyne.TextWrapBreak
+	tokenEntry.SetMinRowsVisible(4)
+
+	content := container.NewVBox(steps, openBtn, widget.NewLabel("Token:"), tokenEntry)
+	d := dialog.NewCustomConfirm("Manual Token Capture", "Validate & Add", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		token := auth.NormalizeToken(tokenEntry.Text)
+		if token == "" {
+			dialog.ShowError(fmt.Errorf("Token không đúng định dạng - hãy copy lại giá trị LokiAuthToken hoặc header Authorization"), at.gui.window)
+			return
+		}
+
+		at.addLog("🔍 Đang kiểm tra token nhập thủ công...")
+		at.manualTokenBtn.Disable()
+		go at.addManualToken(token)
+	}, at.gui.window)
+	d.Resize(fyne.NewSize(600, 480))
+	d.Show()
+}
+
+// addManualToken validates a pasted token against the API and adds it to the pool
+func (at *AccountsTab) addManualToken(token string) {
+	cfg := at.gui.configTab.ResolvedConfig()
+	validTokens, err := crawler.NewValidatorService().ValidateTokensBatch([]string{token}, cfg, cfg.OutputFilePath, nil)
+	if err == nil && len(validTokens) == 0 {
+		err = fmt.Errorf("token bị API từ chối (hết hạn hoặc không hợp lệ)")
+	}
+	if err == nil {
+		err = storageInternal.NewTokenStorage().SaveTokensToFile(cfg.TokensFilePath, validTokens)
+	}
+
+	at.gui.updateUI <- func() {
+		at.manualTokenBtn.Enable()
+		if err != nil {
+			at.addLog(fmt.Sprintf("❌ Token thủ công không được thêm: %v", err))
+			dialog.ShowError(fmt.Errorf("Token không được thêm: %v", err), at.gui.window)
+			return
+		}
+
+		at.addLog("✅ Đã thêm token thủ công vào tokens file")
+		at.updateTokenInfo()
+		dialog.ShowInformation("Token Added", "Token hợp lệ và đã được thêm vào pool.", at.gui.window)
+	}
+}
+
+// VerifyAccounts kiểm tra email/password của tất cả accounts (không lấy token)
+// và đánh dấu Ready / Bad Password / Locked... để dọn danh sách trước khi chạy lớn
+func (at *AccountsTab) VerifyAccounts() {
+	if atomic.LoadInt32(&at.isTokenExtracting) == 1 {
+		at.addLog("⚠️ Token extraction đang chạy!")
+		return
+	}
+	if len(at.accounts) == 0 {
+		dialog.ShowError(fmt.Errorf("Không có accounts để kiểm tra"), at.gui.window)
+		return
+	}
+
+	accounts := append([]models.Account(nil), at.accounts...)
+
+	atomic.StoreInt32(&at.isTokenExtracting, 1)
+	at.startTokenBtn.Disable()
+	at.verifyBtn.Disable()
+	at.stopTokenBtn.Enable()
+	at.resetExtractRows(accounts)
+	at.setExtractProgress(0, len(accounts), 0, 0)
+	at.addLog(fmt.Sprintf("🔎 Bắt đầu kiểm tra %d accounts...", len(accounts)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	at.tokenExtractCancel = cancel
+
+	go func() {
+		defer func() {
+			atomic.StoreInt32(&at.isTokenExtracting, 0)
+			at.gui.updateUI <- func() {
+				at.startTokenBtn.Enable()
+				at.verifyBtn.Enable()
+				at.stopTokenBtn.Disable()
+				at.loadAccountStatus()
+			}
+		}()
+
+		cfg := at.gui.configTab.ResolvedConfig()
+		at.tokenExtractor.SetParallelism(cfg.LoginParallelism)
+		at.tokenExtractor.SetLoginTimeout(cfg.LoginTimeout)
+
+		done, ready, bad := 0, 0, 0
+		results := at.tokenExtractor.VerifyAccountsContext(ctx, accounts, cfg.AccountsFilePath, func(result models.TokenResult) {
+			done++
+			status := "❌ Cancelled"
+			if result.Reason == models.AccountStatusReady {
+				ready++
+				status = "✅ Ready"
+			} else if result.Reason != "" {
+				bad++
+				status = "❌ " + models.AccountStatusLabel(result.Reason)
+			}
+			d, r, b := done, ready, bad
+			at.gui.updateUI <- func() {
+				at.setExtractRowStatus(result.Account.Email, status)
+				at.setExtractProgress(d, len(accounts), r, b)
+			}
+		})
+
+		at.gui.updateUI <- func() {
+			at.addLog(fmt.Sprintf("📈 Kiểm tra xong: Ready: %d | Lỗi: %d | Tổng: %d", ready, bad, len(accounts)))
+			at.logFailureSummary(results)
+		}
+	}()
+}
+
+// loadAccountStatus reloads the stored login outcome of each account
+func (at *AccountsTab) loadAccountStatus() {
+	store, err := storageInternal.LoadAccountStatusStore(at.gui.configTab.ResolvedConfig().AccountsFilePath)
+	if err != nil {
+		at.addLog(fmt.Sprintf("⚠️ Không thể đọc trạng thái accounts: %v", err))
+		return
+	}
+
+	at.accountStatus = make(map[string]string, len(at.accounts))
+	for _, account := range at.accounts {
+		if entry, ok := store.Get(account.Email); ok {
+			at.accountStatus[strings.ToLower(account.Email)] = entry.Status
+		}
+	}
+	at.accountsList.Refresh()
+	at.updateStats()
+}
+
 func (at *AccountsTab) CleanAllAccounts() {
 	dialog.ShowConfirm("Clean All", "Xoá hết account?", func(ok bool) {
 		if ok {
@@ -483,18 +790,7 @@ func (at *AccountsTab) CleanAllAccounts() {
 }
 
 func (at *AccountsTab) addLog(msg string) {
-	ts := time.Now().Format("15:04:05")
-	logEntry := fmt.Sprintf("[%s] %s", ts, msg)
-	at.logBuffer = append(at.logBuffer, logEntry)
-
-	// Keep only last 200 entries
-	if len(at.logBuffer) > 200 {
-		at.logBuffer = at.logBuffer[len(at.logBuffer)-200:]
-	}
-
-	// Update display
-	displayText := "```\n" + strings.Join(at.logBuffer, "\n") + "\n```"
-	at.logText.ParseMarkdown(displayText)
+	at.logView.Add(utils.PrivateText(msg, at.gui.configTab.config.LogPrivacy))
 }
 
 func (at *AccountsTab) ImportAccounts() {
@@ -510,60 +806,152 @@ func (at *AccountsTab) ImportAccounts() {
 			}
 			return
 		}
-		lines := strings.Split(string(raw), "\n")
-		imported := 0
-		skipped := 0
-		emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" || strings.HasPrefix(line, "#") {
-				continue
-			}
-			parts := strings.Split(line, "|")
-			if len(parts) < 2 {
-				continue
-			}
-			email := strings.TrimSpace(parts[0])
-			password := strings.TrimSpace(parts[1])
-			if !emailRegex.MatchString(email) || len(password) < 6 {
-				continue
-			}
-			exists := false
-			for _, account := range at.accounts {
-				if account.Email == email {
-					exists = true
-					skipped++
-					break
-				}
-			}
-			if !exists {
-				at.accounts = append(at.accounts, models.Account{Email: email, Password: password})
-				at.accountData.Append(fmt.Sprintf("%s|%s", email, password))
-				imported++
+		at.importAccountsFrom(string(raw))
+	}, at.gui.window)
+}
+
+// PasteAccounts imports email|password lines from the clipboard
+func (at *AccountsTab) PasteAccounts() {
+	content := at.gui.app.Clipboard().Content()
+	if strings.TrimSpace(content) == "" {
+		dialog.ShowInformation("Paste Accounts", "The clipboard is empty", at.gui.window)
+		return
+	}
+	at.importAccountsFrom(content)
+}
+
+// importAccountsFrom adds the valid, not yet listed email|password lines of
+// raw. Accounts that were used, locked or rejected in earlier runs are left
+// out unless the user chooses to import them anyway.
+func (at *AccountsTab) importAccountsFrom(raw string) {
+	lines := strings.Split(raw, "\n")
+	var candidates []models.Account
+	seen := make(map[string]bool, len(at.accounts))
+	for _, account := range at.accounts {
+		seen[account.Email] = true
+	}
+	skipped := 0
+	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Split(line, "|")
+		if len(parts) < 2 {
+			// Copy từ bảng tính: 2 cột cách nhau bằng tab
+			parts = strings.Split(line, "\t")
+		}
+		if len(parts) < 2 {
+			continue
+		}
+		email := strings.TrimSpace(parts[0])
+		password := strings.TrimSpace(parts[1])
+		if !emailRegex.MatchString(email) || len(password) < 6 {
+			continue
+		}
+		if seen[email] {
+			skipped++
+			continue
+		}
+		seen[email] = true
+		candidates = append(candidates, models.Account{Email: email, Password: password})
+	}
+
+	// Đối chiếu với lịch sử các lần chạy trước
+	var fresh, burned []models.Account
+	history := at.accountHistory(candidates)
+	for _, account := range candidates {
+		if record, ok := history[strings.ToLower(account.Email)]; ok && models.IsBurnedAccountStatus(record.Status) {
+			burned = append(burned, account)
+		} else {
+			fresh = append(fresh, account)
+		}
+	}
+
+	if len(burned) == 0 {
+		at.addImportedAccounts(fresh, nil, skipped)
+		return
+	}
+
+	var details []string
+	for i, account := range burned {
+		if i == 10 {
+			details = append(details, fmt.Sprintf("... %d more", len(burned)-i))
+			break
+		}
+		details = append(details, fmt.Sprintf("%s - %s", account.Email,
+			models.AccountStatusLabel(history[strings.ToLower(account.Email)].Status)))
+	}
+	message := fmt.Sprintf("%d accounts were already used, locked or rejected in previous runs:\n\n%s\n\nImport them anyway?",
+		len(burned), strings.Join(details, "\n"))
+	at.gui.updateUI <- func() {
+		dialog.ShowConfirm("Previously Burned Accounts", message, func(include bool) {
+			if include {
+				at.addImportedAccounts(append(fresh, burned...), nil, skipped)
+			} else {
+				at.addImportedAccounts(fresh, burned, skipped)
 			}
+		}, at.gui.window)
+	}
+}
+
+// accountHistory looks up accounts in the accounts table of the database; an
+// unreadable database only disables the check
+func (at *AccountsTab) accountHistory(accounts []models.Account) map[string]storageInternal.AccountRecord {
+	if len(accounts) == 0 {
+		return nil
+	}
+	emails := make([]string, len(accounts))
+	for i, account := range accounts {
+		emails[i] = account.Email
+	}
+
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		at.addLog(fmt.Sprintf("⚠️ Không thể đọc lịch sử accounts: %v", err))
+		return nil
+	}
+	defer emailStorage.CloseDB()
+
+	history, err := emailStorage.GetAccountHistory(emails)
+	if err != nil {
+		at.addLog(fmt.Sprintf("⚠️ Không thể đọc lịch sử accounts: %v", err))
+		return nil
+	}
+	return history
+}
+
+// addImportedAccounts appends accounts to the list and reports the import;
+// excluded are the burned accounts left out
+func (at *AccountsTab) addImportedAccounts(accounts, excluded []models.Account, skipped int) {
+	for _, account := range accounts {
+		at.accounts = append(at.accounts, account)
+		at.accountData.Append(fmt.Sprintf("%s|%s", account.Email, account.Password))
+	}
+	imported := len(accounts)
+	at.gui.updateUI <- func() {
+		at.accountsList.Refresh()
+		at.updateStats()
+		message := fmt.Sprintf("Imported: %d | Skipped: %d", imported, skipped)
+		if len(excluded) > 0 {
+			message += fmt.Sprintf(" | Excluded (burned): %d", len(excluded))
 		}
-		at.gui.updateUI <- func() {
-			at.accountsList.Refresh()
-			at.updateStats()
-			message := fmt.Sprintf("Imported: %d | Skipped: %d", imported, skipped)
-			dialog.ShowInformation("Import Results", message, at.gui.window)
-			at.gui.updateStatus(fmt.Sprintf("Imported %d accounts", imported))
-			at.addLog(fmt.Sprintf("📥 Import: %d accounts thành công, %d bị bỏ qua", imported, skipped))
+		dialog.ShowInformation("Import Results", message, at.gui.window)
+		at.gui.updateStatus(fmt.Sprintf("Imported %d accounts", imported))
+		at.addLog(fmt.Sprintf("📥 Import: %d accounts thành công, %d bị bỏ qua", imported, skipped))
+		if len(excluded) > 0 {
+			at.addLog(fmt.Sprintf("🚫 %d accounts bị loại vì đã dùng/bị khóa ở lần chạy trước", len(excluded)))
 		}
-	}, at.gui.window)
+	}
 }
 
 func (at *AccountsTab) LoadAccounts() {
+	accountsFile := at.gui.configTab.ResolvedConfig().AccountsFilePath
 	accountStorage := storageInternal.NewAccountStorage()
-	accounts, err := accountStorage.LoadAccounts("accounts.txt")
+	accounts, err := accountStorage.LoadAccounts(accountsFile)
 	if err != nil {
-		if _, err := os.Stat("accounts.txt"); os.IsNotExist(err) {
-			sampleContent := `# Microsoft Teams Accounts
-# Format: email|password
-user1@company.com|password123
-`
-			os.WriteFile("accounts.txt", []byte(sampleContent), 0644)
-		}
+		// LoadAccounts đã tạo file mẫu khi chưa có
 		at.gui.updateUI <- func() {
 			at.gui.updateStatus("No accounts file found")
 		}
@@ -577,8 +965,7 @@ user1@company.com|password123
 		at.accountData.Append(fmt.Sprintf("%s|%s", account.Email, account.Password))
 	}
 	at.gui.updateUI <- func() {
-		at.accountsList.Refresh()
-		at.updateStats()
+		at.loadAccountStatus()
 		at.gui.updateStatus(fmt.Sprintf("Loaded %d accounts", len(accounts)))
 		at.addLog(fmt.Sprintf("📂 Loaded %d accounts từ file", len(accounts)))
 	}
@@ -597,10 +984,10 @@ func (at *AccountsTab) SaveAccounts() {
 		lines = append(lines, fmt.Sprintf("%s|%s", account.Email, account.Password))
 	}
 	content := strings.Join(lines, "\n")
-	err := os.WriteFile("accounts.txt", []byte(content), 0644)
+	err := utils.WriteFileAtomic(at.gui.configTab.ResolvedConfig().AccountsFilePath, []byte(content))
 	if err != nil {
 		at.gui.updateUI <- func() {
-			at.gui.updateStatus(fmt.Sprintf("Failed to save: %v", err))
+			at.gui.postStatus(StatusSourceApp, SeverityError, fmt.Sprintf("Failed to save: %v", err))
 		}
 		return
 	}
@@ -624,11 +1011,14 @@ func (at *AccountsTab) isValidEmail(email string) bool {
 func (at *AccountsTab) getAccountStatus(email string) string {
 	for _, account := range at.accounts {
 		if account.Email == email {
-			if len(account.Password) >= 6 && at.isValidEmail(account.Email) {
-				return "Ready"
-			} else {
+			if len(account.Password) < 6 || !at.isValidEmail(account.Email) {
 				return "Failed"
 			}
+			// Kết quả Verify Accounts / lần extract gần nhất
+			if status, ok := at.accountStatus[strings.ToLower(email)]; ok {
+				return models.AccountStatusLabel(status)
+			}
+			return "Ready"
 		}
 	}
 	return "Unknown"
@@ -641,6 +1031,11 @@ func (at *AccountsTab) updateStats() {
 	for _, account := range at.accounts {
 		if len(account.Password) < 6 || !at.isValidEmail(account.Email) {
 			failed++
+			continue
+		}
+		switch at.accountStatus[strings.ToLower(account.Email)] {
+		case models.AccountStatusWrongPassword, models.AccountStatusLocked, models.AccountStatusMFARequired:
+			failed++
 		}
 	}
 	available := total - used - failed
@@ -653,8 +1048,124 @@ func (at *AccountsTab) GetAccounts() []models.Account {
 	return at.accounts
 }
 func (at *AccountsTab) Cleanup() {
-	if at.tokenInfoTicker != nil {
-		at.tokenInfoTicker.Stop()
-		at.tokenInfoTicker = nil
+	at.gui.refreshScheduler.Cancel("token-info")
+}
+
+// recordLoginAttempts adds the logins of an extraction from this tab to the
+// account ledger; accounts cancelled before logging in are left out
+func (at *AccountsTab) recordLoginAttempts(results []models.TokenResult) {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		return
 	}
+	defer emailStorage.CloseDB()
+
+	for _, result := range results {
+		success := result.Error == nil && result.Token != ""
+		if !success && (result.Reason == "" || result.Reason == models.AccountStatusDailyCap) {
+			continue
+		}
+		if err := emailStorage.RecordLoginAttempt("", result.Account.Email, success, result.Reason, utils.TokenFingerprint(result.Token)); err != nil {
+			at.gui.updateUI <- func() {
+				at.addLog(fmt.Sprintf("⚠️ Không thể ghi lịch sử đăng nhập: %v", err))
+			}
+			return
+		}
+	}
+}
+
+// ShowAccountLedger shows which accounts were used in which run, the tokens
+// each gave and its final state, with CSV export for reconciling with the
+// account supplier
+func (at *AccountsTab) ShowAccountLedger() {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to open database: %w", err), at.gui.window)
+		return
+	}
+	entries, err := emailStorage.GetAccountLedger()
+	emailStorage.CloseDB()
+	if err != nil {
+		dialog.ShowError(err, at.gui.window)
+		return
+	}
+
+	if len(entries) == 0 {
+		dialog.ShowInformation("Usage Ledger", "No account logins recorded yet", at.gui.window)
+		return
+	}
+
+	tokens := 0
+	for _, e := range entries {
+		tokens += e.Tokens
+	}
+
+	headers := []string{"Account", "Run", "Logins", "Tokens", "Emails", "Expired", "Banned", "Last login", "Final state"}
+	table := widget.NewTable(
+		func() (int, int) { return len(entries) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(headers[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			e := entries[id.Row-1]
+			run := e.RunID
+			if run == "" {
+				run = "Accounts tab"
+			}
+			state := "-"
+			if e.FinalStatus != "" {
+				state = models.AccountStatusLabel(e.FinalStatus)
+			}
+			values := []string{
+				e.Account,
+				run,
+				fmt.Sprintf("%d", e.Logins),
+				fmt.Sprintf("%d", e.Tokens),
+				fmt.Sprintf("%d", e.Emails),
+				fmt.Sprintf("%d", e.TokensExpired),
+				fmt.Sprintf("%d", e.TokensBanned),
+				e.LastLogin.Local().Format("2006-01-02 15:04"),
+				state,
+			}
+			label.SetText(values[id.Col])
+		},
+	)
+	table.SetColumnWidth(0, 240)
+	table.SetColumnWidth(1, 150)
+	for col := 2; col < 7; col++ {
+		table.SetColumnWidth(col, 70)
+	}
+	table.SetColumnWidth(7, 140)
+	table.SetColumnWidth(8, 110)
+
+	exportCSV := widget.NewButtonWithIcon("Export CSV", theme.DocumentSaveIcon(), func() {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if _, err := writer.Write([]byte(utils.FormatAccountLedgerCSV(entries))); err != nil {
+				dialog.ShowError(err, at.gui.window)
+				return
+			}
+			at.gui.updateStatus(fmt.Sprintf("Account ledger exported to %s", writer.URI().Name()))
+		}, at.gui.window)
+		saveDialog.SetFileName(fmt.Sprintf("account-ledger-%s.csv", time.Now().Format("20060102")))
+		saveDialog.Show()
+	})
+
+	content := container.NewBorder(
+		nil, container.NewHBox(exportCSV), nil, nil,
+		container.NewScroll(table),
+	)
+
+	d := dialog.NewCustom(fmt.Sprintf("Usage Ledger (%d rows, %d tokens)", len(entries), tokens), "Close", content, at.gui.window)
+	d.Resize(fyne.NewSize(1100, 500))
+	d.Show()
 }
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+const (
+	analyticsChartRuns   = 20  // Số lần chạy gần nhất hiển thị trong biểu đồ
+	analyticsTrendRuns   = 3   // Số lần chạy gần nhất so với các lần trước đó
+	analyticsChartHeight = 120 // Chiều cao cột lớn nhất (px)
+)
+
+// runMetric is one per-run metric that can be charted
+type runMetric struct {
+	name   string
+	format string
+	value  func(storageInternal.RunRecord) float64
+	avg    func(storageInternal.RunAverages) float64
+	higher bool // true = giá trị cao hơn là tốt hơn
+}
+
+var runMetrics = []runMetric{
+	{"Emails/hour", "%.0f", storageInternal.RunRecord.EmailsPerHour,
+		func(a storageInternal.RunAverages) float64 { return a.EmailsPerHour }, true},
+	{"Hit rate", "%.1f%%", storageInternal.RunRecord.HitRate,
+		func(a storageInternal.RunAverages) float64 { return a.HitRate }, true},
+	{"Tokens/account", "%.2f", storageInternal.RunRecord.TokensPerAccount,
+		func(a storageInternal.RunAverages) float64 { return a.TokensPerAccount }, true},
+	{"429 rate", "%.1f%%", storageInternal.RunRecord.RateLimitRate,
+		func(a storageInternal.RunAverages) float64 { return a.RateLimitRate }, false},
+}
+
+// AnalyticsTab compares the metrics of past runs
+type AnalyticsTab struct {
+	gui *CrawlerGUI
+
+	runs []storageInternal.RunRecord // Mới nhất trước
+
+	metricSelect *widget.Select
+	refreshBtn   *widget.Button
+	trendLabel   *widget.Label
+	chart        *fyne.Container
+	runsTable    *widget.Table
+}
+
+// NewAnalyticsTab creates a new analytics tab
+func NewAnalyticsTab(gui *CrawlerGUI) *AnalyticsTab {
+	return &AnalyticsTab{gui: gui}
+}
+
+// CreateContent creates the analytics tab content
+func (at *AnalyticsTab) CreateContent() fyne.CanvasObject {
+	names := make([]string, len(runMetrics))
+	for i, metric := range runMetrics {
+		names[i] = metric.name
+	}
+	at.metricSelect = widget.NewSelect(names, func(string) {
+		at.updateChart()
+	})
+	at.metricSelect.SetSelected(runMetrics[0].name)
+	at.refreshBtn = widget.NewButtonWithIcon("Refresh", theme.ViewRefreshIcon(), func() {
+		at.RefreshRuns()
+	})
+
+	at.trendLabel = widget.NewLabel("")
+	at.trendLabel.Wrapping = fyne.TextWrapWord
+	at.chart = container.NewGridWithColumns(analyticsChartRuns)
+
+	headers := []string{"Started", "Duration", "Mode", "Processed", "Emails/hour", "Hit rate", "Tokens/account", "429 rate", "Concurrency", "Req/s", "Pacing"}
+	at.runsTable = widget.NewTable(
+		func() (int, int) { return len(at.runs) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(headers[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			r := at.runs[id.Row-1]
+			duration := r.Duration().Round(time.Second).String()
+			if r.Unfinished {
+				// Checkpoint của lần chạy đang chạy, hoặc lần chạy bị crash
+				if r.RunID != "" && r.RunID == at.gui.crawlController.RunID() {
+					duration += " (running)"
+				} else {
+					duration += " (interrupted)"
+				}
+			}
+			values := []string{
+				r.StartedAt.Format("2006-01-02 15:04"),
+				duration,
+				r.CrawlMode,
+				fmt.Sprintf("%d", r.Processed),
+				fmt.Sprintf("%.0f", r.EmailsPerHour()),
+				fmt.Sprintf("%.1f%%", r.HitRate()),
+				fmt.Sprintf("%.2f", r.TokensPerAccount()),
+				fmt.Sprintf("%.1f%%", r.RateLimitRate()),
+				fmt.Sprintf("%d", r.MaxConcurrency),
+				fmt.Sprintf("%.1f", r.RequestsPerSec),
+				r.PacingProfile,
+			}
+			label.SetText(values[id.Col])
+		},
+	)
+	at.runsTable.SetColumnWidth(0, 140)
+	for col := 1; col < len(headers); col++ {
+		at.runsTable.SetColumnWidth(col, 100)
+	}
+
+	chartCard := widget.NewCard("📈 Run History", "Compare runs to spot degrading settings or accounts", container.NewVBox(
+		container.NewHBox(widget.NewLabel("Metric:"), at.metricSelect, layout.NewSpacer(), at.refreshBtn),
+		at.trendLabel,
+		at.chart,
+	))
+
+	at.RefreshRuns()
+
+	return container.NewBorder(chartCard, nil, nil, nil, container.NewScroll(at.runsTable))
+}
+
+// RefreshRuns reloads the runs from the database
+func (at *AnalyticsTab) RefreshRuns() {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to open database: %w", err), at.gui.window)
+		return
+	}
+	runs, err := emailStorage.GetRuns(0)
+	emailStorage.CloseDB()
+	if err != nil {
+		dialog.ShowError(err, at.gui.window)
+		return
+	}
+
+	at.runs = runs
+	if at.runsTable != nil {
+		at.runsTable.Refresh()
+	}
+	at.updateChart()
+}
+
+// selectedMetric returns the metric picked in the select
+func (at *AnalyticsTab) selectedMetric() runMetric {
+	for _, metric := range runMetrics {
+		if at.metricSelect != nil && metric.name == at.metricSelect.Selected {
+			return metric
+		}
+	}
+	return runMetrics[0]
+}
+
+// updateChart draws the selected metric for the latest runs, oldest first
+func (at *AnalyticsTab) updateChart() {
+	if at.chart == nil {
+		return
+	}
+	metric := at.selectedMetric()
+	at.trendLabel.SetText(at.describeTrend(metric))
+
+	recent := at.runs
+	if len(recent) > analyticsChartRuns {
+		recent = recent[:analyticsChartRuns]
+	}
+	peak := 0.0
+	for _, run := range recent {
+		peak = max(peak, metric.value(run))
+	}
+
+	bars := make([]fyne.CanvasObject, 0, len(recent))
+	for i := len(recent) - 1; i >= 0; i-- {
+		run := recent[i]
+		value := metric.value(run)
+		height := float32(0)
+		if peak > 0 {
+			height = float32(value/peak) * analyticsChartHeight
+		}
+		bar := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+		bar.SetMinSize(fyne.NewSize(12, max(height, 1)))
+
+		count := widget.NewLabel(fmt.Sprintf(metric.format, value))
+		count.Alignment = fyne.TextAlignCenter
+		date := widget.NewLabel(run.StartedAt.Format("01-02"))
+		date.Alignment = fyne.TextAlignCenter
+		date.TextStyle = fyne.TextStyle{Italic: true}
+
+		bars = append(bars, container.NewVBox(layout.NewSpacer(), count, bar, date))
+	}
+	at.chart.Objects = bars
+	at.chart.Refresh()
+}
+
+// describeTrend compares the latest runs with the runs before them
+func (at *AnalyticsTab) describeTrend(metric runMetric) string {
+	if len(at.runs) == 0 {
+		return "No runs recorded yet. Metrics are saved when a crawl finishes."
+	}
+	if len(at.runs) <= analyticsTrendRuns {
+		return fmt.Sprintf("%d runs recorded. Need more than %d to compare trends.", len(at.runs), analyticsTrendRuns)
+	}
+
+	latest := metric.avg(storageInternal.AverageRuns(at.runs[:analyticsTrendRuns]))
+	earlier := metric.avg(storageInternal.AverageRuns(at.runs[analyticsTrendRuns:]))
+	summary := fmt.Sprintf("%s: last %d runs "+metric.format+" vs earlier runs "+metric.format,
+		metric.name, analyticsTrendRuns, latest, earlier)
+	if earlier == 0 {
+		return summary
+	}
+
+	change := (latest - earlier) / earlier * 100
+	switch {
+	case change > -5 && change < 5:
+		return "➡️ " + summary + " (stable)"
+	case (change > 0) == metric.higher:
+		return fmt.Sprintf("⬆️ %s (%+.0f%%, improving)", summary, change)
+	default:
+		return fmt.Sprintf("⬇️ %s (%+.0f%%, degrading: %s)", summary, change, metric.degradeHint())
+	}
+}
+
+// degradeHint suggests what to look at when the metric gets worse
+func (m runMetric) degradeHint() string {
+	switch m.name {
+	case "Tokens/account":
+		return "check account quality"
+	case "429 rate":
+		return "lower concurrency or requests/sec"
+	case "Hit rate":
+		return "check the email list source"
+	default:
+		return "check rate limits and pacing"
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"linkedin-crawler/internal/orchestrator"
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+// ShowAutoTune asks for a burst size and profile name, then crawls a short
+// calibration burst with the form settings and offers its recommendation
+func (ct *ConfigTab) ShowAutoTune() {
+	if ct.gui.crawlController.Running() {
+		dialog.ShowInformation("Auto-Tune", "Stop the running crawl before calibrating", ct.gui.window)
+		return
+	}
+	if !ct.gui.isLicenseValid {
+		dialog.ShowError(fmt.Errorf("cannot calibrate: no valid license"), ct.gui.window)
+		return
+	}
+
+	emails := widget.NewEntry()
+	emails.SetText(strconv.Itoa(orchestrator.DefaultCalibrationEmails))
+	name := widget.NewEntry()
+	name.SetPlaceHolder("e.g. night, proxy-eu")
+
+	items := []*widget.FormItem{
+		{Text: "Emails:", Widget: emails, HintText: "Crawled for real from the pending emails"},
+		{Text: "Profile Name:", Widget: name, HintText: "Saved in emails.db; empty = don't save"},
+	}
+	form := dialog.NewForm("Auto-Tune", "Calibrate", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(emails.Text))
+		if err != nil || n < 1 {
+			dialog.ShowError(fmt.Errorf("emails must be a number > 0"), ct.gui.window)
+			return
+		}
+		// Calibrate với giá trị đang có trên form, kể cả chưa lưu
+		if err := ct.updateConfigFromForm(); err != nil {
+			dialog.ShowError(err, ct.gui.window)
+			return
+		}
+		ct.calibrate(n, strings.TrimSpace(name.Text))
+	}, ct.gui.window)
+	form.Resize(fyne.NewSize(460, 240))
+	form.Show()
+}
+
+// calibrate runs the burst in the background and shows the result
+func (ct *ConfigTab) calibrate(emails int, name string) {
+	progress := dialog.NewProgressInfinite("Auto-Tune", fmt.Sprintf("Crawling %d emails to measure 429 rate and latency...", emails), ct.gui.window)
+	progress.Show()
+	ct.gui.postStatus(StatusSourceCrawler, SeverityInfo, fmt.Sprintf("Calibrating on %d emails", emails))
+
+	go func() {
+		cfg, err := ct.gui.crawlConfig()
+		var calibration orchestrator.Calibration
+		if err == nil {
+			calibration, err = ct.gui.crawlController.Calibrate(cfg, emails)
+		}
+
+		ct.gui.updateUI <- func() {
+			progress.Hide()
+			if ct.gui.resultsTab != nil {
+				ct.gui.resultsTab.RefreshResults()
+			}
+			if err != nil {
+				if !errors.Is(err, ErrCrawlRunning) {
+					ct.gui.postStatus(StatusSourceCrawler, SeverityError, "Calibration failed")
+				}
+				dialog.ShowError(fmt.Errorf("calibration failed: %w", err), ct.gui.window)
+				return
+			}
+			ct.gui.postStatus(StatusSourceCrawler, SeveritySuccess, "Calibration finished")
+			ct.showCalibration(calibration, name)
+		}
+	}()
+}
+
+// showCalibration shows what the burst measured and applies the recommended
+// settings (and saves them as profile name) when approved
+func (ct *ConfigTab) showCalibration(calibration orchestrator.Calibration, name string) {
+	summary := widget.NewLabel(calibration.Summary())
+	summary.Wrapping = fyne.TextWrapWord
+
+	confirm := "Apply"
+	if name != "" {
+		confirm = "Apply & Save Profile"
+	}
+	result := dialog.NewCustomConfirm("Auto-Tune Result", confirm, "Discard", summary, func(apply bool) {
+		if !apply {
+			return
+		}
+		profile := calibration.Profile(name)
+		if name != "" {
+			emailStorage, err := ct.openMaintenanceStorage()
+			if err == nil {
+				err = emailStorage.SaveTuningProfile(profile)
+				emailStorage.CloseDB()
+			}
+			if err != nil {
+				dialog.ShowError(err, ct.gui.window)
+				return
+			}
+		}
+		ct.applyTuningProfile(profile)
+	}, ct.gui.window)
+	result.Resize(fyne.NewSize(560, 260))
+	result.Show()
+}
+
+// ShowTuningProfiles lists the saved tuning profiles and applies the chosen one
+func (ct *ConfigTab) ShowTuningProfiles() {
+	emailStorage, err := ct.openMaintenanceStorage()
+	var profiles []storageInternal.TuningProfile
+	if err == nil {
+		profiles, err = emailStorage.GetTuningProfiles()
+		emailStorage.CloseDB()
+	}
+	if err != nil {
+		dialog.ShowError(err, ct.gui.window)
+		return
+	}
+	if len(profiles) == 0 {
+		dialog.ShowInformation("Tuning Profiles", "No profiles yet: run Auto-Tune with a profile name", ct.gui.window)
+		return
+	}
+
+	byName := make(map[string]storageInternal.TuningProfile, len(profiles))
+	names := make([]string, len(profiles))
+	for i, p := range profiles {
+		byName[p.Name] = p
+		names[i] = p.Name
+	}
+	details := widget.NewLabel("")
+	details.Wrapping = fyne.TextWrapWord
+	choice := widget.NewSelect(names, func(selected string) {
+		p := byName[selected]
+		details.SetText(fmt.Sprintf("Concurrency %d, %.1f req/s\nMeasured on %d emails: %d of %d requests hit 429, avg latency %s\nSaved %s",
+			p.MaxConcurrency, p.RequestsPerSec, p.Emails, p.RateLimited, p.Requests, p.AvgLatency,
+			p.UpdatedAt.Local().Format("2006-01-02 15:04")))
+	})
+	choice.SetSelected(names[0])
+
+	items := []*widget.FormItem{
+		{Text: "Profile:", Widget: choice},
+		{Text: "", Widget: details},
+	}
+	form := dialog.NewForm("Tuning Profiles", "Apply", "Cancel", items, func(confirmed bool) {
+		if confirmed && choice.Selected != "" {
+			ct.applyTuningProfile(byName[choice.Selected])
+		}
+	}, ct.gui.window)
+	form.Resize(fyne.NewSize(520, 240))
+	form.Show()
+}
+
+// applyTuningProfile puts the profile values on the form and saves the config
+func (ct *ConfigTab) applyTuningProfile(profile storageInternal.TuningProfile) {
+	ct.maxConcurrency.SetText(fmt.Sprintf("%d", profile.MaxConcurrency))
+	ct.requestsPerSec.SetText(fmt.Sprintf("%.1f", profile.RequestsPerSec))
+	ct.SaveConfig()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+// campaignLabel names campaign "" (results saved before campaigns were tracked)
+func campaignLabel(name string) string {
+	if name == "" {
+		return "(untracked)"
+	}
+	return name
+}
+
+// ShowCampaigns lists the campaigns in the database and the archived ones,
+// and archives or restores the selected campaign
+func (ct *ConfigTab) ShowCampaigns() {
+	archiveDir := storageInternal.CampaignArchiveDir(ct.ResolvedConfig().DBPath)
+
+	var campaigns []storageInternal.CampaignSummary
+	var archives []storageInternal.CampaignArchive
+	selectedCampaign, selectedArchive := -1, -1
+	status := widget.NewLabel("")
+
+	campaignList := widget.NewList(
+		func() int { return len(campaigns) },
+		func() fyne.CanvasObject { return widget.NewLabel("Campaign") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(campaigns) {
+				return
+			}
+			c := campaigns[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s   %d results, %d runs", campaignLabel(c.Name), c.Results, c.Runs))
+		},
+	)
+	campaignList.OnSelected = func(id widget.ListItemID) { selectedCampaign = id }
+
+	archiveList := widget.NewList(
+		func() int { return len(archives) },
+		func() fyne.CanvasObject { return widget.NewLabel("Archive") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(archives) {
+				return
+			}
+			a := archives[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s   %d results, %d runs   %s   %d KB",
+				campaignLabel(a.Campaign), a.Results(), a.Runs(), a.ArchivedAt.Local().Format("2006-01-02 15:04"), a.Size/1024))
+		},
+	)
+	archiveList.OnSelected = func(id widget.ListItemID) { selectedArchive = id }
+
+	reload := func() {
+		emailStorage, err := ct.openMaintenanceStorage()
+		if err == nil {
+			campaigns, err = emailStorage.GetCampaigns()
+			emailStorage.CloseDB()
+		}
+		if err == nil {
+			archives, err = storageInternal.ListCampaignArchives(archiveDir)
+		}
+		selectedCampaign, selectedArchive = -1, -1
+		campaignList.UnselectAll()
+		archiveList.UnselectAll()
+		campaignList.Refresh()
+		archiveList.Refresh()
+		if err != nil {
+			status.SetText(err.Error())
+		} else {
+			status.SetText(fmt.Sprintf("%d campaigns in the database, %d archives in %s", len(campaigns), len(archives), archiveDir))
+		}
+	}
+
+	// Sau archive/restore: Results và Analytics đọc lại DB
+	done := func(message string) {
+		reload()
+		ct.gui.updateStatus(message)
+		if ct.gui.resultsTab != nil {
+			ct.gui.resultsTab.RefreshResults()
+		}
+		if ct.gui.analyticsTab != nil {
+			ct.gui.analyticsTab.RefreshRuns()
+		}
+	}
+
+	archiveBtn := widget.NewButton("Archive Selected", func() {
+		if selectedCampaign < 0 || selectedCampaign >= len(campaigns) {
+			return
+		}
+		if ct.gui.crawlController.Running() {
+			dialog.ShowInformation("Archive Campaign", "Stop the crawler before archiving a campaign", ct.gui.window)
+			return
+		}
+		campaign := campaigns[selectedCampaign]
+		dialog.ShowConfirm("Archive Campaign",
+			fmt.Sprintf("Move %d results and %d runs of %s, with its run logs and hit files, into a zip in %s?\n\nRestore brings them back.",
+				campaign.Results, campaign.Runs, campaignLabel(campaign.Name), archiveDir),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				templates := ct.config // File được tìm theo template chưa expand
+				go func() {
+					var archive *storageInternal.CampaignArchive
+					emailStorage, err := ct.openMaintenanceStorage()
+					if err == nil {
+						archive, err = emailStorage.ArchiveCampaign(templates, campaign.Name)
+						emailStorage.CloseDB()
+					}
+					ct.gui.updateUI <- func() {
+						if err != nil {
+							dialog.ShowError(err, ct.gui.window)
+							reload()
+							return
+						}
+						done(fmt.Sprintf("Archived %s to %s (%d KB)", campaignLabel(archive.Campaign), filepath.Base(archive.Path), archive.Size/1024))
+					}
+				}()
+			}, ct.gui.window)
+	})
+
+	restoreBtn := widget.NewButton("Restore Selected", func() {
+		if selectedArchive < 0 || selectedArchive >= len(archives) {
+			return
+		}
+		if ct.gui.crawlController.Running() {
+			dialog.ShowInformation("Restore Campaign", "Stop the crawler before restoring a campaign", ct.gui.window)
+			return
+		}
+		path := archives[selectedArchive].Path
+		templates := ct.config // Chỉ khôi phục file vào thư mục theo template chưa expand
+		go func() {
+			var archive *storageInternal.CampaignArchive
+			emailStorage, err := ct.openMaintenanceStorage()
+			if err == nil {
+				archive, err = emailStorage.RestoreCampaignArchive(templates, path)
+				emailStorage.CloseDB()
+			}
+			ct.gui.updateUI <- func() {
+				if err != nil {
+					dialog.ShowError(err, ct.gui.window)
+					reload()
+					return
+				}
+				done(fmt.Sprintf("Restored %s: %d results, %d runs", campaignLabel(archive.Campaign), archive.Results(), archive.Runs()))
+			}
+		}()
+	})
+
+	lists := container.NewGridWithRows(2,
+		container.NewBorder(widget.NewLabel("In the database:"), archiveBtn, nil, nil, campaignList),
+		container.NewBorder(widget.NewLabel("Archived:"), restoreBtn, nil, nil, archiveList),
+	)
+	content := container.NewBorder(nil, status, nil, nil, lists)
+	campaignsDialog := dialog.NewCustom("Campaigns", "Close", content, ct.gui.window)
+	campaignsDialog.Resize(fyne.NewSize(620, 520))
+	reload()
+	campaignsDialog.Show()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+// createMainMenu returns the window menu
+func (gui *CrawlerGUI) createMainMenu() *fyne.MainMenu {
+	return fyne.NewMainMenu(
+		fyne.NewMenu("File",
+			fyne.NewMenuItem("New Campaign...", gui.configTab.ShowNewCampaign),
+		),
+		fyne.NewMenu("View",
+			fyne.NewMenuItem("Display Settings...", gui.ShowDisplaySettings),
+		),
+	)
+}
+
+// ShowNewCampaign creates a campaign folder with commented sample accounts
+// and input files and optionally points the config paths at it
+func (ct *ConfigTab) ShowNewCampaign() {
+	folder := widget.NewEntry()
+	folder.SetPlaceHolder("campaigns/acme")
+	browse := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err == nil && uri != nil {
+				folder.SetText(uri.Path())
+			}
+		}, ct.gui.window)
+	})
+
+	usePaths := widget.NewCheck("Use the new folder for all file paths", nil)
+	usePaths.SetChecked(true)
+
+	items := []*widget.FormItem{
+		{Text: "Folder:", Widget: container.NewBorder(nil, nil, nil, browse, folder), HintText: "Created if missing; existing files are kept"},
+		{Text: "", Widget: usePaths},
+	}
+	form := dialog.NewForm("New Campaign", "Create", "Cancel", items, func(confirmed bool) {
+		dir := strings.TrimSpace(folder.Text)
+		if !confirmed || dir == "" {
+			return
+		}
+		ct.createCampaign(dir, usePaths.Checked)
+	}, ct.gui.window)
+	form.Resize(fyne.NewSize(520, 220))
+	form.Show()
+}
+
+// createCampaign runs storage.InitCampaign and reports what it created
+func (ct *ConfigTab) createCampaign(dir string, usePaths bool) {
+	layout, err := storageInternal.InitCampaign(dir, false)
+	if err != nil {
+		dialog.ShowError(err, ct.gui.window)
+		return
+	}
+
+	if usePaths {
+		// Giữ các thay đổi chưa lưu khác trên form
+		if err := ct.updateConfigFromForm(); err != nil {
+			dialog.ShowError(err, ct.gui.window)
+			return
+		}
+		ct.config = storageInternal.CampaignConfig(ct.config, dir)
+		ct.updateFormFromConfig()
+	}
+
+	var lines []string
+	for _, path := range layout.Created {
+		lines = append(lines, "Created "+path)
+	}
+	for _, path := range layout.Skipped {
+		lines = append(lines, "Kept existing "+path)
+	}
+	if usePaths {
+		lines = append(lines, "", "Config paths now point into the folder: click Save to keep them.")
+	}
+	lines = append(lines, "", "Edit accounts.txt and emails.txt, then start the crawl.")
+	dialog.ShowInformation("New Campaign", strings.Join(lines, "\n"), ct.gui.window)
+	ct.gui.updateStatus(fmt.Sprintf("Campaign folder ready: %s", dir))
+}
@@ -11,6 +11,11 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/orchestrator"
+	storageInternal "linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
 )
 
 // NewControlTab creates a new control tab
@@ -23,6 +28,8 @@ func NewControlTab(gui *CrawlerGUI) *ControlTab {
 	// Initialize buttons
 	tab.startBtn = widget.NewButtonWithIcon("Start", theme.MediaPlayIcon(), tab.StartCrawler)
 	tab.stopBtn = widget.NewButtonWithIcon("Stop", theme.MediaStopIcon(), tab.StopCrawler)
+	tab.pauseBtn = widget.NewButtonWithIcon("Pause", theme.MediaPauseIcon(), tab.PauseCrawler)
+	tab.resumeBtn = widget.NewButtonWithIcon("Resume", theme.MediaPlayIcon(), tab.ResumeCrawler)
 
 	// Style buttons
 	tab.startBtn.Importance = widget.HighImportance
@@ -42,9 +49,12 @@ func NewControlTab(gui *CrawlerGUI) *ControlTab {
 	// Initialize status labels
 	tab.statusLabel = widget.NewLabel("Status: Ready")
 	tab.timeLabel = widget.NewLabel("Time: 00:00:00")
+	tab.forecastLabel = widget.NewLabel("Forecast: -")
+	tab.forecastLabel.Wrapping = fyne.TextWrapWord
 
 	// Set initial button states
 	tab.updateButtonStates(false)
+	gui.crawlController.OnStateChange(tab.onCrawlStateChange)
 
 	return tab
 }
@@ -55,6 +65,8 @@ func (ct *ControlTab) CreateContent() fyne.CanvasObject {
 	controlButtons := container.NewHBox(
 		ct.startBtn,
 		widget.NewSeparator(),
+		ct.pauseBtn,
+		ct.resumeBtn,
 		ct.stopBtn,
 	)
 
@@ -87,6 +99,12 @@ func (ct *ControlTab) CreateContent() fyne.CanvasObject {
 
 	statsCard := widget.NewCard("Statistics", "", statsGrid)
 
+	forecastCard := widget.NewCard("Token Forecast", "", container.NewBorder(
+		nil, nil, nil, widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), ct.refreshForecast),
+		ct.forecastLabel,
+	))
+	ct.refreshForecast()
+
 	// Performance monitoring
 	performanceCard := ct.createPerformanceCard()
 
@@ -100,6 +118,8 @@ func (ct *ControlTab) CreateContent() fyne.CanvasObject {
 		progressCard,
 		widget.NewSeparator(),
 		statsCard,
+		widget.NewSeparator(),
+		forecastCard,
 	)
 
 	// Right column - Activity log mở rộng xuống dưới
@@ -134,7 +154,7 @@ func (ct *ControlTab) createPerformanceCard() *widget.Card {
 		goroutinesLabel.SetText(fmt.Sprintf("Goroutines: %d", numGoroutines))
 
 		// Update connection status
-		if ct.gui.isRunning {
+		if ct.gui.crawlController.Running() {
 			connectionsLabel.SetText("Status: Running")
 		} else {
 			connectionsLabel.SetText("Status: Idle")
@@ -188,7 +208,37 @@ func (ct *ControlTab) StartCrawler() {
 // StopCrawler stops the crawling process - INTEGRATE WITH MAIN GUI
 func (ct *ControlTab) StopCrawler() {
 	// Use the main GUI's stop crawler function
-	ct.gui.stopCrawler()
+	ct.gui.crawlController.Stop()
+}
+
+// PauseCrawler holds the crawler before its next request
+func (ct *ControlTab) PauseCrawler() {
+	if ct.gui.crawlController.Pause() {
+		ct.updateActivity("⏸️ Crawler paused")
+	}
+}
+
+// ResumeCrawler continues a paused crawler
+func (ct *ControlTab) ResumeCrawler() {
+	if ct.gui.crawlController.Resume() {
+		ct.updateActivity("▶️ Crawler resumed")
+	}
+}
+
+// onCrawlStateChange keeps the buttons in sync with crawls started from any tab
+func (ct *ControlTab) onCrawlStateChange(state CrawlState) {
+	ct.updateButtonStates(state != CrawlIdle)
+	switch state {
+	case CrawlRunning:
+		ct.statusLabel.SetText("Status: Running")
+		ct.pauseBtn.Enable()
+	case CrawlPaused:
+		ct.statusLabel.SetText("Status: Paused")
+		ct.resumeBtn.Enable()
+	case CrawlStopping:
+		ct.statusLabel.SetText("Status: Stopping...")
+		ct.stopBtn.Disable()
+	}
 }
 
 // OnCrawlerStarted updates UI when crawler starts
@@ -243,6 +293,9 @@ func (ct *ControlTab) updateButtonStates(running bool) {
 		ct.startBtn.Enable()
 		ct.stopBtn.Disable()
 	}
+	// Pause/Resume chỉ bật theo trạng thái cụ thể trong onCrawlStateChange
+	ct.pauseBtn.Disable()
+	ct.resumeBtn.Disable()
 }
 
 // startProgressUpdates starts the progress update ticker
@@ -276,7 +329,7 @@ func (ct *ControlTab) stopProgressUpdates() {
 
 // updateProgress updates the progress display
 func (ct *ControlTab) updateProgress() {
-	if !ct.gui.isRunning {
+	if !ct.gui.crawlController.Running() {
 		return
 	}
 
@@ -285,60 +338,49 @@ func (ct *ControlTab) updateProgress() {
 	ct.timeLabel.SetText(fmt.Sprintf("Time: %s", ct.formatDuration(elapsed)))
 
 	// Get stats from the active crawler
-	ct.gui.crawlerMux.RLock()
-	autoCrawler := ct.gui.autoCrawler
-	ct.gui.crawlerMux.RUnlock()
+	autoCrawler := ct.gui.crawlController.Active()
 
 	if autoCrawler != nil {
-		// Get stats from SQLite database
-		emailStorage, _, _ := autoCrawler.GetStorageServices()
-		if emailStorage != nil {
-			if stats, err := emailStorage.GetEmailStats(); err == nil {
-				processed := stats["success"] + stats["failed"]
-				success := stats["success"]
-				failed := stats["failed"]
-				hasInfo := stats["has_info"]
-				noInfo := stats["no_info"]
-				pending := stats["pending"]
-
-				ct.processedEmails = processed
-
-				// Update labels
-				ct.processedLabel.SetText(fmt.Sprintf("Processed: %d", processed))
-				ct.successLabel.SetText(fmt.Sprintf("Success: %d (LinkedIn: %d, NoData: %d)", success, hasInfo, noInfo))
-				ct.failedLabel.SetText(fmt.Sprintf("Failed: %d", failed))
-
-				// Update progress bar
-				if ct.totalEmails > 0 {
-					progress := float64(processed) / float64(ct.totalEmails)
-					ct.progressBar.SetValue(progress)
-
-					remaining := ct.totalEmails - processed
-					ct.progressLabel.SetText(fmt.Sprintf("Progress: %d/%d (%.1f%%) - %d remaining",
-						processed, ct.totalEmails, progress*100, remaining))
-				}
-
-				// Calculate rate
-				if elapsed.Seconds() > 0 {
-					rate := float64(processed) / elapsed.Seconds()
-					ct.rateLabel.SetText(fmt.Sprintf("Rate: %.2f emails/s", rate))
-				}
+		// Stats từ StatsService của crawler, cùng số liệu với progress và license
+		stats := autoCrawler.Stats().Snapshot().EmailStats()
+		processed := stats["success"] + stats["failed"]
+		success := stats["success"]
+		failed := stats["failed"]
+		hasInfo := stats["has_info"]
+		noInfo := stats["no_info"]
+		pending := stats["pending"]
+
+		ct.processedEmails = processed
+
+		// Update labels
+		ct.processedLabel.SetText(fmt.Sprintf("Processed: %d", processed))
+		ct.successLabel.SetText(fmt.Sprintf("Success: %d (LinkedIn: %d, NoData: %d)", success, hasInfo, noInfo))
+		ct.failedLabel.SetText(fmt.Sprintf("Failed: %d", failed))
+
+		// Update progress bar
+		if ct.totalEmails > 0 {
+			progress := float64(processed) / float64(ct.totalEmails)
+			ct.progressBar.SetValue(progress)
+
+			remaining := ct.totalEmails - processed
+			ct.progressLabel.SetText(fmt.Sprintf("Progress: %d/%d (%.1f%%) - %d remaining",
+				processed, ct.totalEmails, progress*100, remaining))
+		}
 
-				// Update activity with important events
-				if processed > 0 && processed%25 == 0 {
-					ct.updateActivity(fmt.Sprintf("📊 Processed %d emails (%.1f%% complete)",
-						processed, float64(processed)*100/float64(ct.totalEmails)))
-				}
+		// Calculate rate
+		if elapsed.Seconds() > 0 {
+			rate := float64(processed) / elapsed.Seconds()
+			ct.rateLabel.SetText(fmt.Sprintf("Rate: %.2f emails/s", rate))
+		}
 
-				if hasInfo > 0 && hasInfo%5 == 0 {
-					ct.updateActivity(fmt.Sprintf("🎯 Found %d LinkedIn profiles!", hasInfo))
-				}
+		// Milestone do crawler log theo luật milestone trong config
+		// Log token extraction progress
+		if pending > 0 && processed == 0 {
+			ct.updateActivity("🔑 Extracting tokens from accounts...")
+		}
 
-				// Log token extraction progress
-				if pending > 0 && processed == 0 {
-					ct.updateActivity("🔑 Extracting tokens from accounts...")
-				}
-			}
+		if time.Since(ct.forecastAt) >= forecastRefreshInterval {
+			ct.refreshForecast()
 		}
 
 		// Get token info from crawler instance
@@ -514,3 +556,60 @@ func (ct *ControlTab) ShowWarning(message string) {
 		ct.updateActivity(fmt.Sprintf("⚠️ %s", message))
 	}
 }
+
+// forecastRefreshInterval is how often the token forecast is recomputed while crawling
+const forecastRefreshInterval = 30 * time.Second
+
+// refreshForecast recomputes the token forecast in the background: from the
+// running crawler during a run, otherwise from the database, tokens file and
+// accounts list
+func (ct *ControlTab) refreshForecast() {
+	ct.forecastAt = time.Now()
+	autoCrawler := ct.gui.crawlController.Active()
+	cfg := ct.gui.configTab.ResolvedConfig()
+	accounts := 0
+	if ct.gui.accountsTab != nil {
+		accounts = len(ct.gui.accountsTab.GetAccounts())
+	}
+
+	go func() {
+		var forecast orchestrator.TokenForecast
+		var err error
+		if autoCrawler != nil {
+			forecast, err = autoCrawler.TokenForecast()
+		} else {
+			forecast, err = forecastBeforeRun(cfg, accounts)
+		}
+
+		text := "Forecast: " + forecast.Summary()
+		if err != nil {
+			text = fmt.Sprintf("Forecast unavailable: %v", err)
+		} else if !forecast.Covered() {
+			text = fmt.Sprintf("⚠️ %s - add ~%d accounts", text, forecast.AccountsNeeded-forecast.AccountsLeft)
+		}
+		ct.gui.updateUI <- func() {
+			ct.forecastLabel.SetText(text)
+		}
+	}()
+}
+
+// forecastBeforeRun forecasts tokens for the pending emails in the database,
+// counting every well-formed token in the tokens file as unused
+func forecastBeforeRun(cfg models.Config, accounts int) (orchestrator.TokenForecast, error) {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		return orchestrator.TokenForecast{}, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer emailStorage.CloseDB()
+
+	stats, err := orchestrator.LoadStats(emailStorage)
+	if err != nil {
+		return orchestrator.TokenForecast{}, err
+	}
+
+	tokens := 0
+	if list, err := storageInternal.NewTokenStorage().LoadTokensFromFile(cfg.TokensFilePath); err == nil {
+		tokens, _ = utils.ValidateTokenBatch(list)
+	}
+	return orchestrator.ForecastTokens(emailStorage, stats.Pending, make([]int, tokens), accounts)
+}
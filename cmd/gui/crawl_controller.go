@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"linkedin-crawler/internal/licensing"
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/orchestrator"
+)
+
+// ErrCrawlRunning is returned when a crawl is started while another one runs
+var ErrCrawlRunning = errors.New("another crawl is already running")
+
+// CrawlState is the lifecycle state of the crawl controller
+type CrawlState string
+
+const (
+	CrawlIdle     CrawlState = "idle"
+	CrawlStarting CrawlState = "starting"
+	CrawlRunning  CrawlState = "running"
+	CrawlPaused   CrawlState = "paused"
+	CrawlStopping CrawlState = "stopping"
+)
+
+// CrawlController owns the single AutoCrawler of the GUI. Every tab starts,
+// stops and pauses crawls through it, so only one crawl runs at a time and
+// the license wrapper and GUI logger are injected in one place.
+type CrawlController struct {
+	gui            *CrawlerGUI
+	licenseWrapper *licensing.LicensedCrawlerWrapper
+	logger         orchestrator.GUILogger
+
+	mutex       sync.RWMutex
+	state       CrawlState
+	autoCrawler *orchestrator.AutoCrawler
+	listeners   []func(CrawlState)
+
+	// Run ID và log của lần chạy hiện tại hoặc gần nhất
+	runID      string
+	runLogPath string
+}
+
+// NewCrawlController creates an idle controller
+func NewCrawlController(gui *CrawlerGUI, licenseWrapper *licensing.LicensedCrawlerWrapper) *CrawlController {
+	return &CrawlController{
+		gui:            gui,
+		licenseWrapper: licenseWrapper,
+		state:          CrawlIdle,
+	}
+}
+
+// SetLogger sets the GUI logger that receives the batch processor logs
+func (cc *CrawlController) SetLogger(logger orchestrator.GUILogger) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	cc.logger = logger
+}
+
+// OnStateChange registers fn to be called on the UI thread after each state change
+func (cc *CrawlController) OnStateChange(fn func(CrawlState)) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	cc.listeners = append(cc.listeners, fn)
+}
+
+// State returns the current state
+func (cc *CrawlController) State() CrawlState {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+	return cc.state
+}
+
+// Running reports whether a crawl is in progress (any state but idle)
+func (cc *CrawlController) Running() bool {
+	return cc.State() != CrawlIdle
+}
+
+// Active returns the running AutoCrawler, or nil
+func (cc *CrawlController) Active() *orchestrator.AutoCrawler {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+	return cc.autoCrawler
+}
+
+// RunID returns the ID of the current or most recent run, or ""
+func (cc *CrawlController) RunID() string {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+	return cc.runID
+}
+
+// RunLogPath returns the log file of the current or most recent run, or ""
+func (cc *CrawlController) RunLogPath() string {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+	return cc.runLogPath
+}
+
+// setState changes the state and notifies the listeners
+func (cc *CrawlController) setState(state CrawlState) {
+	cc.mutex.Lock()
+	cc.state = state
+	listeners := append([]func(CrawlState){}, cc.listeners...)
+	cc.mutex.Unlock()
+	cc.notify(listeners)
+}
+
+// setStateOf changes the state only while autoCrawler is still the running
+// crawler and, when from is given, the current state is one of from. Stop and
+// Pause use it so a crawl that has just finished stays idle.
+func (cc *CrawlController) setStateOf(autoCrawler *orchestrator.AutoCrawler, state CrawlState, from ...CrawlState) bool {
+	cc.mutex.Lock()
+	if autoCrawler == nil || cc.autoCrawler != autoCrawler ||
+		(len(from) > 0 && !slices.Contains(from, cc.state)) {
+		cc.mutex.Unlock()
+		return false
+	}
+	cc.state = state
+	listeners := append([]func(CrawlState){}, cc.listeners...)
+	cc.mutex.Unlock()
+	cc.notify(listeners)
+	return true
+}
+
+// notify calls the listeners on the UI thread. They read the state when they
+// run, so the last one sees the final state even when two changes race.
+func (cc *CrawlController) notify(listeners []func(CrawlState)) {
+	for _, fn := range listeners {
+		fn := fn
+		cc.gui.updateUI <- func() { fn(cc.State()) }
+	}
+}
+
+// Run creates an AutoCrawler from cfg and runs it to completion. It blocks,
+// so callers run it in a goroutine; ErrCrawlRunning means nothing was started.
+func (cc *CrawlController) Run(cfg models.Config) error {
+	_, err := cc.run(cfg, 0)
+	return err
+}
+
+// Calibrate crawls only the first emails pending emails with cfg and returns
+// what the burst measured and recommends. It blocks like Run.
+func (cc *CrawlController) Calibrate(cfg models.Config, emails int) (orchestrator.Calibration, error) {
+	autoCrawler, err := cc.run(cfg, emails)
+	if err != nil {
+		return orchestrator.Calibration{}, err
+	}
+	return autoCrawler.Calibration()
+}
+
+// run runs one crawl limited to emailLimit emails (0 = Max Emails/Run of the
+// config) and returns its AutoCrawler, or nil when none was created
+func (cc *CrawlController) run(cfg models.Config, emailLimit int) (*orchestrator.AutoCrawler, error) {
+	// Giữ chỗ trước khi tạo crawler: crawl bị từ chối không tạo AutoCrawler nào
+	cc.mutex.Lock()
+	if cc.state != CrawlIdle {
+		cc.mutex.Unlock()
+		return nil, ErrCrawlRunning
+	}
+	cc.state = CrawlStarting
+	cc.runID, cc.runLogPath = "", ""
+	logger := cc.logger
+	cc.mutex.Unlock()
+	cc.setState(CrawlStarting)
+
+	autoCrawler, err := orchestrator.New(cfg)
+	if err != nil {
+		cc.setState(CrawlIdle)
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	batchProcessor := autoCrawler.GetBatchProcessor()
+	batchProcessor.SetLicenseWrapper(cc.licenseWrapper)
+	log.Printf("✅ License wrapper injected into batch processor")
+	if logger != nil {
+		batchProcessor.SetGUILogger(logger)
+	}
+	batchProcessor.SetTokenPrompt(cc.gui.promptTokens)
+	autoCrawler.OnMemoryPressure(cc.gui.trimMemory)
+	if emailLimit > 0 {
+		autoCrawler.SetEmailLimit(emailLimit)
+	}
+
+	cc.mutex.Lock()
+	cc.autoCrawler = autoCrawler
+	cc.runID = autoCrawler.GetRunID()
+	cc.runLogPath = autoCrawler.GetRunLogPath()
+	cc.state = CrawlRunning
+	listeners := append([]func(CrawlState){}, cc.listeners...)
+	cc.mutex.Unlock()
+	cc.notify(listeners)
+
+	err = autoCrawler.Run()
+
+	// Bỏ crawler và về idle trong cùng một lần khóa: Stop/Pause đến muộn không ghi đè được
+	cc.mutex.Lock()
+	cc.autoCrawler = nil
+	cc.state = CrawlIdle
+	listeners = append([]func(CrawlState){}, cc.listeners...)
+	cc.mutex.Unlock()
+	cc.notify(listeners)
+
+	if atomic.LoadInt32(autoCrawler.GetShutdownRequested()) == 1 {
+		completed, failed := batchProcessor.RunProgress()
+		waiting, _ := batchProcessor.RateLimitProgress()
+		cc.gui.postStatus(StatusSourceCrawler, SeverityWarning,
+			fmt.Sprintf("Stopped - %d emails completed (%d failed, %d rate limited) before stopping", completed, failed, waiting))
+	}
+	return autoCrawler, err
+}
+
+// Stop signals the running crawler to stop and returns how many emails it has
+// completed so far; ok is false when no crawl is running
+func (cc *CrawlController) Stop() (completed int, ok bool) {
+	autoCrawler := cc.Active()
+	if autoCrawler == nil {
+		return 0, false
+	}
+	atomic.StoreInt32(autoCrawler.GetShutdownRequested(), 1)
+	autoCrawler.Resume() // Worker đang tạm dừng cần thức dậy để thấy tín hiệu dừng
+	if !cc.setStateOf(autoCrawler, CrawlStopping) {
+		return 0, false // Crawl vừa tự kết thúc
+	}
+
+	completed, _ = autoCrawler.GetBatchProcessor().RunProgress()
+	cc.gui.postStatus(StatusSourceCrawler, SeverityInfo, fmt.Sprintf("Stopping... %d emails completed so far", completed))
+	return completed, true
+}
+
+// Pause holds the running crawler before its next request
+func (cc *CrawlController) Pause() bool {
+	autoCrawler := cc.Active()
+	if !cc.setStateOf(autoCrawler, CrawlPaused, CrawlRunning) {
+		return false
+	}
+	autoCrawler.Pause()
+	cc.gui.postStatus(StatusSourceCrawler, SeverityInfo, "Paused")
+	return true
+}
+
+// Resume continues a paused crawler
+func (cc *CrawlController) Resume() bool {
+	autoCrawler := cc.Active()
+	if !cc.setStateOf(autoCrawler, CrawlRunning, CrawlPaused) {
+		return false
+	}
+	autoCrawler.Resume()
+	cc.gui.postStatus(StatusSourceCrawler, SeverityInfo, "Resumed")
+	return true
+}
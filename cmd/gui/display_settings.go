@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Khoảng UI scale cho phép trong Display Settings
+const (
+	minUIScale = 0.8
+	maxUIScale = 2.0
+)
+
+// DisplaySettings are the accessibility options of the GUI, kept in the app
+// preferences apart from the crawler config
+type DisplaySettings struct {
+	Scale        float64 // Hệ số cỡ chữ, icon và khoảng cách (1 = mặc định)
+	HighContrast bool
+}
+
+// highContrastColors replace the default palette when HighContrast is on:
+// white text on black with yellow accents
+var highContrastColors = map[fyne.ThemeColorName]color.Color{
+	theme.ColorNameBackground:        color.Black,
+	theme.ColorNameOverlayBackground: color.Black,
+	theme.ColorNameMenuBackground:    color.Black,
+	theme.ColorNameHeaderBackground:  color.NRGBA{R: 0x1a, G: 0x1a, B: 0x1a, A: 0xff},
+	theme.ColorNameInputBackground:   color.Black,
+	theme.ColorNameButton:            color.NRGBA{R: 0x1a, G: 0x1a, B: 0x1a, A: 0xff},
+	theme.ColorNameDisabledButton:    color.NRGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff},
+	theme.ColorNameForeground:        color.White,
+	theme.ColorNameDisabled:          color.NRGBA{R: 0xbf, G: 0xbf, B: 0xbf, A: 0xff},
+	theme.ColorNamePlaceHolder:       color.NRGBA{R: 0xd0, G: 0xd0, B: 0xd0, A: 0xff},
+	theme.ColorNameInputBorder:       color.White,
+	theme.ColorNameSeparator:         color.White,
+	theme.ColorNamePrimary:           color.NRGBA{R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	theme.ColorNameFocus:             color.NRGBA{R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	theme.ColorNameSelection:         color.NRGBA{R: 0xff, G: 0xd7, B: 0x00, A: 0x66},
+	theme.ColorNameHover:             color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x33},
+	theme.ColorNamePressed:           color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x55},
+	theme.ColorNameHyperlink:         color.NRGBA{R: 0x66, G: 0xcc, B: 0xff, A: 0xff},
+	theme.ColorNameScrollBar:         color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x99},
+	theme.ColorNameShadow:            color.Transparent,
+	theme.ColorNameError:             color.NRGBA{R: 0xff, G: 0x6b, B: 0x6b, A: 0xff},
+	theme.ColorNameWarning:           color.NRGBA{R: 0xff, G: 0xb0, B: 0x00, A: 0xff},
+	theme.ColorNameSuccess:           color.NRGBA{R: 0x4c, G: 0xff, B: 0x4c, A: 0xff},
+
+	// Chữ trên nền vàng/đỏ/xanh sáng: đen cho đủ tương phản
+	theme.ColorNameForegroundOnPrimary: color.Black,
+	theme.ColorNameForegroundOnError:   color.Black,
+	theme.ColorNameForegroundOnWarning: color.Black,
+	theme.ColorNameForegroundOnSuccess: color.Black,
+}
+
+// accessibleTheme is the default theme with every size multiplied by
+// Scale and, with HighContrast, the highContrastColors palette
+type accessibleTheme struct {
+	settings DisplaySettings
+}
+
+func (t *accessibleTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if t.settings.HighContrast {
+		if c, ok := highContrastColors[name]; ok {
+			return c
+		}
+	}
+	return theme.DefaultTheme().Color(name, variant)
+}
+
+func (t *accessibleTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return theme.DefaultTheme().Font(style)
+}
+
+func (t *accessibleTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return theme.DefaultTheme().Icon(name)
+}
+
+func (t *accessibleTheme) Size(name fyne.ThemeSizeName) float32 {
+	return theme.DefaultTheme().Size(name) * float32(t.settings.Scale)
+}
+
+// loadDisplaySettings reads the display settings from the preferences
+func (gui *CrawlerGUI) loadDisplaySettings() DisplaySettings {
+	prefs := gui.app.Preferences()
+	settings := DisplaySettings{
+		Scale:        prefs.FloatWithFallback("ui_scale", 1),
+		HighContrast: prefs.BoolWithFallback("ui_high_contrast", false),
+	}
+	if settings.Scale < minUIScale || settings.Scale > maxUIScale {
+		settings.Scale = 1
+	}
+	return settings
+}
+
+// setDisplayTheme applies settings to the whole app
+func (gui *CrawlerGUI) setDisplayTheme(settings DisplaySettings) {
+	gui.app.Settings().SetTheme(&accessibleTheme{settings: settings})
+	// Layout 1200x700 chỉ vừa ở cỡ mặc định: cho phép phóng to cửa sổ khi tăng scale
+	gui.window.SetFixedSize(settings.Scale <= 1)
+}
+
+// ShowDisplaySettings lets the user scale the UI and turn on high contrast.
+// Changes are previewed at once and kept only when saved.
+func (gui *CrawlerGUI) ShowDisplaySettings() {
+	current := gui.loadDisplaySettings()
+	settings := current
+
+	scaleLabel := widget.NewLabel(fmt.Sprintf("%.0f%%", current.Scale*100))
+	scale := widget.NewSlider(minUIScale, maxUIScale)
+	scale.Step = 0.1
+	scale.SetValue(current.Scale)
+	scale.OnChanged = func(value float64) {
+		settings.Scale = value
+		scaleLabel.SetText(fmt.Sprintf("%.0f%%", value*100))
+	}
+	// Đổi theme vẽ lại toàn bộ cửa sổ: chỉ áp dụng khi thả slider
+	scale.OnChangeEnded = func(float64) {
+		gui.setDisplayTheme(settings)
+	}
+
+	contrast := widget.NewCheck("High contrast", nil)
+	contrast.SetChecked(current.HighContrast)
+	contrast.OnChanged = func(on bool) {
+		settings.HighContrast = on
+		gui.setDisplayTheme(settings)
+	}
+
+	items := []*widget.FormItem{
+		{Text: "UI Scale:", Widget: container.NewBorder(nil, nil, nil, scaleLabel, scale), HintText: "Size of text, icons and spacing; 100% = default"},
+		{Text: "", Widget: contrast, HintText: "White text on black with yellow highlights"},
+	}
+	form := dialog.NewForm("Display Settings", "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			gui.setDisplayTheme(current)
+			return
+		}
+		prefs := gui.app.Preferences()
+		prefs.SetFloat("ui_scale", settings.Scale)
+		prefs.SetBool("ui_high_contrast", settings.HighContrast)
+		gui.setDisplayTheme(settings)
+		gui.updateStatus("✅ Display settings saved")
+	}, gui.window)
+	form.Resize(fyne.NewSize(520, 220))
+	form.Show()
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
@@ -14,10 +16,12 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
-	"linkedin-crawler/internal/config"
+	"linkedin-crawler/internal/integrations"
+	"linkedin-crawler/internal/models"
 	"linkedin-crawler/internal/orchestrator"
 	storageInternal "linkedin-crawler/internal/storage"
 	"linkedin-crawler/internal/utils"
@@ -29,12 +33,15 @@ type EmailsTab struct {
 	emails        []string
 	emailData     binding.StringList
 	importBtn     *widget.Button
+	imapBtn       *widget.Button
+	pasteBtn      *widget.Button
 	clearBtn      *widget.Button
+	addEntry      *widget.Entry
+	addBtn        *widget.Button
 	startCrawlBtn *widget.Button
 	stopCrawlBtn  *widget.Button
 
-	logText   *widget.RichText
-	logBuffer []string
+	logView *LogView
 
 	totalLabel    *widget.Label
 	pendingLabel  *widget.Label
@@ -52,13 +59,12 @@ type EmailsTab struct {
 	crawlCancel context.CancelFunc
 	autoCrawler *orchestrator.AutoCrawler
 
-	// Email status cache để tránh query database liên tục
+	// Status của các emails trên trang hiện tại, nạp bằng một query mỗi lần
+	// đổi trang; pageVersion bỏ kết quả của trang cũ
 	emailStatusCache map[string]string
-	lastCacheUpdate  time.Time
+	pageVersion      int64
 
-	// Stats refresh ticker
-	statsRefreshTicker *time.Ticker
-	lastStats          map[string]int // Cache stats để tránh reset về 0
+	lastStats map[string]int // Cache stats để tránh reset về 0
 
 	// OPTIMIZATION: Virtual scrolling và pagination
 	displayEmails    []string // Emails hiển thị trong UI (limited)
@@ -81,7 +87,6 @@ func NewEmailsTab(gui *CrawlerGUI) *EmailsTab {
 		emails:           []string{}, // Khởi tạo với empty slice thay vì nil
 		emailData:        binding.NewStringList(),
 		emailStatusCache: make(map[string]string),
-		lastCacheUpdate:  time.Time{},
 		lastStats:        make(map[string]int),
 
 		// OPTIMIZATION: Pagination settings
@@ -96,17 +101,23 @@ func NewEmailsTab(gui *CrawlerGUI) *EmailsTab {
 
 	// Initialize UI components
 	tab.importBtn = widget.NewButtonWithIcon("Import", theme.FolderOpenIcon(), tab.ImportEmails)
+	tab.imapBtn = widget.NewButtonWithIcon("Import IMAP", theme.MailComposeIcon(), tab.ImportFromIMAP)
+	tab.pasteBtn = widget.NewButtonWithIcon("Paste", theme.ContentPasteIcon(), tab.PasteEmails)
 	tab.clearBtn = widget.NewButtonWithIcon("Clear All", theme.DeleteIcon(), tab.ClearAllEmails)
 	tab.clearBtn.Importance = widget.DangerImportance
 
+	tab.addEntry = widget.NewEntry()
+	tab.addEntry.SetPlaceHolder("name@company.com")
+	tab.addEntry.OnSubmitted = func(string) { tab.AddEmail() }
+	tab.addBtn = widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), tab.AddEmail)
+
 	tab.startCrawlBtn = widget.NewButtonWithIcon("Start Crawl", theme.MediaPlayIcon(), tab.StartCrawl)
 	tab.stopCrawlBtn = widget.NewButtonWithIcon("Stop Crawl", theme.MediaStopIcon(), tab.StopCrawl)
 	tab.stopCrawlBtn.Importance = widget.DangerImportance
 	tab.stopCrawlBtn.Disable() // Initially disabled
+	gui.crawlController.OnStateChange(tab.onCrawlStateChange)
 
-	tab.logText = widget.NewRichText()
-	tab.logText.Wrapping = fyne.TextWrapWord
-	tab.logBuffer = []string{} // Initialize with empty slice
+	tab.logView = NewLogView(gui, "Email Crawl Log")
 
 	// Initialize labels
 	tab.totalLabel = widget.NewLabel("Total: 0")
@@ -122,7 +133,7 @@ func NewEmailsTab(gui *CrawlerGUI) *EmailsTab {
 	// Setup emails list with safety checks
 	tab.setupEmailsList()
 
-	// Start stats refresh ticker with throttling
+	// Start stats refresh
 	tab.startStatsRefresh()
 
 	return tab
@@ -144,6 +155,8 @@ func (et *EmailsTab) getTotalPages() int {
 func (et *EmailsTab) CreateContent() fyne.CanvasObject {
 	fileButtons := container.NewHBox(
 		et.importBtn,
+		et.imapBtn,
+		et.pasteBtn,
 		et.clearBtn,
 		widget.NewButton("Refresh", et.RefreshEmailsList),
 	)
@@ -209,8 +222,14 @@ func (et *EmailsTab) CreateContent() fyne.CanvasObject {
 	statsGrid := container.NewVBox(statsRow1, statsRow2)
 
 	leftPanel := container.NewVBox(
-		widget.NewCard("File Operations", "", fileButtons),
-		widget.NewCard("Statistics", "", statsGrid),
+		widget.NewCard("File Operations", "", container.NewVBox(
+			fileButtons,
+			container.NewBorder(nil, nil, nil, et.addBtn, et.addEntry),
+		)),
+		widget.NewCard("Statistics", "", container.NewVBox(
+			statsGrid,
+			widget.NewButtonWithIcon("Re-queue Failed", theme.ViewRefreshIcon(), et.ShowRequeueFailed),
+		)),
 		widget.NewCard("Pagination", "", paginationControls), // NEW: Pagination controls
 		container.NewScroll(et.emailsList),
 	)
@@ -229,10 +248,9 @@ func (et *EmailsTab) CreateContent() fyne.CanvasObject {
 	)
 
 	// Log area - MỞ RỘNG XUỐNG DƯỚI
-	logScroll := container.NewScroll(et.logText)
 	logArea := container.NewBorder(
 		widget.NewLabel("Email Crawl Log:"), nil, nil, nil,
-		logScroll,
+		et.logView.CreateContent(),
 	)
 
 	// Right panel with expanded log area
@@ -307,32 +325,24 @@ func (et *EmailsTab) updateDisplayEmails() {
 	}
 }
 
-// OPTIMIZATION: Update emails list với limited items
+// updateEmailsList replaces the items of the bound list in place with the
+// current page and loads their statuses in one batched query
 func (et *EmailsTab) updateEmailsList() {
-	// SAFETY CHECK: Kiểm tra emailData không nil
-	if et.emailData == nil {
-		et.emailData = binding.NewStringList()
-	}
-
-	// Clear existing data
-	et.emailData = binding.NewStringList()
-
 	// SAFETY CHECK: Kiểm tra displayEmails không nil
 	if et.displayEmails == nil {
 		et.displayEmails = []string{}
 	}
 
-	// Add only display emails (max 1000 per page)
+	page := make([]string, 0, len(et.displayEmails))
 	for _, email := range et.displayEmails {
 		if email != "" { // Skip empty emails
-			et.emailData.Append(email)
+			page = append(page, email)
 		}
 	}
 
-	// SAFETY CHECK: Kiểm tra emailsList không nil trước khi refresh
-	if et.emailsList != nil {
-		et.emailsList.Refresh()
-	}
+	// List đã bind với emailData, tạo binding mới thì list không thấy thay đổi
+	et.emailData.Set(page)
+	et.refreshPageStatuses()
 }
 
 func (et *EmailsTab) setupEmailsList() {
@@ -347,7 +357,9 @@ func (et *EmailsTab) setupEmailsList() {
 			icon := widget.NewIcon(theme.MailSendIcon())
 			email := widget.NewLabel("Email")
 			status := widget.NewLabel("Status")
-			return container.NewHBox(icon, container.NewVBox(email, status))
+			editBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil)
+			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+			return container.NewHBox(icon, container.NewVBox(email, status), layout.NewSpacer(), editBtn, deleteBtn)
 		},
 		func(id binding.DataItem, obj fyne.CanvasObject) {
 			// SAFETY CHECK: Kiểm tra obj không nil
@@ -361,7 +373,7 @@ func (et *EmailsTab) setupEmailsList() {
 			}
 
 			container, ok := obj.(*fyne.Container)
-			if !ok || container == nil || len(container.Objects) < 2 {
+			if !ok || container == nil || len(container.Objects) < 5 {
 				return // Skip if cast fails or container invalid
 			}
 
@@ -387,11 +399,14 @@ func (et *EmailsTab) setupEmailsList() {
 
 			emailLabel.SetText(str)
 
-			// OPTIMIZATION: Only get status for visible emails
-			status := "Pending"                                         // Default status - avoid expensive DB queries for all emails
-			if et.displayEmails != nil && len(et.displayEmails) < 100 { // Only get real status for small lists
-				status = et.getEmailStatus(str)
+			if editBtn, ok := container.Objects[3].(*widget.Button); ok {
+				editBtn.OnTapped = func() { et.EditEmail(str) }
+			}
+			if deleteBtn, ok := container.Objects[4].(*widget.Button); ok {
+				deleteBtn.OnTapped = func() { et.DeleteEmail(str) }
 			}
+
+			status := et.getEmailStatus(str)
 			statusLabel.SetText(status)
 
 			// Set appropriate icon based on status
@@ -418,183 +433,483 @@ func (et *EmailsTab) setupEmailsList() {
 	}
 }
 
-// OPTIMIZATION: Start stats refresh ticker with throttling
+// startStatsRefresh refreshes the stats from the database on the stats interval
 func (et *EmailsTab) startStatsRefresh() {
-	if et.statsRefreshTicker != nil {
-		et.statsRefreshTicker.Stop()
-	}
-
-	et.statsRefreshTicker = time.NewTicker(5 * time.Second) // Slower refresh: 5 seconds
-	go func() {
-		defer func() {
-			if et.statsRefreshTicker != nil {
-				et.statsRefreshTicker.Stop()
-			}
-		}()
-
-		for {
-			select {
-			case <-et.statsRefreshTicker.C:
-				// OPTIMIZATION: Throttle updates to prevent UI lag
-				if time.Since(et.lastUpdateTime) > 3*time.Second {
-					et.gui.updateUI <- func() {
-						et.updateStatsFromDatabase()
-						et.lastUpdateTime = time.Now()
-						atomic.AddInt32(&et.updateCount, 1)
-					}
-				}
-			case <-et.gui.ctx.Done():
-				return
-			}
-		}
-	}()
+	et.gui.refreshScheduler.Schedule("emails-stats", RefreshStats, func() {
+		et.updateStatsFromDatabase()
+		et.lastUpdateTime = time.Now()
+		atomic.AddInt32(&et.updateCount, 1)
+	})
 }
 
-// OPTIMIZATION: Chunked, non-blocking import with progress
+// ImportEmails imports emails from a file chosen by the user
 func (et *EmailsTab) ImportEmails() {
 	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
 		if err != nil || reader == nil {
 			return
 		}
-		defer reader.Close()
-
-		// Show progress dialog with cancel button
-		progress := dialog.NewProgressInfinite("Importing", "Reading file...", et.gui.window)
-		progress.Show()
+		et.importEmailsFrom(reader)
+	}, et.gui.window)
+}
 
-		// Process in background thread to avoid blocking UI
-		go func() {
-			defer progress.Hide()
+// PasteEmails imports emails from the clipboard, e.g. a column copied out of
+// a spreadsheet
+func (et *EmailsTab) PasteEmails() {
+	content := et.gui.app.Clipboard().Content()
+	if strings.TrimSpace(content) == "" {
+		dialog.ShowInformation("Paste Emails", "The clipboard is empty", et.gui.window)
+		return
+	}
+	et.importEmailsFrom(io.NopCloser(strings.NewReader(content)))
+}
 
-			startTime := time.Now()
+// OPTIMIZATION: Chunked, non-blocking import with progress
+func (et *EmailsTab) importEmailsFrom(reader io.ReadCloser) {
+	// Show progress dialog with cancel button
+	progress := dialog.NewProgressInfinite("Importing", "Reading emails...", et.gui.window)
+	progress.Show()
 
-			// OPTIMIZATION: Use streaming reader for large files
-			scanner := bufio.NewScanner(reader)
-			scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024) // 10MB buffer for huge files
+	// Process in background thread to avoid blocking UI
+	go func() {
+		defer progress.Hide()
+		defer reader.Close()
 
-			emailSet := make(map[string]struct{}) // O(1) deduplication
-			emails := make([]string, 0, 100000)   // Pre-allocate for performance
+		startTime := time.Now()
 
-			emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+		// OPTIMIZATION: Use streaming reader for large files
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024) // 10MB buffer for huge files
 
-			var totalLines, validEmails, duplicates, invalidEmails int
-			chunkSize := 10000 // Process 10k lines at a time
+		emailSet := make(map[string]struct{}) // O(1) deduplication
+		emails := make([]string, 0, 100000)   // Pre-allocate for performance
 
-			et.gui.updateUI <- func() {
-				progress.Hide()
-				progress = dialog.NewProgressInfinite("Processing", "Validating emails...", et.gui.window)
-				progress.Show()
-			}
+		emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-				totalLines++
+		var totalLines, validEmails, duplicates, invalidEmails int
+		chunkSize := 10000 // Process 10k lines at a time
 
-				// Skip empty lines and comments
-				if line == "" || strings.HasPrefix(line, "#") {
-					continue
-				}
+		et.gui.updateUI <- func() {
+			progress.Hide()
+			progress = dialog.NewProgressInfinite("Processing", "Validating emails...", et.gui.window)
+			progress.Show()
+		}
 
-				// Extract email from CSV format
-				email := line
-				if strings.Contains(line, ",") {
-					parts := strings.Split(line, ",")
-					email = strings.TrimSpace(parts[len(parts)-1])
-				}
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			totalLines++
 
-				// Validate email format
-				if !emailRegex.MatchString(email) {
-					invalidEmails++
-					continue
-				}
+			// Skip empty lines and comments
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
 
-				// Check for duplicates
-				emailLower := strings.ToLower(email)
-				if _, exists := emailSet[emailLower]; exists {
-					duplicates++
-					continue
+			// Extract email from CSV format (hoặc các cột cách nhau bằng tab khi copy từ bảng tính)
+			email := line
+			if strings.ContainsAny(line, ",\t") {
+				parts := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == '\t' })
+				if len(parts) > 0 {
+					email = strings.TrimSpace(parts[len(parts)-1])
 				}
+			}
 
-				emailSet[emailLower] = struct{}{}
-				emails = append(emails, email)
-				validEmails++
-
-				// OPTIMIZATION: Update progress periodically and yield to UI
-				if totalLines%chunkSize == 0 {
-					currentCount := len(emails)
-					et.gui.updateUI <- func() {
-						progress.Hide()
-						progress = dialog.NewProgressInfinite(
-							"Processing",
-							fmt.Sprintf("Processed %d lines, found %d valid emails...", totalLines, currentCount),
-							et.gui.window,
-						)
-						progress.Show()
-					}
+			// Validate email format
+			if !emailRegex.MatchString(email) {
+				invalidEmails++
+				continue
+			}
 
-					// Small delay to let UI refresh
-					time.Sleep(10 * time.Millisecond)
-				}
+			// Check for duplicates
+			emailLower := strings.ToLower(email)
+			if _, exists := emailSet[emailLower]; exists {
+				duplicates++
+				continue
 			}
 
-			if err := scanner.Err(); err != nil {
+			emailSet[emailLower] = struct{}{}
+			emails = append(emails, email)
+			validEmails++
+
+			// OPTIMIZATION: Update progress periodically and yield to UI
+			if totalLines%chunkSize == 0 {
+				currentCount := len(emails)
 				et.gui.updateUI <- func() {
 					progress.Hide()
-					dialog.ShowError(fmt.Errorf("Error reading file: %v", err), et.gui.window)
+					progress = dialog.NewProgressInfinite(
+						"Processing",
+						fmt.Sprintf("Processed %d lines, found %d valid emails...", totalLines, currentCount),
+						et.gui.window,
+					)
+					progress.Show()
 				}
+
+				// Small delay to let UI refresh
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			et.gui.updateUI <- func() {
+				progress.Hide()
+				dialog.ShowError(fmt.Errorf("Error reading file: %v", err), et.gui.window)
+			}
+			return
+		}
+
+		processingTime := time.Since(startTime)
+
+		// Cross-check với database và file kết quả trước khi commit
+		check := et.checkImportAgainstExisting(emails)
+
+		// SAFETY: Initialize if et.emails is nil
+		if et.emails == nil {
+			et.emails = []string{}
+		}
+
+		// OPTIMIZATION: Update UI with final results
+		finish := func(imported []string) {
+			// Đang crawl: thêm vào crawl đang chạy thay vì thay danh sách
+			if autoCrawler := et.gui.crawlController.Active(); autoCrawler != nil {
+				et.hotAddEmails(autoCrawler, imported)
 				return
 			}
 
-			processingTime := time.Since(startTime)
+			// Store all emails but limit UI display
+			et.emails = imported
+			et.totalEmailCount = len(imported)
+			et.currentPage = 0
 
-			// SAFETY: Initialize if et.emails is nil
-			if et.emails == nil {
-				et.emails = []string{}
+			// Update display with pagination
+			et.updateDisplayEmails()
+			et.updateStats()
+
+			// Show detailed results
+			message := fmt.Sprintf(
+				"Import completed in %.2f seconds!\n\n"+
+					"📊 Results:\n"+
+					"✅ Imported emails: %s\n"+
+					"📝 Total lines processed: %s\n"+
+					"🔄 Duplicates skipped: %s\n"+
+					"❌ Invalid emails: %s\n"+
+					"🆕 New: %s | ⏳ Already pending: %s | ✔️ Already processed: %s\n\n"+
+					"💡 Large dataset detected!\n"+
+					"Using pagination: %d emails per page\n"+
+					"Current page: 1/%d",
+				processingTime.Seconds(),
+				et.formatNumber(len(imported)),
+				et.formatNumber(totalLines),
+				et.formatNumber(duplicates),
+				et.formatNumber(invalidEmails),
+				et.formatNumber(len(check.New)),
+				et.formatNumber(len(check.Pending)),
+				et.formatNumber(len(check.Processed)),
+				et.emailsPerPage,
+				et.getTotalPages(),
+			)
+
+			dialog.ShowInformation("Import Results", message, et.gui.window)
+			et.gui.updateStatus(fmt.Sprintf("Imported %s emails (showing page 1/%d)",
+				et.formatNumber(len(imported)), et.getTotalPages()))
+			et.addLog(fmt.Sprintf("📥 Import: %s emails in %.2f seconds (new %d, pending %d, processed %d)",
+				et.formatNumber(len(imported)), processingTime.Seconds(),
+				len(check.New), len(check.Pending), len(check.Processed)))
+		}
+
+		et.gui.updateUI <- func() {
+			progress.Hide()
+
+			if len(check.Processed) == 0 {
+				finish(emails)
+				return
 			}
 
-			// OPTIMIZATION: Update UI with final results
-			et.gui.updateUI <- func() {
-				// Store all emails but limit UI display
-				et.emails = emails
-				et.totalEmailCount = len(emails)
-				et.currentPage = 0
+			// Hỏi người dùng có bỏ qua các email đã xử lý hay không
+			summary := fmt.Sprintf(
+				"Found %s valid emails:\n\n"+
+					"🆕 New: %s\n"+
+					"⏳ Already pending: %s\n"+
+					"✔️ Already processed: %s\n\n"+
+					"Skip already processed emails?",
+				et.formatNumber(validEmails),
+				et.formatNumber(len(check.New)),
+				et.formatNumber(len(check.Pending)),
+				et.formatNumber(len(check.Processed)),
+			)
+			dialog.ShowCustomConfirm("Import Check", "Skip processed", "Import all",
+				widget.NewLabel(summary), func(skip bool) {
+					if skip {
+						finish(append(append([]string{}, check.New...), check.Pending...))
+					} else {
+						finish(emails)
+					}
+				}, et.gui.window)
+		}
+	}()
+}
 
-				// Update display with pagination
-				et.updateDisplayEmails()
-				et.updateStats()
+// checkImportAgainstExisting classifies imported emails against the database
+// and the hit file; emails already in the hit file count as processed
+func (et *EmailsTab) checkImportAgainstExisting(emails []string) *storageInternal.ImportCheck {
+	cfg := et.gui.configTab.ResolvedConfig()
 
-				progress.Hide()
+	emailStorage := storageInternal.NewEmailStorage()
+	check := &storageInternal.ImportCheck{New: emails}
+	if err := emailStorage.InitDB(); err == nil {
+		if result, err := emailStorage.CheckImportAgainstDB(emails); err == nil {
+			check = result
+		}
+		emailStorage.CloseDB()
+	}
+
+	hits, err := utils.ReadAllHitResults(cfg.OutputFilePath)
+	if err != nil || len(hits) == 0 {
+		return check
+	}
+
+	hitEmails := make(map[string]bool, len(hits))
+	for _, hit := range hits {
+		hitEmails[strings.ToLower(hit.Email)] = true
+	}
+
+	var stillNew []string
+	for _, email := range check.New {
+		if hitEmails[strings.ToLower(email)] {
+			check.Processed = append(check.Processed, email)
+		} else {
+			stillNew = append(stillNew, email)
+		}
+	}
+	check.New = stillNew
 
-				// Show detailed results
-				message := fmt.Sprintf(
-					"Import completed in %.2f seconds!\n\n"+
-						"📊 Results:\n"+
-						"✅ Valid emails: %s\n"+
-						"📝 Total lines processed: %s\n"+
-						"🔄 Duplicates skipped: %s\n"+
-						"❌ Invalid emails: %s\n\n"+
-						"💡 Large dataset detected!\n"+
-						"Using pagination: %d emails per page\n"+
-						"Current page: 1/%d",
-					processingTime.Seconds(),
-					et.formatNumber(validEmails),
-					et.formatNumber(totalLines),
-					et.formatNumber(duplicates),
-					et.formatNumber(invalidEmails),
-					et.emailsPerPage,
-					et.getTotalPages(),
-				)
-
-				dialog.ShowInformation("Import Results", message, et.gui.window)
-				et.gui.updateStatus(fmt.Sprintf("Imported %s emails (showing page 1/%d)",
-					et.formatNumber(validEmails), et.getTotalPages()))
-				et.addLog(fmt.Sprintf("📥 Import: %s emails in %.2f seconds",
-					et.formatNumber(validEmails), processingTime.Seconds()))
+	return check
+}
+
+// ImportFromIMAP harvests addresses from the mailbox set under Config → IMAP
+// Import and adds the new ones to the email list; emails already processed
+// are skipped
+func (et *EmailsTab) ImportFromIMAP() {
+	cfg := et.gui.configTab.ResolvedConfig()
+	if cfg.IMAPServer == "" || cfg.IMAPUsername == "" {
+		dialog.ShowError(fmt.Errorf("set the IMAP server and username under Config → IMAP Import first"), et.gui.window)
+		return
+	}
+
+	progress := dialog.NewProgressInfinite("IMAP Import", fmt.Sprintf("Reading %s on %s...", cfg.IMAPMailbox, cfg.IMAPServer), et.gui.window)
+	progress.Show()
+	et.imapBtn.Disable()
+
+	go func() {
+		harvest, err := integrations.HarvestIMAP(cfg)
+		var check *storageInternal.ImportCheck
+		if err == nil {
+			check = et.checkImportAgainstExisting(harvest.Emails)
+		}
+
+		et.gui.updateUI <- func() {
+			progress.Hide()
+			et.imapBtn.Enable()
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("IMAP import failed: %w", err), et.gui.window)
+				return
 			}
-		}()
+
+			existing := make(map[string]bool, len(et.emails))
+			for _, email := range et.emails {
+				existing[utils.NormalizeEmail(email)] = true
+			}
+			var added []string
+			for _, email := range append(append([]string{}, check.New...), check.Pending...) {
+				if !existing[email] {
+					existing[email] = true
+					added = append(added, email)
+				}
+			}
+
+			et.emails = append(et.emails, added...)
+			et.totalEmailCount = len(et.emails)
+			et.updateDisplayEmails()
+			et.updateStats()
+
+			message := fmt.Sprintf(
+				"📨 Messages read: %s\n"+
+					"📇 Addresses found: %s (%s filtered out)\n"+
+					"🔄 Unique emails: %s\n"+
+					"✔️ Already processed (skipped): %s\n"+
+					"✅ Added to the list: %s",
+				et.formatNumber(harvest.Messages),
+				et.formatNumber(harvest.Addresses),
+				et.formatNumber(harvest.Filtered),
+				et.formatNumber(len(harvest.Emails)),
+				et.formatNumber(len(check.Processed)),
+				et.formatNumber(len(added)),
+			)
+			dialog.ShowInformation("IMAP Import", message, et.gui.window)
+			et.addLog(fmt.Sprintf("📬 IMAP import: %d messages, %d emails added (%d already processed)",
+				harvest.Messages, len(added), len(check.Processed)))
+		}
+	}()
+}
+
+// AddEmail adds the email typed in the entry box to the list, emails.txt and
+// the database. During a crawl it is added to the running crawl instead.
+func (et *EmailsTab) AddEmail() {
+	email := utils.NormalizeEmail(et.addEntry.Text)
+	if !utils.IsValidEmail(email) {
+		dialog.ShowError(fmt.Errorf("invalid email: %q", et.addEntry.Text), et.gui.window)
+		return
+	}
+	if et.indexOfEmail(email) >= 0 {
+		dialog.ShowError(fmt.Errorf("%s is already in the list", email), et.gui.window)
+		return
+	}
+	if autoCrawler := et.gui.crawlController.Active(); autoCrawler != nil {
+		et.hotAddEmails(autoCrawler, []string{email})
+		et.addEntry.SetText("")
+		return
+	}
+	if et.gui.crawlController.Running() {
+		dialog.ShowError(fmt.Errorf("wait until the crawl has started before adding emails"), et.gui.window)
+		return
+	}
+
+	cfg := et.gui.configTab.ResolvedConfig()
+	if _, err := utils.AppendNewEmails(cfg.EmailsFilePath, []string{email}); err != nil {
+		dialog.ShowError(err, et.gui.window)
+		return
+	}
+	et.withEmailStorage(func(emailStorage *storageInternal.EmailStorage) error {
+		_, err := emailStorage.AddEmail(email)
+		return err
+	})
+
+	et.emails = append(et.emails, email)
+	et.totalEmailCount = len(et.emails)
+	et.currentPage = et.getTotalPages() - 1
+	et.updateDisplayEmails()
+	et.updateStats()
+	et.addEntry.SetText("")
+	et.addLog(fmt.Sprintf("➕ Đã thêm email %s", email))
+}
+
+// EditEmail lets the user fix one email of the list; the new address is reset
+// to pending in the database
+func (et *EmailsTab) EditEmail(email string) {
+	if et.gui.crawlController.Running() {
+		dialog.ShowError(fmt.Errorf("stop the crawl before editing the email list"), et.gui.window)
+		return
+	}
+
+	entry := widget.NewEntry()
+	entry.SetText(email)
+	entry.Validator = func(text string) error {
+		if !utils.IsValidEmail(text) {
+			return fmt.Errorf("invalid email")
+		}
+		return nil
+	}
+
+	dialog.ShowForm("Edit Email", "Save", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("Email", entry),
+	}, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		newEmail := utils.NormalizeEmail(entry.Text)
+		if newEmail == utils.NormalizeEmail(email) {
+			return
+		}
+
+		index := et.indexOfEmail(email)
+		if index < 0 {
+			return
+		}
+		// Email mới đã có trong list thì chỉ bỏ email cũ
+		duplicate := et.indexOfEmail(newEmail) >= 0
+
+		cfg := et.gui.configTab.ResolvedConfig()
+		replacement := newEmail
+		if duplicate {
+			replacement = ""
+		}
+		if _, err := utils.ReplaceEmailInFile(cfg.EmailsFilePath, email, replacement); err != nil && !errors.Is(err, os.ErrNotExist) {
+			dialog.ShowError(err, et.gui.window)
+			return
+		}
+		et.withEmailStorage(func(emailStorage *storageInternal.EmailStorage) error {
+			return emailStorage.RenameEmail(email, newEmail)
+		})
+
+		if duplicate {
+			et.emails = append(et.emails[:index], et.emails[index+1:]...)
+			et.totalEmailCount = len(et.emails)
+		} else {
+			et.emails[index] = newEmail
+		}
+		et.updateDisplayEmails()
+		et.updateStats()
+		et.addLog(fmt.Sprintf("✏️ Đã sửa email %s → %s", email, newEmail))
+	}, et.gui.window)
+}
+
+// DeleteEmail removes one email from the list, emails.txt and the database
+func (et *EmailsTab) DeleteEmail(email string) {
+	if et.gui.crawlController.Running() {
+		dialog.ShowError(fmt.Errorf("stop the crawl before editing the email list"), et.gui.window)
+		return
+	}
+
+	dialog.ShowConfirm("Delete Email", fmt.Sprintf("Remove %s from the list?", email), func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		index := et.indexOfEmail(email)
+		if index < 0 {
+			return
+		}
+
+		cfg := et.gui.configTab.ResolvedConfig()
+		if _, err := utils.ReplaceEmailInFile(cfg.EmailsFilePath, email, ""); err != nil && !errors.Is(err, os.ErrNotExist) {
+			dialog.ShowError(err, et.gui.window)
+			return
+		}
+		et.withEmailStorage(func(emailStorage *storageInternal.EmailStorage) error {
+			return emailStorage.DeleteEmail(email)
+		})
+
+		et.emails = append(et.emails[:index], et.emails[index+1:]...)
+		et.totalEmailCount = len(et.emails)
+		et.updateDisplayEmails()
+		et.updateStats()
+		et.addLog(fmt.Sprintf("🗑️ Đã xóa email %s", email))
 	}, et.gui.window)
 }
 
+// indexOfEmail returns the position of email in the list (case-insensitive),
+// or -1
+func (et *EmailsTab) indexOfEmail(email string) int {
+	email = utils.NormalizeEmail(email)
+	for i, existing := range et.emails {
+		if utils.NormalizeEmail(existing) == email {
+			return i
+		}
+	}
+	return -1
+}
+
+// withEmailStorage runs fn against the emails database and logs a failure;
+// the list and emails.txt stay the source of truth for the next load
+func (et *EmailsTab) withEmailStorage(fn func(*storageInternal.EmailStorage) error) {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		et.addLog(fmt.Sprintf("⚠️ Không mở được database: %v", err))
+		return
+	}
+	defer emailStorage.CloseDB()
+
+	if err := fn(emailStorage); err != nil {
+		et.addLog(fmt.Sprintf("⚠️ Không cập nhật được database: %v", err))
+	}
+}
+
 // OPTIMIZATION: Format large numbers with commas
 func (et *EmailsTab) formatNumber(n int) string {
 	if n < 1000 {
@@ -714,34 +1029,38 @@ func (et *EmailsTab) estimateProcessingTime() string {
 	}
 }
 
+// onCrawlStateChange tracks the controller's crawler while this tab's crawl runs
+func (et *EmailsTab) onCrawlStateChange(state CrawlState) {
+	if atomic.LoadInt32(&et.isCrawling) == 0 {
+		return
+	}
+	if state == CrawlRunning && et.autoCrawler == nil {
+		et.autoCrawler = et.gui.crawlController.Active()
+		et.addLog("✅ Crawler đã sẵn sàng!")
+		et.addLog("🔄 Bắt đầu quá trình crawling...")
+	}
+}
+
 // STOP CRAWL - Hoạt động thực tế với lưu trạng thái
 func (et *EmailsTab) StopCrawl() {
-	if atomic.LoadInt32(&et.isCrawling) == 0 {
+	// Dừng crawler đang chạy, kể cả khi nó được bắt đầu từ tab khác
+	completed, ok := et.gui.crawlController.Stop()
+	if !ok {
 		et.addLog("⚠️ Email crawling không đang chạy!")
 		return
 	}
 
 	et.addLog("⏹️ Đang dừng email crawling...")
 
-	// Signal shutdown to autoCrawler
-	if et.autoCrawler != nil {
-		shutdownReq := et.autoCrawler.GetShutdownRequested()
-		if shutdownReq != nil {
-			atomic.StoreInt32(shutdownReq, 1)
-		}
-	}
-
 	// Cancel the context
 	if et.crawlCancel != nil {
 		et.crawlCancel()
 	}
 
-	// Reset state immediately
-	atomic.StoreInt32(&et.isCrawling, 0)
-	et.startCrawlBtn.Enable()
+	// Nút Start được bật lại khi crawler thực sự kết thúc
 	et.stopCrawlBtn.Disable()
 
-	et.addLog("🛑 Đã dừng email crawling!")
+	et.addLog(fmt.Sprintf("🛑 Đã gửi tín hiệu dừng - %s emails đã hoàn thành trước khi dừng", et.formatNumber(completed)))
 
 	// QUAN TRỌNG: Không clear cache ngay, để giữ lại stats hiện tại
 	et.addLog("💾 Đang lưu trạng thái hiện tại...")
@@ -799,11 +1118,7 @@ func (et *EmailsTab) ClearAllEmails() {
 						et.displayEmails = []string{}
 
 						et.gui.updateUI <- func() {
-							if et.emailData == nil {
-								et.emailData = binding.NewStringList()
-							} else {
-								et.emailData = binding.NewStringList()
-							}
+							et.emailData.Set([]string{})
 							if et.emailsList != nil {
 								et.emailsList.Refresh()
 							}
@@ -830,11 +1145,7 @@ func (et *EmailsTab) ClearAllEmails() {
 					et.currentPage = 0
 					et.displayEmails = []string{}
 
-					if et.emailData == nil {
-						et.emailData = binding.NewStringList()
-					} else {
-						et.emailData = binding.NewStringList()
-					}
+					et.emailData.Set([]string{})
 
 					if et.emailsList != nil {
 						et.emailsList.Refresh()
@@ -886,10 +1197,10 @@ func (et *EmailsTab) saveEmailsToFile() {
 	}
 
 	content := strings.Join(lines, "\n")
-	err := os.WriteFile("emails.txt", []byte(content), 0644)
+	err := utils.WriteFileAtomic(et.gui.configTab.ResolvedConfig().EmailsFilePath, []byte(content))
 	if err != nil {
 		et.gui.updateUI <- func() {
-			et.gui.updateStatus(fmt.Sprintf("Failed to save: %v", err))
+			et.gui.postStatus(StatusSourceApp, SeverityError, fmt.Sprintf("Failed to save: %v", err))
 		}
 		return
 	}
@@ -915,18 +1226,35 @@ func (et *EmailsTab) saveEmailsToFile() {
 
 // OPTIMIZATION: Load emails with streaming for large files
 func (et *EmailsTab) LoadEmails() {
+	emailsFile := et.gui.configTab.ResolvedConfig().EmailsFilePath
 	emailStorage := storageInternal.NewEmailStorage()
 
 	// Check file size first
-	if fileInfo, err := os.Stat("emails.txt"); err == nil {
+	if fileInfo, err := os.Stat(emailsFile); err == nil {
 		fileSize := fileInfo.Size()
 		if fileSize > 10*1024*1024 { // > 10MB
-			// Show progress for large files
-			progress := dialog.NewProgressInfinite("Loading", "Loading large email file...", et.gui.window)
+			// Show import progress for large files
+			progressBar := widget.NewProgressBar()
+			progressLabel := widget.NewLabel("Reading email file...")
+			progress := dialog.NewCustomWithoutButtons("Loading", container.NewVBox(progressLabel, progressBar), et.gui.window)
 			progress.Show()
 
+			lastPercent := -1
+			emailStorage.SetImportProgress(func(done, total int) {
+				// Chỉ cập nhật UI khi % thay đổi
+				percent := done * 100 / total
+				if percent == lastPercent {
+					return
+				}
+				lastPercent = percent
+				et.gui.updateUI <- func() {
+					progressBar.SetValue(float64(done) / float64(total))
+					progressLabel.SetText(fmt.Sprintf("Importing %s / %s emails", et.formatNumber(done), et.formatNumber(total)))
+				}
+			})
+
 			go func() {
-				defer progress.Hide()
+				defer func() { et.gui.updateUI <- func() { progress.Hide() } }()
 				et.loadEmailsFromStorage(emailStorage)
 			}()
 			return
@@ -938,14 +1266,10 @@ func (et *EmailsTab) LoadEmails() {
 }
 
 func (et *EmailsTab) loadEmailsFromStorage(emailStorage *storageInternal.EmailStorage) {
-	emails, err := emailStorage.LoadEmailsFromFile("emails.txt")
+	emailsFile := et.gui.configTab.ResolvedConfig().EmailsFilePath
+	emails, err := emailStorage.LoadEmailsFromFile(emailsFile)
 	if err != nil {
-		if _, err := os.Stat("emails.txt"); os.IsNotExist(err) {
-			sampleContent := `# Target email addresses
-example@example.com
-`
-			os.WriteFile("emails.txt", []byte(sampleContent), 0644)
-		}
+		// LoadEmailsFromFile đã tạo file mẫu khi chưa có
 		et.gui.updateUI <- func() {
 			et.gui.updateStatus("No emails file found")
 		}
@@ -987,27 +1311,18 @@ func (et *EmailsTab) updateStats() {
 
 	// If crawler is running, get real stats
 	if et.autoCrawler != nil {
-		emailStorage, _, _ := et.autoCrawler.GetStorageServices()
-		if emailStorage != nil {
-			if stats, err := emailStorage.GetEmailStats(); err == nil {
-				pending := stats["pending"]
-				success := stats["success"]
-				failed := stats["failed"]
-				hasInfo := stats["has_info"]
-				noInfo := stats["no_info"]
-
-				et.totalLabel.SetText(fmt.Sprintf("Total: %s", et.formatNumber(total)))
-				et.pendingLabel.SetText(fmt.Sprintf("Pending: %s", et.formatNumber(pending)))
-				et.successLabel.SetText(fmt.Sprintf("Success: %s", et.formatNumber(success)))
-				et.failedLabel.SetText(fmt.Sprintf("Failed: %s", et.formatNumber(failed)))
-				et.hasInfoLabel.SetText(fmt.Sprintf("Has LinkedIn: %s", et.formatNumber(hasInfo)))
-				et.noInfoLabel.SetText(fmt.Sprintf("No LinkedIn: %s", et.formatNumber(noInfo)))
-
-				// Cache stats
-				et.lastStats = stats
-				return
-			}
-		}
+		stats := et.autoCrawler.Stats().Snapshot()
+
+		et.totalLabel.SetText(fmt.Sprintf("Total: %s", et.formatNumber(total)))
+		et.pendingLabel.SetText(fmt.Sprintf("Pending: %s", et.formatNumber(stats.Pending)))
+		et.successLabel.SetText(fmt.Sprintf("Success: %s", et.formatNumber(stats.Success)))
+		et.failedLabel.SetText(fmt.Sprintf("Failed: %s", et.formatNumber(stats.Failed)))
+		et.hasInfoLabel.SetText(fmt.Sprintf("Has LinkedIn: %s", et.formatNumber(stats.HasInfo)))
+		et.noInfoLabel.SetText(fmt.Sprintf("No LinkedIn: %s", et.formatNumber(stats.NoInfo)))
+
+		// Cache stats
+		et.lastStats = stats.EmailStats()
+		return
 	}
 
 	// Try to get stats from database when not crawling with logging
@@ -1038,7 +1353,7 @@ func (et *EmailsTab) updateStatsFromDatabase() {
 	}
 	defer emailStorage.CloseDB()
 
-	stats, err := emailStorage.GetEmailStats()
+	stats, err := orchestrator.LoadStats(emailStorage)
 	if err != nil {
 		// Fallback to cached stats or default
 		if len(et.lastStats) > 0 {
@@ -1050,11 +1365,11 @@ func (et *EmailsTab) updateStatsFromDatabase() {
 	}
 
 	total := et.totalEmailCount
-	pending := stats["pending"]
-	success := stats["success"]
-	failed := stats["failed"]
-	hasInfo := stats["has_info"]
-	noInfo := stats["no_info"]
+	pending := stats.Pending
+	success := stats.Success
+	failed := stats.Failed
+	hasInfo := stats.HasInfo
+	noInfo := stats.NoInfo
 
 	et.totalLabel.SetText(fmt.Sprintf("Total: %s", et.formatNumber(total)))
 	et.pendingLabel.SetText(fmt.Sprintf("Pending: %s", et.formatNumber(pending)))
@@ -1064,7 +1379,7 @@ func (et *EmailsTab) updateStatsFromDatabase() {
 	et.noInfoLabel.SetText(fmt.Sprintf("No LinkedIn: %s", et.formatNumber(noInfo)))
 
 	// Cache stats
-	et.lastStats = stats
+	et.lastStats = stats.EmailStats()
 }
 
 func (et *EmailsTab) updateStatsFromCache() {
@@ -1093,51 +1408,37 @@ func (et *EmailsTab) updateStatsFromCrawler() {
 		return
 	}
 
-	// Get stats from crawler's storage
-	emailStorage, _, _ := et.autoCrawler.GetStorageServices()
-	if emailStorage != nil {
-		stats, err := emailStorage.GetEmailStats()
-		if err == nil {
-			total := et.totalEmailCount
-			pending := stats["pending"]
-			success := stats["success"]
-			failed := stats["failed"]
-			hasInfo := stats["has_info"]
-			noInfo := stats["no_info"]
-
-			et.totalLabel.SetText(fmt.Sprintf("Total: %s", et.formatNumber(total)))
-			et.pendingLabel.SetText(fmt.Sprintf("Pending: %s", et.formatNumber(pending)))
-			et.successLabel.SetText(fmt.Sprintf("Success: %s", et.formatNumber(success)))
-			et.failedLabel.SetText(fmt.Sprintf("Failed: %s", et.formatNumber(failed)))
-			et.hasInfoLabel.SetText(fmt.Sprintf("Has LinkedIn: %s", et.formatNumber(hasInfo)))
-			et.noInfoLabel.SetText(fmt.Sprintf("No LinkedIn: %s", et.formatNumber(noInfo)))
-
-			// Update progress bar
-			if total > 0 {
-				processed := success + failed
-				progress := float64(processed) / float64(total)
-				if et.progressBar != nil {
-					et.progressBar.SetValue(progress)
-				}
-				if et.progressLabel != nil {
-					et.progressLabel.SetText(fmt.Sprintf("Progress: %s/%s (%.1f%%)",
-						et.formatNumber(processed), et.formatNumber(total), progress*100))
-				}
-			}
+	// Stats từ StatsService của crawler
+	stats := et.autoCrawler.Stats().Snapshot()
+	total := et.totalEmailCount
+	pending := stats.Pending
+	success := stats.Success
+	failed := stats.Failed
+	hasInfo := stats.HasInfo
+	noInfo := stats.NoInfo
 
-			// Cache stats
-			et.lastStats = stats
+	et.totalLabel.SetText(fmt.Sprintf("Total: %s", et.formatNumber(total)))
+	et.pendingLabel.SetText(fmt.Sprintf("Pending: %s", et.formatNumber(pending)))
+	et.successLabel.SetText(fmt.Sprintf("Success: %s", et.formatNumber(success)))
+	et.failedLabel.SetText(fmt.Sprintf("Failed: %s", et.formatNumber(failed)))
+	et.hasInfoLabel.SetText(fmt.Sprintf("Has LinkedIn: %s", et.formatNumber(hasInfo)))
+	et.noInfoLabel.SetText(fmt.Sprintf("No LinkedIn: %s", et.formatNumber(noInfo)))
 
-			// Log progress periodically for large datasets
-			processed := success + failed
-			if processed > 0 && processed%1000 == 0 { // Log every 1000 processed
-				progressPercent := float64(processed) * 100 / float64(total)
-				et.addLog(fmt.Sprintf("📊 Progress: %.1f%% (%s/%s) | Success: %s | Failed: %s | LinkedIn: %s",
-					progressPercent, et.formatNumber(processed), et.formatNumber(total),
-					et.formatNumber(success), et.formatNumber(failed), et.formatNumber(hasInfo)))
-			}
+	// Update progress bar
+	if total > 0 {
+		processed := success + failed
+		progress := float64(processed) / float64(total)
+		if et.progressBar != nil {
+			et.progressBar.SetValue(progress)
+		}
+		if et.progressLabel != nil {
+			et.progressLabel.SetText(fmt.Sprintf("Progress: %s/%s (%.1f%%)",
+				et.formatNumber(processed), et.formatNumber(total), progress*100))
 		}
 	}
+
+	// Cache stats; milestones được crawler log theo luật milestone trong config
+	et.lastStats = stats.EmailStats()
 }
 
 func (et *EmailsTab) updateStatsDefault() {
@@ -1161,10 +1462,10 @@ func (et *EmailsTab) finalizeAfterStop() {
 	if et.autoCrawler != nil {
 		// Get final stats from autoCrawler
 		emailStorage, _, _ := et.autoCrawler.GetStorageServices()
-		config := et.autoCrawler.GetConfig()
+		exportPath := et.autoCrawler.PendingExportPath()
 		if emailStorage != nil {
-			// Export pending emails back to emails.txt
-			err := emailStorage.ExportPendingEmailsToFile(config.EmailsFilePath)
+			// Export pending emails back to emails.txt (emails.pending.txt khi file bị sửa trong lúc crawl)
+			err := emailStorage.ExportPendingEmailsToFile(exportPath)
 			if err != nil {
 				et.addLog(fmt.Sprintf("⚠️ Không thể export pending emails: %v", err))
 			} else {
@@ -1172,7 +1473,7 @@ func (et *EmailsTab) finalizeAfterStop() {
 				pendingEmails, err := emailStorage.GetPendingEmails()
 				if err == nil {
 					if len(pendingEmails) > 0 {
-						et.addLog(fmt.Sprintf("💾 Đã lưu %s emails pending vào file emails.txt", et.formatNumber(len(pendingEmails))))
+						et.addLog(fmt.Sprintf("💾 Đã lưu %s emails pending vào file %s", et.formatNumber(len(pendingEmails)), exportPath))
 					} else {
 						et.addLog("✅ Tất cả emails đã được xử lý xong!")
 					}
@@ -1180,12 +1481,10 @@ func (et *EmailsTab) finalizeAfterStop() {
 			}
 
 			// Get final stats và lưu vào cache
-			stats, err := emailStorage.GetEmailStats()
-			if err == nil {
-				et.lastStats = stats // Cache stats để tránh reset về 0
-				et.addLog(fmt.Sprintf("📊 Trạng thái cuối: Success: %s | Failed: %s | LinkedIn: %s",
-					et.formatNumber(stats["success"]), et.formatNumber(stats["failed"]), et.formatNumber(stats["has_info"])))
-			}
+			stats := et.autoCrawler.Stats().Snapshot()
+			et.lastStats = stats.EmailStats() // Cache stats để tránh reset về 0
+			et.addLog(fmt.Sprintf("📊 Trạng thái cuối: Success: %s | Failed: %s | LinkedIn: %s",
+				et.formatNumber(stats.Success), et.formatNumber(stats.Failed), et.formatNumber(stats.HasInfo)))
 
 			// Close database properly
 			emailStorage.CloseDB()
@@ -1195,82 +1494,79 @@ func (et *EmailsTab) finalizeAfterStop() {
 
 func (et *EmailsTab) clearEmailStatusCache() {
 	et.emailStatusCache = make(map[string]string)
-	et.lastCacheUpdate = time.Time{}
 }
 
-func (et *EmailsTab) updateEmailStatusCache() {
-	// Only update cache every 5 seconds to avoid excessive database queries
-	if time.Since(et.lastCacheUpdate) < 5*time.Second {
-		return
-	}
+// refreshPageStatuses loads the statuses of the current page in the
+// background, then refreshes the visible rows. Results for a page that is no
+// longer shown are dropped.
+func (et *EmailsTab) refreshPageStatuses() {
+	page := append([]string(nil), et.displayEmails...)
+	version := atomic.AddInt64(&et.pageVersion, 1)
 
-	emailStorage := storageInternal.NewEmailStorage()
-	if err := emailStorage.InitDB(); err != nil {
+	if len(page) == 0 {
+		if et.emailsList != nil {
+			et.emailsList.Refresh()
+		}
 		return
 	}
-	defer emailStorage.CloseDB()
 
-	// OPTIMIZATION: Only cache status for visible emails to save memory
-	query := `SELECT email, status, has_info, no_info FROM emails LIMIT 1000`
-	db := emailStorage.GetDB()
-	if db == nil {
-		return
+	// Crawler đang chạy thì dùng DB của crawler
+	var emailStorage *storageInternal.EmailStorage
+	if et.autoCrawler != nil {
+		emailStorage, _, _ = et.autoCrawler.GetStorageServices()
 	}
 
-	rows, err := db.Query(query)
-	if err != nil {
-		return
-	}
-	defer rows.Close()
+	go func() {
+		if emailStorage == nil {
+			emailStorage = storageInternal.NewEmailStorage()
+			if err := emailStorage.InitDB(); err != nil {
+				return
+			}
+			defer emailStorage.CloseDB()
+		}
 
-	newCache := make(map[string]string)
-	for rows.Next() {
-		var email, status string
-		var hasInfo, noInfo bool
+		records, err := emailStorage.GetEmailStatuses(page)
+		if err != nil {
+			return
+		}
 
-		if err := rows.Scan(&email, &status, &hasInfo, &noInfo); err != nil {
-			continue
+		statuses := make(map[string]string, len(records))
+		for email, record := range records {
+			statuses[email] = emailStatusText(record)
 		}
 
-		// Convert database status to display status
-		switch status {
-		case "pending":
-			newCache[email] = "Pending"
-		case "success":
-			if hasInfo {
-				newCache[email] = "Success - Has LinkedIn"
-			} else {
-				newCache[email] = "Success - No LinkedIn"
+		et.gui.updateUI <- func() {
+			if atomic.LoadInt64(&et.pageVersion) != version {
+				return
+			}
+			et.emailStatusCache = statuses
+			if et.emailsList != nil {
+				et.emailsList.Refresh()
 			}
-		case "failed":
-			newCache[email] = "Failed"
-		default:
-			newCache[email] = "Unknown"
 		}
-	}
-
-	et.emailStatusCache = newCache
-	et.lastCacheUpdate = time.Now()
+	}()
 }
 
-func (et *EmailsTab) getEmailStatus(email string) string {
-	// If we have crawler running, get live status
-	if et.autoCrawler != nil {
-		emailStorage, _, _ := et.autoCrawler.GetStorageServices()
-		if emailStorage != nil {
-			// Try to get status from running crawler's database
-			if status, ok := et.emailStatusCache[email]; ok {
-				return status
-			}
-			return "Processing"
+// emailStatusText converts a database record to the status shown in the list
+func emailStatusText(record storageInternal.EmailRecord) string {
+	switch record.Status {
+	case storageInternal.StatusPending:
+		return "Pending"
+	case storageInternal.StatusSuccess:
+		if record.HasInfo {
+			return "Success - Has LinkedIn"
 		}
+		return "Success - No LinkedIn"
+	case storageInternal.StatusFailed:
+		return "Failed"
+	default:
+		return "Unknown"
 	}
+}
 
-	// Update cache if needed
-	et.updateEmailStatusCache()
-
-	// Return cached status if available
-	if status, ok := et.emailStatusCache[email]; ok {
+func (et *EmailsTab) getEmailStatus(email string) string {
+	// Return cached status of the current page if available
+	if status, ok := et.emailStatusCache[strings.ToLower(email)]; ok {
 		return status
 	}
 
@@ -1300,18 +1596,19 @@ func (et *EmailsTab) checkTokensAvailability() bool {
 
 func (et *EmailsTab) hasValidTokensFile() bool {
 	tokenStorage := storageInternal.NewTokenStorage()
-	tokens, err := tokenStorage.LoadTokensFromFile("tokens.txt")
+	tokensFile := et.gui.configTab.ResolvedConfig().TokensFilePath
+	tokens, err := tokenStorage.LoadTokensFromFile(tokensFile)
 	if err != nil {
-		et.addLog(fmt.Sprintf("🔍 Không thể đọc file tokens.txt: %v", err))
+		et.addLog(fmt.Sprintf("🔍 Không thể đọc file %s: %v", tokensFile, err))
 		return false
 	}
 
 	if len(tokens) == 0 {
-		et.addLog("🔍 File tokens.txt rỗng hoặc không có tokens")
+		et.addLog(fmt.Sprintf("🔍 File %s rỗng hoặc không có tokens", tokensFile))
 		return false
 	}
 
-	et.addLog(fmt.Sprintf("🔍 Tìm thấy %d tokens trong file tokens.txt", len(tokens)))
+	et.addLog(fmt.Sprintf("🔍 Tìm thấy %d tokens trong file %s", len(tokens), tokensFile))
 
 	// Use utils package for validation
 	validCount, _ := utils.ValidateTokenBatch(tokens)
@@ -1328,7 +1625,7 @@ func (et *EmailsTab) hasValidTokensFile() bool {
 func (et *EmailsTab) logTokenAccountStatus() {
 	// Check tokens
 	tokenStorage := storageInternal.NewTokenStorage()
-	tokens, err := tokenStorage.LoadTokensFromFile("tokens.txt")
+	tokens, err := tokenStorage.LoadTokensFromFile(et.gui.configTab.ResolvedConfig().TokensFilePath)
 	if err == nil && len(tokens) > 0 {
 		et.addLog(fmt.Sprintf("🔑 Tokens khả dụng: %d tokens từ file", len(tokens)))
 	} else {
@@ -1347,39 +1644,48 @@ func (et *EmailsTab) logTokenAccountStatus() {
 	}
 }
 
+// logCrawlSettings writes the effective crawl settings to the crawl log header
+func (et *EmailsTab) logCrawlSettings(cfg models.Config) {
+	et.addLog(fmt.Sprintf("⚙️ Mode: %s | Concurrency: %d | Requests/sec: %.1f | Timeout: %v",
+		cfg.CrawlMode, cfg.MaxConcurrency, cfg.RequestsPerSec, cfg.RequestTimeout))
+	et.addLog(fmt.Sprintf("⚙️ Pacing: %s | Login: %s x%d | Tokens: %d-%d",
+		cfg.PacingProfile, cfg.LoginMethod, cfg.LoginParallelism, cfg.MinTokens, cfg.MaxTokens))
+	if cfg.MaxRequestsPerHour > 0 || cfg.MaxRequestsPerDay > 0 {
+		et.addLog(fmt.Sprintf("⚙️ Request caps: %d/hour, %d/day (0 = không giới hạn)", cfg.MaxRequestsPerHour, cfg.MaxRequestsPerDay))
+	}
+	if cfg.CrawlWindowStart != "" && cfg.CrawlWindowEnd != "" {
+		et.addLog(fmt.Sprintf("⚙️ Crawl window: %s-%s %s", cfg.CrawlWindowStart, cfg.CrawlWindowEnd, cfg.CrawlTimeZone))
+	}
+}
+
 func (et *EmailsTab) performEmailCrawling(ctx context.Context) {
 	et.gui.updateUI <- func() {
 		et.addLog("🔧 Đang khởi tạo crawler...")
 	}
 
-	// Create config
-	cfg := config.DefaultConfig()
-	cfg.EmailsFilePath = "emails.txt"
-	cfg.TokensFilePath = "tokens.txt"
-	cfg.AccountsFilePath = "accounts.txt"
-	cfg.MaxConcurrency = 20
-	cfg.RequestsPerSec = 15.0
-
-	// Initialize AutoCrawler
-	autoCrawler, err := orchestrator.New(cfg)
+	// Dùng chung config với Config tab (đã áp giới hạn license)
+	cfg, err := et.gui.crawlConfig()
 	if err != nil {
 		et.gui.updateUI <- func() {
-			et.addLog(fmt.Sprintf("❌ Lỗi khởi tạo crawler: %v", err))
+			et.addLog(fmt.Sprintf("❌ %v", err))
 		}
 		return
 	}
-
-	et.autoCrawler = autoCrawler
 	et.gui.updateUI <- func() {
-		et.addLog("✅ Crawler đã sẵn sàng!")
-		et.addLog("🔄 Bắt đầu quá trình crawling...")
+		et.logCrawlSettings(cfg)
 	}
 
 	// Start progress monitoring
 	go et.monitorCrawlProgress(ctx)
 
-	// Run the crawler
-	err = autoCrawler.Run()
+	// Run the crawler - controller đảm bảo chỉ một crawl chạy tại một thời điểm
+	err = et.gui.crawlController.Run(cfg)
+	if errors.Is(err, ErrCrawlRunning) {
+		et.gui.updateUI <- func() {
+			et.addLog("⚠️ Một crawl khác đang chạy - hãy dừng nó trước")
+		}
+		return
+	}
 
 	if err != nil {
 		et.gui.updateUI <- func() {
@@ -1403,17 +1709,14 @@ func (et *EmailsTab) performEmailCrawling(ctx context.Context) {
 }
 
 func (et *EmailsTab) Cleanup() {
-	// Stop stats refresh ticker
-	if et.statsRefreshTicker != nil {
-		et.statsRefreshTicker.Stop()
-		et.statsRefreshTicker = nil
-	}
+	// Stop stats refresh
+	et.gui.refreshScheduler.Cancel("emails-stats")
 
 	// Clear cache
 	et.emailStatusCache = nil
 
 	// Clear log buffer to free memory
-	et.logBuffer = nil
+	et.logView.Clear()
 
 	// Close any database connections
 	if et.autoCrawler != nil {
@@ -1425,23 +1728,15 @@ func (et *EmailsTab) Cleanup() {
 }
 
 func (et *EmailsTab) monitorCrawlProgress(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second) // Slower updates for large datasets
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			if et.autoCrawler != nil {
-				et.gui.updateUI <- func() {
-					et.updateStatsFromCrawler()
-					// Clear cache periodically during crawling to get fresh data
-					et.clearEmailStatusCache()
-				}
-			}
+	et.gui.refreshScheduler.Schedule("emails-crawl-progress", RefreshStats, func() {
+		if et.autoCrawler != nil {
+			et.updateStatsFromCrawler()
+			// Nạp lại status của trang hiện tại trong lúc crawl
+			et.gui.updateUI <- et.refreshPageStatuses
 		}
-	}
+	})
+	<-ctx.Done()
+	et.gui.refreshScheduler.Cancel("emails-crawl-progress")
 }
 
 func (et *EmailsTab) showFinalResults() {
@@ -1449,38 +1744,33 @@ func (et *EmailsTab) showFinalResults() {
 		return
 	}
 
-	emailStorage, _, _ := et.autoCrawler.GetStorageServices()
-	if emailStorage != nil {
-		stats, err := emailStorage.GetEmailStats()
-		if err == nil {
-			total := et.totalEmailCount
-			success := stats["success"]
-			failed := stats["failed"]
-			hasInfo := stats["has_info"]
-			noInfo := stats["no_info"]
-
-			et.addLog("🎉 KẾT QUẢ CUỐI CÙNG:")
-			et.addLog(fmt.Sprintf("📊 Tổng emails: %s", et.formatNumber(total)))
-			et.addLog(fmt.Sprintf("✅ Thành công: %s", et.formatNumber(success)))
-			et.addLog(fmt.Sprintf("❌ Thất bại: %s", et.formatNumber(failed)))
-			et.addLog(fmt.Sprintf("🎯 Có LinkedIn: %s", et.formatNumber(hasInfo)))
-			et.addLog(fmt.Sprintf("📭 Không có LinkedIn: %s", et.formatNumber(noInfo)))
-
-			if hasInfo > 0 {
-				et.addLog(fmt.Sprintf("🎉 Tìm thấy %s LinkedIn profiles - Xem trong file hit.txt!", et.formatNumber(hasInfo)))
-			}
+	stats := et.autoCrawler.Stats().Snapshot().EmailStats()
+	total := et.totalEmailCount
+	success := stats["success"]
+	failed := stats["failed"]
+	hasInfo := stats["has_info"]
+	noInfo := stats["no_info"]
 
-			successRate := 0.0
-			if total > 0 {
-				successRate = float64(success) * 100 / float64(total)
-			}
-			et.addLog(fmt.Sprintf("📈 Tỷ lệ thành công: %.1f%%", successRate))
+	et.addLog("🎉 KẾT QUẢ CUỐI CÙNG:")
+	et.addLog(fmt.Sprintf("📊 Tổng emails: %s", et.formatNumber(total)))
+	et.addLog(fmt.Sprintf("✅ Thành công: %s", et.formatNumber(success)))
+	et.addLog(fmt.Sprintf("❌ Thất bại: %s", et.formatNumber(failed)))
+	et.addLog(fmt.Sprintf("🎯 Có LinkedIn: %s", et.formatNumber(hasInfo)))
+	et.addLog(fmt.Sprintf("📭 Không có LinkedIn: %s", et.formatNumber(noInfo)))
 
-			// Cache final stats
-			et.lastStats = stats
-		}
+	if hasInfo > 0 {
+		et.addLog(fmt.Sprintf("🎉 Tìm thấy %s LinkedIn profiles - Xem trong file hit.txt!", et.formatNumber(hasInfo)))
 	}
 
+	successRate := 0.0
+	if total > 0 {
+		successRate = float64(success) * 100 / float64(total)
+	}
+	et.addLog(fmt.Sprintf("📈 Tỷ lệ thành công: %.1f%%", successRate))
+
+	// Cache final stats
+	et.lastStats = stats
+
 	// Refresh results tab
 	if et.gui.resultsTab != nil {
 		et.gui.resultsTab.RefreshResults()
@@ -1488,18 +1778,10 @@ func (et *EmailsTab) showFinalResults() {
 }
 
 func (et *EmailsTab) addLog(msg string) {
-	ts := time.Now().Format("15:04:05")
-	logEntry := fmt.Sprintf("[%s] %s", ts, msg)
-	et.logBuffer = append(et.logBuffer, logEntry)
-
-	// Keep only last 200 entries
-	if len(et.logBuffer) > 200 {
-		et.logBuffer = et.logBuffer[len(et.logBuffer)-200:]
+	if runID := et.gui.crawlController.RunID(); runID != "" {
+		msg = fmt.Sprintf("[%s] %s", runID, msg)
 	}
-
-	// Update display
-	displayText := "```\n" + strings.Join(et.logBuffer, "\n") + "\n```"
-	et.logText.ParseMarkdown(displayText)
+	et.logView.Add(utils.PrivateText(msg, et.gui.configTab.config.LogPrivacy))
 }
 
 func (et *EmailsTab) GetEmails() []string {
@@ -1536,10 +1818,5 @@ func (et *EmailsTab) OnCrawlerStopped() {
 }
 
 func (et *EmailsTab) addCrawlerLog(msg string) {
-	timestamp := time.Now().Format("15:04:05")
-	line := fmt.Sprintf("[%s] %s", timestamp, msg)
-	et.logBuffer = append(et.logBuffer, line)
-	if et.logText != nil {
-		et.logText.ParseMarkdown(strings.Join(et.logBuffer, "\n"))
-	}
+	et.logView.Add(msg)
 }
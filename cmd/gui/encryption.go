@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+// unlockDatabase asks for the passphrase when the results in dbPath are
+// encrypted, then calls then; cancelling quits the app since nothing can be
+// read or crawled while the database is locked
+func (gui *CrawlerGUI) unlockDatabase(dbPath string, then func()) {
+	encrypted, err := storageInternal.IsEncrypted(dbPath)
+	if err != nil || !encrypted {
+		then()
+		return
+	}
+
+	passphrase := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		{Text: "Passphrase:", Widget: passphrase, HintText: "The results in " + dbPath + " are encrypted"},
+	}
+	form := dialog.NewForm("Unlock Results", "Unlock", "Quit", items, func(confirmed bool) {
+		if !confirmed {
+			gui.app.Quit()
+			return
+		}
+		if err := storageInternal.CheckPassphrase(dbPath, passphrase.Text); err != nil {
+			errDialog := dialog.NewError(err, gui.window)
+			errDialog.SetOnClosed(func() { gui.unlockDatabase(dbPath, then) })
+			errDialog.Show()
+			return
+		}
+		storageInternal.SetPassphrase(passphrase.Text)
+		gui.updateStatus("🔐 Results unlocked")
+		then()
+	}, gui.window)
+	form.Resize(fyne.NewSize(480, 200))
+	form.Show()
+	gui.window.Canvas().Focus(passphrase)
+}
+
+// ShowEncryption turns field encryption of the results on or off, or changes
+// the passphrase
+func (ct *ConfigTab) ShowEncryption() {
+	if ct.gui.crawlController.Running() {
+		dialog.ShowInformation("Encrypt Results", "Stop the crawler before changing encryption", ct.gui.window)
+		return
+	}
+	dbPath := ct.ResolvedConfig().DBPath
+	encrypted, err := storageInternal.IsEncrypted(dbPath)
+	if err != nil {
+		dialog.ShowError(err, ct.gui.window)
+		return
+	}
+
+	passphrase := widget.NewPasswordEntry()
+	again := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		{Text: "New Passphrase:", Widget: passphrase, HintText: "At least 8 characters; results cannot be read without it"},
+		{Text: "Repeat:", Widget: again},
+	}
+	title, confirm := "Encrypt Results", "Encrypt"
+
+	// Đã mã hoá: đổi passphrase hoặc giải mã
+	action := widget.NewRadioGroup([]string{"Change passphrase", "Decrypt results"}, func(selected string) {
+		if selected == "Decrypt results" {
+			passphrase.Disable()
+			again.Disable()
+		} else {
+			passphrase.Enable()
+			again.Enable()
+		}
+	})
+	if encrypted {
+		action.SetSelected("Change passphrase")
+		items = append([]*widget.FormItem{{Text: "Action:", Widget: action, HintText: "The results in " + dbPath + " are encrypted"}}, items...)
+		title, confirm = "Results Encryption", "Apply"
+	}
+
+	form := dialog.NewForm(title, confirm, "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if encrypted && action.Selected == "Decrypt results" {
+			ct.rekeyResults(func(emailStorage *storageInternal.EmailStorage) (int, error) {
+				return emailStorage.DisableEncryption()
+			})
+			return
+		}
+		if passphrase.Text != again.Text {
+			dialog.ShowError(fmt.Errorf("the passphrases do not match"), ct.gui.window)
+			return
+		}
+		ct.rekeyResults(func(emailStorage *storageInternal.EmailStorage) (int, error) {
+			if encrypted {
+				return emailStorage.ChangePassphrase(passphrase.Text)
+			}
+			return emailStorage.EnableEncryption(passphrase.Text)
+		})
+	}, ct.gui.window)
+	form.Resize(fyne.NewSize(540, 280))
+	form.Show()
+}
+
+// rekeyResults runs an encryption change in the background and reports it
+func (ct *ConfigTab) rekeyResults(change func(*storageInternal.EmailStorage) (int, error)) {
+	progress := dialog.NewProgressInfinite("Encryption", "Rewriting results and vacuuming emails.db...", ct.gui.window)
+	progress.Show()
+	go func() {
+		var count int
+		emailStorage, err := ct.openMaintenanceStorage()
+		if err == nil {
+			count, err = change(emailStorage)
+			emailStorage.CloseDB()
+		}
+		encrypted := false
+		if err == nil {
+			encrypted, err = storageInternal.IsEncrypted(ct.ResolvedConfig().DBPath)
+		}
+		ct.gui.updateUI <- func() {
+			progress.Hide()
+			if err != nil {
+				dialog.ShowError(err, ct.gui.window)
+				return
+			}
+			if encrypted {
+				ct.gui.updateStatus(fmt.Sprintf("🔐 %d results encrypted", count))
+			} else {
+				ct.gui.updateStatus(fmt.Sprintf("🔓 %d results decrypted", count))
+			}
+			if ct.gui.resultsTab != nil {
+				ct.gui.resultsTab.RefreshResults()
+			}
+		}
+	}()
+}
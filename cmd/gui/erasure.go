@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+// ShowEraseAddresses asks for the addresses of an erasure request, removes
+// every trace of them and shows the erasure report
+func (ct *ConfigTab) ShowEraseAddresses() {
+	if ct.gui.crawlController.Running() {
+		dialog.ShowInformation("Erase Addresses", "Stop the crawler before erasing addresses", ct.gui.window)
+		return
+	}
+
+	addresses := widget.NewMultiLineEntry()
+	addresses.SetPlaceHolder("one address per line")
+	addresses.SetMinRowsVisible(5)
+	info := widget.NewLabel("Deletes the database rows, hit file and emails file lines, debug captures and log lines " +
+		"of these addresses. Backups and campaign archives are only searched and listed in the report.")
+	info.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(info, nil, nil, nil, addresses)
+	d := dialog.NewCustomConfirm("Erase Addresses", "Erase", "Cancel", content, func(confirmed bool) {
+		emails := strings.Fields(strings.ReplaceAll(addresses.Text, ",", " "))
+		if !confirmed || len(emails) == 0 {
+			return
+		}
+		ct.eraseAddresses(emails)
+	}, ct.gui.window)
+	d.Resize(fyne.NewSize(520, 360))
+	d.Show()
+}
+
+// eraseAddresses runs the erasure in the background and writes its report
+// next to the database
+func (ct *ConfigTab) eraseAddresses(emails []string) {
+	templates := ct.config // File được tìm theo template chưa expand
+	dbPath := ct.ResolvedConfig().DBPath
+	go func() {
+		var report *storageInternal.ErasureReport
+		reportPath := ""
+		emailStorage, err := ct.openMaintenanceStorage()
+		if err == nil {
+			report, err = emailStorage.EraseAddresses(templates, emails)
+			emailStorage.CloseDB()
+		}
+		if report != nil {
+			reportPath = storageInternal.ErasureReportPath(dbPath, report.At)
+			if writeErr := os.WriteFile(reportPath, []byte(report.String()), 0644); writeErr != nil && err == nil {
+				err = fmt.Errorf("erased, but failed to write the report: %w", writeErr)
+			}
+		}
+
+		ct.gui.updateUI <- func() {
+			if err != nil {
+				dialog.ShowError(err, ct.gui.window)
+			}
+			if report == nil {
+				return
+			}
+			ct.gui.updateStatus(fmt.Sprintf("Erased %d addresses: report %s", len(report.Emails), reportPath))
+			if ct.gui.resultsTab != nil {
+				ct.gui.resultsTab.RefreshResults()
+			}
+
+			text := widget.NewLabel(report.String())
+			text.Wrapping = fyne.TextWrapWord
+			reportDialog := dialog.NewCustom("Erasure Report", "Close",
+				container.NewBorder(widget.NewLabel("Saved to "+reportPath), nil, nil, nil, container.NewVScroll(text)), ct.gui.window)
+			reportDialog.Resize(fyne.NewSize(640, 480))
+			reportDialog.Show()
+		}
+	}()
+}
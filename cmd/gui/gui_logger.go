@@ -18,38 +18,30 @@ type GUILogger interface {
 // =============================================================================
 
 func (et *EmailsTab) LogInfo(message string) {
-	et.gui.updateUI <- func() {
-		et.addLog(fmt.Sprintf("ℹ️ %s", message))
-	}
+	et.addLog(fmt.Sprintf("ℹ️ %s", message))
 }
 
 func (et *EmailsTab) LogWarning(message string) {
-	et.gui.updateUI <- func() {
-		et.addLog(fmt.Sprintf("⚠️ %s", message))
-	}
+	et.addLog(fmt.Sprintf("⚠️ %s", message))
 }
 
 func (et *EmailsTab) LogError(message string) {
-	et.gui.updateUI <- func() {
-		et.addLog(fmt.Sprintf("❌ %s", message))
-	}
+	et.addLog(fmt.Sprintf("❌ %s", message))
 }
 
 func (et *EmailsTab) LogSuccess(message string) {
-	et.gui.updateUI <- func() {
-		et.addLog(fmt.Sprintf("✅ %s", message))
-	}
+	et.addLog(fmt.Sprintf("✅ %s", message))
 }
 
 func (et *EmailsTab) UpdateProgress(processed, total int, message string) {
-	et.gui.updateUI <- func() {
-		et.addLog(fmt.Sprintf("📊 %s", message))
-
-		// Update progress in status bar instead of control tab
-		if total > 0 {
-			progress := float64(processed) / float64(total)
-			progressMsg := fmt.Sprintf("Progress: %d/%d (%.1f%%)", processed, total, progress*100)
-			et.gui.updateStatus(progressMsg)
+	et.addLog(fmt.Sprintf("📊 %s", message))
+
+	// Update progress in status bar instead of control tab
+	if total > 0 {
+		progress := float64(processed) / float64(total)
+		progressMsg := fmt.Sprintf("Progress: %d/%d (%.1f%%)", processed, total, progress*100)
+		et.gui.updateUI <- func() {
+			et.gui.postStatus(StatusSourceCrawler, SeverityInfo, progressMsg)
 		}
 	}
 }
@@ -59,40 +51,32 @@ func (et *EmailsTab) UpdateProgress(processed, total int, message string) {
 // =============================================================================
 
 func (at *AccountsTab) LogInfo(message string) {
-	at.gui.updateUI <- func() {
-		at.addLog(fmt.Sprintf("ℹ️ %s", message))
-	}
+	at.addLog(fmt.Sprintf("ℹ️ %s", message))
 }
 
 func (at *AccountsTab) LogWarning(message string) {
-	at.gui.updateUI <- func() {
-		at.addLog(fmt.Sprintf("⚠️ %s", message))
-	}
+	at.addLog(fmt.Sprintf("⚠️ %s", message))
 }
 
 func (at *AccountsTab) LogError(message string) {
-	at.gui.updateUI <- func() {
-		at.addLog(fmt.Sprintf("❌ %s", message))
-	}
+	at.addLog(fmt.Sprintf("❌ %s", message))
 }
 
 func (at *AccountsTab) LogSuccess(message string) {
-	at.gui.updateUI <- func() {
-		at.addLog(fmt.Sprintf("✅ %s", message))
-	}
+	at.addLog(fmt.Sprintf("✅ %s", message))
 }
 
 func (at *AccountsTab) UpdateProgress(processed, total int, message string) {
-	at.gui.updateUI <- func() {
-		at.addLog(fmt.Sprintf("📊 %s", message))
-
-		// Update token extraction progress if needed
-		if total > 0 {
-			progress := float64(processed) / float64(total)
-			progressMsg := fmt.Sprintf("Token extraction: %.1f%% (%d/%d)", progress*100, processed, total)
-			at.addLog(progressMsg)
-			// Update status bar with token extraction progress
-			at.gui.updateStatus(fmt.Sprintf("Extracting tokens: %.1f%%", progress*100))
+	at.addLog(fmt.Sprintf("📊 %s", message))
+
+	// Update token extraction progress if needed
+	if total > 0 {
+		progress := float64(processed) / float64(total)
+		progressMsg := fmt.Sprintf("Token extraction: %.1f%% (%d/%d)", progress*100, processed, total)
+		at.addLog(progressMsg)
+		// Update status bar with token extraction progress
+		at.gui.updateUI <- func() {
+			at.gui.postStatus(StatusSourceTokens, SeverityInfo, fmt.Sprintf("Extracting tokens: %.1f%%", progress*100))
 		}
 	}
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -21,13 +22,129 @@ type ConfigTab struct {
 	minTokens      *widget.Entry
 	maxTokens      *widget.Entry
 	sleepDuration  *widget.Entry
+	memoryLimit    *widget.Entry
+	cacheTTL       *widget.Entry
+
+	// Token extraction
+	loginParallelism *widget.Entry
+	loginTimeout     *widget.Entry
+	loginMethod      *widget.Select
+	accountShortfall *widget.Select
+	tokenSources     *widget.Entry
+
+	// Token farm cho nguồn provider
+	tokenProviderURL  *widget.Entry
+	tokenProviderAuth *widget.Entry
+
+	// Giới hạn mỗi account mỗi ngày (0 = không giới hạn)
+	maxAccountLoginsPerDay *widget.Entry
+	maxAccountTokensPerDay *widget.Entry
+
+	// Giới hạn của một lần chạy (0 = không giới hạn)
+	maxEmailsPerRun   *widget.Entry
+	maxAccountsPerRun *widget.Entry
+
+	// Extended profile (headline, company), cần PRO
+	extendedProfile *widget.Check
+
+	// Pacing profile
+	pacingProfile *widget.Select
+
+	// Request budget (0 = không giới hạn)
+	maxRequestsPerHour *widget.Entry
+	maxRequestsPerDay  *widget.Entry
+
+	// Khung giờ crawl (để trống = luôn crawl)
+	crawlWindowStart *widget.Entry
+	crawlWindowEnd   *widget.Entry
+	crawlTimeZone    *widget.Entry
+
+	// Debug settings
+	captureFailures *widget.Check
+
+	// Google Sheets sync (để trống spreadsheet ID = tắt)
+	sheetsSpreadsheetID *widget.Entry
+	sheetsCredentials   *widget.Entry
+	sheetsRange         *widget.Entry
+	sheetsInterval      *widget.Entry
+
+	// Thông báo Slack/Discord (để trống webhook = tắt) và milestones
+	slackWebhook            *widget.Entry
+	discordWebhook          *widget.Entry
+	notifyEveryHits         *widget.Entry
+	milestoneEveryProcessed *widget.Entry
+	milestoneInterval       *widget.Entry
+	notifyTemplates         *widget.Entry
+
+	// Outbound webhook cho Zapier/Make (để trống = tắt)
+	webhookURL       *widget.Entry
+	webhookHitEvents *widget.Check
+
+	// API endpoint (để trống = endpoint Loki mặc định)
+	apiBaseURL     *widget.Entry
+	apiHeaders     *widget.Entry
+	apiQueryParams *widget.Entry
+
+	// Email source là database query (để trống DSN = dùng file emails)
+	emailsDBDriver *widget.Select
+	emailsDBDSN    *widget.Entry
+	emailsDBQuery  *widget.Entry
+
+	// Import emails từ hộp thư IMAP (nút Import IMAP ở tab Emails)
+	imapServer      *widget.Entry
+	imapUsername    *widget.Entry
+	imapPassword    *widget.Entry
+	imapMailbox     *widget.Entry
+	imapSinceDays   *widget.Entry
+	imapFields      *widget.Entry
+	imapDomains     *widget.Entry
+	imapExclude     *widget.Entry
+	imapMaxMessages *widget.Entry
+
+	// Snapshot định kỳ emails.db + results.csv trong lúc crawl (0 = tắt)
+	backupDir      *widget.Entry
+	backupInterval *widget.Entry
+	backupKeep     *widget.Entry
+
+	// Số ngày giữ dữ liệu của emails đã crawl (0 = giữ mãi)
+	retentionDays *widget.Entry
+
+	// Email trong log và activity feed: off, mask hoặc hash
+	logPrivacy *widget.Select
+
+	// Chế độ giả lập: kết quả giả, không cần accounts và mạng
+	simulate        *widget.Check
+	simulateHitRate *widget.Entry
+	simulateLatency *widget.Entry
+
+	// Chu kỳ refresh của GUI (stats, results, token info, license)
+	refreshStats     *widget.Entry
+	refreshResults   *widget.Entry
+	refreshTokenInfo *widget.Entry
+	refreshLicense   *widget.Entry
+
+	// Output paths (hỗ trợ template {campaign}, {mode}, {date}, {time})
+	campaign      *widget.Entry
+	outputFile    *widget.Entry
+	outputMaxSize *widget.Entry
+	dbPath        *widget.Entry
+	logFile       *widget.Entry
+	emailsFile    *widget.Entry
+	emailsEdits   *widget.Select
+	tokensFile    *widget.Entry
+	accountsFile  *widget.Entry
 
 	// Buttons
 	saveBtn  *widget.Button
 	resetBtn *widget.Button
 
 	// Current config
-	config models.Config
+	config  models.Config
+	refresh RefreshIntervals
+
+	// Config với đường dẫn đã expand ở lần ResolveConfig gần nhất
+	resolvedMutex sync.RWMutex
+	resolved      models.Config
 }
 
 // ControlTab handles crawler execution control
@@ -55,6 +172,10 @@ type ControlTab struct {
 	statusLabel *widget.Label
 	timeLabel   *widget.Label
 
+	// Dự báo số token/account cần cho emails pending
+	forecastLabel *widget.Label
+	forecastAt    time.Time
+
 	// Update ticker
 	updateTicker *time.Ticker
 
@@ -86,13 +207,16 @@ type ResultsTab struct {
 
 	// Stats summary
 	summaryCard     *widget.Card
-	refreshTicker   *time.Ticker
 	originalResults []CrawlerResult
 
 	autoRefreshCheck *widget.Check
 	autoRefresh      bool
+	mergeCheck       *widget.Check
+	mergeByURL       bool // Gộp các email trỏ về cùng LinkedIn URL
+	exportNotes      bool // Ghi cả notes khi export
 	sortSelect       *widget.Select
 	statusFilter     *widget.Select
+	reviewerEntry    *widget.Entry // Tên dùng khi claim kết quả
 }
 
 // LogsTab shows real-time logs
@@ -123,8 +247,23 @@ type CrawlerResult struct {
 	LinkedInURL string
 	Location    string
 	Connections string
+	Headline    string // Chỉ có khi crawl với ExtendedProfile
+	Company     string
 	Status      string
+	Source      string
 	Timestamp   time.Time
+	Fields      map[string]string // Cột thêm của file input (xem utils.HitResult.Fields)
+
+	WorkflowStatus string   // models.ResultStatus*, người dùng sửa trong tab Results
+	Tags           []string // Tags người dùng gắn
+	Notes          string   // Ghi chú người dùng
+	ClaimedBy      string   // Reviewer đang giữ kết quả, rỗng nếu chưa ai claim
+	ClaimedAt      time.Time
+
+	// Giá trị chuẩn hoá từ Connections/Location (xem utils.ParseConnections, utils.NormalizeLocation)
+	ConnectionsCount int // -1 nếu không rõ
+	Country          string
+	Region           string
 }
 
 // EmailStatus represents the processing status of an email
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"linkedin-crawler/internal/orchestrator"
+	"linkedin-crawler/internal/utils"
+)
+
+// hotAddEmails adds emails to the running crawl. They go into the database as
+// pending and the current batch picks them up; the ones still pending are
+// written to the emails file when the crawl ends.
+func (et *EmailsTab) hotAddEmails(autoCrawler *orchestrator.AutoCrawler, emails []string) {
+	go func() {
+		added, invalid, err := autoCrawler.AddEmails(emails)
+		if err != nil {
+			et.gui.postStatus(StatusSourceApp, SeverityError, fmt.Sprintf("Adding emails to the crawl failed: %v", err))
+			return
+		}
+
+		et.addLog(fmt.Sprintf("📥 Đã thêm %d emails vào crawl đang chạy (%d đã có, %d không hợp lệ)",
+			added, len(emails)-added-invalid, invalid))
+		et.gui.postStatus(StatusSourceApp, SeveritySuccess, fmt.Sprintf("Added %s emails to the running crawl", et.formatNumber(added)))
+		et.gui.updateUI <- func() {
+			existing := make(map[string]bool, len(et.emails))
+			for _, email := range et.emails {
+				existing[utils.NormalizeEmail(email)] = true
+			}
+			for _, email := range emails {
+				if email = utils.NormalizeEmail(email); !existing[email] {
+					existing[email] = true
+					et.emails = append(et.emails, email)
+				}
+			}
+			et.totalEmailCount = len(et.emails)
+			et.updateDisplayEmails()
+			et.updateStats()
+		}
+	}()
+}
@@ -8,14 +8,22 @@ import (
 	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"linkedin-crawler/internal/licensing"
 )
 
+const (
+	usageHistoryDays      = 14  // Số ngày hiển thị trong biểu đồ usage
+	usageProjectionWindow = 7   // Số ngày gần nhất dùng để tính tốc độ
+	usageChartHeight      = 120 // Chiều cao cột lớn nhất (px)
+)
+
 // LicenseTab handles license management with proper error handling
 type LicenseTab struct {
 	gui            *CrawlerGUI
@@ -26,6 +34,8 @@ type LicenseTab struct {
 	activationCard  *widget.Card
 	licenseKeyEntry *widget.Entry
 	activateBtn     *widget.Button
+	upgradeBtn      *widget.Button
+	addonBtn        *widget.Button
 	removeBtn       *widget.Button
 	refreshBtn      *widget.Button
 
@@ -37,8 +47,12 @@ type LicenseTab struct {
 	limitsLabel   *widget.Label
 	featuresLabel *widget.RichText
 
-	// License info refresh ticker
-	refreshTicker *time.Ticker
+	// Usage gauge + history chart (từ usage ledger)
+	usageCard       *widget.Card
+	usageGauge      *widget.ProgressBar
+	usageLabel      *widget.Label
+	projectionLabel *widget.Label
+	usageChart      *fyne.Container
 }
 
 // NewLicenseTab creates a new license management tab
@@ -69,6 +83,9 @@ func (lt *LicenseTab) setupUI() {
 	lt.activateBtn = widget.NewButtonWithIcon("Activate License", theme.ConfirmIcon(), lt.ActivateLicense)
 	lt.activateBtn.Importance = widget.HighImportance
 
+	lt.upgradeBtn = widget.NewButtonWithIcon("Upgrade", theme.MoveUpIcon(), lt.UpgradeLicense)
+	lt.addonBtn = widget.NewButtonWithIcon("Add Add-on", theme.ContentAddIcon(), lt.AddAddon)
+
 	lt.removeBtn = widget.NewButtonWithIcon("Remove License", theme.DeleteIcon(), lt.RemoveLicense)
 	lt.removeBtn.Importance = widget.DangerImportance
 
@@ -82,6 +99,12 @@ func (lt *LicenseTab) setupUI() {
 	lt.limitsLabel = widget.NewLabel("Limits: Unknown")
 	lt.featuresLabel = widget.NewRichText()
 
+	// Usage components
+	lt.usageGauge = widget.NewProgressBar()
+	lt.usageLabel = widget.NewLabel("Usage: Unknown")
+	lt.projectionLabel = widget.NewLabel("")
+	lt.usageChart = container.NewGridWithColumns(usageHistoryDays)
+
 	// Update initial status
 	lt.updateLicenseDisplay()
 }
@@ -99,6 +122,8 @@ func (lt *LicenseTab) CreateContent() fyne.CanvasObject {
 		lt.licenseKeyEntry,
 		container.NewHBox(
 			lt.activateBtn,
+			lt.upgradeBtn,
+			lt.addonBtn,
 			lt.removeBtn,
 		),
 		widget.NewSeparator(),
@@ -107,6 +132,7 @@ func (lt *LicenseTab) CreateContent() fyne.CanvasObject {
 		container.NewHBox(
 			widget.NewButton("Generate Trial", lt.GenerateTrialKey),
 			widget.NewButton("Help", lt.ShowHelp),
+			widget.NewButton("Clock Reset", lt.ResetClockCheck),
 			widget.NewButton("Contact Support", lt.ContactSupport),
 		),
 	)
@@ -130,10 +156,21 @@ func (lt *LicenseTab) CreateContent() fyne.CanvasObject {
 
 	lt.statusCard = widget.NewCard("License Status", "", statusContent)
 
+	// Usage section
+	usageContent := container.NewVBox(
+		lt.usageLabel,
+		lt.usageGauge,
+		lt.projectionLabel,
+		widget.NewSeparator(),
+		widget.NewLabel(fmt.Sprintf("Billable emails per day (last %d days):", usageHistoryDays)),
+		lt.usageChart,
+	)
+	lt.usageCard = widget.NewCard("Usage", "", usageContent)
+
 	// Main layout
 	content := container.NewVSplit(
 		lt.activationCard,
-		lt.statusCard,
+		container.NewHSplit(lt.statusCard, lt.usageCard),
 	)
 	content.SetOffset(0.4)
 
@@ -220,6 +257,134 @@ func (lt *LicenseTab) ActivateLicense() {
 	}()
 }
 
+// UpgradeLicense replaces the active license with a higher-tier key, keeping usage history
+func (lt *LicenseTab) UpgradeLicense() {
+	info := lt.licenseWrapper.GetLicenseInfo()
+	currentType, _ := info["type"].(string)
+	if status, ok := info["status"].(string); !ok || status == "invalid" {
+		dialog.ShowInformation("No License", "Activate a license before upgrading.", lt.gui.window)
+		return
+	}
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder("New license key: TYPE-USERNAME-EMAIL-EXPIRY-CHECKSUM")
+
+	form := []*widget.FormItem{
+		{Text: "Current:", Widget: widget.NewLabel(strings.ToUpper(currentType))},
+		{Text: "New key:", Widget: keyEntry},
+	}
+
+	dialog.ShowForm("Upgrade License", "Validate", "Cancel", form, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		newKey := strings.TrimSpace(keyEntry.Text)
+		if !lt.isValidKeyFormat(newKey) {
+			dialog.ShowError(fmt.Errorf("Invalid license key format.\n\nExpected format: TYPE-USERNAME-EMAIL-EXPIRY-CHECKSUM"), lt.gui.window)
+			return
+		}
+
+		newInfo, err := licensing.NewLicenseManager().ValidateLicenseKey(newKey)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("License validation failed:\n\n%v", err), lt.gui.window)
+			return
+		}
+
+		used := lt.licenseWrapper.GetUsageLedger().TotalBillable()
+		confirmMsg := fmt.Sprintf("Upgrade %s → %s?\n\nUser: %s\nEmail limit: %s\nEmails used so far: %d (kept after upgrade)",
+			strings.ToUpper(currentType), strings.ToUpper(string(newInfo.Type)),
+			newInfo.UserName, formatEmailLimit(newInfo.MaxEmails), used)
+
+		dialog.ShowConfirm("Confirm Upgrade", confirmMsg, func(ok bool) {
+			if !ok {
+				return
+			}
+
+			oldInfo, upgraded, err := lt.licenseWrapper.UpgradeLicense(newKey)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Upgrade failed:\n\n%v", err), lt.gui.window)
+				lt.updateLicenseDisplay()
+				return
+			}
+
+			lt.updateLicenseDisplay()
+			dialog.ShowInformation("License Upgraded",
+				fmt.Sprintf("License upgraded from %s to %s.\n\nEmail limit: %s\nUsage history has been kept.",
+					strings.ToUpper(string(oldInfo.Type)), strings.ToUpper(string(upgraded.Type)),
+					formatEmailLimit(upgraded.MaxEmails)),
+				lt.gui.window)
+			lt.gui.updateStatus(fmt.Sprintf("✅ License upgraded to %s", strings.ToUpper(string(upgraded.Type))))
+
+			lt.gui.OnLicenseActivated()
+		}, lt.gui.window)
+	}, lt.gui.window)
+}
+
+// ResetClockCheck applies a support-issued code after a false clock-tamper detection
+func (lt *LicenseTab) ResetClockCheck() {
+	codeEntry := widget.NewEntry()
+	codeEntry.SetPlaceHolder("CLK-XXXXXXXXXX")
+
+	form := []*widget.FormItem{
+		{Text: "Reset code:", Widget: codeEntry},
+	}
+
+	dialog.ShowForm("Clock Reset", "Apply", "Cancel", form, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		if err := lt.licenseWrapper.ResetClockMark(codeEntry.Text); err != nil {
+			dialog.ShowError(fmt.Errorf("Clock reset failed:\n\n%v", err), lt.gui.window)
+			return
+		}
+
+		lt.updateLicenseDisplay()
+		dialog.ShowInformation("Clock Reset", "License clock check has been reset.", lt.gui.window)
+		lt.gui.updateStatus("✅ License clock check reset")
+
+		lt.gui.OnLicenseActivated()
+	}, lt.gui.window)
+}
+
+// AddAddon stacks an extra-quota add-on key onto the active license
+func (lt *LicenseTab) AddAddon() {
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder("ADDON-QUANTITY-EMAIL-EXPIRY-CHECKSUM")
+
+	form := []*widget.FormItem{
+		{Text: "Add-on key:", Widget: keyEntry},
+	}
+
+	dialog.ShowForm("Add Quota Add-on", "Add", "Cancel", form, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		addon, err := lt.licenseWrapper.AddAddon(strings.TrimSpace(keyEntry.Text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("Add-on activation failed:\n\n%v", err), lt.gui.window)
+			return
+		}
+
+		lt.updateLicenseDisplay()
+		dialog.ShowInformation("Add-on Activated",
+			fmt.Sprintf("+%d emails added to your quota.\n\nAdd-on expires: %s",
+				addon.Emails, addon.ExpiresAt.Format("2006-01-02")),
+			lt.gui.window)
+		lt.gui.updateStatus(fmt.Sprintf("✅ Add-on activated: +%d emails", addon.Emails))
+	}, lt.gui.window)
+}
+
+// formatEmailLimit renders an email quota, treating <= 0 as unlimited
+func formatEmailLimit(maxEmails int) string {
+	if maxEmails <= 0 {
+		return "Unlimited"
+	}
+	return fmt.Sprintf("%d", maxEmails)
+}
+
 // isValidKeyFormat checks if license key has valid format
 func (lt *LicenseTab) isValidKeyFormat(key string) bool {
 	// Remove spaces and convert to upper
@@ -235,6 +400,9 @@ func (lt *LicenseTab) isValidKeyFormat(key string) bool {
 	// Check license type
 	licenseType := strings.ToLower(parts[0])
 	validTypes := []string{"trial", "personal", "pro"}
+	if licenseType == string(licensing.LicenseTypeAddon) {
+		return false // Add-on key dùng nút "Add Add-on"
+	}
 	found := false
 	for _, validType := range validTypes {
 		if licenseType == validType {
@@ -378,8 +546,24 @@ func (lt *LicenseTab) ShowHelp() {
 - **Email limit**: Unlimited
 - **Account limit**: Unlimited
 - **Features**: All features + advanced crawling, priority support
+- **Advanced crawling**: headline and company in results, concurrency above 30
 - **Best for**: Businesses, large-scale operations
 
+## Quota Add-ons
+
+Add-on keys (ADDON-QUANTITY-EMAIL-EXPIRY-CHECKSUM) add extra emails on top of
+a TRIAL or PERSONAL license. Use the "Add Add-on" button; add-ons stack and are
+only counted for the email they were issued to.
+
+## System Clock Check
+
+The license records the latest time it was used. If the system clock is set
+more than one hour earlier than that, the license is refused.
+
+**Recovery**: correct the system date/time and restart. If the clock is already
+correct (e.g. it was previously set too far ahead), send your license key to
+support to get a one-day reset code (CLK-...) and enter it with "Clock Reset".
+
 ## License Key Format
 
 **Format**: TYPE-USERNAME-EMAIL-EXPIRY-CHECKSUM
@@ -415,8 +599,7 @@ Once you reach your email limit, simply upgrade to the next tier. Your license n
 
 ## Support & Upgrades
 
-- **Trial → Personal**: Contact support to upgrade
-- **Personal → Pro**: Contact support to upgrade  
+- **Trial → Personal / Personal → Pro**: Enter the new key with the "Upgrade" button; usage history is kept
 - **Technical Issues**: Email support with your license key
 - **Questions**: We're here to help 24/7`
 
@@ -533,6 +716,9 @@ func (lt *LicenseTab) updateLicenseDisplay() {
 	case "invalid":
 		errorMsg, _ := info["error"].(string)
 		statusText = fmt.Sprintf("## ❌ NO VALID LICENSE\n\n%s\n\nPlease activate a valid license to use the software.", errorMsg)
+		if tampered, _ := info["clock_tampered"].(bool); tampered {
+			statusText = fmt.Sprintf("## 🕒 SYSTEM CLOCK CHECK FAILED\n\n%s\n\nCorrect the system date/time, or enter a reset code from support via \"Clock Reset\".", errorMsg)
+		}
 	default:
 		statusText = "## ❓ LICENSE STATUS UNKNOWN\n\nUnable to determine license status. Please check your license."
 	}
@@ -565,6 +751,10 @@ func (lt *LicenseTab) updateLicenseDisplay() {
 			emailLimit = "Unlimited"
 		} else {
 			emailLimit = fmt.Sprintf("%d", maxEmails)
+			if addonEmails, _ := info["addon_emails"].(int); addonEmails > 0 {
+				addons, _ := info["addons"].(int)
+				emailLimit += fmt.Sprintf(" (incl. +%d from %d add-on(s))", addonEmails, addons)
+			}
 		}
 
 		lt.limitsLabel.SetText(fmt.Sprintf("📊 Email Limit: %s | Accounts: Unlimited", emailLimit))
@@ -594,40 +784,76 @@ func (lt *LicenseTab) updateLicenseDisplay() {
 		lt.featuresLabel.ParseMarkdown("*No license active - please activate a license to view available features*")
 	}
 
+	lt.updateUsageDisplay(info)
+
 	// Update button states
 	hasValidLicense := status == "active" || status == "expiring_soon"
 	if hasValidLicense {
 		lt.removeBtn.Enable()
+		lt.upgradeBtn.Enable()
+		lt.addonBtn.Enable()
 	} else {
 		lt.removeBtn.Disable()
+		lt.upgradeBtn.Disable()
+		lt.addonBtn.Disable()
 	}
 }
 
-// startAutoRefresh starts automatic license info refresh
-func (lt *LicenseTab) startAutoRefresh() {
-	if lt.refreshTicker != nil {
-		lt.refreshTicker.Stop()
+// updateUsageDisplay refreshes the usage gauge, projection and history chart
+func (lt *LicenseTab) updateUsageDisplay(info map[string]interface{}) {
+	ledger := lt.licenseWrapper.GetUsageLedger()
+	// Chỉ email billable (status=success) tính vào quota
+	used := ledger.TotalBillable()
+	nonBillable := ledger.TotalProcessed() - used
+	maxEmails, _ := info["max_emails"].(int)
+
+	if maxEmails > 0 {
+		lt.usageGauge.Max = float64(maxEmails)
+		lt.usageGauge.SetValue(float64(min(used, maxEmails)))
+		lt.usageLabel.SetText(fmt.Sprintf("📈 Emails used: %d / %d (%.1f%%) • %d failed lookups not billed",
+			used, maxEmails, float64(used)*100/float64(maxEmails), nonBillable))
+
+		if used >= maxEmails {
+			lt.projectionLabel.SetText("🚫 Quota exhausted")
+		} else if at, ok := ledger.ProjectExhaustion(maxEmails, usageProjectionWindow); ok {
+			lt.projectionLabel.SetText(fmt.Sprintf("⏳ At the current rate the quota runs out around %s", at.Format("2006-01-02")))
+		} else {
+			lt.projectionLabel.SetText("⏳ No recent usage to project quota exhaustion")
+		}
+	} else {
+		lt.usageGauge.Max = 1
+		lt.usageGauge.SetValue(0)
+		lt.usageLabel.SetText(fmt.Sprintf("📈 Emails used: %d (Unlimited) • %d failed lookups not billed", used, nonBillable))
+		lt.projectionLabel.SetText("")
 	}
 
-	lt.refreshTicker = time.NewTicker(30 * time.Second)
-	go func() {
-		defer func() {
-			if lt.refreshTicker != nil {
-				lt.refreshTicker.Stop()
-			}
-		}()
+	history := ledger.History(usageHistoryDays)
+	peak := 1
+	for _, day := range history {
+		peak = max(peak, day.Success)
+	}
 
-		for {
-			select {
-			case <-lt.refreshTicker.C:
-				lt.gui.updateUI <- func() {
-					lt.updateLicenseDisplay()
-				}
-			case <-lt.gui.ctx.Done():
-				return
-			}
-		}
-	}()
+	bars := make([]fyne.CanvasObject, 0, len(history))
+	for _, day := range history {
+		height := float32(day.Success) / float32(peak) * usageChartHeight
+		bar := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+		bar.SetMinSize(fyne.NewSize(12, max(height, 1)))
+
+		count := widget.NewLabel(fmt.Sprintf("%d", day.Success))
+		count.Alignment = fyne.TextAlignCenter
+		date := widget.NewLabel(day.Date[5:]) // MM-DD
+		date.Alignment = fyne.TextAlignCenter
+		date.TextStyle = fyne.TextStyle{Italic: true}
+
+		bars = append(bars, container.NewVBox(layout.NewSpacer(), count, bar, date))
+	}
+	lt.usageChart.Objects = bars
+	lt.usageChart.Refresh()
+}
+
+// startAutoRefresh starts automatic license info refresh
+func (lt *LicenseTab) startAutoRefresh() {
+	lt.gui.refreshScheduler.Schedule("license-info", RefreshLicense, lt.updateLicenseDisplay)
 }
 
 // ValidateLicenseForApp validates license before app operations
@@ -645,10 +871,7 @@ func (lt *LicenseTab) CheckFeatureAccess(feature string) bool {
 	return lt.licenseWrapper.CheckFeatureAccess(feature)
 }
 
-// Cleanup stops the refresh ticker
+// Cleanup stops the license info refresh
 func (lt *LicenseTab) Cleanup() {
-	if lt.refreshTicker != nil {
-		lt.refreshTicker.Stop()
-		lt.refreshTicker = nil
-	}
+	lt.gui.refreshScheduler.Cancel("license-info")
 }
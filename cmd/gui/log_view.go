@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	logViewCapacity = 5000                   // Số dòng giữ lại, dòng cũ nhất bị ghi đè
+	logFlushDelay   = 250 * time.Millisecond // Gom các dòng mới trong khoảng này vào một lần vẽ
+)
+
+// logLine is one entry of a LogView
+type logLine struct {
+	time    time.Time
+	text    string
+	repeats int // Số lần lặp lại liên tiếp của cùng message
+}
+
+// String formats the line as shown and exported
+func (l logLine) String() string {
+	line := fmt.Sprintf("[%s] %s", l.time.Format("15:04:05"), l.text)
+	if l.repeats > 1 {
+		line += fmt.Sprintf(" (×%d)", l.repeats)
+	}
+	return line
+}
+
+// LogView is a log panel backed by a ring buffer. Only visible rows are
+// rendered, consecutive duplicate messages are collapsed into one line with a
+// counter, and the log can be searched and exported. Adding a line never
+// blocks: lines are formatted and filtered off the UI thread and flushed to
+// the list at most every logFlushDelay.
+type LogView struct {
+	gui  *CrawlerGUI
+	name string
+
+	mutex        sync.Mutex
+	lines        []logLine // Ring buffer
+	start        int       // Vị trí dòng cũ nhất
+	count        int
+	query        string
+	flushPending bool
+
+	visible []string // Chỉ dùng trên UI thread
+
+	list      *widget.List
+	search    *widget.Entry
+	follow    *widget.Check
+	exportBtn *widget.Button
+	clearBtn  *widget.Button
+}
+
+// NewLogView creates an empty log panel; name is used in the export header
+func NewLogView(gui *CrawlerGUI, name string) *LogView {
+	lv := &LogView{
+		gui:   gui,
+		name:  name,
+		lines: make([]logLine, logViewCapacity),
+	}
+
+	lv.list = widget.NewList(
+		func() int { return len(lv.visible) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id < len(lv.visible) {
+				item.(*widget.Label).SetText(lv.visible[id])
+			}
+		},
+	)
+
+	lv.search = widget.NewEntry()
+	lv.search.SetPlaceHolder("Search log...")
+	lv.search.OnChanged = func(query string) {
+		lv.mutex.Lock()
+		lv.query = strings.ToLower(strings.TrimSpace(query))
+		lv.scheduleFlush()
+		lv.mutex.Unlock()
+	}
+
+	lv.follow = widget.NewCheck("Follow", nil)
+	lv.follow.SetChecked(true)
+	lv.exportBtn = widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), lv.Export)
+	lv.clearBtn = widget.NewButtonWithIcon("", theme.DeleteIcon(), lv.Clear)
+
+	return lv
+}
+
+// CreateContent returns the panel: search bar and controls above the lines
+func (lv *LogView) CreateContent() fyne.CanvasObject {
+	controls := container.NewHBox(lv.follow, lv.exportBtn, lv.clearBtn)
+	return container.NewBorder(
+		container.NewBorder(nil, nil, nil, controls, lv.search),
+		nil, nil, nil,
+		lv.list,
+	)
+}
+
+// Add appends msg with the current time. A message equal to the previous one
+// only bumps its counter. Safe to call from any goroutine.
+func (lv *LogView) Add(msg string) {
+	lv.mutex.Lock()
+	if lv.count > 0 {
+		last := &lv.lines[(lv.start+lv.count-1)%len(lv.lines)]
+		if last.text == msg {
+			last.repeats++
+			last.time = time.Now()
+			lv.scheduleFlush()
+			lv.mutex.Unlock()
+			return
+		}
+	}
+
+	line := logLine{time: time.Now(), text: msg, repeats: 1}
+	if lv.count < len(lv.lines) {
+		lv.lines[(lv.start+lv.count)%len(lv.lines)] = line
+		lv.count++
+	} else {
+		lv.lines[lv.start] = line
+		lv.start = (lv.start + 1) % len(lv.lines)
+	}
+	lv.scheduleFlush()
+	lv.mutex.Unlock()
+}
+
+// scheduleFlush queues one flush for all changes made within
+// logFlushDelay; callers hold the mutex
+func (lv *LogView) scheduleFlush() {
+	if lv.flushPending {
+		return
+	}
+	lv.flushPending = true
+	time.AfterFunc(logFlushDelay, lv.flush)
+}
+
+// flush builds the visible lines in the background and hands only the
+// finished slice to the UI thread
+func (lv *LogView) flush() {
+	lv.mutex.Lock()
+	lv.flushPending = false
+	lines := lv.snapshot()
+	query := lv.query
+	lv.mutex.Unlock()
+
+	if query != "" {
+		matches := lines[:0]
+		for _, line := range lines {
+			if strings.Contains(strings.ToLower(line), query) {
+				matches = append(matches, line)
+			}
+		}
+		lines = matches
+	}
+
+	lv.gui.updateUI <- func() {
+		lv.visible = lines
+		lv.list.Refresh()
+		if lv.follow.Checked && len(lines) > 0 {
+			lv.list.ScrollToBottom()
+		}
+	}
+}
+
+// Lines returns the buffered lines, oldest first
+func (lv *LogView) Lines() []string {
+	lv.mutex.Lock()
+	defer lv.mutex.Unlock()
+	return lv.snapshot()
+}
+
+// snapshot formats the buffered lines; callers hold the mutex
+func (lv *LogView) snapshot() []string {
+	lines := make([]string, 0, lv.count)
+	for i := 0; i < lv.count; i++ {
+		lines = append(lines, lv.lines[(lv.start+i)%len(lv.lines)].String())
+	}
+	return lines
+}
+
+// Trim keeps only the newest n lines
+func (lv *LogView) Trim(n int) {
+	lv.mutex.Lock()
+	for lv.count > n {
+		// Xoá hẳn dòng cũ để GC thu hồi được
+		lv.lines[lv.start] = logLine{}
+		lv.start = (lv.start + 1) % len(lv.lines)
+		lv.count--
+	}
+	lv.scheduleFlush()
+	lv.mutex.Unlock()
+}
+
+// Clear removes all lines
+func (lv *LogView) Clear() {
+	lv.Trim(0)
+}
+
+// Export saves all buffered lines to a file chosen by the user
+func (lv *LogView) Export() {
+	lines := lv.Lines()
+	if len(lines) == 0 {
+		dialog.ShowInformation("No Data", "No logs to export", lv.gui.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		header := []string{
+			fmt.Sprintf("# LinkedIn Auto Crawler - %s", lv.name),
+			fmt.Sprintf("# Generated: %s", time.Now().Format("2006-01-02 15:04:05")),
+			"",
+		}
+		content := strings.Join(append(header, lines...), "\n") + "\n"
+		if _, err := writer.Write([]byte(content)); err != nil {
+			dialog.ShowError(err, lv.gui.window)
+			return
+		}
+
+		lv.gui.postStatus(StatusSourceApp, SeveritySuccess, fmt.Sprintf("Exported %d log lines", len(lines)))
+	}, lv.gui.window)
+	saveDialog.SetFileName(fmt.Sprintf("%s-%s.log",
+		strings.ToLower(strings.ReplaceAll(lv.name, " ", "-")), time.Now().Format("20060102-150405")))
+	saveDialog.Show()
+}
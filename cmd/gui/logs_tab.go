@@ -65,6 +65,9 @@ func (lt *LogsTab) CreateContent() fyne.CanvasObject {
 func (lt *LogsTab) AddLog(message string) {
 	timestamp := time.Now().Format("15:04:05")
 	logEntry := fmt.Sprintf("[%s] %s", timestamp, message)
+	if runID := lt.gui.crawlController.RunID(); runID != "" {
+		logEntry = fmt.Sprintf("[%s] [%s] %s", timestamp, runID, message)
+	}
 
 	lt.logBuffer = append(lt.logBuffer, logEntry)
 
@@ -124,9 +127,9 @@ func (lt *LogsTab) SaveLogs() {
 	}, lt.gui.window)
 }
 
-// LoadExistingLogs loads existing logs from crawler.log file
+// LoadExistingLogs loads existing logs from the crawler log file
 func (lt *LogsTab) LoadExistingLogs() {
-	file, err := os.Open("crawler.log")
+	file, err := os.Open(lt.gui.configTab.ResolvedConfig().LogFilePath)
 	if err != nil {
 		lt.AddLog("No existing log file found")
 		return
@@ -4,13 +4,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
-	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -21,7 +21,8 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"linkedin-crawler/internal/licensing"
-	"linkedin-crawler/internal/orchestrator"
+	"linkedin-crawler/internal/models"
+	storageInternal "linkedin-crawler/internal/storage"
 	"linkedin-crawler/internal/utils"
 )
 
@@ -30,9 +31,8 @@ type CrawlerGUI struct {
 	app    fyne.App
 	window fyne.Window
 
-	autoCrawler *orchestrator.AutoCrawler
-	crawlerMux  sync.RWMutex
-	isRunning   bool
+	// Crawl duy nhất của GUI, dùng chung cho mọi tab
+	crawlController *CrawlController
 
 	configTab          *ConfigTab
 	accountsTab        *AccountsTab
@@ -40,22 +40,33 @@ type CrawlerGUI struct {
 	resultsTab         *ResultsTab
 	statusBarContainer fyne.CanvasObject
 	licenseTab         *LicenseTab
+	analyticsTab       *AnalyticsTab
 
-	statusBar *widget.Label
+	statusCenter *StatusCenter // Status bar + lịch sử message
 
 	ctx      context.Context
 	cancel   context.CancelFunc
 	updateUI chan func()
 
 	// Enhanced license integration
-	licenseWrapper     *licensing.LicensedCrawlerWrapper
-	isLicenseValid     bool
-	licenseCheckTicker *time.Ticker
+	licenseWrapper *licensing.LicensedCrawlerWrapper
+	isLicenseValid bool
+
+	// Instance lock (chống 2 instance ghi cùng emails.db/tokens.txt)
+	instanceLock *storageInternal.InstanceLock
+
+	// Socket để lần mở GUI sau chuyển sang cửa sổ này (nil = --multi-instance)
+	singleInstance *utils.SingleInstance
 
 	// License usage tracking
-	sessionStartTime   time.Time
-	lastUsageCheck     time.Time
-	usageCheckInterval time.Duration
+	sessionStartTime time.Time
+	lastUsageCheck   time.Time
+
+	// Lịch refresh định kỳ của các tab (stats, results, token info, license)
+	refreshScheduler *RefreshScheduler
+
+	// Chế độ reviewer (--reviewer): chỉ tab Results, không license/lock/crawl
+	reviewer bool
 }
 
 func main() {
@@ -71,8 +82,20 @@ func main() {
 		os.MkdirAll(appDir, 0755)
 	}
 
+	// --reviewer [results.db]: chỉ mở tab Results
+	reviewer, reviewerDB := parseReviewerArgs(os.Args[1:])
+	// --multi-instance: cố ý mở thêm GUI, ví dụ cho campaign ở thư mục khác
+	multiInstance := parseMultiInstanceArg(os.Args[1:])
+
 	// Initialize GUI
 	gui := NewCrawlerGUI()
+	gui.reviewer = reviewer
+
+	// GUI đã chạy: đưa cửa sổ đó lên trước thay vì mở thêm license monitor
+	// và ghi file song song. Reviewer chỉ đọc nên được mở cùng lúc.
+	if !reviewer && !multiInstance && !gui.claimSingleInstance() {
+		return
+	}
 
 	// Single dispatcher
 	go func() {
@@ -95,12 +118,29 @@ func main() {
 		gui.cleanup()
 	}()
 
-	// Build UI first
-	gui.setupUI()
+	if reviewer {
+		// Reviewer không crawl: không cần license và instance lock
+		gui.updateUI <- func() {
+			gui.setupReviewerUI(reviewerDB)
+		}
+	} else {
+		// Build UI first
+		gui.setupUI()
 
-	// STRICT LICENSE CHECK - Block app if no valid license
-	gui.updateUI <- func() {
-		gui.performComprehensiveLicenseCheck()
+		// Results mã hoá: hỏi passphrase rồi đọc lại các tab
+		gui.updateUI <- func() {
+			gui.unlockDatabase(gui.configTab.ResolvedConfig().DBPath, gui.reloadAfterUnlock)
+		}
+
+		// Single-instance guard
+		gui.updateUI <- func() {
+			gui.acquireInstanceLock(false)
+		}
+
+		// STRICT LICENSE CHECK - Block app if no valid license
+		gui.updateUI <- func() {
+			gui.performComprehensiveLicenseCheck()
+		}
 	}
 
 	// Start the application
@@ -121,16 +161,20 @@ func NewCrawlerGUI() *CrawlerGUI {
 		window:         w,
 		ctx:            ctx,
 		cancel:         cancel,
-		isRunning:      false,
 		updateUI:       make(chan func(), 100),
 		licenseWrapper: licensing.NewLicensedCrawlerWrapper(),
 		isLicenseValid: false,
 
 		// License tracking
-		sessionStartTime:   time.Now(),
-		lastUsageCheck:     time.Now(),
-		usageCheckInterval: 30 * time.Second, // Check usage every 30 seconds
+		sessionStartTime: time.Now(),
+		lastUsageCheck:   time.Now(),
 	}
+	// UI scale và high contrast (View → Display Settings)
+	gui.setDisplayTheme(gui.loadDisplaySettings())
+	gui.refreshScheduler = NewRefreshScheduler(gui)
+	gui.crawlController = NewCrawlController(gui, gui.licenseWrapper)
+	gui.statusCenter = NewStatusCenter(gui)
+	gui.statusBarContainer = gui.statusCenter.CreateContent()
 
 	// Initialize tabs
 	gui.configTab = NewConfigTab(gui)
@@ -138,10 +182,65 @@ func NewCrawlerGUI() *CrawlerGUI {
 	gui.emailsTab = NewEmailsTab(gui)
 	gui.resultsTab = NewResultsTab(gui)
 	gui.licenseTab = NewLicenseTab(gui)
+	gui.analyticsTab = NewAnalyticsTab(gui)
+
+	// Log của batch processor hiển thị ở Emails tab, bất kể crawl bắt đầu từ tab nào
+	gui.crawlController.SetLogger(gui.emailsTab)
+	gui.crawlController.OnStateChange(func(state CrawlState) {
+		if state != CrawlRunning {
+			return
+		}
+		if window := gui.crawlWindowStatus(); window != "" {
+			gui.postStatus(StatusSourceSchedule, SeverityInfo, window)
+		}
+	})
 
 	return gui
 }
 
+// acquireInstanceLock takes the instance lock or asks whether to take over
+// from the running instance; declining quits the app
+func (gui *CrawlerGUI) acquireInstanceLock(force bool) {
+	cfg := gui.configTab.ResolvedConfig()
+	lock, holder, err := storageInternal.AcquireInstanceLock(storageInternal.InstanceLockPath(cfg.DBPath), "gui", force)
+	if err == nil {
+		gui.instanceLock = lock
+		return
+	}
+
+	if !errors.Is(err, storageInternal.ErrInstanceRunning) {
+		log.Printf("⚠️ Instance lock unavailable: %v", err)
+		return
+	}
+
+	message := widget.NewLabel(fmt.Sprintf(
+		"Another instance is running:\n%s\n\n"+
+			"Running two instances on the same emails.db / tokens.txt can corrupt them.\n"+
+			"Take over only if the other instance is not really running.", holder))
+	dialog.ShowCustomConfirm("Another Instance Running", "Take Over", "Quit", message,
+		func(takeOver bool) {
+			if takeOver {
+				gui.acquireInstanceLock(true)
+				gui.updateStatus("⚠️ Took over instance lock")
+				return
+			}
+			gui.app.Quit()
+		}, gui.window)
+}
+
+// moveInstanceLock takes the instance lock of the database the config now
+// points at, releasing the one of the previous database
+func (gui *CrawlerGUI) moveInstanceLock() {
+	gui.updateUI <- func() {
+		if gui.instanceLock == nil {
+			return
+		}
+		gui.instanceLock.Release()
+		gui.instanceLock = nil
+		gui.acquireInstanceLock(false)
+	}
+}
+
 // performComprehensiveLicenseCheck thực hiện kiểm tra license toàn diện
 func (gui *CrawlerGUI) performComprehensiveLicenseCheck() {
 	log.Printf("🔒 Performing comprehensive license validation...")
@@ -171,29 +270,7 @@ func (gui *CrawlerGUI) performComprehensiveLicenseCheck() {
 
 // startLicenseMonitoring bắt đầu theo dõi license và usage
 func (gui *CrawlerGUI) startLicenseMonitoring() {
-	if gui.licenseCheckTicker != nil {
-		gui.licenseCheckTicker.Stop()
-	}
-
-	gui.licenseCheckTicker = time.NewTicker(gui.usageCheckInterval)
-	go func() {
-		defer func() {
-			if gui.licenseCheckTicker != nil {
-				gui.licenseCheckTicker.Stop()
-			}
-		}()
-
-		for {
-			select {
-			case <-gui.licenseCheckTicker.C:
-				gui.updateUI <- func() {
-					gui.performPeriodicLicenseCheck()
-				}
-			case <-gui.ctx.Done():
-				return
-			}
-		}
-	}()
+	gui.refreshScheduler.Schedule("license-check", RefreshLicense, gui.performPeriodicLicenseCheck)
 }
 
 // performPeriodicLicenseCheck kiểm tra license định kỳ
@@ -203,7 +280,7 @@ func (gui *CrawlerGUI) performPeriodicLicenseCheck() {
 	}
 
 	// Update usage counters if crawler is running
-	if gui.isRunning && gui.autoCrawler != nil {
+	if gui.crawlController.Active() != nil {
 		gui.updateUsageFromCrawler()
 	}
 
@@ -217,7 +294,7 @@ func (gui *CrawlerGUI) performPeriodicLicenseCheck() {
 	}
 
 	// Check usage limits if crawler is running
-	if gui.isRunning {
+	if gui.crawlController.Running() {
 		gui.checkUsageLimitsDuringRuntime()
 	}
 
@@ -227,22 +304,12 @@ func (gui *CrawlerGUI) performPeriodicLicenseCheck() {
 
 // updateUsageFromCrawler cập nhật usage từ crawler hiện tại
 func (gui *CrawlerGUI) updateUsageFromCrawler() {
-	gui.crawlerMux.RLock()
-	autoCrawler := gui.autoCrawler
-	gui.crawlerMux.RUnlock()
+	autoCrawler := gui.crawlController.Active()
 
 	if autoCrawler != nil {
-		emailStorage, _, _ := autoCrawler.GetStorageServices()
-		if emailStorage != nil {
-			stats, err := emailStorage.GetEmailStats()
-			if err == nil {
-				processed := stats["success"] + stats["failed"]
-				success := stats["success"]
-
-				// Update license wrapper counters
-				gui.licenseWrapper.UpdateUsageCounters(processed, success)
-			}
-		}
+		// Update license wrapper counters
+		processed, success := autoCrawler.GetBatchProcessor().GetCurrentUsage()
+		gui.licenseWrapper.UpdateUsageCounters(processed, success)
 	}
 }
 
@@ -250,17 +317,18 @@ func (gui *CrawlerGUI) updateUsageFromCrawler() {
 func (gui *CrawlerGUI) checkUsageLimitsDuringRuntime() {
 	usageStats := gui.licenseWrapper.GetUsageStats()
 
-	currentProcessed, ok1 := usageStats["current_processed_emails"].(int)
+	// Chỉ email billable (status=success) tính vào quota
+	billable, ok1 := usageStats["total_billable_emails"].(int)
 	maxEmails, ok2 := usageStats["max_emails"].(int)
 
 	if ok1 && ok2 && maxEmails > 0 {
 		// Check if approaching limit (90%)
-		if float64(currentProcessed)/float64(maxEmails) >= 0.9 {
-			remaining := maxEmails - currentProcessed
+		if float64(billable)/float64(maxEmails) >= 0.9 {
+			remaining := maxEmails - billable
 			if remaining <= 0 {
 				gui.handleEmailLimitReached()
 			} else if remaining <= 10 {
-				gui.showApproachingLimitWarning(currentProcessed, maxEmails, remaining)
+				gui.showApproachingLimitWarning(billable, maxEmails, remaining)
 			}
 		}
 	}
@@ -270,8 +338,8 @@ func (gui *CrawlerGUI) checkUsageLimitsDuringRuntime() {
 func (gui *CrawlerGUI) handleEmailLimitReached() {
 	log.Printf("🚫 Email processing limit reached")
 
-	if gui.isRunning {
-		gui.stopCrawler()
+	if gui.crawlController.Running() {
+		gui.crawlController.Stop()
 
 		gui.updateUI <- func() {
 			dialog.ShowInformation("License Limit Reached",
@@ -281,7 +349,7 @@ func (gui *CrawlerGUI) handleEmailLimitReached() {
 		}
 	}
 
-	gui.updateStatus("❌ Email limit reached - Crawler stopped")
+	gui.postStatus(StatusSourceLicense, SeverityError, "Email limit reached - Crawler stopped")
 }
 
 // showApproachingLimitWarning hiển thị cảnh báo khi gần đạt giới hạn
@@ -291,7 +359,7 @@ func (gui *CrawlerGUI) showApproachingLimitWarning(current, max, remaining int)
 		gui.lastUsageCheck = time.Now()
 
 		log.Printf("⚠️ Approaching email limit: %d/%d (remaining: %d)", current, max, remaining)
-		gui.updateStatus(fmt.Sprintf("⚠️ Email limit: %d/%d (remaining: %d)", current, max, remaining))
+		gui.postStatus(StatusSourceLicense, SeverityWarning, fmt.Sprintf("Email limit: %d/%d (remaining: %d)", current, max, remaining))
 
 		gui.updateUI <- func() {
 			dialog.ShowInformation("Approaching License Limit",
@@ -306,8 +374,8 @@ func (gui *CrawlerGUI) showApproachingLimitWarning(current, max, remaining int)
 
 // handleLicenseBecameInvalid xử lý khi license bị invalid trong runtime
 func (gui *CrawlerGUI) handleLicenseBecameInvalid(err error) {
-	if gui.isRunning {
-		gui.stopCrawler()
+	if gui.crawlController.Running() {
+		gui.crawlController.Stop()
 	}
 
 	gui.disableAppFeatures()
@@ -317,30 +385,55 @@ func (gui *CrawlerGUI) handleLicenseBecameInvalid(err error) {
 		gui.selectLicenseTab()
 	}
 
-	gui.updateStatus("❌ License invalid - Please reactivate")
+	gui.postStatus(StatusSourceLicense, SeverityError, "License invalid - Please reactivate")
 }
 
 // updateStatusWithLicenseInfo cập nhật status với thông tin license
 func (gui *CrawlerGUI) updateStatusWithLicenseInfo() {
 	usageStats := gui.licenseWrapper.GetUsageStats()
 
-	if currentProcessed, ok := usageStats["current_processed_emails"].(int); ok {
+	if billable, ok := usageStats["total_billable_emails"].(int); ok {
+		status := fmt.Sprintf("Licensed - Billable: %d emails (Unlimited)", billable)
 		if maxEmails, ok := usageStats["max_emails"].(int); ok && maxEmails > 0 {
-			remaining := maxEmails - currentProcessed
-			gui.updateStatus(fmt.Sprintf("Licensed - Used: %d/%d emails (Remaining: %d)",
-				currentProcessed, maxEmails, remaining))
-		} else {
-			gui.updateStatus(fmt.Sprintf("Licensed - Processed: %d emails (Unlimited)", currentProcessed))
+			remaining, _ := usageStats["remaining_emails"].(int)
+			status = fmt.Sprintf("Licensed - Used: %d/%d billable emails (Remaining: %d)",
+				billable, maxEmails, remaining)
+		}
+		if window := gui.crawlWindowStatus(); window != "" {
+			gui.postStatus(StatusSourceSchedule, SeverityInfo, window)
 		}
+		gui.postStatus(StatusSourceLicense, SeverityInfo, status)
+	}
+}
+
+// crawlWindowStatus describes the crawl window state of the running crawler,
+// or "" when nothing is running or no window is configured
+func (gui *CrawlerGUI) crawlWindowStatus() string {
+	autoCrawler := gui.crawlController.Active()
+	if autoCrawler == nil {
+		return ""
+	}
+
+	window := autoCrawler.GetCrawlWindow()
+	if window == nil {
+		return ""
 	}
+	now := time.Now()
+	if window.Contains(now) {
+		return fmt.Sprintf("▶️ Crawl window %s - open until %s", window, window.NextClose(now).Format("15:04"))
+	}
+	return fmt.Sprintf("⏸️ Outside crawl window %s - resumes %s", window, window.NextOpen(now).Format("Mon 15:04"))
+}
+
+// crawlConfig returns the config every crawl runs with: the Config tab
+// settings with paths resolved for this run and license limits applied
+func (gui *CrawlerGUI) crawlConfig() (models.Config, error) {
+	return gui.licenseWrapper.ApplyFeatureGates(gui.configTab.ResolveConfig())
 }
 
 // startCrawler với comprehensive license checks
 func (gui *CrawlerGUI) startCrawler() {
-	gui.crawlerMux.Lock()
-	defer gui.crawlerMux.Unlock()
-
-	if gui.isRunning {
+	if gui.crawlController.Running() {
 		return
 	}
 
@@ -370,8 +463,8 @@ func (gui *CrawlerGUI) startCrawler() {
 		return
 	}
 
-	// Validate inputs
-	if len(gui.accountsTab.accounts) == 0 {
+	// Validate inputs (giả lập không cần accounts)
+	if len(gui.accountsTab.accounts) == 0 && !gui.configTab.ResolvedConfig().Simulate {
 		gui.updateUI <- func() {
 			dialog.ShowError(fmt.Errorf("no accounts configured"), gui.window)
 		}
@@ -388,13 +481,27 @@ func (gui *CrawlerGUI) startCrawler() {
 	emailCount := len(gui.emailsTab.emails)
 	accountCount := len(gui.accountsTab.accounts)
 
-	if err := gui.licenseWrapper.CheckCrawlingLimits(emailCount, accountCount); err != nil {
+	if err := gui.licenseWrapper.CheckCrawlingLimits(0, accountCount); err != nil {
 		gui.updateUI <- func() {
 			dialog.ShowError(fmt.Errorf("Usage limits exceeded: %v", err), gui.window)
 		}
 		return
 	}
 
+	// Batch sẽ được cắt theo quota còn lại; chỉ chặn khi đã hết hẳn quota
+	if quota, err := gui.licenseWrapper.RemainingQuota(); err == nil {
+		if quota == 0 {
+			gui.updateUI <- func() {
+				dialog.ShowError(fmt.Errorf("Email quota exhausted.\n\nUpgrade your license or add a quota add-on to continue."), gui.window)
+				gui.selectLicenseTab()
+			}
+			return
+		}
+		if quota > 0 && emailCount > quota {
+			gui.updateStatus(fmt.Sprintf("⚠️ Quota allows %d of %d emails - the rest stay pending (see quota-withheld-*.txt)", quota, emailCount))
+		}
+	}
+
 	// Reset usage counters for new crawling session
 	gui.licenseWrapper.ResetUsageCounters()
 	gui.sessionStartTime = time.Now()
@@ -407,47 +514,38 @@ func (gui *CrawlerGUI) startCrawler() {
 	go func() {
 		defer func() { gui.updateUI <- func() { progressDialog.Hide() } }()
 
-		cfg := gui.configTab.config
-		autoCrawler, err := orchestrator.New(cfg)
+		cfg, err := gui.crawlConfig()
 		if err != nil {
 			gui.updateUI <- func() {
-				dialog.ShowError(fmt.Errorf("failed to initialize: %v", err), gui.window)
+				dialog.ShowError(fmt.Errorf("%v\n\nUpgrade to PRO in the License tab or switch Crawl Mode to email.", err), gui.window)
 			}
 			return
 		}
 
-		// CRITICAL: Inject license wrapper into batch processor
-		batchProcessor := autoCrawler.GetBatchProcessor()
-		if batchProcessor != nil {
-			batchProcessor.SetLicenseWrapper(gui.licenseWrapper)
-			log.Printf("✅ License wrapper injected into batch processor")
-		}
-
-		gui.autoCrawler = autoCrawler
-		gui.isRunning = true
-
 		// Start enhanced license monitoring
-		if gui.licenseCheckTicker == nil {
+		if !gui.refreshScheduler.Scheduled("license-check") {
 			gui.startLicenseMonitoring()
 		}
 
-		err = autoCrawler.Run()
-
-		gui.crawlerMux.Lock()
-		gui.isRunning = false
-		gui.autoCrawler = nil
-		gui.crawlerMux.Unlock()
+		err = gui.crawlController.Run(cfg)
+		if errors.Is(err, ErrCrawlRunning) {
+			gui.updateUI <- func() {
+				dialog.ShowError(err, gui.window)
+			}
+			return
+		}
 
 		gui.updateUI <- func() {
 			if gui.emailsTab != nil {
 				gui.emailsTab.OnCrawlerStopped()
 			}
 			if err != nil {
-				gui.updateStatus("Stopped with errors")
+				gui.postStatus(StatusSourceCrawler, SeverityError, "Stopped with errors")
 			} else {
-				gui.updateStatus("Completed successfully")
+				gui.postStatus(StatusSourceCrawler, SeveritySuccess, "Completed successfully")
 				gui.resultsTab.RefreshResults()
 			}
+			gui.analyticsTab.RefreshRuns()
 		}
 
 		gui.updateUI <- func() {
@@ -468,31 +566,35 @@ func (gui *CrawlerGUI) showFinalUsageStats() {
 	usageStats := gui.licenseWrapper.GetUsageStats()
 
 	currentProcessed, _ := usageStats["current_processed_emails"].(int)
-	currentSuccess, _ := usageStats["current_success_emails"].(int)
+	currentSuccess, _ := usageStats["billable_emails"].(int)
+	nonBillable, _ := usageStats["non_billable_emails"].(int)
+	totalBillable, _ := usageStats["total_billable_emails"].(int)
 	maxEmails, _ := usageStats["max_emails"].(int)
 	sessionDuration, _ := usageStats["session_duration"].(string)
 
 	var message string
 	if maxEmails > 0 {
-		remaining := maxEmails - currentProcessed
+		remaining, _ := usageStats["remaining_emails"].(int)
 		message = fmt.Sprintf("Licensed crawling session completed!\n\n"+
 			"📊 Session Statistics:\n"+
 			"• Processed: %d emails\n"+
-			"• Successful: %d emails\n"+
+			"• Successful (billable): %d emails\n"+
+			"• Failed (not billed): %d emails\n"+
 			"• License limit: %d/%d emails used\n"+
 			"• Remaining: %d emails\n"+
 			"• Session duration: %s\n\n"+
 			"Thank you for using LinkedIn Crawler!",
-			currentProcessed, currentSuccess, currentProcessed, maxEmails, remaining, sessionDuration)
+			currentProcessed, currentSuccess, nonBillable, totalBillable, maxEmails, remaining, sessionDuration)
 	} else {
 		message = fmt.Sprintf("Licensed crawling session completed!\n\n"+
 			"📊 Session Statistics:\n"+
 			"• Processed: %d emails\n"+
-			"• Successful: %d emails\n"+
+			"• Successful (billable): %d emails\n"+
+			"• Failed (not billed): %d emails\n"+
 			"• License: Unlimited usage\n"+
 			"• Session duration: %s\n\n"+
 			"Thank you for using LinkedIn Crawler!",
-			currentProcessed, currentSuccess, sessionDuration)
+			currentProcessed, currentSuccess, nonBillable, sessionDuration)
 	}
 
 	dialog.ShowInformation("Session Complete", message, gui.window)
@@ -543,13 +645,15 @@ func (gui *CrawlerGUI) enableAppFeatures() {
 	gui.isLicenseValid = true
 	log.Printf("✅ Enabling all app features - license is valid")
 
-	// Auto-deduplicate hit.txt on startup only after license validation
-	fmt.Println("🔄 Checking for duplicates in hit.txt...")
-	utils.AutoDeduplicateOnStartup()
+	hitFile := gui.configTab.ResolvedConfig().OutputFilePath
+
+	// Auto-deduplicate hit file on startup only after license validation
+	fmt.Printf("🔄 Checking for duplicates in %s...\n", hitFile)
+	utils.AutoDeduplicateOnStartup(hitFile)
 
-	// Validate hit.txt
-	if _, err := os.Stat("hit.txt"); err == nil {
-		issues := utils.ValidateHitFile("hit.txt")
+	// Validate hit file
+	if _, err := os.Stat(hitFile); err == nil {
+		issues := utils.ValidateHitFile(hitFile)
 		if len(issues) > 1 || (len(issues) == 1 && issues[0] != "File validation passed - no issues found") {
 			issuesText := "Hit.txt validation results:\n\n" + fmt.Sprintf("Found %d issues:\n", len(issues))
 			for i, issue := range issues {
@@ -583,14 +687,26 @@ func (gui *CrawlerGUI) OnLicenseActivated() {
 func (gui *CrawlerGUI) cleanup() {
 	gui.cancel()
 
-	// Stop license monitoring
-	if gui.licenseCheckTicker != nil {
-		gui.licenseCheckTicker.Stop()
-		gui.licenseCheckTicker = nil
-	}
+	// Stop license monitoring and tab refreshes
+	gui.refreshScheduler.Stop()
 
 	gui.saveSettings()
 
+	if !gui.reviewer {
+		if err := gui.licenseWrapper.FlushUsage(); err != nil {
+			log.Printf("⚠️ Failed to save usage ledger: %v", err)
+		}
+	}
+
+	if gui.instanceLock != nil {
+		gui.instanceLock.Release()
+		gui.instanceLock = nil
+	}
+	if gui.singleInstance != nil {
+		gui.singleInstance.Close()
+		gui.singleInstance = nil
+	}
+
 	if gui.emailsTab != nil {
 		gui.emailsTab.Cleanup()
 	}
@@ -617,26 +733,14 @@ func (gui *CrawlerGUI) cleanup() {
 // Rest of the existing methods remain the same...
 func (gui *CrawlerGUI) setupUI() {
 	// Existing setupUI implementation...
-}
-
-func (gui *CrawlerGUI) stopCrawler() {
-	gui.crawlerMux.Lock()
-	defer gui.crawlerMux.Unlock()
-	if !gui.isRunning || gui.autoCrawler == nil {
-		return
-	}
-	down := gui.autoCrawler.GetShutdownRequested()
-	if down != nil {
-		*down = 1
-	}
-	gui.updateUI <- func() { gui.updateStatus("Stopping...") }
+	gui.window.SetMainMenu(gui.createMainMenu())
 }
 
 func (gui *CrawlerGUI) saveSettings() {
 	if !gui.isLicenseValid {
 		return
 	}
-	gui.updateUI <- func() { gui.configTab.SaveConfig() }
+	gui.updateUI <- func() { gui.configTab.SaveConfigOnExit() }
 	gui.updateUI <- func() { gui.accountsTab.SaveAccounts() }
 	gui.updateUI <- func() { gui.emailsTab.SaveEmails() }
 }
@@ -650,8 +754,51 @@ func (gui *CrawlerGUI) loadSettings() {
 	gui.updateUI <- func() { gui.emailsTab.LoadEmails() }
 }
 
+// reloadAfterUnlock reads the tabs again once an encrypted database is
+// unlocked, since they failed to open it before
+func (gui *CrawlerGUI) reloadAfterUnlock() {
+	if storageInternal.PassphraseSet() && gui.isLicenseValid {
+		gui.reloadProject()
+	}
+}
+
+// reloadProject reads accounts, emails, results and runs again after the
+// files or database under the tabs changed
+func (gui *CrawlerGUI) reloadProject() {
+	gui.accountsTab.LoadAccounts()
+	gui.emailsTab.LoadEmails()
+	gui.resultsTab.RefreshResults()
+	gui.analyticsTab.RefreshRuns()
+}
+
+// updateStatus posts a general status message; the severity is taken from
+// its leading emoji
 func (gui *CrawlerGUI) updateStatus(status string) {
-	if gui.statusBar != nil {
-		gui.statusBar.SetText(status)
+	gui.postStatus(StatusSourceApp, inferStatusSeverity(status), trimStatusIcon(status))
+}
+
+// postStatus posts a status message from source with an explicit severity
+func (gui *CrawlerGUI) postStatus(source string, severity StatusSeverity, text string) {
+	if gui.statusCenter != nil {
+		gui.statusCenter.Post(source, severity, text)
+	}
+}
+
+// memoryTrimLogs is how many entries each log buffer keeps when memory is low
+const memoryTrimLogs = 50
+
+// trimMemory drops the email status cache and old log entries when the
+// crawler nears its memory limit, and warns in the status bar
+func (gui *CrawlerGUI) trimMemory(usedMB, limitMB int) {
+	gui.updateUI <- func() {
+		if gui.emailsTab != nil {
+			gui.emailsTab.clearEmailStatusCache()
+			gui.emailsTab.logView.Trim(memoryTrimLogs)
+		}
+		if gui.accountsTab != nil {
+			gui.accountsTab.logView.Trim(memoryTrimLogs)
+		}
+		gui.postStatus(StatusSourceCrawler, SeverityWarning,
+			fmt.Sprintf("Memory %d/%d MB - caches trimmed, concurrency may be reduced", usedMB, limitMB))
 	}
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+// ShowExportProject zips the config, database, emails, hits and logs of the
+// current campaign into a bundle, with or without the accounts
+func (ct *ConfigTab) ShowExportProject() {
+	credentials := widget.NewCheck("Include accounts, tokens, passwords and webhook URLs", nil)
+	items := []*widget.FormItem{
+		{Text: "", Widget: credentials, HintText: "Leave off when sending the bundle to support"},
+	}
+	form := dialog.NewForm("Export Project", "Choose File...", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			dest := writer.URI().Path()
+			writer.Close()
+			ct.exportProject(dest, credentials.Checked)
+		}, ct.gui.window)
+		saveDialog.SetFileName("project.zip")
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".zip"}))
+		saveDialog.Show()
+	}, ct.gui.window)
+	form.Resize(fyne.NewSize(520, 180))
+	form.Show()
+}
+
+// exportProject writes the bundle in the background
+func (ct *ConfigTab) exportProject(dest string, credentials bool) {
+	templates := ct.config // Bundle giữ config như đã cấu hình
+	ct.gui.updateStatus("Exporting project...")
+	go func() {
+		var bundle *storageInternal.ProjectBundle
+		emailStorage, err := ct.openMaintenanceStorage()
+		if err == nil {
+			bundle, err = emailStorage.ExportProject(templates, dest, credentials)
+			emailStorage.CloseDB()
+		}
+		ct.gui.updateUI <- func() {
+			if err != nil {
+				dialog.ShowError(err, ct.gui.window)
+				ct.gui.updateStatus("❌ Project export failed")
+				return
+			}
+			ct.gui.updateStatus(fmt.Sprintf("✅ Exported %d files to %s (%d KB)", len(bundle.Files), bundle.Path, bundle.Size/1024))
+		}
+	}()
+}
+
+// ShowImportProject opens a bundle, asks for the folder to extract it into
+// and switches the config to the imported project
+func (ct *ConfigTab) ShowImportProject() {
+	if ct.gui.crawlController.Running() {
+		dialog.ShowInformation("Import Project", "Stop the crawler before importing a project", ct.gui.window)
+		return
+	}
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		path := reader.URI().Path()
+		reader.Close()
+
+		bundle, err := storageInternal.ReadProjectBundle(path)
+		if err != nil {
+			dialog.ShowError(err, ct.gui.window)
+			return
+		}
+		dialog.ShowFolderOpen(func(folder fyne.ListableURI, err error) {
+			if err != nil || folder == nil {
+				return
+			}
+			ct.confirmImportProject(bundle, folder.Path())
+		}, ct.gui.window)
+	}, ct.gui.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".zip"}))
+	openDialog.Show()
+}
+
+// confirmImportProject shows what the bundle holds and what it replaces in
+// dir before importing it
+func (ct *ConfigTab) confirmImportProject(bundle *storageInternal.ProjectBundle, dir string) {
+	message := fmt.Sprintf("Project exported from %s on %s: %d files.\n\nExtract into %s and switch the config to it?",
+		bundle.Host, bundle.CreatedAt.Local().Format("2006-01-02 15:04"), len(bundle.Files), dir)
+	if !bundle.Credentials {
+		message += "\n\nThe bundle has no accounts or tokens; your passwords and webhook URLs are kept."
+	}
+	if conflicts := storageInternal.ProjectConflicts(bundle, dir); len(conflicts) > 0 {
+		if len(conflicts) > 5 {
+			conflicts = append(conflicts[:5], "...")
+		}
+		message += "\n\nThese files are replaced:\n" + strings.Join(conflicts, "\n")
+	}
+
+	dialog.ShowConfirm("Import Project", message, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		go func() {
+			_, imported, err := storageInternal.ImportProject(bundle.Path, dir, true)
+			ct.gui.updateUI <- func() {
+				if err != nil {
+					dialog.ShowError(err, ct.gui.window)
+					return
+				}
+				if !bundle.Credentials {
+					imported = storageInternal.KeepCredentials(imported, ct.config)
+				}
+				ct.config = imported
+				ct.updateFormFromConfig()
+				ct.SaveConfig()
+				ct.gui.unlockDatabase(ct.ResolveConfig().DBPath, ct.gui.reloadProject)
+			}
+		}()
+	}, ct.gui.window)
+}
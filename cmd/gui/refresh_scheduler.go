@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RefreshKind groups periodic GUI refreshes that share one interval
+type RefreshKind string
+
+const (
+	RefreshStats     RefreshKind = "stats"      // Emails tab stats và tiến độ crawl
+	RefreshResults   RefreshKind = "results"    // Results tab auto-refresh
+	RefreshTokenInfo RefreshKind = "token_info" // Thông tin tokens.txt ở Accounts tab
+	RefreshLicense   RefreshKind = "license"    // License tab và kiểm tra usage định kỳ
+)
+
+// minRefreshInterval chặn interval quá nhỏ làm GUI đọc DB liên tục
+const minRefreshInterval = time.Second
+
+// RefreshIntervals holds the interval of each refresh kind
+type RefreshIntervals struct {
+	Stats     time.Duration
+	Results   time.Duration
+	TokenInfo time.Duration
+	License   time.Duration
+}
+
+// DefaultRefreshIntervals returns the built-in refresh intervals
+func DefaultRefreshIntervals() RefreshIntervals {
+	return RefreshIntervals{
+		Stats:     5 * time.Second,
+		Results:   5 * time.Second,
+		TokenInfo: 10 * time.Second,
+		License:   30 * time.Second,
+	}
+}
+
+// Of returns the interval of kind
+func (ri RefreshIntervals) Of(kind RefreshKind) time.Duration {
+	switch kind {
+	case RefreshStats:
+		return ri.Stats
+	case RefreshResults:
+		return ri.Results
+	case RefreshTokenInfo:
+		return ri.TokenInfo
+	default:
+		return ri.License
+	}
+}
+
+// Validate checks that every interval is at least minRefreshInterval
+func (ri RefreshIntervals) Validate() error {
+	for _, kind := range []RefreshKind{RefreshStats, RefreshResults, RefreshTokenInfo, RefreshLicense} {
+		if ri.Of(kind) < minRefreshInterval {
+			return fmt.Errorf("%s refresh interval must be at least %v", kind, minRefreshInterval)
+		}
+	}
+	return nil
+}
+
+// refreshJob is one scheduled refresh
+type refreshJob struct {
+	kind   RefreshKind
+	run    func()
+	ticker *time.Ticker
+	done   chan struct{}
+}
+
+// RefreshScheduler runs all periodic GUI refreshes. Jobs are named so a tab
+// can replace or cancel its own refresh; each job runs on the UI thread.
+type RefreshScheduler struct {
+	gui *CrawlerGUI
+
+	mutex     sync.Mutex
+	intervals RefreshIntervals
+	jobs      map[string]*refreshJob
+}
+
+// NewRefreshScheduler creates a scheduler with the default intervals
+func NewRefreshScheduler(gui *CrawlerGUI) *RefreshScheduler {
+	return &RefreshScheduler{
+		gui:       gui,
+		intervals: DefaultRefreshIntervals(),
+		jobs:      make(map[string]*refreshJob),
+	}
+}
+
+// Intervals returns the current intervals
+func (rs *RefreshScheduler) Intervals() RefreshIntervals {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	return rs.intervals
+}
+
+// SetIntervals changes the intervals and reschedules running jobs
+func (rs *RefreshScheduler) SetIntervals(intervals RefreshIntervals) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	rs.intervals = intervals
+	for _, job := range rs.jobs {
+		job.ticker.Reset(intervals.Of(job.kind))
+	}
+}
+
+// Schedule runs fn every interval of kind, replacing any job with the same name
+func (rs *RefreshScheduler) Schedule(name string, kind RefreshKind, fn func()) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	if old, exists := rs.jobs[name]; exists {
+		rs.stopJob(old)
+	}
+	job := &refreshJob{
+		kind:   kind,
+		run:    fn,
+		ticker: time.NewTicker(rs.intervals.Of(kind)),
+		done:   make(chan struct{}),
+	}
+	rs.jobs[name] = job
+
+	go func() {
+		for {
+			select {
+			case <-job.ticker.C:
+				select {
+				case rs.gui.updateUI <- job.run:
+				case <-job.done:
+					return
+				case <-rs.gui.ctx.Done():
+					return
+				}
+			case <-job.done:
+				return
+			case <-rs.gui.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Scheduled reports whether a job named name is running
+func (rs *RefreshScheduler) Scheduled(name string) bool {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	_, exists := rs.jobs[name]
+	return exists
+}
+
+// Cancel stops the job named name, if any
+func (rs *RefreshScheduler) Cancel(name string) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	if job, exists := rs.jobs[name]; exists {
+		rs.stopJob(job)
+		delete(rs.jobs, name)
+	}
+}
+
+// Stop cancels all jobs
+func (rs *RefreshScheduler) Stop() {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	for name, job := range rs.jobs {
+		rs.stopJob(job)
+		delete(rs.jobs, name)
+	}
+}
+
+func (rs *RefreshScheduler) stopJob(job *refreshJob) {
+	job.ticker.Stop()
+	close(job.done)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"linkedin-crawler/internal/models"
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+// ShowRequeueFailed shows the failed emails by error class and sets the
+// selected classes back to pending
+func (et *EmailsTab) ShowRequeueFailed() {
+	if atomic.LoadInt32(&et.isCrawling) == 1 {
+		dialog.ShowInformation("Crawl Running", "Stop the crawl before re-queuing failed emails.", et.gui.window)
+		return
+	}
+
+	go func() {
+		emailStorage := storageInternal.NewEmailStorage()
+		if err := emailStorage.InitDB(); err != nil {
+			et.gui.updateUI <- func() { dialog.ShowError(err, et.gui.window) }
+			return
+		}
+		defer emailStorage.CloseDB()
+
+		stats, err := emailStorage.GetErrorClassStats()
+		et.gui.updateUI <- func() {
+			if err != nil {
+				dialog.ShowError(err, et.gui.window)
+				return
+			}
+			et.showRequeueDialog(stats)
+		}
+	}()
+}
+
+// showRequeueDialog lets the user pick the error classes to re-queue
+func (et *EmailsTab) showRequeueDialog(stats map[string]int) {
+	if len(stats) == 0 {
+		dialog.ShowInformation("Re-queue Failed", "There are no failed emails.", et.gui.window)
+		return
+	}
+
+	var options []string
+	classByOption := make(map[string]string)
+	for _, class := range models.EmailErrorClasses {
+		if stats[class] == 0 {
+			continue
+		}
+		option := fmt.Sprintf("%s - %s emails", models.EmailErrorLabel(class), et.formatNumber(stats[class]))
+		options = append(options, option)
+		classByOption[option] = class
+	}
+	checks := widget.NewCheckGroup(options, nil)
+
+	content := container.NewVBox(
+		widget.NewLabel("Failed emails by last error. Selected classes go back to pending:"),
+		checks,
+	)
+	dialog.ShowCustomConfirm("Re-queue Failed", "Re-queue", "Cancel", content, func(confirmed bool) {
+		if !confirmed || len(checks.Selected) == 0 {
+			return
+		}
+		var classes []string
+		for _, option := range checks.Selected {
+			classes = append(classes, classByOption[option])
+		}
+		go et.requeueFailed(classes)
+	}, et.gui.window)
+}
+
+// requeueFailed sets failed emails of classes back to pending and refreshes the stats
+func (et *EmailsTab) requeueFailed(classes []string) {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		et.gui.postStatus(StatusSourceApp, SeverityError, fmt.Sprintf("Re-queue failed: %v", err))
+		return
+	}
+	defer emailStorage.CloseDB()
+
+	n, err := emailStorage.RequeueFailedByErrorClass(classes)
+	if err != nil {
+		et.gui.postStatus(StatusSourceApp, SeverityError, fmt.Sprintf("Re-queue failed: %v", err))
+		return
+	}
+
+	et.addLog(fmt.Sprintf("🔄 Đã đưa %d emails failed %v về pending", n, classes))
+	et.gui.postStatus(StatusSourceApp, SeveritySuccess, fmt.Sprintf("Re-queued %d failed emails", n))
+	et.gui.updateUI <- func() {
+		et.lastStats = nil
+		et.clearEmailStatusCache()
+		et.updateStatsFromDatabase()
+		if et.emailsList != nil {
+			et.emailsList.Refresh()
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/user"
+	"strings"
+	"time"
+
+	storageInternal "linkedin-crawler/internal/storage"
+)
+
+// reviewerName returns the name results are claimed under: the Reviewer field
+// of the Results tab, saved in the preferences, or the OS user name
+func (rt *ResultsTab) reviewerName() string {
+	if rt.reviewerEntry != nil {
+		if name := strings.TrimSpace(rt.reviewerEntry.Text); name != "" {
+			return name
+		}
+	}
+	return defaultReviewerName()
+}
+
+// defaultReviewerName is the OS user name, or "" when it is unknown
+func defaultReviewerName() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return ""
+}
+
+// claimedByOther reports whether another reviewer holds a live claim on r
+func (r CrawlerResult) claimedByOther(reviewer string) bool {
+	return r.ClaimedBy != "" && r.ClaimedBy != reviewer && time.Since(r.ClaimedAt) < storageInternal.ResultClaimExpiry
+}
+
+// claimLabel describes the claim of r for the details dialog
+func (r CrawlerResult) claimLabel() string {
+	if r.ClaimedBy == "" {
+		return "Unclaimed"
+	}
+	label := fmt.Sprintf("%s since %s", r.ClaimedBy, r.ClaimedAt.Local().Format("2006-01-02 15:04"))
+	if time.Since(r.ClaimedAt) >= storageInternal.ResultClaimExpiry {
+		label += " (expired)"
+	}
+	return label
+}
+
+// setResultClaim claims the results of emails for the reviewer, or releases
+// the claim, and updates the rows in place. It returns false when nothing
+// changed.
+func (rt *ResultsTab) setResultClaim(emails []string, claim bool) bool {
+	reviewer := rt.reviewerName()
+	if reviewer == "" {
+		rt.gui.postStatus(StatusSourceResults, SeverityWarning, "Enter your name in the Reviewer field to claim results")
+		return false
+	}
+
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		rt.gui.postStatus(StatusSourceResults, SeverityError, fmt.Sprintf("Claiming result failed: %v", err))
+		return false
+	}
+	defer emailStorage.CloseDB()
+
+	var err error
+	if claim {
+		err = emailStorage.ClaimResults(emails, reviewer)
+	} else {
+		err = emailStorage.ReleaseResults(emails, reviewer)
+	}
+	if errors.Is(err, storageInternal.ErrResultClaimed) {
+		// Người khác vừa claim: tải lại để thấy ai đang giữ
+		rt.gui.postStatus(StatusSourceResults, SeverityWarning, err.Error())
+		rt.gui.updateUI <- rt.RefreshResults
+		return false
+	}
+	if err != nil {
+		rt.gui.postStatus(StatusSourceResults, SeverityError, fmt.Sprintf("Claiming result failed: %v", err))
+		return false
+	}
+
+	claimedBy, claimedAt, message := "", time.Time{}, "released"
+	if claim {
+		claimedBy, claimedAt, message = reviewer, time.Now(), "claimed by "+reviewer
+	}
+	rt.gui.postStatus(StatusSourceResults, SeveritySuccess, fmt.Sprintf("%s %s", strings.Join(emails, ", "), message))
+	rt.gui.updateUI <- func() {
+		key := strings.Join(emails, "; ")
+		for _, results := range [][]CrawlerResult{rt.results, rt.originalResults} {
+			for i := range results {
+				if results[i].Email == key {
+					results[i].ClaimedBy = claimedBy
+					results[i].ClaimedAt = claimedAt
+				}
+			}
+		}
+		rt.resultsTable.Refresh()
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"linkedin-crawler/internal/models"
+	storageInternal "linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
+)
+
+// showResultDetails shows a result with its custom input fields and lets the
+// user claim it and set its workflow status, tags and notes. A row merged by
+// LinkedIn URL updates all of its emails.
+func (rt *ResultsTab) showResultDetails(result CrawlerResult) {
+	var labels []string
+	for _, status := range models.ResultStatuses {
+		labels = append(labels, models.ResultStatusLabel(status))
+	}
+	statusRadio := widget.NewRadioGroup(labels, nil)
+	statusRadio.Horizontal = true
+	statusRadio.Required = true
+	statusRadio.SetSelected(models.ResultStatusLabel(result.WorkflowStatus))
+
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetText(strings.Join(result.Tags, ", "))
+	tagsEntry.SetPlaceHolder("vip, follow-up")
+
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.Wrapping = fyne.TextWrapWord
+	notesEntry.SetMinRowsVisible(4)
+	notesEntry.SetText(result.Notes)
+	notesEntry.SetPlaceHolder("Notes for the team...")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Email", widget.NewLabel(result.Email)),
+		widget.NewFormItem("Name", widget.NewLabel(result.Name)),
+		widget.NewFormItem("LinkedIn", widget.NewLabel(result.LinkedInURL)),
+		widget.NewFormItem("Location", widget.NewLabel(result.Location)),
+		widget.NewFormItem("Connections", widget.NewLabel(result.Connections)),
+	}
+	if result.Headline != "" || result.Company != "" {
+		items = append(items,
+			widget.NewFormItem("Headline", widget.NewLabel(result.Headline)),
+			widget.NewFormItem("Company", widget.NewLabel(result.Company)))
+	}
+	items = append(items,
+		widget.NewFormItem("Found", widget.NewLabel(result.Timestamp.Local().Format("2006-01-02 15:04:05"))))
+	names := make([]string, 0, len(result.Fields))
+	for name := range result.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		items = append(items, widget.NewFormItem(name, widget.NewLabel(result.Fields[name])))
+	}
+	emails := strings.Split(result.Email, "; ")
+	claimLabel := widget.NewLabel(result.claimLabel())
+	var claimButton *widget.Button
+	claimButton = widget.NewButton("Claim", func() {
+		claim := claimButton.Text == "Claim"
+		claimButton.Disable()
+		go func() {
+			ok := rt.setResultClaim(emails, claim)
+			rt.gui.updateUI <- func() {
+				if ok {
+					result.ClaimedBy, result.ClaimedAt = "", time.Time{}
+					if claim {
+						result.ClaimedBy, result.ClaimedAt = rt.reviewerName(), time.Now()
+					}
+					claimLabel.SetText(result.claimLabel())
+					setClaimButton(claimButton, result, rt.reviewerName())
+				} else {
+					claimButton.Enable()
+				}
+			}
+		}()
+	})
+	setClaimButton(claimButton, result, rt.reviewerName())
+
+	items = append(items,
+		widget.NewFormItem("Claimed", container.NewBorder(nil, nil, nil, claimButton, claimLabel)),
+		widget.NewFormItem("Status", statusRadio),
+		widget.NewFormItem("Tags", tagsEntry),
+		widget.NewFormItem("Notes", notesEntry),
+	)
+
+	form := dialog.NewForm("Result Details", "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		status, ok := models.ParseResultStatus(statusRadio.Selected)
+		if !ok {
+			status = models.ResultStatusNew
+		}
+		go rt.saveResultEdits(emails, status, models.ParseTags(tagsEntry.Text), strings.TrimSpace(notesEntry.Text))
+	}, rt.gui.window)
+	form.Resize(fyne.NewSize(560, 0))
+	form.Show()
+}
+
+// setClaimButton shows Release on results the reviewer holds and Claim
+// otherwise; a live claim of another reviewer cannot be taken
+func setClaimButton(button *widget.Button, result CrawlerResult, reviewer string) {
+	if result.ClaimedBy != "" && result.ClaimedBy == reviewer {
+		button.SetText("Release")
+	} else {
+		button.SetText("Claim")
+	}
+	if result.claimedByOther(reviewer) {
+		button.Disable()
+	} else {
+		button.Enable()
+	}
+}
+
+// saveResultEdits stores the workflow and notes of emails and updates the
+// rows in place, so the active filter and sort are kept
+func (rt *ResultsTab) saveResultEdits(emails []string, status string, tags []string, notes string) {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		rt.gui.postStatus(StatusSourceResults, SeverityError, fmt.Sprintf("Saving result failed: %v", err))
+		return
+	}
+	defer emailStorage.CloseDB()
+
+	err := emailStorage.UpdateResultWorkflow(emails, status, tags)
+	if err == nil {
+		err = emailStorage.UpdateResultNotes(emails, notes)
+	}
+	if err != nil {
+		rt.gui.postStatus(StatusSourceResults, SeverityError, fmt.Sprintf("Saving result failed: %v", err))
+		return
+	}
+
+	rt.gui.postStatus(StatusSourceResults, SeveritySuccess,
+		fmt.Sprintf("%s marked %s", strings.Join(emails, ", "), models.ResultStatusLabel(status)))
+	rt.gui.updateUI <- func() {
+		key := strings.Join(emails, "; ")
+		for _, results := range [][]CrawlerResult{rt.results, rt.originalResults} {
+			for i := range results {
+				if results[i].Email == key {
+					results[i].WorkflowStatus = status
+					results[i].Tags = tags
+					results[i].Notes = notes
+				}
+			}
+		}
+		rt.updateShowOptions()
+		rt.updateSummary()
+		rt.resultsTable.Refresh()
+	}
+}
+
+// showOptions returns the choices of the Show filter, with a "Tag:" choice
+// for every tag in the results
+func (rt *ResultsTab) showOptions() []string {
+	options := []string{"All", "With LinkedIn", "Without LinkedIn", "Unclaimed", "Claimed by me"}
+	for _, status := range models.ResultStatuses {
+		options = append(options, "Status: "+models.ResultStatusLabel(status))
+	}
+	for _, bucket := range utils.ConnectionBuckets {
+		options = append(options, "Connections: "+bucket)
+	}
+	for _, region := range utils.Regions {
+		options = append(options, "Region: "+region)
+	}
+
+	results := rt.results
+	if rt.originalResults != nil {
+		results = rt.originalResults
+	}
+	seen := make(map[string]bool)
+	var tags []string
+	for _, result := range results {
+		for _, tag := range result.Tags {
+			if key := strings.ToLower(tag); !seen[key] {
+				seen[key] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i]) < strings.ToLower(tags[j]) })
+	for _, tag := range tags {
+		options = append(options, "Tag: "+tag)
+	}
+	return options
+}
+
+// updateShowOptions refreshes the tag choices of the Show filter
+func (rt *ResultsTab) updateShowOptions() {
+	if rt.statusFilter == nil {
+		return
+	}
+	rt.statusFilter.Options = rt.showOptions()
+	rt.statusFilter.Refresh()
+}
+
+// hasTag reports whether result is tagged tag (case-insensitive)
+func (r CrawlerResult) hasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
-	"bufio"
+	"encoding/csv"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -13,6 +15,10 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"linkedin-crawler/internal/models"
+	storageInternal "linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
 )
 
 // NewResultsTab creates a new results tab with auto-refresh functionality and deduplication
@@ -32,12 +38,20 @@ func NewResultsTab(gui *CrawlerGUI) *ResultsTab {
 	tab.filterEntry.SetPlaceHolder("Filter by email, name...")
 	tab.filterEntry.OnChanged = tab.applyFilter
 
+	// Tên reviewer khi claim kết quả, nhớ qua các lần mở
+	tab.reviewerEntry = widget.NewEntry()
+	tab.reviewerEntry.SetPlaceHolder("Your name")
+	tab.reviewerEntry.SetText(gui.app.Preferences().StringWithFallback("reviewer_name", defaultReviewerName()))
+	tab.reviewerEntry.OnChanged = func(name string) {
+		gui.app.Preferences().SetString("reviewer_name", strings.TrimSpace(name))
+	}
+
 	// Auto-refresh toggle
-	tab.autoRefreshCheck = widget.NewCheck("Auto-refresh (5s)", func(checked bool) {
+	tab.autoRefreshCheck = widget.NewCheck("Auto-refresh", func(checked bool) {
 		tab.autoRefresh = checked
 		if checked {
 			tab.startAutoRefresh()
-			tab.gui.updateStatus("Auto-refresh enabled (5s)")
+			tab.gui.updateStatus(fmt.Sprintf("Auto-refresh enabled (%v)", tab.gui.refreshScheduler.Intervals().Results))
 		} else {
 			tab.stopAutoRefresh()
 			tab.gui.updateStatus("Auto-refresh disabled")
@@ -46,6 +60,12 @@ func NewResultsTab(gui *CrawlerGUI) *ResultsTab {
 	tab.autoRefreshCheck.SetChecked(true) // Default enabled
 	tab.autoRefresh = true
 
+	// Merged view toggle
+	tab.mergeCheck = widget.NewCheck("Merge by LinkedIn URL", func(checked bool) {
+		tab.mergeByURL = checked
+		tab.RefreshResults()
+	})
+
 	// Initialize table
 	tab.setupResultsTable()
 
@@ -61,25 +81,32 @@ func NewResultsTab(gui *CrawlerGUI) *ResultsTab {
 // CreateContent creates the results tab content
 func (rt *ResultsTab) CreateContent() fyne.CanvasObject {
 	// Controls section
-	sortSelect := widget.NewSelect([]string{"Timestamp", "Email", "Name"}, func(value string) {
+	sortSelect := widget.NewSelect([]string{"Timestamp", "Email", "Name", "Connections", "Country"}, func(value string) {
 		rt.sortResults(value)
 	})
 	sortSelect.SetSelected("Timestamp")
 
-	showSelect := widget.NewSelect([]string{"All", "With LinkedIn", "Without LinkedIn"}, func(value string) {
+	showSelect := widget.NewSelect(rt.showOptions(), func(value string) {
 		rt.filterByStatus(value)
 	})
 	showSelect.SetSelected("All")
+	rt.statusFilter = showSelect
 
 	// Control buttons row
 	controlsRow1 := container.NewHBox(
 		rt.refreshBtn,
 		rt.exportBtn,
+		widget.NewButtonWithIcon("Export New", theme.DocumentSaveIcon(), rt.ExportNewResults),
+		widget.NewCheck("Export notes", func(checked bool) { rt.exportNotes = checked }),
 		rt.clearBtn,
 		widget.NewSeparator(),
 		rt.autoRefreshCheck,
 		widget.NewSeparator(),
 		widget.NewButton("Remove Duplicates", rt.RemoveDuplicates), // NEW: Remove duplicates button
+		rt.mergeCheck,
+		widget.NewButton("Domain Report", rt.ShowDomainReport),
+		widget.NewButton("Session Report", rt.ShowSessionReport),
+		widget.NewButtonWithIcon("Open Run Log", theme.FileTextIcon(), rt.ShowRunLogs),
 	)
 
 	// Filter and sort row
@@ -92,6 +119,9 @@ func (rt *ResultsTab) CreateContent() fyne.CanvasObject {
 		widget.NewSeparator(),
 		widget.NewLabel("Show:"),
 		showSelect,
+		widget.NewSeparator(),
+		widget.NewLabel("Reviewer:"),
+		rt.reviewerEntry,
 	)
 
 	// Combined controls
@@ -118,48 +148,25 @@ func (rt *ResultsTab) CreateContent() fyne.CanvasObject {
 	return content
 }
 
-// startAutoRefresh starts the auto-refresh timer
+// startAutoRefresh starts the auto-refresh on the results interval
 func (rt *ResultsTab) startAutoRefresh() {
-	if rt.refreshTicker != nil {
-		rt.refreshTicker.Stop()
-	}
-
-	rt.refreshTicker = time.NewTicker(5 * time.Second)
-	go func() {
-		defer func() {
-			if rt.refreshTicker != nil {
-				rt.refreshTicker.Stop()
-			}
-		}()
-
-		for {
-			select {
-			case <-rt.refreshTicker.C:
-				if rt.autoRefresh {
-					rt.gui.updateUI <- func() {
-						rt.RefreshResults()
-					}
-				}
-			case <-rt.gui.ctx.Done():
-				return
-			}
+	rt.gui.refreshScheduler.Schedule("results", RefreshResults, func() {
+		if rt.autoRefresh {
+			rt.RefreshResults()
 		}
-	}()
+	})
 }
 
-// stopAutoRefresh stops the auto-refresh timer
+// stopAutoRefresh stops the auto-refresh
 func (rt *ResultsTab) stopAutoRefresh() {
-	if rt.refreshTicker != nil {
-		rt.refreshTicker.Stop()
-		rt.refreshTicker = nil
-	}
+	rt.gui.refreshScheduler.Cancel("results")
 }
 
 // setupResultsTable initializes the results table
 func (rt *ResultsTab) setupResultsTable() {
 	rt.resultsTable = widget.NewTable(
 		func() (int, int) {
-			return len(rt.results) + 1, 6 // +1 for header, 6 columns
+			return len(rt.results) + 1, 12 // +1 for header, 12 columns
 		},
 		func() fyne.CanvasObject {
 			label := widget.NewLabel("Template")
@@ -170,7 +177,7 @@ func (rt *ResultsTab) setupResultsTable() {
 			label := obj.(*widget.Label)
 
 			if id.Row == 0 {
-				headers := []string{"Email", "Name", "LinkedIn URL", "Location", "Connections", "Status"}
+				headers := []string{"Email", "Name", "LinkedIn URL", "Location", "Country", "Connections", "Status", "Source", "Workflow", "Tags", "Notes", "Claimed By"}
 				if id.Col < len(headers) {
 					label.SetText(headers[id.Col])
 					label.TextStyle.Bold = true
@@ -201,10 +208,13 @@ func (rt *ResultsTab) setupResultsTable() {
 				case 3: // Location
 					label.SetText(result.Location)
 					label.Importance = widget.MediumImportance
-				case 4: // Connections
+				case 4: // Country
+					label.SetText(result.Country)
+					label.Importance = widget.MediumImportance
+				case 5: // Connections
 					label.SetText(result.Connections)
 					label.Importance = widget.MediumImportance
-				case 5: // Status
+				case 6: // Status
 					label.SetText(result.Status)
 					switch result.Status {
 					case "Found":
@@ -216,18 +226,58 @@ func (rt *ResultsTab) setupResultsTable() {
 					default:
 						label.Importance = widget.MediumImportance
 					}
+				case 7: // Source
+					label.SetText(result.Source)
+					label.Importance = widget.LowImportance
+				case 8: // Workflow
+					label.SetText(models.ResultStatusLabel(result.WorkflowStatus))
+					switch result.WorkflowStatus {
+					case models.ResultStatusContacted:
+						label.Importance = widget.SuccessImportance
+					case models.ResultStatusIgnored:
+						label.Importance = widget.LowImportance
+					default:
+						label.Importance = widget.HighImportance
+					}
+				case 9: // Tags
+					label.SetText(utils.FormatTags(result.Tags))
+					label.Importance = widget.MediumImportance
+				case 10: // Notes (dòng đầu)
+					label.SetText(strings.SplitN(result.Notes, "\n", 2)[0])
+					label.Importance = widget.LowImportance
+				case 11: // Claimed By
+					label.SetText(result.ClaimedBy)
+					if result.claimedByOther(rt.reviewerName()) {
+						label.Importance = widget.WarningImportance
+					} else {
+						label.Importance = widget.MediumImportance
+					}
 				}
 			}
 		},
 	)
 
 	// Set column widths
-	rt.resultsTable.SetColumnWidth(0, 200) // Email
-	rt.resultsTable.SetColumnWidth(1, 150) // Name
-	rt.resultsTable.SetColumnWidth(2, 250) // LinkedIn URL
-	rt.resultsTable.SetColumnWidth(3, 150) // Location
-	rt.resultsTable.SetColumnWidth(4, 100) // Connections
-	rt.resultsTable.SetColumnWidth(5, 100) // Status
+	rt.resultsTable.SetColumnWidth(0, 200)  // Email
+	rt.resultsTable.SetColumnWidth(1, 150)  // Name
+	rt.resultsTable.SetColumnWidth(2, 250)  // LinkedIn URL
+	rt.resultsTable.SetColumnWidth(3, 150)  // Location
+	rt.resultsTable.SetColumnWidth(4, 120)  // Country
+	rt.resultsTable.SetColumnWidth(5, 100)  // Connections
+	rt.resultsTable.SetColumnWidth(6, 100)  // Status
+	rt.resultsTable.SetColumnWidth(7, 70)   // Source
+	rt.resultsTable.SetColumnWidth(8, 90)   // Workflow
+	rt.resultsTable.SetColumnWidth(9, 150)  // Tags
+	rt.resultsTable.SetColumnWidth(10, 200) // Notes
+	rt.resultsTable.SetColumnWidth(11, 120) // Claimed By
+
+	// Bấm vào dòng để xem chi tiết, sửa workflow status, tags và notes
+	rt.resultsTable.OnSelected = func(id widget.TableCellID) {
+		rt.resultsTable.UnselectAll()
+		if id.Row > 0 && id.Row-1 < len(rt.results) {
+			rt.showResultDetails(rt.results[id.Row-1])
+		}
+	}
 }
 
 // RefreshResults refreshes the results from hit.txt file with DEDUPLICATION
@@ -238,51 +288,49 @@ func (rt *ResultsTab) RefreshResults() {
 	resultsMap := make(map[string]CrawlerResult) // key = email (lowercase)
 	duplicatesCount := 0
 
-	file, err := os.Open("hit.txt")
+	hits, err := rt.loadResults()
 	if err != nil {
 		if !rt.autoRefresh {
-			rt.gui.updateStatus("No results file found")
+			rt.gui.postStatus(StatusSourceResults, SeverityWarning, fmt.Sprintf("No results available: %v", err))
 		}
 		rt.updateSummary()
 		rt.resultsTable.Refresh()
 		return
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	totalLines := 0
+	for _, hit := range hits {
+		emailKey := strings.ToLower(hit.Email) // Normalize email for deduplication
+
+		result := CrawlerResult{
+			Email:       hit.Email,
+			Name:        hit.Name,
+			LinkedInURL: hit.LinkedInURL,
+			Location:    hit.Location,
+			Connections: hit.Connections,
+			Headline:    hit.Headline,
+			Company:     hit.Company,
+			Status:      "Found",
+			Source:      "email",
+			Timestamp:   hit.Timestamp,
+			Fields:      hit.Fields,
+
+			WorkflowStatus: hit.WorkflowStatus,
+			Tags:           hit.Tags,
+			Notes:          hit.Notes,
+			ClaimedBy:      hit.ClaimedBy,
+			ClaimedAt:      hit.ClaimedAt,
+		}
+		if hit.Source != "" {
+			result.Source = hit.Source
+		}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
+		// Check for duplicates
+		if _, exists := resultsMap[emailKey]; exists {
+			duplicatesCount++
 			continue
 		}
 
-		totalLines++
-		parts := strings.Split(line, "|")
-		if len(parts) >= 5 {
-			email := strings.TrimSpace(parts[0])
-			emailKey := strings.ToLower(email) // Normalize email for deduplication
-
-			result := CrawlerResult{
-				Email:       email,
-				Name:        strings.TrimSpace(parts[1]),
-				LinkedInURL: strings.TrimSpace(parts[2]),
-				Location:    strings.TrimSpace(parts[3]),
-				Connections: strings.TrimSpace(parts[4]),
-				Status:      "Found",
-				Timestamp:   time.Now(),
-			}
-
-			// Check for duplicates
-			if _, exists := resultsMap[emailKey]; exists {
-				duplicatesCount++
-				// Keep the newer/better result (can add logic here)
-				continue
-			}
-
-			resultsMap[emailKey] = result
-		}
+		resultsMap[emailKey] = result
 	}
 
 	// Convert map to slice
@@ -291,11 +339,20 @@ func (rt *ResultsTab) RefreshResults() {
 		rt.results = append(rt.results, result)
 	}
 
+	if rt.mergeByURL {
+		rt.results = mergeResultsByURL(rt.results)
+	}
+	for i := range rt.results {
+		rt.results[i].ConnectionsCount = utils.ParseConnections(rt.results[i].Connections)
+		rt.results[i].Country, rt.results[i].Region = utils.NormalizeLocation(rt.results[i].Location)
+	}
+
 	// Sort by timestamp (newest first)
 	sort.Slice(rt.results, func(i, j int) bool {
 		return rt.results[i].Timestamp.After(rt.results[j].Timestamp)
 	})
 
+	rt.updateShowOptions()
 	rt.updateSummary()
 	rt.resultsTable.Refresh()
 
@@ -308,7 +365,7 @@ func (rt *ResultsTab) RefreshResults() {
 		if duplicatesCount > 0 {
 			statusMsg += fmt.Sprintf(" | Removed %d duplicates", duplicatesCount)
 		}
-		rt.gui.updateStatus(statusMsg)
+		rt.gui.postStatus(StatusSourceResults, SeveritySuccess, statusMsg)
 
 		// Log to emails tab if available (removed controlTab reference)
 		if rt.gui.emailsTab != nil {
@@ -319,7 +376,7 @@ func (rt *ResultsTab) RefreshResults() {
 		if duplicatesCount > 0 {
 			statusMsg += fmt.Sprintf(" | Removed %d duplicates", duplicatesCount)
 		}
-		rt.gui.updateStatus(statusMsg)
+		rt.gui.postStatus(StatusSourceResults, SeverityInfo, statusMsg)
 	}
 
 	// Log duplicates info if found
@@ -330,6 +387,53 @@ func (rt *ResultsTab) RefreshResults() {
 	}
 }
 
+// loadResults reads results from the database. The hit files are only used
+// once to backfill a database that has no results yet.
+func (rt *ResultsTab) loadResults() ([]utils.HitResult, error) {
+	cfg := rt.gui.configTab.ResolvedConfig()
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer emailStorage.CloseDB()
+
+	count, err := emailStorage.CountResults()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		if hits, err := utils.ReadAllHitResults(cfg.OutputFilePath); err == nil && len(hits) > 0 {
+			if added, err := emailStorage.ImportResults(hits); err == nil && added > 0 {
+				fmt.Printf("📥 Imported %d results from %s into the database\n", added, cfg.OutputFilePath)
+			}
+		}
+	}
+
+	results, err := emailStorage.GetResults()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := emailStorage.GetAllInputFields()
+	if err != nil {
+		fmt.Printf("⚠️ Could not load custom fields: %v\n", err)
+	}
+	utils.AttachInputFields(results, fields)
+	return results, nil
+}
+
+// annotateHits adds the workflow status, tags and custom input fields to
+// hits read from the hit file for "Export New"; without the database the
+// export only has the hit file columns
+func (rt *ResultsTab) annotateHits(entries []utils.HitResult) {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		return
+	}
+	defer emailStorage.CloseDB()
+
+	emailStorage.AnnotateHits(entries)
+}
+
 // RemoveDuplicates manually removes duplicates from current results
 func (rt *ResultsTab) RemoveDuplicates() {
 	if len(rt.results) == 0 {
@@ -395,27 +499,48 @@ func (rt *ResultsTab) ExportResults() {
 		}
 		defer writer.Close()
 
-		var lines []string
-		lines = append(lines, "Email,Name,LinkedIn URL,Location,Connections,Status,Timestamp")
-
 		// Use map để ensure no duplicates in export
 		exportMap := make(map[string]CrawlerResult)
+		var hits []utils.HitResult
+		withDetails := false
 		for _, result := range rt.results {
 			emailKey := strings.ToLower(strings.TrimSpace(result.Email))
 			exportMap[emailKey] = result
+			hits = append(hits, utils.HitResult{Fields: result.Fields})
+			withDetails = withDetails || result.Headline != "" || result.Company != ""
 		}
+		fieldNames := utils.InputFieldNames(hits)
 
-		for _, result := range exportMap {
-			line := fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s",
-				result.Email, result.Name, result.LinkedInURL,
-				result.Location, result.Connections, result.Status,
-				result.Timestamp.Format("2006-01-02 15:04:05"))
-			lines = append(lines, line)
+		header := []string{"Email", "Name", "LinkedIn URL", "Location", "Connections"}
+		if withDetails {
+			header = append(header, "Headline", "Company")
+		}
+		header = append(header, "Status", "Source", "Timestamp", "Workflow", "Tags")
+		if rt.exportNotes {
+			header = append(header, "Notes")
 		}
 
-		content := strings.Join(lines, "\n")
-		_, err = writer.Write([]byte(content))
-		if err != nil {
+		// csv.Writer quote các giá trị có dấu phẩy hoặc xuống dòng (notes)
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.Write(append(header, fieldNames...))
+		for _, result := range exportMap {
+			record := []string{result.Email, result.Name, result.LinkedInURL, result.Location, result.Connections}
+			if withDetails {
+				record = append(record, result.Headline, result.Company)
+			}
+			record = append(record, result.Status, result.Source,
+				result.Timestamp.Format("2006-01-02 15:04:05"),
+				models.ResultStatusLabel(result.WorkflowStatus), utils.FormatTags(result.Tags))
+			if rt.exportNotes {
+				record = append(record, result.Notes)
+			}
+			for _, name := range fieldNames {
+				record = append(record, result.Fields[name])
+			}
+			csvWriter.Write(record)
+		}
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
 			dialog.ShowError(err, rt.gui.window)
 			return
 		}
@@ -429,6 +554,276 @@ func (rt *ResultsTab) ExportResults() {
 	}, rt.gui.window)
 }
 
+// ExportNewResults exports only hits found since the previous "Export New"
+func (rt *ResultsTab) ExportNewResults() {
+	hitFile := rt.gui.configTab.ResolvedConfig().OutputFilePath
+
+	entries, err := utils.ReadAllHitResults(hitFile)
+	if err != nil {
+		dialog.ShowInformation("No Data", "No results file found", rt.gui.window)
+		return
+	}
+	state, err := utils.LoadExportState(hitFile)
+	if err != nil {
+		dialog.ShowError(err, rt.gui.window)
+		return
+	}
+
+	fresh := state.NewHits(entries)
+	if len(fresh) == 0 {
+		message := "No new results since the last export."
+		if !state.LastExportAt.IsZero() {
+			message = fmt.Sprintf("No new results since %s.", state.LastExportAt.Format("2006-01-02 15:04:05"))
+		}
+		dialog.ShowConfirm("Export New", message+"\n\nReset the export watermark so the next export includes all results?",
+			func(reset bool) {
+				if !reset {
+					return
+				}
+				if err := state.Reset(); err != nil {
+					dialog.ShowError(err, rt.gui.window)
+					return
+				}
+				rt.gui.updateStatus("Export watermark reset")
+			}, rt.gui.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		rt.annotateHits(fresh)
+		if !rt.exportNotes {
+			utils.DropNotes(fresh)
+		}
+		now := time.Now()
+		data, err := utils.EncodeHits(fresh, utils.ExportFormatFromPath(writer.URI().Name()), now)
+		if err != nil {
+			dialog.ShowError(err, rt.gui.window)
+			return
+		}
+		if _, err := writer.Write(data); err != nil {
+			dialog.ShowError(err, rt.gui.window)
+			return
+		}
+		if err := state.MarkExported(fresh, now); err != nil {
+			dialog.ShowError(err, rt.gui.window)
+			return
+		}
+
+		rt.gui.updateStatus(fmt.Sprintf("Exported %d new results to %s", len(fresh), writer.URI().Name()))
+	}, rt.gui.window)
+	saveDialog.SetFileName(fmt.Sprintf("new-hits-%s.csv", time.Now().Format("20060102-150405")))
+	saveDialog.Show()
+}
+
+// ShowDomainReport shows hit rates per email domain with CSV/markdown export
+func (rt *ResultsTab) ShowDomainReport() {
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to open database: %w", err), rt.gui.window)
+		return
+	}
+	stats, err := emailStorage.GetDomainStats()
+	emailStorage.CloseDB()
+	if err != nil {
+		dialog.ShowError(err, rt.gui.window)
+		return
+	}
+
+	if len(stats) == 0 {
+		dialog.ShowInformation("Domain Report", "No email data in database", rt.gui.window)
+		return
+	}
+	if hits, err := utils.ReadAllHitResults(rt.gui.configTab.ResolvedConfig().OutputFilePath); err == nil {
+		utils.ApplyHitProfileStats(stats, hits)
+	}
+
+	headers := []string{"Domain", "Total", "Success", "Failed", "Pending", "Hits", "Hit rate", "Top country", "Avg conn."}
+	table := widget.NewTable(
+		func() (int, int) { return len(stats) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(headers[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			d := stats[id.Row-1]
+			values := []string{
+				d.Domain,
+				fmt.Sprintf("%d", d.Total),
+				fmt.Sprintf("%d", d.Success),
+				fmt.Sprintf("%d", d.Failed),
+				fmt.Sprintf("%d", d.Pending),
+				fmt.Sprintf("%d", d.Hits),
+				fmt.Sprintf("%.1f%%", d.HitRate()),
+				d.TopCountry,
+				fmt.Sprintf("%.0f", d.AvgConnections),
+			}
+			label.SetText(values[id.Col])
+		},
+	)
+	table.SetColumnWidth(0, 220)
+	for col := 1; col < len(headers); col++ {
+		table.SetColumnWidth(col, 80)
+	}
+
+	exportCSV := widget.NewButton("Export CSV", func() {
+		rt.saveReport([]byte(utils.FormatDomainReportCSV(stats)), "domain-report.csv", "Domain report")
+	})
+	exportMD := widget.NewButton("Export Markdown", func() {
+		rt.saveReport([]byte(utils.FormatDomainReportMarkdown(stats)), "domain-report.md", "Domain report")
+	})
+
+	content := container.NewBorder(
+		nil, container.NewHBox(exportCSV, exportMD), nil, nil,
+		container.NewScroll(table),
+	)
+
+	d := dialog.NewCustom(fmt.Sprintf("Domain Report (%d domains)", len(stats)), "Close", content, rt.gui.window)
+	d.Resize(fyne.NewSize(950, 500))
+	d.Show()
+}
+
+// ShowSessionReport summarises the current or most recent run and offers
+// HTML and PDF export for sending to clients
+func (rt *ResultsTab) ShowSessionReport() {
+	cfg := rt.gui.configTab.ResolvedConfig()
+	emailStorage := storageInternal.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to open database: %w", err), rt.gui.window)
+		return
+	}
+	report, err := emailStorage.GetSessionReport(rt.gui.crawlController.RunID(), utils.SessionReportTopDomains, cfg.OutputFilePath)
+	if err != nil && rt.gui.crawlController.RunID() != "" {
+		// Run đang chạy chưa được ghi vào bảng runs: dùng run gần nhất
+		report, err = emailStorage.GetSessionReport("", utils.SessionReportTopDomains, cfg.OutputFilePath)
+	}
+	emailStorage.CloseDB()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to build session report: %w", err), rt.gui.window)
+		return
+	}
+	rt.gui.licenseWrapper.FillSessionReport(&report)
+
+	summary := widget.NewLabel(strings.Join(utils.FormatSessionReportText(report), "\n"))
+	summary.TextStyle = fyne.TextStyle{Monospace: true}
+
+	exportHTML := widget.NewButtonWithIcon("Export HTML", theme.DocumentSaveIcon(), func() {
+		html, err := utils.FormatSessionReportHTML(report)
+		if err != nil {
+			dialog.ShowError(err, rt.gui.window)
+			return
+		}
+		rt.saveReport([]byte(html), utils.SessionReportFileName(report, "html"), "Session report")
+	})
+	exportPDF := widget.NewButtonWithIcon("Export PDF", theme.DocumentSaveIcon(), func() {
+		rt.saveReport(utils.FormatSessionReportPDF(report), utils.SessionReportFileName(report, "pdf"), "Session report")
+	})
+
+	content := container.NewBorder(nil, container.NewHBox(exportHTML, exportPDF), nil, nil, container.NewScroll(summary))
+	d := dialog.NewCustom(fmt.Sprintf("Session Report - %s", report.StartedAt.Format("2006-01-02 15:04")), "Close", content, rt.gui.window)
+	d.Resize(fyne.NewSize(800, 600))
+	d.Show()
+}
+
+// ShowRunLogs lists the per-run log files and shows the selected one,
+// starting with the current or most recent run
+func (rt *ResultsTab) ShowRunLogs() {
+	runLogs, err := utils.ListRunLogs(rt.gui.configTab.ResolvedConfig().LogFilePath)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to list run logs: %w", err), rt.gui.window)
+		return
+	}
+	if len(runLogs) == 0 {
+		dialog.ShowInformation("Run Logs", "No run logs yet. Each crawl writes logs/run-<id>.log next to the crawler log.", rt.gui.window)
+		return
+	}
+
+	logView := widget.NewMultiLineEntry()
+	logView.Wrapping = fyne.TextWrapOff
+	pathLabel := widget.NewLabel("")
+	pathLabel.Truncation = fyne.TextTruncateEllipsis
+
+	var selected utils.RunLog
+	showLog := func(runLog utils.RunLog) {
+		selected = runLog
+		pathLabel.SetText(runLog.Path)
+		content, err := os.ReadFile(runLog.Path)
+		if err != nil {
+			logView.SetText(fmt.Sprintf("❌ %v", err))
+			return
+		}
+		logView.SetText(string(content))
+		logView.CursorRow = strings.Count(logView.Text, "\n")
+		logView.Refresh()
+	}
+
+	list := widget.NewList(
+		func() int { return len(runLogs) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			runLog := runLogs[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s  (%d KB)", runLog.RunID, runLog.Size/1024))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		showLog(runLogs[id])
+	}
+
+	openBtn := widget.NewButtonWithIcon("Open in Editor", theme.FileTextIcon(), func() {
+		absPath, err := filepath.Abs(selected.Path)
+		if err != nil {
+			dialog.ShowError(err, rt.gui.window)
+			return
+		}
+		if err := rt.gui.app.OpenURL(&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}); err != nil {
+			dialog.ShowError(fmt.Errorf("không mở được file log: %v", err), rt.gui.window)
+		}
+	})
+
+	split := container.NewHSplit(list, container.NewBorder(nil, container.NewBorder(nil, nil, nil, openBtn, pathLabel), nil, nil, logView))
+	split.Offset = 0.25
+
+	// Mặc định chọn run hiện tại/gần nhất của GUI, nếu không thì file mới nhất
+	initial := 0
+	for i, runLog := range runLogs {
+		if runLog.RunID == rt.gui.crawlController.RunID() {
+			initial = i
+			break
+		}
+	}
+	list.Select(initial)
+
+	d := dialog.NewCustom(fmt.Sprintf("Run Logs (%d runs)", len(runLogs)), "Close", split, rt.gui.window)
+	d.Resize(fyne.NewSize(1000, 600))
+	d.Show()
+}
+
+// saveReport writes a rendered report through a save dialog
+func (rt *ResultsTab) saveReport(content []byte, defaultName, name string) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if _, err := writer.Write(content); err != nil {
+			dialog.ShowError(err, rt.gui.window)
+			return
+		}
+		rt.gui.updateStatus(fmt.Sprintf("%s exported to %s", name, writer.URI().Name()))
+	}, rt.gui.window)
+	saveDialog.SetFileName(defaultName)
+	saveDialog.Show()
+}
+
 // ClearResults clears all results
 func (rt *ResultsTab) ClearResults() {
 	if len(rt.results) == 0 {
@@ -456,7 +851,9 @@ func (rt *ResultsTab) updateSummary() {
 
 	// Count unique emails and detect potential duplicates
 	emailMap := make(map[string]int)
+	workflowCounts := make(map[string]int)
 	for _, result := range rt.results {
+		workflowCounts[models.ResultStatusLabel(result.WorkflowStatus)]++
 		emailKey := strings.ToLower(strings.TrimSpace(result.Email))
 		emailMap[emailKey]++
 
@@ -481,10 +878,8 @@ func (rt *ResultsTab) updateSummary() {
 	// Get additional stats from crawler if running
 	additionalStats := ""
 	if rt.gui.emailsTab != nil && rt.gui.emailsTab.autoCrawler != nil {
-		emailStorage, _, _ := rt.gui.emailsTab.autoCrawler.GetStorageServices()
-		if emailStorage != nil {
-			if stats, err := emailStorage.GetEmailStats(); err == nil {
-				additionalStats = fmt.Sprintf(`
+		stats := rt.gui.emailsTab.autoCrawler.Stats().Snapshot().EmailStats()
+		additionalStats = fmt.Sprintf(`
 **Current Processing:**
 ⏳ **Pending:** %d emails
 ✅ **Success:** %d emails  
@@ -495,14 +890,12 @@ func (rt *ResultsTab) updateSummary() {
 **Processing Rate:**
 📈 **Success Rate:** %.1f%%
 `, stats["pending"], stats["success"], stats["failed"], stats["has_info"], stats["no_info"],
-					func() float64 {
-						if stats["success"]+stats["failed"] > 0 {
-							return float64(stats["success"]) * 100 / float64(stats["success"]+stats["failed"])
-						}
-						return 0.0
-					}())
-			}
-		}
+			func() float64 {
+				if stats["success"]+stats["failed"] > 0 {
+					return float64(stats["success"]) * 100 / float64(stats["success"]+stats["failed"])
+				}
+				return 0.0
+			}())
 	}
 
 	refreshStatus := ""
@@ -530,12 +923,15 @@ func (rt *ResultsTab) updateSummary() {
 📭 **Without LinkedIn:** %d profiles (%.1f%%)
 🔍 **Unique Emails:** %d
 
+📋 **Workflow:** %d new, %d contacted, %d ignored
+
 📈 **Find Rate:** %.1f%%
 📅 **Last Updated:** %s
 %s
 %s
 %s
 `, total, withLinkedIn, percentage, total-withLinkedIn, 100-percentage, len(emailMap),
+		workflowCounts["New"], workflowCounts["Contacted"], workflowCounts["Ignored"],
 		percentage, time.Now().Format("15:04:05"), refreshStatus, duplicateInfo, additionalStats)
 
 	rt.summaryCard.SetContent(widget.NewRichTextFromMarkdown(summaryText))
@@ -557,7 +953,10 @@ func (rt *ResultsTab) applyFilter(text string) {
 		if strings.Contains(strings.ToLower(r.Email), text) ||
 			strings.Contains(strings.ToLower(r.Name), text) ||
 			strings.Contains(strings.ToLower(r.Location), text) ||
-			strings.Contains(strings.ToLower(r.LinkedInURL), text) {
+			strings.Contains(strings.ToLower(r.Country), text) ||
+			strings.Contains(strings.ToLower(r.LinkedInURL), text) ||
+			strings.Contains(strings.ToLower(utils.FormatTags(r.Tags)), text) ||
+			strings.Contains(strings.ToLower(r.Notes), text) {
 			filtered = append(filtered, r)
 		}
 	}
@@ -582,6 +981,18 @@ func (rt *ResultsTab) sortResults(field string) {
 		sort.Slice(rt.results, func(i, j int) bool {
 			return rt.results[i].Timestamp.After(rt.results[j].Timestamp) // Newest first
 		})
+	case "Connections":
+		sort.SliceStable(rt.results, func(i, j int) bool {
+			return rt.results[i].ConnectionsCount > rt.results[j].ConnectionsCount // Nhiều nhất trước, không rõ ở cuối
+		})
+	case "Country":
+		sort.SliceStable(rt.results, func(i, j int) bool {
+			a, b := rt.results[i].Country, rt.results[j].Country
+			if (a == "") != (b == "") {
+				return b == "" // Không rõ quốc gia ở cuối
+			}
+			return a < b
+		})
 	}
 	rt.resultsTable.Refresh()
 	rt.gui.updateStatus(fmt.Sprintf("Sorted by %s", field))
@@ -610,7 +1021,55 @@ func (rt *ResultsTab) filterByStatus(status string) {
 				filtered = append(filtered, r)
 			}
 		}
-	default: // "All"
+	case "Unclaimed":
+		reviewer := rt.reviewerName()
+		for _, r := range sourceResults {
+			if r.ClaimedBy == "" || (r.ClaimedBy != reviewer && !r.claimedByOther(reviewer)) {
+				filtered = append(filtered, r)
+			}
+		}
+	case "Claimed by me":
+		reviewer := rt.reviewerName()
+		for _, r := range sourceResults {
+			if r.ClaimedBy != "" && r.ClaimedBy == reviewer {
+				filtered = append(filtered, r)
+			}
+		}
+	default:
+		if bucket, ok := strings.CutPrefix(status, "Connections: "); ok {
+			for _, r := range sourceResults {
+				if utils.ConnectionBucket(r.ConnectionsCount) == bucket {
+					filtered = append(filtered, r)
+				}
+			}
+			break
+		}
+		if label, ok := strings.CutPrefix(status, "Status: "); ok {
+			for _, r := range sourceResults {
+				if models.ResultStatusLabel(r.WorkflowStatus) == label {
+					filtered = append(filtered, r)
+				}
+			}
+			break
+		}
+		if tag, ok := strings.CutPrefix(status, "Tag: "); ok {
+			for _, r := range sourceResults {
+				if r.hasTag(tag) {
+					filtered = append(filtered, r)
+				}
+			}
+			break
+		}
+		if region, ok := strings.CutPrefix(status, "Region: "); ok {
+			for _, r := range sourceResults {
+				if r.Region == region {
+					filtered = append(filtered, r)
+				}
+			}
+			break
+		}
+
+		// "All"
 		filtered = make([]CrawlerResult, len(sourceResults))
 		copy(filtered, sourceResults)
 		rt.originalResults = nil // Clear saved results
@@ -697,3 +1156,60 @@ func (rt *ResultsTab) ForceRefresh() {
 func (rt *ResultsTab) Cleanup() {
 	rt.stopAutoRefresh()
 }
+
+// mergeResultsByURL groups results pointing to the same canonical LinkedIn URL into one row
+func mergeResultsByURL(results []CrawlerResult) []CrawlerResult {
+	entries := make([]utils.HitResult, 0, len(results))
+	timestamps := make(map[string]time.Time, len(results))
+	workflows := make(map[string]CrawlerResult, len(results))
+	for _, r := range results {
+		entries = append(entries, utils.HitResult{
+			Email:       r.Email,
+			Name:        r.Name,
+			LinkedInURL: r.LinkedInURL,
+			Location:    r.Location,
+			Connections: r.Connections,
+			Source:      r.Source,
+		})
+		timestamps[strings.ToLower(r.Email)] = r.Timestamp
+		workflows[strings.ToLower(r.Email)] = r
+	}
+
+	groups := utils.GroupHitsByLinkedInURL(entries)
+	merged := make([]CrawlerResult, 0, len(groups))
+	for _, g := range groups {
+		status := "Found"
+		if len(g.Emails) > 1 {
+			status = fmt.Sprintf("Merged (%d)", len(g.Emails))
+		}
+
+		var latest time.Time
+		var tags []string
+		for _, email := range g.Emails {
+			if ts := timestamps[strings.ToLower(email)]; ts.After(latest) {
+				latest = ts
+			}
+			tags = append(tags, workflows[strings.ToLower(email)].Tags...)
+		}
+
+		merged = append(merged, CrawlerResult{
+			Email:       strings.Join(g.Emails, "; "),
+			Name:        g.Name,
+			LinkedInURL: g.CanonicalURL,
+			Location:    g.Location,
+			Connections: g.Connections,
+			Status:      status,
+			Source:      strings.Join(g.Sources, "; "),
+			Timestamp:   latest,
+
+			// Status và claim của email đầu tiên, tags của tất cả emails
+			WorkflowStatus: workflows[strings.ToLower(g.Emails[0])].WorkflowStatus,
+			Tags:           models.ParseTags(strings.Join(tags, ",")),
+			Notes:          workflows[strings.ToLower(g.Emails[0])].Notes,
+			ClaimedBy:      workflows[strings.ToLower(g.Emails[0])].ClaimedBy,
+			ClaimedAt:      workflows[strings.ToLower(g.Emails[0])].ClaimedAt,
+		})
+	}
+
+	return merged
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// parseReviewerArgs reports whether the GUI was started with
+// --reviewer [results.db] and the database to review ("" = the configured one)
+func parseReviewerArgs(args []string) (bool, string) {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--reviewer="); ok {
+			return true, value
+		}
+		if arg == "--reviewer" {
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				return true, args[i+1]
+			}
+			return true, ""
+		}
+	}
+	return false, ""
+}
+
+// setupReviewerUI shows only the Results tab on an existing results
+// database: no accounts, tokens or crawl controls, no license check and no
+// instance lock, so results can be handed to teammates
+func (gui *CrawlerGUI) setupReviewerUI(dbPath string) {
+	if dbPath != "" {
+		if _, err := os.Stat(dbPath); err != nil {
+			errDialog := dialog.NewError(fmt.Errorf("results database not found: %w", err), gui.window)
+			errDialog.SetOnClosed(gui.app.Quit)
+			errDialog.Show()
+			return
+		}
+		gui.configTab.config.DBPath = dbPath
+	}
+	cfg := gui.configTab.ResolveConfig()
+
+	gui.window.SetTitle("LinkedIn Auto Crawler - Results Review")
+	gui.window.SetContent(container.NewBorder(nil, gui.statusBarContainer, nil, nil, gui.resultsTab.CreateContent()))
+	gui.unlockDatabase(cfg.DBPath, gui.resultsTab.RefreshResults)
+	gui.updateStatus(fmt.Sprintf("Reviewer mode - %s", cfg.DBPath))
+}
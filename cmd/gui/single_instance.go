@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"log"
+
+	"linkedin-crawler/internal/utils"
+)
+
+// parseMultiInstanceArg reports whether the GUI was started with
+// --multi-instance, which skips the single-instance check
+func parseMultiInstanceArg(args []string) bool {
+	for _, arg := range args {
+		if arg == "--multi-instance" {
+			return true
+		}
+	}
+	return false
+}
+
+// claimSingleInstance makes this GUI the one later launches hand over to. It
+// returns false when another GUI is running: that one was asked to show its
+// window and this one should quit. A broken socket only logs a warning.
+func (gui *CrawlerGUI) claimSingleInstance() bool {
+	path, err := utils.SingleInstancePath("gui")
+	if err == nil {
+		gui.singleInstance, err = utils.ListenSingleInstance(path, func() {
+			gui.updateUI <- gui.focusWindow
+		})
+	}
+	if errors.Is(err, utils.ErrAlreadyRunning) {
+		log.Printf("ℹ️ The GUI is already running, showing its window (use --multi-instance to start another one)")
+		return false
+	}
+	if err != nil {
+		log.Printf("⚠️ Single-instance check unavailable: %v", err)
+	}
+	return true
+}
+
+// focusWindow brings the window to the front when the GUI is launched again
+func (gui *CrawlerGUI) focusWindow() {
+	gui.window.Show()
+	gui.window.RequestFocus()
+	gui.updateStatus("Already running - the GUI was launched again")
+}
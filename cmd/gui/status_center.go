@@ -0,0 +1,280 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// StatusSeverity is the importance of a status message
+type StatusSeverity int
+
+const (
+	SeverityInfo StatusSeverity = iota
+	SeveritySuccess
+	SeverityWarning
+	SeverityError
+)
+
+// Icon returns the emoji shown before messages of this severity
+func (s StatusSeverity) Icon() string {
+	switch s {
+	case SeveritySuccess:
+		return "✅"
+	case SeverityWarning:
+		return "⚠️"
+	case SeverityError:
+		return "❌"
+	default:
+		return "ℹ️"
+	}
+}
+
+// Status sources: các message cùng source thay thế nhau trong hàng đợi
+const (
+	StatusSourceApp      = "app"
+	StatusSourceCrawler  = "crawler"
+	StatusSourceTokens   = "tokens"
+	StatusSourceResults  = "results"
+	StatusSourceLicense  = "license"
+	StatusSourceSchedule = "schedule"
+)
+
+const (
+	statusMinDisplay   = 2 * time.Second // Thời gian tối thiểu một message transient được hiển thị
+	statusHistoryLimit = 100             // Số message giữ lại cho popover
+)
+
+// StatusMessage is one message posted to the status bar
+type StatusMessage struct {
+	Source   string
+	Severity StatusSeverity
+	Text     string
+	Time     time.Time
+}
+
+// String formats the message for the status bar and history
+func (m StatusMessage) String() string {
+	return fmt.Sprintf("%s %s", m.Severity.Icon(), m.Text)
+}
+
+// StatusCenter owns the single status bar. Transient messages are queued and
+// each is shown for at least statusMinDisplay; a newer message from the same
+// source replaces a queued one. Errors are sticky: they stay until dismissed,
+// so periodic updates can't clobber them.
+type StatusCenter struct {
+	gui *CrawlerGUI
+
+	mutex     sync.Mutex
+	history   []StatusMessage // Cũ nhất trước
+	queue     []StatusMessage
+	current   *StatusMessage
+	sticky    *StatusMessage
+	unseen    int // Số message mới đến khi đang hiện lỗi sticky
+	shownAt   time.Time
+	advancing bool // Đã hẹn giờ chuyển sang message tiếp theo
+
+	label      *widget.Label
+	historyBtn *widget.Button
+	dismissBtn *widget.Button
+	popUp      *widget.PopUp
+}
+
+// NewStatusCenter creates the status center and its status bar widgets
+func NewStatusCenter(gui *CrawlerGUI) *StatusCenter {
+	sc := &StatusCenter{gui: gui}
+
+	sc.label = widget.NewLabel("Ready")
+	sc.label.Truncation = fyne.TextTruncateEllipsis
+	sc.historyBtn = widget.NewButtonWithIcon("", theme.HistoryIcon(), sc.ShowHistory)
+	sc.dismissBtn = widget.NewButtonWithIcon("", theme.CancelIcon(), sc.DismissSticky)
+	sc.dismissBtn.Hide()
+
+	return sc
+}
+
+// CreateContent returns the status bar
+func (sc *StatusCenter) CreateContent() fyne.CanvasObject {
+	return container.NewBorder(nil, nil, nil, container.NewHBox(sc.dismissBtn, sc.historyBtn), sc.label)
+}
+
+// Post adds a message to the status bar. It may be called from any goroutine.
+func (sc *StatusCenter) Post(source string, severity StatusSeverity, text string) {
+	msg := StatusMessage{Source: source, Severity: severity, Text: text, Time: time.Now()}
+
+	sc.mutex.Lock()
+	sc.history = append(sc.history, msg)
+	if len(sc.history) > statusHistoryLimit {
+		sc.history = sc.history[len(sc.history)-statusHistoryLimit:]
+	}
+
+	if severity == SeverityError {
+		sc.sticky = &msg
+		sc.unseen = 0
+		sc.mutex.Unlock()
+		sc.render()
+		return
+	}
+
+	if sc.sticky != nil {
+		sc.unseen++
+	}
+	replaced := false
+	for i := range sc.queue {
+		if sc.queue[i].Source == source {
+			sc.queue[i] = msg
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		sc.queue = append(sc.queue, msg)
+	}
+	sc.mutex.Unlock()
+
+	sc.advance()
+}
+
+// advance shows the next queued message once the current one has been
+// visible long enough, and schedules itself while messages are waiting
+func (sc *StatusCenter) advance() {
+	sc.mutex.Lock()
+	if len(sc.queue) == 0 || sc.advancing {
+		sc.mutex.Unlock()
+		return
+	}
+	if wait := statusMinDisplay - time.Since(sc.shownAt); sc.current != nil && wait > 0 {
+		sc.advancing = true
+		sc.mutex.Unlock()
+		time.AfterFunc(wait, func() {
+			sc.mutex.Lock()
+			sc.advancing = false
+			sc.mutex.Unlock()
+			sc.advance()
+		})
+		return
+	}
+
+	next := sc.queue[0]
+	sc.queue = sc.queue[1:]
+	sc.current = &next
+	sc.shownAt = time.Now()
+	sc.mutex.Unlock()
+
+	sc.render()
+	sc.advance()
+}
+
+// render updates the status bar widgets on the UI thread
+func (sc *StatusCenter) render() {
+	sc.mutex.Lock()
+	text := "Ready"
+	sticky := sc.sticky != nil
+	switch {
+	case sticky:
+		text = sc.sticky.String()
+		if sc.unseen > 0 {
+			text += fmt.Sprintf("  (+%d newer)", sc.unseen)
+		}
+	case sc.current != nil:
+		text = sc.current.String()
+	}
+	sc.mutex.Unlock()
+
+	fyne.Do(func() {
+		sc.label.SetText(text)
+		if sticky {
+			sc.label.Importance = widget.DangerImportance
+			sc.dismissBtn.Show()
+		} else {
+			sc.label.Importance = widget.MediumImportance
+			sc.dismissBtn.Hide()
+		}
+		sc.label.Refresh()
+	})
+}
+
+// DismissSticky clears the sticky error and returns to the message queue
+func (sc *StatusCenter) DismissSticky() {
+	sc.mutex.Lock()
+	sc.sticky = nil
+	sc.unseen = 0
+	sc.mutex.Unlock()
+	sc.render()
+}
+
+// Recent returns up to limit messages, newest first
+func (sc *StatusCenter) Recent(limit int) []StatusMessage {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	recent := make([]StatusMessage, 0, min(limit, len(sc.history)))
+	for i := len(sc.history) - 1; i >= 0 && len(recent) < limit; i-- {
+		recent = append(recent, sc.history[i])
+	}
+	return recent
+}
+
+// ShowHistory opens a popover above the status bar with the recent messages
+func (sc *StatusCenter) ShowHistory() {
+	if sc.popUp != nil {
+		sc.popUp.Hide()
+	}
+
+	recent := sc.Recent(statusHistoryLimit)
+	list := widget.NewList(
+		func() int { return len(recent) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			m := recent[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s [%s] %s", m.Time.Format("15:04:05"), m.Source, m))
+		},
+	)
+
+	var content fyne.CanvasObject = list
+	if len(recent) == 0 {
+		content = widget.NewLabel("No messages yet")
+	}
+	header := container.NewBorder(nil, nil, widget.NewLabelWithStyle("Recent messages", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+		sc.popUp.Hide()
+	}))
+
+	canvas := sc.gui.window.Canvas()
+	size := fyne.NewSize(min(640, canvas.Size().Width-20), 320)
+	sc.popUp = widget.NewPopUp(container.NewBorder(header, nil, nil, nil, content), canvas)
+	sc.popUp.Resize(size)
+
+	anchor := fyne.CurrentApp().Driver().AbsolutePositionForObject(sc.historyBtn)
+	pos := fyne.NewPos(anchor.X+sc.historyBtn.Size().Width-size.Width, anchor.Y-size.Height)
+	sc.popUp.ShowAtPosition(fyne.NewPos(max(pos.X, 0), max(pos.Y, 0)))
+}
+
+// inferStatusSeverity guesses the severity of a plain status text from its emoji
+func inferStatusSeverity(text string) StatusSeverity {
+	switch {
+	case strings.HasPrefix(text, "❌"), strings.HasPrefix(text, "🚫"):
+		return SeverityError
+	case strings.HasPrefix(text, "⚠️"):
+		return SeverityWarning
+	case strings.HasPrefix(text, "✅"):
+		return SeveritySuccess
+	default:
+		return SeverityInfo
+	}
+}
+
+// trimStatusIcon strips a leading severity emoji so it isn't shown twice
+func trimStatusIcon(text string) string {
+	for _, icon := range []string{"❌", "🚫", "⚠️", "✅", "ℹ️"} {
+		if strings.HasPrefix(text, icon) {
+			return strings.TrimSpace(strings.TrimPrefix(text, icon))
+		}
+	}
+	return text
+}
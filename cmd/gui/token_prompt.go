@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"linkedin-crawler/internal/auth"
+)
+
+// promptTokens is the manual token source of a running crawl: it asks for
+// pasted tokens when the sources before it ran dry and blocks until the
+// dialog is closed; Skip returns "" so the crawl stops asking
+func (gui *CrawlerGUI) promptTokens(ctx context.Context) (string, error) {
+	result := make(chan string, 1)
+	gui.updateUI <- func() {
+		gui.postStatus(StatusSourceCrawler, SeverityWarning, "Crawler is waiting for pasted tokens")
+
+		steps := widget.NewLabel("The crawler ran out of tokens from the other sources.\n" +
+			"Sign in to Teams, copy LokiAuthToken (see Accounts → Manual Token) and paste one token per line.")
+		steps.Wrapping = fyne.TextWrapWord
+		openBtn := widget.NewButtonWithIcon("Open Teams Login", theme.ComputerIcon(), func() {
+			if u, err := url.Parse(auth.ManualLoginURL); err == nil {
+				if err := gui.app.OpenURL(u); err != nil {
+					dialog.ShowError(fmt.Errorf("không mở được browser: %v", err), gui.window)
+				}
+			}
+		})
+		tokenEntry := widget.NewMultiLineEntry()
+		tokenEntry.SetPlaceHolder("Paste tokens here, one per line...")
+		tokenEntry.Wrapping = fyne.TextWrapBreak
+		tokenEntry.SetMinRowsVisible(5)
+
+		content := container.NewVBox(steps, openBtn, tokenEntry)
+		d := dialog.NewCustomConfirm("Tokens Needed", "Use Tokens", "Skip", content, func(ok bool) {
+			if ok {
+				result <- tokenEntry.Text
+			} else {
+				result <- ""
+			}
+		}, gui.window)
+		d.Resize(fyne.NewSize(600, 400))
+		d.Show()
+	}
+
+	select {
+	case text := <-result:
+		return text, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
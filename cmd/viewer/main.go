@@ -0,0 +1,350 @@
+// cmd/viewer/main.go - Viewer chỉ đọc cho kết quả, không cần license
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"linkedin-crawler/internal/models"
+	storageInternal "linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
+)
+
+// viewerHeaders are the table columns
+var viewerHeaders = []string{"Email", "Name", "LinkedIn URL", "Location", "Connections", "Source", "Status", "Tags", "Notes", "Fields"}
+
+// Viewer shows the results of a database, hit file or CSV export read-only
+type Viewer struct {
+	window fyne.Window
+
+	path    string
+	all     []utils.HitResult // Toàn bộ kết quả của file đang mở
+	results []utils.HitResult // Sau filter/show
+
+	filterEntry *widget.Entry
+	showSelect  *widget.Select
+	table       *widget.Table
+	statusLabel *widget.Label
+	exportNotes bool
+}
+
+func main() {
+	a := app.NewWithID("com.linkedin.crawler.viewer")
+	w := a.NewWindow("LinkedIn Crawler - Results Viewer")
+	w.Resize(fyne.NewSize(1100, 650))
+
+	v := &Viewer{window: w}
+	w.SetContent(v.CreateContent())
+
+	if len(os.Args) > 1 {
+		v.Load(os.Args[1])
+	}
+	w.ShowAndRun()
+}
+
+// CreateContent builds the toolbar, the results table and the status line
+func (v *Viewer) CreateContent() fyne.CanvasObject {
+	v.filterEntry = widget.NewEntry()
+	v.filterEntry.SetPlaceHolder("Search email, name, location, tags, notes...")
+	v.filterEntry.OnChanged = func(string) { v.applyFilter() }
+
+	v.showSelect = widget.NewSelect([]string{"All"}, func(string) { v.applyFilter() })
+	v.showSelect.SetSelected("All")
+
+	v.statusLabel = widget.NewLabel("Open a results database (emails.db), hit file or CSV export")
+
+	v.table = widget.NewTable(
+		func() (int, int) {
+			return len(v.results) + 1, len(viewerHeaders) // +1 cho header
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("Template")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.TextStyle.Bold = id.Row == 0
+			if id.Row == 0 {
+				label.SetText(viewerHeaders[id.Col])
+				return
+			}
+			if id.Row-1 < len(v.results) {
+				label.SetText(cellText(v.results[id.Row-1], id.Col))
+			}
+		},
+	)
+	for col, width := range []float32{220, 160, 240, 160, 90, 70, 90, 140, 200, 200} {
+		v.table.SetColumnWidth(col, width)
+	}
+	// Click ô → copy giá trị (bảng chỉ đọc)
+	v.table.OnSelected = func(id widget.TableCellID) {
+		if id.Row > 0 && id.Row-1 < len(v.results) {
+			if text := cellText(v.results[id.Row-1], id.Col); text != "" {
+				v.window.Clipboard().SetContent(text)
+				v.statusLabel.SetText(fmt.Sprintf("Copied %s: %s", viewerHeaders[id.Col], text))
+			}
+		}
+		v.table.UnselectAll()
+	}
+
+	toolbar := container.NewHBox(
+		widget.NewButtonWithIcon("Open", theme.FolderOpenIcon(), v.ShowOpen),
+		widget.NewButtonWithIcon("Reload", theme.ViewRefreshIcon(), func() {
+			if v.path != "" {
+				v.Load(v.path)
+			}
+		}),
+		widget.NewButtonWithIcon("Export", theme.DocumentSaveIcon(), v.ShowExport),
+		widget.NewCheck("Export notes", func(checked bool) { v.exportNotes = checked }),
+		widget.NewSeparator(),
+		widget.NewLabel("Show:"),
+		v.showSelect,
+	)
+	top := container.NewBorder(nil, nil, toolbar, nil, v.filterEntry)
+
+	return container.NewBorder(top, v.statusLabel, nil, nil, v.table)
+}
+
+// ShowOpen lets the user pick a file to view
+func (v *Viewer) ShowOpen() {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		reader.Close()
+		v.Load(reader.URI().Path())
+	}, v.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".db", ".txt", ".csv"}))
+	open.Show()
+}
+
+// Load reads the results of path in the background and shows them
+func (v *Viewer) Load(path string) {
+	v.statusLabel.SetText(fmt.Sprintf("Loading %s...", path))
+	go func() {
+		results, err := readResults(path)
+		fyne.Do(func() {
+			if errors.Is(err, storageInternal.ErrDatabaseLocked) || errors.Is(err, storageInternal.ErrWrongPassphrase) {
+				v.askPassphrase(path, err)
+				return
+			}
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("failed to open %s: %w", path, err), v.window)
+				v.statusLabel.SetText(fmt.Sprintf("Failed to open %s", path))
+				return
+			}
+			v.path = path
+			v.all = results
+			v.window.SetTitle(fmt.Sprintf("LinkedIn Crawler - Results Viewer - %s", filepath.Base(path)))
+			v.showSelect.Options = showOptions(results)
+			v.showSelect.SetSelected("All")
+			v.applyFilter()
+		})
+	}()
+}
+
+// askPassphrase asks for the passphrase of a database with encrypted results
+// and loads it again
+func (v *Viewer) askPassphrase(path string, err error) {
+	passphrase := widget.NewPasswordEntry()
+	hint := "The results in this database are encrypted"
+	if errors.Is(err, storageInternal.ErrWrongPassphrase) {
+		hint = "Wrong passphrase, try again"
+	}
+	items := []*widget.FormItem{{Text: "Passphrase:", Widget: passphrase, HintText: hint}}
+	v.statusLabel.SetText(fmt.Sprintf("%s is encrypted", path))
+	dialog.ShowForm("Unlock "+filepath.Base(path), "Unlock", "Cancel", items, func(confirmed bool) {
+		if confirmed {
+			storageInternal.SetPassphrase(passphrase.Text)
+			v.Load(path)
+		}
+	}, v.window)
+}
+
+// readResults reads a crawler database read-only (results with workflow data
+// and custom fields) or a hit file / CSV export
+func readResults(path string) ([]utils.HitResult, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		es := storageInternal.NewReadOnlyEmailStorage(path)
+		defer es.CloseDB()
+
+		results, err := es.GetResults()
+		if err != nil {
+			return nil, err
+		}
+		fields, err := es.GetAllInputFields()
+		if err != nil {
+			return nil, err
+		}
+		utils.AttachInputFields(results, fields)
+		return results, nil
+	case ".csv":
+		return utils.ReadResultFile(path)
+	default:
+		return utils.ReadAllHitResults(path)
+	}
+}
+
+// showOptions returns the Show filter options for results: LinkedIn
+// presence, workflow statuses when any are set, and every tag in use
+func showOptions(results []utils.HitResult) []string {
+	options := []string{"All", "With LinkedIn", "Without LinkedIn"}
+
+	withWorkflow := false
+	tagSet := make(map[string]bool)
+	for _, r := range results {
+		withWorkflow = withWorkflow || r.WorkflowStatus != ""
+		for _, tag := range r.Tags {
+			tagSet[tag] = true
+		}
+	}
+	if withWorkflow {
+		for _, status := range models.ResultStatuses {
+			options = append(options, "Status: "+models.ResultStatusLabel(status))
+		}
+	}
+	tags := make([]string, 0, len(tagSet))
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		options = append(options, "Tag: "+tag)
+	}
+	return options
+}
+
+// applyFilter keeps the results matching the search text and the Show option
+func (v *Viewer) applyFilter() {
+	if v.table == nil {
+		return
+	}
+	text := strings.ToLower(strings.TrimSpace(v.filterEntry.Text))
+	show := v.showSelect.Selected
+
+	v.results = nil
+	for _, r := range v.all {
+		if matchesShow(r, show) && matchesText(r, text) {
+			v.results = append(v.results, r)
+		}
+	}
+	v.table.Refresh()
+
+	if v.path != "" {
+		v.statusLabel.SetText(fmt.Sprintf("%s: showing %d of %d results", v.path, len(v.results), len(v.all)))
+	}
+}
+
+// matchesShow reports whether r matches a Show option
+func matchesShow(r utils.HitResult, show string) bool {
+	hasURL := r.LinkedInURL != "" && r.LinkedInURL != "N/A"
+	switch show {
+	case "", "All":
+		return true
+	case "With LinkedIn":
+		return hasURL
+	case "Without LinkedIn":
+		return !hasURL
+	}
+	if label, ok := strings.CutPrefix(show, "Status: "); ok {
+		return models.ResultStatusLabel(r.WorkflowStatus) == label
+	}
+	if tag, ok := strings.CutPrefix(show, "Tag: "); ok {
+		for _, t := range r.Tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesText reports whether any shown field of r contains text (lowercase)
+func matchesText(r utils.HitResult, text string) bool {
+	if text == "" {
+		return true
+	}
+	for col := range viewerHeaders {
+		if strings.Contains(strings.ToLower(cellText(r, col)), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// cellText returns the text of column col for r
+func cellText(r utils.HitResult, col int) string {
+	switch col {
+	case 0:
+		return r.Email
+	case 1:
+		return r.Name
+	case 2:
+		return r.LinkedInURL
+	case 3:
+		return r.Location
+	case 4:
+		return r.Connections
+	case 5:
+		return r.Source
+	case 6:
+		if r.WorkflowStatus == "" {
+			return ""
+		}
+		return models.ResultStatusLabel(r.WorkflowStatus)
+	case 7:
+		return utils.FormatTags(r.Tags)
+	case 8:
+		return r.Notes
+	case 9:
+		return utils.FormatInputFields(r.Fields)
+	}
+	return ""
+}
+
+// ShowExport writes the shown results to a CSV or JSONL file (by extension)
+func (v *Viewer) ShowExport() {
+	if len(v.results) == 0 {
+		dialog.ShowInformation("No Data", "No results to export", v.window)
+		return
+	}
+
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		entries := append([]utils.HitResult(nil), v.results...)
+		if !v.exportNotes {
+			utils.DropNotes(entries)
+		}
+		data, err := utils.EncodeHits(entries, utils.ExportFormatFromPath(writer.URI().Path()), time.Now())
+		if err == nil {
+			_, err = writer.Write(data)
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("export failed: %w", err), v.window)
+			return
+		}
+		v.statusLabel.SetText(fmt.Sprintf("Exported %d results to %s", len(entries), writer.URI().Path()))
+	}, v.window)
+	save.SetFileName("results.csv")
+	save.Show()
+}
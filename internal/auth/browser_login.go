@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+
+	"linkedin-crawler/internal/models"
+)
+
+const (
+	// lokiHost là host API nhận LokiAuthToken trong header Authorization
+	lokiHost = "loki.delve.office.com"
+	// browserLoginPoll là khoảng thời gian giữa hai lần kiểm tra trang
+	browserLoginPoll = 2 * time.Second
+	// browserLoginMaxSteps giới hạn số lần lặp lại cùng một bước đăng nhập
+	browserLoginMaxSteps = 3
+)
+
+// loginPageStateJS trả về bước đăng nhập đang hiển thị trên trang
+const loginPageStateJS = `(() => {
+	const visible = el => el && el.offsetParent !== null;
+	if (document.querySelector('div[data-viewid="22"][data-showidentitybanner="true"]') || visible(document.querySelector('#newPassword'))) return "change_password";
+	if (visible(document.querySelector('input[type="password"]'))) return "password";
+	if (visible(document.querySelector('input[type="email"]'))) return "email";
+	if (document.querySelector('input[type="submit"][value="Yes"], #idSIButton9[value="Yes"]')) return "stay_signed_in";
+	return "";
+})()`
+
+// fillAndSubmitJS điền value vào input khớp selector rồi bấm submit; không
+// phụ thuộc vào thứ tự các bước của trang login
+const fillAndSubmitJS = `((selector, value) => {
+	const input = document.querySelector(selector);
+	if (!input) return false;
+	const setter = Object.getOwnPropertyDescriptor(HTMLInputElement.prototype, 'value').set;
+	setter.call(input, value);
+	input.dispatchEvent(new Event('input', { bubbles: true }));
+	input.dispatchEvent(new Event('change', { bubbles: true }));
+	const submit = document.querySelector('input[type="submit"], button[type="submit"]');
+	if (submit) submit.click();
+	return true;
+})(%q, %q)`
+
+// LoginWithBrowser is the fallback login: it drives whichever login step the
+// page currently shows instead of a fixed sequence, and captures the token
+// from sessionStorage or from the Authorization header of Loki API requests
+func (ls *LoginService) LoginWithBrowser(ctx context.Context, account models.Account) (string, error) {
+	loginURL := "https://m365.cloud.microsoft/search/?auth=2&home=1"
+
+	fmt.Printf("🌐 Đăng nhập bằng browser fallback: %s\n", account.Email)
+
+	tokenCh := make(chan string, 1)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		var headers network.Headers
+		switch e := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			if !strings.Contains(e.Request.URL, lokiHost) {
+				return
+			}
+			headers = e.Request.Headers
+		case *network.EventRequestWillBeSentExtraInfo:
+			if !strings.Contains(headerValue(e.Headers, ":authority"), lokiHost) {
+				return
+			}
+			headers = e.Headers
+		default:
+			return
+		}
+
+		if token := bearerToken(headerValue(headers, "authorization")); token != "" {
+			select {
+			case tokenCh <- token:
+			default:
+			}
+		}
+	})
+
+	if err := chromedp.Run(ctx, chromedp.Navigate(loginURL)); err != nil {
+		return "", fmt.Errorf("không mở được trang login: %v", err)
+	}
+
+	steps := make(map[string]int)
+	for {
+		select {
+		case token := <-tokenCh:
+			fmt.Printf("✅ Browser fallback lấy token từ network cho: %s\n", account.Email)
+			return token, nil
+		case <-ctx.Done():
+			return "", fmt.Errorf("browser fallback không lấy được token: %w", ctx.Err())
+		case <-time.After(browserLoginPoll):
+		}
+
+		var lokiToken string
+		if err := chromedp.Run(ctx, chromedp.Evaluate(`sessionStorage.getItem("LokiAuthToken") || ""`, &lokiToken)); err == nil && lokiToken != "" {
+			fmt.Printf("✅ Browser fallback lấy token từ sessionStorage cho: %s\n", account.Email)
+			return strings.ReplaceAll(strings.ReplaceAll(lokiToken, "\"", ""), "\\", ""), nil
+		}
+
+		if reason := detectLoginProblem(ctx); reason != "" {
+			return "", &LoginError{Reason: reason, Err: fmt.Errorf("browser fallback")}
+		}
+
+		var state string
+		if err := chromedp.Run(ctx, chromedp.Evaluate(loginPageStateJS, &state)); err != nil || state == "" {
+			continue // Trang đang tải hoặc đang redirect
+		}
+
+		steps[state]++
+		if steps[state] > browserLoginMaxSteps {
+			return "", fmt.Errorf("browser fallback kẹt ở bước %q", state)
+		}
+
+		var err error
+		var filled bool
+		switch state {
+		case "email":
+			err = chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(fillAndSubmitJS, `input[type="email"]`, account.Email), &filled))
+		case "password":
+			err = chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(fillAndSubmitJS, `input[type="password"]`, account.Password), &filled))
+		case "stay_signed_in":
+			err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+				return ls.browserManager.HandleStaySignedInPrompt(ctx, "browser fallback")
+			}))
+		case "change_password":
+			err = ls.handleChangePassword(ctx, account)
+		}
+		if err != nil {
+			return "", fmt.Errorf("browser fallback lỗi ở bước %q: %v", state, err)
+		}
+	}
+}
+
+// headerValue returns a header value regardless of key case
+func headerValue(headers network.Headers, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			if s, ok := value.(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// bearerToken strips the "Bearer " prefix from an Authorization header
+func bearerToken(header string) string {
+	const prefix = "bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -38,12 +39,14 @@ func (ls *LoginService) LoginToTeams(ctx context.Context, account models.Account
 		chromedp.SendKeys(`input[type="email"]`, account.Email, chromedp.ByQuery),
 		chromedp.Click(`input[type="submit"]`, chromedp.ByQuery),
 		chromedp.Sleep(3*time.Second),
+		checkLoginProblem("lỗi sau khi nhập email"),
 
 		chromedp.WaitVisible(`input[type="password"]`, chromedp.ByQuery),
 		chromedp.Clear(`input[type="password"]`, chromedp.ByQuery),
 		chromedp.SendKeys(`input[type="password"]`, account.Password, chromedp.ByQuery),
 		chromedp.Click(`input[type="submit"]`, chromedp.ByQuery),
 		chromedp.Sleep(5*time.Second),
+		checkLoginProblem("lỗi sau khi nhập password"),
 
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			return ls.browserManager.HandleStaySignedInPrompt(ctx, "sau login")
@@ -68,8 +71,18 @@ func (ls *LoginService) LoginToTeams(ctx context.Context, account models.Account
 		chromedp.Sleep(10*time.Second),
 		chromedp.Evaluate(`sessionStorage.getItem("LokiAuthToken")`, &lokiToken),
 	)
+	var loginErr *LoginError
+	if errors.As(err, &loginErr) {
+		return "", err
+	}
+	if err != nil || lokiToken == "" {
+		// Kiểm tra trang hiện tại có báo lỗi đăng nhập không (MFA, bị khóa...)
+		if reason := detectLoginProblem(ctx); reason != "" {
+			return "", &LoginError{Reason: reason, Err: err}
+		}
+	}
 	if err != nil {
-		return "", fmt.Errorf("lỗi khi lấy token: %v", err)
+		return "", fmt.Errorf("lỗi khi lấy token: %w", err)
 	}
 
 	if lokiToken == "" {
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/chromedp/chromedp"
+
+	"linkedin-crawler/internal/models"
+)
+
+// LoginError is a login failure whose cause was recognized on the login page
+type LoginError struct {
+	Reason string // models.AccountStatus*
+	Err    error
+}
+
+// Error implements the error interface
+func (e *LoginError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", models.AccountStatusLabel(e.Reason), e.Err)
+	}
+	return models.AccountStatusLabel(e.Reason)
+}
+
+// Unwrap returns the underlying error
+func (e *LoginError) Unwrap() error {
+	return e.Err
+}
+
+// loginProblemJS nhận diện các trang lỗi đăng nhập của Microsoft (theo element
+// và mã AADSTS), trả về "" khi không có lỗi
+const loginProblemJS = `(() => {
+	const text = (document.body ? document.body.innerText : "").toLowerCase();
+	const has = sel => document.querySelector(sel) !== null;
+	if (text.includes("aadsts50053") || text.includes("account has been locked") || text.includes("account is locked") ||
+		text.includes("tried to sign in too many times")) return "locked";
+	if (text.includes("aadsts90055") || text.includes("too many requests") || text.includes("aadsts50196")) return "rate_limited";
+	if (has('#passwordError') || has('#usernameError') || text.includes("aadsts50126") || text.includes("aadsts50034")) return "wrong_password";
+	if (has('#idDiv_SAOTCS_Proofs') || has('#idDiv_SAOTCAS_Title') || has('#idDiv_SAASDS_Title') || has('input[name="otc"]') ||
+		text.includes("aadsts50076") || text.includes("aadsts50079") || text.includes("more information required")) return "mfa_required";
+	return "";
+})()`
+
+// checkLoginProblem fails the login chain with a LoginError when the page shows
+// a recognized login error
+func checkLoginProblem(step string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		var reason string
+		if err := chromedp.Evaluate(loginProblemJS, &reason).Do(ctx); err != nil || reason == "" {
+			return nil
+		}
+		return &LoginError{Reason: reason, Err: fmt.Errorf("phát hiện %s", step)}
+	}
+}
+
+// detectLoginProblem returns the login error shown on the page, or ""
+func detectLoginProblem(ctx context.Context) string {
+	var reason string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(loginProblemJS, &reason)); err != nil {
+		return ""
+	}
+	return reason
+}
+
+// FailureReason classifies a token extraction error into a models.AccountStatus*
+// value; cancelled logins return ""
+func FailureReason(err error) string {
+	var loginErr *LoginError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &loginErr):
+		return loginErr.Reason
+	case errors.Is(err, context.Canceled):
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return models.AccountStatusTimeout
+	}
+	return models.AccountStatusFailed
+}
+
+// failureAdvice tells the user what to do about each failure reason
+var failureAdvice = map[string]string{
+	models.AccountStatusWrongPassword: "sửa email/password trong accounts file",
+	models.AccountStatusLocked:        "chờ hết lockout (thường 15-60 phút) rồi thử lại",
+	models.AccountStatusMFARequired:   "tắt MFA hoặc thay account khác",
+	models.AccountStatusRateLimited:   "giảm Login Parallelism hoặc chờ vài phút",
+	models.AccountStatusTimeout:       "tăng Login Timeout hoặc thử Login Method browser/auto",
+	models.AccountStatusFailed:        "xem log chi tiết, có thể thử Manual Token",
+	models.AccountStatusDailyCap:      "chờ sang ngày mới hoặc tăng giới hạn đăng nhập/token mỗi ngày của account",
+}
+
+// FailureSummary counts failed accounts by reason and returns one line per
+// reason with a hint, most frequent first
+func FailureSummary(results []models.TokenResult) []string {
+	counts := make(map[string]int)
+	for _, result := range results {
+		if result.Error != nil && result.Reason != "" {
+			counts[result.Reason]++
+		}
+	}
+
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	lines := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		lines = append(lines, fmt.Sprintf("%s: %d account(s) → %s",
+			models.AccountStatusLabel(reason), counts[reason], failureAdvice[reason]))
+	}
+	return lines
+}
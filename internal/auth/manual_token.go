@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ManualLoginURL is the page users open in their own browser to capture a token
+const ManualLoginURL = "https://m365.cloud.microsoft/search/?auth=2&home=1"
+
+// manualTokenPattern matches the characters a LokiAuthToken may contain
+var manualTokenPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// NormalizeToken cleans a token pasted by the user: it accepts the raw token,
+// "Bearer <token>", a full "Authorization: Bearer <token>" header line or the
+// quoted sessionStorage value, and returns "" when nothing usable remains
+func NormalizeToken(raw string) string {
+	token := strings.TrimSpace(raw)
+	if idx := strings.Index(strings.ToLower(token), "authorization:"); idx >= 0 {
+		token = strings.TrimSpace(token[idx+len("authorization:"):])
+	}
+	if t := bearerToken(token); t != "" {
+		token = t
+	}
+	token = strings.ReplaceAll(strings.ReplaceAll(token, "\"", ""), "\\", "")
+	token = strings.Join(strings.Fields(token), "")
+
+	if len(token) <= 50 || !manualTokenPattern.MatchString(token) {
+		return ""
+	}
+	return token
+}
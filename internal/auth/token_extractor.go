@@ -2,7 +2,9 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,10 +12,24 @@ import (
 	"linkedin-crawler/internal/storage"
 )
 
+const (
+	// DefaultLoginParallelism là số account đăng nhập song song mặc định
+	DefaultLoginParallelism = 5
+	// DefaultLoginTimeout là timeout đăng nhập mặc định cho mỗi account
+	DefaultLoginTimeout = 120 * time.Second
+)
+
 // TokenExtractor handles token extraction from browser
 type TokenExtractor struct {
 	loginService   *LoginService
 	accountStorage *storage.AccountStorage
+	parallelism    int
+	loginTimeout   time.Duration
+	loginMethod    string
+
+	// Giới hạn mỗi account mỗi ngày (0 = không giới hạn)
+	maxLoginsPerDay int
+	maxTokensPerDay int
 }
 
 // NewTokenExtractor creates a new TokenExtractor instance
@@ -21,26 +37,78 @@ func NewTokenExtractor() *TokenExtractor {
 	return &TokenExtractor{
 		loginService:   NewLoginService(),
 		accountStorage: storage.NewAccountStorage(),
+		parallelism:    DefaultLoginParallelism,
+		loginTimeout:   DefaultLoginTimeout,
+		loginMethod:    models.LoginMethodDirect,
+	}
+}
+
+// SetParallelism sets how many accounts log in at the same time
+func (te *TokenExtractor) SetParallelism(n int) {
+	if n < 1 {
+		n = DefaultLoginParallelism
+	}
+	te.parallelism = n
+}
+
+// Parallelism returns how many accounts log in at the same time
+func (te *TokenExtractor) Parallelism() int {
+	return te.parallelism
+}
+
+// SetLoginTimeout sets the login timeout for a single account
+func (te *TokenExtractor) SetLoginTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultLoginTimeout
+	}
+	te.loginTimeout = d
+}
+
+// SetLoginMethod selects direct, browser or auto (direct with browser fallback)
+func (te *TokenExtractor) SetLoginMethod(method string) {
+	switch method {
+	case models.LoginMethodBrowser, models.LoginMethodAuto:
+		te.loginMethod = method
+	default:
+		te.loginMethod = models.LoginMethodDirect
 	}
 }
 
+// SetDailyCaps limits how many extraction logins and tokens each account may
+// have per local day (0 = no cap); counts are kept in the accounts table
+func (te *TokenExtractor) SetDailyCaps(logins, tokens int) {
+	te.maxLoginsPerDay = max(logins, 0)
+	te.maxTokensPerDay = max(tokens, 0)
+}
+
 // GetTokenForAccount extracts LokiAuthToken for a given account
 func (te *TokenExtractor) GetTokenForAccount(account models.Account, accountsFilePath string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
-	defer cancel()
+	return te.GetTokenForAccountContext(context.Background(), account, accountsFilePath)
+}
 
-	browserManager := NewBrowserManager()
-	browserCtx, browserCancel, err := browserManager.CreateBrowserContext(ctx)
+// GetTokenForAccountContext extracts LokiAuthToken for a given account, giving
+// up when ctx is cancelled or the per-account login timeout expires
+func (te *TokenExtractor) GetTokenForAccountContext(parent context.Context, account models.Account, accountsFilePath string) (string, error) {
+	var cleanToken string
+	var err error
+	switch te.loginMethod {
+	case models.LoginMethodBrowser:
+		cleanToken, err = te.login(parent, account, te.loginService.LoginWithBrowser)
+	case models.LoginMethodAuto:
+		cleanToken, err = te.login(parent, account, te.loginService.LoginToTeams)
+		// Sai password / bị khóa / MFA thì browser fallback cũng không giúp được
+		var loginErr *LoginError
+		if err != nil && parent.Err() == nil && !errors.As(err, &loginErr) {
+			fmt.Printf("🔁 Direct login lỗi cho %s (%v), thử lại bằng browser fallback...\n", account.Email, err)
+			cleanToken, err = te.login(parent, account, te.loginService.LoginWithBrowser)
+		}
+	default:
+		cleanToken, err = te.login(parent, account, te.loginService.LoginToTeams)
+	}
 	if err != nil {
 		return "", err
 	}
-	defer browserCancel()
 
-	// Perform login
-	var cleanToken string
-	if cleanToken, err = te.loginService.LoginToTeams(browserCtx, account); err != nil {
-		return "", fmt.Errorf("lỗi trong quá trình đăng nhập: %v", err)
-	}
 	// Remove account from file after successful token extraction
 	if rmErr := te.accountStorage.RemoveAccountFromFile(accountsFilePath, account); rmErr != nil {
 		fmt.Printf("⚠️ Không thể xóa account %s: %v\n", account.Email, rmErr)
@@ -51,31 +119,203 @@ func (te *TokenExtractor) GetTokenForAccount(account models.Account, accountsFil
 	return cleanToken, nil
 }
 
+// login runs one login attempt in a fresh browser within the per-account timeout
+func (te *TokenExtractor) login(parent context.Context, account models.Account, loginFn func(context.Context, models.Account) (string, error)) (string, error) {
+	ctx, cancel := context.WithTimeout(parent, te.loginTimeout)
+	defer cancel()
+
+	browserManager := NewBrowserManager()
+	browserCtx, browserCancel, err := browserManager.CreateBrowserContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer browserCancel()
+
+	token, err := loginFn(browserCtx, account)
+	if err != nil {
+		switch {
+		case parent.Err() != nil:
+			return "", fmt.Errorf("đăng nhập bị hủy: %w", parent.Err())
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return "", fmt.Errorf("đăng nhập quá thời gian %v: %w", te.loginTimeout, context.DeadlineExceeded)
+		}
+		return "", fmt.Errorf("lỗi trong quá trình đăng nhập: %w", err)
+	}
+	return token, nil
+}
+
 // ExtractTokensBatch extracts tokens from a batch of accounts
 func (te *TokenExtractor) ExtractTokensBatch(accounts []models.Account, accountsFilePath string) []models.TokenResult {
-	results := make(chan models.TokenResult, len(accounts))
+	return te.ExtractTokensBatchContext(context.Background(), accounts, accountsFilePath, nil)
+}
+
+// ExtractTokensBatchContext logs in up to Parallelism accounts at a time and
+// returns results in input order. onResult (optional) is called as each
+// account finishes; accounts not started before ctx is cancelled fail with
+// ctx.Err()
+func (te *TokenExtractor) ExtractTokensBatchContext(ctx context.Context, accounts []models.Account, accountsFilePath string, onResult func(models.TokenResult)) []models.TokenResult {
+	capped := te.cappedAccounts(accounts)
+	return te.runAccounts(ctx, accounts, accountsFilePath, true, onResult, func(ctx context.Context, acc models.Account) models.TokenResult {
+		if err := capped[strings.ToLower(strings.TrimSpace(acc.Email))]; err != nil {
+			return models.TokenResult{Account: acc, Error: err, Reason: models.AccountStatusDailyCap}
+		}
+		token, err := te.GetTokenForAccountContext(ctx, acc, accountsFilePath)
+		return models.TokenResult{Account: acc, Token: token, Error: err, Reason: FailureReason(err)}
+	})
+}
+
+// cappedAccounts returns the accounts of the batch that reached a daily cap,
+// keyed by lowercase email
+func (te *TokenExtractor) cappedAccounts(accounts []models.Account) map[string]error {
+	if te.maxLoginsPerDay == 0 && te.maxTokensPerDay == 0 {
+		return nil
+	}
+
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		fmt.Printf("⚠️ Không thể đọc giới hạn ngày của accounts: %v\n", err)
+		return nil
+	}
+	defer emailStorage.CloseDB()
+
+	emails := make([]string, len(accounts))
+	for i, account := range accounts {
+		emails[i] = account.Email
+	}
+	history, err := emailStorage.GetAccountHistory(emails)
+	if err != nil {
+		fmt.Printf("⚠️ Không thể đọc giới hạn ngày của accounts: %v\n", err)
+		return nil
+	}
+
+	capped := make(map[string]error)
+	for email, record := range history {
+		switch {
+		case te.maxLoginsPerDay > 0 && record.DayLogins >= te.maxLoginsPerDay:
+			capped[email] = &LoginError{Reason: models.AccountStatusDailyCap,
+				Err: fmt.Errorf("đã đăng nhập %d/%d lần hôm nay", record.DayLogins, te.maxLoginsPerDay)}
+		case te.maxTokensPerDay > 0 && record.DayTokens >= te.maxTokensPerDay:
+			capped[email] = &LoginError{Reason: models.AccountStatusDailyCap,
+				Err: fmt.Errorf("đã lấy %d/%d token hôm nay", record.DayTokens, te.maxTokensPerDay)}
+		}
+	}
+	return capped
+}
+
+// VerifyAccountsContext checks credentials of accounts in parallel (same
+// parallelism and timeout as extraction) without extracting tokens; valid
+// accounts get Reason AccountStatusReady
+func (te *TokenExtractor) VerifyAccountsContext(ctx context.Context, accounts []models.Account, accountsFilePath string, onResult func(models.TokenResult)) []models.TokenResult {
+	return te.runAccounts(ctx, accounts, accountsFilePath, false, onResult, func(ctx context.Context, acc models.Account) models.TokenResult {
+		_, err := te.login(ctx, acc, func(ctx context.Context, acc models.Account) (string, error) {
+			return "", te.loginService.VerifyCredentials(ctx, acc)
+		})
+		reason := FailureReason(err)
+		if err == nil {
+			reason = models.AccountStatusReady
+		}
+		return models.TokenResult{Account: acc, Error: err, Reason: reason}
+	})
+}
+
+// runAccounts runs fn for up to Parallelism accounts at a time and returns
+// results in input order, recording each account's status (and its daily
+// usage when extraction is set)
+func (te *TokenExtractor) runAccounts(ctx context.Context, accounts []models.Account, accountsFilePath string, extraction bool, onResult func(models.TokenResult), fn func(context.Context, models.Account) models.TokenResult) []models.TokenResult {
+	tokenResults := make([]models.TokenResult, len(accounts))
+	slots := make(chan struct{}, te.parallelism)
 	var wg sync.WaitGroup
+	var resultMutex sync.Mutex
 
-	for _, account := range accounts {
+	for i, account := range accounts {
 		wg.Add(1)
-		go func(acc models.Account) {
+		go func(idx int, acc models.Account) {
 			defer wg.Done()
-			token, err := te.GetTokenForAccount(acc, accountsFilePath)
-			results <- models.TokenResult{
-				Account: acc,
-				Token:   token,
-				Error:   err,
+
+			var result models.TokenResult
+			select {
+			case slots <- struct{}{}:
+				result = fn(ctx, acc)
+				<-slots
+			case <-ctx.Done():
+				result = models.TokenResult{Account: acc, Error: fmt.Errorf("đăng nhập bị hủy: %w", ctx.Err())}
 			}
-		}(account)
+
+			tokenResults[idx] = result
+			if onResult != nil {
+				resultMutex.Lock()
+				onResult(result)
+				resultMutex.Unlock()
+			}
+		}(i, account)
 	}
 
 	wg.Wait()
-	close(results)
+	te.recordAccountStatus(accountsFilePath, tokenResults, extraction)
+	return tokenResults
+}
+
+// recordAccountStatus stores the outcome of each account next to the accounts
+// file; accounts that produced a token (and were removed) are forgotten there
+// but kept as used in the accounts table of the database. Accounts skipped by
+// a daily cap keep their earlier status.
+func (te *TokenExtractor) recordAccountStatus(accountsFilePath string, results []models.TokenResult, extraction bool) {
+	recordAccountHistory(results, extraction)
 
-	var tokenResults []models.TokenResult
-	for result := range results {
-		tokenResults = append(tokenResults, result)
+	store, err := storage.LoadAccountStatusStore(accountsFilePath)
+	if err != nil {
+		fmt.Printf("⚠️ Không thể đọc trạng thái accounts: %v\n", err)
+		return
 	}
 
-	return tokenResults
+	for _, result := range results {
+		switch {
+		case result.Reason == models.AccountStatusDailyCap:
+			continue
+		case result.Error == nil && result.Token != "":
+			store.Delete(result.Account.Email)
+		case result.Error != nil && result.Reason != "":
+			store.Set(result.Account.Email, result.Reason, result.Error.Error())
+		case result.Reason != "":
+			store.Set(result.Account.Email, result.Reason, "")
+		}
+	}
+
+	if err := store.Save(); err != nil {
+		fmt.Printf("⚠️ Không thể lưu trạng thái accounts: %v\n", err)
+	}
+}
+
+// recordAccountHistory stores the outcome of each account in the database, so
+// re-imported accounts can be checked against earlier runs, and counts
+// extraction logins towards the daily caps
+func recordAccountHistory(results []models.TokenResult, extraction bool) {
+	emailStorage := storage.NewEmailStorage()
+	if err := emailStorage.InitDB(); err != nil {
+		fmt.Printf("⚠️ Không thể ghi lịch sử accounts: %v\n", err)
+		return
+	}
+	defer emailStorage.CloseDB()
+
+	for _, result := range results {
+		status, detail := result.Reason, ""
+		switch {
+		case result.Error == nil && result.Token != "":
+			status = models.AccountStatusUsed
+		case result.Error != nil:
+			detail = result.Error.Error()
+		}
+		if status == "" || status == models.AccountStatusDailyCap {
+			continue // Bị hủy hoặc bỏ qua trước khi đăng nhập
+		}
+		if err := emailStorage.RecordAccountStatus(result.Account.Email, status, detail); err != nil {
+			fmt.Printf("⚠️ Không thể ghi lịch sử account %s: %v\n", result.Account.Email, err)
+			continue
+		}
+		if extraction {
+			if err := emailStorage.RecordAccountExtraction(result.Account.Email, status == models.AccountStatusUsed); err != nil {
+				fmt.Printf("⚠️ Không thể ghi lịch sử account %s: %v\n", result.Account.Email, err)
+			}
+		}
+	}
 }
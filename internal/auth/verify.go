@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"linkedin-crawler/internal/models"
+)
+
+// VerifyCredentials submits email and password and checks the login page's
+// answer without waiting for Teams to load or extracting a token
+func (ls *LoginService) VerifyCredentials(ctx context.Context, account models.Account) error {
+	fmt.Printf("🔎 Đang kiểm tra account: %s\n", account.Email)
+
+	var state string
+	err := chromedp.Run(ctx,
+		chromedp.Navigate(ManualLoginURL),
+
+		chromedp.WaitVisible(`input[type="email"]`, chromedp.ByQuery),
+		chromedp.SendKeys(`input[type="email"]`, account.Email, chromedp.ByQuery),
+		chromedp.Click(`input[type="submit"]`, chromedp.ByQuery),
+		chromedp.Sleep(3*time.Second),
+		checkLoginProblem("lỗi sau khi nhập email"),
+
+		chromedp.WaitVisible(`input[type="password"]`, chromedp.ByQuery),
+		chromedp.SendKeys(`input[type="password"]`, account.Password, chromedp.ByQuery),
+		chromedp.Click(`input[type="submit"]`, chromedp.ByQuery),
+		chromedp.Sleep(5*time.Second),
+		checkLoginProblem("lỗi sau khi nhập password"),
+
+		chromedp.Evaluate(loginPageStateJS, &state),
+	)
+
+	var loginErr *LoginError
+	if errors.As(err, &loginErr) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("không kiểm tra được account: %w", err)
+	}
+
+	// Vẫn ở trang password mà không có thông báo lỗi: coi như password bị từ chối
+	if state == "password" {
+		return &LoginError{Reason: models.AccountStatusWrongPassword, Err: fmt.Errorf("password không được chấp nhận")}
+	}
+
+	fmt.Printf("✅ Account hợp lệ: %s\n", account.Email)
+	return nil
+}
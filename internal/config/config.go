@@ -9,15 +9,47 @@ import (
 // DefaultConfig returns the default configuration for the crawler
 func DefaultConfig() models.Config {
 	return models.Config{
-		MaxConcurrency:   30,
-		RequestsPerSec:   15.0,
-		RequestTimeout:   15 * time.Second,
-		ShutdownTimeout:  10 * time.Second,
-		EmailsFilePath:   "emails.txt",
-		TokensFilePath:   "tokens.txt",
-		AccountsFilePath: "accounts.txt",
-		MinTokens:        10,
-		MaxTokens:        10,
-		SleepDuration:    30 * time.Second,
+		MaxConcurrency:          30,
+		RequestsPerSec:          15.0,
+		RequestTimeout:          15 * time.Second,
+		ShutdownTimeout:         10 * time.Second,
+		EmailsFilePath:          "emails.txt",
+		TokensFilePath:          "tokens.txt",
+		AccountsFilePath:        "accounts.txt",
+		OutputFilePath:          "hit.txt",
+		OutputMaxSizeMB:         50,
+		DBPath:                  "emails.db",
+		LogFilePath:             "crawler.log",
+		Campaign:                "default",
+		MinTokens:               10,
+		MaxTokens:               10,
+		LoginParallelism:        5,
+		LoginTimeout:            120 * time.Second,
+		LoginMethod:             models.LoginMethodDirect,
+		TokenSources:            models.DefaultTokenSources,
+		PacingProfile:           models.PacingSteady,
+		AccountShortfall:        models.AccountShortfallWarn,
+		SheetsRange:             "Sheet1",
+		SheetsInterval:          time.Minute,
+		NotifyEveryHits:         100,
+		MilestoneEveryProcessed: 1000,
+		MilestoneInterval:       15 * time.Minute,
+		NotifyTemplates:         "notify-templates.json",
+		EmailsDBDriver:          "postgres",
+		EmailsFileEdits:         models.EmailsFileEditsImport,
+		LogPrivacy:              models.LogPrivacyOff,
+		IMAPMailbox:             "INBOX",
+		IMAPSinceDays:           30,
+		IMAPFields:              "from,to,cc",
+		IMAPExclude:             "noreply,no-reply,donotreply,mailer-daemon,postmaster",
+		IMAPMaxMessages:         5000,
+		BackupDir:               "backups",
+		BackupKeep:              10,
+		SimulateHitRate:         0.3,
+		SimulateLatency:         300 * time.Millisecond,
+		CrawlMode:               models.CrawlModeEmail,
+		CaptureFailures:         false,
+		ExtendedProfile:         false,
+		CaptureDir:              "debug",
 	}
 }
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// Mức độ của một vấn đề cấu hình
+const (
+	SeverityError   = "error"   // Không chạy được hoặc chạy sai
+	SeverityWarning = "warning" // Chạy được nhưng nhiều khả năng không như ý
+)
+
+// aggressiveLoad là ngưỡng concurrency × requests/s coi là quá mạnh
+// (gấp đôi preset Aggressive 75 × 30/s thì token gần như chắc bị 429)
+const aggressiveLoad = 4500
+
+// Problem is one issue found by Validate, with a suggested fix
+type Problem struct {
+	Field    string // Tên field như trên form Config
+	Severity string // SeverityError hoặc SeverityWarning
+	Message  string
+	Fix      string
+}
+
+// String renders the problem as "Field: message (fix)"
+func (p Problem) String() string {
+	if p.Fix == "" {
+		return fmt.Sprintf("%s: %s", p.Field, p.Message)
+	}
+	return fmt.Sprintf("%s: %s (%s)", p.Field, p.Message, p.Fix)
+}
+
+// Problems are all issues found by Validate
+type Problems []Problem
+
+// Errors returns the problems that prevent a correct run
+func (ps Problems) Errors() Problems {
+	return ps.withSeverity(SeverityError)
+}
+
+// Warnings returns the problems a run can live with
+func (ps Problems) Warnings() Problems {
+	return ps.withSeverity(SeverityWarning)
+}
+
+func (ps Problems) withSeverity(severity string) Problems {
+	var out Problems
+	for _, p := range ps {
+		if p.Severity == severity {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Err returns an error listing every error, or nil when there is none
+func (ps Problems) Err() error {
+	errs := ps.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config:\n%s", errs)
+}
+
+// String renders one problem per line
+func (ps Problems) String() string {
+	lines := make([]string, len(ps))
+	for i, p := range ps {
+		lines[i] = "- " + p.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks cfg for out-of-range values and combinations that do not
+// work together, and returns every problem at once instead of stopping at the
+// first. The API endpoint is checked by crawler.EndpointFromConfig.
+func Validate(cfg models.Config) Problems {
+	var ps Problems
+	fail := func(field, fix, format string, args ...interface{}) {
+		ps = append(ps, Problem{Field: field, Severity: SeverityError, Message: fmt.Sprintf(format, args...), Fix: fix})
+	}
+	warn := func(field, fix, format string, args ...interface{}) {
+		ps = append(ps, Problem{Field: field, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...), Fix: fix})
+	}
+	defaults := DefaultConfig()
+
+	// Tốc độ
+	if cfg.MaxConcurrency < 1 {
+		fail("Max Concurrency", fmt.Sprintf("use %d", defaults.MaxConcurrency), "must be at least 1, got %d", cfg.MaxConcurrency)
+	}
+	if cfg.RequestsPerSec <= 0 {
+		fail("Requests/Sec", fmt.Sprintf("use %.0f", defaults.RequestsPerSec), "must be > 0, got %g", cfg.RequestsPerSec)
+	}
+	if cfg.MaxConcurrency >= 1 && cfg.RequestsPerSec > 0 {
+		if load := float64(cfg.MaxConcurrency) * cfg.RequestsPerSec; load > aggressiveLoad {
+			warn("Requests/Sec", "stay near Aggressive: concurrency 75, 30/s",
+				"concurrency %d × %.1f req/s is far above the Aggressive preset; tokens will mostly hit 429", cfg.MaxConcurrency, cfg.RequestsPerSec)
+		}
+		if cfg.RequestsPerSec > float64(cfg.MaxConcurrency) {
+			warn("Max Concurrency", fmt.Sprintf("raise it to at least %.0f or lower Requests/Sec", cfg.RequestsPerSec),
+				"%d workers cannot sustain %.1f req/s with ~1s per request", cfg.MaxConcurrency, cfg.RequestsPerSec)
+		}
+	}
+	if cfg.MaxRequestsPerHour < 0 {
+		fail("Max Requests/Hour", "use 0 for no cap", "must be >= 0, got %d", cfg.MaxRequestsPerHour)
+	}
+	if cfg.MaxRequestsPerDay < 0 {
+		fail("Max Requests/Day", "use 0 for no cap", "must be >= 0, got %d", cfg.MaxRequestsPerDay)
+	}
+	if cfg.MaxRequestsPerHour > 0 && cfg.MaxRequestsPerDay > 0 && cfg.MaxRequestsPerDay < cfg.MaxRequestsPerHour {
+		warn("Max Requests/Day", fmt.Sprintf("raise it to at least %d or lower the hourly cap", cfg.MaxRequestsPerHour),
+			"daily cap %d is below the hourly cap %d, so the hourly cap never applies", cfg.MaxRequestsPerDay, cfg.MaxRequestsPerHour)
+	}
+	if _, err := utils.ParseCrawlWindow(cfg.CrawlWindowStart, cfg.CrawlWindowEnd, cfg.CrawlTimeZone); err != nil {
+		fail("Crawl From/Until", "use HH:MM for both ends and an IANA zone such as Europe/Paris, or leave all empty", "%v", err)
+	}
+
+	// Timeouts
+	if cfg.RequestTimeout <= 0 {
+		fail("Request Timeout", fmt.Sprintf("use %v", defaults.RequestTimeout), "must be > 0, got %v", cfg.RequestTimeout)
+	}
+	if cfg.ShutdownTimeout < 0 {
+		fail("Shutdown Timeout", fmt.Sprintf("use %v", defaults.ShutdownTimeout), "must be >= 0, got %v", cfg.ShutdownTimeout)
+	}
+	if cfg.SleepDuration < 0 {
+		fail("Sleep Duration", "use 0 to exit as soon as the state is saved", "must be >= 0, got %v", cfg.SleepDuration)
+	}
+	if cfg.LoginTimeout > 0 && cfg.LoginTimeout < 10*time.Second {
+		fail("Login Timeout", fmt.Sprintf("use %v", defaults.LoginTimeout), "must be at least 10s, got %v", cfg.LoginTimeout)
+	}
+
+	// Tokens
+	if cfg.MinTokens < 1 {
+		fail("Min Tokens", fmt.Sprintf("use %d", defaults.MinTokens), "must be at least 1, got %d", cfg.MinTokens)
+	}
+	if cfg.MaxTokens < 1 {
+		fail("Max Tokens", fmt.Sprintf("use %d", defaults.MaxTokens), "must be at least 1, got %d", cfg.MaxTokens)
+	}
+	if cfg.MinTokens > cfg.MaxTokens && cfg.MaxTokens >= 1 {
+		fail("Min Tokens", fmt.Sprintf("lower Min Tokens to %d or raise Max Tokens to %d", cfg.MaxTokens, cfg.MinTokens),
+			"%d is more than Max Tokens %d, so a refill never reaches the minimum", cfg.MinTokens, cfg.MaxTokens)
+	}
+	if cfg.LoginParallelism < 1 {
+		fail("Login Parallelism", fmt.Sprintf("use %d", defaults.LoginParallelism), "must be at least 1, got %d", cfg.LoginParallelism)
+	}
+	if sources, err := models.ParseTokenSources(cfg.TokenSources); err != nil {
+		fail("Token Sources", "use e.g. "+models.DefaultTokenSources, "%v", err)
+	} else if slices.Contains(sources, models.TokenSourceProvider) && strings.TrimSpace(cfg.TokenProviderURL) == "" {
+		fail("Token Provider URL", "enter the token farm endpoint, or remove provider from Token Sources", "is empty but provider is a token source")
+	} else if !slices.Contains(sources, models.TokenSourceProvider) && strings.TrimSpace(cfg.TokenProviderURL) != "" {
+		warn("Token Sources", "add provider to Token Sources, e.g. file,provider,accounts", "a Token Provider URL is set but provider is not a token source, so it is never asked")
+	}
+	if provider := strings.TrimSpace(cfg.TokenProviderURL); provider != "" &&
+		!strings.HasPrefix(provider, "http://") && !strings.HasPrefix(provider, "https://") {
+		fail("Token Provider URL", "use an http(s) URL", "%q is not an http(s) URL", provider)
+	}
+	if cfg.MaxAccountLoginsPerDay < 0 {
+		fail("Max Logins/Account/Day", "use 0 for no cap", "must be >= 0, got %d", cfg.MaxAccountLoginsPerDay)
+	}
+	if cfg.MaxAccountTokensPerDay < 0 {
+		fail("Max Tokens/Account/Day", "use 0 for no cap", "must be >= 0, got %d", cfg.MaxAccountTokensPerDay)
+	}
+	if cfg.MaxAccountLoginsPerDay > 0 && cfg.MaxAccountTokensPerDay > cfg.MaxAccountLoginsPerDay {
+		warn("Max Tokens/Account/Day", fmt.Sprintf("lower it to %d or raise Max Logins/Account/Day", cfg.MaxAccountLoginsPerDay),
+			"%d is more than the %d logins allowed per day, and each login gives at most one token, so the token cap never applies", cfg.MaxAccountTokensPerDay, cfg.MaxAccountLoginsPerDay)
+	}
+	if cfg.MaxEmailsPerRun < 0 {
+		fail("Max Emails/Run", "use 0 for no limit", "must be >= 0, got %d", cfg.MaxEmailsPerRun)
+	}
+	if cfg.MaxAccountsPerRun < 0 {
+		fail("Max Accounts/Run", "use 0 for no limit", "must be >= 0, got %d", cfg.MaxAccountsPerRun)
+	}
+
+	// Lựa chọn có tập giá trị cố định
+	options := []struct {
+		field, value string
+		allowed      []string
+	}{
+		{"Crawl Mode", cfg.CrawlMode, models.CrawlModes},
+		{"Login Method", cfg.LoginMethod, models.LoginMethods},
+		{"Pacing", cfg.PacingProfile, models.PacingProfiles},
+		{"Account Shortfall", cfg.AccountShortfall, models.AccountShortfallActions},
+		{"Edits While Crawling", cfg.EmailsFileEdits, models.EmailsFileEditModes},
+		{"Emails in Logs", cfg.LogPrivacy, models.LogPrivacyModes},
+	}
+	for _, option := range options {
+		if !slices.Contains(option.allowed, option.value) {
+			fail(option.field, "use one of "+strings.Join(option.allowed, ", "), "unknown value %q", option.value)
+		}
+	}
+
+	// Tính năng bật một nửa
+	if cfg.BackupInterval < 0 {
+		fail("Scheduled Backups Every", "use 0 to turn scheduled backups off", "must be >= 0, got %v", cfg.BackupInterval)
+	}
+	if cfg.RetentionDays < 0 {
+		fail("Keep Data For", "use 0 to keep data forever", "must be >= 0, got %d", cfg.RetentionDays)
+	}
+	if cfg.BackupInterval > 0 && strings.TrimSpace(cfg.BackupDir) == "" {
+		fail("Scheduled Backups Folder", fmt.Sprintf("use %q", defaults.BackupDir), "is empty but scheduled backups are on")
+	}
+	if cfg.SheetsSpreadsheetID != "" && cfg.SheetsCredentialsFile == "" {
+		fail("Google Sheets Service Account", "choose the service account JSON key, or clear the spreadsheet ID", "is empty but a spreadsheet ID is set")
+	}
+	if cfg.EmailsDBDSN != "" && strings.TrimSpace(cfg.EmailsDBQuery) == "" {
+		fail("Email Source Query", "enter a SELECT returning one email per row, or clear the DSN", "is empty but a database DSN is set")
+	}
+	if cfg.IMAPServer != "" && cfg.IMAPUsername == "" {
+		fail("IMAP Username", "enter the mailbox login, or clear the server", "is empty but an IMAP server is set")
+	}
+	if cfg.WebhookHitEvents && cfg.WebhookURL == "" {
+		warn("Outbound Webhook", "enter a Webhook URL or untick the profile.found events", "profile.found events are on but no URL is set")
+	}
+	if cfg.NotifyEveryHits < 0 {
+		fail("Milestone Every Hits", "use 0 to turn hit milestones off", "must be >= 0, got %d", cfg.NotifyEveryHits)
+	}
+	if cfg.MilestoneEveryProcessed < 0 {
+		fail("Milestone Every Processed", "use 0 to turn processed milestones off", "must be >= 0, got %d", cfg.MilestoneEveryProcessed)
+	}
+	if cfg.MilestoneInterval < 0 {
+		fail("Milestone Interval", "use 0 to turn timed milestones off", "must be >= 0, got %v", cfg.MilestoneInterval)
+	}
+	if cfg.Simulate && (cfg.SimulateHitRate < 0 || cfg.SimulateHitRate > 1) {
+		fail("Simulation Hit Rate", "use a value between 0 and 1, e.g. 0.3", "must be 0-1, got %g", cfg.SimulateHitRate)
+	}
+
+	// Bộ nhớ và cache
+	if cfg.MemoryLimitMB != 0 && cfg.MemoryLimitMB < 128 {
+		fail("Memory Limit", "use 0 (no limit) or at least 128", "%d MB is too low to run", cfg.MemoryLimitMB)
+	}
+	if cfg.ResponseCacheTTL < 0 {
+		fail("Response Cache TTL", "use 0 to turn the cache off", "must be >= 0, got %v", cfg.ResponseCacheTTL)
+	}
+	if cfg.OutputMaxSizeMB < 0 {
+		fail("Hit File Max Size", "use 0 to never split", "must be >= 0, got %d", cfg.OutputMaxSizeMB)
+	}
+
+	return ps
+}
@@ -0,0 +1,214 @@
+package crawler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCapturedBodyBytes giới hạn kích thước body được lưu trong mỗi capture
+	maxCapturedBodyBytes = 4096
+	// captureFileName là file JSONL chứa các capture trong thư mục debug
+	captureFileName = "captures.jsonl"
+)
+
+// sensitiveHeaders are masked before a capture is written to disk
+var sensitiveHeaders = map[string]bool{
+	"authorization":   true,
+	"cookie":          true,
+	"set-cookie":      true,
+	"x-anchormailbox": true,
+}
+
+// CaptureRecord is one sanitized request/response pair
+type CaptureRecord struct {
+	Timestamp       string            `json:"timestamp"`
+	Email           string            `json:"email"`
+	Reason          string            `json:"reason"`
+	Method          string            `json:"method,omitempty"`
+	URL             string            `json:"url,omitempty"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	StatusCode      int               `json:"status_code"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	BodyTruncated   bool              `json:"body_truncated,omitempty"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// ResponseCapture records failing requests into a debug directory
+type ResponseCapture struct {
+	dir   string
+	mutex sync.Mutex
+	count int
+}
+
+// NewResponseCapture creates a new ResponseCapture writing into dir
+func NewResponseCapture(dir string) *ResponseCapture {
+	if dir == "" {
+		dir = "debug"
+	}
+	return &ResponseCapture{dir: dir}
+}
+
+// Dir returns the capture directory
+func (rc *ResponseCapture) Dir() string {
+	return rc.dir
+}
+
+// Count returns number of captures written in this session
+func (rc *ResponseCapture) Count() int {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	return rc.count
+}
+
+// CaptureResponse records a failed HTTP exchange for the given email
+func (rc *ResponseCapture) CaptureResponse(email, reason string, req *http.Request, statusCode int, respHeader http.Header, body []byte, reqErr error) {
+	record := CaptureRecord{
+		Timestamp:  time.Now().Format(time.RFC3339),
+		Email:      email,
+		Reason:     reason,
+		StatusCode: statusCode,
+	}
+
+	if req != nil {
+		record.Method = req.Method
+		record.URL = sanitizeURL(req.URL)
+		record.RequestHeaders = sanitizeHeaders(req.Header)
+	}
+
+	if respHeader != nil {
+		record.ResponseHeaders = sanitizeHeaders(respHeader)
+	}
+
+	if len(body) > maxCapturedBodyBytes {
+		record.Body = string(body[:maxCapturedBodyBytes])
+		record.BodyTruncated = true
+	} else {
+		record.Body = string(body)
+	}
+
+	if reqErr != nil {
+		record.Error = reqErr.Error()
+	}
+
+	if err := rc.writeRecord(record); err != nil {
+		fmt.Printf("⚠️ Không thể ghi debug capture: %v\n", err)
+	}
+}
+
+// CaptureParseFailure records a 200 response whose body could not be parsed
+func (rc *ResponseCapture) CaptureParseFailure(email string, body []byte, parseErr error) {
+	rc.CaptureResponse(email, "parse_failure", nil, http.StatusOK, nil, body, parseErr)
+}
+
+// writeRecord appends a record to the JSONL capture file
+func (rc *ResponseCapture) writeRecord(record CaptureRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode capture: %w", err)
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if err := os.MkdirAll(rc.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create capture directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(rc.dir, captureFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open capture file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write capture: %w", err)
+	}
+
+	rc.count++
+	return nil
+}
+
+// ExportBundle zips the capture directory into a single debug bundle
+func (rc *ResponseCapture) ExportBundle(bundlePath string) error {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	entries, err := os.ReadDir(rc.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read capture directory: %w", err)
+	}
+
+	out, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	for _, entry := range entries {
+		// Bỏ qua thư mục con và các bundle cũ
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".zip") {
+			continue
+		}
+
+		src, err := os.Open(filepath.Join(rc.dir, entry.Name()))
+		if err != nil {
+			zw.Close()
+			return fmt.Errorf("failed to open %s: %w", entry.Name(), err)
+		}
+
+		dst, err := zw.Create(entry.Name())
+		if err == nil {
+			_, err = io.Copy(dst, src)
+		}
+		src.Close()
+		if err != nil {
+			zw.Close()
+			return fmt.Errorf("failed to add %s to bundle: %w", entry.Name(), err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// sanitizeHeaders flattens headers and masks credentials
+func sanitizeHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for key, values := range header {
+		if sensitiveHeaders[strings.ToLower(key)] {
+			result[key] = "***REDACTED***"
+			continue
+		}
+		result[key] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// sanitizeURL drops query values that may carry credentials
+func sanitizeURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	clean := *u
+	q := clean.Query()
+	for key := range q {
+		lower := strings.ToLower(key)
+		if strings.Contains(lower, "token") || strings.Contains(lower, "auth") {
+			q.Set(key, "***REDACTED***")
+		}
+	}
+	clean.RawQuery = q.Encode()
+	return clean.String()
+}
@@ -12,6 +12,7 @@ import (
 	"golang.org/x/sync/semaphore"
 
 	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
 )
 
 // New creates a new LinkedInCrawler instance
@@ -38,8 +39,8 @@ func New(config models.Config, outputFilePath string) (*models.LinkedInCrawler,
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// APPEND mode - ghi thêm vào file hit.txt (KHÔNG ghi đè)
-	outputFile, err := os.OpenFile(outputFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	// APPEND mode - ghi thêm vào file hit.txt (KHÔNG ghi đè), tách file khi đạt OutputMaxSizeMB
+	outputFile, err := utils.NewRotatingWriter(outputFilePath, int64(config.OutputMaxSizeMB)<<20)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create output file: %w", err)
 	}
@@ -92,6 +93,7 @@ func New(config models.Config, outputFilePath string) (*models.LinkedInCrawler,
 		RequestSemaphore:  semaphore.NewWeighted(config.MaxConcurrency),
 		RequestTicker:     requestTicker,
 		RequestChan:       requestChan,
+		Pacer:             NewPacer(config.PacingProfile),
 		Ctx:               ctx,
 		Cancel:            cancel,
 	}, nil
@@ -0,0 +1,93 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"linkedin-crawler/internal/models"
+)
+
+// DefaultAPIBaseURL is the base of the Loki LinkedIn endpoints; the profile
+// endpoint is <base>/full
+const DefaultAPIBaseURL = "https://eur.loki.delve.office.com/api/v1/linkedin/profiles"
+
+// Endpoint is where Loki requests are sent and what is added to them: a
+// regional endpoint, a proxy or a mock server, with extra or overridden
+// headers and query parameters
+type Endpoint struct {
+	BaseURL string
+	Headers http.Header // Ghi đè/bổ sung header mặc định
+	Params  url.Values  // Ghi đè/bổ sung query parameter mặc định
+}
+
+// DefaultEndpoint returns the Loki endpoint without overrides
+func DefaultEndpoint() Endpoint {
+	return Endpoint{BaseURL: DefaultAPIBaseURL}
+}
+
+// NewEndpoint parses an endpoint. headers holds one "Name: value" per line,
+// params is a query string such as "UserLocale=de-DE&PersonaType=User".
+// Empty baseURL means DefaultAPIBaseURL.
+func NewEndpoint(baseURL, headers, params string) (Endpoint, error) {
+	endpoint := DefaultEndpoint()
+
+	if baseURL = strings.TrimSpace(baseURL); baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return endpoint, fmt.Errorf("invalid API base URL %q: must be an http(s) URL", baseURL)
+		}
+		endpoint.BaseURL = strings.TrimRight(baseURL, "/")
+	}
+
+	for _, line := range strings.Split(headers, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return endpoint, fmt.Errorf("invalid API header %q: expected \"Name: value\"", line)
+		}
+		if endpoint.Headers == nil {
+			endpoint.Headers = make(http.Header)
+		}
+		endpoint.Headers.Add(name, strings.TrimSpace(value))
+	}
+
+	if params = strings.TrimSpace(params); params != "" {
+		values, err := url.ParseQuery(params)
+		if err != nil {
+			return endpoint, fmt.Errorf("invalid API query parameters %q: %w", params, err)
+		}
+		endpoint.Params = values
+	}
+
+	return endpoint, nil
+}
+
+// EndpointFromConfig builds the endpoint from APIBaseURL, APIHeaders and APIQueryParams
+func EndpointFromConfig(config models.Config) (Endpoint, error) {
+	return NewEndpoint(config.APIBaseURL, config.APIHeaders, config.APIQueryParams)
+}
+
+// ProfileURL returns the endpoint queried for each email in email mode
+func (e Endpoint) ProfileURL() string {
+	return e.BaseURL + "/full"
+}
+
+// prepare applies the parameter overrides to q, sets it as the request query
+// and adds the Loki headers followed by the header overrides
+func (e Endpoint) prepare(req *http.Request, q url.Values, authHeader string) {
+	for name, values := range e.Params {
+		q[name] = values
+	}
+	req.URL.RawQuery = q.Encode()
+
+	addLokiHeaders(req, authHeader)
+	for name, values := range e.Headers {
+		req.Header[name] = values
+	}
+}
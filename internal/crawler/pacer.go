@@ -0,0 +1,190 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"linkedin-crawler/internal/models"
+)
+
+// Tham số mặc định cho các pacing profile
+const (
+	thinkTimeMin = 200 * time.Millisecond
+	thinkTimeMax = 1500 * time.Millisecond
+
+	burstSizeMin  = 40
+	burstSizeMax  = 120
+	burstPauseMin = 20 * time.Second
+	burstPauseMax = 90 * time.Second
+
+	nightStartHour = 0 // Ban đêm tính theo giờ máy: [0h, 6h)
+	nightEndHour   = 6
+	nightDelayMin  = 1 * time.Second
+	nightDelayMax  = 4 * time.Second
+)
+
+// NewPacer returns the pacer for a pacing profile; unknown profiles fall back to steady
+func NewPacer(profile string) models.Pacer {
+	switch profile {
+	case models.PacingJitter:
+		return newThinkTimePacer()
+	case models.PacingBurst:
+		return newBurstPacer()
+	case models.PacingNightly:
+		return newNightlyPacer()
+	case models.PacingHuman:
+		return chainPacer{newThinkTimePacer(), newBurstPacer(), newNightlyPacer()}
+	}
+	return steadyPacer{}
+}
+
+// steadyPacer never adds delay
+type steadyPacer struct{}
+
+// Wait returns immediately
+func (steadyPacer) Wait(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// chainPacer runs several pacers one after another
+type chainPacer []models.Pacer
+
+// Wait waits on every pacer in order
+func (c chainPacer) Wait(ctx context.Context) error {
+	for _, p := range c {
+		if err := p.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// randomSource is a goroutine-safe random source shared by the pacers
+type randomSource struct {
+	mutex sync.Mutex
+	r     *rand.Rand
+}
+
+func newRandomSource() *randomSource {
+	return &randomSource{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// duration returns a random duration in [lo, hi]
+func (rs *randomSource) duration(lo, hi time.Duration) time.Duration {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	if hi <= lo {
+		return lo
+	}
+	return lo + time.Duration(rs.r.Int63n(int64(hi-lo)+1))
+}
+
+// intn returns a random int in [lo, hi]
+func (rs *randomSource) intn(lo, hi int) int {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	if hi <= lo {
+		return lo
+	}
+	return lo + rs.r.Intn(hi-lo+1)
+}
+
+// thinkTimePacer sleeps a random think time before each request
+type thinkTimePacer struct {
+	rand   *randomSource
+	lo, hi time.Duration
+}
+
+func newThinkTimePacer() *thinkTimePacer {
+	return &thinkTimePacer{rand: newRandomSource(), lo: thinkTimeMin, hi: thinkTimeMax}
+}
+
+// Wait sleeps a random think time
+func (p *thinkTimePacer) Wait(ctx context.Context) error {
+	return sleepContext(ctx, p.rand.duration(p.lo, p.hi))
+}
+
+// burstPacer lets a random-sized burst of requests through, then pauses all
+// workers for a random duration before the next burst
+type burstPacer struct {
+	rand       *randomSource
+	mutex      sync.Mutex
+	remaining  int
+	pauseUntil time.Time
+}
+
+func newBurstPacer() *burstPacer {
+	p := &burstPacer{rand: newRandomSource()}
+	p.remaining = p.rand.intn(burstSizeMin, burstSizeMax)
+	return p
+}
+
+// Wait blocks while a pause is active and starts a pause once the burst is used up
+func (p *burstPacer) Wait(ctx context.Context) error {
+	p.mutex.Lock()
+	now := time.Now()
+	wait := p.pauseUntil.Sub(now)
+	if wait <= 0 {
+		p.remaining--
+		if p.remaining <= 0 {
+			pause := p.rand.duration(burstPauseMin, burstPauseMax)
+			p.pauseUntil = now.Add(pause)
+			p.remaining = p.rand.intn(burstSizeMin, burstSizeMax)
+			fmt.Printf("⏸️ Pacing: nghỉ %v sau một loạt request\n", pause.Round(time.Second))
+		}
+	}
+	p.mutex.Unlock()
+
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	// Hết thời gian nghỉ thì xếp hàng lại như một request mới
+	if err := sleepContext(ctx, wait); err != nil {
+		return err
+	}
+	return p.Wait(ctx)
+}
+
+// nightlyPacer adds a longer random delay during night hours
+type nightlyPacer struct {
+	rand               *randomSource
+	startHour, endHour int
+}
+
+func newNightlyPacer() *nightlyPacer {
+	return &nightlyPacer{rand: newRandomSource(), startHour: nightStartHour, endHour: nightEndHour}
+}
+
+// Wait sleeps only when the local time is inside the night window
+func (p *nightlyPacer) Wait(ctx context.Context) error {
+	if !inHourWindow(time.Now().Hour(), p.startHour, p.endHour) {
+		return ctx.Err()
+	}
+	return sleepContext(ctx, p.rand.duration(nightDelayMin, nightDelayMax))
+}
+
+// inHourWindow reports whether hour is in [start, end), wrapping past midnight
+func inHourWindow(hour, start, end int) bool {
+	if start <= end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// sleepContext sleeps for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -2,26 +2,48 @@ package crawler
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"sync"
 
 	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
 )
 
+// hitFilePath là file kết quả dùng để nạp cache chống ghi trùng
+var (
+	hitFilePath      = "hit.txt"
+	hitFilePathMutex sync.RWMutex
+)
+
+// SetHitFilePath changes the hit file used by new ProfileExtractor instances
+func SetHitFilePath(path string) {
+	if path == "" {
+		return
+	}
+	hitFilePathMutex.Lock()
+	hitFilePath = path
+	hitFilePathMutex.Unlock()
+}
+
 // ProfileExtractor handles LinkedIn profile data extraction
 type ProfileExtractor struct {
 	// Cache để tránh ghi trùng vào hit.txt
 	writtenProfiles map[string]bool
 	profilesMutex   sync.RWMutex
+	hitFilePath     string
 }
 
 // NewProfileExtractor creates a new ProfileExtractor instance
 func NewProfileExtractor() *ProfileExtractor {
+	hitFilePathMutex.RLock()
+	path := hitFilePath
+	hitFilePathMutex.RUnlock()
+
 	pe := &ProfileExtractor{
 		writtenProfiles: make(map[string]bool),
+		hitFilePath:     path,
 	}
 
 	// Load existing profiles from hit.txt để tránh ghi trùng
@@ -35,12 +57,25 @@ func NewProfileExtractorForCrawler(lc *models.LinkedInCrawler) *ProfileExtractor
 	return NewProfileExtractor()
 }
 
-// loadExistingProfiles loads existing emails from hit.txt to avoid duplicates
+// loadExistingProfiles loads existing emails from the hit file and its rotated
+// parts to avoid duplicates
 func (pe *ProfileExtractor) loadExistingProfiles() {
-	file, err := os.Open("hit.txt")
+	loadedCount := 0
+	for _, path := range utils.HitFileParts(pe.hitFilePath) {
+		loadedCount += pe.loadProfilesFromFile(path)
+	}
+
+	if loadedCount > 0 {
+		fmt.Printf("🔄 ProfileExtractor: Loaded %d existing profiles to prevent duplicates\n", loadedCount)
+	}
+}
+
+// loadProfilesFromFile marks the emails of one hit file as written
+func (pe *ProfileExtractor) loadProfilesFromFile(path string) int {
+	file, err := os.Open(path)
 	if err != nil {
 		// File doesn't exist, that's fine
-		return
+		return 0
 	}
 	defer file.Close()
 
@@ -53,7 +88,7 @@ func (pe *ProfileExtractor) loadExistingProfiles() {
 			continue
 		}
 
-		// Parse existing entries: email|name|url|location|connections
+		// Parse existing entries: email|name|url|location|connections[|source]
 		parts := strings.Split(line, "|")
 		if len(parts) >= 1 {
 			email := strings.TrimSpace(parts[0])
@@ -64,49 +99,13 @@ func (pe *ProfileExtractor) loadExistingProfiles() {
 		}
 	}
 
-	if loadedCount > 0 {
-		fmt.Printf("🔄 ProfileExtractor: Loaded %d existing profiles to prevent duplicates\n", loadedCount)
-	}
+	return loadedCount
 }
 
-// ExtractProfileData extracts LinkedIn profile data from response JSON
+// ExtractProfileData extracts LinkedIn profile data from response JSON.
+// The matching parser name is recorded in profile.Parser.
 func (pe *ProfileExtractor) ExtractProfileData(responseJSON []byte) (models.ProfileData, error) {
-	var data map[string]interface{}
-	var profile models.ProfileData
-
-	if err := json.Unmarshal(responseJSON, &data); err != nil {
-		return profile, err
-	}
-
-	persons, ok := data["persons"].([]interface{})
-	if !ok || len(persons) == 0 {
-		return profile, nil
-	}
-
-	p, ok := persons[0].(map[string]interface{})
-	if !ok {
-		return profile, nil
-	}
-
-	if val, ok := p["displayName"].(string); ok {
-		profile.User = val
-	}
-
-	if val, ok := p["linkedInUrl"].(string); ok {
-		profile.LinkedInURL = val
-	}
-
-	if val, ok := p["connectionCount"].(string); ok {
-		profile.ConnectionCount = val
-	} else if val, ok := p["connectionCount"].(float64); ok {
-		profile.ConnectionCount = fmt.Sprintf("%d", int(val))
-	}
-
-	if val, ok := p["location"].(string); ok {
-		profile.Location = val
-	}
-
-	return profile, nil
+	return defaultParserRegistry.Parse(responseJSON)
 }
 
 // WriteProfileToFile writes profile data to output file with duplicate prevention
@@ -138,7 +137,12 @@ func (pe *ProfileExtractor) WriteProfileToFile(lc *models.LinkedInCrawler, email
 	}
 
 	// APPEND mode - ghi thêm vào file hit.txt (KHÔNG ghi đè)
-	line := fmt.Sprintf("%s|%s|%s|%s|%s\n", email, profile.User, profile.LinkedInURL, profile.Location, profile.ConnectionCount)
+	source := profile.Source
+	if source == "" {
+		source = models.SourceEmail
+	}
+	linkedInURL := utils.NormalizeLinkedInURL(profile.LinkedInURL)
+	line := fmt.Sprintf("%s|%s|%s|%s|%s|%s\n", email, profile.User, linkedInURL, profile.Location, profile.ConnectionCount, source)
 	_, err := lc.BufferedWriter.WriteString(line)
 	if err != nil {
 		return fmt.Errorf("failed to write to output file: %w", err)
@@ -228,6 +232,7 @@ func (pe *ProfileExtractor) GetCacheStats() map[string]interface{} {
 	stats := map[string]interface{}{
 		"total_profiles_cached": len(pe.writtenProfiles),
 		"cache_enabled":         true,
+		"parser_matches":        GetParserStats(),
 	}
 
 	return stats
@@ -0,0 +1,295 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"linkedin-crawler/internal/models"
+)
+
+// ProfileParser extracts profile data from one response shape.
+// Parse returns ok=false when the payload does not match its shape.
+type ProfileParser interface {
+	Name() string
+	Parse(body []byte) (profile models.ProfileData, ok bool, err error)
+}
+
+// ParserRegistry tries registered parsers in order until one matches
+type ParserRegistry struct {
+	parsers     []ProfileParser
+	matchCounts map[string]int
+	mutex       sync.RWMutex
+}
+
+// NewParserRegistry creates a registry with the built-in parsers
+func NewParserRegistry() *ParserRegistry {
+	pr := &ParserRegistry{
+		matchCounts: make(map[string]int),
+	}
+
+	pr.Register(&personsV1Parser{})
+	pr.Register(&profileV2Parser{})
+	pr.Register(&regexFallbackParser{})
+
+	return pr
+}
+
+// defaultParserRegistry is shared by all ProfileExtractor instances
+var defaultParserRegistry = NewParserRegistry()
+
+// RegisterProfileParser adds a parser to the default registry
+func RegisterProfileParser(p ProfileParser) {
+	defaultParserRegistry.Register(p)
+}
+
+// GetParserStats returns match counts of the default registry
+func GetParserStats() map[string]int {
+	return defaultParserRegistry.GetMatchStats()
+}
+
+// Register appends a parser. Parsers registered later are tried later,
+// except the regex fallback which always stays last.
+func (pr *ParserRegistry) Register(p ProfileParser) {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	n := len(pr.parsers)
+	if n > 0 && pr.parsers[n-1].Name() == "regex" && p.Name() != "regex" {
+		pr.parsers = append(pr.parsers[:n-1], p, pr.parsers[n-1])
+		return
+	}
+	pr.parsers = append(pr.parsers, p)
+}
+
+// Parsers returns names of registered parsers in try order
+func (pr *ParserRegistry) Parsers() []string {
+	pr.mutex.RLock()
+	defer pr.mutex.RUnlock()
+
+	names := make([]string, 0, len(pr.parsers))
+	for _, p := range pr.parsers {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// Parse runs parsers in order and returns the first match.
+// An empty profile with nil error means the response has no LinkedIn data.
+func (pr *ParserRegistry) Parse(body []byte) (models.ProfileData, error) {
+	pr.mutex.RLock()
+	parsers := make([]ProfileParser, len(pr.parsers))
+	copy(parsers, pr.parsers)
+	pr.mutex.RUnlock()
+
+	var firstErr error
+	for _, p := range parsers {
+		profile, ok, err := p.Parse(body)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s parser: %w", p.Name(), err)
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		profile.Parser = p.Name()
+
+		pr.mutex.Lock()
+		pr.matchCounts[p.Name()]++
+		pr.mutex.Unlock()
+
+		return profile, nil
+	}
+
+	if firstErr != nil && !json.Valid(body) {
+		return models.ProfileData{}, firstErr
+	}
+
+	pr.mutex.Lock()
+	pr.matchCounts["none"]++
+	pr.mutex.Unlock()
+
+	return models.ProfileData{}, nil
+}
+
+// GetMatchStats returns how many responses each parser matched
+func (pr *ParserRegistry) GetMatchStats() map[string]int {
+	pr.mutex.RLock()
+	defer pr.mutex.RUnlock()
+
+	stats := make(map[string]int, len(pr.matchCounts))
+	for name, count := range pr.matchCounts {
+		stats[name] = count
+	}
+	return stats
+}
+
+// personsV1Parser handles the original {"persons":[{...}]} payload
+type personsV1Parser struct{}
+
+func (p *personsV1Parser) Name() string { return "v1" }
+
+func (p *personsV1Parser) Parse(body []byte) (models.ProfileData, bool, error) {
+	var profile models.ProfileData
+	var data map[string]interface{}
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return profile, false, err
+	}
+
+	persons, ok := data["persons"].([]interface{})
+	if !ok || len(persons) == 0 {
+		return profile, false, nil
+	}
+
+	person, ok := persons[0].(map[string]interface{})
+	if !ok {
+		return profile, false, nil
+	}
+
+	profile.User = stringField(person, "displayName")
+	profile.LinkedInURL = stringField(person, "linkedInUrl")
+	profile.ConnectionCount = numberField(person, "connectionCount")
+	profile.Location = stringField(person, "location")
+	profile.Headline = stringField(person, "headline")
+	profile.Company = firstString(person, "companyName", "company")
+
+	return profile, true, nil
+}
+
+// profileV2Parser handles payloads that wrap the person in a "profile"
+// or "person" object and use the newer field names
+type profileV2Parser struct{}
+
+func (p *profileV2Parser) Name() string { return "v2" }
+
+func (p *profileV2Parser) Parse(body []byte) (models.ProfileData, bool, error) {
+	var profile models.ProfileData
+	var data map[string]interface{}
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return profile, false, err
+	}
+
+	var person map[string]interface{}
+	for _, key := range []string{"profile", "person", "linkedInProfile"} {
+		if val, ok := data[key].(map[string]interface{}); ok {
+			person = val
+			break
+		}
+	}
+	if person == nil {
+		return profile, false, nil
+	}
+
+	profile.User = firstString(person, "displayName", "fullName", "name")
+	profile.LinkedInURL = firstString(person, "linkedInUrl", "publicProfileUrl", "profileUrl")
+	profile.ConnectionCount = numberField(person, "connectionCount")
+	if profile.ConnectionCount == "" {
+		profile.ConnectionCount = numberField(person, "connections")
+	}
+	profile.Location = firstString(person, "location", "locationName")
+
+	if loc, ok := person["location"].(map[string]interface{}); ok && profile.Location == "" {
+		profile.Location = firstString(loc, "displayName", "name")
+	}
+	profile.Headline = firstString(person, "headline", "title")
+	profile.Company = firstString(person, "companyName", "company", "currentCompany")
+
+	if profile.User == "" && profile.LinkedInURL == "" {
+		return profile, false, nil
+	}
+
+	return profile, true, nil
+}
+
+var (
+	reDisplayName = regexp.MustCompile(`"displayName"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+	reLinkedInURL = regexp.MustCompile(`"(?:linkedInUrl|publicProfileUrl)"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+	reConnections = regexp.MustCompile(`"connectionCount"\s*:\s*"?(\d+\+?)"?`)
+	reLocation    = regexp.MustCompile(`"location"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+)
+
+// structuredKeys are the top-level keys the v1 and v2 parsers read
+var structuredKeys = []string{"persons", "profile", "person", "linkedInProfile"}
+
+// regexFallbackParser scans the raw body when no structured parser matched.
+// It only guesses on bodies that are not JSON or have none of structuredKeys:
+// a known payload the structured parsers rejected (e.g. "persons": []) is a
+// miss, whatever "displayName" it contains elsewhere.
+type regexFallbackParser struct{}
+
+func (p *regexFallbackParser) Name() string { return "regex" }
+
+func (p *regexFallbackParser) Parse(body []byte) (models.ProfileData, bool, error) {
+	var profile models.ProfileData
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(body, &data); err == nil {
+		for _, key := range structuredKeys {
+			if _, ok := data[key]; ok {
+				return profile, false, nil
+			}
+		}
+	}
+
+	match := reDisplayName.FindSubmatch(body)
+	if match == nil {
+		return profile, false, nil
+	}
+
+	profile.User = unescapeJSONString(match[1])
+	if m := reLinkedInURL.FindSubmatch(body); m != nil {
+		profile.LinkedInURL = unescapeJSONString(m[1])
+	}
+	if m := reConnections.FindSubmatch(body); m != nil {
+		profile.ConnectionCount = string(m[1])
+	}
+	if m := reLocation.FindSubmatch(body); m != nil {
+		profile.Location = unescapeJSONString(m[1])
+	}
+
+	return profile, true, nil
+}
+
+// stringField returns m[key] if it is a string
+func stringField(m map[string]interface{}, key string) string {
+	if val, ok := m[key].(string); ok {
+		return val
+	}
+	return ""
+}
+
+// firstString returns the first non-empty string among keys
+func firstString(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if val := strings.TrimSpace(stringField(m, key)); val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
+// numberField returns m[key] formatted as string whether it is a string or number
+func numberField(m map[string]interface{}, key string) string {
+	if val, ok := m[key].(string); ok {
+		return val
+	} else if val, ok := m[key].(float64); ok {
+		return fmt.Sprintf("%d", int(val))
+	}
+	return ""
+}
+
+// unescapeJSONString decodes a quoted JSON string fragment
+func unescapeJSONString(raw []byte) string {
+	var s string
+	if err := json.Unmarshal([]byte(`"`+string(raw)+`"`), &s); err != nil {
+		return string(raw)
+	}
+	return s
+}
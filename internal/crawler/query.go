@@ -2,8 +2,10 @@ package crawler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 	"sync/atomic"
@@ -20,6 +22,9 @@ type QueryService struct {
 	tokenManager     *TokenManager
 	profileExtractor *ProfileExtractor
 	tokenStorage     *storage.TokenStorage
+	capture          *ResponseCapture // nil khi capture mode tắt
+	simulator        *Simulator       // nil khi không chạy giả lập
+	endpoint         Endpoint
 }
 
 // NewQueryService creates a new QueryService instance
@@ -28,11 +33,58 @@ func NewQueryService() *QueryService {
 		tokenManager:     &TokenManager{},
 		profileExtractor: NewProfileExtractor(),
 		tokenStorage:     storage.NewTokenStorage(),
+		endpoint:         DefaultEndpoint(),
+	}
+}
+
+// SetEndpoint changes where requests are sent (see Endpoint)
+func (qs *QueryService) SetEndpoint(endpoint Endpoint) {
+	qs.endpoint = endpoint
+}
+
+// SetCapture enables debug capture of failing requests
+func (qs *QueryService) SetCapture(capture *ResponseCapture) {
+	qs.capture = capture
+}
+
+// GetCapture returns the active capture, or nil when disabled
+func (qs *QueryService) GetCapture() *ResponseCapture {
+	return qs.capture
+}
+
+// queryFunc performs one request with the given token
+type queryFunc func(token string) (bool, []byte, int, error)
+
+// tokenRecorderKey is the context key for WithTokenRecorder
+type tokenRecorderKey struct{}
+
+// WithTokenRecorder returns a context that stores the last token used by a
+// query into dst, so callers can attribute the result to a token
+func WithTokenRecorder(ctx context.Context, dst *string) context.Context {
+	return context.WithValue(ctx, tokenRecorderKey{}, dst)
+}
+
+// recordingQuery wraps do so every attempt updates the context's token recorder
+func recordingQuery(ctx context.Context, do queryFunc) queryFunc {
+	dst, ok := ctx.Value(tokenRecorderKey{}).(*string)
+	if !ok || dst == nil {
+		return do
+	}
+	return func(token string) (bool, []byte, int, error) {
+		*dst = token
+		return do(token)
 	}
 }
 
 // QueryProfileWithRetryLogic queries LinkedIn profile with retry logic and token switching
 func (qs *QueryService) QueryProfileWithRetryLogic(lc *models.LinkedInCrawler, ctx context.Context, email string) (bool, []byte, int, error) {
+	return qs.queryWithRetryLogic(lc, ctx, func(token string) (bool, []byte, int, error) {
+		return qs.doQueryProfile(lc, ctx, email, token)
+	})
+}
+
+// queryWithRetryLogic applies rate limiting, concurrency limits and token switching around do
+func (qs *QueryService) queryWithRetryLogic(lc *models.LinkedInCrawler, ctx context.Context, do queryFunc) (bool, []byte, int, error) {
 	if qs.tokenManager.AreAllTokensFailed(lc) {
 		return false, nil, 0, fmt.Errorf("all tokens have failed")
 	}
@@ -59,9 +111,11 @@ func (qs *QueryService) QueryProfileWithRetryLogic(lc *models.LinkedInCrawler, c
 		atomic.AddInt32(&lc.ActiveRequests, -1)
 	}()
 
+	do = recordingQuery(ctx, do)
+
 	// Thử với token đầu tiên
 	token := qs.tokenManager.GetToken(lc)
-	hasProfile, body, statusCode, err := qs.doQueryProfile(lc, ctx, email, token)
+	hasProfile, body, statusCode, err := do(token)
 
 	// Xử lý logic token switching đặc biệt cho 429
 	if statusCode == 429 {
@@ -77,12 +131,12 @@ func (qs *QueryService) QueryProfileWithRetryLogic(lc *models.LinkedInCrawler, c
 			// Thử với token khác
 			newToken := qs.tokenManager.GetToken(lc)
 			if newToken != "" && newToken != token {
-				hasProfile, body, statusCode, err = qs.doQueryProfile(lc, ctx, email, newToken)
+				hasProfile, body, statusCode, err = do(newToken)
 			}
 		} else {
 			time.Sleep(1 * time.Second)
 			// Thử lại với cùng token
-			hasProfile, body, statusCode, err = qs.doQueryProfile(lc, ctx, email, token)
+			hasProfile, body, statusCode, err = do(token)
 		}
 	} else if statusCode == 401 || statusCode == 424 {
 		// Xóa token không hợp lệ khỏi file
@@ -102,7 +156,7 @@ func (qs *QueryService) QueryProfileWithRetryLogic(lc *models.LinkedInCrawler, c
 		// Thử với token khác
 		newToken := qs.tokenManager.GetToken(lc)
 		if newToken != "" {
-			hasProfile, body, statusCode, err = qs.doQueryProfile(lc, ctx, email, newToken)
+			hasProfile, body, statusCode, err = do(newToken)
 		}
 	}
 
@@ -122,7 +176,7 @@ func (qs *QueryService) doQueryProfile(lc *models.LinkedInCrawler, ctx context.C
 	correlationID := uuid.New().String()
 	clientCorrelationID := uuid.New().String()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://eur.loki.delve.office.com/api/v1/linkedin/profiles/full", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", qs.endpoint.ProfileURL(), nil)
 	if err != nil {
 		return false, nil, 0, err
 	}
@@ -135,19 +189,13 @@ func (qs *QueryService) doQueryProfile(lc *models.LinkedInCrawler, ctx context.C
 	q.Add("UserLocale", "en-US")
 	q.Add("ExternalPageInstance", "0000-0000-0000-0000-0000")
 	q.Add("PersonaType", "User")
-	req.URL.RawQuery = q.Encode()
-
-	req.Header.Add("Authorization", authHeader)
-	req.Header.Add("X-ClientFeature", "LivePersonaCard")
-	req.Header.Add("Accept", "text/plain, application/json, text/json")
-	req.Header.Add("X-ClientType", "OwaMail")
-	req.Header.Add("X-HostAppCapabilities", "{}")
-	req.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:57.0) Gecko/20100101 Firefox/57.0")
-	req.Header.Add("Connection", "keep-alive")
-	req.Header.Add("X-LPCVersion", "1.20210418.1.0")
+	qs.endpoint.prepare(req, q, authHeader)
 
 	resp, err := lc.Client.Do(req)
 	if err != nil {
+		if qs.capture != nil && ctx.Err() == nil {
+			qs.capture.CaptureResponse(email, "transport_error", req, 0, nil, nil, err)
+		}
 		return false, nil, 0, err
 	}
 	defer resp.Body.Close()
@@ -155,16 +203,11 @@ func (qs *QueryService) doQueryProfile(lc *models.LinkedInCrawler, ctx context.C
 	statusCode := resp.StatusCode
 
 	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusUnauthorized {
-			return false, nil, statusCode, fmt.Errorf("token authentication failed (401 Unauthorized): %s", resp.Status)
-		} else if resp.StatusCode == 424 {
-			return false, nil, statusCode, fmt.Errorf("token dependency failed (424 Failed Dependency): %s", resp.Status)
-		} else if resp.StatusCode == 429 {
-			return false, nil, statusCode, fmt.Errorf("rate limited (429 Too Many Requests): %s", resp.Status)
-		} else if resp.StatusCode == 500 {
-			return false, nil, statusCode, fmt.Errorf("internal server error (500): %s", resp.Status)
+		if qs.capture != nil {
+			errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxCapturedBodyBytes+1))
+			qs.capture.CaptureResponse(email, "http_error", req, statusCode, resp.Header, errBody, nil)
 		}
-		return false, nil, statusCode, fmt.Errorf("HTTP error: %s", resp.Status)
+		return false, nil, statusCode, statusError(resp)
 	}
 
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
@@ -176,3 +219,56 @@ func (qs *QueryService) doQueryProfile(lc *models.LinkedInCrawler, ctx context.C
 
 	return hasProfile, body, statusCode, nil
 }
+
+// addLokiHeaders sets the headers expected by the Loki persona endpoints
+func addLokiHeaders(req *http.Request, authHeader string) {
+	req.Header.Add("Authorization", authHeader)
+	req.Header.Add("X-ClientFeature", "LivePersonaCard")
+	req.Header.Add("Accept", "text/plain, application/json, text/json")
+	req.Header.Add("X-ClientType", "OwaMail")
+	req.Header.Add("X-HostAppCapabilities", "{}")
+	req.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:57.0) Gecko/20100101 Firefox/57.0")
+	req.Header.Add("Connection", "keep-alive")
+	req.Header.Add("X-LPCVersion", "1.20210418.1.0")
+}
+
+// statusError maps a non-200 response to a descriptive error
+func statusError(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("token authentication failed (401 Unauthorized): %s", resp.Status)
+	case 424:
+		return fmt.Errorf("token dependency failed (424 Failed Dependency): %s", resp.Status)
+	case 429:
+		return fmt.Errorf("rate limited (429 Too Many Requests): %s", resp.Status)
+	case 500:
+		return fmt.Errorf("internal server error (500): %s", resp.Status)
+	}
+	return fmt.Errorf("HTTP error: %s", resp.Status)
+}
+
+// ClassifyError returns the error class (models.EmailError*) of a failed
+// query from its status code and error
+func ClassifyError(statusCode int, err error) string {
+	switch {
+	case statusCode == 429:
+		return models.EmailErrorRateLimited
+	case statusCode == http.StatusForbidden:
+		return models.EmailErrorForbidden
+	case statusCode == http.StatusUnauthorized || statusCode == 424:
+		return models.EmailErrorAuth
+	case statusCode >= 500:
+		return models.EmailErrorServer
+	case statusCode != 0:
+		return models.EmailErrorHTTP
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return models.EmailErrorTimeout
+	}
+	if err != nil {
+		return models.EmailErrorNetwork
+	}
+	return models.EmailErrorUnknown
+}
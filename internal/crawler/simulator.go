@@ -0,0 +1,130 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+)
+
+// simulatedRateLimitRate is the share of simulated requests answered with 429
+const simulatedRateLimitRate = 0.01
+
+var (
+	simulatedFirstNames = []string{"Anna", "Minh", "David", "Linh", "Sarah", "Tuan", "Michael", "Lan", "Emma", "Huy"}
+	simulatedLastNames  = []string{"Nguyen", "Smith", "Tran", "Johnson", "Le", "Brown", "Pham", "Miller", "Hoang", "Davis"}
+	simulatedLocations  = []string{"Ho Chi Minh City, Vietnam", "Hanoi, Vietnam", "Singapore", "London, United Kingdom", "Berlin, Germany", "San Francisco Bay Area", "Sydney, Australia", "Tokyo, Japan"}
+)
+
+// Simulator answers queries with synthetic responses instead of calling the
+// API, so a crawl can run without accounts or network access
+type Simulator struct {
+	hitRate float64
+	latency time.Duration
+}
+
+// NewSimulator creates a simulator returning a profile for about hitRate
+// (0..1) of the targets, each after roughly latency
+func NewSimulator(hitRate float64, latency time.Duration) *Simulator {
+	return &Simulator{
+		hitRate: min(max(hitRate, 0), 1),
+		latency: latency,
+	}
+}
+
+// SimulatedTokens returns n placeholder tokens for a simulated crawl
+func SimulatedTokens(n int) []string {
+	tokens := make([]string, max(n, 1))
+	for i := range tokens {
+		tokens[i] = fmt.Sprintf("simulated-token-%d", i+1)
+	}
+	return tokens
+}
+
+// SetSimulator replaces all requests of the service with the simulator
+// (nil = real requests)
+func (qs *QueryService) SetSimulator(simulator *Simulator) {
+	qs.simulator = simulator
+}
+
+// Simulating reports whether the service answers with synthetic responses
+func (qs *QueryService) Simulating() bool {
+	return qs.simulator != nil
+}
+
+// QuerySimulatedWithRetryLogic answers target from the simulator with the
+// same rate limiting, concurrency limit and token switching as a real query
+func (qs *QueryService) QuerySimulatedWithRetryLogic(lc *models.LinkedInCrawler, ctx context.Context, target string) (bool, []byte, int, error) {
+	return qs.queryWithRetryLogic(lc, ctx, func(token string) (bool, []byte, int, error) {
+		return qs.simulator.respond(ctx, target)
+	})
+}
+
+// respond waits for the simulated latency and returns a response shaped like
+// the profiles API. Whether target is a hit, and the profile returned, only
+// depend on target, so reruns give the same results.
+func (s *Simulator) respond(ctx context.Context, target string) (bool, []byte, int, error) {
+	if s.latency > 0 {
+		// Độ trễ dao động 50%-150% để giống request thật
+		delay := time.Duration(float64(s.latency) * (0.5 + rand.Float64()))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return false, nil, 0, ctx.Err()
+		}
+	}
+
+	if rand.Float64() < simulatedRateLimitRate {
+		return false, nil, 429, fmt.Errorf("rate limited (429 Too Many Requests): simulated")
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(strings.ToLower(strings.TrimSpace(target))))
+	rng := rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	if rng.Float64() >= s.hitRate {
+		return false, []byte(`{"persons":[]}`), 200, nil
+	}
+
+	name := simulatedName(target, rng)
+	slug := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+	person := map[string]interface{}{
+		"displayName":     name,
+		"linkedInUrl":     fmt.Sprintf("https://www.linkedin.com/in/%s-%04x", slug, rng.Intn(0x10000)),
+		"location":        simulatedLocations[rng.Intn(len(simulatedLocations))],
+		"connectionCount": 50 + rng.Intn(450),
+	}
+	body, err := json.Marshal(map[string]interface{}{"persons": []interface{}{person}})
+	if err != nil {
+		return false, nil, 0, err
+	}
+	return true, body, 200, nil
+}
+
+// simulatedName derives a display name from the target: the local part of
+// an email, or a random name
+func simulatedName(target string, rng *rand.Rand) string {
+	var parts []string
+	if at := strings.Index(target, "@"); at > 0 {
+		parts = strings.FieldsFunc(target[:at], func(r rune) bool {
+			return r == '.' || r == '_' || r == '-' || (r >= '0' && r <= '9')
+		})
+	}
+	if len(parts) < 2 {
+		parts = []string{
+			simulatedFirstNames[rng.Intn(len(simulatedFirstNames))],
+			simulatedLastNames[rng.Intn(len(simulatedLastNames))],
+		}
+	}
+
+	for i, part := range parts {
+		runes := []rune(strings.ToLower(part))
+		parts[i] = strings.ToUpper(string(runes[:1])) + string(runes[1:])
+	}
+	return strings.Join(parts, " ")
+}
@@ -131,7 +131,7 @@ func (vs *ValidatorService) HasValidTokens(config models.Config, outputFile stri
 	validCount := 0
 	checkLimit := 3 // Only check first 3 tokens to save time
 
-	queryService := NewQueryService()
+	queryService := newQueryServiceFor(config)
 
 	for i, token := range existingTokens {
 		if i >= checkLimit {
@@ -175,7 +175,7 @@ func (vs *ValidatorService) ValidateExistingTokens(tokens []string, config model
 
 	fmt.Printf("🔍 Kiểm tra %d tokens với email test: %s\n", len(tokens), testEmail)
 
-	queryService := NewQueryService()
+	queryService := newQueryServiceFor(config)
 
 	for i, token := range tokens {
 		fmt.Printf("  🔑 Kiểm tra token %d/%d...\n", i+1, len(tokens))
@@ -228,7 +228,7 @@ func (vs *ValidatorService) ValidateTokensBatch(tokens []string, config models.C
 		testEmail = totalEmails[0]
 	}
 
-	queryService := NewQueryService()
+	queryService := newQueryServiceFor(config)
 
 	for i, token := range tokens {
 		fmt.Printf("    🔑 Kiểm tra token %d/%d...\n", i+1, len(tokens))
@@ -249,3 +249,12 @@ func (vs *ValidatorService) ValidateTokensBatch(tokens []string, config models.C
 
 	return validTokens, nil
 }
+
+// newQueryServiceFor creates a QueryService sending to the endpoint in config
+func newQueryServiceFor(config models.Config) *QueryService {
+	queryService := NewQueryService()
+	if endpoint, err := EndpointFromConfig(config); err == nil {
+		queryService.SetEndpoint(endpoint)
+	}
+	return queryService
+}
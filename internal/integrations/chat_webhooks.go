@@ -0,0 +1,90 @@
+package integrations
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Discord giới hạn content 2000 ký tự mỗi message
+const discordMaxContent = 2000
+
+// postJSON sends payload to a webhook URL and fails on a non-2xx response
+func postJSON(client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// SlackNotifier posts messages to a Slack incoming webhook
+type SlackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackNotifier creates a notifier for a Slack incoming webhook URL
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{url: url, client: &http.Client{Timeout: notifyTimeout}}
+}
+
+// Name returns "Slack"
+func (s *SlackNotifier) Name() string {
+	return "Slack"
+}
+
+// Accepts reports whether Slack gets events of eventType; per-hit events are
+// too noisy for a channel
+func (s *SlackNotifier) Accepts(eventType EventType) bool {
+	return eventType != EventProfileFound
+}
+
+// Notify posts the rendered message
+func (s *SlackNotifier) Notify(event Event, message string) error {
+	return postJSON(s.client, s.url, map[string]string{"text": message})
+}
+
+// DiscordNotifier posts messages to a Discord channel webhook
+type DiscordNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewDiscordNotifier creates a notifier for a Discord webhook URL
+func NewDiscordNotifier(url string) *DiscordNotifier {
+	return &DiscordNotifier{url: url, client: &http.Client{Timeout: notifyTimeout}}
+}
+
+// Name returns "Discord"
+func (d *DiscordNotifier) Name() string {
+	return "Discord"
+}
+
+// Accepts reports whether Discord gets events of eventType; per-hit events
+// are too noisy for a channel
+func (d *DiscordNotifier) Accepts(eventType EventType) bool {
+	return eventType != EventProfileFound
+}
+
+// Notify posts the rendered message, truncated to Discord's limit
+func (d *DiscordNotifier) Notify(event Event, message string) error {
+	if runes := []rune(message); len(runes) > discordMaxContent {
+		message = string(runes[:discordMaxContent-1]) + "…"
+	}
+	return postJSON(d.client, d.url, map[string]string{"content": message})
+}
@@ -0,0 +1,84 @@
+package integrations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	emailListTimeout = 5 * time.Minute
+	emailListMaxSize = 200 << 20 // 200 MB
+)
+
+// IsEmailListURL reports whether an --emails source is an HTTP(S) URL
+func IsEmailListURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// FetchEmailList downloads an email list and verifies its SHA-256. With an
+// empty checksum it looks for a "<url>.sha256" file next to the list; when
+// there is none the list is accepted unverified and verified is false.
+func FetchEmailList(rawURL, checksum string) (data []byte, verified bool, err error) {
+	client := &http.Client{Timeout: emailListTimeout}
+
+	data, err = fetchURL(client, rawURL, emailListMaxSize)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if checksum == "" {
+		sidecar, err := fetchURL(client, rawURL+".sha256", 4096)
+		if err != nil {
+			return data, false, nil
+		}
+		// Định dạng của sha256sum: "<hex>  <tên file>"
+		fields := strings.Fields(string(sidecar))
+		if len(fields) == 0 {
+			return nil, false, fmt.Errorf("empty checksum file %s.sha256", rawURL)
+		}
+		checksum = fields[0]
+	}
+
+	if err := VerifySHA256(data, checksum); err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// VerifySHA256 checks data against a hex SHA-256 checksum
+func VerifySHA256(data []byte, checksum string) error {
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(actual, strings.TrimSpace(checksum)) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", strings.TrimSpace(checksum), actual)
+	}
+	return nil
+}
+
+// fetchURL GETs url and returns the body, failing on a non-200 status or a
+// body larger than maxSize
+func fetchURL(client *http.Client, url string, maxSize int64) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%s is larger than %d MB", url, maxSize>>20)
+	}
+	return data, nil
+}
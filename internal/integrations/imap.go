@@ -0,0 +1,324 @@
+package integrations
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+const (
+	imapTimeout    = 2 * time.Minute
+	imapFetchBatch = 200 // Số message mỗi lệnh FETCH
+)
+
+// IMAPFields lists the header fields that can be harvested
+var IMAPFields = []string{"from", "to", "cc", "reply-to"}
+
+var imapLiteralRegex = regexp.MustCompile(`\{(\d+)\}$`)
+
+// IMAPHarvest is the result of harvesting a mailbox
+type IMAPHarvest struct {
+	Emails    []string // Đã lọc và dedupe, theo thứ tự gặp đầu tiên
+	Messages  int      // Số message đã đọc header
+	Addresses int      // Tổng số địa chỉ trong các header
+	Filtered  int      // Địa chỉ bị loại bởi filter (domain, exclude, chính mình)
+}
+
+// imapResponse is one server response line with the literals it carried
+type imapResponse struct {
+	line     string
+	literals [][]byte
+}
+
+// imapClient is a minimal IMAP4rev1 client: just enough to log in, search
+// a mailbox and fetch header fields over TLS
+type imapClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+// HarvestIMAP connects to the configured mailbox and returns the addresses in
+// the selected header fields of messages from the last IMAPSinceDays days,
+// filtered by IMAPDomains/IMAPExclude and deduplicated
+func HarvestIMAP(cfg models.Config) (*IMAPHarvest, error) {
+	if cfg.IMAPServer == "" || cfg.IMAPUsername == "" {
+		return nil, fmt.Errorf("no IMAP server or username configured")
+	}
+	fields, err := parseIMAPFields(cfg.IMAPFields)
+	if err != nil {
+		return nil, err
+	}
+
+	server := cfg.IMAPServer
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "993")
+	}
+	host, _, _ := net.SplitHostPort(server)
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", server, &tls.Config{ServerName: host})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to IMAP server: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(imapTimeout))
+
+	client := &imapClient{conn: conn, reader: bufio.NewReader(conn)}
+	if greeting, err := client.readLine(); err != nil {
+		return nil, fmt.Errorf("failed to read IMAP greeting: %w", err)
+	} else if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		return nil, fmt.Errorf("unexpected IMAP greeting: %s", greeting)
+	}
+
+	if _, err := client.command("LOGIN %s %s", imapQuote(cfg.IMAPUsername), imapQuote(cfg.IMAPPassword)); err != nil {
+		return nil, fmt.Errorf("IMAP login failed: %w", err)
+	}
+	defer client.command("LOGOUT")
+
+	mailbox := cfg.IMAPMailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	if _, err := client.command("EXAMINE %s", imapQuote(mailbox)); err != nil {
+		return nil, fmt.Errorf("failed to open mailbox %s: %w", mailbox, err)
+	}
+
+	criteria := "ALL"
+	if cfg.IMAPSinceDays > 0 {
+		since := time.Now().AddDate(0, 0, -cfg.IMAPSinceDays)
+		criteria = "SINCE " + since.Format("02-Jan-2006")
+	}
+	responses, err := client.command("UID SEARCH %s", criteria)
+	if err != nil {
+		return nil, fmt.Errorf("IMAP search failed: %w", err)
+	}
+	var uids []string
+	for _, resp := range responses {
+		if strings.HasPrefix(resp.line, "* SEARCH") {
+			uids = append(uids, strings.Fields(strings.TrimPrefix(resp.line, "* SEARCH"))...)
+		}
+	}
+	// Giữ các message mới nhất khi vượt giới hạn
+	if cfg.IMAPMaxMessages > 0 && len(uids) > cfg.IMAPMaxMessages {
+		uids = uids[len(uids)-cfg.IMAPMaxMessages:]
+	}
+
+	filter := newIMAPFilter(cfg)
+	harvest := &IMAPHarvest{}
+	seen := make(map[string]bool)
+	fetchItem := fmt.Sprintf("BODY.PEEK[HEADER.FIELDS (%s)]", strings.ToUpper(strings.Join(fields, " ")))
+
+	for start := 0; start < len(uids); start += imapFetchBatch {
+		end := min(start+imapFetchBatch, len(uids))
+		responses, err := client.command("UID FETCH %s (%s)", strings.Join(uids[start:end], ","), fetchItem)
+		if err != nil {
+			return nil, fmt.Errorf("IMAP fetch failed: %w", err)
+		}
+
+		for _, resp := range responses {
+			if !strings.Contains(resp.line, "FETCH") || len(resp.literals) == 0 {
+				continue
+			}
+			harvest.Messages++
+			for _, address := range headerAddresses(resp.literals[0], fields) {
+				harvest.Addresses++
+				if !filter.allow(address) {
+					harvest.Filtered++
+					continue
+				}
+				if !seen[address] {
+					seen[address] = true
+					harvest.Emails = append(harvest.Emails, address)
+				}
+			}
+		}
+	}
+
+	return harvest, nil
+}
+
+// parseIMAPFields splits a comma list of header fields and checks each one
+func parseIMAPFields(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return []string{"from"}, nil
+	}
+	var fields []string
+	for _, field := range strings.Split(list, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		valid := false
+		for _, f := range IMAPFields {
+			if field == f {
+				valid = true
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown IMAP header field %q (use %s)", field, strings.Join(IMAPFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// headerAddresses returns the lowercased addresses in the given header fields
+func headerAddresses(raw []byte, fields []string) []string {
+	// HEADER.FIELDS có thể thiếu dòng trống cuối khi message không có field nào
+	msg, err := mail.ReadMessage(io.MultiReader(bytes.NewReader(raw), strings.NewReader("\r\n\r\n")))
+	if err != nil {
+		return nil
+	}
+
+	var addresses []string
+	for _, field := range fields {
+		value := msg.Header.Get(field)
+		if value == "" {
+			continue
+		}
+		list, err := mail.ParseAddressList(value)
+		if err != nil {
+			// Header không chuẩn RFC 5322: lấy các chuỗi trông như email
+			for _, word := range strings.FieldsFunc(value, func(r rune) bool {
+				return r == ' ' || r == ',' || r == ';' || r == '<' || r == '>' || r == '"'
+			}) {
+				if utils.IsValidEmail(word) {
+					addresses = append(addresses, utils.NormalizeEmail(word))
+				}
+			}
+			continue
+		}
+		for _, addr := range list {
+			if utils.IsValidEmail(addr.Address) {
+				addresses = append(addresses, utils.NormalizeEmail(addr.Address))
+			}
+		}
+	}
+	return addresses
+}
+
+// imapFilter applies the domain and exclude filters
+type imapFilter struct {
+	self    string
+	domains []string
+	exclude []string
+}
+
+// newIMAPFilter builds the filter from the IMAP config
+func newIMAPFilter(cfg models.Config) imapFilter {
+	split := func(list string) []string {
+		var items []string
+		for _, item := range strings.Split(list, ",") {
+			if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+				items = append(items, strings.TrimPrefix(item, "@"))
+			}
+		}
+		return items
+	}
+	return imapFilter{
+		self:    utils.NormalizeEmail(cfg.IMAPUsername),
+		domains: split(cfg.IMAPDomains),
+		exclude: split(cfg.IMAPExclude),
+	}
+}
+
+// allow reports whether address passes the filters
+func (f imapFilter) allow(address string) bool {
+	if address == f.self {
+		return false
+	}
+	for _, pattern := range f.exclude {
+		if strings.Contains(address, pattern) {
+			return false
+		}
+	}
+	if len(f.domains) == 0 {
+		return true
+	}
+	domain := address[strings.LastIndex(address, "@")+1:]
+	for _, d := range f.domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// imapQuote returns s as an IMAP quoted string
+func imapQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// readLine reads one CRLF terminated line
+func (c *imapClient) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readResponse reads one response, following {n} literals to the end of it
+func (c *imapClient) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	var line strings.Builder
+	for {
+		part, err := c.readLine()
+		if err != nil {
+			return resp, err
+		}
+		line.WriteString(part)
+
+		match := imapLiteralRegex.FindStringSubmatch(part)
+		if match == nil {
+			break
+		}
+		size, err := strconv.Atoi(match[1])
+		if err != nil {
+			return resp, fmt.Errorf("invalid literal size: %w", err)
+		}
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.reader, literal); err != nil {
+			return resp, err
+		}
+		resp.literals = append(resp.literals, literal)
+	}
+	resp.line = line.String()
+	return resp, nil
+}
+
+// command sends a tagged command and returns the untagged responses; a NO or
+// BAD completion is returned as an error
+func (c *imapClient) command(format string, args ...interface{}) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%03d", c.tag)
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, err
+	}
+
+	var responses []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return responses, err
+		}
+		if !strings.HasPrefix(resp.line, tag+" ") {
+			responses = append(responses, resp)
+			continue
+		}
+		status := strings.TrimPrefix(resp.line, tag+" ")
+		if !strings.HasPrefix(status, "OK") {
+			return responses, fmt.Errorf("%s", status)
+		}
+		return responses, nil
+	}
+}
@@ -0,0 +1,65 @@
+package integrations
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// Milestones tracks the milestone rules of a run: every N processed emails,
+// every N hits and every fixed interval. Each milestone is reported once,
+// also when a check skips over it.
+type Milestones struct {
+	everyProcessed int
+	everyHits      int
+	interval       time.Duration
+
+	mutex         sync.Mutex
+	lastProcessed int // Số emails ở milestone processed gần nhất
+	lastHits      int // Số hit ở milestone hits gần nhất
+	lastTime      time.Time
+}
+
+// NewMilestones creates the milestone rules of cfg; it returns nil when every
+// rule is off
+func NewMilestones(cfg models.Config) *Milestones {
+	if cfg.MilestoneEveryProcessed <= 0 && cfg.NotifyEveryHits <= 0 && cfg.MilestoneInterval <= 0 {
+		return nil
+	}
+	return &Milestones{
+		everyProcessed: cfg.MilestoneEveryProcessed,
+		everyHits:      cfg.NotifyEveryHits,
+		interval:       cfg.MilestoneInterval,
+		lastTime:       time.Now(),
+	}
+}
+
+// Reached returns the rules crossed since the last milestone, e.g.
+// "1000 processed, 15m0s interval", or "" when none was
+func (m *Milestones) Reached(processed, hits int, now time.Time) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var reasons []string
+	if m.everyProcessed > 0 {
+		if milestone := processed / m.everyProcessed * m.everyProcessed; milestone > m.lastProcessed {
+			m.lastProcessed = milestone
+			reasons = append(reasons, fmt.Sprintf("%d processed", milestone))
+		}
+	}
+	if m.everyHits > 0 {
+		if milestone := hits / m.everyHits * m.everyHits; milestone > m.lastHits {
+			m.lastHits = milestone
+			reasons = append(reasons, fmt.Sprintf("%d hits", milestone))
+		}
+	}
+	if m.interval > 0 && now.Sub(m.lastTime) >= m.interval {
+		m.lastTime = now
+		reasons = append(reasons, utils.FormatDuration(m.interval)+" interval")
+	}
+	return strings.Join(reasons, ", ")
+}
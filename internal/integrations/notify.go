@@ -0,0 +1,216 @@
+package integrations
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// EventType is the kind of crawl event sent to notifiers
+type EventType string
+
+const (
+	EventStarted   EventType = "crawl.started"
+	EventMilestone EventType = "crawl.milestone"
+	EventCompleted EventType = "crawl.completed"
+	EventError     EventType = "crawl.error"
+
+	// Mỗi hit, chỉ gửi tới outbound webhook (không có message template)
+	EventProfileFound EventType = "profile.found"
+)
+
+// EventTypes lists the event types that have a message template, in the
+// order they are shown to users
+var EventTypes = []EventType{EventStarted, EventMilestone, EventCompleted, EventError}
+
+const notifyTimeout = 15 * time.Second
+
+// Event is one crawl event
+type Event struct {
+	Type      EventType
+	Time      time.Time
+	RunID     string
+	Campaign  string
+	CrawlMode string
+	Total     int // Emails của lần chạy
+	Processed int
+	Hits      int
+	Failed    int
+	Duration  time.Duration
+	Stopped   bool // Người dùng dừng trước khi xong
+	Error     string
+	Milestone string           // Luật milestone vừa đạt, vd "1000 processed"
+	Profile   *utils.HitResult // Chỉ có với profile.found
+}
+
+// HitRate returns the percentage of processed emails that had a profile
+func (e Event) HitRate() float64 {
+	if e.Processed == 0 {
+		return 0
+	}
+	return float64(e.Hits) * 100 / float64(e.Processed)
+}
+
+// DurationText returns the run duration for messages
+func (e Event) DurationText() string {
+	return utils.FormatDuration(e.Duration)
+}
+
+// Notifier delivers crawl events to one destination
+type Notifier interface {
+	Name() string
+	Accepts(eventType EventType) bool
+	Notify(event Event, message string) error
+}
+
+// DefaultNotifyTemplates are the built-in messages; users override them in
+// the templates file (text/template, fields of Event)
+var DefaultNotifyTemplates = map[EventType]string{
+	EventStarted:   "🚀 Crawl {{.RunID}} started ({{.Campaign}}, {{.CrawlMode}}): {{.Total}} emails",
+	EventMilestone: "🎯 {{if .Milestone}}[{{.Milestone}}] {{end}}{{.Hits}} profiles found - {{.Processed}}/{{.Total}} processed ({{printf \"%.1f\" .HitRate}}% hit rate)",
+	EventCompleted: "🎉 Crawl {{.RunID}} {{if .Stopped}}stopped{{else}}finished{{end}} after {{.DurationText}}: {{.Hits}} profiles from {{.Processed}} emails ({{printf \"%.1f\" .HitRate}}%), {{.Failed}} failed",
+	EventError:     "❌ Crawl {{.RunID}} error: {{.Error}}",
+}
+
+// LoadNotifyTemplates reads the templates file; events missing from the file
+// (or a missing file) use DefaultNotifyTemplates
+func LoadNotifyTemplates(path string) (map[EventType]string, error) {
+	templates := make(map[EventType]string, len(DefaultNotifyTemplates))
+	for eventType, text := range DefaultNotifyTemplates {
+		templates[eventType] = text
+	}
+	if path == "" {
+		return templates, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return templates, nil
+	}
+	if err != nil {
+		return templates, fmt.Errorf("failed to read notification templates: %w", err)
+	}
+
+	var custom map[EventType]string
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return templates, fmt.Errorf("invalid notification templates file: %w", err)
+	}
+	for eventType, text := range custom {
+		if strings.TrimSpace(text) != "" {
+			templates[eventType] = text
+		}
+	}
+	return templates, nil
+}
+
+// SaveNotifyTemplates validates and writes the templates file
+func SaveNotifyTemplates(path string, templates map[EventType]string) error {
+	for eventType, text := range templates {
+		if _, err := RenderNotifyTemplate(text, Event{Type: eventType}); err != nil {
+			return fmt.Errorf("%s: %w", eventType, err)
+		}
+	}
+	data, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode notification templates: %w", err)
+	}
+	return utils.WriteFileAtomic(path, data)
+}
+
+// RenderNotifyTemplate renders one message template for event
+func RenderNotifyTemplate(text string, event Event) (string, error) {
+	tmpl, err := template.New(string(event.Type)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, event); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// Notifications sends crawl events to every configured notifier. Sending is
+// asynchronous so a slow webhook never holds up the crawl; Wait blocks until
+// the queued events are delivered.
+type Notifications struct {
+	notifiers []Notifier
+	templates map[EventType]string
+
+	wg sync.WaitGroup
+}
+
+// NewNotifications creates the notifiers configured in cfg; it returns nil
+// when none is configured
+func NewNotifications(cfg models.Config) *Notifications {
+	var notifiers []Notifier
+	if cfg.SlackWebhookURL != "" {
+		notifiers = append(notifiers, NewSlackNotifier(cfg.SlackWebhookURL))
+	}
+	if cfg.DiscordWebhookURL != "" {
+		notifiers = append(notifiers, NewDiscordNotifier(cfg.DiscordWebhookURL))
+	}
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookHitEvents))
+	}
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	templates, err := LoadNotifyTemplates(cfg.NotifyTemplates)
+	if err != nil {
+		fmt.Printf("⚠️ %v - dùng template mặc định\n", err)
+	}
+	return &Notifications{
+		notifiers: notifiers,
+		templates: templates,
+	}
+}
+
+// Notifiers returns the names of the configured notifiers
+func (n *Notifications) Notifiers() []string {
+	names := make([]string, len(n.notifiers))
+	for i, notifier := range n.notifiers {
+		names[i] = notifier.Name()
+	}
+	return names
+}
+
+// Notify renders event and sends it to every notifier in the background
+func (n *Notifications) Notify(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	message, err := RenderNotifyTemplate(n.templates[event.Type], event)
+	if err != nil && DefaultNotifyTemplates[event.Type] != "" {
+		// Template người dùng lỗi: vẫn gửi bằng template mặc định
+		fmt.Printf("⚠️ Notification template %s: %v\n", event.Type, err)
+		message, _ = RenderNotifyTemplate(DefaultNotifyTemplates[event.Type], event)
+	}
+
+	for _, notifier := range n.notifiers {
+		if !notifier.Accepts(event.Type) {
+			continue
+		}
+		n.wg.Add(1)
+		go func(notifier Notifier) {
+			defer n.wg.Done()
+			if err := notifier.Notify(event, message); err != nil {
+				fmt.Printf("⚠️ Không gửi được thông báo %s qua %s: %v\n", event.Type, notifier.Name(), err)
+			}
+		}(notifier)
+	}
+}
+
+// Wait blocks until every queued notification was sent or failed
+func (n *Notifications) Wait() {
+	n.wg.Wait()
+}
@@ -0,0 +1,191 @@
+package integrations
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/utils"
+)
+
+// WebhookSchemaVersion is bumped whenever a field of WebhookPayload changes meaning
+const WebhookSchemaVersion = 1
+
+// WebhookPayload is the JSON body of every outbound webhook request. All
+// fields are always present (empty when they don't apply) so tools like
+// Zapier and Make see the same structure for every event.
+type WebhookPayload struct {
+	SchemaVersion int            `json:"schema_version"`
+	EventID       string         `json:"event_id"`
+	Event         EventType      `json:"event"`
+	OccurredAt    string         `json:"occurred_at"` // RFC 3339, UTC
+	RunID         string         `json:"run_id"`
+	Campaign      string         `json:"campaign"`
+	CrawlMode     string         `json:"crawl_mode"`
+	Message       string         `json:"message"` // Nội dung đã render từ template (rỗng với profile.found)
+	Error         string         `json:"error"`
+	Stats         WebhookStats   `json:"stats"`
+	Profile       WebhookProfile `json:"profile"`
+}
+
+// WebhookStats is the run progress at the time of the event
+type WebhookStats struct {
+	Total           int     `json:"total"`
+	Processed       int     `json:"processed"`
+	Hits            int     `json:"hits"`
+	Failed          int     `json:"failed"`
+	HitRate         float64 `json:"hit_rate"` // Phần trăm, làm tròn 1 chữ số
+	DurationSeconds int64   `json:"duration_seconds"`
+	Stopped         bool    `json:"stopped"`
+}
+
+// WebhookProfile is the profile found for an email (profile.found only)
+type WebhookProfile struct {
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	LinkedInURL string `json:"linkedin_url"`
+	Location    string `json:"location"`
+	Connections string `json:"connections"`
+	Source      string `json:"source"`
+	FoundAt     string `json:"found_at"` // RFC 3339, UTC
+	// Cột thêm của dòng input (lead id, owner...), {} khi file input không có header
+	Fields map[string]string `json:"fields"`
+}
+
+// NewWebhookPayload converts an event to the outbound schema
+func NewWebhookPayload(event Event, message string) WebhookPayload {
+	payload := WebhookPayload{
+		SchemaVersion: WebhookSchemaVersion,
+		EventID:       newEventID(),
+		Event:         event.Type,
+		OccurredAt:    event.Time.UTC().Format(time.RFC3339),
+		RunID:         event.RunID,
+		Campaign:      event.Campaign,
+		CrawlMode:     event.CrawlMode,
+		Message:       message,
+		Error:         event.Error,
+		Stats: WebhookStats{
+			Total:           event.Total,
+			Processed:       event.Processed,
+			Hits:            event.Hits,
+			Failed:          event.Failed,
+			HitRate:         float64(int(event.HitRate()*10+0.5)) / 10,
+			DurationSeconds: int64(event.Duration / time.Second),
+			Stopped:         event.Stopped,
+		},
+	}
+	if event.Profile != nil {
+		payload.Profile = WebhookProfile{
+			Email:       event.Profile.Email,
+			Name:        event.Profile.Name,
+			LinkedInURL: event.Profile.LinkedInURL,
+			Location:    event.Profile.Location,
+			Connections: event.Profile.Connections,
+			Source:      event.Profile.Source,
+			FoundAt:     event.Profile.Timestamp.UTC().Format(time.RFC3339),
+			Fields:      event.Profile.Fields,
+		}
+	}
+	if payload.Profile.Fields == nil {
+		payload.Profile.Fields = map[string]string{}
+	}
+	return payload
+}
+
+// newEventID returns a random ID so receivers can drop duplicate deliveries
+func newEventID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// SampleWebhookEvent returns a profile.found event with every field filled,
+// used by the test-fire button so Zapier/Make can learn the structure
+func SampleWebhookEvent() Event {
+	now := time.Now()
+	return Event{
+		Type:      EventProfileFound,
+		Time:      now,
+		RunID:     "20060102-150405",
+		Campaign:  "test",
+		CrawlMode: "email",
+		Total:     100,
+		Processed: 40,
+		Hits:      12,
+		Failed:    1,
+		Duration:  5 * time.Minute,
+		Profile: &utils.HitResult{
+			Email:       "jane.doe@example.com",
+			Name:        "Jane Doe",
+			LinkedInURL: "https://www.linkedin.com/in/jane-doe",
+			Location:    "Ho Chi Minh City, Vietnam",
+			Connections: "500+",
+			Source:      "test",
+			Timestamp:   now,
+			Fields:      map[string]string{"lead_id": "00Q5e00000AbCdE", "owner": "john"},
+		},
+	}
+}
+
+// WebhookNotifier posts every event as a WebhookPayload to a URL
+type WebhookNotifier struct {
+	url    string
+	hits   bool // Gửi cả profile.found cho từng hit
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier for url; hits enables profile.found events
+func NewWebhookNotifier(url string, hits bool) *WebhookNotifier {
+	return &WebhookNotifier{url: url, hits: hits, client: &http.Client{Timeout: notifyTimeout}}
+}
+
+// Name returns "Webhook"
+func (w *WebhookNotifier) Name() string {
+	return "Webhook"
+}
+
+// Accepts reports whether the webhook wants events of eventType
+func (w *WebhookNotifier) Accepts(eventType EventType) bool {
+	return eventType != EventProfileFound || w.hits
+}
+
+// Notify posts the event in the outbound schema
+func (w *WebhookNotifier) Notify(event Event, message string) error {
+	body, err := json.Marshal(NewWebhookPayload(event, message))
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "linkedin-crawler-webhook/1")
+	req.Header.Set("X-Crawler-Event", string(event.Type))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// TestWebhook sends SampleWebhookEvent to url and waits for the response
+func TestWebhook(url string) error {
+	return NewWebhookNotifier(url, true).Notify(SampleWebhookEvent(), "")
+}
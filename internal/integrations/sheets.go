@@ -0,0 +1,332 @@
+package integrations
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+const (
+	sheetsScope       = "https://www.googleapis.com/auth/spreadsheets"
+	sheetsAPIBase     = "https://sheets.googleapis.com/v4/spreadsheets"
+	sheetsDefaultTab  = "Sheet1"
+	sheetsMaxPending  = 10000 // Giữ tối đa số dòng chưa sync được, tránh đầy RAM khi mất mạng lâu
+	sheetsHTTPTimeout = 30 * time.Second
+)
+
+// SheetsHeader is the column order of rows appended to the sheet
+var SheetsHeader = []string{"Time", "Email", "Name", "LinkedIn URL", "Location", "Connections", "Source", "Fields"}
+
+// serviceAccount holds the fields of a Google service account JSON key
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// SheetsSync appends hits to a Google Sheet through a service account.
+// Hits are queued and sent in one request every interval (interval 0 =
+// send each hit right away); rows that fail stay queued for the next sync.
+type SheetsSync struct {
+	spreadsheetID string
+	sheetRange    string
+	interval      time.Duration
+	account       serviceAccount
+	key           *rsa.PrivateKey
+	client        *http.Client
+
+	mutex       sync.Mutex
+	pending     [][]string
+	token       string
+	tokenExpiry time.Time
+
+	syncMutex sync.Mutex // Chỉ một lần gửi tại một thời điểm để giữ thứ tự dòng
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewSheetsSync creates a sync for cfg.SheetsSpreadsheetID using the service
+// account key in cfg.SheetsCredentialsFile
+func NewSheetsSync(cfg models.Config) (*SheetsSync, error) {
+	if cfg.SheetsSpreadsheetID == "" {
+		return nil, fmt.Errorf("no spreadsheet ID configured")
+	}
+
+	data, err := os.ReadFile(cfg.SheetsCredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account file: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("invalid service account file: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("service account file has no client_email or private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	key, err := parseServiceAccountKey(account.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sheetRange := strings.TrimSpace(cfg.SheetsRange)
+	if sheetRange == "" {
+		sheetRange = sheetsDefaultTab
+	}
+
+	return &SheetsSync{
+		spreadsheetID: cfg.SheetsSpreadsheetID,
+		sheetRange:    sheetRange,
+		interval:      cfg.SheetsInterval,
+		account:       account,
+		key:           key,
+		client:        &http.Client{Timeout: sheetsHTTPTimeout},
+		done:          make(chan struct{}),
+	}, nil
+}
+
+// parseServiceAccountKey parses the PEM private key of a service account
+func parseServiceAccountKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, fmt.Errorf("service account private_key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+		return nil, fmt.Errorf("service account private_key is not an RSA key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service account private_key: %w", err)
+	}
+	return key, nil
+}
+
+// Start begins the periodic sync; it does nothing when hits are sent right away
+func (s *SheetsSync) Start() {
+	if s.interval <= 0 {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Flush(); err != nil {
+					fmt.Printf("⚠️ Google Sheets sync lỗi, sẽ thử lại: %v\n", err)
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// Add queues a hit for the next sync
+func (s *SheetsSync) Add(hit utils.HitResult) {
+	if hit.Timestamp.IsZero() {
+		hit.Timestamp = time.Now()
+	}
+	row := []string{
+		hit.Timestamp.Format("2006-01-02 15:04:05"),
+		hit.Email, hit.Name, hit.LinkedInURL, hit.Location, hit.Connections, hit.Source,
+		utils.FormatInputFields(hit.Fields),
+	}
+
+	s.mutex.Lock()
+	s.pending = append(s.pending, row)
+	if len(s.pending) > sheetsMaxPending {
+		s.pending = s.pending[len(s.pending)-sheetsMaxPending:]
+	}
+	s.mutex.Unlock()
+
+	if s.interval <= 0 {
+		go func() {
+			if err := s.Flush(); err != nil {
+				fmt.Printf("⚠️ Google Sheets sync lỗi, sẽ thử lại ở hit sau: %v\n", err)
+			}
+		}()
+	}
+}
+
+// Pending returns the number of rows waiting to be synced
+func (s *SheetsSync) Pending() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.pending)
+}
+
+// Flush appends all queued rows to the sheet in one request
+func (s *SheetsSync) Flush() error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	s.mutex.Lock()
+	rows := s.pending
+	s.pending = nil
+	s.mutex.Unlock()
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err := s.appendRows(rows); err != nil {
+		// Trả lại các dòng chưa gửi được lên đầu hàng đợi
+		s.mutex.Lock()
+		s.pending = append(rows, s.pending...)
+		if len(s.pending) > sheetsMaxPending {
+			s.pending = s.pending[len(s.pending)-sheetsMaxPending:]
+		}
+		s.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Stop ends the periodic sync and sends what is still queued
+func (s *SheetsSync) Stop() error {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	s.wg.Wait()
+	return s.Flush()
+}
+
+// Test appends the header row, to check the credentials and sheet access
+func (s *SheetsSync) Test() error {
+	return s.appendRows([][]string{SheetsHeader})
+}
+
+// appendRows calls the Sheets values.append API
+func (s *SheetsSync) appendRows(rows [][]string) error {
+	token, err := s.accessToken()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"values": rows})
+	if err != nil {
+		return fmt.Errorf("failed to encode rows: %w", err)
+	}
+	endpoint := fmt.Sprintf("%s/%s/values/%s:append?valueInputOption=RAW&insertDataOption=INSERT_ROWS",
+		sheetsAPIBase, url.PathEscape(s.spreadsheetID), url.PathEscape(s.sheetRange))
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sheets request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sheets request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if resp.StatusCode == http.StatusUnauthorized {
+			s.mutex.Lock()
+			s.token = ""
+			s.mutex.Unlock()
+		}
+		return fmt.Errorf("sheets API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// accessToken returns a cached OAuth token, exchanging a signed JWT for a new
+// one when it is about to expire
+func (s *SheetsSync) accessToken() (string, error) {
+	s.mutex.Lock()
+	if s.token != "" && time.Until(s.tokenExpiry) > time.Minute {
+		token := s.token
+		s.mutex.Unlock()
+		return token, nil
+	}
+	s.mutex.Unlock()
+
+	assertion, err := s.signJWT(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.PostForm(s.account.TokenURI, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.AccessToken == "" {
+		return "", fmt.Errorf("token request returned %d: %s %s", resp.StatusCode, result.Error, result.Description)
+	}
+
+	s.mutex.Lock()
+	s.token = result.AccessToken
+	s.tokenExpiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	s.mutex.Unlock()
+	return result.AccessToken, nil
+}
+
+// signJWT builds the RS256 assertion of the service account token request
+func (s *SheetsSync) signJWT(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   s.account.ClientEmail,
+		"scope": sheetsScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT claims: %w", err)
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,111 @@
+package integrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // Driver "mysql"
+	_ "github.com/lib/pq"              // Driver "postgres"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+const sqlSourceTimeout = 2 * time.Minute
+
+// Database drivers supported as email source
+const (
+	SQLDriverPostgres = "postgres"
+	SQLDriverMySQL    = "mysql"
+)
+
+// SQLDrivers lists the supported email source drivers
+var SQLDrivers = []string{SQLDriverPostgres, SQLDriverMySQL}
+
+// QueryEmailSource runs the configured email source query and returns one
+// line per row. Multiple columns are joined with ","; NULL columns become
+// empty.
+func QueryEmailSource(cfg models.Config) ([]string, error) {
+	driver := cfg.EmailsDBDriver
+	if driver == "" {
+		driver = SQLDriverPostgres
+	}
+	valid := false
+	for _, d := range SQLDrivers {
+		if driver == d {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("unsupported email source driver %q (use %s)", driver, strings.Join(SQLDrivers, " or "))
+	}
+	if strings.TrimSpace(cfg.EmailsDBQuery) == "" {
+		return nil, fmt.Errorf("no email source query configured")
+	}
+
+	db, err := sql.Open(driver, cfg.EmailsDBDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open email source database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqlSourceTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, cfg.EmailsDBQuery)
+	if err != nil {
+		return nil, fmt.Errorf("email source query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query columns: %w", err)
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var lines []string
+	fields := make([]string, len(columns))
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to read query row: %w", err)
+		}
+		for i, v := range values {
+			// Dấu phẩy trong giá trị sẽ làm lệch cột khi parse lại
+			fields[i] = strings.TrimSpace(strings.ReplaceAll(v.String, ",", " "))
+		}
+		if line := strings.Join(fields, ","); strings.Trim(line, ",") != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("email source query failed: %w", err)
+	}
+	return lines, nil
+}
+
+// RefreshEmailsFromSource runs the email source query and replaces the emails
+// file with its rows, so the usual loader (validation, dedupe, crawl modes)
+// reads them. It returns the number of rows written.
+func RefreshEmailsFromSource(cfg models.Config) (int, error) {
+	lines, err := QueryEmailSource(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	header := fmt.Sprintf("# Generated from the email source query at %s - edits are overwritten on the next run\n",
+		time.Now().Format("2006-01-02 15:04:05"))
+	content := header + strings.Join(lines, "\n") + "\n"
+	if err := utils.WriteFileAtomic(cfg.EmailsFilePath, []byte(content)); err != nil {
+		return 0, fmt.Errorf("failed to write emails file: %w", err)
+	}
+	return len(lines), nil
+}
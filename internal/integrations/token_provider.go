@@ -0,0 +1,120 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tokenProviderTimeout = 30 * time.Second
+	tokenProviderMaxSize = 4 << 20 // 4 MB
+)
+
+// TokenProvider fetches tokens from a central token farm over HTTP. Each
+// request is GET <url>?count=N&instance=<hostname> with the auth header; the
+// response is JSON {"tokens": [...]}, a JSON array of strings or plain text
+// with one token per line. 204 No Content means the farm has none left.
+type TokenProvider struct {
+	url         *url.URL
+	headerName  string
+	headerValue string
+	instance    string
+	client      *http.Client
+}
+
+// NewTokenProvider parses the endpoint and the auth header: "Name: value", or
+// a bare value sent as the Authorization header ("" = no header)
+func NewTokenProvider(rawURL, authHeader string) (*TokenProvider, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid token provider URL %q: must be an http(s) URL", rawURL)
+	}
+
+	p := &TokenProvider{url: u, client: &http.Client{Timeout: tokenProviderTimeout}}
+	p.instance, _ = os.Hostname()
+	if authHeader = strings.TrimSpace(authHeader); authHeader != "" {
+		name, value, ok := strings.Cut(authHeader, ":")
+		// "Bearer abc" không có tên header: gửi làm Authorization
+		if !ok || strings.ContainsAny(strings.TrimSpace(name), " \t") {
+			name, value = "Authorization", authHeader
+		}
+		p.headerName, p.headerValue = strings.TrimSpace(name), strings.TrimSpace(value)
+	}
+	return p, nil
+}
+
+// Fetch asks the provider for up to count tokens
+func (p *TokenProvider) Fetch(ctx context.Context, count int) ([]string, error) {
+	u := *p.url
+	q := u.Query()
+	q.Set("count", strconv.Itoa(count))
+	if p.instance != "" {
+		q.Set("instance", p.instance)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token provider request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json, text/plain")
+	if p.headerName != "" {
+		req.Header.Set(p.headerName, p.headerValue)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token provider request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, tokenProviderMaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token provider response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("token provider returned %s: %s", resp.Status, strings.TrimSpace(string(body[:min(len(body), 200)])))
+	}
+	return parseProviderTokens(body)
+}
+
+// parseProviderTokens reads {"tokens": [...]}, [...] or one token per line
+func parseProviderTokens(body []byte) ([]string, error) {
+	text := strings.TrimSpace(string(body))
+	var raw []string
+	switch {
+	case strings.HasPrefix(text, "{"):
+		var payload struct {
+			Tokens []string `json:"tokens"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, fmt.Errorf("invalid token provider response: %w", err)
+		}
+		raw = payload.Tokens
+	case strings.HasPrefix(text, "["):
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, fmt.Errorf("invalid token provider response: %w", err)
+		}
+	default:
+		raw = strings.Split(text, "\n")
+	}
+
+	tokens := make([]string, 0, len(raw))
+	for _, token := range raw {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,14 @@
+package licensing
+
+// Billing policy: chỉ email tra cứu hoàn tất (status=success, có hoặc không có
+// profile LinkedIn) mới tính vào quota. Lookup thất bại (429, lỗi mạng, token
+// hết hạn, status=failed) là non-billable: vẫn được ghi vào usage ledger để
+// thống kê nhưng không trừ quota.
+
+// BillableStatus is the email status that consumes license quota
+const BillableStatus = "success"
+
+// IsBillableStatus reports whether an email with this status counts against the quota
+func IsBillableStatus(status string) bool {
+	return status == BillableStatus
+}
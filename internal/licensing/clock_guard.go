@@ -0,0 +1,101 @@
+package licensing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// clockSkewTolerance cho phép lệch giờ nhỏ (NTP, đổi múi giờ) trước khi coi là tamper
+	clockSkewTolerance = time.Hour
+	// lastSeenUpdateInterval giới hạn tần suất ghi lại high-water mark
+	lastSeenUpdateInterval = 10 * time.Minute
+	// clockResetCodePrefix đánh dấu mã khôi phục do support cấp
+	clockResetCodePrefix = "CLK-"
+)
+
+// ErrClockTampered is returned when the system clock is earlier than the recorded high-water mark
+var ErrClockTampered = errors.New("system clock is earlier than the last recorded license use")
+
+// checkClock refuses validation when the clock went backwards and advances the
+// high-water mark stored in licenseData
+func (lm *LicenseManager) checkClock(licenseKey string, licenseData map[string]interface{}) error {
+	now := time.Now()
+	lastSeen := lm.lastSeen(licenseKey, licenseData)
+
+	if !lastSeen.IsZero() && now.Before(lastSeen.Add(-clockSkewTolerance)) {
+		return fmt.Errorf("%w (now %s, last seen %s) - correct the system clock or request a clock reset code",
+			ErrClockTampered, now.Format("2006-01-02 15:04"), lastSeen.Format("2006-01-02 15:04"))
+	}
+
+	if now.After(lastSeen.Add(lastSeenUpdateInterval)) {
+		lm.setLastSeen(licenseKey, licenseData, now)
+		if err := lm.saveLicenseFile(licenseData); err != nil {
+			fmt.Printf("⚠️ Không thể cập nhật license last-seen: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// lastSeen returns the signed high-water mark, or zero when missing or forged
+func (lm *LicenseManager) lastSeen(licenseKey string, licenseData map[string]interface{}) time.Time {
+	raw, _ := licenseData["last_seen"].(string)
+	sig, _ := licenseData["last_seen_sig"].(string)
+	if raw == "" || sig != lm.generateChecksum(licenseKey+"|"+raw) {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// setLastSeen stores a signed high-water mark in licenseData
+func (lm *LicenseManager) setLastSeen(licenseKey string, licenseData map[string]interface{}, t time.Time) {
+	raw := t.UTC().Format(time.RFC3339)
+	licenseData["last_seen"] = raw
+	licenseData["last_seen_sig"] = lm.generateChecksum(licenseKey + "|" + raw)
+}
+
+// ResetClockMark clears a wrong high-water mark using a support-issued reset code
+func (lm *LicenseManager) ResetClockMark(code string) error {
+	licenseData, err := lm.loadLicenseFile()
+	if err != nil {
+		return fmt.Errorf("failed to load license: %w", err)
+	}
+
+	licenseKey, ok := licenseData["key"].(string)
+	if !ok {
+		return fmt.Errorf("invalid license file format")
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	now := time.Now()
+	valid := false
+	// Mã có hiệu lực trong ngày cấp ±1 ngày để tránh lệch múi giờ
+	for _, offset := range []int{-1, 0, 1} {
+		if code == GenerateClockResetCode(licenseKey, now.AddDate(0, 0, offset)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid or expired clock reset code")
+	}
+
+	lm.setLastSeen(licenseKey, licenseData, now)
+	return lm.saveLicenseFile(licenseData)
+}
+
+// GenerateClockResetCode returns the reset code for licenseKey valid on day (for support use)
+func GenerateClockResetCode(licenseKey string, day time.Time) string {
+	lm := NewLicenseManager()
+	licenseKey = strings.TrimSpace(strings.ReplaceAll(licenseKey, " ", ""))
+	checksum := lm.generateChecksum(licenseKey + "|clock-reset|" + day.UTC().Format("20060102"))
+	return clockResetCodePrefix + strings.ToUpper(checksum[:10])
+}
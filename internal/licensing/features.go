@@ -0,0 +1,32 @@
+package licensing
+
+import (
+	"fmt"
+
+	"linkedin-crawler/internal/models"
+)
+
+// StandardMaxConcurrency caps workers for licenses without FeatureAdvancedCrawling
+const StandardMaxConcurrency = 30
+
+// ApplyFeatureGates checks cfg against the license features and returns the
+// config actually allowed: without advanced crawling concurrency is capped at
+// StandardMaxConcurrency and the extended profile fields are turned off
+func (lcw *LicensedCrawlerWrapper) ApplyFeatureGates(cfg models.Config) (models.Config, error) {
+	if lcw.CheckFeatureAccess(FeatureAdvancedCrawling) {
+		return cfg, nil
+	}
+
+	if cfg.MaxConcurrency > StandardMaxConcurrency {
+		fmt.Printf("⚠️ Concurrency %d vượt giới hạn license, giảm xuống %d (PRO license để tăng)\n",
+			cfg.MaxConcurrency, StandardMaxConcurrency)
+		cfg.MaxConcurrency = StandardMaxConcurrency
+	}
+
+	if cfg.ExtendedProfile {
+		fmt.Println("⚠️ Headline và company chỉ có với PRO license, bỏ qua ExtendedProfile")
+		cfg.ExtendedProfile = false
+	}
+
+	return cfg, nil
+}
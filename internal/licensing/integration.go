@@ -3,6 +3,7 @@
 package licensing
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -12,6 +13,7 @@ import (
 // LicensedCrawlerWrapper với enhanced checking
 type LicensedCrawlerWrapper struct {
 	licenseManager *LicenseManager
+	usageLedger    *UsageLedger // Usage tích luỹ theo ngày, dùng chung giữa các wrapper
 
 	// Real-time tracking
 	currentProcessedEmails int
@@ -23,6 +25,7 @@ type LicensedCrawlerWrapper struct {
 func NewLicensedCrawlerWrapper() *LicensedCrawlerWrapper {
 	return &LicensedCrawlerWrapper{
 		licenseManager:         NewLicenseManager(),
+		usageLedger:            DefaultUsageLedger(),
 		currentProcessedEmails: 0,
 		currentSuccessEmails:   0,
 		startTime:              time.Now(),
@@ -69,12 +72,14 @@ func (lcw *LicensedCrawlerWrapper) CheckCrawlingLimits(emailCount, accountCount
 
 	// Enhanced email limit checking
 	if maxEmails > 0 {
-		// Check current processed emails + new emails
-		totalWillProcess := lcw.currentProcessedEmails + emailCount
+		// Chỉ email billable (status=success) trong ledger tính vào quota (mọi
+		// session); email mới được tính theo trường hợp xấu nhất là tất cả đều thành công
+		billable := lcw.usageLedger.TotalBillable()
+		totalWillProcess := billable + emailCount
 
 		if totalWillProcess > maxEmails {
 			return fmt.Errorf("email limit will be exceeded: %d + %d = %d > %d (upgrade license for more emails)",
-				lcw.currentProcessedEmails, emailCount, totalWillProcess, maxEmails)
+				billable, emailCount, totalWillProcess, maxEmails)
 		}
 
 		// Warning when approaching limit
@@ -98,16 +103,10 @@ func (lcw *LicensedCrawlerWrapper) CheckRealTimeLimits(currentProcessed, current
 	lcw.currentProcessedEmails = currentProcessed
 	lcw.currentSuccessEmails = currentSuccess
 
-	// Check processed email limits
-	if maxEmails > 0 {
-		if currentProcessed >= maxEmails {
-			return fmt.Errorf("email processing limit reached: %d/%d emails processed", currentProcessed, maxEmails)
-		}
-
-		// Alternative: Check success emails instead of processed
-		// if currentSuccess >= maxEmails {
-		//     return fmt.Errorf("successful email limit reached: %d/%d emails", currentSuccess, maxEmails)
-		// }
+	// Chỉ email billable (status=success) tính vào limit, lookup thất bại thì không.
+	// Ledger được ghi sau mỗi email nên đã gồm cả session này.
+	if billable := lcw.usageLedger.TotalBillable(); maxEmails > 0 && billable >= maxEmails {
+		return fmt.Errorf("email limit reached: %d/%d billable emails", billable, maxEmails)
 	}
 
 	return nil
@@ -123,17 +122,27 @@ func (lcw *LicensedCrawlerWrapper) GetUsageStats() map[string]interface{} {
 	}
 
 	stats := map[string]interface{}{
-		"current_processed_emails": lcw.currentProcessedEmails,
-		"current_success_emails":   lcw.currentSuccessEmails,
-		"max_emails":               maxEmails,
-		"max_accounts":             maxAccounts,
-		"session_duration":         time.Since(lcw.startTime).String(),
+		"current_processed_emails":  lcw.currentProcessedEmails,
+		"current_success_emails":    lcw.currentSuccessEmails,
+		"max_emails":                maxEmails,
+		"max_accounts":              maxAccounts,
+		"session_duration":          time.Since(lcw.startTime).String(),
+		"total_processed_emails":    lcw.usageLedger.TotalProcessed(),
+		"billable_emails":           lcw.currentSuccessEmails,
+		"non_billable_emails":       lcw.currentProcessedEmails - lcw.currentSuccessEmails,
+		"total_billable_emails":     lcw.usageLedger.TotalBillable(),
+		"total_non_billable_emails": lcw.usageLedger.TotalProcessed() - lcw.usageLedger.TotalBillable(),
 	}
 
-	// Calculate percentages
+	// Quota tính theo tổng email billable trong ledger, giống RemainingQuota
 	if maxEmails > 0 {
-		stats["email_usage_percent"] = float64(lcw.currentProcessedEmails) * 100 / float64(maxEmails)
-		stats["remaining_emails"] = maxEmails - lcw.currentProcessedEmails
+		billable := lcw.usageLedger.TotalBillable()
+		remaining := maxEmails - billable
+		if remaining < 0 {
+			remaining = 0
+		}
+		stats["email_usage_percent"] = float64(billable) * 100 / float64(maxEmails)
+		stats["remaining_emails"] = remaining
 	} else {
 		stats["email_usage_percent"] = 0.0
 		stats["remaining_emails"] = -1 // Unlimited
@@ -155,6 +164,39 @@ func (lcw *LicensedCrawlerWrapper) UpdateUsageCounters(processed, success int) {
 	lcw.currentSuccessEmails = success
 }
 
+// RecordUsage adds processed emails to the persisted usage ledger
+func (lcw *LicensedCrawlerWrapper) RecordUsage(processed, success int) {
+	lcw.usageLedger.Record(processed, success)
+}
+
+// RemainingQuota returns emails left under the license (cumulative billable
+// ledger usage against MaxEmails incl. add-ons), or -1 when unlimited
+func (lcw *LicensedCrawlerWrapper) RemainingQuota() (int, error) {
+	maxEmails, _, err := lcw.licenseManager.GetUsageLimits()
+	if err != nil {
+		return 0, fmt.Errorf("license validation failed: %w", err)
+	}
+	if maxEmails <= 0 {
+		return -1, nil
+	}
+
+	remaining := maxEmails - lcw.usageLedger.TotalBillable()
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, nil
+}
+
+// FlushUsage writes pending ledger changes to disk
+func (lcw *LicensedCrawlerWrapper) FlushUsage() error {
+	return lcw.usageLedger.Flush()
+}
+
+// GetUsageLedger returns the persisted usage ledger
+func (lcw *LicensedCrawlerWrapper) GetUsageLedger() *UsageLedger {
+	return lcw.usageLedger
+}
+
 // GetLicenseInfo returns enhanced license information
 func (lcw *LicensedCrawlerWrapper) GetLicenseInfo() map[string]interface{} {
 	info := lcw.licenseManager.GetLicenseInfo()
@@ -222,10 +264,45 @@ func (lcw *LicensedCrawlerWrapper) ActivateLicense(licenseKey string) error {
 	if err == nil {
 		// Reset counters on new license activation
 		lcw.ResetUsageCounters()
+		lcw.usageLedger.BindLicense(lcw.licenseManager.LicenseFingerprint(licenseKey))
+		if flushErr := lcw.usageLedger.Flush(); flushErr != nil {
+			log.Printf("⚠️ Failed to save usage ledger: %v", flushErr)
+		}
 	}
 	return err
 }
 
+// ResetClockMark applies a support-issued clock reset code
+func (lcw *LicensedCrawlerWrapper) ResetClockMark(code string) error {
+	return lcw.licenseManager.ResetClockMark(code)
+}
+
+// AddAddon stacks a quota add-on key onto the active license
+func (lcw *LicensedCrawlerWrapper) AddAddon(addonKey string) (*AddonInfo, error) {
+	return lcw.licenseManager.AddAddon(addonKey)
+}
+
+// UpgradeLicense swaps in a higher-tier key and migrates the usage ledger to it
+func (lcw *LicensedCrawlerWrapper) UpgradeLicense(newKey string) (oldInfo, newInfo *LicenseInfo, err error) {
+	oldKey, err := lcw.licenseManager.CurrentLicenseKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oldInfo, newInfo, err = lcw.licenseManager.UpgradeLicense(newKey)
+	if err != nil {
+		return oldInfo, newInfo, err
+	}
+
+	// License đã được thay; usage tích luỹ được giữ nguyên và gắn sang license mới
+	if err := lcw.usageLedger.MigrateLicense(oldInfo, newInfo,
+		lcw.licenseManager.LicenseFingerprint(oldKey), lcw.licenseManager.LicenseFingerprint(newKey)); err != nil {
+		return oldInfo, newInfo, fmt.Errorf("license upgraded but usage ledger could not be saved: %w", err)
+	}
+
+	return oldInfo, newInfo, nil
+}
+
 // RemoveLicense removes current license
 func (lcw *LicensedCrawlerWrapper) RemoveLicense() error {
 	err := lcw.licenseManager.RemoveLicense()
@@ -245,6 +322,13 @@ func (lcw *LicensedCrawlerWrapper) handleLicenseError(err error) error {
 	fmt.Println("   1. Obtain a valid license key")
 	fmt.Println("   2. Use the license activation feature in the GUI")
 	fmt.Println("   3. Or contact support for assistance")
+	if errors.Is(err, ErrClockTampered) {
+		fmt.Println("")
+		fmt.Println("🕒 System clock appears to have been set back:")
+		fmt.Println("   1. Correct the system date/time and restart")
+		fmt.Println("   2. If the clock is correct, request a clock reset code from support")
+		fmt.Println("      and enter it in License tab → Clock Reset")
+	}
 	fmt.Println("")
 	fmt.Println("💡 License Types Available:")
 	fmt.Println("   - TRIAL: 100 emails, 2 accounts, 30 days")
@@ -270,10 +354,10 @@ func (lcw *LicensedCrawlerWrapper) showLicenseInfo(info *LicenseInfo) {
 
 	// Show limits with current usage
 	if info.MaxEmails > 0 {
-		fmt.Printf("📧 Email limit: %d (Used: %d, Remaining: %d)\n",
-			info.MaxEmails, lcw.currentProcessedEmails, info.MaxEmails-lcw.currentProcessedEmails)
+		fmt.Printf("📧 Email limit: %d (Billable used: %d, Remaining: %d)\n",
+			info.MaxEmails, lcw.currentSuccessEmails, info.MaxEmails-lcw.currentSuccessEmails)
 	} else {
-		fmt.Printf("📧 Email limit: Unlimited (Used: %d)\n", lcw.currentProcessedEmails)
+		fmt.Printf("📧 Email limit: Unlimited (Billable used: %d)\n", lcw.currentSuccessEmails)
 	}
 
 	if info.MaxAccounts > 0 {
@@ -293,11 +377,11 @@ func (lcw *LicensedCrawlerWrapper) showLicenseInfo(info *LicenseInfo) {
 	}
 
 	// Warning for approaching email limits
-	if info.MaxEmails > 0 && lcw.currentProcessedEmails > 0 {
-		usagePercent := float64(lcw.currentProcessedEmails) * 100 / float64(info.MaxEmails)
+	if info.MaxEmails > 0 && lcw.currentSuccessEmails > 0 {
+		usagePercent := float64(lcw.currentSuccessEmails) * 100 / float64(info.MaxEmails)
 		if usagePercent > 80 {
 			fmt.Printf("⚠️  WARNING: %d%% of email quota used (%d/%d)\n",
-				int(usagePercent), lcw.currentProcessedEmails, info.MaxEmails)
+				int(usagePercent), lcw.currentSuccessEmails, info.MaxEmails)
 			fmt.Println("   Consider upgrading for more email processing capacity.")
 			fmt.Println("")
 		}
@@ -6,9 +6,11 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -20,6 +22,7 @@ const (
 	LicenseTypeTrial    LicenseType = "trial"
 	LicenseTypePersonal LicenseType = "personal"
 	LicenseTypePro      LicenseType = "pro"
+	LicenseTypeAddon    LicenseType = "addon" // Key cộng thêm quota email, không dùng độc lập
 )
 
 // LicenseInfo represents license information
@@ -34,6 +37,14 @@ type LicenseInfo struct {
 	IsValid     bool        `json:"is_valid"`
 }
 
+// AddonInfo is a quota add-on stacked on top of the base license
+type AddonInfo struct {
+	Key       string    `json:"key"`
+	Emails    int       `json:"emails"`
+	UserEmail string    `json:"user_email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // LicenseManager handles offline license validation
 type LicenseManager struct {
 	licenseFile string
@@ -86,12 +97,158 @@ func (lm *LicenseManager) SaveLicense(licenseKey string) error {
 		"checksum": lm.generateChecksum(licenseKey),
 	}
 
+	// Giữ lại add-on đã kích hoạt (chỉ add-on cùng email mới được tính khi load)
+	if addons := lm.storedAddonKeys(); len(addons) > 0 {
+		licenseData["addons"] = addons
+	}
+
+	// High-water mark không bao giờ lùi khi thay license
+	lastSeen := time.Now()
+	if existing, err := lm.loadLicenseFile(); err == nil {
+		if oldKey, ok := existing["key"].(string); ok {
+			if mark := lm.lastSeen(oldKey, existing); mark.After(lastSeen) {
+				lastSeen = mark
+			}
+		}
+	}
+	lm.setLastSeen(licenseKey, licenseData, lastSeen)
+
 	// Save to file
 	return lm.saveLicenseFile(licenseData)
 }
 
-// LoadLicense loads and validates saved license
+// ParseAddonKey validates an add-on key: ADDON-QUANTITY-EMAIL-EXPIRY-CHECKSUM
+func (lm *LicenseManager) ParseAddonKey(addonKey string) (*AddonInfo, error) {
+	addonKey = strings.TrimSpace(strings.ReplaceAll(addonKey, " ", ""))
+
+	parts := strings.Split(addonKey, "-")
+	if len(parts) < 5 || !strings.EqualFold(parts[0], string(LicenseTypeAddon)) {
+		return nil, fmt.Errorf("invalid add-on key format - expected ADDON-QUANTITY-EMAIL-EXPIRY-CHECKSUM")
+	}
+
+	emails, err := strconv.Atoi(parts[1])
+	if err != nil || emails <= 0 {
+		return nil, fmt.Errorf("invalid add-on quantity: %s", parts[1])
+	}
+
+	userEmail := strings.ToLower(parts[2])
+	expiryStr := parts[3]
+	expiryDate, err := time.Parse("20060102", expiryStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiry date: %s (%v)", expiryStr, err)
+	}
+
+	providedChecksum := strings.Join(parts[4:], "-")
+	if providedChecksum != lm.generateLicenseChecksum(LicenseTypeAddon, parts[1], userEmail, expiryStr) {
+		return nil, fmt.Errorf("invalid add-on checksum - key may be corrupted or tampered with")
+	}
+
+	addon := &AddonInfo{
+		Key:       addonKey,
+		Emails:    emails,
+		UserEmail: userEmail,
+		ExpiresAt: expiryDate,
+	}
+	if time.Now().After(addon.ExpiresAt) {
+		return addon, fmt.Errorf("add-on has expired on %s", addon.ExpiresAt.Format("2006-01-02"))
+	}
+
+	return addon, nil
+}
+
+// AddAddon validates an add-on key and stacks it onto the stored license
+func (lm *LicenseManager) AddAddon(addonKey string) (*AddonInfo, error) {
+	info, err := lm.loadBaseLicense()
+	if err != nil {
+		return nil, fmt.Errorf("activate a base license before adding add-ons: %w", err)
+	}
+
+	addon, err := lm.ParseAddonKey(addonKey)
+	if err != nil {
+		return nil, err
+	}
+	if addon.UserEmail != strings.ToLower(info.UserEmail) {
+		return nil, fmt.Errorf("add-on is issued to %s, license belongs to %s", addon.UserEmail, info.UserEmail)
+	}
+	if info.MaxEmails <= 0 {
+		return nil, fmt.Errorf("license already has unlimited emails")
+	}
+
+	licenseData, err := lm.loadLicenseFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load license: %w", err)
+	}
+
+	keys := lm.storedAddonKeys()
+	for _, key := range keys {
+		if key == addon.Key {
+			return nil, fmt.Errorf("add-on key is already active")
+		}
+	}
+	licenseData["addons"] = append(keys, addon.Key)
+
+	if err := lm.saveLicenseFile(licenseData); err != nil {
+		return nil, fmt.Errorf("failed to save add-on: %w", err)
+	}
+	return addon, nil
+}
+
+// ActiveAddons returns unexpired add-ons issued to the license owner
+func (lm *LicenseManager) ActiveAddons() []AddonInfo {
+	info, err := lm.loadBaseLicense()
+	if err != nil {
+		return nil
+	}
+	return lm.activeAddonsFor(info)
+}
+
+// activeAddonsFor filters stored add-ons down to the valid ones for info
+func (lm *LicenseManager) activeAddonsFor(info *LicenseInfo) []AddonInfo {
+	var active []AddonInfo
+	for _, key := range lm.storedAddonKeys() {
+		addon, err := lm.ParseAddonKey(key)
+		if err != nil || addon.UserEmail != strings.ToLower(info.UserEmail) {
+			continue
+		}
+		active = append(active, *addon)
+	}
+	return active
+}
+
+// storedAddonKeys returns add-on keys saved in the license file
+func (lm *LicenseManager) storedAddonKeys() []string {
+	licenseData, err := lm.loadLicenseFile()
+	if err != nil {
+		return nil
+	}
+
+	raw, _ := licenseData["addons"].([]interface{})
+	keys := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if key, ok := v.(string); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// LoadLicense loads and validates saved license; active add-ons are added to MaxEmails
 func (lm *LicenseManager) LoadLicense() (*LicenseInfo, error) {
+	info, err := lm.loadBaseLicense()
+	if err != nil {
+		return info, err
+	}
+
+	if info.MaxEmails > 0 {
+		for _, addon := range lm.activeAddonsFor(info) {
+			info.MaxEmails += addon.Emails
+		}
+	}
+	return info, nil
+}
+
+// loadBaseLicense loads and validates the saved license without add-ons
+func (lm *LicenseManager) loadBaseLicense() (*LicenseInfo, error) {
 	// Check if license file exists
 	if _, err := os.Stat(lm.licenseFile); os.IsNotExist(err) {
 		return nil, fmt.Errorf("no license found - please enter your license key")
@@ -115,6 +272,11 @@ func (lm *LicenseManager) LoadLicense() (*LicenseInfo, error) {
 		return nil, fmt.Errorf("license file has been tampered with")
 	}
 
+	// Chặn việc lùi đồng hồ hệ thống để né kiểm tra hết hạn
+	if err := lm.checkClock(licenseKey, licenseData); err != nil {
+		return nil, err
+	}
+
 	// Validate license key
 	return lm.ValidateLicenseKey(licenseKey)
 }
@@ -149,11 +311,18 @@ func (lm *LicenseManager) GetLicenseInfo() map[string]interface{} {
 	info, err := lm.LoadLicense()
 	if err != nil {
 		return map[string]interface{}{
-			"status": "invalid",
-			"error":  err.Error(),
+			"status":         "invalid",
+			"error":          err.Error(),
+			"clock_tampered": errors.Is(err, ErrClockTampered),
 		}
 	}
 
+	addons := lm.activeAddonsFor(info)
+	addonEmails := 0
+	for _, addon := range addons {
+		addonEmails += addon.Emails
+	}
+
 	daysLeft := int(time.Until(info.ExpiresAt).Hours() / 24)
 	status := "active"
 	if daysLeft <= 0 {
@@ -172,6 +341,65 @@ func (lm *LicenseManager) GetLicenseInfo() map[string]interface{} {
 		"max_emails":   info.MaxEmails,
 		"max_accounts": info.MaxAccounts,
 		"features":     info.Features,
+		"addon_emails": addonEmails,
+		"addons":       len(addons),
+	}
+}
+
+// CurrentLicenseKey returns the key of the stored license
+func (lm *LicenseManager) CurrentLicenseKey() (string, error) {
+	licenseData, err := lm.loadLicenseFile()
+	if err != nil {
+		return "", fmt.Errorf("no license found: %w", err)
+	}
+
+	licenseKey, ok := licenseData["key"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid license file format")
+	}
+	return licenseKey, nil
+}
+
+// UpgradeLicense validates a higher-tier key and swaps it in for the stored license
+func (lm *LicenseManager) UpgradeLicense(newKey string) (oldInfo, newInfo *LicenseInfo, err error) {
+	oldInfo, err = lm.LoadLicense()
+	if err != nil {
+		return nil, nil, fmt.Errorf("no active license to upgrade: %w", err)
+	}
+
+	newInfo, err = lm.ValidateLicenseKey(newKey)
+	if err != nil {
+		return oldInfo, nil, err
+	}
+
+	if licenseRank(newInfo.Type) <= licenseRank(oldInfo.Type) {
+		return oldInfo, newInfo, fmt.Errorf("%s is not an upgrade from %s",
+			strings.ToUpper(string(newInfo.Type)), strings.ToUpper(string(oldInfo.Type)))
+	}
+
+	if err := lm.SaveLicense(newKey); err != nil {
+		return oldInfo, newInfo, fmt.Errorf("failed to save upgraded license: %w", err)
+	}
+
+	return oldInfo, newInfo, nil
+}
+
+// LicenseFingerprint returns a short stable identifier for a license key
+func (lm *LicenseManager) LicenseFingerprint(licenseKey string) string {
+	return lm.generateChecksum(strings.TrimSpace(strings.ReplaceAll(licenseKey, " ", "")))
+}
+
+// licenseRank orders license types from lowest to highest tier
+func licenseRank(t LicenseType) int {
+	switch t {
+	case LicenseTypeTrial:
+		return 1
+	case LicenseTypePersonal:
+		return 2
+	case LicenseTypePro:
+		return 3
+	default:
+		return 0
 	}
 }
 
@@ -225,6 +453,8 @@ func (lm *LicenseManager) parseCustomLicenseKey(licenseKey string) (*LicenseInfo
 		licenseType = LicenseTypePersonal
 	case "pro":
 		licenseType = LicenseTypePro
+	case "addon":
+		return nil, fmt.Errorf("this is an add-on key - activate a base license first, then add it as an add-on")
 	default:
 		return nil, fmt.Errorf("invalid license type: %s (must be TRIAL, PERSONAL, or PRO)", parts[0])
 	}
@@ -324,8 +554,16 @@ func (lm *LicenseManager) saveLicenseFile(data map[string]interface{}) error {
 	// Encode as base64
 	encoded := base64.StdEncoding.EncodeToString(encrypted)
 
-	// Write to file
-	return os.WriteFile(lm.licenseFile, []byte(encoded), 0600)
+	// Ghi file tạm rồi rename để thay license một cách atomic
+	tmpFile := lm.licenseFile + ".tmp"
+	if err := os.WriteFile(tmpFile, []byte(encoded), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFile, lm.licenseFile); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+	return nil
 }
 
 // loadLicenseFile loads and decrypts license file
@@ -402,6 +640,18 @@ func GenerateLicenseKey(licenseType LicenseType, userName, userEmail string, val
 	return licenseKey
 }
 
+// GenerateAddonKey generates an add-on key adding emails to userEmail's license
+func GenerateAddonKey(emails int, userEmail string, validDays int) string {
+	expiryStr := time.Now().AddDate(0, 0, validDays).Format("20060102")
+	normalizedEmail := strings.ToLower(userEmail)
+	quantity := strconv.Itoa(emails)
+
+	lm := NewLicenseManager()
+	checksum := lm.generateLicenseChecksum(LicenseTypeAddon, quantity, normalizedEmail, expiryStr)
+
+	return fmt.Sprintf("ADDON-%s-%s-%s-%s", quantity, normalizedEmail, expiryStr, checksum)
+}
+
 // Example usage and testing functions
 func ExampleGenerateLicenseKeys() {
 	fmt.Println("Example License Keys:")
@@ -0,0 +1,18 @@
+package licensing
+
+import "linkedin-crawler/internal/models"
+
+// FillSessionReport adds the license type, holder and email usage to report.
+// The report is left unchanged when no valid license is installed.
+func (lcw *LicensedCrawlerWrapper) FillSessionReport(report *models.SessionReport) {
+	info, err := lcw.licenseManager.LoadLicense()
+	if err != nil {
+		return
+	}
+
+	report.LicenseType = string(info.Type)
+	report.LicenseHolder = info.UserName
+	report.LicenseExpires = info.ExpiresAt.Format("2006-01-02")
+	report.LicenseUsed = lcw.usageLedger.TotalBillable()
+	report.LicenseLimit = info.MaxEmails // Đã gồm add-on
+}
@@ -0,0 +1,336 @@
+package licensing
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"linkedin-crawler/internal/utils"
+)
+
+const (
+	// usageLedgerFile lưu usage tích luỹ theo ngày, trong thư mục config của user
+	usageLedgerFile = "usage.ledger"
+	// usageSaveInterval giới hạn tần suất ghi ledger xuống đĩa
+	usageSaveInterval = 5 * time.Second
+	usageDayFormat    = "2006-01-02"
+)
+
+// DailyUsage is the number of emails processed on one day; Success is the
+// billable part (see IsBillableStatus)
+type DailyUsage struct {
+	Date      string `json:"date"`
+	Processed int    `json:"processed"`
+	Success   int    `json:"success"`
+}
+
+// NonBillable returns processed emails that did not consume quota
+func (d DailyUsage) NonBillable() int {
+	return d.Processed - d.Success
+}
+
+// LicenseUpgrade records a license swap that carried the ledger over
+type LicenseUpgrade struct {
+	At       time.Time   `json:"at"`
+	FromType LicenseType `json:"from_type"`
+	ToType   LicenseType `json:"to_type"`
+	From     string      `json:"from"` // Fingerprint license cũ
+	To       string      `json:"to"`   // Fingerprint license mới
+	Used     int         `json:"used"` // Tổng emails billable tại thời điểm nâng cấp
+}
+
+// UsageLedger persists cumulative email usage per day across sessions
+type UsageLedger struct {
+	path     string
+	lm       *LicenseManager
+	mutex    sync.Mutex
+	days     map[string]*DailyUsage
+	license  string // Fingerprint của license sở hữu ledger
+	upgrades []LicenseUpgrade
+	dirty    bool
+	lastSave time.Time
+}
+
+type usageLedgerData struct {
+	Days     []DailyUsage     `json:"days"`
+	License  string           `json:"license,omitempty"`
+	Upgrades []LicenseUpgrade `json:"upgrades,omitempty"`
+	Checksum string           `json:"checksum"`
+}
+
+var (
+	defaultLedger     *UsageLedger
+	defaultLedgerOnce sync.Once
+)
+
+// DefaultUsageLedger returns the process-wide ledger backed by usage.ledger
+func DefaultUsageLedger() *UsageLedger {
+	defaultLedgerOnce.Do(func() {
+		defaultLedger = NewUsageLedger(usageLedgerPath())
+		if err := defaultLedger.Load(); err != nil {
+			fmt.Printf("⚠️ Không thể đọc usage ledger: %v\n", err)
+		}
+	})
+	return defaultLedger
+}
+
+// usageLedgerPath returns usage.ledger in the user config directory, so the
+// usage does not depend on the directory the crawler is started from. A ledger
+// older versions left in the working directory is moved there.
+func usageLedgerPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		fmt.Printf("⚠️ Không tìm thấy thư mục config, usage ledger lưu ở thư mục hiện tại: %v\n", err)
+		return usageLedgerFile
+	}
+	path := filepath.Join(configDir, "linkedin-crawler", usageLedgerFile)
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return path
+	}
+	legacy, err := os.ReadFile(usageLedgerFile)
+	if err != nil {
+		return path
+	}
+	if err := writeUsageLedger(path, legacy); err != nil {
+		fmt.Printf("⚠️ Không thể chuyển usage ledger sang %s: %v\n", path, err)
+		return usageLedgerFile
+	}
+	os.Remove(usageLedgerFile)
+	fmt.Printf("📦 Đã chuyển usage ledger sang %s\n", path)
+	return path
+}
+
+// writeUsageLedger atomically writes an encoded ledger readable only by the user
+func writeUsageLedger(path string, data []byte) error {
+	if err := utils.WriteFileAtomic(path, data); err != nil {
+		return err
+	}
+	// File mới được tạo với quyền 0644; lần ghi sau giữ nguyên quyền 0600
+	return os.Chmod(path, 0600)
+}
+
+// NewUsageLedger creates an empty ledger stored at path
+func NewUsageLedger(path string) *UsageLedger {
+	return &UsageLedger{
+		path: path,
+		lm:   NewLicenseManager(),
+		days: make(map[string]*DailyUsage),
+	}
+}
+
+// Load reads the ledger from disk; a missing file is an empty ledger
+func (ul *UsageLedger) Load() error {
+	raw, err := os.ReadFile(ul.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(raw))
+	if err != nil {
+		return fmt.Errorf("invalid usage ledger format")
+	}
+
+	var data usageLedgerData
+	if err := json.Unmarshal(ul.lm.xorEncrypt(decoded, ul.lm.secretKey), &data); err != nil {
+		return fmt.Errorf("corrupted usage ledger")
+	}
+	if data.Checksum != ul.checksum(data) {
+		return fmt.Errorf("usage ledger has been tampered with")
+	}
+
+	ul.mutex.Lock()
+	defer ul.mutex.Unlock()
+
+	ul.days = make(map[string]*DailyUsage, len(data.Days))
+	for i := range data.Days {
+		day := data.Days[i]
+		ul.days[day.Date] = &day
+	}
+	ul.license = data.License
+	ul.upgrades = data.Upgrades
+	return nil
+}
+
+// BindLicense makes fingerprint the owner of the ledger if it has none yet
+func (ul *UsageLedger) BindLicense(fingerprint string) {
+	ul.mutex.Lock()
+	defer ul.mutex.Unlock()
+
+	if ul.license == "" {
+		ul.license = fingerprint
+		ul.dirty = true
+	}
+}
+
+// MigrateLicense carries usage over to an upgraded license and saves the ledger
+func (ul *UsageLedger) MigrateLicense(from, to *LicenseInfo, fromFingerprint, toFingerprint string) error {
+	used := ul.TotalBillable()
+
+	ul.mutex.Lock()
+	ul.upgrades = append(ul.upgrades, LicenseUpgrade{
+		At:       time.Now(),
+		FromType: from.Type,
+		ToType:   to.Type,
+		From:     fromFingerprint,
+		To:       toFingerprint,
+		Used:     used,
+	})
+	ul.license = toFingerprint
+	ul.dirty = true
+	ul.mutex.Unlock()
+
+	return ul.Flush()
+}
+
+// Upgrades returns the license upgrade history
+func (ul *UsageLedger) Upgrades() []LicenseUpgrade {
+	ul.mutex.Lock()
+	defer ul.mutex.Unlock()
+	return append([]LicenseUpgrade(nil), ul.upgrades...)
+}
+
+// Record adds processed/success emails to today's entry
+func (ul *UsageLedger) Record(processed, success int) {
+	ul.mutex.Lock()
+	key := time.Now().Format(usageDayFormat)
+	day := ul.days[key]
+	if day == nil {
+		day = &DailyUsage{Date: key}
+		ul.days[key] = day
+	}
+	day.Processed += processed
+	day.Success += success
+	ul.dirty = true
+	due := time.Since(ul.lastSave) >= usageSaveInterval
+	ul.mutex.Unlock()
+
+	if due {
+		if err := ul.Flush(); err != nil {
+			fmt.Printf("⚠️ Không thể lưu usage ledger: %v\n", err)
+		}
+	}
+}
+
+// Flush writes pending changes to disk
+func (ul *UsageLedger) Flush() error {
+	ul.mutex.Lock()
+	defer ul.mutex.Unlock()
+
+	if !ul.dirty {
+		return nil
+	}
+	if err := ul.save(); err != nil {
+		return err
+	}
+	ul.dirty = false
+	ul.lastSave = time.Now()
+	return nil
+}
+
+// TotalProcessed returns cumulative processed emails over all days
+func (ul *UsageLedger) TotalProcessed() int {
+	ul.mutex.Lock()
+	defer ul.mutex.Unlock()
+
+	total := 0
+	for _, day := range ul.days {
+		total += day.Processed
+	}
+	return total
+}
+
+// TotalBillable returns cumulative billable (status=success) emails over all days
+func (ul *UsageLedger) TotalBillable() int {
+	ul.mutex.Lock()
+	defer ul.mutex.Unlock()
+
+	total := 0
+	for _, day := range ul.days {
+		total += day.Success
+	}
+	return total
+}
+
+// History returns the last n days (oldest first), including days without usage
+func (ul *UsageLedger) History(n int) []DailyUsage {
+	ul.mutex.Lock()
+	defer ul.mutex.Unlock()
+
+	history := make([]DailyUsage, 0, n)
+	today := time.Now()
+	for i := n - 1; i >= 0; i-- {
+		key := today.AddDate(0, 0, -i).Format(usageDayFormat)
+		if day := ul.days[key]; day != nil {
+			history = append(history, *day)
+		} else {
+			history = append(history, DailyUsage{Date: key})
+		}
+	}
+	return history
+}
+
+// ProjectExhaustion estimates when limit is reached at the average daily billable
+// rate of the last window days; ok is false when unlimited, idle or already exhausted
+func (ul *UsageLedger) ProjectExhaustion(limit, window int) (at time.Time, ok bool) {
+	if limit <= 0 || window <= 0 {
+		return time.Time{}, false
+	}
+
+	used := ul.TotalBillable()
+	remaining := limit - used
+	if remaining <= 0 {
+		return time.Time{}, false
+	}
+
+	recent := 0
+	for _, day := range ul.History(window) {
+		recent += day.Success
+	}
+	if recent == 0 {
+		return time.Time{}, false
+	}
+
+	rate := float64(recent) / float64(window)
+	daysLeft := math.Ceil(float64(remaining) / rate)
+	return time.Now().AddDate(0, 0, int(daysLeft)), true
+}
+
+// save writes the ledger; caller must hold the mutex
+func (ul *UsageLedger) save() error {
+	days := make([]DailyUsage, 0, len(ul.days))
+	for _, day := range ul.days {
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+
+	data := usageLedgerData{Days: days, License: ul.license, Upgrades: ul.upgrades}
+	data.Checksum = ul.checksum(data)
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(ul.lm.xorEncrypt(jsonData, ul.lm.secretKey))
+
+	return writeUsageLedger(ul.path, []byte(encoded))
+}
+
+// checksum signs the ledger content so casual edits are detected
+func (ul *UsageLedger) checksum(data usageLedgerData) string {
+	data.Checksum = ""
+	data.Days = append([]DailyUsage(nil), data.Days...)
+	sort.Slice(data.Days, func(i, j int) bool { return data.Days[i].Date < data.Days[j].Date })
+
+	encoded, _ := json.Marshal(data)
+	return ul.lm.generateChecksum(string(encoded))
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Account represents a user account with email and password
 type Account struct {
 	Email    string
@@ -11,4 +13,78 @@ type TokenResult struct {
 	Account Account
 	Token   string
 	Error   error
+	Reason  string // AccountStatus* khi Error != nil
+}
+
+// Account statuses ghi lại kết quả đăng nhập gần nhất của mỗi account
+const (
+	AccountStatusReady         = "ready"          // Đăng nhập được
+	AccountStatusWrongPassword = "wrong_password" // Sai email/password
+	AccountStatusMFARequired   = "mfa_required"   // Bị yêu cầu MFA / bổ sung thông tin
+	AccountStatusLocked        = "locked"         // Bị khóa (smart lockout)
+	AccountStatusRateLimited   = "rate_limited"   // Bị giới hạn đăng nhập tạm thời
+	AccountStatusTimeout       = "timeout"        // Quá thời gian đăng nhập
+	AccountStatusFailed        = "failed"         // Lỗi khác
+	AccountStatusUsed          = "used"           // Đã lấy token, account bị xoá khỏi file
+	AccountStatusBanned        = "banned"         // Token của account liên tục bị chặn sớm
+	AccountStatusDailyCap      = "daily_cap"      // Đã đủ giới hạn trong ngày, không đăng nhập (không lưu làm status)
+)
+
+// Nguyên nhân token hết hiệu lực, lưu ở token_invalidations.cause
+const (
+	TokenInvalidExpired = "expired" // Hết hạn sau thời gian sống bình thường
+	TokenInvalidBanned  = "banned"  // Bị vô hiệu hoá sớm, nghi account bị chặn
+)
+
+// AccountStatusLabel returns a short human label for an account status
+func AccountStatusLabel(status string) string {
+	switch status {
+	case AccountStatusReady:
+		return "Ready"
+	case AccountStatusWrongPassword:
+		return "Bad Password"
+	case AccountStatusMFARequired:
+		return "MFA Required"
+	case AccountStatusLocked:
+		return "Locked"
+	case AccountStatusRateLimited:
+		return "Rate Limited"
+	case AccountStatusTimeout:
+		return "Timeout"
+	case AccountStatusFailed:
+		return "Failed"
+	case AccountStatusUsed:
+		return "Used"
+	case AccountStatusBanned:
+		return "Banned"
+	case AccountStatusDailyCap:
+		return "Daily Cap"
+	}
+	return "Unknown"
+}
+
+// IsBurnedAccountStatus reports whether an account with this status cannot
+// give a token again: already used, wrong password, locked, MFA required or
+// banned
+func IsBurnedAccountStatus(status string) bool {
+	switch status {
+	case AccountStatusUsed, AccountStatusWrongPassword, AccountStatusLocked, AccountStatusMFARequired, AccountStatusBanned:
+		return true
+	}
+	return false
+}
+
+// AccountLedgerEntry is the use of one account in one run
+type AccountLedgerEntry struct {
+	Account     string
+	RunID       string // "" = extraction từ tab Accounts
+	Logins      int
+	Tokens      int // Số lần đăng nhập lấy được token
+	Emails      int // Số email hoàn thành bằng các token đó
+	FirstLogin  time.Time
+	LastLogin   time.Time
+	FinalStatus string // Trạng thái mới nhất trong bảng accounts
+
+	TokensExpired int // Token hết hạn bình thường
+	TokensBanned  int // Token bị vô hiệu hoá sớm
 }
@@ -7,11 +7,202 @@ type Config struct {
 	MaxConcurrency   int64
 	RequestsPerSec   float64
 	RequestTimeout   time.Duration
-	ShutdownTimeout  time.Duration
+	ShutdownTimeout  time.Duration // Thời gian chờ lưu trạng thái khi nhận SIGINT/SIGTERM, ngoài RequestTimeout
 	EmailsFilePath   string
 	TokensFilePath   string
 	AccountsFilePath string
+	OutputFilePath   string // File hit (kết quả), hỗ trợ template
+	OutputMaxSizeMB  int    // Tách hit file thành hit-0001.txt... khi đạt kích thước này (0 = không tách)
+	DBPath           string // SQLite database, hỗ trợ template
+	LogFilePath      string // Crawler log, hỗ trợ template
+	Campaign         string // Giá trị cho {campaign} trong template
 	MinTokens        int
 	MaxTokens        int
-	SleepDuration    time.Duration
+	SleepDuration    time.Duration // Nghỉ thêm sau khi lưu xong trạng thái lúc thoát (0 = thoát ngay)
+	CrawlMode        string        // Chỉ có email (xem CrawlModes)
+	ExtendedProfile  bool          // Lưu thêm headline và company của profile (cần advanced crawling)
+	CaptureFailures  bool          // Ghi lại request/response lỗi để debug
+	CaptureDir       string        // Thư mục chứa debug bundle
+
+	// Lấy token: số account đăng nhập song song, timeout cho mỗi account và
+	// cách đăng nhập (xem LoginMethods)
+	LoginParallelism int
+	LoginTimeout     time.Duration
+	LoginMethod      string
+
+	// Giới hạn mỗi account mỗi ngày (0 = không giới hạn): số lần đăng nhập lấy
+	// token và số token lấy được, đếm trong bảng accounts
+	MaxAccountLoginsPerDay int
+	MaxAccountTokensPerDay int
+
+	// Giới hạn của một lần chạy (0 = không giới hạn): số emails xử lý và số
+	// account đăng nhập lấy token; phần còn lại để dành cho lần chạy sau
+	MaxEmailsPerRun   int
+	MaxAccountsPerRun int
+
+	// Nguồn token theo thứ tự ưu tiên, cách nhau bởi dấu phẩy, vd
+	// "file,accounts,manual" (xem TokenSourceNames, để trống = DefaultTokenSources)
+	TokenSources string
+
+	// Token farm cho nguồn provider: endpoint HTTP và header xác thực
+	// ("Name: value", hoặc chỉ value để gửi làm Authorization)
+	TokenProviderURL  string
+	TokenProviderAuth string
+
+	// Nhịp gửi request ngoài rate limiter (xem PacingProfiles)
+	PacingProfile string
+
+	// Giới hạn tuyệt đối số request theo giờ/ngày (0 = không giới hạn), hết thì
+	// ngủ đến khi cửa sổ mới bắt đầu
+	MaxRequestsPerHour int
+	MaxRequestsPerDay  int
+
+	// Chỉ crawl trong khung giờ "HH:MM"-"HH:MM" (để trống = luôn crawl), theo
+	// time zone IANA (để trống = giờ máy)
+	CrawlWindowStart string
+	CrawlWindowEnd   string
+	CrawlTimeZone    string
+
+	// Xử lý khi ước lượng accounts không đủ cho số emails còn lại (xem AccountShortfallActions)
+	AccountShortfall string
+
+	// Đồng bộ hit lên Google Sheet qua service account (để trống ID = tắt).
+	// SheetsRange là tab/range để append, SheetsInterval là chu kỳ gửi
+	// (0 = gửi ngay từng hit)
+	SheetsSpreadsheetID   string
+	SheetsCredentialsFile string
+	SheetsRange           string
+	SheetsInterval        time.Duration
+
+	// Thông báo lên Slack/Discord webhook (để trống = tắt): bắt đầu, mỗi
+	// milestone, hoàn thành và lỗi. NotifyTemplates là file JSON chứa message
+	// template của từng sự kiện
+	SlackWebhookURL   string
+	DiscordWebhookURL string
+	NotifyTemplates   string
+
+	// Milestone được log ra CLI/GUI và gửi làm sự kiện crawl.milestone: mỗi
+	// NotifyEveryHits hits, mỗi MilestoneEveryProcessed emails đã xử lý và mỗi
+	// MilestoneInterval (0 = tắt luật đó)
+	NotifyEveryHits         int
+	MilestoneEveryProcessed int
+	MilestoneInterval       time.Duration
+
+	// Outbound webhook (Zapier/Make...) nhận mọi sự kiện theo schema JSON cố
+	// định; WebhookHitEvents bật thêm sự kiện profile.found cho từng hit
+	WebhookURL       string
+	WebhookHitEvents bool
+
+	// Lấy danh sách emails từ câu query Postgres/MySQL thay cho file (để trống
+	// DSN = đọc EmailsFilePath như bình thường). Query chạy lại mỗi lần chạy và
+	// kết quả được ghi đè vào EmailsFilePath
+	EmailsDBDriver string
+	EmailsDBDSN    string
+	EmailsDBQuery  string
+
+	// Khi file emails bị sửa trong lúc crawl (xem EmailsFileEditModes): import
+	// các dòng thêm vào cuối file hoặc khoá file chỉ đọc đến khi xong
+	EmailsFileEdits string
+
+	// Import emails từ hộp thư IMAP (TLS): lấy địa chỉ trong các header
+	// IMAPFields ("from,to,cc") của message trong IMAPSinceDays ngày gần
+	// đây. IMAPDomains chỉ giữ các domain này, IMAPExclude bỏ địa chỉ chứa
+	// một trong các chuỗi (đều phân cách bằng dấu phẩy)
+	IMAPServer      string
+	IMAPUsername    string
+	IMAPPassword    string
+	IMAPMailbox     string
+	IMAPSinceDays   int
+	IMAPFields      string
+	IMAPDomains     string
+	IMAPExclude     string
+	IMAPMaxMessages int
+
+	// Snapshot emails.db và results.csv vào BackupDir/<thời gian> mỗi
+	// BackupInterval trong lúc crawl (0 = tắt); chỉ giữ BackupKeep bản mới
+	// nhất (0 = giữ tất cả)
+	BackupDir      string
+	BackupInterval time.Duration
+	BackupKeep     int
+
+	// Khi bắt đầu crawl, xoá emails đã xử lý, results, events, input fields,
+	// cache và run log cũ hơn RetentionDays ngày (0 = giữ mãi)
+	RetentionDays int
+
+	// Email trong console, log file và activity feed của GUI (xem
+	// LogPrivacyModes): giữ nguyên, che một phần hoặc thay bằng hash. Database
+	// và export luôn giữ giá trị đầy đủ
+	LogPrivacy string
+
+	// Chế độ giả lập: không đăng nhập, không gọi mạng; khoảng SimulateHitRate
+	// (0..1) emails trả về profile giả sau độ trễ SimulateLatency
+	Simulate        bool
+	SimulateHitRate float64
+	SimulateLatency time.Duration
+
+	// Endpoint Loki: base URL (để trống = endpoint EU mặc định), header thêm
+	// hoặc ghi đè dạng "Name: value" mỗi dòng, query parameter thêm hoặc ghi
+	// đè dạng "UserLocale=de-DE&PersonaType=User". Dùng cho endpoint theo
+	// vùng, proxy hoặc mock server
+	APIBaseURL     string
+	APIHeaders     string
+	APIQueryParams string
+
+	// Cache response 200 theo email trong emails.db (0 = tắt): chạy lại danh
+	// sách trùng trong thời gian này dùng kết quả cũ, không tốn token và quota
+	ResponseCacheTTL time.Duration
+
+	// Giới hạn bộ nhớ heap (MB, 0 = không giới hạn): gần giới hạn thì dọn
+	// cache và giảm số worker
+	MemoryLimitMB int
 }
+
+// Login methods dùng khi lấy token
+const (
+	LoginMethodDirect  = "direct"  // Flow đăng nhập cố định theo từng bước
+	LoginMethodBrowser = "browser" // Headless browser tự nhận diện bước, bắt token từ network
+	LoginMethodAuto    = "auto"    // direct, lỗi thì thử lại bằng browser
+)
+
+// LoginMethods lists all supported login methods
+var LoginMethods = []string{LoginMethodDirect, LoginMethodBrowser, LoginMethodAuto}
+
+// Pacing profiles: độ trễ thêm vào trước mỗi request để giống người dùng thật
+const (
+	PacingSteady  = "steady"  // Chỉ dùng rate limiter
+	PacingJitter  = "jitter"  // Think time ngẫu nhiên giữa các request
+	PacingBurst   = "burst"   // Gửi một loạt rồi nghỉ
+	PacingNightly = "nightly" // Chậm lại vào ban đêm (giờ máy)
+	PacingHuman   = "human"   // Kết hợp jitter, burst và nightly
+)
+
+// PacingProfiles lists all supported pacing profiles
+var PacingProfiles = []string{PacingSteady, PacingJitter, PacingBurst, PacingNightly, PacingHuman}
+
+// Account shortfall actions: khi số accounts còn lại không đủ theo ước lượng
+const (
+	AccountShortfallWarn = "warn" // Chỉ cảnh báo rồi vẫn chạy
+	AccountShortfallStop = "stop" // Dừng trước khi bắt đầu crawl
+)
+
+// AccountShortfallActions lists all supported account shortfall actions
+var AccountShortfallActions = []string{AccountShortfallWarn, AccountShortfallStop}
+
+// Emails file edit modes: xử lý khi file emails bị sửa trong lúc crawl
+const (
+	EmailsFileEditsImport = "import" // Import các dòng thêm vào cuối file vào hàng chờ
+	EmailsFileEditsLock   = "lock"   // Đặt file chỉ đọc trong lúc crawl
+)
+
+// EmailsFileEditModes lists all supported emails file edit modes
+var EmailsFileEditModes = []string{EmailsFileEditsImport, EmailsFileEditsLock}
+
+// Log privacy modes: cách ghi email vào log
+const (
+	LogPrivacyOff  = "off"  // Ghi đầy đủ
+	LogPrivacyMask = "mask" // j***@example.com
+	LogPrivacyHash = "hash" // email#1a2b3c4d5e, cùng email luôn ra cùng hash
+)
+
+// LogPrivacyModes lists all supported log privacy modes
+var LogPrivacyModes = []string{LogPrivacyOff, LogPrivacyMask, LogPrivacyHash}
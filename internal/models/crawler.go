@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"sync"
@@ -12,24 +13,30 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
+// HitOutput is the destination of hit lines: a plain file or a rotating writer
+type HitOutput interface {
+	io.Writer
+	Sync() error
+	Close() error
+}
+
+// Pacer delays a request on top of the rate limiter (see PacingProfiles)
+type Pacer interface {
+	Wait(ctx context.Context) error
+}
+
 // LinkedInCrawler represents the core LinkedIn crawler
 type LinkedInCrawler struct {
-	Tokens         []string
-	InvalidTokens  map[string]bool
-	CurrentToken   int32
-	Client         *http.Client
-	MaxConcurrency int64
-	Sem            *semaphore.Weighted
-	RateLimiter    <-chan time.Time
-	OutputFile     *os.File
-	BufferedWriter *bufio.Writer
-	OutputMutex    sync.Mutex
-	Stats          struct {
-		Processed   int32
-		Success     int32
-		Failed      int32
-		TokenErrors int32
-	}
+	Tokens            []string
+	InvalidTokens     map[string]bool
+	CurrentToken      int32
+	Client            *http.Client
+	MaxConcurrency    int64
+	Sem               *semaphore.Weighted
+	RateLimiter       <-chan time.Time
+	OutputFile        HitOutput
+	BufferedWriter    *bufio.Writer
+	OutputMutex       sync.Mutex
 	StartTime         time.Time
 	AllTokensFailed   bool
 	TokenMutex        sync.Mutex
@@ -40,6 +47,7 @@ type LinkedInCrawler struct {
 	RequestSemaphore  *semaphore.Weighted
 	RequestTicker     *time.Ticker
 	RequestChan       chan struct{}
+	Pacer             Pacer
 	Ctx               context.Context
 	Cancel            context.CancelFunc
 }
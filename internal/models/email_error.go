@@ -0,0 +1,50 @@
+package models
+
+// Error classes của email thất bại, lưu ở cột emails.error_code
+const (
+	EmailErrorRateLimited = "429"         // Bị giới hạn request
+	EmailErrorForbidden   = "403"         // Bị từ chối truy cập
+	EmailErrorAuth        = "auth"        // Token hết hạn hoặc không hợp lệ (401/424)
+	EmailErrorServer      = "server"      // Lỗi 5xx phía LinkedIn
+	EmailErrorHTTP        = "http"        // Status HTTP khác
+	EmailErrorTimeout     = "timeout"     // Quá thời gian request
+	EmailErrorNetwork     = "network"     // Lỗi kết nối
+	EmailErrorParse       = "parse_error" // Response 200 nhưng không đọc được profile
+	EmailErrorUnknown     = "unknown"     // Thất bại trước khi có error class
+)
+
+// EmailErrorClasses lists the error classes in display order
+var EmailErrorClasses = []string{
+	EmailErrorRateLimited,
+	EmailErrorForbidden,
+	EmailErrorAuth,
+	EmailErrorServer,
+	EmailErrorHTTP,
+	EmailErrorTimeout,
+	EmailErrorNetwork,
+	EmailErrorParse,
+	EmailErrorUnknown,
+}
+
+// EmailErrorLabel returns a short human label for an error class
+func EmailErrorLabel(class string) string {
+	switch class {
+	case EmailErrorRateLimited:
+		return "Rate limited (429)"
+	case EmailErrorForbidden:
+		return "Forbidden (403)"
+	case EmailErrorAuth:
+		return "Token rejected"
+	case EmailErrorServer:
+		return "Server error"
+	case EmailErrorHTTP:
+		return "Other HTTP error"
+	case EmailErrorTimeout:
+		return "Timeout"
+	case EmailErrorNetwork:
+		return "Network error"
+	case EmailErrorParse:
+		return "Parse error"
+	}
+	return "Unknown"
+}
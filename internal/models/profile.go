@@ -6,4 +6,8 @@ type ProfileData struct {
 	LinkedInURL     string
 	ConnectionCount string
 	Location        string
+	Headline        string // Chỉ giữ khi Config.ExtendedProfile (advanced crawling)
+	Company         string // Công ty hiện tại, như Headline
+	Parser          string // Tên parser đã trích xuất được dữ liệu
+	Source          string // email hoặc simulated (xem SourceEmail)
 }
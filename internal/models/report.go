@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// DomainStat aggregates crawl results for one email domain
+type DomainStat struct {
+	Domain  string
+	Total   int
+	Success int
+	Failed  int
+	Pending int
+	Hits    int // Emails có thông tin LinkedIn
+
+	// Từ hit file (xem utils.ApplyHitProfileStats)
+	TopCountry     string
+	AvgConnections float64 // Trung bình connections của các hit có số liệu ("500+" tính là 500)
+}
+
+// HitRate returns hits as a percentage of processed (success + failed) emails
+func (d DomainStat) HitRate() float64 {
+	processed := d.Success + d.Failed
+	if processed == 0 {
+		return 0
+	}
+	return float64(d.Hits) * 100 / float64(processed)
+}
+
+// SessionReport summarises one crawl run for sharing with clients
+type SessionReport struct {
+	GeneratedAt time.Time
+	RunID       string
+	StartedAt   time.Time
+	FinishedAt  time.Time
+
+	// Cấu hình đã dùng cho lần chạy
+	Campaign       string
+	CrawlMode      string
+	MaxConcurrency int64
+	RequestsPerSec float64
+	PacingProfile  string
+
+	Processed      int
+	Hits           int
+	NoInfo         int
+	Failed         int
+	Pending        int // Emails còn chờ trong database lúc tạo report
+	Requests       int
+	RateLimited    int
+	LoginAttempts  int
+	TokensObtained int
+
+	// License lúc tạo report (để trống khi không có license)
+	LicenseType    string
+	LicenseHolder  string
+	LicenseExpires string
+	LicenseUsed    int // Tổng emails billable đã dùng
+	LicenseLimit   int // MaxEmails kể cả add-on (0 = không giới hạn)
+
+	TopDomains []DomainStat
+}
+
+// Duration returns how long the run took
+func (r SessionReport) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// HitRate returns the percentage of processed emails that had a profile
+func (r SessionReport) HitRate() float64 {
+	if r.Processed == 0 {
+		return 0
+	}
+	return float64(r.Hits) * 100 / float64(r.Processed)
+}
@@ -0,0 +1,52 @@
+package models
+
+import "strings"
+
+// Workflow status của kết quả, lưu ở cột results.workflow_status
+const (
+	ResultStatusNew       = "new"       // Chưa xử lý
+	ResultStatusContacted = "contacted" // Đã liên hệ
+	ResultStatusIgnored   = "ignored"   // Bỏ qua
+)
+
+// ResultStatuses lists the workflow statuses in display order
+var ResultStatuses = []string{ResultStatusNew, ResultStatusContacted, ResultStatusIgnored}
+
+// ResultStatusLabel returns the display label of a workflow status
+func ResultStatusLabel(status string) string {
+	switch status {
+	case ResultStatusContacted:
+		return "Contacted"
+	case ResultStatusIgnored:
+		return "Ignored"
+	}
+	return "New"
+}
+
+// ParseResultStatus returns the workflow status of a status or its label
+// (case-insensitive); ok is false for anything else
+func ParseResultStatus(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, status := range ResultStatuses {
+		if value == status {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// ParseTags splits comma-separated tags, trimming them and dropping empty and
+// repeated (case-insensitive) ones
+func ParseTags(text string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,16 @@
+package models
+
+// Crawl modes
+const (
+	CrawlModeEmail = "email" // Input là danh sách email
+)
+
+// Result sources ghi vào cột source của hit.txt và bảng emails
+const (
+	SourceEmail = "email"
+
+	SourceSimulated = "simulated" // Kết quả giả của chế độ giả lập
+)
+
+// CrawlModes lists all supported crawl modes
+var CrawlModes = []string{CrawlModeEmail}
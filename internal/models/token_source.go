@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Nguồn token, sắp theo thứ tự ưu tiên trong Config.TokenSources
+const (
+	TokenSourceFile     = "file"     // Token pool trong tokens file
+	TokenSourceAccounts = "accounts" // Đăng nhập accounts để lấy token
+	TokenSourceManual   = "manual"   // Hỏi user dán token (dialog GUI hoặc terminal)
+	TokenSourceProvider = "provider" // Token farm qua HTTP (TokenProviderURL)
+)
+
+// TokenSourceNames lists all supported token sources
+var TokenSourceNames = []string{TokenSourceFile, TokenSourceAccounts, TokenSourceManual, TokenSourceProvider}
+
+// DefaultTokenSources is the order used when Config.TokenSources is empty
+const DefaultTokenSources = TokenSourceFile + "," + TokenSourceAccounts
+
+// ParseTokenSources splits a comma separated list of token sources in priority
+// order; empty means DefaultTokenSources
+func ParseTokenSources(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		value = DefaultTokenSources
+	}
+
+	var sources []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(TokenSourceNames, name) {
+			return nil, fmt.Errorf("unknown token source %q (use %s)", name, strings.Join(TokenSourceNames, ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("token source %q is listed twice", name)
+		}
+		seen[name] = true
+		sources = append(sources, name)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no token source in %q", value)
+	}
+	return sources, nil
+}
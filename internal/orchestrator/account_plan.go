@@ -0,0 +1,97 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"linkedin-crawler/internal/models"
+)
+
+// ErrAccountShortfall is returned when the account pool is not expected to
+// cover the remaining emails and AccountShortfall is set to stop
+var ErrAccountShortfall = errors.New("not enough accounts for remaining emails")
+
+// minPlanLoginAttempts là số lần đăng nhập tối thiểu trong lịch sử trước khi ước lượng
+const minPlanLoginAttempts = 5
+
+// AccountPlan estimates whether the remaining accounts can cover the remaining
+// emails, based on the token yield of earlier runs
+type AccountPlan struct {
+	RemainingEmails  int
+	AccountsLeft     int
+	TokensOnHand     int
+	TokensPerAccount float64
+	EmailsPerToken   float64
+	AccountsNeeded   int
+	HasHistory       bool // false khi chưa đủ dữ liệu để ước lượng
+}
+
+// Covered reports whether the account pool is expected to be enough
+func (p AccountPlan) Covered() bool {
+	return !p.HasHistory || p.AccountsNeeded <= p.AccountsLeft
+}
+
+// EmailsCoverable returns how many emails the tokens on hand and the
+// remaining accounts are expected to complete
+func (p AccountPlan) EmailsCoverable() int {
+	tokens := float64(p.TokensOnHand) + float64(p.AccountsLeft)*p.TokensPerAccount
+	return int(tokens * p.EmailsPerToken)
+}
+
+// planAccounts builds the account plan for the current state
+func (bp *BatchProcessor) planAccounts(tokensOnHand int) (AccountPlan, error) {
+	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
+	plan := AccountPlan{
+		RemainingEmails: bp.autoCrawler.stateManager.CountRemainingEmails(),
+		AccountsLeft:    len(bp.autoCrawler.GetAccounts()) - bp.autoCrawler.GetUsedAccountIndex(),
+		TokensOnHand:    tokensOnHand,
+	}
+
+	history, err := emailStorage.GetYieldHistory()
+	if err != nil {
+		return plan, err
+	}
+	plan.TokensPerAccount = history.TokensPerAccount()
+	plan.EmailsPerToken = history.EmailsPerToken()
+	plan.HasHistory = history.LoginAttempts >= minPlanLoginAttempts &&
+		plan.TokensPerAccount > 0 && plan.EmailsPerToken > 0
+	if !plan.HasHistory {
+		return plan, nil
+	}
+
+	tokensNeeded := float64(plan.RemainingEmails)/plan.EmailsPerToken - float64(tokensOnHand)
+	if tokensNeeded > 0 {
+		plan.AccountsNeeded = int(math.Ceil(tokensNeeded / plan.TokensPerAccount))
+	}
+	return plan, nil
+}
+
+// checkAccountPlan logs the account estimate and, when AccountShortfall is
+// stop, returns ErrAccountShortfall if the pool won't cover the remaining emails
+func (bp *BatchProcessor) checkAccountPlan(tokensOnHand int, enforce bool) error {
+	plan, err := bp.planAccounts(tokensOnHand)
+	if err != nil {
+		bp.logWarning("⚠️ Không thể ước lượng số accounts cần dùng: %v", err)
+		return nil
+	}
+	if !plan.HasHistory {
+		bp.logInfo("📐 Chưa đủ dữ liệu lịch sử để ước lượng số accounts cần dùng")
+		return nil
+	}
+
+	bp.logInfo("📐 Ước lượng: %.2f tokens/account, %.0f emails/token → cần ~%d accounts cho %d emails (còn %d accounts)",
+		plan.TokensPerAccount, plan.EmailsPerToken, plan.AccountsNeeded, plan.RemainingEmails, plan.AccountsLeft)
+	if plan.Covered() {
+		return nil
+	}
+
+	message := fmt.Sprintf("Accounts còn lại chỉ đủ cho khoảng %d/%d emails (thiếu ~%d accounts)",
+		plan.EmailsCoverable(), plan.RemainingEmails, plan.AccountsNeeded-plan.AccountsLeft)
+	if enforce && bp.autoCrawler.GetConfig().AccountShortfall == models.AccountShortfallStop {
+		bp.logError("🛑 %s - dừng trước khi chạy", message)
+		return fmt.Errorf("%w: %s", ErrAccountShortfall, message)
+	}
+	bp.logWarning("⚠️ %s - hãy thêm accounts", message)
+	return nil
+}
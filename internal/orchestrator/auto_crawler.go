@@ -3,12 +3,16 @@ package orchestrator
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"linkedin-crawler/internal/crawler"
+	"linkedin-crawler/internal/integrations"
 	"linkedin-crawler/internal/licensing"
 	"linkedin-crawler/internal/models"
 	"linkedin-crawler/internal/storage"
@@ -24,13 +28,28 @@ type AutoCrawler struct {
 	crawlerMutex      sync.RWMutex
 	outputFile        string
 	totalEmails       []string
+	addedEmails       int64 // Dòng thêm vào hàng chờ trong lúc crawl
 	processedEmails   int
 	shutdownRequested int32
+	pauseRequested    int32 // 1 = tạm dừng trước request tiếp theo
 
 	logFile      *os.File
 	logWriter    *bufio.Writer
 	logChan      chan string
 	logWaitGroup sync.WaitGroup
+	logMutex     sync.RWMutex
+	logClosed    bool // logChan đã đóng bởi flushLogs
+
+	// running = 1 từ khi Run bắt đầu; runDone đóng khi Run đã lưu xong trạng thái
+	running int32
+	runDone chan struct{}
+
+	// Mỗi lần chạy có ID riêng và log riêng ở logs/run-<id>.log; crawler log
+	// chung vẫn nhận mọi dòng, gắn thêm run ID
+	runID        string
+	runLogPath   string
+	runLogFile   *os.File
+	runLogWriter *bufio.Writer
 
 	// File operation mutex để tránh race condition
 	fileOpMutex sync.Mutex
@@ -41,27 +60,114 @@ type AutoCrawler struct {
 	accountStorage *storage.AccountStorage
 
 	// Processing services
+	stats          *StatsService
 	batchProcessor *BatchProcessor
 	retryHandler   *RetryHandler
 	stateManager   *StateManager
 
 	// Database cleanup flag
 	dbCleanupDone int32
+
+	// Khung giờ được phép crawl (nil = luôn được phép)
+	crawlWindow *utils.CrawlWindow
+
+	// Đồng bộ hit lên Google Sheet (nil = tắt)
+	sheetsSync *integrations.SheetsSync
+
+	// Thông báo Slack/Discord/outbound webhook (nil = tắt)
+	notifications *integrations.Notifications
+
+	// Luật milestone (nil = tắt), được kiểm tra định kỳ trong lúc crawl
+	milestones    *integrations.Milestones
+	milestoneStop chan struct{}
+	milestoneDone chan struct{}
+
+	// Snapshot định kỳ (nil = tắt)
+	backupStop chan struct{}
+	backupDone chan struct{}
+
+	// Lưu số liệu lần chạy định kỳ vào bảng runs
+	checkpointStop chan struct{}
+	checkpointDone chan struct{}
+
+	// Luồng NDJSON progress cho --progress=json (nil = tắt)
+	progressOut  io.Writer
+	progressStop chan struct{}
+	progressDone chan struct{}
+
+	// Giới hạn bộ nhớ (MemoryLimitMB = 0 thì tắt)
+	memoryStop          chan struct{}
+	memoryDone          chan struct{}
+	previousMemoryLimit int64
+	memoryMutex         sync.Mutex
+	memoryHandlers      []func(usedMB, limitMB int)
+
+	// Theo dõi file emails bị sửa trong lúc crawl (nil = không theo dõi)
+	emailsWatch *emailsFileWatch
 }
 
 // New creates a new AutoCrawler instance with SQLite integration
 func New(config models.Config) (*AutoCrawler, error) {
-	outputFile := "hit.txt"
+	// Expand path templates ({campaign}, {date}, ...) và tạo thư mục
+	config, err := utils.ResolveConfigPaths(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve output paths: %w", err)
+	}
+	storage.SetDefaultDBPath(config.DBPath)
+	crawler.SetHitFilePath(config.OutputFilePath)
+
+	crawlWindow, err := utils.ParseCrawlWindow(config.CrawlWindowStart, config.CrawlWindowEnd, config.CrawlTimeZone)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := crawler.EndpointFromConfig(config); err != nil {
+		return nil, err
+	}
+
+	outputFile := config.OutputFilePath
+	if outputFile == "" {
+		outputFile = "hit.txt"
+	}
 
 	// Initialize storage services
 	emailStorage := storage.NewEmailStorage()
 	tokenStorage := storage.NewTokenStorage()
 	accountStorage := storage.NewAccountStorage()
+	// Retention và load emails mở database: khởi tạo lỗi thì đóng lại, không để kết nối treo
+	created := false
+	defer func() {
+		if !created {
+			emailStorage.CloseDB()
+		}
+	}()
+
+	// Retention: xoá dữ liệu cũ hơn RetentionDays ngày trước khi load emails
+	if config.RetentionDays > 0 {
+		purged, logs, err := emailStorage.ApplyRetention(config, config.RetentionDays)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply retention: %w", err)
+		}
+		if purged.Total() > 0 || logs > 0 {
+			fmt.Printf("🧹 Retention %d ngày: đã xoá %s, %d run log\n", config.RetentionDays, purged, logs)
+		}
+	}
 
 	// Load accounts
 	accounts, err := accountStorage.LoadAccounts(config.AccountsFilePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load accounts: %w", err)
+		if !config.Simulate {
+			return nil, fmt.Errorf("failed to load accounts: %w", err)
+		}
+		accounts = nil // Giả lập chạy được khi chưa có accounts
+	}
+
+	// Email source là database: chạy lại query và ghi đè file emails trước khi load
+	if config.EmailsDBDSN != "" {
+		count, err := integrations.RefreshEmailsFromSource(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load emails from database: %w", err)
+		}
+		fmt.Printf("🗄️ Đã lấy %d dòng từ email source (%s) vào %s\n", count, config.EmailsDBDriver, config.EmailsFilePath)
 	}
 
 	// Load emails and import to SQLite (with validation and deduplication)
@@ -71,11 +177,27 @@ func New(config models.Config) (*AutoCrawler, error) {
 	}
 
 	// Setup logging
-	logFile, err := os.OpenFile("crawler.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	logFilePath := config.LogFilePath
+	if logFilePath == "" {
+		logFilePath = "crawler.log"
+	}
+	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open log file: %w", err)
 	}
 
+	runID := utils.NewRunID(time.Now())
+	runLogPath := utils.RunLogPath(logFilePath, runID)
+	if err := os.MkdirAll(filepath.Dir(runLogPath), 0755); err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("failed to create run log directory: %w", err)
+	}
+	runLogFile, err := os.OpenFile(runLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("failed to open run log file: %w", err)
+	}
+
 	ac := &AutoCrawler{
 		config:           config,
 		accounts:         accounts,
@@ -86,7 +208,13 @@ func New(config models.Config) (*AutoCrawler, error) {
 		logFile:          logFile,
 		logWriter:        bufio.NewWriter(logFile),
 		logChan:          make(chan string, 1000),
+		runDone:          make(chan struct{}),
+		runID:            runID,
+		runLogPath:       runLogPath,
+		runLogFile:       runLogFile,
+		runLogWriter:     bufio.NewWriter(runLogFile),
 		dbCleanupDone:    0,
+		crawlWindow:      crawlWindow,
 
 		// Initialize storage services
 		emailStorage:   emailStorage,
@@ -95,7 +223,10 @@ func New(config models.Config) (*AutoCrawler, error) {
 	}
 
 	// Initialize processing services
+	ac.stats = NewStatsService(emailStorage)
 	ac.batchProcessor = NewBatchProcessor(ac)
+	ac.startSheetsSync()
+	ac.startNotifications()
 	ac.retryHandler = NewRetryHandler(ac)
 	ac.stateManager = NewStateManager(ac)
 
@@ -104,18 +235,23 @@ func New(config models.Config) (*AutoCrawler, error) {
 	go func() {
 		defer ac.logWaitGroup.Done()
 		for line := range ac.logChan {
-			_, err := ac.logWriter.WriteString(line + "\n")
+			_, err := ac.logWriter.WriteString("[" + ac.runID + "] " + line + "\n")
+			if err == nil {
+				_, err = ac.runLogWriter.WriteString(line + "\n")
+			}
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "⚠️ Lỗi ghi log: %v\n", err)
 			}
 		}
 		ac.logWriter.Flush()
 		ac.logFile.Close()
+		ac.runLogWriter.Flush()
+		ac.runLogFile.Close()
 	}()
-	//ac.stateManager.SaveStateOnShutdown()
 	// Setup signal handling
-	utils.SetupSignalHandling(&ac.shutdownRequested, ac.gracefulShutdown, config.SleepDuration)
+	utils.SetupSignalHandling(&ac.shutdownRequested, ac.shutdownOnSignal)
 
+	created = true
 	return ac, nil
 }
 
@@ -128,25 +264,44 @@ func (ac *AutoCrawler) gracefulShutdown() {
 
 	fmt.Println("🔄 Thực hiện graceful shutdown...")
 
+	// Mở khoá file emails trước khi ghi lại emails pending
+	ac.stopEmailsWatcher()
+
 	// Save state including exporting pending emails
 	ac.stateManager.SaveStateOnShutdown()
 }
 
 // Run starts the crawling process with SQLite integration
-func (ac *AutoCrawler) Run() error {
-	defer func() {
-		// Ensure cleanup on exit
-		ac.gracefulShutdown()
-
-		if atomic.LoadInt32(&ac.shutdownRequested) == 0 {
-			fmt.Printf("💤 Sleep %v trước khi thoát...\n", ac.config.SleepDuration)
-			time.Sleep(ac.config.SleepDuration)
-		}
-	}()
+func (ac *AutoCrawler) Run() (err error) {
+	atomic.StoreInt32(&ac.running, 1)
+	defer close(ac.runDone)
+	// Export pending emails và flush log sau mọi bước bên dưới
+	defer ac.finishRun()
+	// Ghi thống kê lần chạy và gửi nốt hits lên Google Sheets trước khi shutdown,
+	// snapshot cuối cùng chạy sau khi đã ghi thống kê
+	defer ac.stopProgress()
+	defer ac.stopMemoryGuard()
+	defer ac.stopBackups()
+	defer ac.recordRun()
+	defer ac.stopRunCheckpoints()
+	defer ac.stopSheetsSync()
+	defer ac.stopEmailsWatcher()
+	ac.batchProcessor.startRun()
+	ac.startEmailsWatcher()
+	ac.startBackups()
+	ac.startProgress()
+	ac.startRunCheckpoints()
+	ac.startMemoryGuard()
+	ac.notify(integrations.EventStarted, nil)
+	defer func() { ac.notifyFinished(err) }()
+	// Dừng kiểm tra milestone trước khi gửi sự kiện hoàn thành
+	ac.startMilestones()
+	defer ac.stopMilestones()
 
 	fmt.Printf("🚀 Bắt đầu Auto LinkedIn Crawler với SQLite\n")
+	fmt.Printf("🆔 Run ID: %s (log: %s)\n", ac.runID, ac.runLogPath)
 	fmt.Printf("📊 Tổng số accounts: %d\n", len(ac.accounts))
-	fmt.Printf("📧 Tổng số emails: %d\n", len(ac.totalEmails))
+	fmt.Printf("📧 Tổng số emails: %d\n", ac.TotalEmailCount())
 	fmt.Printf("🎯 Sẽ lấy %d tokens mỗi lần\n", ac.config.MaxTokens)
 
 	// Show initial SQLite stats
@@ -155,28 +310,51 @@ func (ac *AutoCrawler) Run() error {
 	fmt.Println(strings.Repeat("=", 80))
 
 	// Phase 1 - Xử lý tất cả emails
-	if err := ac.batchProcessor.ProcessAllEmails(); err != nil {
+	if err = ac.batchProcessor.ProcessAllEmails(); err != nil {
 		return err
 	}
 
-	// Phase 2 - Retry emails thất bại (only if not shutting down)
-	if atomic.LoadInt32(&ac.shutdownRequested) == 0 {
+	// Phase 2 - Retry emails thất bại (only if not shutting down, không retry khi giới hạn số emails)
+	if atomic.LoadInt32(&ac.shutdownRequested) == 0 && ac.batchProcessor.emailLimit == 0 {
 		if err := ac.retryHandler.RetryFailedEmails(); err != nil {
 			fmt.Printf("⚠️ Lỗi khi retry emails bị thất bại: %v\n", err)
 		}
 	}
 
-	close(ac.logChan)
-	ac.logWaitGroup.Wait()
-
 	// Print final results
 	ac.printFinalResults()
 
+	// Đóng gói debug bundle nếu capture mode bật
+	ac.exportDebugBundle()
+
 	return nil
 }
 
-// LogLine adds a line to the log channel
+// exportDebugBundle zips captured failures so they can be attached to a bug report
+func (ac *AutoCrawler) exportDebugBundle() {
+	capture := ac.batchProcessor.GetCapture()
+	if capture == nil || capture.Count() == 0 {
+		return
+	}
+
+	bundlePath := filepath.Join(capture.Dir(), fmt.Sprintf("debug-bundle-%s.zip", time.Now().Format("20060102-150405")))
+	if err := capture.ExportBundle(bundlePath); err != nil {
+		fmt.Printf("⚠️ Không thể tạo debug bundle: %v\n", err)
+		return
+	}
+
+	fmt.Printf("🐞 Đã lưu %d captures vào debug bundle: %s\n", capture.Count(), bundlePath)
+}
+
+// LogLine adds a line to the log channel, with emails hidden according to
+// LogPrivacy
 func (ac *AutoCrawler) LogLine(line string) {
+	line = utils.PrivateText(line, ac.config.LogPrivacy)
+	ac.logMutex.RLock()
+	defer ac.logMutex.RUnlock()
+	if ac.logClosed {
+		return
+	}
 	select {
 	case ac.logChan <- line:
 	default:
@@ -184,39 +362,26 @@ func (ac *AutoCrawler) LogLine(line string) {
 	}
 }
 
-// printFinalResults prints the final crawling results using SQLite stats
+// printFinalResults prints the final crawling results from the refreshed StatsService
 func (ac *AutoCrawler) printFinalResults() {
 	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("🎉 HOÀN THÀNH AUTO LINKEDIN CRAWLER!")
 	fmt.Println(strings.Repeat("=", 80))
 
-	// Tạo một storage mới để chắc chắn DB chưa bị closed
-	fresh := storage.NewEmailStorage()
-	if err := fresh.InitDB(); err != nil {
-		fmt.Printf("⚠️ Không thể mở database để lấy stats cuối cùng: %v\n", err)
-		fmt.Printf("📁 Kết quả có thể xem trong file: %s\n", ac.outputFile)
-		return
-	}
-	defer func() {
-		if err := fresh.CloseDB(); err != nil {
-			fmt.Printf("⚠️ Lỗi khi đóng database: %v\n", err)
-		}
-	}()
-
-	// Lấy stats thực sự
-	stats, err := fresh.GetEmailStats()
-	if err != nil {
+	// Đọc lại tổng số từ database: retry phase ghi thẳng vào DB
+	if err := ac.stats.Refresh(); err != nil {
 		fmt.Printf("⚠️ Không thể lấy stats cuối cùng: %v\n", err)
 		fmt.Printf("📁 Kết quả có thể xem trong file: %s\n", ac.outputFile)
 		return
 	}
+	stats := ac.stats.Snapshot()
 
-	totalOriginal := len(ac.totalEmails)
-	successCount := stats["success"]
-	failedCount := stats["failed"]
-	pendingCount := stats["pending"]
-	hasInfoCount := stats["has_info"]
-	noInfoCount := stats["no_info"]
+	totalOriginal := ac.TotalEmailCount()
+	successCount := stats.Success
+	failedCount := stats.Failed
+	pendingCount := stats.Pending
+	hasInfoCount := stats.HasInfo
+	noInfoCount := stats.NoInfo
 
 	successPercent := 0.0
 	if totalOriginal > 0 {
@@ -231,6 +396,11 @@ func (ac *AutoCrawler) printFinalResults() {
 	fmt.Printf("   📊 Tổng emails ban đầu:   %d\n", totalOriginal)
 	fmt.Printf("   ✅ Đã xử lý thành công:  %d (%.1f%%)\n", successCount, successPercent)
 	fmt.Printf("   ❌ Thất bại:             %d\n", failedCount)
+	if failedCount > 0 {
+		if classes, err := ac.emailStorage.GetErrorClassStats(); err == nil {
+			fmt.Printf("      %s\n", utils.FormatErrorClassStats(classes))
+		}
+	}
 	fmt.Printf("   ⏳ Chưa xử lý:           %d\n", pendingCount)
 	fmt.Printf("\n")
 	fmt.Printf("   🎯 CÓ THÔNG TIN LINKEDIN: %d emails (%.1f%% trong thành công)\n", hasInfoCount, dataPercent)
@@ -242,24 +412,19 @@ func (ac *AutoCrawler) printFinalResults() {
 		fmt.Printf("\n😔 Không tìm thấy profile LinkedIn nào\n")
 	}
 	if pendingCount > 0 {
-		fmt.Printf("\n💾 Còn %d emails chưa xử lý đã được lưu vào file %s\n", pendingCount, ac.config.EmailsFilePath)
+		fmt.Printf("\n💾 Còn %d emails chưa xử lý đã được lưu vào file %s\n", pendingCount, ac.PendingExportPath())
 	}
 	fmt.Println(strings.Repeat("=", 80))
 }
 
-// PrintCurrentStats prints current processing statistics using SQLite
+// PrintCurrentStats prints the StatsService counters of the crawl
 func (ac *AutoCrawler) PrintCurrentStats() {
-	stats, err := ac.stateManager.GetEmailStats()
-	if err != nil {
-		fmt.Printf("⚠️ Không thể lấy stats: %v\n", err)
-		return
-	}
-
-	total := len(ac.totalEmails)
-	processed := stats["success"] + stats["failed"]
+	stats := ac.stats.Snapshot()
+	total := ac.TotalEmailCount()
+	processed := stats.Processed()
 
 	fmt.Printf("📊 Stats: ✅%d 📭%d ❌%d ⏳%d | Progress: %d/%d (%.1f%%)\n",
-		stats["has_info"], stats["no_info"], stats["failed"], stats["pending"],
+		stats.HasInfo, stats.NoInfo, stats.Failed, stats.Pending,
 		processed, total, float64(processed)*100/float64(total))
 }
 
@@ -272,6 +437,12 @@ func (ac *AutoCrawler) GetTotalEmails() []string {
 	return ac.totalEmails
 }
 
+// TotalEmailCount returns the emails loaded at start plus the rows added to
+// the queue while crawling
+func (ac *AutoCrawler) TotalEmailCount() int {
+	return len(ac.totalEmails) + int(atomic.LoadInt64(&ac.addedEmails))
+}
+
 func (ac *AutoCrawler) GetAccounts() []models.Account {
 	return ac.accounts
 }
@@ -288,6 +459,16 @@ func (ac *AutoCrawler) GetOutputFile() string {
 	return ac.outputFile
 }
 
+// GetRunID returns the ID of this run
+func (ac *AutoCrawler) GetRunID() string {
+	return ac.runID
+}
+
+// GetRunLogPath returns the log file of this run
+func (ac *AutoCrawler) GetRunLogPath() string {
+	return ac.runLogPath
+}
+
 func (ac *AutoCrawler) GetStorageServices() (*storage.EmailStorage, *storage.TokenStorage, *storage.AccountStorage) {
 	return ac.emailStorage, ac.tokenStorage, ac.accountStorage
 }
@@ -339,6 +520,16 @@ func (ac *AutoCrawler) GetBatchProcessor() *BatchProcessor {
 	return ac.batchProcessor
 }
 
+// Stats returns the email counters of the crawl
+func (ac *AutoCrawler) Stats() *StatsService {
+	return ac.stats
+}
+
+// GetCrawlWindow returns the configured crawl window, or nil when crawling is always allowed
+func (ac *AutoCrawler) GetCrawlWindow() *utils.CrawlWindow {
+	return ac.crawlWindow
+}
+
 // SetLicenseWrapper sets license wrapper for all components
 func (ac *AutoCrawler) SetLicenseWrapper(wrapper *licensing.LicensedCrawlerWrapper) {
 	if ac.batchProcessor != nil {
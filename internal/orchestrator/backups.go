@@ -0,0 +1,61 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-crawler/internal/storage"
+)
+
+// startBackups snapshots the database and results every BackupInterval
+// while the crawl runs
+func (ac *AutoCrawler) startBackups() {
+	if ac.config.BackupInterval <= 0 || ac.config.BackupDir == "" {
+		return
+	}
+
+	ac.backupStop = make(chan struct{})
+	ac.backupDone = make(chan struct{})
+	go func() {
+		defer close(ac.backupDone)
+		ticker := time.NewTicker(ac.config.BackupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ac.takeSnapshot()
+			case <-ac.backupStop:
+				return
+			}
+		}
+	}()
+
+	fmt.Printf("💾 Snapshot tự động mỗi %v vào %s\n", ac.config.BackupInterval, ac.config.BackupDir)
+}
+
+// stopBackups stops the backup loop and takes a last snapshot of the run
+func (ac *AutoCrawler) stopBackups() {
+	if ac.backupStop == nil {
+		return
+	}
+	close(ac.backupStop)
+	<-ac.backupDone
+	ac.takeSnapshot()
+}
+
+// takeSnapshot writes one snapshot and deletes those beyond BackupKeep
+func (ac *AutoCrawler) takeSnapshot() {
+	snapshot, err := ac.emailStorage.CreateSnapshot(ac.config.BackupDir)
+	if err != nil {
+		fmt.Printf("⚠️ Không thể tạo snapshot: %v\n", err)
+		return
+	}
+	fmt.Printf("💾 Snapshot %s (%d KB)\n", snapshot.Dir, snapshot.DBSize/1024)
+
+	removed, err := storage.PruneSnapshots(ac.config.BackupDir, ac.config.BackupKeep)
+	if err != nil {
+		fmt.Printf("⚠️ Không thể xóa snapshot cũ: %v\n", err)
+	} else if removed > 0 {
+		fmt.Printf("🧹 Đã xóa %d snapshot cũ (giữ %d)\n", removed, ac.config.BackupKeep)
+	}
+}
@@ -2,8 +2,11 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,8 +16,12 @@ import (
 	"linkedin-crawler/internal/licensing"
 	"linkedin-crawler/internal/models"
 	"linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
 )
 
+// ErrQuotaExhausted is returned when the license has no email quota left
+var ErrQuotaExhausted = errors.New("license email quota exhausted")
+
 // BatchProcessor handles batch processing of emails with GUI logging and license checking
 type BatchProcessor struct {
 	autoCrawler      *AutoCrawler
@@ -26,9 +33,30 @@ type BatchProcessor struct {
 	// GUI logging interface
 	guiLogger GUILogger
 
-	// License tracking
-	processedEmailsCount int32 // Track số emails đã process thành công
-	successEmailsCount   int32 // Track số emails thành công (có kết quả)
+	maxWorkers      int   // Số worker tối đa license cho phép (0 = theo config)
+	workerLimit     int32 // Số worker được chạy khi thiếu bộ nhớ (0 = không giới hạn)
+	extendedProfile bool  // Giữ headline và company (ExtendedProfile đã qua feature gates)
+
+	requestBudget *RequestBudget // Giới hạn request theo giờ/ngày
+
+	tokenSources *TokenSourceManager // Nguồn token theo thứ tự ưu tiên
+	tokenPrompt  TokenPrompt         // Hỏi user dán token (nil = nguồn manual bị bỏ qua)
+
+	rateLimits *rateLimitQueue // Emails bị 429 chờ backoff để thử lại
+
+	// Trạng thái tạm dừng ngoài khung giờ crawl (zero = đang crawl)
+	windowMutex       sync.Mutex
+	windowPausedUntil time.Time
+
+	run runMetrics // Số liệu của lần chạy hiện tại, ghi vào bảng runs
+
+	emailLimit   int // Dừng sau chừng này emails trong lần chạy (0 = không giới hạn, xem SetEmailLimit)
+	accountLimit int // Số account tối đa được đăng nhập trong lần chạy (0 = không giới hạn)
+
+	// Trạng thái token và hit gần nhất cho TUI
+	liveMutex   sync.Mutex
+	tokenHealth map[string]*TokenHealth
+	recentHits  []RecentHit
 }
 
 // GUILogger interface for sending logs to GUI
@@ -42,15 +70,69 @@ type GUILogger interface {
 
 // NewBatchProcessor creates a new BatchProcessor instance
 func NewBatchProcessor(ac *AutoCrawler) *BatchProcessor {
-	return &BatchProcessor{
-		autoCrawler:          ac,
-		tokenExtractor:       auth.NewTokenExtractor(),
-		queryService:         crawler.NewQueryService(),
-		validatorService:     crawler.NewValidatorService(),
-		licenseWrapper:       licensing.NewLicensedCrawlerWrapper(),
-		processedEmailsCount: 0,
-		successEmailsCount:   0,
+	bp := &BatchProcessor{
+		autoCrawler:      ac,
+		tokenExtractor:   auth.NewTokenExtractor(),
+		queryService:     crawler.NewQueryService(),
+		validatorService: crawler.NewValidatorService(),
+		licenseWrapper:   licensing.NewLicensedCrawlerWrapper(),
+		rateLimits:       newRateLimitQueue(),
+	}
+
+	config := ac.GetConfig()
+	// Config đã được kiểm tra trong New
+	if endpoint, err := crawler.EndpointFromConfig(config); err == nil {
+		bp.queryService.SetEndpoint(endpoint)
+		if endpoint.BaseURL != crawler.DefaultAPIBaseURL {
+			fmt.Printf("🌐 API endpoint: %s\n", endpoint.BaseURL)
+		}
+	}
+	if config.Simulate {
+		bp.queryService.SetSimulator(crawler.NewSimulator(config.SimulateHitRate, config.SimulateLatency))
+		fmt.Printf("🧪 Chế độ giả lập: không đăng nhập, không gọi mạng, hit rate %.0f%%\n", config.SimulateHitRate*100)
+	}
+	if config.CaptureFailures {
+		bp.queryService.SetCapture(crawler.NewResponseCapture(config.CaptureDir))
+		fmt.Printf("🐞 Debug capture đang bật, lưu vào thư mục: %s\n", config.CaptureDir)
+	}
+	bp.tokenExtractor.SetParallelism(config.LoginParallelism)
+	bp.tokenExtractor.SetLoginTimeout(config.LoginTimeout)
+	bp.tokenExtractor.SetLoginMethod(config.LoginMethod)
+	bp.tokenExtractor.SetDailyCaps(config.MaxAccountLoginsPerDay, config.MaxAccountTokensPerDay)
+	if config.MaxAccountLoginsPerDay > 0 || config.MaxAccountTokensPerDay > 0 {
+		fmt.Printf("🧮 Giới hạn mỗi account: %d lần đăng nhập, %d token mỗi ngày (0 = không giới hạn)\n",
+			config.MaxAccountLoginsPerDay, config.MaxAccountTokensPerDay)
+	}
+
+	bp.emailLimit, bp.accountLimit = config.MaxEmailsPerRun, config.MaxAccountsPerRun
+	if bp.emailLimit > 0 || bp.accountLimit > 0 {
+		fmt.Printf("🎯 Giới hạn lần chạy: %s emails, %s accounts\n", formatRunLimit(bp.emailLimit), formatRunLimit(bp.accountLimit))
 	}
+
+	sources, _ := models.ParseTokenSources(config.TokenSources)
+	bp.tokenSources = newTokenSourceManager(bp, sources)
+	if strings.Join(sources, ",") != models.DefaultTokenSources {
+		fmt.Printf("🔑 Nguồn token: %s\n", strings.Join(bp.tokenSources.Names(), " → "))
+	}
+
+	bp.requestBudget = NewRequestBudget(config.MaxRequestsPerHour, config.MaxRequestsPerDay, RequestBudgetPath(config.DBPath))
+	if bp.requestBudget.Enabled() {
+		hour, day := bp.requestBudget.Usage()
+		fmt.Printf("🧮 Request budget: %d/%d giờ này, %d/%d hôm nay (0 = không giới hạn)\n",
+			hour, config.MaxRequestsPerHour, day, config.MaxRequestsPerDay)
+	}
+
+	return bp
+}
+
+// SetTokenPrompt sets how the manual token source asks the user for tokens
+func (bp *BatchProcessor) SetTokenPrompt(prompt TokenPrompt) {
+	bp.tokenPrompt = prompt
+}
+
+// GetCapture returns the debug capture, or nil when capture mode is off
+func (bp *BatchProcessor) GetCapture() *crawler.ResponseCapture {
+	return bp.queryService.GetCapture()
 }
 
 // SetGUILogger sets the GUI logger interface
@@ -63,70 +145,99 @@ func (bp *BatchProcessor) SetLicenseWrapper(wrapper *licensing.LicensedCrawlerWr
 	bp.licenseWrapper = wrapper
 }
 
-// logInfo logs info message to GUI instead of console
+// logInfo logs info message to GUI instead of console (CLI: only with -v)
 func (bp *BatchProcessor) logInfo(format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
+	message := bp.privateText(fmt.Sprintf(format, args...))
 	if bp.guiLogger != nil {
 		bp.guiLogger.LogInfo(message)
+	} else if utils.ConsoleAtLeast(utils.ConsoleVerbose) {
+		fmt.Println(message)
 	}
 }
 
 // logWarning logs warning message to GUI instead of console
 func (bp *BatchProcessor) logWarning(format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
+	message := bp.privateText(fmt.Sprintf(format, args...))
 	if bp.guiLogger != nil {
 		bp.guiLogger.LogWarning(message)
+	} else if utils.ConsoleAtLeast(utils.ConsoleVerbose) {
+		fmt.Println(message)
 	}
 }
 
 // logError logs error message to GUI instead of console
 func (bp *BatchProcessor) logError(format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
+	message := bp.privateText(fmt.Sprintf(format, args...))
 	if bp.guiLogger != nil {
 		bp.guiLogger.LogError(message)
+	} else if utils.ConsoleAtLeast(utils.ConsoleVerbose) {
+		fmt.Println(message)
 	}
 }
 
 // logSuccess logs success message to GUI instead of console
 func (bp *BatchProcessor) logSuccess(format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
+	message := bp.privateText(fmt.Sprintf(format, args...))
 	if bp.guiLogger != nil {
 		bp.guiLogger.LogSuccess(message)
+	} else if utils.ConsoleAtLeast(utils.ConsoleVerbose) {
+		fmt.Println(message)
 	}
 }
 
 // updateProgress updates progress in GUI
 func (bp *BatchProcessor) updateProgress(processed, total int, format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
+	message := bp.privateText(fmt.Sprintf(format, args...))
 	if bp.guiLogger != nil {
 		bp.guiLogger.UpdateProgress(processed, total, message)
 	}
 }
 
-// checkLicenseLimitsBeforeProcessing kiểm tra license trước khi process emails
-func (bp *BatchProcessor) checkLicenseLimitsBeforeProcessing(emailsToProcess int) error {
+// privateText hides the emails in a log message according to LogPrivacy
+func (bp *BatchProcessor) privateText(message string) string {
+	return utils.PrivateText(message, bp.autoCrawler.config.LogPrivacy)
+}
+
+// fitBatchToQuota trims emails to the remaining license quota and reports the withheld ones
+func (bp *BatchProcessor) fitBatchToQuota(emails []string) ([]string, error) {
 	if bp.licenseWrapper == nil {
-		return fmt.Errorf("license not initialized")
+		return nil, fmt.Errorf("license not initialized")
 	}
 
-	// Lấy thống kê hiện tại
-	currentProcessed := atomic.LoadInt32(&bp.processedEmailsCount)
+	quota, err := bp.licenseWrapper.RemainingQuota()
+	if err != nil {
+		return nil, err
+	}
+	if quota < 0 || len(emails) <= quota {
+		bp.logInfo("✅ License check passed: Will process %d emails", len(emails))
+		return emails, nil
+	}
+	if quota == 0 {
+		return nil, ErrQuotaExhausted
+	}
 
-	// Tính tổng số emails sẽ được process
-	totalWillProcess := int(currentProcessed) + emailsToProcess
+	batch, withheld := emails[:quota], emails[quota:]
+	bp.logWarning("⚠️ Quota còn %d emails: xử lý %d, giữ lại %d emails (vẫn pending)", quota, len(batch), len(withheld))
 
-	// Lấy account count
-	accountCount := len(bp.autoCrawler.GetAccounts())
+	preview := withheld
+	if len(preview) > 5 {
+		preview = preview[:5]
+	}
+	more := ""
+	if len(withheld) > len(preview) {
+		more = ", ..."
+	}
+	bp.logWarning("   Giữ lại: %s%s", strings.Join(preview, ", "), more)
 
-	// Check license limits
-	err := bp.licenseWrapper.CheckCrawlingLimits(totalWillProcess, accountCount)
-	if err != nil {
-		bp.logError("License limit check failed: %v", err)
-		return err
+	reportPath := filepath.Join(filepath.Dir(bp.autoCrawler.GetConfig().OutputFilePath),
+		fmt.Sprintf("quota-withheld-%s.txt", time.Now().Format("20060102-150405")))
+	if err := utils.WriteFileAtomic(reportPath, []byte(strings.Join(withheld, "\n")+"\n")); err != nil {
+		bp.logWarning("Không thể ghi danh sách emails bị giữ lại: %v", err)
+	} else {
+		bp.logWarning("   Danh sách đầy đủ: %s", reportPath)
 	}
 
-	bp.logInfo("✅ License check passed: Will process %d emails (total: %d)", emailsToProcess, totalWillProcess)
-	return nil
+	return batch, nil
 }
 
 // checkLicenseLimitsDuringProcessing kiểm tra license trong quá trình process
@@ -135,30 +246,25 @@ func (bp *BatchProcessor) checkLicenseLimitsDuringProcessing() error {
 		return fmt.Errorf("license not initialized")
 	}
 
-	// Lấy số emails đã process thành công
-	currentSuccess := atomic.LoadInt32(&bp.successEmailsCount)
-
-	// Lấy license info để check limits
-	info := bp.licenseWrapper.GetLicenseInfo()
-	maxEmails, ok := info["max_emails"].(int)
-	if !ok {
-		// Không thể lấy limit info, cho phép tiếp tục
-		return nil
+	// Cùng billing policy với LicensedCrawlerWrapper: chỉ email status=success
+	// (ghi vào usage ledger) mới trừ quota, lookup thất bại thì không
+	remaining, err := bp.licenseWrapper.RemainingQuota()
+	if err != nil {
+		return err
 	}
 
 	// Nếu unlimited (-1), không cần check
-	if maxEmails <= 0 {
+	if remaining < 0 {
 		return nil
 	}
 
-	// Check nếu đã vượt quá limit
-	if int(currentSuccess) >= maxEmails {
-		return fmt.Errorf("email processing limit reached: %d/%d successful emails processed", currentSuccess, maxEmails)
+	if remaining == 0 {
+		return fmt.Errorf("%w: no billable emails left under license", ErrQuotaExhausted)
 	}
 
 	// Cảnh báo khi gần đến limit
-	if int(currentSuccess) >= maxEmails-10 {
-		bp.logWarning("Approaching email limit: %d/%d emails processed", currentSuccess, maxEmails)
+	if remaining <= 10 {
+		bp.logWarning("Approaching email limit: còn %d billable emails", remaining)
 	}
 
 	return nil
@@ -167,15 +273,68 @@ func (bp *BatchProcessor) checkLicenseLimitsDuringProcessing() error {
 // ProcessAllEmails processes all emails with GUI logging and license checking
 func (bp *BatchProcessor) ProcessAllEmails() error {
 	bp.logInfo("🔄 Phase 1: Xử lý tất cả emails với token rotation và license checking...")
+	defer func() {
+		if bp.licenseWrapper != nil {
+			if err := bp.licenseWrapper.FlushUsage(); err != nil {
+				bp.logWarning("Không thể lưu usage ledger: %v", err)
+			}
+		}
+		if err := bp.requestBudget.Save(); err != nil {
+			bp.logWarning("Không thể lưu request budget: %v", err)
+		}
+	}()
+
+	// Feature gates: concurrency cao và extended profile cần advanced crawling
+	if bp.licenseWrapper != nil {
+		gated, err := bp.licenseWrapper.ApplyFeatureGates(bp.autoCrawler.GetConfig())
+		if err != nil {
+			bp.logError("❌ %v", err)
+			return err
+		}
+		bp.maxWorkers = int(gated.MaxConcurrency)
+		bp.extendedProfile = gated.ExtendedProfile
+	}
 
 	stateManager := bp.autoCrawler.stateManager
 
+	// Emails đã có response trong cache được xử lý trước khi lấy tokens
+	bp.serveCachedResponses()
+
+	// Ước lượng accounts cần dùng từ lịch sử trước khi bắt đầu (giả lập không cần accounts)
+	if !bp.autoCrawler.GetConfig().Simulate && bp.tokenSources.Uses(models.TokenSourceAccounts) {
+		if err := bp.checkAccountPlan(0, true); err != nil {
+			return err
+		}
+	}
+
 	// Main loop - continue until no emails left or no accounts left
 	for stateManager.HasEmailsToProcess() {
 		if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
 			bp.logWarning("⚠️ Nhận tín hiệu dừng, thoát khỏi vòng lặp chính")
 			break
 		}
+		if bp.emailLimitLeft() == 0 {
+			bp.logInfo("🎯 Đã xử lý đủ %d emails của lần chạy", bp.emailLimit)
+			break
+		}
+
+		// Đang tạm dừng hoặc ngoài khung giờ crawl thì chờ trước khi tốn account lấy tokens
+		if err := bp.waitWhilePaused(context.Background()); err != nil {
+			bp.logWarning("⚠️ Dừng khi đang tạm dừng: %v", err)
+			break
+		}
+		if err := bp.waitForCrawlWindow(context.Background()); err != nil {
+			bp.logWarning("⚠️ Dừng khi đang chờ khung giờ crawl: %v", err)
+			break
+		}
+
+		// Hết quota thì dừng trước khi tốn công lấy tokens
+		if bp.licenseWrapper != nil {
+			if quota, err := bp.licenseWrapper.RemainingQuota(); err == nil && quota == 0 {
+				bp.logWarning("🚫 Hết quota license, %d emails còn pending - nâng cấp hoặc thêm add-on để tiếp tục", stateManager.CountRemainingEmails())
+				break
+			}
+		}
 
 		// Display current status
 		remaining := stateManager.CountRemainingEmails()
@@ -184,62 +343,17 @@ func (bp *BatchProcessor) ProcessAllEmails() error {
 		bp.logInfo("📧 Còn lại: %d emails chưa xử lý", remaining)
 		bp.logInfo("📂 Account index hiện tại: %d/%d", bp.autoCrawler.GetUsedAccountIndex(), len(bp.autoCrawler.GetAccounts()))
 
-		// STEP 1: Check if there are available tokens
+		// STEP 1-2: Hỏi các nguồn token theo thứ tự ưu tiên
 		var validTokens []string
-		config := bp.autoCrawler.GetConfig()
-		_, tokenStorage, _ := bp.autoCrawler.GetStorageServices()
-
-		if bp.hasValidTokens() {
-			bp.logInfo("🔍 Phát hiện có tokens khả dụng, đang load và validate...")
-			existingTokens, err := tokenStorage.LoadTokensFromFile(config.TokensFilePath)
-			if err == nil && len(existingTokens) > 0 {
-				bp.logInfo("📂 Tìm thấy %d tokens trong file, đang kiểm tra chi tiết...", len(existingTokens))
-				validTokens, err = bp.validateExistingTokens(existingTokens)
-				if err != nil {
-					bp.logError("⚠️ Lỗi khi kiểm tra tokens: %v", err)
-					validTokens = []string{}
-				}
-			}
+		if bp.autoCrawler.GetConfig().Simulate {
+			validTokens = bp.simulatedTokens()
 		} else {
-			bp.logInfo("🔍 Không có tokens khả dụng trong file, cần lấy tokens mới")
-		}
-
-		// STEP 2: If not enough tokens, get more from accounts
-		if len(validTokens) < config.MinTokens {
-			bp.logInfo("📊 Có %d tokens hợp lệ, cần thêm %d tokens", len(validTokens), config.MinTokens-len(validTokens))
-
-			// Check if there are accounts left
-			if bp.autoCrawler.GetUsedAccountIndex() >= len(bp.autoCrawler.GetAccounts()) {
-				bp.logError("❌ Đã hết accounts để lấy tokens!")
-				if len(validTokens) > 0 {
-					bp.logWarning("🔋 Sử dụng %d tokens còn lại...", len(validTokens))
-				} else {
-					bp.logError("💀 Không còn tokens nào, dừng chương trình")
-					break
-				}
-			} else {
-				bp.logInfo("🔄 Lấy thêm tokens từ accounts (còn %d accounts)", len(bp.autoCrawler.GetAccounts())-bp.autoCrawler.GetUsedAccountIndex())
-
-				newTokens, err := bp.getTokensBatch()
-				if err != nil {
-					bp.logError("❌ Lỗi lấy tokens: %v", err)
-					if len(validTokens) == 0 {
-						break
-					}
-				} else {
-					// Merge old and new tokens
-					allTokens := append(validTokens, newTokens...)
-					validTokens = allTokens
-
-					// Save all tokens to file
-					if err := tokenStorage.SaveTokensToFile(config.TokensFilePath, validTokens); err != nil {
-						bp.logError("⚠️ Lỗi lưu tokens: %v", err)
-					}
-					bp.logSuccess("✅ Tổng cộng có %d tokens để sử dụng", len(validTokens))
-				}
+			tokens, err := bp.tokenSources.Acquire(context.Background())
+			if err != nil {
+				bp.logError("💀 Không còn tokens nào (%s), dừng chương trình", strings.Join(bp.tokenSources.Names(), ", "))
+				break
 			}
-		} else {
-			bp.logSuccess("✅ Đủ tokens (%d) để tiếp tục crawling", len(validTokens))
+			validTokens = tokens
 		}
 
 		// STEP 3: Crawl with current tokens
@@ -247,6 +361,10 @@ func (bp *BatchProcessor) ProcessAllEmails() error {
 			bp.logInfo("▶️ BẮT ĐẦU CRAWLING với %d tokens...", len(validTokens))
 
 			if err := bp.processEmailsWithTokens(validTokens); err != nil {
+				if errors.Is(err, ErrQuotaExhausted) {
+					bp.logWarning("🚫 Hết quota license, %d emails còn pending - nâng cấp hoặc thêm add-on để tiếp tục", stateManager.CountRemainingEmails())
+					break
+				}
 				bp.logError("⚠️ Lỗi khi xử lý emails: %v", err)
 			}
 
@@ -268,6 +386,7 @@ func (bp *BatchProcessor) ProcessAllEmails() error {
 		}
 	}
 
+	bp.logRunLimits()
 	return nil
 }
 
@@ -283,6 +402,9 @@ func (bp *BatchProcessor) hasValidTokens() bool {
 // validateExistingTokens validates existing tokens from file
 func (bp *BatchProcessor) validateExistingTokens(tokens []string) ([]string, error) {
 	config := bp.autoCrawler.GetConfig()
+	if config.Simulate {
+		return tokens, nil
+	}
 	outputFile := bp.autoCrawler.GetOutputFile()
 	totalEmails := bp.autoCrawler.GetTotalEmails()
 
@@ -298,13 +420,11 @@ func (bp *BatchProcessor) validateTokensBatch(tokens []string) ([]string, error)
 	return bp.validatorService.ValidateTokensBatch(tokens, config, outputFile, totalEmails)
 }
 
-// getTokensBatch gets a batch of tokens from accounts with GUI progress
-func (bp *BatchProcessor) getTokensBatch() ([]string, error) {
+// getTokensBatch gets up to tokensNeeded tokens from accounts with GUI progress
+func (bp *BatchProcessor) getTokensBatch(tokensNeeded int) ([]string, error) {
 	var validTokens []string
-	config := bp.autoCrawler.GetConfig()
 	accounts := bp.autoCrawler.GetAccounts()
 	usedIndex := bp.autoCrawler.GetUsedAccountIndex()
-	tokensNeeded := config.MaxTokens
 
 	bp.logInfo("🎯 Mục tiêu: Lấy %d tokens mới", tokensNeeded)
 
@@ -324,8 +444,8 @@ func (bp *BatchProcessor) getTokensBatch() ([]string, error) {
 	accountsBatch := accounts[usedIndex:endIndex]
 	bp.logInfo("🔄 Sử dụng %d accounts (từ index %d đến %d) để lấy %d tokens", len(accountsBatch), usedIndex, endIndex-1, tokensNeeded)
 
-	// Process in small batches to avoid overload
-	batchSize := 5
+	// Mỗi batch đăng nhập song song tối đa LoginParallelism accounts
+	batchSize := bp.tokenExtractor.Parallelism()
 	processedAccounts := 0
 
 	for i := 0; i < len(accountsBatch) && len(validTokens) < tokensNeeded; i += batchSize {
@@ -339,12 +459,23 @@ func (bp *BatchProcessor) getTokensBatch() ([]string, error) {
 			end = len(accountsBatch)
 		}
 
+		// Không đăng nhập quá số account còn lại của lần chạy
+		if left := bp.accountLimitLeft(); left == 0 {
+			bp.logInfo("🎯 Đã dùng đủ %d accounts của lần chạy", bp.accountLimit)
+			break
+		} else if left > 0 && end-i > left {
+			end = i + left
+		}
+
 		batch := accountsBatch[i:end]
 		bp.logInfo("📦 Xử lý batch %d-%d (cần thêm %d tokens)...", i+1, end, tokensNeeded-len(validTokens))
 
-		// Get tokens from this batch
-		rawTokens := bp.processAccountsBatch(batch)
-		processedAccounts += len(batch)
+		// Get tokens from this batch, trừ các account đã bị đánh dấu banned
+		var rawTokens []string
+		if selected := bp.skipBannedAccounts(batch); len(selected) > 0 {
+			rawTokens = bp.processAccountsBatch(selected)
+			processedAccounts += len(selected)
+		}
 
 		// Validate tokens immediately
 		if len(rawTokens) > 0 {
@@ -386,16 +517,33 @@ func (bp *BatchProcessor) getTokensBatch() ([]string, error) {
 func (bp *BatchProcessor) processAccountsBatch(accounts []models.Account) []string {
 	config := bp.autoCrawler.GetConfig()
 	results := bp.tokenExtractor.ExtractTokensBatch(accounts, config.AccountsFilePath)
+	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
 
 	var validTokens []string
 	for _, result := range results {
-		if result.Error == nil && result.Token != "" {
+		// Account đã đủ giới hạn ngày: không đăng nhập, không tính vào lịch sử
+		if result.Reason == models.AccountStatusDailyCap {
+			bp.logWarning("⏭️ Bỏ qua account %s: %v", result.Account.Email, result.Error)
+			continue
+		}
+
+		success := result.Error == nil && result.Token != ""
+		if err := emailStorage.RecordLoginAttempt(bp.autoCrawler.GetRunID(), result.Account.Email, success, result.Reason, utils.TokenFingerprint(result.Token)); err != nil {
+			bp.logWarning("⚠️ Không thể ghi lịch sử đăng nhập: %v", err)
+		}
+
+		bp.countLogin(success)
+
+		if success {
 			validTokens = append(validTokens, result.Token)
 			bp.logSuccess("✅ Thành công lấy token từ account: %s", result.Account.Email)
 		} else {
 			bp.logError("❌ Lỗi account %s: %v", result.Account.Email, result.Error)
 		}
 	}
+	for _, line := range auth.FailureSummary(results) {
+		bp.logWarning("📋 %s", line)
+	}
 	return validTokens
 }
 
@@ -410,12 +558,18 @@ func (bp *BatchProcessor) processEmailsWithTokens(tokens []string) error {
 		return nil
 	}
 
-	// STEP 2: License check trước khi process
-	if err := bp.checkLicenseLimitsBeforeProcessing(len(remainingEmails)); err != nil {
+	// STEP 2: Cắt batch cho vừa quota license còn lại
+	remainingEmails, err := bp.fitBatchToQuota(remainingEmails)
+	if err != nil {
 		bp.logError("❌ License limit exceeded before processing: %v", err)
 		return err
 	}
 
+	// Lần chạy giới hạn số emails (calibration) chỉ lấy phần còn thiếu
+	if left := bp.emailLimitLeft(); left >= 0 && len(remainingEmails) > left {
+		remainingEmails = remainingEmails[:left]
+	}
+
 	// STEP 3: Initialize crawler
 	if err := bp.initializeCrawler(tokens); err != nil {
 		return fmt.Errorf("failed to initialize crawler: %w", err)
@@ -464,36 +618,35 @@ func (bp *BatchProcessor) crawlWithCurrentTokensAndLicenseCheck(emails []string)
 		return 0, nil
 	}
 
-	totalOriginalEmails := len(bp.autoCrawler.GetTotalEmails())
+	totalOriginalEmails := bp.autoCrawler.TotalEmailCount()
 	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
 
-	// Get initial stats
-	stats, err := emailStorage.GetEmailStats()
-	if err != nil {
-		bp.logError("⚠️ Không thể lấy stats từ database: %v", err)
-		stats = make(map[string]int)
-	}
-
-	alreadyProcessed := stats["success"]
-	atomic.StoreInt32(&bp.processedEmailsCount, int32(alreadyProcessed))
-	atomic.StoreInt32(&bp.successEmailsCount, int32(stats["has_info"]+stats["no_info"]))
+	// Counters của batch bắt đầu lại, tổng trong DB được đọc lại (retry đã reset failed → pending)
+	bp.autoCrawler.stats.StartBatch()
 
 	bp.logInfo("🎯 Bắt đầu crawl %d emails với license checking...", len(emails))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Reset crawler stats
-	crawlerInstance := bp.autoCrawler.GetCrawler()
-	if crawlerInstance != nil {
-		atomic.StoreInt32(&crawlerInstance.Stats.Processed, 0)
-		atomic.StoreInt32(&crawlerInstance.Stats.Success, 0)
-		atomic.StoreInt32(&crawlerInstance.Stats.Failed, 0)
+	if crawlerInstance := bp.autoCrawler.GetCrawler(); crawlerInstance != nil {
 		crawlerInstance.AllTokensFailed = false
 	}
 
+	// Emails còn chờ từ batch trước vẫn pending trong DB nên đã có trong emails
+	bp.rateLimits.drop()
+
+	// Dòng thêm vào bảng emails sau thời điểm này được producer nhận trong lúc chạy
+	lastEmailID, idErr := emailStorage.MaxEmailID()
+	if idErr != nil {
+		bp.logWarning("⚠️ Không thể theo dõi emails mới thêm: %v", idErr)
+	}
+
 	emailCh := make(chan string, 100)
 	done := make(chan struct{})
+	var inFlight int32 // Emails đã gửi vào emailCh nhưng chưa xử lý xong
+	inFlightEmails := newInFlightSet()
+	var duplicates int32 // Emails trùng bị bỏ qua vì đang/đã được xử lý
 
 	// License check ticker - Kiểm tra license định kỳ
 	licenseCheckTicker := time.NewTicker(30 * time.Second) // Check every 30 seconds
@@ -522,7 +675,12 @@ func (bp *BatchProcessor) crawlWithCurrentTokensAndLicenseCheck(emails []string)
 			case <-ctx.Done():
 				return
 			case <-statusTicker.C:
-				bp.updateProgressWithLicenseInfo(ctx, emailStorage, totalOriginalEmails, len(emails))
+				bp.updateProgressWithLicenseInfo(ctx, totalOriginalEmails, len(emails))
+				// Đánh thức các worker đang chờ pacing khi người dùng dừng
+				if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
+					cancel()
+					return
+				}
 			}
 		}
 	}()
@@ -530,11 +688,50 @@ func (bp *BatchProcessor) crawlWithCurrentTokensAndLicenseCheck(emails []string)
 	// Producer goroutine
 	go func() {
 		defer close(emailCh)
+		send := func(email string) bool {
+			atomic.AddInt32(&inFlight, 1)
+			select {
+			case <-ctx.Done():
+				return false
+			case emailCh <- email:
+				return true
+			}
+		}
+
+		queued := make(map[string]bool, len(emails))
 		for _, email := range emails {
+			queued[email] = true
+			if !send(email) {
+				return
+			}
+		}
+
+		// Phát lại emails bị 429 khi hết backoff và gửi emails mới thêm vào bảng emails,
+		// đến khi không còn email nào đang xử lý hoặc chờ
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		lastPoll := time.Now()
+		for atomic.LoadInt32(&inFlight) > 0 || bp.rateLimits.Len() > 0 {
+			for _, email := range bp.rateLimits.due(time.Now()) {
+				if !send(email) {
+					return
+				}
+			}
+			if idErr == nil && time.Since(lastPoll) >= hotAddPollInterval {
+				lastPoll = time.Now()
+				var added []string
+				added, lastEmailID = bp.pollAddedEmails(lastEmailID, queued)
+				for _, email := range added {
+					queued[email] = true
+					if !send(email) {
+						return
+					}
+				}
+			}
 			select {
 			case <-ctx.Done():
 				return
-			case emailCh <- email:
+			case <-ticker.C:
 			}
 		}
 	}()
@@ -545,9 +742,13 @@ func (bp *BatchProcessor) crawlWithCurrentTokensAndLicenseCheck(emails []string)
 		var wg sync.WaitGroup
 		config := bp.autoCrawler.GetConfig()
 		maxConcurrency := int(config.MaxConcurrency)
+		if bp.maxWorkers > 0 && maxConcurrency > bp.maxWorkers {
+			maxConcurrency = bp.maxWorkers
+		}
 
 		for i := 0; i < maxConcurrency; i++ {
 			wg.Add(1)
+			worker := i
 			go func() {
 				defer wg.Done()
 				for email := range emailCh {
@@ -557,6 +758,11 @@ func (bp *BatchProcessor) crawlWithCurrentTokensAndLicenseCheck(emails []string)
 					default:
 					}
 
+					// Worker vượt giới hạn bộ nhớ thì chờ (email đã nhận vẫn được xử lý sau khi chờ)
+					if err := bp.waitForWorkerSlot(ctx, worker); err != nil {
+						return
+					}
+
 					if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
 						return
 					}
@@ -568,23 +774,41 @@ func (bp *BatchProcessor) crawlWithCurrentTokensAndLicenseCheck(emails []string)
 						return
 					}
 
+					// Email trùng đang được worker khác xử lý, đang chờ backoff 429 hoặc đã xong thì bỏ qua
+					if bp.rateLimits.isWaiting(email) || !inFlightEmails.acquire(email) {
+						atomic.AddInt32(&duplicates, 1)
+						if utils.ConsoleAtLeast(utils.ConsoleDebug) {
+							fmt.Printf("🔁 Bỏ qua email trùng đang/đã xử lý: %s\n", email)
+						}
+						atomic.AddInt32(&inFlight, -1)
+						continue
+					}
+
 					// Process email
 					crawlerInstance := bp.autoCrawler.GetCrawler()
 					if crawlerInstance != nil {
 						if crawlerInstance.AllTokensFailed {
 							bp.logError("❌ Tokens hết hiệu lực, dừng worker")
+							inFlightEmails.release(email, false)
 							cancel()
 							return
 						}
 
-						atomic.AddInt32(&crawlerInstance.Stats.Processed, 1)
-						atomic.AddInt32(&bp.processedEmailsCount, 1)
+						bp.autoCrawler.stats.BeginEmail()
 
-						success := bp.retryEmailWithLicenseCheck(email, 5)
-						if success {
-							atomic.AddInt32(&bp.successEmailsCount, 1)
+						success := bp.retryEmailWithLicenseCheck(ctx, email, 5)
+						// Email bị 429 đưa vào hàng chờ chưa xong: chỉ ghi usage khi lần phát lại kết thúc
+						if bp.licenseWrapper != nil && !bp.rateLimits.isWaiting(email) {
+							successCount := 0
+							if success {
+								successCount = 1
+							}
+							bp.licenseWrapper.RecordUsage(1, successCount)
 						}
 					}
+					// Email chờ backoff 429 được phát lại nên chưa tính là xong
+					inFlightEmails.release(email, !bp.rateLimits.isWaiting(email))
+					atomic.AddInt32(&inFlight, -1)
 				}
 			}()
 		}
@@ -597,60 +821,46 @@ func (bp *BatchProcessor) crawlWithCurrentTokensAndLicenseCheck(emails []string)
 		licenseCheckTicker.Stop()
 		statusTicker.Stop()
 
-		processed := int32(0)
-		success := int32(0)
-		failed := int32(0)
-		crawlerInstance := bp.autoCrawler.GetCrawler()
-		if crawlerInstance != nil {
-			processed = atomic.LoadInt32(&crawlerInstance.Stats.Processed)
-			success = atomic.LoadInt32(&crawlerInstance.Stats.Success)
-			failed = atomic.LoadInt32(&crawlerInstance.Stats.Failed)
+		stats := bp.autoCrawler.stats.Snapshot()
+		bp.logSuccess("✅ Hoàn thành batch: Processed: %d | Success: %d | Failed: %d | Rate limited (re-queued): %d",
+			stats.BatchProcessed, stats.BatchSuccess, stats.BatchFailed, stats.BatchRequeued)
+		if n := atomic.LoadInt32(&duplicates); n > 0 {
+			bp.logInfo("🔁 Đã bỏ qua %d emails trùng trong batch", n)
 		}
 
-		bp.logSuccess("✅ Hoàn thành batch: Processed: %d | Success: %d | Failed: %d", processed, success, failed)
-
 		// Final license check
 		finalErr := bp.checkLicenseLimitsDuringProcessing()
 		if finalErr != nil {
 			bp.logWarning("⚠️ License limit reached at end of batch: %v", finalErr)
 		}
 
-		return int(processed), nil
+		return stats.BatchProcessed, nil
 
 	case <-ctx.Done():
 		licenseCheckTicker.Stop()
 		statusTicker.Stop()
 
-		processed := int32(0)
-		crawlerInstance := bp.autoCrawler.GetCrawler()
-		if crawlerInstance != nil {
-			processed = atomic.LoadInt32(&crawlerInstance.Stats.Processed)
-		}
+		processed := bp.autoCrawler.stats.Snapshot().BatchProcessed
 
 		if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
 			bp.logWarning("⚠️ Crawling stopped by user: Processed %d emails", processed)
 		} else {
 			bp.logInfo("🔄 Crawling stopped by license limit or tokens: Processed %d emails", processed)
 		}
-		return int(processed), ctx.Err()
+		return processed, ctx.Err()
 	}
 }
 
 // updateProgressWithLicenseInfo cập nhật progress với thông tin license
-func (bp *BatchProcessor) updateProgressWithLicenseInfo(ctx context.Context, emailStorage *storage.EmailStorage, totalOriginalEmails, currentBatchSize int) {
-	// Get current stats
-	currentStats, err := emailStorage.GetEmailStats()
-	if err != nil {
-		currentStats = make(map[string]int)
-	}
+func (bp *BatchProcessor) updateProgressWithLicenseInfo(ctx context.Context, totalOriginalEmails, currentBatchSize int) {
+	stats := bp.autoCrawler.stats.Snapshot()
 
 	// Get license info
 	licenseInfo := ""
 	if bp.licenseWrapper != nil {
 		info := bp.licenseWrapper.GetLicenseInfo()
 		if maxEmails, ok := info["max_emails"].(int); ok && maxEmails > 0 {
-			successCount := currentStats["success"]
-			licenseInfo = fmt.Sprintf(" | License: %d/%d", successCount, maxEmails)
+			licenseInfo = fmt.Sprintf(" | License: %d/%d", stats.Success, maxEmails)
 		} else {
 			licenseInfo = " | License: Unlimited"
 		}
@@ -658,19 +868,21 @@ func (bp *BatchProcessor) updateProgressWithLicenseInfo(ctx context.Context, ema
 
 	batchPercent := 0.0
 	if currentBatchSize > 0 {
-		batchProcessed := atomic.LoadInt32(&bp.processedEmailsCount)
-		batchPercent = float64(batchProcessed) * 100 / float64(currentBatchSize)
+		batchPercent = float64(stats.BatchProcessed) * 100 / float64(currentBatchSize)
 	}
 
-	totalPercent := float64(currentStats["success"]) * 100 / float64(totalOriginalEmails)
+	totalPercent := 0.0
+	if totalOriginalEmails > 0 {
+		totalPercent = float64(stats.Success) * 100 / float64(totalOriginalEmails)
+	}
 
-	bp.updateProgress(int(atomic.LoadInt32(&bp.processedEmailsCount)), currentBatchSize,
-		"🔄 Batch: %.1f%% | Total: %.1f%% | Success: %d | Failed: %d%s",
-		batchPercent, totalPercent, currentStats["success"], currentStats["failed"], licenseInfo)
+	bp.updateProgress(stats.BatchProcessed, currentBatchSize,
+		"🔄 Batch: %.1f%% | Total: %.1f%% | Success: %d | Failed: %d | 429 queue: %d%s",
+		batchPercent, totalPercent, stats.Success, stats.Failed, bp.rateLimits.Len(), licenseInfo)
 }
 
 // retryEmailWithLicenseCheck - Enhanced retry với license checking
-func (bp *BatchProcessor) retryEmailWithLicenseCheck(email string, maxRetries int) bool {
+func (bp *BatchProcessor) retryEmailWithLicenseCheck(ctx context.Context, email string, maxRetries int) bool {
 	// License check trước khi retry
 	if err := bp.checkLicenseLimitsDuringProcessing(); err != nil {
 		bp.logError("❌ License limit reached, skipping email: %s (%v)", email, err)
@@ -678,14 +890,17 @@ func (bp *BatchProcessor) retryEmailWithLicenseCheck(email string, maxRetries in
 	}
 
 	// Proceed với regular retry logic
-	return bp.retryEmailWithSQLite(email, maxRetries)
+	return bp.retryEmailWithSQLite(ctx, email, maxRetries)
 }
 
-// retryEmailWithSQLite retries email with SQLite integration - GUI LOGGING
-func (bp *BatchProcessor) retryEmailWithSQLite(email string, maxRetries int) bool {
+// retryEmailWithSQLite retries email with SQLite integration - GUI LOGGING.
+// ctx chỉ dùng cho thời gian chờ pacing, không giới hạn từng request
+func (bp *BatchProcessor) retryEmailWithSQLite(ctx context.Context, email string, maxRetries int) bool {
 	config := bp.autoCrawler.GetConfig()
 	crawlerInstance := bp.autoCrawler.GetCrawler()
-	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
+
+	// Thông tin request cuối cùng, ghi vào email_events
+	var lastEvent storage.EmailEvent
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
@@ -696,13 +911,50 @@ func (bp *BatchProcessor) retryEmailWithSQLite(email string, maxRetries int) boo
 			allTokensFailed := crawlerInstance.AllTokensFailed
 			if allTokensFailed {
 				bp.logError("❌ Tất cả tokens đã bị lỗi, dừng retry cho email: %s", email)
-				emailStorage.UpdateEmailStatus(email, storage.StatusFailed, false, false)
+				lastEvent.Error = "all tokens failed"
+				lastEvent.ErrorCode = models.EmailErrorAuth
+				bp.markEmailFailed(email, lastEvent)
+				return false
+			}
+
+			// Tạm dừng, khung giờ, budget và pacing chờ trước khi bắt đầu tính request timeout
+			if err := bp.waitWhilePaused(ctx); err != nil {
 				return false
 			}
+			if err := bp.waitForCrawlWindow(ctx); err != nil {
+				return false
+			}
+			if err := bp.requestBudget.Acquire(ctx, bp.logBudgetPause); err != nil {
+				return false
+			}
+			if crawlerInstance.Pacer != nil {
+				if err := crawlerInstance.Pacer.Wait(ctx); err != nil {
+					return false
+				}
+			}
 
+			var usedToken string
 			reqCtx, reqCancel := context.WithTimeout(context.Background(), config.RequestTimeout)
-			hasProfile, body, statusCode, _ := bp.queryService.QueryProfileWithRetryLogic(crawlerInstance, reqCtx, email)
+			reqCtx = crawler.WithTokenRecorder(reqCtx, &usedToken)
+			sentAt := time.Now()
+			hasProfile, body, statusCode, queryErr := bp.queryTarget(crawlerInstance, reqCtx, email)
 			reqCancel()
+			bp.countRequest(statusCode, sentAt)
+			bp.recordTokenResult(usedToken, statusCode)
+
+			lastEvent = storage.EmailEvent{
+				HTTPStatus: statusCode,
+				TokenHash:  utils.TokenFingerprint(usedToken),
+			}
+			if queryErr != nil {
+				lastEvent.Error = queryErr.Error()
+			}
+			if statusCode != 200 {
+				lastEvent.ErrorCode = crawler.ClassifyError(statusCode, queryErr)
+			}
+			if utils.ConsoleAtLeast(utils.ConsoleDebug) {
+				fmt.Printf("🔎 %s | lần %d/%d | HTTP %d | token %s\n", email, attempt, maxRetries, statusCode, lastEvent.TokenHash)
+			}
 
 			// Only log detailed info on final attempt or success
 			if attempt == maxRetries || statusCode == 200 {
@@ -711,43 +963,10 @@ func (bp *BatchProcessor) retryEmailWithSQLite(email string, maxRetries int) boo
 
 			// Process successful response
 			if statusCode == 200 {
-				if hasProfile {
-					// Check if there's actual profile data
-					profileExtractor := crawler.NewProfileExtractor()
-					profile, parseErr := profileExtractor.ExtractProfileData(body)
-					if parseErr == nil && profile.User != "" && profile.User != "null" && profile.User != "{}" {
-						// HAS LINKEDIN INFO
-						err := emailStorage.UpdateEmailStatus(email, storage.StatusSuccess, true, false)
-						if err != nil {
-							bp.logError("⚠️ Không thể cập nhật status trong DB cho email %s: %v", email, err)
-						}
-
-						bp.logSuccess("✅ Email có thông tin LinkedIn: %s | User: %s", email, profile.User)
-
-						// Write to hit.txt file
-						profileExtractor.WriteProfileToFile(crawlerInstance, email, profile)
-						atomic.AddInt32(&crawlerInstance.Stats.Success, 1)
-					} else {
-						// NO LINKEDIN INFO (200 response but no useful data)
-						err := emailStorage.UpdateEmailStatus(email, storage.StatusSuccess, false, true)
-						if err != nil {
-							bp.logError("⚠️ Không thể cập nhật status trong DB cho email %s: %v", email, err)
-						}
-
-						bp.logInfo("📭 Email không có thông tin LinkedIn: %s", email)
-						atomic.AddInt32(&crawlerInstance.Stats.Success, 1)
-					}
-				} else {
-					// NO LINKEDIN INFO
-					err := emailStorage.UpdateEmailStatus(email, storage.StatusSuccess, false, true)
-					if err != nil {
-						bp.logError("⚠️ Không thể cập nhật status trong DB cho email %s: %v", email, err)
-					}
-
-					bp.logInfo("📭 Email không có thông tin LinkedIn: %s", email)
-					atomic.AddInt32(&crawlerInstance.Stats.Success, 1)
+				if !bp.handleResponse(crawlerInstance, email, hasProfile, body, lastEvent) {
+					return false
 				}
-
+				bp.cacheResponse(email, hasProfile, body)
 				return true
 			}
 
@@ -762,19 +981,149 @@ func (bp *BatchProcessor) retryEmailWithSQLite(email string, maxRetries int) boo
 		}
 	}
 
+	// Hết lượt retry vì 429 thì chờ backoff rồi thử lại thay vì đánh dấu failed
+	if lastEvent.HTTPStatus == 429 && bp.requeueRateLimited(email, lastEvent) {
+		return false
+	}
+
 	// After retrying maxRetries times and still not successful
 	bp.logError("❌ Email %s thất bại sau %d lần retry - Đánh dấu failed trong DB", email, maxRetries)
 
 	// Update status to failed in SQLite
-	emailStorage.UpdateEmailStatus(email, storage.StatusFailed, false, false)
-
-	crawlerInstance = bp.autoCrawler.GetCrawler()
-	if crawlerInstance != nil {
-		atomic.AddInt32(&crawlerInstance.Stats.Failed, 1)
+	if lastEvent.Error == "" {
+		lastEvent.Error = fmt.Sprintf("failed after %d retries", maxRetries)
+	}
+	if lastEvent.ErrorCode == "" {
+		lastEvent.ErrorCode = models.EmailErrorUnknown
 	}
+	bp.markEmailFailed(email, lastEvent)
 	return false
 }
 
+// handleResponse stores the verdict of a 200 response for email: a hit is
+// written to hit.txt and the results table. It returns false when the
+// profile could not be parsed and the email was marked failed.
+func (bp *BatchProcessor) handleResponse(crawlerInstance *models.LinkedInCrawler, email string, hasProfile bool, body []byte, lastEvent storage.EmailEvent) bool {
+	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
+	stats := bp.autoCrawler.stats
+
+	if hasProfile {
+		// Check if there's actual profile data
+		profileExtractor := crawler.NewProfileExtractor()
+		profile, parseErr := profileExtractor.ExtractProfileData(body)
+		profile.Source = bp.resultSource()
+		if !bp.extendedProfile {
+			profile.Headline, profile.Company = "", ""
+		}
+		if parseErr != nil {
+			if capture := bp.queryService.GetCapture(); capture != nil {
+				capture.CaptureParseFailure(email, body, parseErr)
+			}
+			// Response giống nhau nếu thử lại nên đánh dấu failed ngay, có thể re-queue theo class parse_error
+			lastEvent.Error = parseErr.Error()
+			lastEvent.ErrorCode = models.EmailErrorParse
+			bp.markEmailFailed(email, lastEvent)
+			bp.logError("❌ Email %s: không đọc được profile (%v)", email, parseErr)
+			return false
+		}
+		if parseErr == nil && profile.User != "" && profile.User != "null" && profile.User != "{}" {
+			// HAS LINKEDIN INFO
+			if _, err := stats.Record(email, OutcomeHit, lastEvent); err != nil {
+				bp.logError("⚠️ Không thể cập nhật status trong DB cho email %s: %v", email, err)
+			}
+
+			bp.logSuccess("✅ Email có thông tin LinkedIn: %s | User: %s", email, profile.User)
+
+			// Write to hit.txt file
+			profileExtractor.WriteProfileToFile(crawlerInstance, email, profile)
+			if err := emailStorage.SaveResult(email, profile, bp.autoCrawler.config.Campaign); err != nil {
+				bp.logError("⚠️ Không thể lưu kết quả vào DB cho email %s: %v", email, err)
+			}
+			bp.autoCrawler.syncHit(email, profile)
+			bp.autoCrawler.notifyProfile(email, profile)
+			bp.recordHit(email, profile.User)
+		} else {
+			// NO LINKEDIN INFO (200 response but no useful data)
+			if _, err := stats.Record(email, OutcomeNoInfo, lastEvent); err != nil {
+				bp.logError("⚠️ Không thể cập nhật status trong DB cho email %s: %v", email, err)
+			}
+
+			bp.logInfo("📭 Email không có thông tin LinkedIn: %s", email)
+		}
+	} else {
+		// NO LINKEDIN INFO
+		if _, err := stats.Record(email, OutcomeNoInfo, lastEvent); err != nil {
+			bp.logError("⚠️ Không thể cập nhật status trong DB cho email %s: %v", email, err)
+		}
+
+		bp.logInfo("📭 Email không có thông tin LinkedIn: %s", email)
+	}
+
+	return true
+}
+
+// markEmailFailed stores an email as failed with the error class of its last
+// attempt and counts it in the run and batch stats
+func (bp *BatchProcessor) markEmailFailed(email string, lastEvent storage.EmailEvent) {
+	if _, err := bp.autoCrawler.stats.Record(email, OutcomeFailed, lastEvent); err != nil {
+		bp.logError("⚠️ Không thể cập nhật status trong DB cho email %s: %v", email, err)
+	}
+}
+
+// requeueRateLimited puts an email whose retries all got 429 back in the
+// rate-limit queue. It stays pending in the database so an interrupted run
+// still picks it up. It returns false when the email used up its re-queues.
+func (bp *BatchProcessor) requeueRateLimited(email string, lastEvent storage.EmailEvent) bool {
+	delay, ok := bp.rateLimits.add(email)
+	if !ok {
+		bp.logWarning("⚠️ Email %s vẫn bị 429 sau %d lần chờ", email, rateLimitMaxRequeues)
+		return false
+	}
+
+	if _, err := bp.autoCrawler.stats.Record(email, OutcomeRequeued, lastEvent); err != nil {
+		bp.logError("⚠️ Không thể cập nhật status trong DB cho email %s: %v", email, err)
+	}
+
+	if crawlerInstance := bp.autoCrawler.GetCrawler(); crawlerInstance != nil {
+		crawlerInstance.RateLimitMutex.Lock()
+		crawlerInstance.RateLimitedEmails = append(crawlerInstance.RateLimitedEmails, email)
+		crawlerInstance.RateLimitMutex.Unlock()
+	}
+
+	bp.logWarning("⏳ Email %s bị 429, thử lại sau %s", email, delay)
+	return true
+}
+
+// logBudgetPause reports that workers sleep until the request budget resets
+func (bp *BatchProcessor) logBudgetPause(limit string, resumeAt time.Time) {
+	message := fmt.Sprintf("⏳ Đạt giới hạn %s, tạm dừng đến %s", limit, resumeAt.Format("2006-01-02 15:04"))
+	fmt.Println(message)
+	bp.logWarning("%s", message)
+}
+
+// queryTarget queries one input row, or fakes it in simulation
+func (bp *BatchProcessor) queryTarget(lc *models.LinkedInCrawler, ctx context.Context, target string) (bool, []byte, int, error) {
+	if bp.queryService.Simulating() {
+		return bp.queryService.QuerySimulatedWithRetryLogic(lc, ctx, target)
+	}
+	return bp.queryService.QueryProfileWithRetryLogic(lc, ctx, target)
+}
+
+// resultSource returns the source column value of new results
+func (bp *BatchProcessor) resultSource() string {
+	if bp.queryService.Simulating() {
+		return models.SourceSimulated
+	}
+	return models.SourceEmail
+}
+
+// simulatedTokens returns placeholder tokens for a simulated crawl; they are
+// never written to the tokens file
+func (bp *BatchProcessor) simulatedTokens() []string {
+	config := bp.autoCrawler.GetConfig()
+	return crawler.SimulatedTokens(max(config.MinTokens, config.MaxTokens))
+}
+
 // GetLicenseStats returns current license usage statistics
 func (bp *BatchProcessor) GetLicenseStats() map[string]interface{} {
 	if bp.licenseWrapper == nil {
@@ -785,43 +1134,23 @@ func (bp *BatchProcessor) GetLicenseStats() map[string]interface{} {
 	}
 
 	info := bp.licenseWrapper.GetLicenseInfo()
-	currentProcessed := atomic.LoadInt32(&bp.processedEmailsCount)
-	currentSuccess := atomic.LoadInt32(&bp.successEmailsCount)
+	processed, success := bp.GetCurrentUsage()
 
 	stats := map[string]interface{}{
 		"license_active":    true,
-		"current_processed": int(currentProcessed),
-		"current_success":   int(currentSuccess),
+		"current_processed": processed,
+		"current_success":   success,
 		"license_info":      info,
 	}
 
 	return stats
 }
 
-// UpdateLicenseUsage updates license usage counters (called externally)
-func (bp *BatchProcessor) UpdateLicenseUsage(processed, success int) {
-	atomic.StoreInt32(&bp.processedEmailsCount, int32(processed))
-	atomic.StoreInt32(&bp.successEmailsCount, int32(success))
-
-	// Also update license wrapper
-	if bp.licenseWrapper != nil {
-		bp.licenseWrapper.UpdateUsageCounters(processed, success)
-	}
-}
-
-// ResetLicenseCounters resets license usage counters
-func (bp *BatchProcessor) ResetLicenseCounters() {
-	atomic.StoreInt32(&bp.processedEmailsCount, 0)
-	atomic.StoreInt32(&bp.successEmailsCount, 0)
-
-	if bp.licenseWrapper != nil {
-		bp.licenseWrapper.ResetUsageCounters()
-	}
-}
-
-// GetCurrentUsage returns current usage statistics
+// GetCurrentUsage returns the processed and success emails in the database,
+// from the StatsService
 func (bp *BatchProcessor) GetCurrentUsage() (processed, success int) {
-	return int(atomic.LoadInt32(&bp.processedEmailsCount)), int(atomic.LoadInt32(&bp.successEmailsCount))
+	stats := bp.autoCrawler.stats.Snapshot()
+	return stats.Processed(), stats.Success
 }
 
 // IsLicenseValid checks if license is currently valid
@@ -834,27 +1163,6 @@ func (bp *BatchProcessor) IsLicenseValid() bool {
 	return err == nil
 }
 
-// GetRemainingEmailQuota returns remaining email quota based on license
-func (bp *BatchProcessor) GetRemainingEmailQuota() int {
-	if bp.licenseWrapper == nil {
-		return 0
-	}
-
-	info := bp.licenseWrapper.GetLicenseInfo()
-	maxEmails, ok := info["max_emails"].(int)
-	if !ok || maxEmails <= 0 {
-		return -1 // Unlimited
-	}
-
-	currentProcessed := atomic.LoadInt32(&bp.processedEmailsCount)
-	remaining := maxEmails - int(currentProcessed)
-	if remaining < 0 {
-		remaining = 0
-	}
-
-	return remaining
-}
-
 // ShowLicenseStatus displays current license status (for debugging)
 func (bp *BatchProcessor) ShowLicenseStatus() {
 	if bp.licenseWrapper != nil {
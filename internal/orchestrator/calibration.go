@@ -0,0 +1,160 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"linkedin-crawler/internal/storage"
+)
+
+// DefaultCalibrationEmails is the size of a calibration burst
+const DefaultCalibrationEmails = 200
+
+// Ngưỡng để đánh giá một lần calibration
+const (
+	calibrationMinRequests = 20   // Ít hơn thì số liệu không đủ tin cậy
+	calibrationHighLimited = 0.05 // Trên 5% response 429: giảm tốc
+	calibrationLowLimited  = 0.01 // Dưới 1% và đạt gần đủ tốc độ: có thể tăng
+	calibrationStepUp      = 1.25 // Mức tăng requests/s khi còn dư địa
+	calibrationHeadroom    = 1.5  // Worker dư so với requests/s × latency
+)
+
+// Giới hạn giống form Config
+const (
+	tuneMinRPS         = 1.0
+	tuneMaxRPS         = 50.0
+	tuneMaxConcurrency = 100
+)
+
+// Calibration is what a calibration burst measured and the concurrency and
+// requests/sec it recommends
+type Calibration struct {
+	RunID      string
+	Emails     int // Emails đã xử lý (kể cả failed)
+	Requests   int
+	Limited    int // Response 429
+	AvgLatency time.Duration
+	Elapsed    time.Duration // Từ request đầu tiên đến request cuối cùng
+
+	// Giá trị đã dùng và giá trị đề xuất
+	MaxConcurrency            int64
+	RequestsPerSec            float64
+	RecommendedConcurrency    int64
+	RecommendedRequestsPerSec float64
+	Verdict                   string
+}
+
+// SetEmailLimit stops the run after n emails (0 = no limit), replacing
+// Config.MaxEmailsPerRun; calibration bursts use it to crawl only the first n
+// pending emails
+func (ac *AutoCrawler) SetEmailLimit(n int) {
+	ac.batchProcessor.emailLimit = max(n, 0)
+}
+
+// emailLimitLeft returns how many emails the run may still process, or -1
+// without a limit
+func (bp *BatchProcessor) emailLimitLeft() int {
+	if bp.emailLimit <= 0 {
+		return -1
+	}
+	completed, failed := bp.RunProgress()
+	return max(bp.emailLimit-completed-failed, 0)
+}
+
+// Calibration returns the measurements of the run and the settings they
+// recommend. Call it after Run.
+func (ac *AutoCrawler) Calibration() (Calibration, error) {
+	bp := ac.batchProcessor
+	completed, failed := bp.RunProgress()
+	c := Calibration{
+		RunID:          ac.runID,
+		Emails:         completed + failed,
+		Requests:       int(atomic.LoadInt64(&bp.run.requests)),
+		Limited:        int(atomic.LoadInt64(&bp.run.rateLimited)),
+		MaxConcurrency: ac.config.MaxConcurrency,
+		RequestsPerSec: ac.config.RequestsPerSec,
+	}
+	if answered := atomic.LoadInt64(&bp.run.answered); answered > 0 {
+		c.AvgLatency = time.Duration(atomic.LoadInt64(&bp.run.latencyNanos) / answered)
+	}
+	if first := atomic.LoadInt64(&bp.run.firstRequestAt); first > 0 {
+		c.Elapsed = time.Duration(atomic.LoadInt64(&bp.run.lastRequestAt) - first)
+	}
+
+	if c.Requests < calibrationMinRequests {
+		return c, fmt.Errorf("calibration sent only %d requests, need at least %d: add pending emails, tokens or accounts", c.Requests, calibrationMinRequests)
+	}
+	c.recommend()
+	return c, nil
+}
+
+// LimitedRate returns the share of requests answered with 429 (0-1)
+func (c Calibration) LimitedRate() float64 {
+	if c.Requests == 0 {
+		return 0
+	}
+	return float64(c.Limited) / float64(c.Requests)
+}
+
+// AchievedRequestsPerSec returns the request rate the burst reached
+func (c Calibration) AchievedRequestsPerSec() float64 {
+	if c.Elapsed <= 0 {
+		return 0
+	}
+	return float64(c.Requests) / c.Elapsed.Seconds()
+}
+
+// recommend derives requests/sec from the 429 rate and concurrency from
+// requests/sec × latency (Little's law) with headroom
+func (c *Calibration) recommend() {
+	rps := c.RequestsPerSec
+	achieved := c.AchievedRequestsPerSec()
+	limited := c.LimitedRate()
+
+	switch {
+	case limited > calibrationHighLimited:
+		// Giảm theo tỷ lệ 429, tính từ tốc độ thực tế nếu thấp hơn cấu hình
+		if achieved > 0 && achieved < rps {
+			rps = achieved
+		}
+		rps *= (1 - limited) * 0.8
+		c.Verdict = fmt.Sprintf("%.1f%% of requests hit 429: slow down", limited*100)
+	case limited < calibrationLowLimited && achieved >= rps*0.8:
+		rps *= calibrationStepUp
+		c.Verdict = fmt.Sprintf("only %.1f%% of requests hit 429: there is room to go faster", limited*100)
+	default:
+		c.Verdict = fmt.Sprintf("%.1f%% of requests hit 429: keep the rate", limited*100)
+	}
+	rps = math.Round(min(max(rps, tuneMinRPS), tuneMaxRPS)*2) / 2 // Bước 0.5 như form
+
+	workers := int64(math.Ceil(rps * c.AvgLatency.Seconds() * calibrationHeadroom))
+	c.RecommendedRequestsPerSec = rps
+	c.RecommendedConcurrency = min(max(workers, 1), tuneMaxConcurrency)
+}
+
+// Summary renders the measurements and the recommendation on a few lines
+func (c Calibration) Summary() string {
+	return fmt.Sprintf(
+		"Calibrated on %d emails: %d requests in %s (%.1f req/s), %d hit 429 (%.1f%%), avg latency %s\n"+
+			"%s\n"+
+			"Concurrency %d → %d, requests/sec %.1f → %.1f",
+		c.Emails, c.Requests, c.Elapsed.Round(time.Second), c.AchievedRequestsPerSec(), c.Limited, c.LimitedRate()*100,
+		c.AvgLatency.Round(time.Millisecond), c.Verdict,
+		c.MaxConcurrency, c.RecommendedConcurrency, c.RequestsPerSec, c.RecommendedRequestsPerSec)
+}
+
+// Profile returns the recommendation as a tuning profile called name
+func (c Calibration) Profile(name string) storage.TuningProfile {
+	return storage.TuningProfile{
+		Name:           name,
+		MaxConcurrency: c.RecommendedConcurrency,
+		RequestsPerSec: c.RecommendedRequestsPerSec,
+		Emails:         c.Emails,
+		Requests:       c.Requests,
+		RateLimited:    c.Limited,
+		AvgLatency:     c.AvgLatency,
+		RunID:          c.RunID,
+	}
+}
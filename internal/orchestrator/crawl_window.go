@@ -0,0 +1,73 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// windowPollInterval là chu kỳ kiểm tra lại khung giờ và tín hiệu dừng khi đang tạm dừng
+const windowPollInterval = 5 * time.Second
+
+// waitForCrawlWindow blocks until the crawl window is open. It returns an
+// error when ctx is done or a shutdown is requested while waiting.
+func (bp *BatchProcessor) waitForCrawlWindow(ctx context.Context) error {
+	window := bp.autoCrawler.GetCrawlWindow()
+	if window == nil {
+		return nil
+	}
+
+	for {
+		now := time.Now()
+		if window.Contains(now) {
+			bp.announceWindowOpen()
+			return nil
+		}
+		if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
+			return fmt.Errorf("shutdown requested")
+		}
+
+		resumeAt := window.NextOpen(now)
+		bp.announceWindowPause(resumeAt)
+
+		wait := time.Until(resumeAt)
+		if wait > windowPollInterval {
+			wait = windowPollInterval
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// announceWindowPause logs once per pause that crawling waits for the window
+func (bp *BatchProcessor) announceWindowPause(resumeAt time.Time) {
+	bp.windowMutex.Lock()
+	defer bp.windowMutex.Unlock()
+	if bp.windowPausedUntil.Equal(resumeAt) {
+		return
+	}
+	bp.windowPausedUntil = resumeAt
+
+	message := fmt.Sprintf("🌙 Ngoài khung giờ crawl (%s), tạm dừng đến %s",
+		bp.autoCrawler.GetCrawlWindow(), resumeAt.Format("2006-01-02 15:04"))
+	fmt.Println(message)
+	bp.logWarning("%s", message)
+}
+
+// announceWindowOpen logs when crawling resumes after a window pause
+func (bp *BatchProcessor) announceWindowOpen() {
+	bp.windowMutex.Lock()
+	defer bp.windowMutex.Unlock()
+	if bp.windowPausedUntil.IsZero() {
+		return
+	}
+	bp.windowPausedUntil = time.Time{}
+
+	message := fmt.Sprintf("▶️ Vào khung giờ crawl (%s), tiếp tục", bp.autoCrawler.GetCrawlWindow())
+	fmt.Println(message)
+	bp.logInfo("%s", message)
+}
@@ -0,0 +1,177 @@
+package orchestrator
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"linkedin-crawler/internal/models"
+)
+
+// emailsWatchInterval is how often the emails file is checked for edits
+const emailsWatchInterval = 3 * time.Second
+
+// emailsFileWatch follows the emails file while a crawl runs. Lines appended
+// to the end are imported as pending; any other edit is flagged so pending
+// emails are exported next to the file instead of overwriting the edit.
+type emailsFileWatch struct {
+	path     string
+	offset   int64    // Số byte đầu file đã import
+	prefix   [32]byte // sha256 của offset byte đầu
+	size     int64    // Kích thước và thời gian sửa ở lần kiểm tra trước
+	modTime  time.Time
+	conflict int32       // 1 = file bị sửa khác với thêm dòng cuối
+	lockedAt os.FileMode // Quyền gốc khi file đang bị khoá (0 = không khoá)
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startEmailsWatcher locks the emails file or starts following appended
+// lines, depending on EmailsFileEdits
+func (ac *AutoCrawler) startEmailsWatcher() {
+	// Email source là database: file emails được ghi lại từ query mỗi lần chạy
+	if ac.config.EmailsDBDSN != "" {
+		return
+	}
+	data, err := os.ReadFile(ac.config.EmailsFilePath)
+	if err != nil {
+		return
+	}
+	info, err := os.Stat(ac.config.EmailsFilePath)
+	if err != nil {
+		return
+	}
+
+	w := &emailsFileWatch{
+		path:    ac.config.EmailsFilePath,
+		offset:  int64(len(data)),
+		prefix:  sha256.Sum256(data),
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	ac.emailsWatch = w
+
+	if ac.config.EmailsFileEdits == models.EmailsFileEditsLock {
+		close(w.done)
+		mode := info.Mode().Perm()
+		if err := os.Chmod(w.path, mode&^0222); err != nil {
+			fmt.Printf("⚠️ Không thể khoá file %s: %v\n", w.path, err)
+			return
+		}
+		w.lockedAt = mode
+		fmt.Printf("🔒 %s chỉ đọc cho đến khi crawl xong\n", w.path)
+		return
+	}
+
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(emailsWatchInterval)
+		defer ticker.Stop()
+
+		pending := false // File đã đổi, chờ một lần kiểm tra không đổi nữa rồi mới đọc
+		for {
+			select {
+			case <-ticker.C:
+				pending = ac.checkEmailsFile(w, pending)
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopEmailsWatcher stops following the emails file and unlocks it; safe to
+// call more than once
+func (ac *AutoCrawler) stopEmailsWatcher() {
+	w := ac.emailsWatch
+	if w == nil {
+		return
+	}
+	w.stopOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+		if w.lockedAt != 0 {
+			if err := os.Chmod(w.path, w.lockedAt); err != nil {
+				fmt.Printf("⚠️ Không thể mở khoá file %s: %v\n", w.path, err)
+			}
+		}
+	})
+}
+
+// checkEmailsFile applies one step of the watcher. A changed file is read
+// once it stopped changing between two checks, so a save in progress is not
+// read half written. It returns whether a change is waiting to be read.
+func (ac *AutoCrawler) checkEmailsFile(w *emailsFileWatch, pending bool) bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return pending
+	}
+	if info.Size() != w.size || !info.ModTime().Equal(w.modTime) {
+		w.size, w.modTime = info.Size(), info.ModTime()
+		return true
+	}
+	if !pending {
+		return false
+	}
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		fmt.Printf("⚠️ Không thể đọc file %s: %v\n", w.path, err)
+		return false
+	}
+	appended := int64(len(data)) >= w.offset && sha256.Sum256(data[:w.offset]) == w.prefix
+	if !appended {
+		if atomic.SwapInt32(&w.conflict, 1) == 0 {
+			ac.logEmailsFile(true, "⚠️ %s bị sửa trong lúc crawl (không chỉ thêm dòng cuối): thay đổi không được import, emails pending sẽ lưu vào %s", w.path, ac.PendingExportPath())
+		}
+	} else if w.offset < int64(len(data)) {
+		lines := strings.Split(string(data[w.offset:]), "\n")
+		added, invalid, err := ac.AddEmails(lines)
+		if err != nil {
+			ac.logEmailsFile(true, "⚠️ Không thể import dòng mới từ %s: %v", w.path, err)
+			return true // Thử lại ở lần kiểm tra sau
+		}
+		if added > 0 {
+			ac.logEmailsFile(false, "📥 Đã thêm %d dòng mới từ %s vào hàng chờ", added, w.path)
+		}
+		if invalid > 0 {
+			ac.logEmailsFile(true, "⚠️ Bỏ qua %d dòng không hợp lệ thêm vào %s", invalid, w.path)
+		}
+	}
+
+	// Sau khi sửa, các dòng thêm vào cuối vẫn được import tiếp
+	w.offset = int64(len(data))
+	w.prefix = sha256.Sum256(data)
+	return false
+}
+
+// logEmailsFile reports a watcher event on the console and in the GUI log
+func (ac *AutoCrawler) logEmailsFile(warning bool, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Println(message)
+	if warning {
+		ac.batchProcessor.logWarning("%s", message)
+	} else {
+		ac.batchProcessor.logInfo("%s", message)
+	}
+}
+
+// PendingExportPath returns where pending emails are exported when the run
+// ends: the emails file, or <name>.pending<ext> next to it when the file was
+// edited in a way that could not be imported, so the edit is not overwritten
+func (ac *AutoCrawler) PendingExportPath() string {
+	if w := ac.emailsWatch; w != nil && atomic.LoadInt32(&w.conflict) == 1 {
+		ext := filepath.Ext(w.path)
+		return strings.TrimSuffix(w.path, ext) + ".pending" + ext
+	}
+	return ac.config.EmailsFilePath
+}
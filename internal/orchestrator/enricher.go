@@ -0,0 +1,306 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"linkedin-crawler/internal/crawler"
+	"linkedin-crawler/internal/licensing"
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
+)
+
+// FieldChange is one changed field of a re-crawled profile
+type FieldChange struct {
+	Key   string
+	Field string
+	Old   string
+	New   string
+}
+
+// EnrichReport summarizes an enrich/update run
+type EnrichReport struct {
+	Total      int
+	Requeried  int
+	Changed    int
+	Unchanged  int
+	Failed     int
+	Changes    []FieldChange
+	ReportPath string
+}
+
+// Enricher re-queries existing hits and records profile changes
+type Enricher struct {
+	config         models.Config
+	queryService   *crawler.QueryService
+	tokenStorage   *storage.TokenStorage
+	emailStorage   *storage.EmailStorage
+	extractor      *crawler.ProfileExtractor
+	licenseWrapper *licensing.LicensedCrawlerWrapper
+}
+
+// NewEnricher creates a new Enricher instance
+func NewEnricher(config models.Config) *Enricher {
+	return &Enricher{
+		config:         config,
+		queryService:   crawler.NewQueryService(),
+		tokenStorage:   storage.NewTokenStorage(),
+		emailStorage:   storage.NewEmailStorage(),
+		extractor:      crawler.NewProfileExtractor(),
+		licenseWrapper: licensing.NewLicensedCrawlerWrapper(),
+	}
+}
+
+// SetLicenseWrapper sets the license wrapper used for checking and metering
+func (e *Enricher) SetLicenseWrapper(wrapper *licensing.LicensedCrawlerWrapper) {
+	e.licenseWrapper = wrapper
+}
+
+// Close closes the database of the stored results
+func (e *Enricher) Close() error {
+	return e.emailStorage.CloseDB()
+}
+
+// Run re-crawls every entry of hitFile, rewrites it with fresh data, updates
+// the matching stored results and writes a CSV diff report
+func (e *Enricher) Run(hitFile string) (*EnrichReport, error) {
+	entries, err := utils.ReadHitResults(hitFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hit file: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no entries in %s", hitFile)
+	}
+
+	report, err := e.enrich(entries, hitFile)
+	if err != nil {
+		return report, err
+	}
+	if report.Changed > 0 {
+		if err := utils.WriteHitResults(hitFile, entries); err != nil {
+			return report, fmt.Errorf("failed to update hit file: %w", err)
+		}
+	}
+	return report, e.finish(report, entries)
+}
+
+// RunResults re-crawls the results stored in the database, updates the
+// changed ones and writes a CSV diff report
+func (e *Enricher) RunResults() (*EnrichReport, error) {
+	entries, err := e.emailStorage.GetResults()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no results in the database")
+	}
+
+	report, err := e.enrich(entries, "database results")
+	if err != nil {
+		return report, err
+	}
+	return report, e.finish(report, entries)
+}
+
+// enrich re-crawls entries in place once the license quota covers all of
+// them; each lookup is recorded as license usage
+func (e *Enricher) enrich(entries []utils.HitResult, source string) (*EnrichReport, error) {
+	if e.licenseWrapper == nil {
+		return nil, fmt.Errorf("license not initialized")
+	}
+	// Mỗi profile tìm lại được tính như một email crawl thành công
+	if err := e.licenseWrapper.CheckCrawlingLimits(len(entries), 0); err != nil {
+		return nil, err
+	}
+	config, err := e.licenseWrapper.ApplyFeatureGates(e.config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := e.licenseWrapper.FlushUsage(); err != nil {
+			fmt.Printf("⚠️ Không thể lưu usage ledger: %v\n", err)
+		}
+	}()
+
+	tokens, err := e.tokenStorage.LoadTokensFromFile(config.TokensFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tokens: %w", err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no tokens available in %s", config.TokensFilePath)
+	}
+
+	// Kết quả được ghi lại bằng WriteHitResults nên crawler không cần output file thật
+	lc, err := crawler.New(config, os.DevNull)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create crawler: %w", err)
+	}
+	defer crawler.Close(lc)
+	lc.Tokens = tokens
+
+	fmt.Printf("🔄 Enrich: re-crawl %d profiles từ %s với %d tokens\n", len(entries), source, len(tokens))
+
+	report := &EnrichReport{Total: len(entries)}
+	var reportMutex sync.Mutex
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	workers := int(config.MaxConcurrency)
+	if workers < 1 {
+		workers = 1
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				updated, changes, found, err := e.enrichEntry(lc, entries[i])
+
+				// Giống BatchProcessor: chỉ lookup trả về profile mới tính billable
+				success := 0
+				if found {
+					success = 1
+				}
+				e.licenseWrapper.RecordUsage(1, success)
+
+				reportMutex.Lock()
+				report.Requeried++
+				if err != nil {
+					report.Failed++
+					fmt.Printf("⚠️ Enrich thất bại cho %s: %v\n", entries[i].Email, err)
+				} else if len(changes) > 0 {
+					report.Changed++
+					report.Changes = append(report.Changes, changes...)
+					entries[i] = updated
+				} else {
+					report.Unchanged++
+				}
+				reportMutex.Unlock()
+			}
+		}()
+	}
+
+	for i := range entries {
+		if lc.AreAllTokensFailed() {
+			fmt.Println("❌ Tất cả tokens đã bị lỗi, dừng enrich")
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return report, nil
+}
+
+// finish writes the changed entries back to the results table and the diff
+// report
+func (e *Enricher) finish(report *EnrichReport, entries []utils.HitResult) error {
+	if report.Changed > 0 {
+		changed := make(map[string]bool, report.Changed)
+		for _, c := range report.Changes {
+			changed[c.Key] = true
+		}
+		var updates []utils.HitResult
+		for _, entry := range entries {
+			if changed[entry.Email] {
+				updates = append(updates, entry)
+			}
+		}
+		n, err := e.emailStorage.UpdateResultProfiles(updates)
+		if err != nil {
+			return fmt.Errorf("failed to update results: %w", err)
+		}
+		fmt.Printf("🗄️ Đã cập nhật %d results trong database\n", n)
+	}
+
+	report.ReportPath = fmt.Sprintf("enrich-report-%s.csv", time.Now().Format("20060102-150405"))
+	if err := writeEnrichReport(report.ReportPath, report.Changes); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Enrich xong: %d profiles | Thay đổi: %d | Không đổi: %d | Lỗi: %d\n",
+		report.Total, report.Changed, report.Unchanged, report.Failed)
+	fmt.Printf("📄 Diff report: %s\n", report.ReportPath)
+	return nil
+}
+
+// enrichEntry re-queries one entry and returns the updated entry with its
+// changes; found reports whether the profile was returned
+func (e *Enricher) enrichEntry(lc *models.LinkedInCrawler, entry utils.HitResult) (utils.HitResult, []FieldChange, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.RequestTimeout)
+	defer cancel()
+
+	hasProfile, body, statusCode, err := e.query(lc, ctx, entry)
+	if err != nil {
+		return entry, nil, false, err
+	}
+	if statusCode != 200 {
+		return entry, nil, false, fmt.Errorf("status %d", statusCode)
+	}
+	if !hasProfile {
+		// Profile không còn trả về dữ liệu, giữ nguyên bản cũ
+		return entry, nil, false, nil
+	}
+
+	profile, err := e.extractor.ExtractProfileData(body)
+	if err != nil {
+		return entry, nil, true, fmt.Errorf("parse failed: %w", err)
+	}
+
+	updated := entry
+	var changes []FieldChange
+
+	compare := func(field, oldVal, newVal string, set func(string)) {
+		newVal = strings.TrimSpace(newVal)
+		if newVal == "" || newVal == oldVal {
+			return
+		}
+		changes = append(changes, FieldChange{Key: entry.Email, Field: field, Old: oldVal, New: newVal})
+		set(newVal)
+	}
+
+	compare("name", entry.Name, profile.User, func(v string) { updated.Name = v })
+	compare("linkedin_url", entry.LinkedInURL, profile.LinkedInURL, func(v string) { updated.LinkedInURL = v })
+	compare("location", entry.Location, profile.Location, func(v string) { updated.Location = v })
+	compare("connections", entry.Connections, profile.ConnectionCount, func(v string) { updated.Connections = v })
+
+	return updated, changes, true, nil
+}
+
+// query looks the entry up again; simulated results are skipped
+func (e *Enricher) query(lc *models.LinkedInCrawler, ctx context.Context, entry utils.HitResult) (bool, []byte, int, error) {
+	if entry.Source == models.SourceSimulated {
+		return false, nil, 0, fmt.Errorf("simulated result, nothing to refresh")
+	}
+	return e.queryService.QueryProfileWithRetryLogic(lc, ctx, entry.Email)
+}
+
+// writeEnrichReport writes field changes as CSV
+func writeEnrichReport(path string, changes []FieldChange) error {
+	file, err := utils.CreateAtomic(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Abort()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"key", "field", "old_value", "new_value"})
+	for _, c := range changes {
+		w.Write([]string{c.Key, c.Field, c.Old, c.New})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return file.Commit()
+}
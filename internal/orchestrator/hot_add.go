@@ -0,0 +1,49 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// hotAddPollInterval is how often the running batch looks for rows added to
+// the emails table while it runs
+const hotAddPollInterval = 5 * time.Second
+
+// AddEmails adds input rows to the running crawl. New rows go into the emails table as
+// pending and the current batch picks them up without a restart. It returns
+// how many rows were new and how many were invalid.
+func (ac *AutoCrawler) AddEmails(lines []string) (added, invalid int, err error) {
+	added, invalid, err = ac.emailStorage.ImportRows(lines)
+	if err != nil {
+		return 0, invalid, fmt.Errorf("failed to add emails: %w", err)
+	}
+	atomic.AddInt64(&ac.addedEmails, int64(added))
+	if added > 0 {
+		ac.stats.Refresh()
+	}
+	return added, invalid, nil
+}
+
+// pollAddedEmails returns the pending rows inserted after row lastID that
+// were not queued in this batch yet, and the new lastID
+func (bp *BatchProcessor) pollAddedEmails(lastID int, queued map[string]bool) ([]string, int) {
+	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
+	emails, newLastID, err := emailStorage.GetPendingEmailsAfter(lastID)
+	if err != nil {
+		bp.logWarning("⚠️ Không thể kiểm tra emails mới thêm: %v", err)
+		return nil, lastID
+	}
+
+	var added []string
+	for _, email := range emails {
+		if !queued[email] {
+			added = append(added, email)
+		}
+	}
+	if len(added) > 0 {
+		bp.logInfo("📥 Nhận %d emails mới thêm vào hàng chờ", len(added))
+		bp.autoCrawler.stats.Refresh() // Có thể do process khác thêm vào
+	}
+	return added, newLastID
+}
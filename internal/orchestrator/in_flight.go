@@ -0,0 +1,52 @@
+package orchestrator
+
+import (
+	"strings"
+	"sync"
+)
+
+// inFlightSet tracks the emails of one batch that are being queried or are
+// done, so a duplicate of an email (re-import race, retry overlap) is never
+// queried by two workers at once or again after it finished. Emails waiting
+// in the rate-limit queue are not done and can be acquired again.
+type inFlightSet struct {
+	mutex    sync.Mutex
+	active   map[string]bool // Email đang được một worker xử lý
+	finished map[string]bool // Email đã có kết quả cuối trong batch này
+}
+
+// newInFlightSet creates an empty set for a batch
+func newInFlightSet() *inFlightSet {
+	return &inFlightSet{
+		active:   make(map[string]bool),
+		finished: make(map[string]bool),
+	}
+}
+
+// acquire marks email as being processed; it returns false when another
+// worker is processing it or it already finished in this batch
+func (s *inFlightSet) acquire(email string) bool {
+	key := strings.ToLower(strings.TrimSpace(email))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.active[key] || s.finished[key] {
+		return false
+	}
+	s.active[key] = true
+	return true
+}
+
+// release ends the processing of email; finished emails are not acquired again
+func (s *inFlightSet) release(email string, finished bool) {
+	key := strings.ToLower(strings.TrimSpace(email))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.active, key)
+	if finished {
+		s.finished[key] = true
+	}
+}
@@ -0,0 +1,146 @@
+package orchestrator
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"linkedin-crawler/internal/storage"
+	"linkedin-crawler/internal/utils"
+)
+
+// maxRecentHits is how many hits RecentHits keeps
+const maxRecentHits = 10
+
+// TokenHealth is what the current run has seen from one token
+type TokenHealth struct {
+	Fingerprint string // utils.TokenFingerprint, không lộ token gốc
+	Valid       bool
+	Requests    int
+	Errors      int  // Response khác 200
+	Expired     bool // Đã trả 401/424 và bị xoá khỏi file
+	LastStatus  int
+	LastUsed    time.Time
+}
+
+// RecentHit is a profile found during the current run
+type RecentHit struct {
+	Email string
+	Name  string
+	Time  time.Time
+}
+
+// recordTokenResult counts one response for the token that sent it
+func (bp *BatchProcessor) recordTokenResult(token string, statusCode int) {
+	if token == "" {
+		return
+	}
+	fingerprint := utils.TokenFingerprint(token)
+
+	bp.liveMutex.Lock()
+	if bp.tokenHealth == nil {
+		bp.tokenHealth = make(map[string]*TokenHealth)
+	}
+	health, ok := bp.tokenHealth[fingerprint]
+	if !ok {
+		health = &TokenHealth{Fingerprint: fingerprint}
+		bp.tokenHealth[fingerprint] = health
+	}
+	health.Requests++
+	if statusCode != 200 {
+		health.Errors++
+	}
+	invalidated := !health.Expired && (statusCode == 401 || statusCode == 424)
+	if invalidated {
+		health.Expired = true
+	}
+	health.LastStatus = statusCode
+	health.LastUsed = time.Now()
+	requests := health.Requests
+	bp.liveMutex.Unlock()
+
+	if invalidated {
+		bp.reportTokenInvalidation(fingerprint, statusCode, requests)
+	}
+}
+
+// tokenUsage returns the requests sent with each token in this run
+func (bp *BatchProcessor) tokenUsage() []storage.TokenUsage {
+	bp.liveMutex.Lock()
+	defer bp.liveMutex.Unlock()
+
+	usage := make([]storage.TokenUsage, 0, len(bp.tokenHealth))
+	for _, health := range bp.tokenHealth {
+		usage = append(usage, storage.TokenUsage{
+			TokenHash: health.Fingerprint,
+			Requests:  health.Requests,
+			Expired:   health.Expired,
+		})
+	}
+	return usage
+}
+
+// recordHit remembers a hit for RecentHits
+func (bp *BatchProcessor) recordHit(email, name string) {
+	bp.liveMutex.Lock()
+	defer bp.liveMutex.Unlock()
+
+	bp.recentHits = append(bp.recentHits, RecentHit{Email: email, Name: name, Time: time.Now()})
+	if len(bp.recentHits) > maxRecentHits {
+		bp.recentHits = bp.recentHits[len(bp.recentHits)-maxRecentHits:]
+	}
+}
+
+// TokenHealth returns the tokens of the running crawler with their request
+// counts in this run, valid tokens first
+func (ac *AutoCrawler) TokenHealth() []TokenHealth {
+	crawlerInstance := ac.GetCrawler()
+	if crawlerInstance == nil {
+		return nil
+	}
+
+	crawlerInstance.TokenMutex.Lock()
+	var tokens []TokenHealth
+	for _, token := range crawlerInstance.Tokens {
+		tokens = append(tokens, TokenHealth{
+			Fingerprint: utils.TokenFingerprint(token),
+			Valid:       !crawlerInstance.InvalidTokens[token],
+		})
+	}
+	crawlerInstance.TokenMutex.Unlock()
+
+	bp := ac.batchProcessor
+	bp.liveMutex.Lock()
+	for i := range tokens {
+		if health, ok := bp.tokenHealth[tokens[i].Fingerprint]; ok {
+			valid := tokens[i].Valid
+			tokens[i] = *health
+			tokens[i].Valid = valid
+		}
+	}
+	bp.liveMutex.Unlock()
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].Valid && !tokens[j].Valid
+	})
+	return tokens
+}
+
+// RecentHits returns the latest hits of this run, newest first
+func (ac *AutoCrawler) RecentHits() []RecentHit {
+	bp := ac.batchProcessor
+	bp.liveMutex.Lock()
+	defer bp.liveMutex.Unlock()
+
+	hits := make([]RecentHit, len(bp.recentHits))
+	for i, hit := range bp.recentHits {
+		hits[len(hits)-1-i] = hit
+	}
+	return hits
+}
+
+// Stop asks the crawler to finish the requests in flight and stop
+func (ac *AutoCrawler) Stop() {
+	atomic.StoreInt32(&ac.shutdownRequested, 1)
+	ac.Resume() // Worker đang tạm dừng cần thức dậy để thấy tín hiệu dừng
+}
@@ -0,0 +1,163 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// memoryCheckInterval is how often the memory guard reads the heap size
+const memoryCheckInterval = 5 * time.Second
+
+// Ngưỡng so với MemoryLimitMB
+const (
+	memoryWarnRatio     = 0.75 // Cảnh báo và dọn cache
+	memoryCriticalRatio = 0.90 // Giảm một nửa số worker
+	memoryRecoverRatio  = 0.60 // Tăng lại số worker
+)
+
+// startMemoryGuard watches the heap while the crawl runs. Near MemoryLimitMB
+// it asks the OnMemoryPressure handlers to trim their caches, and above the
+// critical level it halves the number of active workers until memory drops.
+func (ac *AutoCrawler) startMemoryGuard() {
+	if ac.config.MemoryLimitMB <= 0 {
+		return
+	}
+
+	limit := int64(ac.config.MemoryLimitMB) * 1024 * 1024
+	// Soft limit cho Go runtime: GC chạy thường hơn khi gần giới hạn
+	ac.previousMemoryLimit = debug.SetMemoryLimit(limit)
+
+	ac.memoryStop = make(chan struct{})
+	ac.memoryDone = make(chan struct{})
+	go func() {
+		defer close(ac.memoryDone)
+		ticker := time.NewTicker(memoryCheckInterval)
+		defer ticker.Stop()
+
+		warned := false
+		for {
+			select {
+			case <-ticker.C:
+				warned = ac.checkMemory(limit, warned)
+			case <-ac.memoryStop:
+				return
+			}
+		}
+	}()
+
+	fmt.Printf("🧠 Giới hạn bộ nhớ: %d MB\n", ac.config.MemoryLimitMB)
+}
+
+// stopMemoryGuard stops the memory guard and lifts its limits
+func (ac *AutoCrawler) stopMemoryGuard() {
+	if ac.memoryStop == nil {
+		return
+	}
+	close(ac.memoryStop)
+	<-ac.memoryDone
+	debug.SetMemoryLimit(ac.previousMemoryLimit)
+	atomic.StoreInt32(&ac.batchProcessor.workerLimit, 0)
+}
+
+// checkMemory applies one step of the guard; warned tells whether the
+// warning for the current high-memory period was already sent
+func (ac *AutoCrawler) checkMemory(limit int64, warned bool) bool {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	used := int64(m.HeapAlloc)
+	ratio := float64(used) / float64(limit)
+	bp := ac.batchProcessor
+
+	switch {
+	case ratio >= memoryCriticalRatio:
+		ac.trimMemory(used, limit)
+		workers := ac.activeWorkers()
+		if next := max(workers/2, 1); next < workers {
+			atomic.StoreInt32(&bp.workerLimit, int32(next))
+			bp.logWarning("🧠 Bộ nhớ %d/%d MB, giảm số worker xuống %d", used>>20, limit>>20, next)
+			fmt.Printf("⚠️ Bộ nhớ %d/%d MB, giảm số worker xuống %d\n", used>>20, limit>>20, next)
+		}
+		return true
+
+	case ratio >= memoryWarnRatio:
+		if !warned {
+			ac.trimMemory(used, limit)
+			bp.logWarning("🧠 Bộ nhớ %d/%d MB, đã dọn cache", used>>20, limit>>20)
+			fmt.Printf("⚠️ Bộ nhớ %d/%d MB, đã dọn cache\n", used>>20, limit>>20)
+		}
+		return true
+
+	case ratio < memoryRecoverRatio:
+		if workerLimit := atomic.LoadInt32(&bp.workerLimit); workerLimit > 0 {
+			next := workerLimit * 2
+			if int(next) >= ac.maxWorkers() {
+				next = 0 // Bỏ giới hạn
+			}
+			atomic.StoreInt32(&bp.workerLimit, next)
+			fmt.Printf("🧠 Bộ nhớ %d/%d MB, tăng lại số worker\n", used>>20, limit>>20)
+		}
+		return false
+	}
+	return warned
+}
+
+// trimMemory runs the OnMemoryPressure handlers and returns freed memory to the OS
+func (ac *AutoCrawler) trimMemory(used, limit int64) {
+	ac.memoryMutex.Lock()
+	handlers := append([]func(usedMB, limitMB int){}, ac.memoryHandlers...)
+	ac.memoryMutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(int(used>>20), int(limit>>20))
+	}
+	debug.FreeOSMemory()
+}
+
+// OnMemoryPressure registers fn to be called when memory nears MemoryLimitMB,
+// e.g. to drop caches
+func (ac *AutoCrawler) OnMemoryPressure(fn func(usedMB, limitMB int)) {
+	ac.memoryMutex.Lock()
+	defer ac.memoryMutex.Unlock()
+	ac.memoryHandlers = append(ac.memoryHandlers, fn)
+}
+
+// activeWorkers returns how many workers may currently process emails
+func (ac *AutoCrawler) activeWorkers() int {
+	if limit := int(atomic.LoadInt32(&ac.batchProcessor.workerLimit)); limit > 0 {
+		return limit
+	}
+	return ac.maxWorkers()
+}
+
+// maxWorkers returns the worker count of a batch without memory limits
+func (ac *AutoCrawler) maxWorkers() int {
+	workers := int(ac.config.MaxConcurrency)
+	if limit := ac.batchProcessor.maxWorkers; limit > 0 && workers > limit {
+		workers = limit
+	}
+	return workers
+}
+
+// waitForWorkerSlot blocks worker while the memory guard allows fewer
+// workers than its index. It returns an error when ctx is done or a shutdown
+// is requested while waiting.
+func (bp *BatchProcessor) waitForWorkerSlot(ctx context.Context, worker int) error {
+	for {
+		limit := int(atomic.LoadInt32(&bp.workerLimit))
+		if limit == 0 || worker < limit {
+			return nil
+		}
+		if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
+			return fmt.Errorf("shutdown requested")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pausePollInterval):
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-crawler/internal/integrations"
+	"linkedin-crawler/internal/utils"
+)
+
+// milestoneCheckInterval is how often the milestone rules are checked
+const milestoneCheckInterval = time.Second
+
+// startMilestones checks the milestone rules (NotifyEveryHits,
+// MilestoneEveryProcessed, MilestoneInterval) while Run is active
+func (ac *AutoCrawler) startMilestones() {
+	ac.milestones = integrations.NewMilestones(ac.config)
+	if ac.milestones == nil {
+		return
+	}
+
+	ac.milestoneStop = make(chan struct{})
+	ac.milestoneDone = make(chan struct{})
+	go func() {
+		defer close(ac.milestoneDone)
+		ticker := time.NewTicker(milestoneCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ac.checkMilestone()
+			case <-ac.milestoneStop:
+				return
+			}
+		}
+	}()
+}
+
+// stopMilestones stops the milestone loop
+func (ac *AutoCrawler) stopMilestones() {
+	if ac.milestoneStop == nil {
+		return
+	}
+	close(ac.milestoneStop)
+	<-ac.milestoneDone
+	ac.milestoneStop = nil
+}
+
+// checkMilestone logs the milestone reached since the last check to the GUI
+// or the console and sends it as a crawl.milestone event
+func (ac *AutoCrawler) checkMilestone() {
+	stats := ac.stats.Snapshot()
+	reached := ac.milestones.Reached(stats.RunProcessed(), stats.RunHits, time.Now())
+	if reached == "" {
+		return
+	}
+
+	message := milestoneMessage(reached, ac.Progress())
+	bp := ac.batchProcessor
+	if bp.guiLogger != nil {
+		bp.guiLogger.LogInfo(message)
+	} else {
+		// Milestone in ở mức console bình thường, không cần --verbose
+		fmt.Println(message)
+	}
+
+	if ac.notifications != nil {
+		event := ac.newEvent(integrations.EventMilestone)
+		event.Milestone = reached
+		ac.notifications.Notify(event)
+	}
+}
+
+// milestoneMessage formats one milestone line for the CLI and the GUI
+func milestoneMessage(reached string, progress ProgressEvent) string {
+	message := fmt.Sprintf("🏁 Milestone (%s): %d", reached, progress.Processed)
+	if progress.Total > 0 {
+		message += fmt.Sprintf("/%d emails (%.1f%%)", progress.Total, float64(progress.Processed)*100/float64(progress.Total))
+	} else {
+		message += " emails"
+	}
+
+	hitRate := 0.0
+	if progress.Processed > 0 {
+		hitRate = float64(progress.Hits) * 100 / float64(progress.Processed)
+	}
+	message += fmt.Sprintf(" | %d hits (%.1f%%) | %d failed | %.2f emails/s", progress.Hits, hitRate, progress.Failed, progress.Rate)
+	if progress.ETASeconds > 0 {
+		message += " | ETA " + utils.FormatDuration(time.Duration(progress.ETASeconds)*time.Second)
+	}
+	return message
+}
@@ -0,0 +1,97 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"linkedin-crawler/internal/integrations"
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// startNotifications sets up the Slack/Discord/outbound webhook notifiers
+// that are configured
+func (ac *AutoCrawler) startNotifications() {
+	ac.notifications = integrations.NewNotifications(ac.config)
+	if ac.notifications == nil {
+		return
+	}
+	fmt.Printf("🔔 Thông báo qua: %s\n", strings.Join(ac.notifications.Notifiers(), ", "))
+}
+
+// newEvent returns an event of eventType with the current run progress
+func (ac *AutoCrawler) newEvent(eventType integrations.EventType) integrations.Event {
+	bp := ac.batchProcessor
+	stats := ac.stats.Snapshot()
+	return integrations.Event{
+		Type:      eventType,
+		Time:      time.Now(),
+		RunID:     ac.runID,
+		Campaign:  ac.config.Campaign,
+		CrawlMode: ac.config.CrawlMode,
+		Total:     ac.TotalEmailCount(),
+		Processed: stats.RunProcessed(),
+		Hits:      stats.RunHits,
+		Failed:    stats.RunFailed,
+		Duration:  time.Since(bp.run.startedAt),
+		Stopped:   atomic.LoadInt32(&ac.shutdownRequested) == 1,
+	}
+}
+
+// notify sends an event with the current run progress
+func (ac *AutoCrawler) notify(eventType integrations.EventType, err error) {
+	if ac.notifications == nil {
+		return
+	}
+	event := ac.newEvent(eventType)
+	if err != nil {
+		event.Error = err.Error()
+	}
+	ac.notifications.Notify(event)
+}
+
+// notifyProfile sends a profile.found event for a hit to the outbound webhook
+func (ac *AutoCrawler) notifyProfile(email string, profile models.ProfileData) {
+	if ac.notifications == nil || !ac.config.WebhookHitEvents {
+		return
+	}
+
+	event := ac.newEvent(integrations.EventProfileFound)
+	event.Profile = &utils.HitResult{
+		Email:       strings.TrimSpace(email),
+		Name:        profile.User,
+		LinkedInURL: utils.NormalizeLinkedInURL(profile.LinkedInURL),
+		Location:    profile.Location,
+		Connections: profile.ConnectionCount,
+		Source:      profile.Source,
+		Timestamp:   event.Time,
+		Fields:      ac.inputFields(email),
+	}
+	ac.notifications.Notify(event)
+}
+
+// inputFields returns the custom input fields of email for webhooks and the
+// sheet; a lookup error only leaves them out
+func (ac *AutoCrawler) inputFields(email string) map[string]string {
+	fields, err := ac.emailStorage.GetInputFields(email)
+	if err != nil {
+		fmt.Printf("⚠️ Không thể đọc custom fields của %s: %v\n", email, err)
+	}
+	return fields
+}
+
+// notifyFinished sends the completion or error event of the run and waits
+// until every notification is delivered
+func (ac *AutoCrawler) notifyFinished(err error) {
+	if ac.notifications == nil {
+		return
+	}
+	if err != nil {
+		ac.notify(integrations.EventError, err)
+	} else {
+		ac.notify(integrations.EventCompleted, nil)
+	}
+	ac.notifications.Wait()
+}
@@ -0,0 +1,47 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// pausePollInterval là chu kỳ kiểm tra lại trạng thái tạm dừng
+const pausePollInterval = time.Second
+
+// Pause asks workers to hold before their next request; requests already in
+// flight finish normally
+func (ac *AutoCrawler) Pause() {
+	if atomic.CompareAndSwapInt32(&ac.pauseRequested, 0, 1) {
+		fmt.Println("⏸️ Đã tạm dừng crawler")
+	}
+}
+
+// Resume lets paused workers continue
+func (ac *AutoCrawler) Resume() {
+	if atomic.CompareAndSwapInt32(&ac.pauseRequested, 1, 0) {
+		fmt.Println("▶️ Tiếp tục crawler")
+	}
+}
+
+// IsPaused reports whether a pause is in effect
+func (ac *AutoCrawler) IsPaused() bool {
+	return atomic.LoadInt32(&ac.pauseRequested) == 1
+}
+
+// waitWhilePaused blocks while the crawler is paused. It returns an error when
+// ctx is done or a shutdown is requested while waiting.
+func (bp *BatchProcessor) waitWhilePaused(ctx context.Context) error {
+	for bp.autoCrawler.IsPaused() {
+		if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
+			return fmt.Errorf("shutdown requested")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pausePollInterval):
+		}
+	}
+	return nil
+}
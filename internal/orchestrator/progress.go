@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// progressInterval is how often a progress event is written
+const progressInterval = 2 * time.Second
+
+// ProgressEvent is one line of the NDJSON progress stream
+type ProgressEvent struct {
+	Type        string    `json:"type"` // "progress" hoặc "done" ở dòng cuối
+	Time        time.Time `json:"time"`
+	RunID       string    `json:"run_id"`
+	Total       int       `json:"total"`
+	Processed   int       `json:"processed"`
+	Success     int       `json:"success"` // Hit + không có thông tin
+	Failed      int       `json:"failed"`
+	Hits        int       `json:"hits"`
+	RateLimited int       `json:"rate_limited"` // Emails bị 429 đang chờ thử lại, không tính vào failed
+	Rate        float64   `json:"rate"`         // Emails/giây từ đầu lần chạy
+	ETASeconds  int64     `json:"eta_seconds"`  // -1 khi chưa tính được
+}
+
+// SetProgressWriter writes a ProgressEvent as one JSON line to w every
+// progressInterval while Run is active
+func (ac *AutoCrawler) SetProgressWriter(w io.Writer) {
+	ac.progressOut = w
+}
+
+// startProgress starts the progress event loop when a writer is set
+func (ac *AutoCrawler) startProgress() {
+	if ac.progressOut == nil {
+		return
+	}
+
+	ac.progressStop = make(chan struct{})
+	ac.progressDone = make(chan struct{})
+	go func() {
+		defer close(ac.progressDone)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ac.writeProgress("progress")
+			case <-ac.progressStop:
+				return
+			}
+		}
+	}()
+}
+
+// stopProgress stops the progress loop and writes the final "done" event
+func (ac *AutoCrawler) stopProgress() {
+	if ac.progressStop == nil {
+		return
+	}
+	close(ac.progressStop)
+	<-ac.progressDone
+	ac.writeProgress("done")
+}
+
+// writeProgress encodes the current run progress as one line
+func (ac *AutoCrawler) writeProgress(eventType string) {
+	event := ac.Progress()
+	event.Type = eventType
+
+	// Encoder ghi mỗi event kèm "\n", đúng định dạng NDJSON
+	json.NewEncoder(ac.progressOut).Encode(event)
+}
+
+// Progress returns the progress of the current run
+func (ac *AutoCrawler) Progress() ProgressEvent {
+	bp := ac.batchProcessor
+	stats := ac.stats.Snapshot()
+	waiting, _ := bp.RateLimitProgress()
+	event := ProgressEvent{
+		Type:        "progress",
+		Time:        time.Now(),
+		RunID:       ac.runID,
+		Total:       ac.TotalEmailCount(),
+		Processed:   stats.RunProcessed(),
+		Success:     stats.RunCompleted(),
+		Failed:      stats.RunFailed,
+		Hits:        stats.RunHits,
+		RateLimited: waiting,
+		ETASeconds:  -1,
+	}
+
+	if elapsed := time.Since(bp.run.startedAt).Seconds(); elapsed > 0 {
+		event.Rate = float64(event.Processed) / elapsed
+	}
+	if remaining := event.Total - event.Processed; remaining <= 0 {
+		event.ETASeconds = 0
+	} else if event.Rate > 0 {
+		event.ETASeconds = int64(float64(remaining) / event.Rate)
+	}
+	return event
+}
@@ -0,0 +1,93 @@
+package orchestrator
+
+import (
+	"sync"
+	"time"
+)
+
+// Backoff cho emails bị 429: 30s, 1m, 2m, 4m, 8m rồi đánh dấu failed
+const (
+	rateLimitBaseDelay   = 30 * time.Second
+	rateLimitMaxDelay    = 10 * time.Minute
+	rateLimitMaxRequeues = 5
+)
+
+// rateLimitQueue holds emails whose retries all ended in 429 until their
+// backoff expires, so they are replayed later in the batch instead of being
+// marked failed. The backoff doubles each time the same email is re-queued.
+type rateLimitQueue struct {
+	mutex    sync.Mutex
+	waiting  map[string]time.Time // Email -> thời điểm được thử lại
+	requeues map[string]int       // Số lần đã re-queue trong lần chạy này
+}
+
+// newRateLimitQueue creates an empty queue
+func newRateLimitQueue() *rateLimitQueue {
+	return &rateLimitQueue{
+		waiting:  make(map[string]time.Time),
+		requeues: make(map[string]int),
+	}
+}
+
+// add queues email for a later retry and returns its backoff. It returns
+// false once the email used up its re-queues and should be marked failed.
+func (q *rateLimitQueue) add(email string) (time.Duration, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	n := q.requeues[email]
+	if n >= rateLimitMaxRequeues {
+		return 0, false
+	}
+	q.requeues[email] = n + 1
+
+	delay := min(rateLimitBaseDelay<<n, rateLimitMaxDelay)
+	q.waiting[email] = time.Now().Add(delay)
+	return delay, true
+}
+
+// due removes and returns the emails whose backoff has expired
+func (q *rateLimitQueue) due(now time.Time) []string {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var emails []string
+	for email, retryAt := range q.waiting {
+		if !now.Before(retryAt) {
+			emails = append(emails, email)
+			delete(q.waiting, email)
+		}
+	}
+	return emails
+}
+
+// isWaiting reports whether email is waiting for its backoff
+func (q *rateLimitQueue) isWaiting(email string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	_, ok := q.waiting[email]
+	return ok
+}
+
+// Len returns how many emails are waiting for their backoff
+func (q *rateLimitQueue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.waiting)
+}
+
+// drop forgets the waiting emails; they stay pending in the database and are
+// picked up by the next batch. Re-queue counts are kept for the run.
+func (q *rateLimitQueue) drop() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.waiting = make(map[string]time.Time)
+}
+
+// reset clears the queue and the re-queue counts for a new run
+func (q *rateLimitQueue) reset() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.waiting = make(map[string]time.Time)
+	q.requeues = make(map[string]int)
+}
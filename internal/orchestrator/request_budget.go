@@ -0,0 +1,166 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"linkedin-crawler/internal/utils"
+)
+
+// budgetSaveEvery là số request giữa hai lần ghi trạng thái budget ra file
+const budgetSaveEvery = 50
+
+// budgetWindows is the persisted request count of the current hour and day
+type budgetWindows struct {
+	HourStart time.Time `json:"hour_start"`
+	HourCount int       `json:"hour_count"`
+	DayStart  time.Time `json:"day_start"`
+	DayCount  int       `json:"day_count"`
+}
+
+// RequestBudget caps requests per clock hour and per calendar day (local
+// time). Counts are kept in a sidecar file so restarts don't reset them.
+type RequestBudget struct {
+	perHour int // 0 = không giới hạn
+	perDay  int // 0 = không giới hạn
+	path    string
+
+	mutex     sync.Mutex
+	windows   budgetWindows
+	unsaved   int
+	announced time.Time // resumeAt của lần tạm dừng đã báo, tránh log trùng từ nhiều worker
+}
+
+// RequestBudgetPath returns the budget state file kept next to the database
+func RequestBudgetPath(dbPath string) string {
+	dir, base := filepath.Split(dbPath)
+	return filepath.Join(dir, "."+base+".budget.json")
+}
+
+// NewRequestBudget creates a budget and loads the counts saved at path
+func NewRequestBudget(perHour, perDay int, path string) *RequestBudget {
+	rb := &RequestBudget{perHour: perHour, perDay: perDay, path: path}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &rb.windows); err != nil {
+			fmt.Printf("⚠️ File budget %s không hợp lệ, bắt đầu lại từ 0: %v\n", path, err)
+			rb.windows = budgetWindows{}
+		}
+	}
+	return rb
+}
+
+// Enabled reports whether any cap is set
+func (rb *RequestBudget) Enabled() bool {
+	return rb != nil && (rb.perHour > 0 || rb.perDay > 0)
+}
+
+// Usage returns the requests counted in the current hour and day
+func (rb *RequestBudget) Usage() (hour, day int) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	rb.roll(time.Now())
+	return rb.windows.HourCount, rb.windows.DayCount
+}
+
+// roll resets the counters whose window has passed
+func (rb *RequestBudget) roll(now time.Time) {
+	hourStart := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+	if !rb.windows.HourStart.Equal(hourStart) {
+		rb.windows.HourStart = hourStart
+		rb.windows.HourCount = 0
+	}
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if !rb.windows.DayStart.Equal(dayStart) {
+		rb.windows.DayStart = dayStart
+		rb.windows.DayCount = 0
+	}
+}
+
+// reserve counts one request, or returns when the exhausted window resets.
+// announce is true only for the first caller that hits a given pause
+func (rb *RequestBudget) reserve(now time.Time) (resumeAt time.Time, limit string, announce, ok bool) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	rb.roll(now)
+	switch {
+	case rb.perDay > 0 && rb.windows.DayCount >= rb.perDay:
+		resumeAt, limit = rb.windows.DayStart.AddDate(0, 0, 1), fmt.Sprintf("%d requests/ngày", rb.perDay)
+	case rb.perHour > 0 && rb.windows.HourCount >= rb.perHour:
+		resumeAt, limit = rb.windows.HourStart.Add(time.Hour), fmt.Sprintf("%d requests/giờ", rb.perHour)
+	}
+	if !resumeAt.IsZero() {
+		announce = !rb.announced.Equal(resumeAt)
+		if announce {
+			rb.announced = resumeAt
+			// Lưu ngay để lần chạy sau vẫn thấy cửa sổ đã hết
+			if err := rb.saveLocked(); err != nil {
+				fmt.Printf("⚠️ Không thể lưu request budget: %v\n", err)
+			}
+		}
+		return resumeAt, limit, announce, false
+	}
+
+	rb.windows.HourCount++
+	rb.windows.DayCount++
+	rb.unsaved++
+	if rb.unsaved >= budgetSaveEvery {
+		if err := rb.saveLocked(); err != nil {
+			fmt.Printf("⚠️ Không thể lưu request budget: %v\n", err)
+		}
+	}
+	return time.Time{}, "", false, true
+}
+
+// Acquire counts one request against the caps. When a cap is reached it
+// sleeps until that window resets; onWait is called once per pause.
+func (rb *RequestBudget) Acquire(ctx context.Context, onWait func(limit string, resumeAt time.Time)) error {
+	if !rb.Enabled() {
+		return ctx.Err()
+	}
+
+	for {
+		resumeAt, limit, announce, ok := rb.reserve(time.Now())
+		if ok {
+			return nil
+		}
+
+		if announce && onWait != nil {
+			onWait(limit, resumeAt)
+		}
+		timer := time.NewTimer(time.Until(resumeAt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Save writes the current counts to the sidecar file
+func (rb *RequestBudget) Save() error {
+	if !rb.Enabled() {
+		return nil
+	}
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	return rb.saveLocked()
+}
+
+func (rb *RequestBudget) saveLocked() error {
+	data, err := json.MarshalIndent(rb.windows, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode request budget: %w", err)
+	}
+	if err := utils.WriteFileAtomic(rb.path, data); err != nil {
+		return err
+	}
+	rb.unsaved = 0
+	return nil
+}
@@ -0,0 +1,70 @@
+package orchestrator
+
+import (
+	"sync/atomic"
+
+	"linkedin-crawler/internal/crawler"
+	"linkedin-crawler/internal/storage"
+)
+
+// cacheResponse stores the 200 response of target when ResponseCacheTTL is set
+func (bp *BatchProcessor) cacheResponse(target string, hasProfile bool, body []byte) {
+	config := bp.autoCrawler.GetConfig()
+	if config.ResponseCacheTTL <= 0 || bp.queryService.Simulating() {
+		return
+	}
+	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
+	if err := emailStorage.SaveCachedResponse(config.CrawlMode, target, hasProfile, body); err != nil {
+		bp.logWarning("⚠️ Không thể lưu response cache cho %s: %v", target, err)
+	}
+}
+
+// serveCachedResponses completes the pending emails that have a cached
+// response younger than ResponseCacheTTL before any token is obtained, so
+// they cost no request, token or license quota
+func (bp *BatchProcessor) serveCachedResponses() {
+	config := bp.autoCrawler.GetConfig()
+	if config.ResponseCacheTTL <= 0 || config.Simulate {
+		return
+	}
+	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
+
+	if purged, err := emailStorage.PurgeResponseCache(config.ResponseCacheTTL); err != nil {
+		bp.logWarning("⚠️ Không thể xoá response cache quá hạn: %v", err)
+	} else if purged > 0 {
+		bp.logInfo("🧹 Đã xoá %d response cache quá hạn", purged)
+	}
+
+	responses, err := emailStorage.GetCachedPendingResponses(config.CrawlMode, config.ResponseCacheTTL)
+	if err != nil {
+		bp.logWarning("⚠️ Không thể đọc response cache: %v", err)
+		return
+	}
+	if len(responses) == 0 {
+		return
+	}
+
+	// Crawler tạm chỉ để ghi hit.txt, crawler có tokens được tạo sau
+	crawlerInstance, err := crawler.New(config, bp.autoCrawler.GetOutputFile())
+	if err != nil {
+		bp.logWarning("⚠️ Không thể dùng response cache: %v", err)
+		return
+	}
+	bp.autoCrawler.SetCrawler(crawlerInstance)
+	defer func() {
+		crawler.Close(crawlerInstance)
+		bp.autoCrawler.SetCrawler(nil)
+	}()
+
+	served := 0
+	for _, response := range responses {
+		if atomic.LoadInt32(bp.autoCrawler.GetShutdownRequested()) == 1 {
+			break
+		}
+		// Không có token hash: email_events cho thấy kết quả không gửi request
+		bp.handleResponse(crawlerInstance, response.Target, response.HasProfile, response.Body, storage.EmailEvent{HTTPStatus: 200})
+		served++
+	}
+
+	bp.logSuccess("⚡ %d emails lấy từ response cache (TTL %s), không tốn request và quota", served, config.ResponseCacheTTL)
+}
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -23,7 +24,7 @@ func NewRetryHandler(ac *AutoCrawler) *RetryHandler {
 // RetryFailedEmails handles Phase 2 retry - processes failed emails from SQLite
 func (rh *RetryHandler) RetryFailedEmails() error {
 	maxRetry := 7
-	emailStorage, tokenStorage, _ := rh.autoCrawler.GetStorageServices()
+	emailStorage, _, _ := rh.autoCrawler.GetStorageServices()
 
 	for i := 1; i <= maxRetry; i++ {
 		config := rh.autoCrawler.GetConfig()
@@ -54,43 +55,26 @@ func (rh *RetryHandler) RetryFailedEmails() error {
 			i, len(retryEmails), len(failedEmails), len(pendingEmails))
 
 		// Show current stats
-		stats, err := emailStorage.GetEmailStats()
-		if err == nil {
-			fmt.Printf("📊 Stats hiện tại: Success: %d | Failed: %d | Pending: %d | HasInfo: %d | NoInfo: %d\n",
-				stats["success"], stats["failed"], stats["pending"], stats["has_info"], stats["no_info"])
-		}
+		stats := rh.autoCrawler.stats.Snapshot()
+		fmt.Printf("📊 Stats hiện tại: Success: %d | Failed: %d | Pending: %d | HasInfo: %d | NoInfo: %d\n",
+			stats.Success, stats.Failed, stats.Pending, stats.HasInfo, stats.NoInfo)
 
 		fmt.Println("⏳ Chờ 10 giây trước khi retry...")
 		time.Sleep(10 * time.Second)
 
-		// Get tokens for retry
-		existingTokens, err := tokenStorage.LoadTokensFromFile(config.TokensFilePath)
-		if err != nil || len(existingTokens) == 0 {
-			fmt.Println("🔑 Không có tokens, lấy tokens mới cho retry...")
-			if rh.autoCrawler.GetUsedAccountIndex() < len(rh.autoCrawler.GetAccounts()) {
-				batchProcessor := rh.autoCrawler.batchProcessor
-				tokens, err := batchProcessor.getTokensBatch()
-				if err != nil {
-					return fmt.Errorf("không thể lấy tokens cho retry: %w", err)
-				}
-				existingTokens = tokens
-			} else {
-				fmt.Println("⚠️ Không còn accounts để lấy tokens cho retry")
+		// Get tokens for retry từ các nguồn token theo thứ tự ưu tiên
+		batchProcessor := rh.autoCrawler.batchProcessor
+		var validTokens []string
+		if config.Simulate {
+			validTokens = batchProcessor.simulatedTokens()
+		} else {
+			validTokens, err = batchProcessor.tokenSources.Acquire(context.Background())
+			if err != nil {
+				fmt.Println("❌ Không có tokens hợp lệ cho retry")
 				return nil
 			}
 		}
 
-		batchProcessor := rh.autoCrawler.batchProcessor
-		validTokens, err := batchProcessor.validateExistingTokens(existingTokens)
-		if err != nil {
-			return fmt.Errorf("lỗi validate tokens cho retry: %w", err)
-		}
-
-		if len(validTokens) == 0 {
-			fmt.Println("❌ Không có tokens hợp lệ cho retry")
-			return nil
-		}
-
 		fmt.Printf("🔄 Retry với %d tokens hợp lệ...\n", len(validTokens))
 
 		// Reset failed emails to pending status before retry
@@ -148,12 +132,9 @@ func (rh *RetryHandler) RetryFailedEmails() error {
 			i, emailsBefore, emailsAfter, len(pendingAfter), len(failedAfter))
 
 		// Show updated stats
-		statsAfter, err := emailStorage.GetEmailStats()
-		if err == nil {
-			fmt.Printf("📈 Stats sau retry: Success: %d | Failed: %d | Pending: %d | HasInfo: %d | NoInfo: %d\n",
-				statsAfter["success"], statsAfter["failed"], statsAfter["pending"],
-				statsAfter["has_info"], statsAfter["no_info"])
-		}
+		statsAfter := rh.autoCrawler.stats.Snapshot()
+		fmt.Printf("📈 Stats sau retry: Success: %d | Failed: %d | Pending: %d | HasInfo: %d | NoInfo: %d\n",
+			statsAfter.Success, statsAfter.Failed, statsAfter.Pending, statsAfter.HasInfo, statsAfter.NoInfo)
 	}
 
 	return nil
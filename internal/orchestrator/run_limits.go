@@ -0,0 +1,46 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// accountLimitLeft returns how many accounts the run may still log in with,
+// or -1 without a limit. Accounts skipped for their daily cap do not count.
+func (bp *BatchProcessor) accountLimitLeft() int {
+	if bp.accountLimit <= 0 {
+		return -1
+	}
+	return max(bp.accountLimit-int(atomic.LoadInt64(&bp.run.loginAttempts)), 0)
+}
+
+// logRunLimits reports, for runs with a per-run limit, what the run used and
+// what it left for the next run
+func (bp *BatchProcessor) logRunLimits() {
+	if bp.emailLimit <= 0 && bp.accountLimit <= 0 {
+		return
+	}
+	completed, failed := bp.RunProgress()
+	logins := int(atomic.LoadInt64(&bp.run.loginAttempts))
+	accountsLeft := len(bp.autoCrawler.GetAccounts()) - bp.autoCrawler.GetUsedAccountIndex()
+	remaining := bp.autoCrawler.stateManager.CountRemainingEmails()
+
+	switch {
+	case remaining == 0:
+	case bp.emailLimitLeft() == 0:
+		bp.logInfo("🎯 Lần chạy dừng vì đạt giới hạn %d emails", bp.emailLimit)
+	case bp.accountLimitLeft() == 0:
+		bp.logInfo("🎯 Lần chạy dừng vì đạt giới hạn %d accounts", bp.accountLimit)
+	}
+	bp.logInfo("📊 Đã dùng: %d/%s emails, %d/%s accounts", completed+failed, formatRunLimit(bp.emailLimit), logins, formatRunLimit(bp.accountLimit))
+	bp.logInfo("📋 Còn lại cho lần chạy sau: %d emails chưa xử lý, %d accounts chưa dùng",
+		remaining, max(accountsLeft, 0))
+}
+
+// formatRunLimit formats a per-run limit, 0 meaning no limit
+func formatRunLimit(limit int) string {
+	if limit <= 0 {
+		return "∞"
+	}
+	return fmt.Sprintf("%d", limit)
+}
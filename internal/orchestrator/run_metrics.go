@@ -0,0 +1,161 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"linkedin-crawler/internal/storage"
+)
+
+// runCheckpointInterval is how often the metrics of a running crawl are saved
+const runCheckpointInterval = 5 * time.Second
+
+// runMetrics counts what happens during one run; it is saved to the runs
+// table every runCheckpointInterval and when the run ends so runs can be
+// compared over time, and a crash or restart loses at most a few seconds
+type runMetrics struct {
+	startedAt time.Time
+
+	requests       int64 // Mọi lần gửi request, kể cả retry
+	rateLimited    int64 // Số response 429
+	answered       int64 // Số request có response (status != 0)
+	latencyNanos   int64 // Tổng thời gian chờ của các request có response
+	firstRequestAt int64 // UnixNano của request đầu tiên (0 = chưa có)
+	lastRequestAt  int64 // UnixNano của request gần nhất
+	loginAttempts  int64
+	tokensObtained int64
+}
+
+// startRun resets the run metrics and marks the start time
+func (bp *BatchProcessor) startRun() {
+	bp.run = runMetrics{startedAt: time.Now()}
+	bp.rateLimits.reset()
+	bp.autoCrawler.stats.StartRun()
+}
+
+// countRequest records one request sent at sentAt, whether it was rate
+// limited and how long the response took
+func (bp *BatchProcessor) countRequest(statusCode int, sentAt time.Time) {
+	atomic.AddInt64(&bp.run.requests, 1)
+	if statusCode == 429 {
+		atomic.AddInt64(&bp.run.rateLimited, 1)
+	}
+	if statusCode != 0 {
+		atomic.AddInt64(&bp.run.answered, 1)
+		atomic.AddInt64(&bp.run.latencyNanos, int64(time.Since(sentAt)))
+	}
+	atomic.CompareAndSwapInt64(&bp.run.firstRequestAt, 0, sentAt.UnixNano())
+	atomic.StoreInt64(&bp.run.lastRequestAt, time.Now().UnixNano())
+}
+
+// countLogin records one account login attempt
+func (bp *BatchProcessor) countLogin(success bool) {
+	atomic.AddInt64(&bp.run.loginAttempts, 1)
+	if success {
+		atomic.AddInt64(&bp.run.tokensObtained, 1)
+	}
+}
+
+// RunProgress returns the emails completed and failed so far in this run
+func (bp *BatchProcessor) RunProgress() (completed, failed int) {
+	stats := bp.autoCrawler.stats.Snapshot()
+	return stats.RunCompleted(), stats.RunFailed
+}
+
+// RateLimitProgress returns how many emails wait for a 429 backoff and how
+// many times emails were re-queued so far in this run. They are counted
+// apart from failed emails.
+func (bp *BatchProcessor) RateLimitProgress() (waiting, requeued int) {
+	return bp.rateLimits.Len(), bp.autoCrawler.stats.Snapshot().RunRequeued
+}
+
+// runRecord snapshots the run metrics into a storage record
+func (bp *BatchProcessor) runRecord(finishedAt time.Time) storage.RunRecord {
+	config := bp.autoCrawler.GetConfig()
+	stats := bp.autoCrawler.stats.Snapshot()
+	record := storage.RunRecord{
+		RunID:          bp.autoCrawler.GetRunID(),
+		StartedAt:      bp.run.startedAt,
+		FinishedAt:     finishedAt,
+		Campaign:       config.Campaign,
+		CrawlMode:      config.CrawlMode,
+		MaxConcurrency: config.MaxConcurrency,
+		RequestsPerSec: config.RequestsPerSec,
+		PacingProfile:  config.PacingProfile,
+		Hits:           stats.RunHits,
+		NoInfo:         stats.RunNoInfo,
+		Failed:         stats.RunFailed,
+		Requests:       int(atomic.LoadInt64(&bp.run.requests)),
+		RateLimited:    int(atomic.LoadInt64(&bp.run.rateLimited)),
+		LoginAttempts:  int(atomic.LoadInt64(&bp.run.loginAttempts)),
+		TokensObtained: int(atomic.LoadInt64(&bp.run.tokensObtained)),
+	}
+	record.Processed = record.Hits + record.NoInfo + record.Failed
+	return record
+}
+
+// startRunCheckpoints saves the run as unfinished every runCheckpointInterval
+func (ac *AutoCrawler) startRunCheckpoints() {
+	ac.checkpointStop = make(chan struct{})
+	ac.checkpointDone = make(chan struct{})
+	go func() {
+		defer close(ac.checkpointDone)
+		ticker := time.NewTicker(runCheckpointInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ac.checkpointRun()
+			case <-ac.checkpointStop:
+				return
+			}
+		}
+	}()
+}
+
+// stopRunCheckpoints stops the checkpoint loop before the final recordRun
+func (ac *AutoCrawler) stopRunCheckpoints() {
+	if ac.checkpointStop == nil {
+		return
+	}
+	close(ac.checkpointStop)
+	<-ac.checkpointDone
+	ac.checkpointStop = nil
+}
+
+// checkpointRun saves the metrics so far; runs that sent no request yet are skipped
+func (ac *AutoCrawler) checkpointRun() {
+	record := ac.batchProcessor.runRecord(time.Now())
+	if record.Requests == 0 && record.LoginAttempts == 0 {
+		return
+	}
+	record.Unfinished = true
+	if err := ac.emailStorage.SaveRun(record); err != nil {
+		fmt.Printf("⚠️ Không thể lưu checkpoint lần chạy: %v\n", err)
+	}
+}
+
+// recordRun saves the final metrics of this run; runs that sent no request are skipped
+func (ac *AutoCrawler) recordRun() {
+	if ac.batchProcessor.run.startedAt.IsZero() {
+		return
+	}
+	record := ac.batchProcessor.runRecord(time.Now())
+	if record.Requests == 0 && record.LoginAttempts == 0 {
+		return
+	}
+
+	if err := ac.emailStorage.SaveRun(record); err != nil {
+		fmt.Printf("⚠️ Không thể lưu thống kê lần chạy: %v\n", err)
+		return
+	}
+	// Token giả lập không nói gì về token thật
+	if !ac.config.Simulate {
+		if err := ac.emailStorage.SaveTokenUsage(record.RunID, ac.batchProcessor.tokenUsage()); err != nil {
+			fmt.Printf("⚠️ Không thể lưu số liệu token: %v\n", err)
+		}
+	}
+	fmt.Printf("📈 Lần chạy: %.0f emails/giờ | Hit rate %.1f%% | %.2f tokens/account | 429: %.1f%%\n",
+		record.EmailsPerHour(), record.HitRate(), record.TokensPerAccount(), record.RateLimitRate())
+}
@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/integrations"
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// startSheetsSync starts the Google Sheets sync when a spreadsheet is
+// configured. Cấu hình sai chỉ cảnh báo: hit vẫn được lưu vào file và DB.
+func (ac *AutoCrawler) startSheetsSync() {
+	if ac.config.SheetsSpreadsheetID == "" {
+		return
+	}
+
+	sheetsSync, err := integrations.NewSheetsSync(ac.config)
+	if err != nil {
+		fmt.Printf("⚠️ Google Sheets sync bị tắt: %v\n", err)
+		return
+	}
+	sheetsSync.Start()
+	ac.sheetsSync = sheetsSync
+
+	if ac.config.SheetsInterval > 0 {
+		fmt.Printf("📗 Google Sheets sync: %s mỗi %v\n", ac.config.SheetsRange, ac.config.SheetsInterval)
+	} else {
+		fmt.Printf("📗 Google Sheets sync: %s, gửi ngay từng hit\n", ac.config.SheetsRange)
+	}
+}
+
+// syncHit queues a hit for the Google Sheet, if sync is on
+func (ac *AutoCrawler) syncHit(email string, profile models.ProfileData) {
+	if ac.sheetsSync == nil {
+		return
+	}
+	ac.sheetsSync.Add(utils.HitResult{
+		Email:       strings.TrimSpace(email),
+		Name:        profile.User,
+		LinkedInURL: utils.NormalizeLinkedInURL(profile.LinkedInURL),
+		Location:    profile.Location,
+		Connections: profile.ConnectionCount,
+		Source:      profile.Source,
+		Timestamp:   time.Now(),
+		Fields:      ac.inputFields(email),
+	})
+}
+
+// stopSheetsSync sends the hits still queued for the Google Sheet
+func (ac *AutoCrawler) stopSheetsSync() {
+	if ac.sheetsSync == nil {
+		return
+	}
+	if err := ac.sheetsSync.Stop(); err != nil {
+		fmt.Printf("⚠️ Không thể sync %d hits cuối lên Google Sheets: %v\n", ac.sheetsSync.Pending(), err)
+		return
+	}
+	fmt.Println("📗 Đã sync hits lên Google Sheets")
+}
@@ -0,0 +1,55 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// finishRun is the last step of Run: it exports the pending emails, flushes
+// the log files and only then pauses SleepDuration when one is configured.
+// The run metrics, Google Sheets queue and notifications are flushed by the
+// deferred steps before it.
+func (ac *AutoCrawler) finishRun() {
+	ac.gracefulShutdown()
+	ac.flushLogs()
+
+	if ac.config.SleepDuration > 0 && atomic.LoadInt32(&ac.shutdownRequested) == 0 {
+		fmt.Printf("💤 Sleep %v trước khi thoát...\n", ac.config.SleepDuration)
+		time.Sleep(ac.config.SleepDuration)
+	}
+}
+
+// flushLogs closes the log channel and waits until every line is written to
+// the crawler and run logs; lines logged afterwards are dropped
+func (ac *AutoCrawler) flushLogs() {
+	ac.logMutex.Lock()
+	if ac.logClosed {
+		ac.logMutex.Unlock()
+		return
+	}
+	ac.logClosed = true
+	close(ac.logChan)
+	ac.logMutex.Unlock()
+
+	ac.logWaitGroup.Wait()
+}
+
+// shutdownOnSignal stops the crawl on SIGINT/SIGTERM and waits until Run has
+// finished the requests in flight and saved its state, at most RequestTimeout
+// plus ShutdownTimeout, before the process exits
+func (ac *AutoCrawler) shutdownOnSignal() {
+	ac.Stop()
+	if atomic.LoadInt32(&ac.running) == 1 {
+		timeout := ac.config.RequestTimeout + ac.config.ShutdownTimeout
+		fmt.Printf("⏳ Chờ các request đang chạy kết thúc và lưu trạng thái (tối đa %v)...\n", timeout)
+		select {
+		case <-ac.runDone:
+			return
+		case <-time.After(timeout):
+			fmt.Println("⚠️ Hết thời gian chờ, lưu trạng thái và thoát")
+		}
+	}
+	ac.gracefulShutdown()
+	ac.flushLogs()
+}
@@ -59,7 +59,6 @@ func (sm *StateManager) GetRemainingEmails() []string {
 
 // SaveStateOnShutdown saves the current state when shutting down - exports pending emails to file
 func (sm *StateManager) SaveStateOnShutdown() {
-	config := sm.autoCrawler.GetConfig()
 	fmt.Println("💾 Đang lưu trạng thái trước khi thoát…")
 
 	// 1) Mở 1 kết nối DB mới riêng cho việc export
@@ -77,21 +76,21 @@ func (sm *StateManager) SaveStateOnShutdown() {
 	}()
 
 	// 2) Export pending emails về file
-	if err := freshStorage.ExportPendingEmailsToFile(config.EmailsFilePath); err != nil {
+	if err := freshStorage.ExportPendingEmailsToFile(sm.autoCrawler.PendingExportPath()); err != nil {
 		fmt.Printf("⚠️ Không thể export pending emails: %v\n", err)
 	} else {
 		fmt.Println("💾 Đã export pending emails thành công")
 	}
 
 	// 3) Lấy stats cuối cùng
-	stats, err := freshStorage.GetEmailStats()
-	if err != nil {
+	statsService := sm.autoCrawler.Stats()
+	if err := statsService.Refresh(); err != nil {
 		fmt.Printf("⚠️ Không thể lấy stats cuối: %v\n", err)
 	} else {
+		stats := statsService.Snapshot()
 		fmt.Printf(
 			"📊 Tổng kết: Success: %d | Failed: %d | Pending: %d | HasInfo: %d | NoInfo: %d\n",
-			stats["success"], stats["failed"], stats["pending"],
-			stats["has_info"], stats["no_info"],
+			stats.Success, stats.Failed, stats.Pending, stats.HasInfo, stats.NoInfo,
 		)
 	}
 }
@@ -116,23 +115,9 @@ func (sm *StateManager) UpdateEmailsFile() {
 	fmt.Printf("💾 Đã cập nhật file emails: %d emails pending còn lại\n", len(pendingEmails))
 }
 
-// GetEmailStats returns current email statistics from SQLite with error handling
+// GetEmailStats returns the current email statistics from the StatsService
 func (sm *StateManager) GetEmailStats() (map[string]int, error) {
-	emailStorage, _, _ := sm.autoCrawler.GetStorageServices()
-
-	stats, err := emailStorage.GetEmailStats()
-	if err != nil {
-		// Return empty stats instead of nil to avoid panics
-		return map[string]int{
-			"pending":  0,
-			"success":  0,
-			"failed":   0,
-			"has_info": 0,
-			"no_info":  0,
-		}, err
-	}
-
-	return stats, nil
+	return sm.autoCrawler.Stats().Snapshot().EmailStats(), nil
 }
 
 // PrintDetailedStats prints detailed statistics from SQLite with error handling
@@ -163,4 +148,5 @@ func (sm *StateManager) PrintDetailedStats() {
 			fmt.Printf("   🎯 Tỷ lệ có data trong thành công: %.1f%%\n", dataPercent)
 		}
 	}
+
 }
@@ -0,0 +1,195 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync"
+
+	"linkedin-crawler/internal/storage"
+)
+
+// EmailOutcome is what a finished lookup did to an email
+type EmailOutcome int
+
+const (
+	OutcomeHit      EmailOutcome = iota // success, có thông tin LinkedIn
+	OutcomeNoInfo                       // success, không có thông tin
+	OutcomeFailed                       // failed
+	OutcomeRequeued                     // 429, vẫn pending chờ backoff
+)
+
+// Stats is a consistent snapshot of the StatsService counters
+type Stats struct {
+	// Emails trong database, như GetEmailStats
+	Pending int
+	Success int
+	Failed  int
+	HasInfo int
+	NoInfo  int
+
+	// Lần chạy hiện tại
+	RunHits     int
+	RunNoInfo   int
+	RunFailed   int
+	RunRequeued int // Số lần email bị 429 được đưa vào hàng chờ thử lại
+
+	// Batch hiện tại (mỗi bộ tokens là một batch)
+	BatchProcessed int // Emails worker đã nhận
+	BatchSuccess   int
+	BatchFailed    int
+	BatchRequeued  int
+}
+
+// Processed returns the emails in the database that are done, success or failed
+func (s Stats) Processed() int {
+	return s.Success + s.Failed
+}
+
+// RunCompleted returns the emails completed in this run, hit or no info
+func (s Stats) RunCompleted() int {
+	return s.RunHits + s.RunNoInfo
+}
+
+// RunProcessed returns the emails done in this run, completed or failed
+func (s Stats) RunProcessed() int {
+	return s.RunCompleted() + s.RunFailed
+}
+
+// EmailStats returns the database totals with the keys of GetEmailStats
+func (s Stats) EmailStats() map[string]int {
+	return map[string]int{
+		"pending":  s.Pending,
+		"success":  s.Success,
+		"failed":   s.Failed,
+		"has_info": s.HasInfo,
+		"no_info":  s.NoInfo,
+	}
+}
+
+// StatsService owns the email counters of a crawl. Every status change the
+// crawler makes goes through Record, which writes the database and updates
+// the counters under one lock, so the run, the batch and the database totals
+// never disagree. The progress stream, notifications, license checks and the
+// GUI read Snapshot instead of keeping counters of their own.
+type StatsService struct {
+	emailStorage *storage.EmailStorage
+
+	mutex sync.RWMutex
+	stats Stats
+}
+
+// NewStatsService creates the service and loads the database totals
+func NewStatsService(emailStorage *storage.EmailStorage) *StatsService {
+	s := &StatsService{emailStorage: emailStorage}
+	if err := s.Refresh(); err != nil {
+		fmt.Printf("⚠️ Không thể lấy stats từ database: %v\n", err)
+	}
+	return s
+}
+
+// LoadStats reads the database totals of emailStorage through a StatsService,
+// for views that need them while no crawl is running
+func LoadStats(emailStorage *storage.EmailStorage) (Stats, error) {
+	s := &StatsService{emailStorage: emailStorage}
+	if err := s.Refresh(); err != nil {
+		return Stats{}, err
+	}
+	return s.Snapshot(), nil
+}
+
+// Refresh reloads the database totals, for changes made outside Record:
+// rows added during the crawl, failed emails reset for retry
+func (s *StatsService) Refresh() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	totals, err := s.emailStorage.GetEmailStats()
+	if err != nil {
+		return err
+	}
+	s.stats.Pending = totals["pending"]
+	s.stats.Success = totals["success"]
+	s.stats.Failed = totals["failed"]
+	s.stats.HasInfo = totals["has_info"]
+	s.stats.NoInfo = totals["no_info"]
+	return nil
+}
+
+// StartRun resets the run and batch counters
+func (s *StatsService) StartRun() {
+	s.mutex.Lock()
+	s.stats.RunHits, s.stats.RunNoInfo, s.stats.RunFailed, s.stats.RunRequeued = 0, 0, 0, 0
+	s.mutex.Unlock()
+	s.StartBatch()
+}
+
+// StartBatch resets the batch counters and reloads the database totals
+func (s *StatsService) StartBatch() {
+	s.mutex.Lock()
+	s.stats.BatchProcessed, s.stats.BatchSuccess, s.stats.BatchFailed, s.stats.BatchRequeued = 0, 0, 0, 0
+	s.mutex.Unlock()
+	if err := s.Refresh(); err != nil {
+		fmt.Printf("⚠️ Không thể lấy stats từ database: %v\n", err)
+	}
+}
+
+// BeginEmail counts an email taken by a worker in the current batch
+func (s *StatsService) BeginEmail() {
+	s.mutex.Lock()
+	s.stats.BatchProcessed++
+	s.mutex.Unlock()
+}
+
+// Record writes the outcome of a pending email to the database with its last
+// request event and counts it once the write succeeded. It returns the
+// counters right after the change.
+func (s *StatsService) Record(email string, outcome EmailOutcome, event storage.EmailEvent) (Stats, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var err error
+	switch outcome {
+	case OutcomeHit:
+		err = s.emailStorage.UpdateEmailStatusWithEvent(email, storage.StatusSuccess, true, false, event)
+	case OutcomeNoInfo:
+		err = s.emailStorage.UpdateEmailStatusWithEvent(email, storage.StatusSuccess, false, true, event)
+	case OutcomeFailed:
+		err = s.emailStorage.UpdateEmailStatusWithEvent(email, storage.StatusFailed, false, false, event)
+	case OutcomeRequeued:
+		err = s.emailStorage.UpdateEmailStatusWithEvent(email, storage.StatusPending, false, false, event)
+	}
+	if err != nil {
+		return s.stats, err
+	}
+
+	st := &s.stats
+	switch outcome {
+	case OutcomeHit:
+		st.Pending--
+		st.Success++
+		st.HasInfo++
+		st.RunHits++
+		st.BatchSuccess++
+	case OutcomeNoInfo:
+		st.Pending--
+		st.Success++
+		st.NoInfo++
+		st.RunNoInfo++
+		st.BatchSuccess++
+	case OutcomeFailed:
+		st.Pending--
+		st.Failed++
+		st.RunFailed++
+		st.BatchFailed++
+	case OutcomeRequeued:
+		st.RunRequeued++
+		st.BatchRequeued++
+	}
+	return s.stats, nil
+}
+
+// Snapshot returns all counters at one instant
+func (s *StatsService) Snapshot() Stats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.stats
+}
@@ -0,0 +1,93 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+
+	"linkedin-crawler/internal/storage"
+)
+
+// minForecastExpiredTokens là số token đã hết hạn tối thiểu trong lịch sử trước khi dự báo
+const minForecastExpiredTokens = 3
+
+// TokenForecast estimates how many tokens, and so accounts, the pending
+// emails will use, from how many requests tokens lasted in earlier runs
+type TokenForecast struct {
+	PendingEmails    int
+	RequestsPerEmail float64
+	RequestsPerToken float64 // Số request trung bình trước khi token hết hạn
+	TokensPerAccount float64
+	TokensOnHand     float64 // Phần đời còn lại của các token hợp lệ, tính theo số token
+	TokensNeeded     int     // Token mới cần lấy thêm
+	AccountsNeeded   int
+	AccountsLeft     int
+	HasHistory       bool // false khi chưa đủ dữ liệu để dự báo
+}
+
+// Covered reports whether the accounts left are expected to be enough
+func (f TokenForecast) Covered() bool {
+	return !f.HasHistory || f.AccountsNeeded <= f.AccountsLeft
+}
+
+// Summary describes the forecast in one line
+func (f TokenForecast) Summary() string {
+	if !f.HasHistory {
+		return fmt.Sprintf("%d pending emails - not enough token history to forecast yet", f.PendingEmails)
+	}
+	return fmt.Sprintf("%d pending emails need ~%d more tokens (~%d accounts, %d left)",
+		f.PendingEmails, f.TokensNeeded, f.AccountsNeeded, f.AccountsLeft)
+}
+
+// ForecastTokens builds a token forecast for pending emails. validTokenRequests
+// holds, for each token still valid, the requests it already sent in this run
+// (zeros before a run).
+func ForecastTokens(emailStorage *storage.EmailStorage, pending int, validTokenRequests []int, accountsLeft int) (TokenForecast, error) {
+	forecast := TokenForecast{PendingEmails: pending, AccountsLeft: accountsLeft}
+
+	lifetime, err := emailStorage.GetTokenLifetime()
+	if err != nil {
+		return forecast, err
+	}
+	yield, err := emailStorage.GetYieldHistory()
+	if err != nil {
+		return forecast, err
+	}
+
+	forecast.RequestsPerEmail = lifetime.RequestsPerEmail
+	forecast.RequestsPerToken = lifetime.RequestsPerToken
+	forecast.TokensPerAccount = yield.TokensPerAccount()
+	forecast.HasHistory = lifetime.ExpiredTokens >= minForecastExpiredTokens &&
+		forecast.RequestsPerEmail > 0 && forecast.RequestsPerToken > 0
+	if !forecast.HasHistory {
+		return forecast, nil
+	}
+
+	for _, used := range validTokenRequests {
+		forecast.TokensOnHand += math.Max(forecast.RequestsPerToken-float64(used), 0) / forecast.RequestsPerToken
+	}
+
+	tokensNeeded := float64(pending)*forecast.RequestsPerEmail/forecast.RequestsPerToken - forecast.TokensOnHand
+	if tokensNeeded > 0 {
+		forecast.TokensNeeded = int(math.Ceil(tokensNeeded))
+		// Chưa có lịch sử đăng nhập thì coi mỗi account cho một token
+		perAccount := forecast.TokensPerAccount
+		if perAccount <= 0 {
+			perAccount = 1
+		}
+		forecast.AccountsNeeded = int(math.Ceil(tokensNeeded / perAccount))
+	}
+	return forecast, nil
+}
+
+// TokenForecast forecasts the tokens the remaining emails of the running
+// crawl will need, counting what the current tokens have already used
+func (ac *AutoCrawler) TokenForecast() (TokenForecast, error) {
+	var used []int
+	for _, token := range ac.TokenHealth() {
+		if token.Valid && !token.Expired {
+			used = append(used, token.Requests)
+		}
+	}
+	accountsLeft := len(ac.GetAccounts()) - ac.GetUsedAccountIndex()
+	return ForecastTokens(ac.emailStorage, ac.stats.Snapshot().Pending, used, max(accountsLeft, 0))
+}
@@ -0,0 +1,94 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/storage"
+)
+
+// tokenExpiryAge is the age from which an invalidated token counts as expired;
+// a token rejected younger than this counts as banned
+const tokenExpiryAge = 45 * time.Minute
+
+// accountBanThreshold is how many banned tokens mark their account as banned
+const accountBanThreshold = 2
+
+// reportTokenInvalidation records which account an invalidated token came
+// from and whether it expired or was banned, and marks the account banned
+// once enough of its tokens were
+func (bp *BatchProcessor) reportTokenInvalidation(fingerprint string, statusCode, requests int) {
+	if bp.autoCrawler.GetConfig().Simulate {
+		return
+	}
+	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
+
+	account, obtainedAt, ok, err := emailStorage.GetTokenOrigin(fingerprint)
+	if err != nil {
+		bp.logWarning("⚠️ Không thể tra account của token %s: %v", fingerprint, err)
+	}
+
+	inv := storage.TokenInvalidation{
+		RunID:      bp.autoCrawler.GetRunID(),
+		TokenHash:  fingerprint,
+		Account:    account,
+		Cause:      models.TokenInvalidExpired,
+		HTTPStatus: statusCode,
+		Requests:   requests,
+	}
+	// Không rõ lúc lấy token (token cũ trong file) thì coi là hết hạn, không phạt account
+	if ok && !obtainedAt.IsZero() {
+		inv.TokenAge = time.Since(obtainedAt)
+		if inv.TokenAge < tokenExpiryAge {
+			inv.Cause = models.TokenInvalidBanned
+		}
+	}
+
+	bans, err := emailStorage.RecordTokenInvalidation(inv)
+	if err != nil {
+		bp.logWarning("⚠️ Không thể ghi token hết hiệu lực: %v", err)
+		return
+	}
+	if account == "" {
+		return
+	}
+
+	if inv.Cause == models.TokenInvalidBanned {
+		bp.logWarning("🚫 Token %s của %s bị chặn sau %s (%d requests)", fingerprint, account, inv.TokenAge.Round(time.Minute), requests)
+	}
+	if inv.Cause == models.TokenInvalidBanned && bans >= accountBanThreshold {
+		detail := fmt.Sprintf("%d tokens bị chặn sớm", bans)
+		if err := emailStorage.MarkAccountBanned(account, detail); err != nil {
+			bp.logWarning("⚠️ Không thể đánh dấu account %s bị chặn: %v", account, err)
+			return
+		}
+		bp.logWarning("🚫 Account %s bị đánh dấu banned (%s), sẽ không được dùng lại", account, detail)
+	}
+}
+
+// skipBannedAccounts drops accounts marked banned in the account history
+func (bp *BatchProcessor) skipBannedAccounts(accounts []models.Account) []models.Account {
+	emailStorage, _, _ := bp.autoCrawler.GetStorageServices()
+
+	emails := make([]string, len(accounts))
+	for i, account := range accounts {
+		emails[i] = account.Email
+	}
+	history, err := emailStorage.GetAccountHistory(emails)
+	if err != nil {
+		bp.logWarning("⚠️ Không thể đọc lịch sử accounts: %v", err)
+		return accounts
+	}
+
+	selected := make([]models.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if record, ok := history[strings.ToLower(strings.TrimSpace(account.Email))]; ok && record.Status == models.AccountStatusBanned {
+			bp.logWarning("⏭️ Bỏ qua account bị chặn: %s (%s)", account.Email, record.Detail)
+			continue
+		}
+		selected = append(selected, account)
+	}
+	return selected
+}
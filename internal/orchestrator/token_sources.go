@@ -0,0 +1,257 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"linkedin-crawler/internal/auth"
+	"linkedin-crawler/internal/integrations"
+	"linkedin-crawler/internal/models"
+)
+
+// ErrNoTokens is returned when no token source gave a valid token
+var ErrNoTokens = errors.New("no token source has tokens left")
+
+// TokenSource gives the crawler tokens from one place. The TokenSourceManager
+// asks the sources of Config.TokenSources in priority order.
+type TokenSource interface {
+	// Name returns the name of the source in Config.TokenSources
+	Name() string
+	// Available reports whether the source can still give tokens in this run
+	Available() bool
+	// Tokens returns up to need validated tokens; have is how many the
+	// sources before it gave. The file pool returns all of its valid tokens.
+	Tokens(ctx context.Context, have, need int) ([]string, error)
+}
+
+// TokenPrompt asks the user to paste tokens, one per line; "" skips
+type TokenPrompt func(ctx context.Context) (string, error)
+
+// TokenSourceManager asks token sources in priority order until MinTokens
+// valid tokens are on hand
+type TokenSourceManager struct {
+	bp      *BatchProcessor
+	sources []TokenSource
+}
+
+// newTokenSourceManager creates the sources named in priority order; names
+// were checked by config validation, unknown ones are ignored
+func newTokenSourceManager(bp *BatchProcessor, names []string) *TokenSourceManager {
+	m := &TokenSourceManager{bp: bp}
+	for _, name := range names {
+		switch name {
+		case models.TokenSourceFile:
+			m.sources = append(m.sources, &fileTokenSource{bp: bp})
+		case models.TokenSourceAccounts:
+			m.sources = append(m.sources, &accountTokenSource{bp: bp})
+		case models.TokenSourceManual:
+			m.sources = append(m.sources, &manualTokenSource{bp: bp})
+		case models.TokenSourceProvider:
+			config := bp.autoCrawler.GetConfig()
+			provider, err := integrations.NewTokenProvider(config.TokenProviderURL, config.TokenProviderAuth)
+			if err != nil {
+				fmt.Printf("⚠️ Bỏ qua nguồn token provider: %v\n", err)
+				continue
+			}
+			m.sources = append(m.sources, &providerTokenSource{bp: bp, provider: provider})
+		}
+	}
+	return m
+}
+
+// Uses reports whether the source name is configured
+func (m *TokenSourceManager) Uses(name string) bool {
+	for _, source := range m.sources {
+		if source.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the configured sources in priority order
+func (m *TokenSourceManager) Names() []string {
+	names := make([]string, len(m.sources))
+	for i, source := range m.sources {
+		names[i] = source.Name()
+	}
+	return names
+}
+
+// Acquire asks the sources in priority order until MinTokens valid tokens are
+// on hand, filling up to MaxTokens, and adds tokens from sources other than
+// the file pool to the tokens file
+func (m *TokenSourceManager) Acquire(ctx context.Context) ([]string, error) {
+	bp := m.bp
+	config := bp.autoCrawler.GetConfig()
+	target := max(config.MaxTokens, config.MinTokens)
+
+	var tokens, fresh []string
+	seen := make(map[string]bool)
+	for _, source := range m.sources {
+		if len(tokens) >= config.MinTokens {
+			break
+		}
+		if !source.Available() {
+			bp.logInfo("⏭️ Nguồn token %s không còn khả dụng", source.Name())
+			continue
+		}
+		if len(tokens) > 0 {
+			bp.logInfo("📊 Có %d tokens hợp lệ, cần thêm %d tokens - thử nguồn %s", len(tokens), config.MinTokens-len(tokens), source.Name())
+		}
+
+		got, err := source.Tokens(ctx, len(tokens), target-len(tokens))
+		if err != nil {
+			bp.logError("❌ Lỗi lấy tokens từ nguồn %s: %v", source.Name(), err)
+		}
+		for _, token := range got {
+			if seen[token] {
+				continue
+			}
+			seen[token] = true
+			tokens = append(tokens, token)
+			if source.Name() != models.TokenSourceFile {
+				fresh = append(fresh, token)
+			}
+		}
+	}
+
+	// Token mới được thêm vào pool để lần sau (và instance khác) dùng lại
+	if len(fresh) > 0 {
+		_, tokenStorage, _ := bp.autoCrawler.GetStorageServices()
+		if err := tokenStorage.SaveTokensToFile(config.TokensFilePath, fresh); err != nil {
+			bp.logError("⚠️ Lỗi lưu tokens: %v", err)
+		}
+	}
+
+	switch {
+	case len(tokens) == 0:
+		return nil, ErrNoTokens
+	case len(tokens) < config.MinTokens:
+		bp.logWarning("🔋 Các nguồn token chỉ cho %d/%d tokens, sử dụng tokens còn lại...", len(tokens), config.MinTokens)
+	default:
+		bp.logSuccess("✅ Tổng cộng có %d tokens để sử dụng", len(tokens))
+	}
+	return tokens, nil
+}
+
+// fileTokenSource is the token pool in the tokens file
+type fileTokenSource struct {
+	bp *BatchProcessor
+}
+
+func (s *fileTokenSource) Name() string { return models.TokenSourceFile }
+
+// Available is always true: the GUI and other instances may add tokens to the file
+func (s *fileTokenSource) Available() bool { return true }
+
+func (s *fileTokenSource) Tokens(ctx context.Context, have, need int) ([]string, error) {
+	bp := s.bp
+	if !bp.hasValidTokens() {
+		bp.logInfo("🔍 Không có tokens khả dụng trong file")
+		return nil, nil
+	}
+
+	bp.logInfo("🔍 Phát hiện có tokens khả dụng, đang load và validate...")
+	config := bp.autoCrawler.GetConfig()
+	_, tokenStorage, _ := bp.autoCrawler.GetStorageServices()
+	existingTokens, err := tokenStorage.LoadTokensFromFile(config.TokensFilePath)
+	if err != nil || len(existingTokens) == 0 {
+		return nil, err
+	}
+	bp.logInfo("📂 Tìm thấy %d tokens trong file, đang kiểm tra chi tiết...", len(existingTokens))
+	return bp.validateExistingTokens(existingTokens)
+}
+
+// accountTokenSource logs in with the accounts not used yet in this run
+type accountTokenSource struct {
+	bp *BatchProcessor
+}
+
+func (s *accountTokenSource) Name() string { return models.TokenSourceAccounts }
+
+// Available is false once every account was used or the run used
+// MaxAccountsPerRun of them
+func (s *accountTokenSource) Available() bool {
+	return s.bp.autoCrawler.GetUsedAccountIndex() < len(s.bp.autoCrawler.GetAccounts()) && s.bp.accountLimitLeft() != 0
+}
+
+func (s *accountTokenSource) Tokens(ctx context.Context, have, need int) ([]string, error) {
+	bp := s.bp
+	bp.logInfo("🔄 Lấy thêm tokens từ accounts (còn %d accounts)", len(bp.autoCrawler.GetAccounts())-bp.autoCrawler.GetUsedAccountIndex())
+	bp.checkAccountPlan(have, false)
+	return bp.getTokensBatch(need)
+}
+
+// manualTokenSource asks the user to paste tokens through the prompt set with
+// SetTokenPrompt; once skipped it is not asked again in the run
+type manualTokenSource struct {
+	bp      *BatchProcessor
+	skipped bool
+}
+
+func (s *manualTokenSource) Name() string { return models.TokenSourceManual }
+
+func (s *manualTokenSource) Available() bool {
+	return s.bp.tokenPrompt != nil && !s.skipped
+}
+
+func (s *manualTokenSource) Tokens(ctx context.Context, have, need int) ([]string, error) {
+	bp := s.bp
+	bp.logInfo("✋ Chờ dán token thủ công (cần %d tokens)...", need)
+	text, err := bp.tokenPrompt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []string
+	for _, line := range strings.Split(text, "\n") {
+		if token := auth.NormalizeToken(line); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		s.skipped = true
+		bp.logInfo("⏭️ Bỏ qua token thủ công, không hỏi lại trong lần chạy này")
+		return nil, nil
+	}
+
+	bp.logInfo("🔍 Kiểm tra %d tokens nhập thủ công...", len(tokens))
+	return bp.validateTokensBatch(tokens)
+}
+
+// providerTokenSource fetches tokens from the token farm at TokenProviderURL
+type providerTokenSource struct {
+	bp       *BatchProcessor
+	provider *integrations.TokenProvider
+}
+
+func (s *providerTokenSource) Name() string { return models.TokenSourceProvider }
+
+// Available is always true: the farm may have new tokens on the next request
+func (s *providerTokenSource) Available() bool { return true }
+
+func (s *providerTokenSource) Tokens(ctx context.Context, have, need int) ([]string, error) {
+	bp := s.bp
+	bp.logInfo("🌐 Lấy %d tokens từ token provider...", need)
+	fetched, err := s.provider.Fetch(ctx, need)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []string
+	for _, raw := range fetched {
+		if token := auth.NormalizeToken(raw); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		bp.logWarning("⚠️ Token provider không còn tokens")
+		return nil, nil
+	}
+
+	bp.logInfo("🔍 Kiểm tra %d tokens từ token provider...", len(tokens))
+	return bp.validateTokensBatch(tokens)
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccountRecord is the last known status of an account, kept across runs
+type AccountRecord struct {
+	Email     string
+	Status    string // models.AccountStatus*
+	Detail    string
+	Logins    int
+	UpdatedAt time.Time
+
+	// Lần đăng nhập lấy token và số token lấy được hôm nay (giờ local), cho
+	// giới hạn theo ngày của mỗi account
+	DayLogins int
+	DayTokens int
+}
+
+// RecordAccountStatus stores the latest login outcome of an account in the
+// accounts table and counts the login
+func (es *EmailStorage) RecordAccountStatus(email, status, detail string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if _, err := es.db.Exec(`
+		INSERT INTO accounts (email, status, detail, logins) VALUES (?, ?, ?, 1)
+		ON CONFLICT(email) DO UPDATE SET
+			status = excluded.status,
+			detail = excluded.detail,
+			logins = accounts.logins + 1,
+			updated_at = CURRENT_TIMESTAMP`,
+		strings.ToLower(strings.TrimSpace(email)), status, nullString(detail),
+	); err != nil {
+		return fmt.Errorf("failed to record account status: %w", err)
+	}
+	return nil
+}
+
+// RecordAccountExtraction counts one token extraction login of an account
+// (and the token, when it produced one) towards today's usage; call it after
+// RecordAccountStatus so the account row exists
+func (es *EmailStorage) RecordAccountExtraction(email string, gotToken bool) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	tokens := 0
+	if gotToken {
+		tokens = 1
+	}
+	// Ngày mới thì đếm lại từ đầu
+	if _, err := es.db.Exec(`
+		UPDATE accounts SET
+			day_logins = CASE WHEN usage_day = date('now', 'localtime') THEN day_logins ELSE 0 END + 1,
+			day_tokens = CASE WHEN usage_day = date('now', 'localtime') THEN day_tokens ELSE 0 END + ?,
+			usage_day = date('now', 'localtime')
+		WHERE email = ?`,
+		tokens, strings.ToLower(strings.TrimSpace(email)),
+	); err != nil {
+		return fmt.Errorf("failed to record account usage: %w", err)
+	}
+	return nil
+}
+
+// GetAccountHistory returns the stored record of each of emails seen in an
+// earlier run, keyed by lowercase email
+func (es *EmailStorage) GetAccountHistory(emails []string) (map[string]AccountRecord, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	records := make(map[string]AccountRecord, len(emails))
+	args := make([]interface{}, 0, bulkInsertChunk)
+
+	for start := 0; start < len(emails); start += bulkInsertChunk {
+		end := min(start+bulkInsertChunk, len(emails))
+
+		args = args[:0]
+		for _, email := range emails[start:end] {
+			args = append(args, strings.ToLower(strings.TrimSpace(email)))
+		}
+
+		query := `SELECT email, status, COALESCE(detail, ''), logins, updated_at,
+			CASE WHEN usage_day = date('now', 'localtime') THEN day_logins ELSE 0 END,
+			CASE WHEN usage_day = date('now', 'localtime') THEN day_tokens ELSE 0 END
+			FROM accounts WHERE email IN (?` +
+			strings.Repeat(", ?", len(args)-1) + ")"
+		rows, err := es.db.Query(query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query account history: %w", err)
+		}
+
+		for rows.Next() {
+			var record AccountRecord
+			if err := rows.Scan(&record.Email, &record.Status, &record.Detail, &record.Logins, &record.UpdatedAt,
+				&record.DayLogins, &record.DayTokens); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan account history: %w", err)
+			}
+			records[record.Email] = record
+		}
+		rows.Close()
+	}
+
+	return records, nil
+}
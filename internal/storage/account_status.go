@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"linkedin-crawler/internal/utils"
+)
+
+// AccountStatusEntry is the last known login outcome of one account
+type AccountStatusEntry struct {
+	Status    string    `json:"status"`
+	Detail    string    `json:"detail,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AccountStatusStore keeps per-account login outcomes next to the accounts file
+type AccountStatusStore struct {
+	path    string
+	entries map[string]AccountStatusEntry // email (lowercase) → status
+	mutex   sync.Mutex
+}
+
+// AccountStatusPath returns the status file kept next to an accounts file
+func AccountStatusPath(accountsFilePath string) string {
+	dir, base := filepath.Split(accountsFilePath)
+	return filepath.Join(dir, "."+base+".status.json")
+}
+
+// LoadAccountStatusStore reads the status file for accountsFilePath; a missing
+// file is an empty store
+func LoadAccountStatusStore(accountsFilePath string) (*AccountStatusStore, error) {
+	store := &AccountStatusStore{
+		path:    AccountStatusPath(accountsFilePath),
+		entries: make(map[string]AccountStatusEntry),
+	}
+
+	data, err := os.ReadFile(store.path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account status: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse account status %s: %w", store.path, err)
+	}
+	if store.entries == nil {
+		store.entries = make(map[string]AccountStatusEntry)
+	}
+
+	return store, nil
+}
+
+// Get returns the stored status of an account
+func (s *AccountStatusStore) Get(email string) (AccountStatusEntry, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entry, ok := s.entries[strings.ToLower(strings.TrimSpace(email))]
+	return entry, ok
+}
+
+// Set records the status of an account
+func (s *AccountStatusStore) Set(email, status, detail string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries[strings.ToLower(strings.TrimSpace(email))] = AccountStatusEntry{
+		Status:    status,
+		Detail:    detail,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Delete forgets the status of an account
+func (s *AccountStatusStore) Delete(email string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.entries, strings.ToLower(strings.TrimSpace(email)))
+}
+
+// Save writes the store to disk
+func (s *AccountStatusStore) Save() error {
+	s.mutex.Lock()
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	s.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode account status: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to save account status: %w", err)
+	}
+	return nil
+}
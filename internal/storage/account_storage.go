@@ -23,13 +23,7 @@ func NewAccountStorage() *AccountStorage {
 // LoadAccounts loads accounts from a file
 func (as *AccountStorage) LoadAccounts(filename string) ([]models.Account, error) {
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		sampleContent := `# Format: email|password
-# Ví dụ:
-# user1@example.com|password123
-# user2@example.com|mypassword456
-example@domain.com|yourpassword`
-
-		if err := os.WriteFile(filename, []byte(sampleContent), 0644); err != nil {
+		if _, err := writeSampleFile(filename, SampleAccounts); err != nil {
 			return nil, fmt.Errorf("không thể tạo file mẫu: %v", err)
 		}
 		return nil, fmt.Errorf("đã tạo file mẫu %s, vui lòng thêm accounts và chạy lại", filename)
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// bulkInsertChunk là số rows mỗi câu INSERT nhiều giá trị
+// (3 tham số/row, dưới giới hạn biến của SQLite)
+const bulkInsertChunk = 500
+
+// ImportProgressFunc is called while rows are inserted during an import
+type ImportProgressFunc func(done, total int)
+
+// SetImportProgress registers a callback for import progress (nil to disable)
+func (es *EmailStorage) SetImportProgress(fn ImportProgressFunc) {
+	es.dbMutex.Lock()
+	es.importProgress = fn
+	es.dbMutex.Unlock()
+}
+
+// bulkInsert inserts keys with multi-row INSERT OR IGNORE statements inside tx
+// and returns the number of rows actually inserted
+func bulkInsert(tx *sql.Tx, keys []string, source string, progress ImportProgressFunc) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	fullStmt, err := tx.Prepare(bulkInsertSQL(bulkInsertChunk))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare bulk insert: %w", err)
+	}
+	defer fullStmt.Close()
+
+	inserted := 0
+	args := make([]interface{}, 0, bulkInsertChunk*3)
+
+	for start := 0; start < len(keys); start += bulkInsertChunk {
+		end := start + bulkInsertChunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		args = args[:0]
+		for _, key := range keys[start:end] {
+			args = append(args, key, StatusPending, source)
+		}
+
+		var result sql.Result
+		if end-start == bulkInsertChunk {
+			result, err = fullStmt.Exec(args...)
+		} else {
+			// Chunk cuối ít rows hơn
+			result, err = tx.Exec(bulkInsertSQL(end-start), args...)
+		}
+		if err != nil {
+			return inserted, fmt.Errorf("failed to insert rows %d-%d: %w", start+1, end, err)
+		}
+
+		if rowsAffected, err := result.RowsAffected(); err == nil {
+			inserted += int(rowsAffected)
+		}
+
+		if progress != nil {
+			progress(end, len(keys))
+		}
+	}
+
+	return inserted, nil
+}
+
+// bulkInsertSQL builds an INSERT OR IGNORE statement for n rows
+func bulkInsertSQL(n int) string {
+	var b strings.Builder
+	b.WriteString("INSERT OR IGNORE INTO emails (email, status, source) VALUES ")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("(?, ?, ?)")
+	}
+	return b.String()
+}
+
+// consoleImportProgress prints progress roughly every 10% for large imports
+func consoleImportProgress(next ImportProgressFunc) ImportProgressFunc {
+	lastPercent := -1
+	return func(done, total int) {
+		if total >= 100000 {
+			if percent := done * 100 / total; percent/10 != lastPercent/10 || done == total {
+				lastPercent = percent
+				fmt.Printf("📥 Importing: %d/%d rows (%d%%)\n", done, total, percent)
+			}
+		}
+		if next != nil {
+			next(done, total)
+		}
+	}
+}
@@ -0,0 +1,618 @@
+package storage
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// campaignArchiveDirName là thư mục chứa các archive, nằm cạnh emails.db
+const campaignArchiveDirName = "archives"
+
+// archiveManifestFile is the manifest inside a campaign archive
+const archiveManifestFile = "manifest.json"
+
+// archiveTables are the rows that belong to a campaign, children first so the
+// WHERE clauses still find their parents while deleting. Each clause takes
+// the campaign as its only argument; results saved before campaigns were
+// tracked belong to campaign "". The addresses of the results come from
+// archive_emails (see fillArchiveEmails): encrypted results only hold hashes.
+var archiveTables = []struct{ name, where string }{
+	{"email_events", "lower(trim(email)) IN (SELECT email FROM temp.archive_emails WHERE campaign = ?)"},
+	{"input_fields", "key IN (SELECT email FROM temp.archive_emails WHERE campaign = ?)"},
+	{"token_usage", "run_id IN (SELECT run_id FROM runs WHERE COALESCE(campaign, '') = ?)"},
+	{"token_invalidations", "run_id IN (SELECT run_id FROM runs WHERE COALESCE(campaign, '') = ?)"},
+	{"login_attempts", "run_id IN (SELECT run_id FROM runs WHERE COALESCE(campaign, '') = ?)"},
+	{"results", "COALESCE(campaign, '') = ?"},
+	{"runs", "COALESCE(campaign, '') = ?"},
+}
+
+// CampaignSummary is one campaign found in the results and runs tables
+type CampaignSummary struct {
+	Name    string // "" = kết quả từ trước khi lưu campaign
+	Results int
+	Runs    int
+}
+
+// ArchivedFile is a result or log file stored in a campaign archive
+type ArchivedFile struct {
+	Path string `json:"path"` // Đường dẫn gốc, khôi phục về đây
+	Name string `json:"name"` // Tên trong file zip
+}
+
+// CampaignArchive is the manifest of an archived campaign
+type CampaignArchive struct {
+	Campaign   string         `json:"campaign"`
+	ArchivedAt time.Time      `json:"archived_at"`
+	Rows       map[string]int `json:"rows"` // Số dòng theo bảng
+	Files      []ArchivedFile `json:"files"`
+
+	Path string `json:"-"` // File zip
+	Size int64  `json:"-"`
+}
+
+// Results returns the number of archived results
+func (a CampaignArchive) Results() int {
+	return a.Rows["results"]
+}
+
+// Runs returns the number of archived runs
+func (a CampaignArchive) Runs() int {
+	return a.Rows["runs"]
+}
+
+// CampaignArchiveDir returns the directory holding the campaign archives of
+// the database at dbPath
+func CampaignArchiveDir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), campaignArchiveDirName)
+}
+
+// GetCampaigns returns every campaign that has results or runs, by name
+func (es *EmailStorage) GetCampaigns() ([]CampaignSummary, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	rows, err := es.db.Query(`
+		SELECT name, SUM(results), SUM(runs) FROM (
+			SELECT COALESCE(campaign, '') AS name, COUNT(*) AS results, 0 AS runs FROM results GROUP BY 1
+			UNION ALL
+			SELECT COALESCE(campaign, ''), 0, COUNT(*) FROM runs GROUP BY 1
+		) GROUP BY name ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []CampaignSummary
+	for rows.Next() {
+		var c CampaignSummary
+		if err := rows.Scan(&c.Name, &c.Results, &c.Runs); err != nil {
+			return nil, fmt.Errorf("failed to scan campaign: %w", err)
+		}
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, rows.Err()
+}
+
+// CampaignFiles returns the files of campaign on disk: the hit files when the
+// output path has a {campaign} placeholder, and the logs of runIDs. cfg holds
+// the path templates as configured, not resolved.
+func CampaignFiles(cfg models.Config, campaign string, runIDs []string) []string {
+	cfg.Campaign = campaign
+
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string) {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	// Hit file chung cho mọi campaign thì giữ nguyên, chỉ archive các dòng trong DB
+	if strings.Contains(cfg.OutputFilePath, "{campaign}") {
+		matches, _ := filepath.Glob(utils.PathTemplateGlob(cfg.OutputFilePath, cfg))
+		for _, match := range matches {
+			for _, part := range utils.HitFileParts(match) {
+				add(part)
+				add(utils.ExportStatePath(part))
+			}
+		}
+	}
+
+	logGlob := utils.PathTemplateGlob(cfg.LogFilePath, cfg)
+	for _, runID := range runIDs {
+		matches, _ := filepath.Glob(utils.RunLogPath(logGlob, runID))
+		for _, match := range matches {
+			add(match)
+		}
+	}
+
+	sort.Strings(files)
+	return files
+}
+
+// ArchiveCampaign moves the rows of campaign (results, their events and input
+// fields, runs and their token and login rows) and its files into a zip under
+// CampaignArchiveDir, then deletes them from the database and the disk. cfg
+// holds the path templates as configured.
+func (es *EmailStorage) ArchiveCampaign(cfg models.Config, campaign string) (*CampaignArchive, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	now := time.Now()
+	name := utils.ExpandPathTemplate("{campaign}", models.Config{Campaign: campaign}, now)
+	archive := &CampaignArchive{
+		Campaign:   campaign,
+		ArchivedAt: now,
+		Rows:       make(map[string]int),
+		Path:       filepath.Join(CampaignArchiveDir(es.dbPath), fmt.Sprintf("%s-%s.zip", name, now.Format(snapshotLayout))),
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var runIDs []string
+	rows, err := tx.Query("SELECT run_id FROM runs WHERE COALESCE(campaign, '') = ? AND run_id IS NOT NULL", campaign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	for rows.Next() {
+		var runID string
+		if err := rows.Scan(&runID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		runIDs = append(runIDs, runID)
+	}
+	rows.Close()
+
+	if err := es.fillArchiveEmails(tx, campaign); err != nil {
+		return nil, err
+	}
+
+	file, err := utils.CreateAtomic(archive.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Abort()
+	zw := zip.NewWriter(file)
+
+	for _, table := range archiveTables {
+		count, err := dumpArchiveTable(tx, zw, table.name, table.where, campaign)
+		if err != nil {
+			return nil, err
+		}
+		archive.Rows[table.name] = count
+	}
+	if archive.Results() == 0 && archive.Runs() == 0 {
+		return nil, fmt.Errorf("campaign %q has no results or runs", campaign)
+	}
+
+	for i, path := range CampaignFiles(cfg, campaign, runIDs) {
+		entry := ArchivedFile{Path: path, Name: fmt.Sprintf("files/%03d-%s", i+1, filepath.Base(path))}
+		if err := addArchiveFile(zw, entry); err != nil {
+			return nil, err
+		}
+		archive.Files = append(archive.Files, entry)
+	}
+
+	manifest, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	w, err := zw.Create(archiveManifestFile)
+	if err == nil {
+		_, err = w.Write(manifest)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Archive đã nằm trên đĩa: giờ mới xoá khỏi DB
+	for _, table := range archiveTables {
+		if _, err := tx.Exec("DELETE FROM "+table.name+" WHERE "+table.where, campaign); err != nil {
+			os.Remove(archive.Path)
+			return nil, fmt.Errorf("failed to delete %s of campaign: %w", table.name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		os.Remove(archive.Path)
+		return nil, fmt.Errorf("failed to commit archive: %w", err)
+	}
+
+	for _, f := range archive.Files {
+		os.Remove(f.Path)
+	}
+	archive.Size = fileSize(archive.Path)
+	return archive, nil
+}
+
+// fillArchiveEmails lists the (decrypted) addresses of the results of
+// campaign in the temporary table archive_emails of the connection of tx
+func (es *EmailStorage) fillArchiveEmails(tx *sql.Tx, campaign string) error {
+	for _, stmt := range []string{
+		"CREATE TEMP TABLE IF NOT EXISTS archive_emails (campaign TEXT NOT NULL, email TEXT NOT NULL)",
+		"DELETE FROM temp.archive_emails",
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to list campaign emails: %w", err)
+		}
+	}
+
+	rows, err := tx.Query("SELECT email, COALESCE(encrypted_email, '') FROM results WHERE COALESCE(campaign, '') = ?", campaign)
+	if err != nil {
+		return fmt.Errorf("failed to query results: %w", err)
+	}
+	var emails []string
+	for rows.Next() {
+		var email, encrypted string
+		if err := rows.Scan(&email, &encrypted); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan result: %w", err)
+		}
+		if encrypted != "" {
+			if email, err = es.cipher.decrypt(encrypted); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to decrypt result: %w", err)
+			}
+		}
+		emails = append(emails, strings.ToLower(strings.TrimSpace(email)))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query results: %w", err)
+	}
+
+	for _, email := range emails {
+		if _, err := tx.Exec("INSERT INTO temp.archive_emails (campaign, email) VALUES (?, ?)", campaign, email); err != nil {
+			return fmt.Errorf("failed to list campaign emails: %w", err)
+		}
+	}
+	return nil
+}
+
+// dumpArchiveTable writes the matching rows of table as JSON lines into
+// tables/<table>.jsonl and returns how many there were
+func dumpArchiveTable(tx *sql.Tx, zw *zip.Writer, table, where, campaign string) (int, error) {
+	rows, err := tx.Query("SELECT * FROM "+table+" WHERE "+where, campaign)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	w, err := zw.Create("tables/" + table + ".jsonl")
+	if err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	encoder := json.NewEncoder(w)
+
+	count := 0
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			switch v := values[i].(type) {
+			case time.Time:
+				row[column] = v.UTC().Format("2006-01-02 15:04:05") // Định dạng của CURRENT_TIMESTAMP
+			case []byte:
+				row[column] = string(v)
+			default:
+				row[column] = v
+			}
+		}
+		if err := encoder.Encode(row); err != nil {
+			return count, fmt.Errorf("failed to write archive: %w", err)
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// addArchiveFile copies the file at entry.Path into the zip as entry.Name
+func addArchiveFile(zw *zip.Writer, entry ArchivedFile) error {
+	src, err := os.Open(entry.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", entry.Path, err)
+	}
+	defer src.Close()
+
+	w, err := zw.Create(entry.Name)
+	if err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", entry.Path, err)
+	}
+	return nil
+}
+
+// ListCampaignArchives returns the campaign archives under dir, newest first.
+// A missing directory has no archives.
+func ListCampaignArchives(dir string) ([]CampaignArchive, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.zip"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archives: %w", err)
+	}
+
+	var archives []CampaignArchive
+	for _, path := range matches {
+		archive, err := ReadCampaignArchive(path)
+		if err != nil {
+			continue // Không phải archive của crawler
+		}
+		archives = append(archives, *archive)
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].ArchivedAt.After(archives[j].ArchivedAt)
+	})
+	return archives, nil
+}
+
+// ReadCampaignArchive reads the manifest of the campaign archive at path
+func ReadCampaignArchive(path string) (*CampaignArchive, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer zr.Close()
+	return readArchiveManifest(&zr.Reader, path)
+}
+
+func readArchiveManifest(zr *zip.Reader, path string) (*CampaignArchive, error) {
+	data, err := readArchiveEntry(zr, archiveManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("%s is not a campaign archive", path)
+	}
+	var archive CampaignArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("failed to parse archive manifest: %w", err)
+	}
+	archive.Path = path
+	archive.Size = fileSize(path)
+	return &archive, nil
+}
+
+// readArchiveEntry returns the content of the zip entry name, or nil when the
+// zip has no such entry
+func readArchiveEntry(zr *zip.Reader, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from archive: %w", name, err)
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	return nil, nil
+}
+
+// RestoreCampaignArchive puts the rows and files of the archive at path back
+// and deletes the archive. Rows still in the database (e.g. an email crawled
+// again since) and files that exist again are kept as they are. cfg holds the
+// path templates as configured: files are only restored into the folders of
+// the hit files and run logs of the campaign. Nothing is restored when a file
+// cannot be written.
+func (es *EmailStorage) RestoreCampaignArchive(cfg models.Config, path string) (*CampaignArchive, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer zr.Close()
+
+	archive, err := readArchiveManifest(&zr.Reader, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range archive.Files {
+		if !archiveFileAllowed(cfg, archive.Campaign, f.Path) {
+			return nil, fmt.Errorf("archive file %s is outside the hit file and log folders", f.Path)
+		}
+	}
+
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range archiveTables {
+		data, err := readArchiveEntry(&zr.Reader, "tables/"+table.name+".jsonl")
+		if err != nil {
+			return nil, err
+		}
+		if err := restoreArchiveTable(tx, table.name, data); err != nil {
+			return nil, err
+		}
+	}
+	// Archive làm trước khi bật mã hoá: email của results còn là plain text
+	if err := sealResultEmails(tx, es.cipher); err != nil {
+		return nil, err
+	}
+
+	// Ghi file trước khi commit: file lỗi thì xoá các file đã ghi và rollback
+	var written []string
+	removeWritten := func() {
+		for _, path := range written {
+			os.Remove(path)
+		}
+	}
+	for _, f := range archive.Files {
+		if _, err := os.Stat(f.Path); err == nil {
+			continue
+		}
+		data, err := readArchiveEntry(&zr.Reader, f.Name)
+		if err == nil && data == nil {
+			err = fmt.Errorf("archive has no %s", f.Name)
+		}
+		if err == nil {
+			err = utils.WriteFileAtomic(f.Path, data)
+		}
+		if err != nil {
+			removeWritten()
+			return nil, fmt.Errorf("failed to restore %s: %w", f.Path, err)
+		}
+		written = append(written, f.Path)
+	}
+	if err := tx.Commit(); err != nil {
+		removeWritten()
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+
+	zr.Close()
+	if err := os.Remove(path); err != nil {
+		return archive, fmt.Errorf("restored, but failed to delete the archive: %w", err)
+	}
+	return archive, nil
+}
+
+// archiveFileAllowed reports whether an archived file may be restored to
+// path: it must lie in the folder of the hit files or run logs of campaign,
+// where CampaignFiles found it
+func archiveFileAllowed(cfg models.Config, campaign, path string) bool {
+	cfg.Campaign = campaign
+	dir := filepath.Dir(filepath.Clean(path))
+	for _, template := range []string{cfg.OutputFilePath, cfg.LogFilePath} {
+		if template == "" {
+			continue
+		}
+		pattern := filepath.Dir(filepath.Clean(utils.PathTemplateGlob(template, cfg)))
+		if ok, _ := filepath.Match(pattern, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// tableColumns returns the columns of table in the current schema
+func tableColumns(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// restoreArchiveTable inserts the JSON lines of one archived table, skipping
+// rows whose key is taken. Every archived column must exist in table.
+func restoreArchiveTable(tx *sql.Tx, table string, data []byte) error {
+	known, err := tableColumns(tx, table)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber() // Giữ nguyên số nguyên lớn
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return fmt.Errorf("failed to parse archived %s: %w", table, err)
+		}
+
+		columns := make([]string, 0, len(row))
+		for column := range row {
+			if !known[column] {
+				return fmt.Errorf("archived %s has unknown column %q", table, column)
+			}
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+
+		quoted := make([]string, len(columns))
+		args := make([]interface{}, len(columns))
+		for i, column := range columns {
+			quoted[i] = `"` + column + `"`
+			args[i] = row[column]
+			if n, ok := row[column].(json.Number); ok {
+				if v, err := n.Int64(); err == nil {
+					args[i] = v
+				} else {
+					args[i], _ = n.Float64()
+				}
+			}
+		}
+		query := fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (%s)",
+			table, strings.Join(quoted, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", table, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// BackupDB copies the live database to destPath using the SQLite online backup API
+func (es *EmailStorage) BackupDB(destPath string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	destDB, err := sql.Open("sqlite3", destPath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer destDB.Close()
+
+	if err := copyDatabase(destDB, es.db); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	return nil
+}
+
+// RestoreDB replaces the database contents with the backup at srcPath. The
+// backup must be a crawler database no newer than this build; an older one is
+// migrated forward after the copy.
+func (es *EmailStorage) RestoreDB(srcPath string) error {
+	if _, err := os.Stat(srcPath); err != nil {
+		return fmt.Errorf("backup file not found: %w", err)
+	}
+
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	// Restore cần lock ghi để không có query nào chạy song song
+	es.dbMutex.Lock()
+	defer es.dbMutex.Unlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	srcDB, err := sql.Open("sqlite3", "file:"+srcPath+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer srcDB.Close()
+
+	if err := checkBackupSchema(srcDB); err != nil {
+		return fmt.Errorf("cannot restore %s: %w", srcPath, err)
+	}
+
+	if err := copyDatabase(es.db, srcDB); err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	// Backup cũ hơn build này: nâng schema để các query mới chạy được
+	if err := runMigrations(es.db); err != nil {
+		return fmt.Errorf("restored backup could not be migrated: %w", err)
+	}
+	// Backup có thể khác trạng thái mã hoá (hoặc salt): dùng cipher của database vừa restore
+	if es.cipher, err = loadCipher(es.db, es.dbPath); err != nil {
+		return fmt.Errorf("restored backup could not be unlocked: %w", err)
+	}
+	return es.sealStoredEmails()
+}
+
+// checkBackupSchema fails unless db is a crawler database whose schema this
+// build knows: not newer than the latest embedded migration
+func checkBackupSchema(db *sql.DB) error {
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('schema_migrations', 'emails')").Scan(&tables); err != nil {
+		return fmt.Errorf("not a readable SQLite database: %w", err)
+	}
+	if tables != 2 {
+		return fmt.Errorf("not a crawler database")
+	}
+
+	version, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	if latest := migrations[len(migrations)-1].Version; version > latest {
+		return fmt.Errorf("backup schema is at version %d, newer than this build (%d): update the crawler first", version, latest)
+	}
+	return nil
+}
+
+// VacuumDB rebuilds the database file and returns its size before and after
+func (es *EmailStorage) VacuumDB() (int64, int64, error) {
+	if err := es.ensureDB(); err != nil {
+		return 0, 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.Lock()
+	defer es.dbMutex.Unlock()
+
+	if es.isDBClosed {
+		return 0, 0, fmt.Errorf("database is closed")
+	}
+
+	before := fileSize(es.dbPath)
+	if _, err := es.db.Exec("VACUUM"); err != nil {
+		return before, before, fmt.Errorf("vacuum failed: %w", err)
+	}
+	return before, fileSize(es.dbPath), nil
+}
+
+// copyDatabase copies the main schema of src into dst page by page
+func copyDatabase(dst, src *sql.DB) error {
+	ctx := context.Background()
+
+	dstConn, err := dst.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	return dstConn.Raw(func(dstRaw interface{}) error {
+		return srcConn.Raw(func(srcRaw interface{}) error {
+			dstSQLite, ok := dstRaw.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected destination driver connection")
+			}
+			srcSQLite, ok := srcRaw.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected source driver connection")
+			}
+
+			backup, err := dstSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return err
+			}
+
+			// Copy từng đợt 256 pages để không giữ lock quá lâu
+			for {
+				done, err := backup.Step(256)
+				if err != nil {
+					backup.Close()
+					return err
+				}
+				if done {
+					break
+				}
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+// fileSize returns the size of path or 0 if it cannot be read
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
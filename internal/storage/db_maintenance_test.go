@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"linkedin-crawler/internal/models"
+)
+
+// newTestBackup backs es up into a temp file and opens the copy for editing
+func newTestBackup(t *testing.T, es *EmailStorage) (string, *sql.DB) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := es.BackupDB(path); err != nil {
+		t.Fatalf("BackupDB: %v", err)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return path, db
+}
+
+func TestRestoreDBRejectsForeignDatabase(t *testing.T) {
+	es := newTestStorage(t)
+
+	path := filepath.Join(t.TempDir(), "other.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE TABLE notes (body TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if err := es.RestoreDB(path); err == nil || !strings.Contains(err.Error(), "not a crawler database") {
+		t.Fatalf("RestoreDB = %v, want not a crawler database", err)
+	}
+}
+
+func TestRestoreDBRejectsNewerSchema(t *testing.T) {
+	es := newTestStorage(t)
+	path, db := newTestBackup(t, es)
+	if _, err := db.Exec("INSERT INTO schema_migrations (version, name) VALUES (9999, 'future')"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if err := es.RestoreDB(path); err == nil || !strings.Contains(err.Error(), "newer than this build") {
+		t.Fatalf("RestoreDB = %v, want newer than this build", err)
+	}
+}
+
+func TestRestoreDBMigratesOlderBackup(t *testing.T) {
+	es := newTestStorage(t)
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migrations[len(migrations)-1].Version
+
+	// Backup từ build trước migration result claims
+	path, db := newTestBackup(t, es)
+	for _, stmt := range []string{
+		"ALTER TABLE results DROP COLUMN headline",
+		"ALTER TABLE results DROP COLUMN company",
+		"ALTER TABLE results DROP COLUMN encrypted_email",
+		"ALTER TABLE results DROP COLUMN claimed_by",
+		"ALTER TABLE results DROP COLUMN claimed_at",
+		"DELETE FROM schema_migrations WHERE version >= 23",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	db.Close()
+
+	if err := es.RestoreDB(path); err != nil {
+		t.Fatalf("RestoreDB: %v", err)
+	}
+	if version, err := schemaVersion(es.db); err != nil || version != latest {
+		t.Fatalf("schema version = %d, %v; want %d", version, err, latest)
+	}
+	if _, err := es.GetResults(); err != nil {
+		t.Fatalf("GetResults after restore: %v", err)
+	}
+}
+
+func TestRestoreDBReloadsCipher(t *testing.T) {
+	es := newTestStorage(t)
+	t.Cleanup(func() { SetPassphrase("") })
+
+	plainBackup, db := newTestBackup(t, es)
+	db.Close()
+	if _, err := es.EnableEncryption("correct horse battery"); err != nil {
+		t.Fatalf("EnableEncryption: %v", err)
+	}
+
+	if err := es.RestoreDB(plainBackup); err != nil {
+		t.Fatalf("RestoreDB: %v", err)
+	}
+	if es.Encrypted() {
+		t.Fatal("encryption still on after restoring an unencrypted backup")
+	}
+	profile := models.ProfileData{User: "Ada Lovelace", LinkedInURL: "https://www.linkedin.com/in/ada"}
+	if err := es.SaveResult("ada@example.com", profile, ""); err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+
+	// Mở lại không cần passphrase: kết quả phải là plain text
+	es.CloseDB()
+	SetPassphrase("")
+	reopened := &EmailStorage{fileManager: NewFileManager(), dbPath: es.dbPath}
+	if err := reopened.InitDB(); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.CloseDB()
+	results, err := reopened.GetResults()
+	if err != nil {
+		t.Fatalf("GetResults: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != profile.User {
+		t.Fatalf("results = %+v, want %s", results, profile.User)
+	}
+}
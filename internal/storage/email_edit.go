@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddEmail inserts a single pending email and reports whether it was new
+func (es *EmailStorage) AddEmail(email string) (bool, error) {
+	if err := es.ensureDB(); err != nil {
+		return false, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return false, fmt.Errorf("database is closed")
+	}
+
+	result, err := es.db.Exec(
+		"INSERT OR IGNORE INTO emails (email, status, source) VALUES (?, ?, ?)",
+		strings.ToLower(email), StatusPending, "email",
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to add email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, nil
+	}
+	return rowsAffected > 0, nil
+}
+
+// RenameEmail replaces oldEmail with newEmail and resets it to pending. When
+// newEmail is already in the database the old row is removed instead, so
+// existing results for newEmail are kept.
+func (es *EmailStorage) RenameEmail(oldEmail, newEmail string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	oldEmail, newEmail = strings.ToLower(oldEmail), strings.ToLower(newEmail)
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM emails WHERE email = ?", newEmail).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+
+	if exists > 0 {
+		_, err = tx.Exec("DELETE FROM emails WHERE email = ?", oldEmail)
+	} else {
+		_, err = tx.Exec(
+			"UPDATE emails SET email = ?, status = ?, has_info = FALSE, no_info = FALSE, error_code = NULL, error_message = NULL, last_checked_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE email = ?",
+			newEmail, StatusPending, oldEmail,
+		)
+		if err == nil {
+			// Email cũ chưa có trong DB (chưa load) thì thêm mới
+			_, err = tx.Exec(
+				"INSERT OR IGNORE INTO emails (email, status, source) VALUES (?, ?, ?)",
+				newEmail, StatusPending, "email",
+			)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rename email: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit email rename: %w", err)
+	}
+	return nil
+}
+
+// DeleteEmail removes an email from the database; its status history and
+// saved results are kept
+func (es *EmailStorage) DeleteEmail(email string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if _, err := es.db.Exec("DELETE FROM emails WHERE email = ?", strings.ToLower(email)); err != nil {
+		return fmt.Errorf("failed to delete email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// EmailEvent carries the request details attached to a status transition
+type EmailEvent struct {
+	HTTPStatus int
+	TokenHash  string // utils.TokenFingerprint, không lưu token gốc
+	Error      string
+	ErrorCode  string // models.EmailError*, lưu vào emails.error_code ("" = xoá)
+}
+
+// EmailEventRecord is one row of the email_events audit table
+type EmailEventRecord struct {
+	ID         int    `json:"id"`
+	Email      string `json:"email"`
+	OldStatus  string `json:"old_status"`
+	NewStatus  string `json:"new_status"`
+	HTTPStatus int    `json:"http_status"`
+	TokenHash  string `json:"token_hash"`
+	Error      string `json:"error"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// UpdateEmailStatusWithEvent updates an email's status and records the
+// transition in email_events within the same transaction
+func (es *EmailStorage) UpdateEmailStatusWithEvent(email string, status EmailStatus, hasInfo, noInfo bool, event EmailEvent) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldStatus sql.NullString
+	if err := tx.QueryRow("SELECT status FROM emails WHERE email = ?", email).Scan(&oldStatus); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read current status: %w", err)
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE emails SET status = ?, has_info = ?, no_info = ?, error_code = ?, error_message = ?, updated_at = CURRENT_TIMESTAMP, last_checked_at = CURRENT_TIMESTAMP WHERE email = ?",
+		status, hasInfo, noInfo, nullString(event.ErrorCode), nullString(event.Error), email,
+	); err != nil {
+		return fmt.Errorf("failed to update email status: %w", err)
+	}
+
+	// Chỉ ghi event khi status đổi hoặc có lỗi kèm theo
+	if oldStatus.String != string(status) || event.Error != "" {
+		if _, err := tx.Exec(
+			"INSERT INTO email_events (email, old_status, new_status, http_status, token_hash, error) VALUES (?, ?, ?, ?, ?, ?)",
+			email, oldStatus, string(status), nullInt(event.HTTPStatus), nullString(event.TokenHash), nullString(event.Error),
+		); err != nil {
+			return fmt.Errorf("failed to record email event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit status update: %w", err)
+	}
+	return nil
+}
+
+// GetEmailEvents returns the status history of an email, oldest first
+func (es *EmailStorage) GetEmailEvents(email string) ([]EmailEventRecord, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	rows, err := es.db.Query(`
+		SELECT id, email, COALESCE(old_status, ''), new_status, COALESCE(http_status, 0),
+		       COALESCE(token_hash, ''), COALESCE(error, ''), created_at
+		FROM email_events WHERE email = ? ORDER BY id`, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query email events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []EmailEventRecord
+	for rows.Next() {
+		var e EmailEventRecord
+		if err := rows.Scan(&e.ID, &e.Email, &e.OldStatus, &e.NewStatus, &e.HTTPStatus, &e.TokenHash, &e.Error, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan email event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// nullInt stores zero as NULL
+func nullInt(v int) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+// nullString stores empty strings as NULL
+func nullString(v string) interface{} {
+	if v == "" {
+		return nil
+	}
+	return v
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bufio"
 	"database/sql"
 	"fmt"
 	"os"
@@ -11,6 +12,9 @@ import (
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
 )
 
 // EmailStatus represents the status of an email
@@ -38,17 +42,70 @@ type EmailStorage struct {
 	dbPath      string
 	dbMutex     sync.RWMutex // Protect database access
 	isDBClosed  bool         // Track if DB is closed
+	readOnly    bool         // Mở mode=ro, không chạy migrations (viewer)
+	cipher      *fieldCipher // Mã hoá các cột của results (nil = tắt)
+
+	importProgress ImportProgressFunc // Callback tiến độ khi import
+}
+
+// emailPattern is compiled once; import validates every line against it
+var emailPattern = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+
+// defaultDBPath is used by NewEmailStorage, configurable via SetDefaultDBPath
+var (
+	defaultDBPath = "emails.db"
+	dbPathMutex   sync.RWMutex
+)
+
+// SetDefaultDBPath changes the database path used by new EmailStorage instances
+func SetDefaultDBPath(path string) {
+	if path == "" {
+		return
+	}
+
+	dbPathMutex.Lock()
+	defaultDBPath = path
+	dbPathMutex.Unlock()
+
+	// Legacy storage chưa mở DB thì trỏ sang path mới
+	globalEmailStorage.dbMutex.Lock()
+	if globalEmailStorage.db == nil {
+		globalEmailStorage.dbPath = path
+	}
+	globalEmailStorage.dbMutex.Unlock()
+}
+
+// DefaultDBPath returns the database path used by new EmailStorage instances
+func DefaultDBPath() string {
+	dbPathMutex.RLock()
+	defer dbPathMutex.RUnlock()
+	return defaultDBPath
 }
 
 // NewEmailStorage creates a new EmailStorage instance
 func NewEmailStorage() *EmailStorage {
 	return &EmailStorage{
 		fileManager: NewFileManager(),
-		dbPath:      "emails.db",
+		dbPath:      DefaultDBPath(),
 		isDBClosed:  false,
 	}
 }
 
+// NewReadOnlyEmailStorage opens the database at dbPath read-only, for
+// viewers: migrations are not applied and writes fail
+func NewReadOnlyEmailStorage(dbPath string) *EmailStorage {
+	return &EmailStorage{
+		fileManager: NewFileManager(),
+		dbPath:      dbPath,
+		readOnly:    true,
+	}
+}
+
+// GetDBPath returns the database file path
+func (es *EmailStorage) GetDBPath() string {
+	return es.dbPath
+}
+
 // InitDB initializes the SQLite database and DROPS existing table
 func (es *EmailStorage) InitDB() error {
 	es.dbMutex.Lock()
@@ -60,7 +117,12 @@ func (es *EmailStorage) InitDB() error {
 	}
 
 	var err error
-	es.db, err = sql.Open("sqlite3", es.dbPath)
+	// busy_timeout: chờ thay vì lỗi "database is locked" khi process khác đang ghi
+	dsn := es.dbPath + "?_busy_timeout=5000"
+	if es.readOnly {
+		dsn = "file:" + es.dbPath + "?mode=ro&_busy_timeout=5000"
+	}
+	es.db, err = sql.Open("sqlite3", dsn)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -72,32 +134,24 @@ func (es *EmailStorage) InitDB() error {
 
 	es.isDBClosed = false
 
-	// // IMPORTANT: Drop existing table first to start fresh
-	// if _, err := es.db.Exec("DROP TABLE IF EXISTS emails"); err != nil {
-	// 	return fmt.Errorf("failed to drop existing emails table: %w", err)
-	// }
-
-	// Create fresh table
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS emails (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		email TEXT NOT NULL UNIQUE,
-		status TEXT NOT NULL DEFAULT 'pending',
-		has_info BOOLEAN DEFAULT FALSE,
-		no_info BOOLEAN DEFAULT FALSE,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-	CREATE INDEX IF NOT EXISTS idx_email_status ON emails(status);
-	CREATE INDEX IF NOT EXISTS idx_email_email ON emails(email);
-	CREATE INDEX IF NOT EXISTS idx_email_has_info ON emails(has_info);
-	CREATE INDEX IF NOT EXISTS idx_email_no_info ON emails(no_info);
-	`
-
-	if _, err := es.db.Exec(createTableSQL); err != nil {
-		return fmt.Errorf("failed to create emails table: %w", err)
+	if es.readOnly {
+		err = checkSchemaCurrent(es.db)
+	} else {
+		// Áp dụng schema migrations (embedded SQL)
+		err = runMigrations(es.db)
 	}
-	return nil
+	if err != nil {
+		return err
+	}
+
+	// Results đã mã hoá: cần passphrase, chưa có thì lần gọi sau mở lại
+	if es.cipher, err = loadCipher(es.db, es.dbPath); err != nil {
+		es.db.Close()
+		es.db = nil
+		return err
+	}
+	// Results lưu trước khi email được mã hoá (build cũ)
+	return es.sealStoredEmails()
 }
 
 // CloseDB closes the database connection
@@ -132,36 +186,35 @@ func (es *EmailStorage) ensureDB() error {
 
 // isValidEmail validates email format
 func (es *EmailStorage) isValidEmail(email string) bool {
-	// Basic email regex pattern
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
-	return emailRegex.MatchString(email)
+	return emailPattern.MatchString(email)
+}
+
+// clearEmailsTable empties the emails table for a fresh start. The table is
+// kept: dropping it would leave a schema the recorded migrations no longer
+// match.
+func (es *EmailStorage) clearEmailsTable() error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to initialize database before clearing emails: %w", err)
+	}
+	if _, err := es.db.Exec("DELETE FROM emails"); err != nil {
+		return fmt.Errorf("failed to clear emails table: %w", err)
+	}
+	// Đánh số id lại từ 1 như khi tạo bảng mới
+	if _, err := es.db.Exec("DELETE FROM sqlite_sequence WHERE name = 'emails'"); err != nil {
+		return fmt.Errorf("failed to reset emails ids: %w", err)
+	}
+	return nil
 }
 
 // LoadEmailsFromFile loads emails from file, validates them, and imports to SQLite
-// ALWAYS drops and recreates table for fresh start
+// ALWAYS empties the emails table for a fresh start
 func (es *EmailStorage) LoadEmailsFromFile(filePath string) ([]string, error) {
-	// Initialize database (this will drop existing table)
+	// Initialize database (the emails table is emptied below)
 	if err := es.ensureDB(); err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
-	if err := es.ensureDB(); err != nil {
-		return nil, fmt.Errorf("failed to initialize database before drop: %w", err)
-	}
-	if _, err := es.db.Exec("DROP TABLE IF EXISTS emails"); err != nil {
-		return nil, fmt.Errorf("failed to drop existing emails table: %w", err)
-	}
-	// Sau khi drop, cần tạo lại schema (tương tự InitDB)
-	if _, err := es.db.Exec(`
-        CREATE TABLE IF NOT EXISTS emails (
-            id INTEGER PRIMARY KEY AUTOINCREMENT,
-            email TEXT UNIQUE,
-            status TEXT,
-            has_info BOOLEAN,
-            no_info BOOLEAN,
-            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-        )
-    `); err != nil {
-		return nil, fmt.Errorf("failed to recreate emails table: %w", err)
+	if err := es.clearEmailsTable(); err != nil {
+		return nil, err
 	}
 
 	dir := filepath.Dir(filePath)
@@ -171,12 +224,7 @@ func (es *EmailStorage) LoadEmailsFromFile(filePath string) ([]string, error) {
 
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		fmt.Printf("Emails file not found at %s, creating sample file\n", filePath)
-		sampleContent := `# Target email addresses
-# One email per line
-example@example.com
-test@test.com
-`
-		if err := os.WriteFile(filePath, []byte(sampleContent), 0644); err != nil {
+		if _, err := writeSampleFile(filePath, sampleEmails); err != nil {
 			return nil, fmt.Errorf("failed to create emails file: %w", err)
 		}
 	}
@@ -185,6 +233,7 @@ test@test.com
 	if err != nil {
 		return nil, fmt.Errorf("failed to read emails file: %w", err)
 	}
+	lines, fields := splitInputFields(lines)
 
 	es.dbMutex.Lock()
 	defer es.dbMutex.Unlock()
@@ -246,39 +295,29 @@ test@test.com
 		fmt.Printf("🔄 Removed %d duplicate emails\n", duplicates)
 	}
 
-	// Import unique valid emails to database
+	// Import unique valid emails to database (multi-row INSERT)
 	if len(uniqueEmails) > 0 {
+		start := time.Now()
 		tx, err := es.db.Begin()
 		if err != nil {
 			return nil, fmt.Errorf("failed to begin transaction: %w", err)
 		}
 
-		stmt, err := tx.Prepare("INSERT OR IGNORE INTO emails (email, status) VALUES (?, ?)")
+		inserted, err := bulkInsert(tx, uniqueEmails, models.SourceEmail, consoleImportProgress(es.importProgress))
 		if err != nil {
 			tx.Rollback()
-			return nil, fmt.Errorf("failed to prepare statement: %w", err)
+			return nil, err
 		}
-		defer stmt.Close()
-
-		inserted := 0
-		for _, email := range uniqueEmails {
-			result, err := stmt.Exec(email, StatusPending)
-			if err != nil {
-				fmt.Printf("⚠️ Failed to insert email %s: %v\n", email, err)
-				continue
-			}
-
-			// Check if actually inserted (not ignored due to duplicate)
-			if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
-				inserted++
-			}
+		if err := saveInputFields(tx, fields); err != nil {
+			tx.Rollback()
+			return nil, err
 		}
 
 		if err := tx.Commit(); err != nil {
 			return nil, fmt.Errorf("failed to commit transaction: %w", err)
 		}
 
-		fmt.Printf("✅ Imported %d unique emails to database\n", inserted)
+		fmt.Printf("✅ Imported %d unique emails to database in %s\n", inserted, utils.FormatDuration(time.Since(start)))
 	}
 
 	// Return all pending emails from database
@@ -334,46 +373,103 @@ func (es *EmailStorage) GetPendingEmails() ([]string, error) {
 
 // UpdateEmailStatus updates the status of an email
 func (es *EmailStorage) UpdateEmailStatus(email string, status EmailStatus, hasInfo, noInfo bool) error {
+	return es.UpdateEmailStatusWithEvent(email, status, hasInfo, noInfo, EmailEvent{})
+}
+
+// exportChunkSize là số rows đọc mỗi lần khi export, lock DB được nhả giữa các chunk
+const exportChunkSize = 10000
+
+// ExportPendingEmailsToFile streams pending emails back to file in chunks
+func (es *EmailStorage) ExportPendingEmailsToFile(filePath string) error {
 	if err := es.ensureDB(); err != nil {
 		return fmt.Errorf("failed to ensure database: %w", err)
 	}
 
+	total, err := es.countPendingEmails()
+	if err != nil {
+		return fmt.Errorf("failed to get pending emails: %w", err)
+	}
+
+	file, err := utils.CreateAtomic(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	writer := bufio.NewWriterSize(file, 64*1024)
+
+	// Add header comment
+	fmt.Fprintln(writer, "# Pending emails for LinkedIn crawler")
+	fmt.Fprintf(writer, "# Exported on: %s\n", time.Now().Format("2006-01-02"))
+	fmt.Fprintf(writer, "# Total pending: %d\n", total)
+	fmt.Fprintln(writer)
+
+	// Stream emails theo id, mỗi lần một chunk
+	lastID := 0
+	for {
+		n, nextID, err := es.writePendingChunk(writer, lastID, exportChunkSize)
+		if err != nil {
+			return err
+		}
+		if n < exportChunkSize {
+			break
+		}
+		lastID = nextID
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+	return file.Commit()
+}
+
+// countPendingEmails returns the number of pending rows
+func (es *EmailStorage) countPendingEmails() (int, error) {
 	es.dbMutex.RLock()
 	defer es.dbMutex.RUnlock()
 
 	if es.isDBClosed {
-		return fmt.Errorf("database is closed")
-	}
-
-	_, err := es.db.Exec(
-		"UPDATE emails SET status = ?, has_info = ?, no_info = ?, updated_at = CURRENT_TIMESTAMP WHERE email = ?",
-		status, hasInfo, noInfo, email,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to update email status: %w", err)
+		return 0, fmt.Errorf("database is closed")
 	}
 
-	return nil
+	var count int
+	err := es.db.QueryRow("SELECT COUNT(*) FROM emails WHERE status = ?", StatusPending).Scan(&count)
+	return count, err
 }
 
-// ExportPendingEmailsToFile exports pending emails back to file
-func (es *EmailStorage) ExportPendingEmailsToFile(filePath string) error {
-	pendingEmails, err := es.GetPendingEmails()
-	if err != nil {
-		return fmt.Errorf("failed to get pending emails: %w", err)
+// writePendingChunk writes up to limit pending emails with id > afterID and
+// returns how many were written and the last id seen
+func (es *EmailStorage) writePendingChunk(w *bufio.Writer, afterID, limit int) (int, int, error) {
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return 0, afterID, fmt.Errorf("database is closed")
 	}
 
-	// Add header comment
-	var lines []string
-	lines = append(lines, "# Pending emails for LinkedIn crawler")
-	lines = append(lines, fmt.Sprintf("# Exported on: %s", strings.Split(fmt.Sprintf("%v", time.Now()), " ")[0]))
-	lines = append(lines, fmt.Sprintf("# Total pending: %d", len(pendingEmails)))
-	lines = append(lines, "")
+	rows, err := es.db.Query(
+		"SELECT id, email FROM emails WHERE status = ? AND id > ? ORDER BY id LIMIT ?",
+		StatusPending, afterID, limit,
+	)
+	if err != nil {
+		return 0, afterID, fmt.Errorf("failed to query pending emails: %w", err)
+	}
+	defer rows.Close()
 
-	// Add emails
-	lines = append(lines, pendingEmails...)
+	written := 0
+	lastID := afterID
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&lastID, &email); err != nil {
+			return written, lastID, fmt.Errorf("failed to scan email: %w", err)
+		}
+		if _, err := w.WriteString(email + "\n"); err != nil {
+			return written, lastID, fmt.Errorf("failed to write email: %w", err)
+		}
+		written++
+	}
 
-	return es.fileManager.WriteLines(filePath, lines)
+	return written, lastID, rows.Err()
 }
 
 // GetEmailStats returns statistics about emails
@@ -467,6 +563,56 @@ func (es *EmailStorage) GetEmailsByStatus(status EmailStatus) ([]string, error)
 	return emails, nil
 }
 
+// GetEmailStatuses looks up the given emails in batched IN queries and returns
+// their records keyed by lowercased email. Emails not in the database are
+// missing from the map.
+func (es *EmailStorage) GetEmailStatuses(emails []string) (map[string]EmailRecord, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	records := make(map[string]EmailRecord, len(emails))
+	args := make([]interface{}, 0, bulkInsertChunk)
+
+	for start := 0; start < len(emails); start += bulkInsertChunk {
+		end := start + bulkInsertChunk
+		if end > len(emails) {
+			end = len(emails)
+		}
+
+		args = args[:0]
+		for _, email := range emails[start:end] {
+			args = append(args, strings.ToLower(email))
+		}
+
+		query := "SELECT id, email, status, has_info, no_info FROM emails WHERE email IN (?" +
+			strings.Repeat(", ?", len(args)-1) + ")"
+		rows, err := es.db.Query(query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query email statuses: %w", err)
+		}
+
+		for rows.Next() {
+			var record EmailRecord
+			if err := rows.Scan(&record.ID, &record.Email, &record.Status, &record.HasInfo, &record.NoInfo); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan email status: %w", err)
+			}
+			records[record.Email] = record
+		}
+		rows.Close()
+	}
+
+	return records, nil
+}
+
 // GetDatabaseInfo returns information about the database
 func (es *EmailStorage) GetDatabaseInfo() (map[string]interface{}, error) {
 	if err := es.ensureDB(); err != nil {
@@ -495,13 +641,17 @@ func (es *EmailStorage) GetDatabaseInfo() (map[string]interface{}, error) {
 		info["db_file_size"] = stat.Size()
 	}
 
+	if version, err := schemaVersion(es.db); err == nil {
+		info["schema_version"] = version
+	}
+
 	info["db_path"] = es.dbPath
 	info["is_closed"] = es.isDBClosed
 
 	return info, nil
 }
 
-// ResetDatabase drops and recreates the emails table (for testing/reset purposes)
+// ResetDatabase empties the emails table (for testing/reset purposes)
 func (es *EmailStorage) ResetDatabase() error {
 	es.dbMutex.Lock()
 	defer es.dbMutex.Unlock()
@@ -510,33 +660,14 @@ func (es *EmailStorage) ResetDatabase() error {
 		return fmt.Errorf("database is not initialized or closed")
 	}
 
-	// Drop existing table
-	if _, err := es.db.Exec("DROP TABLE IF EXISTS emails"); err != nil {
-		return fmt.Errorf("failed to drop emails table: %w", err)
+	if _, err := es.db.Exec("DELETE FROM emails"); err != nil {
+		return fmt.Errorf("failed to clear emails table: %w", err)
 	}
-
-	// Create fresh table
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS emails (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		email TEXT NOT NULL UNIQUE,
-		status TEXT NOT NULL DEFAULT 'pending',
-		has_info BOOLEAN DEFAULT FALSE,
-		no_info BOOLEAN DEFAULT FALSE,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-	CREATE INDEX IF NOT EXISTS idx_email_status ON emails(status);
-	CREATE INDEX IF NOT EXISTS idx_email_email ON emails(email);
-	CREATE INDEX IF NOT EXISTS idx_email_has_info ON emails(has_info);
-	CREATE INDEX IF NOT EXISTS idx_email_no_info ON emails(no_info);
-	`
-
-	if _, err := es.db.Exec(createTableSQL); err != nil {
-		return fmt.Errorf("failed to recreate emails table: %w", err)
+	if _, err := es.db.Exec("DELETE FROM sqlite_sequence WHERE name = 'emails'"); err != nil {
+		return fmt.Errorf("failed to reset emails ids: %w", err)
 	}
 
-	fmt.Println("✅ Database reset: Emails table dropped and recreated")
+	fmt.Println("✅ Database reset: Emails table cleared")
 	return nil
 }
 
@@ -559,3 +690,44 @@ func (es *EmailStorage) GetDB() *sql.DB {
 
 	return es.db
 }
+
+// GetDomainStats aggregates email-mode rows by domain, ordered by hits then total
+func (es *EmailStorage) GetDomainStats() ([]models.DomainStat, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	rows, err := es.db.Query(`
+		SELECT LOWER(SUBSTR(email, INSTR(email, '@') + 1)) AS domain,
+		       COUNT(*),
+		       SUM(CASE WHEN status = 'success' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN status = 'failed' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN status = 'pending' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN has_info THEN 1 ELSE 0 END)
+		FROM emails
+		WHERE INSTR(email, '@') > 0 AND COALESCE(source, 'email') = 'email'
+		GROUP BY domain
+		ORDER BY 6 DESC, 2 DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query domain stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.DomainStat
+	for rows.Next() {
+		var d models.DomainStat
+		if err := rows.Scan(&d.Domain, &d.Total, &d.Success, &d.Failed, &d.Pending, &d.Hits); err != nil {
+			return nil, fmt.Errorf("failed to scan domain stats: %w", err)
+		}
+		stats = append(stats, d)
+	}
+
+	return stats, nil
+}
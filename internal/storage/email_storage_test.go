@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestStorage opens a migrated database in a temp directory
+func newTestStorage(t *testing.T) *EmailStorage {
+	t.Helper()
+	es := &EmailStorage{
+		fileManager: NewFileManager(),
+		dbPath:      filepath.Join(t.TempDir(), "emails.db"),
+	}
+	if err := es.InitDB(); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { es.CloseDB() })
+	return es
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadEmailsFromFileTwice(t *testing.T) {
+	es := newTestStorage(t)
+
+	first := writeTestFile(t, "first.txt", "a@example.com\nb@example.com\n")
+	if emails, err := es.LoadEmailsFromFile(first); err != nil || len(emails) != 2 {
+		t.Fatalf("first load: %v, %d emails", err, len(emails))
+	}
+
+	// Lần load thứ hai thay hàng chờ, không được làm hỏng schema
+	second := writeTestFile(t, "second.txt", "c@example.com\n")
+	emails, err := es.LoadEmailsFromFile(second)
+	if err != nil {
+		t.Fatalf("second load: %v", err)
+	}
+	if len(emails) != 1 || emails[0] != "c@example.com" {
+		t.Fatalf("second load returned %v, want [c@example.com]", emails)
+	}
+
+	pending, err := es.GetPendingEmails()
+	if err != nil {
+		t.Fatalf("GetPendingEmails: %v", err)
+	}
+	if len(pending) != 1 || pending[0] != "c@example.com" {
+		t.Fatalf("pending = %v, want [c@example.com]", pending)
+	}
+
+	// Mở lại database: migrations vẫn khớp với schema
+	es.CloseDB()
+	reopened := &EmailStorage{fileManager: NewFileManager(), dbPath: es.dbPath}
+	if err := reopened.InitDB(); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	reopened.CloseDB()
+}
@@ -0,0 +1,499 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Result fields encrypted while encryption is on; status, tags and campaign
+// stay readable because queries match on them. The email is kept in
+// encrypted_email and replaced by a keyed hash (see resultKey).
+var encryptedResultColumns = []string{"name", "linkedin_url", "location", "connections", "headline", "company", "notes"}
+
+const (
+	encryptedPrefix     = "enc1:"            // Giá trị đã mã hoá: enc1:<base64(nonce|ciphertext)>
+	hashedPrefix        = "hash1:"           // Email đã hash: hash1:<hex(hmac)>
+	hashKeyLabel        = "result email"     // Khoá HMAC tách từ khoá AES theo nhãn này
+	encryptionCheck     = "linkedin-crawler" // Giá trị mẫu để kiểm tra passphrase
+	encryptionIteration = 210000             // PBKDF2-HMAC-SHA256
+)
+
+var (
+	// ErrDatabaseLocked is returned when the database is encrypted and no
+	// passphrase was set with SetPassphrase
+	ErrDatabaseLocked = errors.New("the results in this database are encrypted: a passphrase is needed")
+	// ErrWrongPassphrase is returned when the passphrase does not unlock the database
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+)
+
+var (
+	passphraseMutex  sync.Mutex
+	passphrase       string
+	passphrasePrompt func(dbPath string) (string, error)
+	derivedCiphers   = make(map[string]*fieldCipher) // Theo salt: PBKDF2 chậm, chỉ tính một lần
+)
+
+// SetPassphrase sets the passphrase that unlocks encrypted databases opened
+// from now on
+func SetPassphrase(value string) {
+	passphraseMutex.Lock()
+	defer passphraseMutex.Unlock()
+	if value != passphrase {
+		passphrase = value
+		derivedCiphers = make(map[string]*fieldCipher)
+	}
+}
+
+// PassphraseSet reports whether a passphrase was set or entered
+func PassphraseSet() bool {
+	passphraseMutex.Lock()
+	defer passphraseMutex.Unlock()
+	return passphrase != ""
+}
+
+// SetPassphrasePrompt sets how the passphrase is asked for when an encrypted
+// database is opened before SetPassphrase was called (CLI: on the terminal)
+func SetPassphrasePrompt(prompt func(dbPath string) (string, error)) {
+	passphraseMutex.Lock()
+	defer passphraseMutex.Unlock()
+	passphrasePrompt = prompt
+}
+
+// fieldCipher encrypts single column values with AES-256-GCM and hashes
+// result emails with HMAC-SHA256
+type fieldCipher struct {
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+// newFieldCipher derives the key of passphrase and salt
+func newFieldCipher(passphrase string, salt []byte, iterations int) (*fieldCipher, error) {
+	key := pbkdf2.Key([]byte(passphrase), salt, iterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(hashKeyLabel))
+	return &fieldCipher{aead: aead, hashKey: mac.Sum(nil)}, nil
+}
+
+// resultKey returns what the email column of results holds for email: the
+// trimmed address, or while encryption is on a keyed hash of it (case
+// insensitive) so rows can still be looked up by address
+func (c *fieldCipher) resultKey(email string) string {
+	email = strings.TrimSpace(email)
+	if c == nil {
+		return email
+	}
+	mac := hmac.New(sha256.New, c.hashKey)
+	mac.Write([]byte(strings.ToLower(email)))
+	return hashedPrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sealEmail returns the email and encrypted_email values of a result for
+// email; encrypted_email is NULL while encryption is off
+func (c *fieldCipher) sealEmail(email string) (key string, encrypted interface{}, err error) {
+	if c == nil {
+		return c.resultKey(email), nil, nil
+	}
+	value, err := c.encrypt(strings.TrimSpace(email))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encrypt result: %w", err)
+	}
+	return c.resultKey(email), value, nil
+}
+
+// sealResultEmails hashes and encrypts the emails of the results stored
+// before they were (results written by older builds, restored archives).
+// A result whose address is already stored encrypted (also in another case)
+// is dropped: the stored one is kept.
+func sealResultEmails(tx *sql.Tx, c *fieldCipher) error {
+	if c == nil {
+		return nil
+	}
+	rows, err := tx.Query("SELECT email FROM results WHERE encrypted_email IS NULL AND email NOT LIKE ?", hashedPrefix+"%")
+	if err != nil {
+		return fmt.Errorf("failed to query results: %w", err)
+	}
+	var emails []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan result: %w", err)
+		}
+		emails = append(emails, email)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query results: %w", err)
+	}
+
+	for _, email := range emails {
+		key, encrypted, err := c.sealEmail(email)
+		if err != nil {
+			return err
+		}
+		res, err := tx.Exec("UPDATE OR IGNORE results SET email = ?, encrypted_email = ? WHERE email = ?", key, encrypted, email)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt result %s: %w", email, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			continue
+		}
+		if _, err := tx.Exec("DELETE FROM results WHERE email = ?", email); err != nil {
+			return fmt.Errorf("failed to encrypt result %s: %w", email, err)
+		}
+	}
+	return nil
+}
+
+// sealStoredEmails runs sealResultEmails on the database with its cipher
+func (es *EmailStorage) sealStoredEmails() error {
+	if es.cipher == nil || es.readOnly {
+		return nil
+	}
+	tx, err := es.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := sealResultEmails(tx, es.cipher); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// encrypt returns value encrypted with a random nonce; "" stays ""
+func (c *fieldCipher) encrypt(value string) (string, error) {
+	if c == nil || value == "" || strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to create nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt returns the plain value of an encrypted one; values written before
+// encryption was turned on are returned as they are
+func (c *fieldCipher) decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	if c == nil {
+		return "", ErrDatabaseLocked
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("corrupt encrypted value")
+	}
+	size := c.aead.NonceSize()
+	plain, err := c.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+	return string(plain), nil
+}
+
+// encryptionSettings reads the encryption row of db; found is false when
+// encryption is off or the schema predates it (read-only databases)
+func encryptionSettings(db *sql.DB) (salt []byte, iterations int, check string, found bool, err error) {
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'encryption'").Scan(&tables); err != nil {
+		return nil, 0, "", false, fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	if tables == 0 {
+		return nil, 0, "", false, nil
+	}
+	err = db.QueryRow("SELECT salt, iterations, check_value FROM encryption WHERE id = 1").Scan(&salt, &iterations, &check)
+	if err == sql.ErrNoRows {
+		return nil, 0, "", false, nil
+	}
+	if err != nil {
+		return nil, 0, "", false, fmt.Errorf("failed to read encryption settings: %w", err)
+	}
+	return salt, iterations, check, true, nil
+}
+
+// unlockCipher derives the cipher of value and checks it against check
+func unlockCipher(value string, salt []byte, iterations int, check string) (*fieldCipher, error) {
+	c, err := newFieldCipher(value, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	if plain, err := c.decrypt(check); err != nil || plain != encryptionCheck {
+		return nil, ErrWrongPassphrase
+	}
+	return c, nil
+}
+
+// loadCipher returns the cipher of db (opened from dbPath), nil when
+// encryption is off. Without a passphrase the prompt is asked; the one that
+// unlocks is kept for the databases opened later.
+func loadCipher(db *sql.DB, dbPath string) (*fieldCipher, error) {
+	salt, iterations, check, found, err := encryptionSettings(db)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	passphraseMutex.Lock()
+	defer passphraseMutex.Unlock()
+	if c, ok := derivedCiphers[string(salt)]; ok {
+		return c, nil
+	}
+	value := passphrase
+	if value == "" {
+		if passphrasePrompt == nil {
+			return nil, ErrDatabaseLocked
+		}
+		if value, err = passphrasePrompt(dbPath); err != nil {
+			return nil, err
+		}
+	}
+	c, err := unlockCipher(value, salt, iterations, check)
+	if err != nil {
+		return nil, err
+	}
+	passphrase = value
+	derivedCiphers[string(salt)] = c
+	return c, nil
+}
+
+// IsEncrypted reports whether the results of the database at dbPath are
+// encrypted; a missing database is not
+func IsEncrypted(dbPath string) (bool, error) {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return false, nil
+	}
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro&_busy_timeout=5000")
+	if err != nil {
+		return false, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	_, _, _, found, err := encryptionSettings(db)
+	return found, err
+}
+
+// CheckPassphrase reports whether value unlocks the database at dbPath
+func CheckPassphrase(dbPath, value string) error {
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro&_busy_timeout=5000")
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	salt, iterations, check, found, err := encryptionSettings(db)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("the database is not encrypted")
+	}
+	_, err = unlockCipher(value, salt, iterations, check)
+	return err
+}
+
+// Encrypted reports whether encryption is on for this database
+func (es *EmailStorage) Encrypted() bool {
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+	return es.cipher != nil
+}
+
+// EnableEncryption turns encryption on with passphrase value, encrypts the
+// stored results and vacuums so their plain copies do not stay in free pages.
+// It returns how many results were encrypted.
+func (es *EmailStorage) EnableEncryption(value string) (int, error) {
+	if len(value) < 8 {
+		return 0, fmt.Errorf("the passphrase must have at least 8 characters")
+	}
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+	if es.Encrypted() {
+		return 0, fmt.Errorf("encryption is already on: change the passphrase instead")
+	}
+	return es.rekey(value)
+}
+
+// ChangePassphrase encrypts the stored results again with a key derived from
+// a new passphrase
+func (es *EmailStorage) ChangePassphrase(value string) (int, error) {
+	if len(value) < 8 {
+		return 0, fmt.Errorf("the passphrase must have at least 8 characters")
+	}
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+	if !es.Encrypted() {
+		return 0, fmt.Errorf("encryption is off")
+	}
+	return es.rekey(value)
+}
+
+// DisableEncryption decrypts the stored results and turns encryption off
+func (es *EmailStorage) DisableEncryption() (int, error) {
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+	if !es.Encrypted() {
+		return 0, fmt.Errorf("encryption is off")
+	}
+	return es.rekey("")
+}
+
+// rekey rewrites the encrypted result columns and emails with the key of
+// value ("" = plain text), drops the cached responses and records the new
+// settings
+func (es *EmailStorage) rekey(value string) (int, error) {
+	var next *fieldCipher
+	var salt []byte
+	var check string
+	if value != "" {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return 0, fmt.Errorf("failed to create salt: %w", err)
+		}
+		var err error
+		if next, err = newFieldCipher(value, salt, encryptionIteration); err != nil {
+			return 0, err
+		}
+		if check, err = next.encrypt(encryptionCheck); err != nil {
+			return 0, err
+		}
+	}
+
+	es.dbMutex.Lock()
+	count, err := es.rekeyLocked(next, salt, check)
+	es.dbMutex.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	// Database khác (campaign khác) mở sau đó dùng passphrase mới
+	SetPassphrase(value)
+	if next != nil {
+		passphraseMutex.Lock()
+		derivedCiphers[string(salt)] = next
+		passphraseMutex.Unlock()
+	}
+	if _, _, err := es.VacuumDB(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// rekeyLocked runs the rewrite of rekey; dbMutex must be held
+func (es *EmailStorage) rekeyLocked(next *fieldCipher, salt []byte, check string) (int, error) {
+	if es.isDBClosed {
+		return 0, fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	selected := []string{"email", "COALESCE(encrypted_email, '')"}
+	for _, column := range encryptedResultColumns {
+		selected = append(selected, "COALESCE("+column+", '')")
+	}
+	rows, err := tx.Query("SELECT " + strings.Join(selected, ", ") + " FROM results")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query results: %w", err)
+	}
+	type row struct {
+		email, encryptedEmail string
+		values                []string
+	}
+	var results []row
+	for rows.Next() {
+		r := row{values: make([]string, len(encryptedResultColumns))}
+		dest := []any{&r.email, &r.encryptedEmail}
+		for i := range r.values {
+			dest = append(dest, &r.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan result: %w", err)
+		}
+		results = append(results, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to query results: %w", err)
+	}
+
+	// Email là khoá chính: đổi khoá thì hash mới có thể trùng một dòng chỉ khác hoa/thường
+	update := "UPDATE OR REPLACE results SET " + strings.Join(encryptedResultColumns, " = ?, ") + " = ?, email = ?, encrypted_email = ? WHERE email = ?"
+	for _, r := range results {
+		email := r.email
+		if r.encryptedEmail != "" {
+			if email, err = es.cipher.decrypt(r.encryptedEmail); err != nil {
+				return 0, fmt.Errorf("failed to decrypt result %s: %w", r.email, err)
+			}
+		}
+		args := make([]any, 0, len(r.values)+3)
+		for _, value := range r.values {
+			plain, err := es.cipher.decrypt(value)
+			if err != nil {
+				return 0, fmt.Errorf("failed to decrypt result %s: %w", email, err)
+			}
+			encrypted, err := next.encrypt(plain)
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, nullString(encrypted))
+		}
+		key, encryptedEmail, err := next.sealEmail(email)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(update, append(args, key, encryptedEmail, r.email)...); err != nil {
+			return 0, fmt.Errorf("failed to update result %s: %w", email, err)
+		}
+	}
+
+	// Response cache giữ body JSON của profile: mã hoá theo khoá cũ, chỉ là cache nên xoá luôn
+	if _, err := tx.Exec("DELETE FROM response_cache"); err != nil {
+		return 0, fmt.Errorf("failed to clear response cache: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM encryption"); err != nil {
+		return 0, fmt.Errorf("failed to update encryption settings: %w", err)
+	}
+	if next != nil {
+		if _, err := tx.Exec("INSERT INTO encryption (id, salt, iterations, check_value) VALUES (1, ?, ?, ?)",
+			salt, encryptionIteration, check); err != nil {
+			return 0, fmt.Errorf("failed to update encryption settings: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit encryption: %w", err)
+	}
+	es.cipher = next
+	return len(results), nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"linkedin-crawler/internal/models"
+)
+
+// newEncryptedTestStorage opens a test database with encryption on
+func newEncryptedTestStorage(t *testing.T) *EmailStorage {
+	t.Helper()
+	es := newTestStorage(t)
+	t.Cleanup(func() { SetPassphrase("") })
+	if _, err := es.EnableEncryption("correct horse battery"); err != nil {
+		t.Fatalf("EnableEncryption: %v", err)
+	}
+	return es
+}
+
+func TestEncryptionHidesResultEmails(t *testing.T) {
+	es := newTestStorage(t)
+	t.Cleanup(func() { SetPassphrase("") })
+
+	profile := models.ProfileData{User: "Ada Lovelace", LinkedInURL: "https://www.linkedin.com/in/ada"}
+	if err := es.SaveResult("ada@example.com", profile, ""); err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+	if _, err := es.EnableEncryption("correct horse battery"); err != nil {
+		t.Fatalf("EnableEncryption: %v", err)
+	}
+	if err := es.SaveResult("Bob@Example.com", profile, ""); err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+
+	rows, err := es.db.Query("SELECT email, COALESCE(encrypted_email, '') FROM results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var email, encrypted string
+		if err := rows.Scan(&email, &encrypted); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(email+encrypted, "example.com") {
+			t.Errorf("result stored in plain text: %q, %q", email, encrypted)
+		}
+	}
+	rows.Close()
+
+	// Tra cứu theo địa chỉ vẫn chạy, không phân biệt hoa/thường
+	if err := es.UpdateResultNotes([]string{"ADA@example.com"}, "call back"); err != nil {
+		t.Fatalf("UpdateResultNotes: %v", err)
+	}
+	if err := es.SaveResult("bob@example.com", profile, ""); err != nil {
+		t.Fatalf("SaveResult again: %v", err)
+	}
+	results, err := es.GetResults()
+	if err != nil {
+		t.Fatalf("GetResults: %v", err)
+	}
+	notes := make(map[string]string)
+	for _, r := range results {
+		notes[r.Email] = r.Notes
+	}
+	if len(results) != 2 || notes["ada@example.com"] != "call back" {
+		t.Fatalf("results = %+v, want ada@example.com (noted) and bob@example.com", results)
+	}
+
+	if _, err := es.DisableEncryption(); err != nil {
+		t.Fatalf("DisableEncryption: %v", err)
+	}
+	var plain int
+	if err := es.db.QueryRow("SELECT COUNT(*) FROM results WHERE email IN ('ada@example.com', 'bob@example.com') AND encrypted_email IS NULL").Scan(&plain); err != nil {
+		t.Fatal(err)
+	}
+	if plain != 2 {
+		t.Fatalf("%d plain results after disabling encryption, want 2", plain)
+	}
+}
+
+func TestPurgeEmailsEncryptedResult(t *testing.T) {
+	es := newEncryptedTestStorage(t)
+	if err := es.SaveResult("ada@example.com", models.ProfileData{User: "Ada Lovelace"}, ""); err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+
+	counts, err := es.PurgeEmails([]string{" Ada@Example.com "})
+	if err != nil {
+		t.Fatalf("PurgeEmails: %v", err)
+	}
+	if counts["results"] != 1 {
+		t.Fatalf("purged %v, want 1 result", counts)
+	}
+}
+
+func TestCachedResponseEncrypted(t *testing.T) {
+	es := newEncryptedTestStorage(t)
+	if _, err := es.LoadEmailsFromFile(writeTestFile(t, "emails.txt", "ada@example.com\n")); err != nil {
+		t.Fatalf("LoadEmailsFromFile: %v", err)
+	}
+
+	body := []byte(`{"displayName":"Ada Lovelace"}`)
+	if err := es.SaveCachedResponse(models.CrawlModeEmail, "ada@example.com", true, body); err != nil {
+		t.Fatalf("SaveCachedResponse: %v", err)
+	}
+	var stored string
+	if err := es.db.QueryRow("SELECT body FROM response_cache").Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stored, "Ada") {
+		t.Fatalf("cached body stored in plain text: %s", stored)
+	}
+
+	responses, err := es.GetCachedPendingResponses(models.CrawlModeEmail, time.Hour)
+	if err != nil {
+		t.Fatalf("GetCachedPendingResponses: %v", err)
+	}
+	if len(responses) != 1 || string(responses[0].Body) != string(body) {
+		t.Fatalf("responses = %+v, want the cached body", responses)
+	}
+
+	// Đổi passphrase: cache mã hoá theo khoá cũ bị xoá
+	if _, err := es.ChangePassphrase("another long passphrase"); err != nil {
+		t.Fatalf("ChangePassphrase: %v", err)
+	}
+	if responses, err = es.GetCachedPendingResponses(models.CrawlModeEmail, time.Hour); err != nil || len(responses) != 0 {
+		t.Fatalf("responses after ChangePassphrase = %v, %v; want none", responses, err)
+	}
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"linkedin-crawler/internal/models"
+)
+
+// GetErrorClassStats counts failed emails by error class (models.EmailError*).
+// Failures recorded before error classes existed count as unknown.
+func (es *EmailStorage) GetErrorClassStats() (map[string]int, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	rows, err := es.db.Query(
+		"SELECT COALESCE(error_code, ?), COUNT(*) FROM emails WHERE status = ? GROUP BY 1",
+		models.EmailErrorUnknown, StatusFailed,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get error class stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]int)
+	for rows.Next() {
+		var class string
+		var count int
+		if err := rows.Scan(&class, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan error class stats: %w", err)
+		}
+		stats[class] = count
+	}
+	return stats, rows.Err()
+}
+
+// RequeueFailedByErrorClass sets failed emails of the given error classes
+// back to pending and returns how many were re-queued
+func (es *EmailStorage) RequeueFailedByErrorClass(classes []string) (int, error) {
+	if len(classes) == 0 {
+		return 0, nil
+	}
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return 0, fmt.Errorf("database is closed")
+	}
+
+	args := []interface{}{StatusPending, models.EmailErrorUnknown}
+	for _, class := range classes {
+		args = append(args, class)
+	}
+	args = append(args, StatusFailed)
+
+	result, err := es.db.Exec(
+		"UPDATE emails SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE COALESCE(error_code, ?) IN (?"+
+			strings.Repeat(", ?", len(classes)-1)+") AND status = ?",
+		args...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to re-queue emails: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
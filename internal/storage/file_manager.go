@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"linkedin-crawler/internal/utils"
 )
 
 // FileManager handles thread-safe file operations
@@ -18,25 +20,18 @@ func NewFileManager() *FileManager {
 	return &FileManager{}
 }
 
-// WriteLines writes lines to a file in a thread-safe manner
+// WriteLines atomically replaces a file with lines in a thread-safe manner
 func (fm *FileManager) WriteLines(filePath string, lines []string) error {
 	fm.mutex.Lock()
 	defer fm.mutex.Unlock()
 
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	file, err := os.Create(filePath)
+	file, err := utils.CreateAtomic(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", filePath, err)
+		return err
 	}
-	defer file.Close()
+	defer file.Abort()
 
 	writer := bufio.NewWriter(file)
-	defer writer.Flush()
-
 	for i, line := range lines {
 		if i > 0 {
 			writer.WriteString("\n")
@@ -44,7 +39,18 @@ func (fm *FileManager) WriteLines(filePath string, lines []string) error {
 		writer.WriteString(line)
 	}
 
-	return nil
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+	return file.Commit()
+}
+
+// WriteFileAtomic atomically replaces a file with data in a thread-safe manner
+func (fm *FileManager) WriteFileAtomic(filePath string, data []byte) error {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+
+	return utils.WriteFileAtomic(filePath, data)
 }
 
 // ReadLines reads lines from a file
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImportCheck splits an import list by what the database already knows
+type ImportCheck struct {
+	New       []string // Chưa có trong DB
+	Pending   []string // Đã có trong DB, đang chờ xử lý
+	Processed []string // Đã xử lý (success hoặc failed)
+}
+
+// CheckImportAgainstDB classifies emails as new, already pending or already
+// processed without modifying the database. Emails are compared lowercased,
+// matching how LoadEmailsFromFile stores them.
+func (es *EmailStorage) CheckImportAgainstDB(emails []string) (*ImportCheck, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	statuses := make(map[string]string, len(emails))
+	args := make([]interface{}, 0, bulkInsertChunk)
+
+	for start := 0; start < len(emails); start += bulkInsertChunk {
+		end := start + bulkInsertChunk
+		if end > len(emails) {
+			end = len(emails)
+		}
+
+		args = args[:0]
+		for _, email := range emails[start:end] {
+			args = append(args, strings.ToLower(email))
+		}
+
+		query := "SELECT email, status FROM emails WHERE email IN (?" +
+			strings.Repeat(", ?", len(args)-1) + ")"
+		rows, err := es.db.Query(query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing emails: %w", err)
+		}
+
+		for rows.Next() {
+			var email, status string
+			if err := rows.Scan(&email, &status); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan existing email: %w", err)
+			}
+			statuses[email] = status
+		}
+		rows.Close()
+	}
+
+	check := &ImportCheck{}
+	for _, email := range emails {
+		switch EmailStatus(statuses[strings.ToLower(email)]) {
+		case "":
+			check.New = append(check.New, email)
+		case StatusPending:
+			check.Pending = append(check.Pending, email)
+		default:
+			check.Processed = append(check.Processed, email)
+		}
+	}
+
+	return check, nil
+}
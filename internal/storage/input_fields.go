@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// isInputHeader reports whether the columns of the first input row are a
+// header: a first column that is not a valid key
+func isInputHeader(columns []string) bool {
+	if len(columns) < 2 {
+		return false
+	}
+	_, err := parseEmailRow(columns[0])
+	return err != nil
+}
+
+// splitInputFields separates the extra columns of an input file whose first
+// row is a header. It returns the rows without the header, cut down to their
+// key column, and the non-empty extra columns of each row by parsed key.
+// Files without a header are returned unchanged with no fields.
+func splitInputFields(lines []string) ([]string, map[string]map[string]string) {
+	headerAt := -1
+	var header []string
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if columns := strings.Split(line, ","); isInputHeader(columns) {
+			headerAt, header = i, columns
+		}
+		break
+	}
+	if headerAt < 0 {
+		return lines, nil
+	}
+
+	rows := make([]string, 0, len(lines)-1)
+	fields := make(map[string]map[string]string)
+	for i, line := range lines {
+		if i == headerAt {
+			continue
+		}
+		columns := strings.Split(strings.TrimSpace(line), ",")
+		if len(columns) < 2 {
+			rows = append(rows, line)
+			continue
+		}
+		row := columns[0]
+		rows = append(rows, row)
+
+		key, err := parseEmailRow(strings.TrimSpace(row))
+		if err != nil {
+			continue
+		}
+		for j := 1; j < len(columns) && j < len(header); j++ {
+			name := strings.TrimSpace(header[j])
+			value := strings.TrimSpace(columns[j])
+			if name == "" || value == "" {
+				continue
+			}
+			if fields[key] == nil {
+				fields[key] = make(map[string]string)
+			}
+			fields[key][name] = value
+		}
+	}
+	return rows, fields
+}
+
+// saveInputFields stores the custom fields of input rows, replacing the
+// fields a key got from an earlier import
+func saveInputFields(tx *sql.Tx, fields map[string]map[string]string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO input_fields (key, fields) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET fields = excluded.fields, updated_at = CURRENT_TIMESTAMP`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare input fields insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for key, values := range fields {
+		data, err := json.Marshal(values)
+		if err != nil {
+			return fmt.Errorf("failed to encode input fields of %s: %w", key, err)
+		}
+		if _, err := stmt.Exec(key, string(data)); err != nil {
+			return fmt.Errorf("failed to save input fields of %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// GetInputFields returns the custom fields imported for an input key (nil
+// when it has none)
+func (es *EmailStorage) GetInputFields(key string) (map[string]string, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	var data string
+	err := es.db.QueryRow("SELECT fields FROM input_fields WHERE key = ?", strings.ToLower(strings.TrimSpace(key))).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get input fields: %w", err)
+	}
+
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse input fields of %s: %w", key, err)
+	}
+	return fields, nil
+}
+
+// GetAllInputFields returns the custom fields of every input key, for exports
+func (es *EmailStorage) GetAllInputFields() (map[string]map[string]string, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	rows, err := es.db.Query("SELECT key, fields FROM input_fields")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get input fields: %w", err)
+	}
+	defer rows.Close()
+
+	all := make(map[string]map[string]string)
+	for rows.Next() {
+		var key, data string
+		if err := rows.Scan(&key, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan input fields: %w", err)
+		}
+		var fields map[string]string
+		if err := json.Unmarshal([]byte(data), &fields); err != nil {
+			continue // Dòng hỏng không chặn cả export
+		}
+		all[key] = fields
+	}
+	return all, rows.Err()
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"linkedin-crawler/internal/utils"
+)
+
+const (
+	// instanceLockFile nằm cạnh emails.db
+	instanceLockFile = "crawler.lock"
+	// lockHeartbeat là chu kỳ cập nhật lock file của instance đang chạy
+	lockHeartbeat = 10 * time.Second
+	// lockStaleAfter: lock không được cập nhật quá thời gian này coi như đã chết
+	lockStaleAfter = 3 * lockHeartbeat
+)
+
+// ErrInstanceRunning is returned when another live instance holds the lock
+var ErrInstanceRunning = errors.New("another instance is running")
+
+// InstanceInfo describes the process holding the instance lock
+type InstanceInfo struct {
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	App       string    `json:"app"`
+	StartedAt time.Time `json:"started_at"`
+	Heartbeat time.Time `json:"heartbeat"`
+}
+
+// String formats the holder for messages
+func (i InstanceInfo) String() string {
+	return fmt.Sprintf("%s (pid %d on %s, started %s)", i.App, i.PID, i.Host, i.StartedAt.Format("2006-01-02 15:04:05"))
+}
+
+// InstanceLock guards tokens.txt/emails.db against concurrent instances
+type InstanceLock struct {
+	path string
+	info InstanceInfo
+	stop chan struct{}
+	once sync.Once
+}
+
+// InstanceLockPath returns the lock file path for a database path
+func InstanceLockPath(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), instanceLockFile)
+}
+
+// AcquireInstanceLock takes the lock at path for app. If a live instance holds
+// it, ErrInstanceRunning is returned together with the holder; force takes over.
+func AcquireInstanceLock(path, app string, force bool) (*InstanceLock, *InstanceInfo, error) {
+	if holder, err := readInstanceInfo(path); err == nil && !force {
+		if time.Since(holder.Heartbeat) < lockStaleAfter && !isSelf(holder) {
+			return nil, holder, ErrInstanceRunning
+		}
+	}
+
+	host, _ := os.Hostname()
+	now := time.Now()
+	lock := &InstanceLock{
+		path: path,
+		info: InstanceInfo{
+			PID:       os.Getpid(),
+			Host:      host,
+			App:       app,
+			StartedAt: now,
+			Heartbeat: now,
+		},
+		stop: make(chan struct{}),
+	}
+
+	if err := lock.write(); err != nil {
+		return nil, nil, err
+	}
+
+	go lock.heartbeat()
+	return lock, nil, nil
+}
+
+// Release stops the heartbeat and removes the lock if still owned
+func (l *InstanceLock) Release() {
+	l.once.Do(func() {
+		close(l.stop)
+		if l.owned() {
+			os.Remove(l.path)
+		}
+	})
+}
+
+// heartbeat refreshes the lock until released or taken over
+func (l *InstanceLock) heartbeat() {
+	ticker := time.NewTicker(lockHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !l.owned() {
+				fmt.Printf("⚠️ Instance lock đã bị instance khác chiếm (%s)\n", l.path)
+				return
+			}
+			l.info.Heartbeat = time.Now()
+			if err := l.write(); err != nil {
+				fmt.Printf("⚠️ Không thể cập nhật instance lock: %v\n", err)
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// owned reports whether the lock file still belongs to this process
+func (l *InstanceLock) owned() bool {
+	holder, err := readInstanceInfo(l.path)
+	if err != nil {
+		return false
+	}
+	return holder.PID == l.info.PID && holder.Host == l.info.Host && holder.StartedAt.Equal(l.info.StartedAt)
+}
+
+// write stores the lock info atomically
+func (l *InstanceLock) write() error {
+	data, err := json.Marshal(l.info)
+	if err != nil {
+		return fmt.Errorf("failed to encode instance lock: %w", err)
+	}
+	if err := utils.WriteFileAtomic(l.path, data); err != nil {
+		return fmt.Errorf("failed to write instance lock: %w", err)
+	}
+	return nil
+}
+
+// readInstanceInfo reads the current lock holder
+func readInstanceInfo(path string) (*InstanceInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var info InstanceInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("invalid instance lock: %w", err)
+	}
+	return &info, nil
+}
+
+// isSelf reports whether info describes the current process
+func isSelf(info *InstanceInfo) bool {
+	host, _ := os.Hostname()
+	return info.PID == os.Getpid() && info.Host == host
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"fmt"
+
+	"linkedin-crawler/internal/models"
+)
+
+// YieldHistory summarizes how many tokens accounts produced and how many
+// emails those tokens completed in earlier runs
+type YieldHistory struct {
+	LoginAttempts   int // Số lần đăng nhập account
+	TokensObtained  int // Số lần đăng nhập lấy được token
+	TokensUsed      int // Số token (theo fingerprint) đã hoàn thành ít nhất một email
+	EmailsCompleted int // Số email chuyển sang success có gắn token
+}
+
+// TokensPerAccount returns the average tokens obtained per account login
+func (y YieldHistory) TokensPerAccount() float64 {
+	if y.LoginAttempts == 0 {
+		return 0
+	}
+	return float64(y.TokensObtained) / float64(y.LoginAttempts)
+}
+
+// EmailsPerToken returns the average emails completed per token
+func (y YieldHistory) EmailsPerToken() float64 {
+	if y.TokensUsed == 0 {
+		return 0
+	}
+	return float64(y.EmailsCompleted) / float64(y.TokensUsed)
+}
+
+// RecordLoginAttempt stores the outcome of one account login made by run
+// runID ("" = outside a crawler run)
+func (es *EmailStorage) RecordLoginAttempt(runID, account string, success bool, reason, tokenHash string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if _, err := es.db.Exec(
+		"INSERT INTO login_attempts (run_id, account, success, reason, token_hash) VALUES (?, ?, ?, ?, ?)",
+		nullString(runID), account, success, nullString(reason), nullString(tokenHash),
+	); err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	return nil
+}
+
+// GetYieldHistory aggregates login_attempts and email_events
+func (es *EmailStorage) GetYieldHistory() (YieldHistory, error) {
+	var history YieldHistory
+	if err := es.ensureDB(); err != nil {
+		return history, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if err := es.db.QueryRow(
+		"SELECT COUNT(*), COALESCE(SUM(success), 0) FROM login_attempts",
+	).Scan(&history.LoginAttempts, &history.TokensObtained); err != nil {
+		return history, fmt.Errorf("failed to read login attempts: %w", err)
+	}
+
+	if err := es.db.QueryRow(
+		"SELECT COUNT(DISTINCT token_hash), COUNT(*) FROM email_events WHERE new_status = ? AND token_hash IS NOT NULL",
+		string(StatusSuccess),
+	).Scan(&history.TokensUsed, &history.EmailsCompleted); err != nil {
+		return history, fmt.Errorf("failed to read email events: %w", err)
+	}
+
+	return history, nil
+}
+
+// GetAccountLedger lists every account login grouped by account and run,
+// newest first
+func (es *EmailStorage) GetAccountLedger() ([]models.AccountLedgerEntry, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	rows, err := es.db.Query(`
+		SELECT la.account, COALESCE(la.run_id, ''), COUNT(*), COALESCE(SUM(la.success), 0),
+			(SELECT COUNT(*) FROM email_events e
+				WHERE e.new_status = ? AND e.token_hash IN (
+					SELECT l.token_hash FROM login_attempts l
+					WHERE l.account = la.account AND COALESCE(l.run_id, '') = COALESCE(la.run_id, '') AND l.token_hash IS NOT NULL)),
+			MIN(la.created_at), MAX(la.created_at), COALESCE(a.status, ''),
+			(SELECT COUNT(*) FROM token_invalidations ti
+				WHERE ti.cause = ? AND ti.account = la.account AND COALESCE(ti.run_id, '') = COALESCE(la.run_id, '')),
+			(SELECT COUNT(*) FROM token_invalidations ti
+				WHERE ti.cause = ? AND ti.account = la.account AND COALESCE(ti.run_id, '') = COALESCE(la.run_id, ''))
+		FROM login_attempts la
+		LEFT JOIN accounts a ON a.email = LOWER(la.account)
+		GROUP BY la.account, la.run_id
+		ORDER BY MAX(la.created_at) DESC, la.account`,
+		string(StatusSuccess), models.TokenInvalidExpired, models.TokenInvalidBanned,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account ledger: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.AccountLedgerEntry
+	for rows.Next() {
+		var entry models.AccountLedgerEntry
+		var first, last string
+		if err := rows.Scan(&entry.Account, &entry.RunID, &entry.Logins, &entry.Tokens, &entry.Emails,
+			&first, &last, &entry.FinalStatus, &entry.TokensExpired, &entry.TokensBanned); err != nil {
+			return nil, fmt.Errorf("failed to scan account ledger: %w", err)
+		}
+		entry.FirstLogin = parseSQLiteTime(first)
+		entry.LastLogin = parseSQLiteTime(last)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
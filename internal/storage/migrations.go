@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is one versioned schema change loaded from migrations/NNNN_name.sql
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// loadMigrations returns the embedded migrations sorted by version
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		prefix, label, found := strings.Cut(name, "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: label, SQL: string(content)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// runMigrations applies every migration newer than the recorded schema version
+func runMigrations(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	var emailsTables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'emails'").Scan(&emailsTables); err != nil {
+		return fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	switch {
+	case emailsTables == 0 && current > 0:
+		// Bảng emails bị drop bên ngoài: không xoá lịch sử migrations, để người dùng tự xử lý
+		return fmt.Errorf("database schema is damaged: the emails table is missing although migrations up to %04d were applied; restore a backup or delete the database to start over", current)
+	case emailsTables == 1 && current == 0:
+		if current, err = baselineLegacySchema(db); err != nil {
+			return err
+		}
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// baselineLegacySchema records the migrations a database created before
+// migrations existed already has: the emails table (0001) and, if present,
+// its source column (0002). It returns the recorded schema version.
+func baselineLegacySchema(db *sql.DB) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin schema baseline: %w", err)
+	}
+	defer tx.Rollback()
+
+	columns, err := tableColumns(tx, "emails")
+	if err != nil {
+		return 0, err
+	}
+
+	baseline := []Migration{{Version: 1, Name: "create_emails"}}
+	if columns["source"] {
+		baseline = append(baseline, Migration{Version: 2, Name: "add_source"})
+	}
+	for _, m := range baseline {
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.Version, m.Name); err != nil {
+			return 0, fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit schema baseline: %w", err)
+	}
+
+	version := baseline[len(baseline)-1].Version
+	fmt.Printf("🗄️ Database predates migrations, recorded it at version %04d\n", version)
+	return version, nil
+}
+
+// checkSchemaCurrent fails unless every migration was applied to db, for
+// databases opened read-only
+func checkSchemaCurrent(db *sql.DB) error {
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'").Scan(&tables); err != nil {
+		return fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	if tables == 0 {
+		return fmt.Errorf("not a crawler database")
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	if latest := migrations[len(migrations)-1].Version; current < latest {
+		return fmt.Errorf("database schema is at version %d, this build needs %d: open it once with the crawler to upgrade it", current, latest)
+	}
+	return nil
+}
+
+// schemaVersion returns the highest applied migration version (0 if none)
+func schemaVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// applyMigration runs one migration and records it in a single transaction
+func applyMigration(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(m.SQL) {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.Version, m.Name); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.Version, err)
+	}
+
+	fmt.Printf("🗄️ Applied migration %04d_%s\n", m.Version, m.Name)
+	return nil
+}
+
+// splitStatements splits a migration file into statements, dropping comments
+func splitStatements(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var statements []string
+	for _, stmt := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements
+}
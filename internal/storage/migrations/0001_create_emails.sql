@@ -0,0 +1,14 @@
+-- Base emails table
+CREATE TABLE IF NOT EXISTS emails (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	email TEXT NOT NULL UNIQUE,
+	status TEXT NOT NULL DEFAULT 'pending',
+	has_info BOOLEAN DEFAULT FALSE,
+	no_info BOOLEAN DEFAULT FALSE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_email_status ON emails(status);
+CREATE INDEX IF NOT EXISTS idx_email_email ON emails(email);
+CREATE INDEX IF NOT EXISTS idx_email_has_info ON emails(has_info);
+CREATE INDEX IF NOT EXISTS idx_email_no_info ON emails(no_info);
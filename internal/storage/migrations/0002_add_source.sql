@@ -0,0 +1,2 @@
+-- Input type of each row: email, name or phone
+ALTER TABLE emails ADD COLUMN source TEXT NOT NULL DEFAULT 'email';
@@ -0,0 +1,3 @@
+-- When a row was last queried and the error code of the last failure
+ALTER TABLE emails ADD COLUMN last_checked_at DATETIME;
+ALTER TABLE emails ADD COLUMN error_code TEXT;
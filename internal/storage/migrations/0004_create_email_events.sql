@@ -0,0 +1,12 @@
+-- Audit trail of status transitions per email
+CREATE TABLE IF NOT EXISTS email_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	email TEXT NOT NULL,
+	old_status TEXT,
+	new_status TEXT NOT NULL,
+	http_status INTEGER,
+	token_hash TEXT,
+	error TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_email_events_email ON email_events(email);
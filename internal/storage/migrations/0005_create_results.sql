@@ -0,0 +1,11 @@
+-- LinkedIn profiles found per email; source of truth for the Results tab
+CREATE TABLE IF NOT EXISTS results (
+	email TEXT PRIMARY KEY,
+	name TEXT,
+	linkedin_url TEXT,
+	location TEXT,
+	connections TEXT,
+	source TEXT NOT NULL DEFAULT 'email',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_results_created_at ON results(created_at);
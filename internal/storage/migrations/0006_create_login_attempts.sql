@@ -0,0 +1,10 @@
+-- One row per account login made by the crawler, used to estimate account yield
+CREATE TABLE IF NOT EXISTS login_attempts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	account TEXT NOT NULL,
+	success INTEGER NOT NULL DEFAULT 0,
+	reason TEXT,
+	token_hash TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_created_at ON login_attempts(created_at);
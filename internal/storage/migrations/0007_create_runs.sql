@@ -0,0 +1,20 @@
+-- Metrics of each crawler run, for comparing runs over time
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at DATETIME NOT NULL,
+	finished_at DATETIME NOT NULL,
+	campaign TEXT,
+	crawl_mode TEXT,
+	max_concurrency INTEGER,
+	requests_per_sec REAL,
+	pacing_profile TEXT,
+	processed INTEGER NOT NULL DEFAULT 0,
+	hits INTEGER NOT NULL DEFAULT 0,
+	no_info INTEGER NOT NULL DEFAULT 0,
+	failed INTEGER NOT NULL DEFAULT 0,
+	requests INTEGER NOT NULL DEFAULT 0,
+	rate_limited INTEGER NOT NULL DEFAULT 0,
+	login_attempts INTEGER NOT NULL DEFAULT 0,
+	tokens_obtained INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_runs_started_at ON runs(started_at);
@@ -0,0 +1,2 @@
+-- Run ID of each run, matching its log file logs/run-<run_id>.log
+ALTER TABLE runs ADD COLUMN run_id TEXT;
@@ -0,0 +1,12 @@
+-- Last known status of every account the crawler has logged in with. Rows are
+-- kept after an account leaves the accounts file, so re-imported lists can be
+-- checked against earlier runs; login_attempts holds the full history.
+CREATE TABLE IF NOT EXISTS accounts (
+	email TEXT PRIMARY KEY,
+	status TEXT NOT NULL,
+	detail TEXT,
+	logins INTEGER NOT NULL DEFAULT 0,
+	first_seen_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_accounts_status ON accounts(status);
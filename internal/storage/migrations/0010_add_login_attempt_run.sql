@@ -0,0 +1,3 @@
+-- Run ID of the crawler run that made each login (NULL = extraction from the Accounts tab)
+ALTER TABLE login_attempts ADD COLUMN run_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_login_attempts_account ON login_attempts(account);
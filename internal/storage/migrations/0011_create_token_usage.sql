@@ -0,0 +1,11 @@
+-- Requests sent with each token in each run and whether the token expired
+-- (401/424), used to forecast how many tokens the pending emails will need
+CREATE TABLE IF NOT EXISTS token_usage (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id TEXT,
+	token_hash TEXT NOT NULL,
+	requests INTEGER NOT NULL DEFAULT 0,
+	expired INTEGER NOT NULL DEFAULT 0,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_token_usage_token_hash ON token_usage(token_hash);
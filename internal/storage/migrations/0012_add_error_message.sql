@@ -0,0 +1,3 @@
+-- Message of the last failure next to its error class (error_code, see models.EmailError*)
+ALTER TABLE emails ADD COLUMN error_message TEXT;
+CREATE INDEX IF NOT EXISTS idx_emails_error_code ON emails(error_code);
@@ -0,0 +1,20 @@
+-- One row per token that stopped working during a run, with the account it
+-- came from (via login_attempts.token_hash) and whether it expired or was banned
+CREATE TABLE IF NOT EXISTS token_invalidations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id TEXT,
+	token_hash TEXT NOT NULL,
+	account TEXT,
+	cause TEXT NOT NULL,
+	http_status INTEGER,
+	requests INTEGER NOT NULL DEFAULT 0,
+	token_age_seconds INTEGER,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_token_invalidations_account ON token_invalidations(account);
+CREATE INDEX IF NOT EXISTS idx_token_invalidations_token ON token_invalidations(token_hash);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_token ON login_attempts(token_hash);
+
+-- Token health of each account, fed from token_invalidations
+ALTER TABLE accounts ADD COLUMN token_expiries INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE accounts ADD COLUMN token_bans INTEGER NOT NULL DEFAULT 0;
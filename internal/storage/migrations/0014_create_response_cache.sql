@@ -0,0 +1,10 @@
+-- Successful API responses by crawl mode and target (email, phone or name
+-- query), replayed instead of querying again while younger than ResponseCacheTTL
+CREATE TABLE IF NOT EXISTS response_cache (
+	mode TEXT NOT NULL,
+	target TEXT NOT NULL,
+	has_profile INTEGER NOT NULL DEFAULT 0,
+	body BLOB,
+	cached_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (mode, target)
+);
@@ -0,0 +1,7 @@
+-- Extra columns of input CSVs with a header row (lead id, source, owner...) by
+-- input key, echoed into exports and webhooks; kept across runs like results
+CREATE TABLE IF NOT EXISTS input_fields (
+	key TEXT PRIMARY KEY,
+	fields TEXT NOT NULL,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
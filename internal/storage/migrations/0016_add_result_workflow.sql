@@ -0,0 +1,5 @@
+-- User-editable working status (new, contacted, ignored) and comma-separated
+-- tags of each result; re-crawling an email keeps them
+ALTER TABLE results ADD COLUMN workflow_status TEXT NOT NULL DEFAULT 'new';
+ALTER TABLE results ADD COLUMN tags TEXT;
+CREATE INDEX IF NOT EXISTS idx_results_workflow_status ON results(workflow_status);
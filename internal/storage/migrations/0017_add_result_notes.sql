@@ -0,0 +1,2 @@
+-- Free-text notes of the team reviewing a result; re-crawling keeps them
+ALTER TABLE results ADD COLUMN notes TEXT;
@@ -0,0 +1,13 @@
+-- Concurrency and requests/sec approved after a calibration burst, with what
+-- the burst measured, so a campaign can start from tuned values
+CREATE TABLE IF NOT EXISTS tuning_profiles (
+	name TEXT PRIMARY KEY,
+	max_concurrency INTEGER NOT NULL,
+	requests_per_sec REAL NOT NULL,
+	emails INTEGER NOT NULL DEFAULT 0,
+	requests INTEGER NOT NULL DEFAULT 0,
+	rate_limited INTEGER NOT NULL DEFAULT 0,
+	avg_latency_ms INTEGER NOT NULL DEFAULT 0,
+	run_id TEXT,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
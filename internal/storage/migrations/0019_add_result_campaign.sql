@@ -0,0 +1,4 @@
+-- Campaign (Config.Campaign) of the run that last found each result, for
+-- archiving a campaign; NULL for results saved before this column existed
+ALTER TABLE results ADD COLUMN campaign TEXT;
+CREATE INDEX IF NOT EXISTS idx_results_campaign ON results(campaign);
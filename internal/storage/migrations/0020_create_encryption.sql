@@ -0,0 +1,9 @@
+-- Field encryption of results: one row while it is on, holding the salt the
+-- key is derived from and a known value encrypted with it to check passphrases
+CREATE TABLE IF NOT EXISTS encryption (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	salt BLOB NOT NULL,
+	iterations INTEGER NOT NULL,
+	check_value TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
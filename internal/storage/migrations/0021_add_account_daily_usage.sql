@@ -0,0 +1,5 @@
+-- Token extraction logins and tokens of each account on usage_day (local date),
+-- for the per-account daily caps; the counts restart on the first login of a new day
+ALTER TABLE accounts ADD COLUMN usage_day TEXT;
+ALTER TABLE accounts ADD COLUMN day_logins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE accounts ADD COLUMN day_tokens INTEGER NOT NULL DEFAULT 0;
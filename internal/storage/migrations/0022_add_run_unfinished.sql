@@ -0,0 +1,3 @@
+-- Runs are saved every few seconds while they crawl; unfinished = 1 until the
+-- final save, so a run that crashed keeps its last checkpoint
+ALTER TABLE runs ADD COLUMN unfinished INTEGER NOT NULL DEFAULT 0;
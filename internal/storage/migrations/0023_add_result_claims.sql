@@ -0,0 +1,4 @@
+-- Soft lock of reviewers sharing one results database: who claimed a result
+-- to contact the lead and when; NULL = unclaimed
+ALTER TABLE results ADD COLUMN claimed_by TEXT;
+ALTER TABLE results ADD COLUMN claimed_at DATETIME;
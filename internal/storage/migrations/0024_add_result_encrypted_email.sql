@@ -0,0 +1,3 @@
+-- Email of a result encrypted while encryption is on; the email column then
+-- holds a keyed hash of it so lookups by address still work. NULL = plain.
+ALTER TABLE results ADD COLUMN encrypted_email TEXT;
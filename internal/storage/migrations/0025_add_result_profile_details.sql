@@ -0,0 +1,4 @@
+-- Headline and current company of a result, kept only with Config.ExtendedProfile
+-- (advanced crawling license); NULL for results saved without them
+ALTER TABLE results ADD COLUMN headline TEXT;
+ALTER TABLE results ADD COLUMN company TEXT;
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// projectManifestFile is the manifest inside a project bundle
+const projectManifestFile = "project.json"
+
+// Roles of the files in a project bundle
+const (
+	ProjectFileDatabase  = "database"
+	ProjectFileEmails    = "emails"
+	ProjectFileHits      = "hits"
+	ProjectFileHitState  = "hits-state"
+	ProjectFileLog       = "log"
+	ProjectFileRunLog    = "run-log"
+	ProjectFileTemplates = "notify-templates"
+	ProjectFileAccounts  = "accounts"
+	ProjectFileTokens    = "tokens"
+)
+
+// ProjectFile is one file of a project bundle
+type ProjectFile struct {
+	Role string `json:"role"`
+	Path string `json:"path"` // Đường dẫn trên máy export
+	Name string `json:"name"` // Tên trong zip, cũng là đường dẫn tương đối khi import
+	Size int64  `json:"size"`
+}
+
+// ProjectBundle is the manifest of an exported project
+type ProjectBundle struct {
+	CreatedAt   time.Time     `json:"created_at"`
+	Host        string        `json:"host"`
+	Config      models.Config `json:"config"`      // Config như đã cấu hình (path template)
+	Credentials bool          `json:"credentials"` // Có accounts, tokens và mật khẩu/webhook trong config
+	Encrypted   bool          `json:"encrypted"`   // Results trong database cần passphrase
+	Files       []ProjectFile `json:"files"`
+
+	Path string `json:"-"`
+	Size int64  `json:"-"`
+}
+
+// credentialFields are the config values left out of bundles exported
+// without credentials
+func credentialFields(cfg *models.Config) []*string {
+	return []*string{
+		&cfg.IMAPPassword, &cfg.EmailsDBDSN, &cfg.SlackWebhookURL, &cfg.DiscordWebhookURL,
+		&cfg.WebhookURL, &cfg.APIHeaders, &cfg.TokenProviderAuth,
+	}
+}
+
+// KeepCredentials returns imported with the credential values of local, for
+// bundles exported without them
+func KeepCredentials(imported, local models.Config) models.Config {
+	dst, src := credentialFields(&imported), credentialFields(&local)
+	for i := range dst {
+		*dst[i] = *src[i]
+	}
+	return imported
+}
+
+// ExportProject zips the config, database, emails file, hit files and logs
+// of the current campaign into dest, with the accounts and tokens files and
+// config credentials only when credentials is set. cfg holds the path
+// templates as configured.
+func (es *EmailStorage) ExportProject(cfg models.Config, dest string, credentials bool) (*ProjectBundle, error) {
+	resolved, err := utils.ResolveConfigPaths(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &ProjectBundle{CreatedAt: time.Now().UTC(), Config: cfg, Credentials: credentials, Encrypted: es.Encrypted()}
+	bundle.Host, _ = os.Hostname()
+	if !credentials {
+		for _, field := range credentialFields(&bundle.Config) {
+			*field = ""
+		}
+	}
+
+	// Database: bản backup nhất quán, crawl vẫn có thể đang chạy
+	tmp, err := os.CreateTemp("", "project-*.db")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmp.Close()
+	os.Remove(tmp.Name())
+	defer os.Remove(tmp.Name())
+	if err := es.BackupDB(tmp.Name()); err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string) // Tên trong zip → file được copy
+	add := func(role, src, name string) {
+		info, err := os.Stat(src)
+		if err != nil || info.IsDir() {
+			return
+		}
+		// Hai file trùng tên: thêm role vào trước
+		if _, used := sources[name]; used {
+			name = path.Join(path.Dir(name), role+"-"+path.Base(name))
+		}
+		sources[name] = src
+		bundle.Files = append(bundle.Files, ProjectFile{Role: role, Path: src, Name: name, Size: info.Size()})
+	}
+
+	add(ProjectFileDatabase, tmp.Name(), filepath.Base(resolved.DBPath))
+	if len(bundle.Files) > 0 {
+		bundle.Files[0].Path = resolved.DBPath
+	}
+	add(ProjectFileEmails, resolved.EmailsFilePath, filepath.Base(resolved.EmailsFilePath))
+	for _, part := range utils.HitFileParts(resolved.OutputFilePath) {
+		add(ProjectFileHits, part, filepath.Base(part))
+		add(ProjectFileHitState, utils.ExportStatePath(part), filepath.Base(utils.ExportStatePath(part)))
+	}
+	add(ProjectFileLog, resolved.LogFilePath, filepath.Base(resolved.LogFilePath))
+	runLogs, _ := utils.ListRunLogs(resolved.LogFilePath)
+	for _, runLog := range runLogs {
+		add(ProjectFileRunLog, runLog.Path, path.Join(filepath.Base(utils.RunLogDir(resolved.LogFilePath)), filepath.Base(runLog.Path)))
+	}
+	add(ProjectFileTemplates, resolved.NotifyTemplates, filepath.Base(resolved.NotifyTemplates))
+	if credentials {
+		add(ProjectFileAccounts, resolved.AccountsFilePath, filepath.Base(resolved.AccountsFilePath))
+		add(ProjectFileTokens, resolved.TokensFilePath, filepath.Base(resolved.TokensFilePath))
+	}
+
+	out, err := utils.CreateAtomic(dest)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Abort()
+
+	zw := zip.NewWriter(out)
+	for _, f := range bundle.Files {
+		if err := addArchiveFile(zw, ArchivedFile{Path: sources[f.Name], Name: "files/" + f.Name}); err != nil {
+			return nil, err
+		}
+	}
+	w, err := zw.Create(projectManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := out.Commit(); err != nil {
+		return nil, err
+	}
+
+	bundle.Path = dest
+	bundle.Size = fileSize(dest)
+	return bundle, nil
+}
+
+// ReadProjectBundle reads the manifest of the project bundle at path
+func ReadProjectBundle(path string) (*ProjectBundle, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer zr.Close()
+	return readProjectManifest(&zr.Reader, path)
+}
+
+func readProjectManifest(zr *zip.Reader, path string) (*ProjectBundle, error) {
+	data, err := readArchiveEntry(zr, projectManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("%s is not a project bundle", path)
+	}
+	var bundle ProjectBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+	}
+	bundle.Path = path
+	bundle.Size = fileSize(path)
+	return &bundle, nil
+}
+
+// ProjectConfig returns the config of bundle with its file paths in dir: the
+// extracted files, and the default names for the ones not in the bundle
+func ProjectConfig(bundle *ProjectBundle, dir string) models.Config {
+	cfg := CampaignConfig(bundle.Config, dir)
+	if cfg.NotifyTemplates != "" {
+		cfg.NotifyTemplates = filepath.Join(dir, filepath.Base(cfg.NotifyTemplates))
+	}
+
+	paths := map[string]*string{
+		ProjectFileDatabase:  &cfg.DBPath,
+		ProjectFileEmails:    &cfg.EmailsFilePath,
+		ProjectFileHits:      &cfg.OutputFilePath, // Phần cuối cùng là hit file đang ghi
+		ProjectFileLog:       &cfg.LogFilePath,
+		ProjectFileTemplates: &cfg.NotifyTemplates,
+		ProjectFileAccounts:  &cfg.AccountsFilePath,
+		ProjectFileTokens:    &cfg.TokensFilePath,
+	}
+	for _, f := range bundle.Files {
+		if target, ok := paths[f.Role]; ok {
+			*target = filepath.Join(dir, filepath.FromSlash(f.Name))
+		}
+	}
+	return cfg
+}
+
+// ProjectConflicts returns the files of bundle that already exist in dir
+func ProjectConflicts(bundle *ProjectBundle, dir string) []string {
+	var conflicts []string
+	for _, f := range bundle.Files {
+		target := filepath.Join(dir, filepath.FromSlash(f.Name))
+		if _, err := os.Stat(target); err == nil {
+			conflicts = append(conflicts, target)
+		}
+	}
+	return conflicts
+}
+
+// ImportProject extracts the bundle at path into dir and returns it with the
+// config pointing at the extracted files (see ProjectConfig). Existing files
+// are only replaced when overwrite is set.
+func ImportProject(path, dir string, overwrite bool) (*ProjectBundle, models.Config, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, models.Config{}, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer zr.Close()
+
+	bundle, err := readProjectManifest(&zr.Reader, path)
+	if err != nil {
+		return nil, models.Config{}, err
+	}
+	for _, f := range bundle.Files {
+		if !filepath.IsLocal(filepath.FromSlash(f.Name)) {
+			return bundle, models.Config{}, fmt.Errorf("invalid file name in bundle: %s", f.Name)
+		}
+	}
+	if conflicts := ProjectConflicts(bundle, dir); len(conflicts) > 0 && !overwrite {
+		return bundle, models.Config{}, fmt.Errorf("%d files already exist in %s (first: %s)", len(conflicts), dir, conflicts[0])
+	}
+
+	for _, f := range bundle.Files {
+		target := filepath.Join(dir, filepath.FromSlash(f.Name))
+		if err := extractProjectFile(&zr.Reader, "files/"+f.Name, target); err != nil {
+			return bundle, models.Config{}, err
+		}
+	}
+	return bundle, ProjectConfig(bundle, dir), nil
+}
+
+// extractProjectFile writes the zip entry name to target atomically
+func extractProjectFile(zr *zip.Reader, name, target string) error {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s from bundle: %w", name, err)
+		}
+		defer r.Close()
+
+		out, err := utils.CreateAtomic(target)
+		if err != nil {
+			return err
+		}
+		defer out.Abort()
+		if _, err := io.Copy(out, r); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", target, err)
+		}
+		return out.Commit()
+	}
+	return fmt.Errorf("bundle is missing %s", name)
+}
@@ -0,0 +1,348 @@
+package storage
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// purgeEmailTables are the tables holding a target address, with the column
+// that holds it
+var purgeEmailTables = []struct{ name, column string }{
+	{"emails", "email"},
+	{"results", "email"},
+	{"email_events", "email"},
+	{"input_fields", "key"},
+	{"response_cache", "target"},
+}
+
+// retentionTables are the tables purged by age, with the WHERE clause taking
+// the cutoff (CURRENT_TIMESTAMP format) as its only argument. Emails still
+// pending are kept; runs and login rows hold no target addresses.
+var retentionTables = []struct{ name, where string }{
+	{"emails", "COALESCE(updated_at, created_at) < ? AND status != 'pending'"},
+	{"results", "created_at < ?"},
+	{"email_events", "created_at < ?"},
+	{"input_fields", "updated_at < ?"},
+	{"response_cache", "cached_at < ?"},
+}
+
+// PurgeCounts are the rows deleted per table
+type PurgeCounts map[string]int
+
+// Total returns the number of deleted rows
+func (c PurgeCounts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// String renders the non-zero counts as "results 3, email_events 12"
+func (c PurgeCounts) String() string {
+	tables := make([]string, 0, len(c))
+	for table := range c {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	var parts []string
+	for _, table := range tables {
+		if c[table] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", table, c[table]))
+		}
+	}
+	if len(parts) == 0 {
+		return "no rows"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PurgeEmails deletes every row about emails (compared case-insensitively)
+func (es *EmailStorage) PurgeEmails(emails []string) (PurgeCounts, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	counts := make(PurgeCounts)
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			continue
+		}
+		for _, table := range purgeEmailTables {
+			where, value := "lower(trim("+table.column+")) = ?", email
+			if table.name == "results" && es.cipher != nil {
+				// Results đã mã hoá chỉ giữ keyed hash của email
+				where, value = "email = ?", es.cipher.resultKey(email)
+			}
+			res, err := tx.Exec("DELETE FROM "+table.name+" WHERE "+where, value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to purge %s: %w", table.name, err)
+			}
+			n, _ := res.RowsAffected()
+			counts[table.name] += int(n)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return counts, nil
+}
+
+// PurgeOlderThan deletes processed emails, results, events, input fields and
+// cached responses last touched before cutoff
+func (es *EmailStorage) PurgeOlderThan(cutoff time.Time) (PurgeCounts, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	counts := make(PurgeCounts)
+	before := cutoff.UTC().Format("2006-01-02 15:04:05") // Định dạng của CURRENT_TIMESTAMP
+	for _, table := range retentionTables {
+		res, err := tx.Exec("DELETE FROM "+table.name+" WHERE "+table.where, before)
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge %s: %w", table.name, err)
+		}
+		n, _ := res.RowsAffected()
+		counts[table.name] = int(n)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return counts, nil
+}
+
+// ApplyRetention purges the rows older than days days and the run logs of
+// cfg (resolved paths) not written to since; days <= 0 keeps everything
+func (es *EmailStorage) ApplyRetention(cfg models.Config, days int) (PurgeCounts, int, error) {
+	if days <= 0 {
+		return PurgeCounts{}, 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	counts, err := es.PurgeOlderThan(cutoff)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logs, err := utils.ListRunLogs(cfg.LogFilePath)
+	if err != nil {
+		return counts, 0, fmt.Errorf("failed to list run logs: %w", err)
+	}
+	removed := 0
+	for _, runLog := range logs {
+		if runLog.ModTime.Before(cutoff) && os.Remove(runLog.Path) == nil {
+			removed++
+		}
+	}
+	return counts, removed, nil
+}
+
+// ErasedFile is one file changed by EraseAddresses
+type ErasedFile struct {
+	Path   string
+	Action string // "removed lines", "redacted" hoặc "deleted"
+	Lines  int
+}
+
+// ErasureReport records what EraseAddresses removed, for answering erasure
+// requests
+type ErasureReport struct {
+	Emails    []string
+	At        time.Time
+	Rows      PurgeCounts
+	Files     []ErasedFile
+	Remaining []string // Backups và archives vẫn chứa địa chỉ: xoá hoặc tạo lại
+}
+
+// ErasureReportPath returns where the report of an erasure at at is written:
+// erasure-report-<time>.txt next to the database at dbPath
+func ErasureReportPath(dbPath string, at time.Time) string {
+	return filepath.Join(filepath.Dir(dbPath), fmt.Sprintf("erasure-report-%s.txt", at.Format(snapshotLayout)))
+}
+
+// String renders the report as plain text
+func (r ErasureReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Erasure report - %s\n", r.At.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Addresses: %s\n\n", strings.Join(r.Emails, ", "))
+
+	fmt.Fprintf(&b, "Database rows deleted: %s\n", r.Rows)
+	b.WriteString("The database was vacuumed so deleted rows do not remain in free pages.\n\n")
+
+	fmt.Fprintf(&b, "Files changed: %d\n", len(r.Files))
+	for _, f := range r.Files {
+		if f.Action == "deleted" {
+			fmt.Fprintf(&b, "- %s: deleted\n", f.Path)
+		} else {
+			fmt.Fprintf(&b, "- %s: %s (%d lines)\n", f.Path, f.Action, f.Lines)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nStill mentioned in backups and archives: %d\n", len(r.Remaining))
+	for _, path := range r.Remaining {
+		fmt.Fprintf(&b, "- %s\n", path)
+	}
+	if len(r.Remaining) > 0 {
+		b.WriteString("These copies were not changed: delete them or restore, erase and archive again.\n")
+	}
+	b.WriteString("\nExports written elsewhere (CSV/JSONL exports, Google Sheets, webhooks) are not tracked and must be checked by hand.\n")
+	return b.String()
+}
+
+// EraseAddresses removes every trace of emails: database rows (then vacuums),
+// hit file and input file lines of every campaign, mentions in the crawler
+// and run logs, and debug captures. Backups and campaign archives are only
+// searched and listed in the report. cfg holds the path templates as
+// configured.
+func (es *EmailStorage) EraseAddresses(cfg models.Config, emails []string) (*ErasureReport, error) {
+	report := &ErasureReport{At: time.Now()}
+	for _, email := range emails {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			report.Emails = append(report.Emails, email)
+		}
+	}
+	if len(report.Emails) == 0 {
+		return nil, fmt.Errorf("no email address to erase")
+	}
+	pattern := utils.AddressPattern(report.Emails)
+
+	rows, err := es.PurgeEmails(report.Emails)
+	if err != nil {
+		return nil, err
+	}
+	report.Rows = rows
+	if _, _, err := es.VacuumDB(); err != nil {
+		return report, err
+	}
+
+	// Mọi campaign, mode và ngày của các path template
+	glob := func(template string) []string {
+		if template == "" {
+			return nil
+		}
+		matches, _ := filepath.Glob(utils.PathTemplateGlob(strings.ReplaceAll(template, "{campaign}", "*"), cfg))
+		return matches
+	}
+	edit := func(path, action string, fn func(string, *regexp.Regexp) (int, error)) error {
+		n, err := fn(path, pattern)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			report.Files = append(report.Files, ErasedFile{Path: path, Action: action, Lines: n})
+		}
+		return nil
+	}
+
+	for _, hitFile := range glob(cfg.OutputFilePath) {
+		for _, part := range utils.HitFileParts(hitFile) {
+			if err := edit(part, "removed lines", utils.EraseLines); err != nil {
+				return report, err
+			}
+		}
+	}
+	for _, inputFile := range glob(cfg.EmailsFilePath) {
+		if err := edit(inputFile, "removed lines", utils.EraseLines); err != nil {
+			return report, err
+		}
+	}
+	for _, logFile := range glob(cfg.LogFilePath) {
+		if err := edit(logFile, "redacted", utils.RedactFile); err != nil {
+			return report, err
+		}
+		runLogs, _ := utils.ListRunLogs(logFile)
+		for _, runLog := range runLogs {
+			if err := edit(runLog.Path, "redacted", utils.RedactFile); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	// Debug capture chứa request/response đầy đủ: xoá cả file
+	if cfg.CaptureDir != "" {
+		filepath.WalkDir(cfg.CaptureDir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && utils.FileMentions(path, pattern) && os.Remove(path) == nil {
+				report.Files = append(report.Files, ErasedFile{Path: path, Action: "deleted"})
+			}
+			return nil
+		})
+	}
+
+	// Bản sao chỉ được liệt kê, không sửa
+	if cfg.BackupDir != "" {
+		filepath.WalkDir(cfg.BackupDir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && utils.FileMentions(path, pattern) {
+				report.Remaining = append(report.Remaining, path)
+			}
+			return nil
+		})
+	}
+	archives, _ := filepath.Glob(filepath.Join(CampaignArchiveDir(es.dbPath), "*.zip"))
+	for _, path := range archives {
+		if zipMentions(path, pattern) {
+			report.Remaining = append(report.Remaining, path)
+		}
+	}
+	return report, nil
+}
+
+// zipMentions reports whether any entry of the zip at path matches pattern
+func zipMentions(path string, pattern *regexp.Regexp) bool {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return false
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err == nil && pattern.Match(data) {
+			return true
+		}
+	}
+	return false
+}
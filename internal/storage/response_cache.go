@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// CachedResponse is a stored 200 response for one target
+type CachedResponse struct {
+	Target     string
+	HasProfile bool
+	Body       []byte // nil khi không có profile
+}
+
+// SaveCachedResponse stores the 200 response of target in crawl mode mode,
+// replacing an older one. The body holds the profile, so it is encrypted
+// while encryption is on.
+func (es *EmailStorage) SaveCachedResponse(mode, target string, hasProfile bool, body []byte) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if !hasProfile {
+		body = nil
+	}
+	if body != nil && es.cipher != nil {
+		encrypted, err := es.cipher.encrypt(string(body))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt cached response: %w", err)
+		}
+		body = []byte(encrypted)
+	}
+	if _, err := es.db.Exec(
+		"INSERT OR REPLACE INTO response_cache (mode, target, has_profile, body, cached_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)",
+		mode, target, hasProfile, body,
+	); err != nil {
+		return fmt.Errorf("failed to cache response: %w", err)
+	}
+	return nil
+}
+
+// GetCachedPendingResponses returns the cached responses younger than ttl
+// for the pending emails of crawl mode mode
+func (es *EmailStorage) GetCachedPendingResponses(mode string, ttl time.Duration) ([]CachedResponse, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	rows, err := es.db.Query(`
+		SELECT c.target, c.has_profile, c.body FROM response_cache c
+		JOIN emails e ON e.email = c.target
+		WHERE c.mode = ? AND e.status = ? AND c.cached_at >= datetime('now', ?)
+		ORDER BY e.id`,
+		mode, StatusPending, sqliteAge(ttl),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query response cache: %w", err)
+	}
+	defer rows.Close()
+
+	var responses []CachedResponse
+	for rows.Next() {
+		var r CachedResponse
+		if err := rows.Scan(&r.Target, &r.HasProfile, &r.Body); err != nil {
+			return nil, fmt.Errorf("failed to scan cached response: %w", err)
+		}
+		if r.Body != nil {
+			plain, err := es.cipher.decrypt(string(r.Body))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt cached response of %s: %w", r.Target, err)
+			}
+			r.Body = []byte(plain)
+		}
+		responses = append(responses, r)
+	}
+	return responses, rows.Err()
+}
+
+// PurgeResponseCache deletes the cached responses older than ttl, or all of
+// them when ttl is 0, and returns how many were deleted
+func (es *EmailStorage) PurgeResponseCache(ttl time.Duration) (int, error) {
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return 0, fmt.Errorf("database is closed")
+	}
+
+	query, args := "DELETE FROM response_cache", []interface{}{}
+	if ttl > 0 {
+		query += " WHERE cached_at < datetime('now', ?)"
+		args = append(args, sqliteAge(ttl))
+	}
+	result, err := es.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge response cache: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// sqliteAge formats ttl as a datetime('now', ?) modifier
+func sqliteAge(ttl time.Duration) string {
+	return fmt.Sprintf("-%d seconds", int64(ttl.Seconds()))
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ResultClaimExpiry is how long a claim holds: an older claim, e.g. of a
+// reviewer who left, can be taken by someone else
+const ResultClaimExpiry = 24 * time.Hour
+
+// ErrResultClaimed is returned when a result is claimed by another reviewer
+var ErrResultClaimed = errors.New("result is claimed by another reviewer")
+
+// ClaimResults marks the results of emails as claimed by reviewer so other
+// reviewers sharing the database do not contact the same leads. It claims all
+// or none: when one is held by another reviewer (and not expired) nothing
+// changes and ErrResultClaimed is returned. Claiming again renews the claim.
+func (es *EmailStorage) ClaimResults(emails []string, reviewer string) error {
+	reviewer = strings.TrimSpace(reviewer)
+	if reviewer == "" {
+		return fmt.Errorf("reviewer name is required to claim results")
+	}
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	expired := fmt.Sprintf("-%d seconds", int(ResultClaimExpiry.Seconds()))
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		// Điều kiện nằm trong UPDATE: hai reviewer claim cùng lúc thì chỉ một người thắng
+		res, err := tx.Exec(`
+			UPDATE results SET claimed_by = ?, claimed_at = CURRENT_TIMESTAMP
+			WHERE email = ? AND (claimed_by IS NULL OR claimed_by = ? OR claimed_at < datetime('now', ?))`,
+			reviewer, es.cipher.resultKey(email), reviewer, expired,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to claim %s: %w", email, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			continue
+		}
+
+		var holder string
+		err = tx.QueryRow("SELECT COALESCE(claimed_by, '') FROM results WHERE email = ?", es.cipher.resultKey(email)).Scan(&holder)
+		if err != nil {
+			return fmt.Errorf("failed to claim %s: result not found", email)
+		}
+		return fmt.Errorf("%w: %s is claimed by %s", ErrResultClaimed, email, holder)
+	}
+	return tx.Commit()
+}
+
+// ReleaseResults removes the claims reviewer holds on the results of emails;
+// claims of other reviewers are kept
+func (es *EmailStorage) ReleaseResults(emails []string, reviewer string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, email := range emails {
+		if _, err := tx.Exec(
+			"UPDATE results SET claimed_by = NULL, claimed_at = NULL WHERE email = ? AND claimed_by = ?",
+			es.cipher.resultKey(email), strings.TrimSpace(reviewer),
+		); err != nil {
+			return fmt.Errorf("failed to release %s: %w", email, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,329 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// SaveResult stores the profile found for an email in campaign, replacing an
+// older one
+func (es *EmailStorage) SaveResult(email string, profile models.ProfileData, campaign string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	source := profile.Source
+	if source == "" {
+		source = models.SourceEmail
+	}
+	values, err := es.encryptFields(profile.User, utils.NormalizeLinkedInURL(profile.LinkedInURL), profile.Location, profile.ConnectionCount,
+		profile.Headline, profile.Company)
+	if err != nil {
+		return err
+	}
+	key, encryptedEmail, err := es.cipher.sealEmail(email)
+	if err != nil {
+		return err
+	}
+	_, err = es.db.Exec(`
+		INSERT INTO results (email, encrypted_email, name, linkedin_url, location, connections, headline, company, source, campaign)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(email) DO UPDATE SET
+			encrypted_email = excluded.encrypted_email,
+			name = excluded.name, linkedin_url = excluded.linkedin_url, location = excluded.location,
+			connections = excluded.connections, headline = excluded.headline, company = excluded.company,
+			source = excluded.source, campaign = excluded.campaign`,
+		key, encryptedEmail, values[0], values[1], values[2], values[3], nullString(values[4]), nullString(values[5]),
+		source, nullString(strings.TrimSpace(campaign)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save result: %w", err)
+	}
+	return nil
+}
+
+// ImportResults adds hits (e.g. from existing hit files) that are not stored
+// yet and returns how many were added
+func (es *EmailStorage) ImportResults(entries []utils.HitResult) (int, error) {
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO results (email, encrypted_email, name, linkedin_url, location, connections, source) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare import: %w", err)
+	}
+	defer stmt.Close()
+
+	added := 0
+	for _, entry := range entries {
+		source := entry.Source
+		if source == "" {
+			source = models.SourceEmail
+		}
+		values, err := es.encryptFields(entry.Name, entry.LinkedInURL, entry.Location, entry.Connections)
+		if err != nil {
+			return 0, err
+		}
+		key, encryptedEmail, err := es.cipher.sealEmail(entry.Email)
+		if err != nil {
+			return 0, err
+		}
+		res, err := stmt.Exec(key, encryptedEmail, values[0], values[1], values[2], values[3], source)
+		if err != nil {
+			return 0, fmt.Errorf("failed to import result %s: %w", entry.Email, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return added, nil
+}
+
+// GetResults returns all stored results, newest first
+func (es *EmailStorage) GetResults() ([]utils.HitResult, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	rows, err := es.db.Query(`
+		SELECT email, COALESCE(encrypted_email, ''), COALESCE(name, ''), COALESCE(linkedin_url, ''), COALESCE(location, ''),
+			COALESCE(connections, ''), COALESCE(headline, ''), COALESCE(company, ''), source, COALESCE(created_at, ''), workflow_status, COALESCE(tags, ''),
+			COALESCE(notes, ''), COALESCE(claimed_by, ''), COALESCE(claimed_at, '')
+		FROM results ORDER BY created_at DESC, email`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []utils.HitResult
+	for rows.Next() {
+		var hit utils.HitResult
+		var encryptedEmail, createdAt, tags, claimedAt string
+		if err := rows.Scan(&hit.Email, &encryptedEmail, &hit.Name, &hit.LinkedInURL, &hit.Location, &hit.Connections, &hit.Headline, &hit.Company, &hit.Source, &createdAt,
+			&hit.WorkflowStatus, &tags, &hit.Notes, &hit.ClaimedBy, &claimedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan result: %w", err)
+		}
+		if encryptedEmail != "" {
+			if hit.Email, err = es.cipher.decrypt(encryptedEmail); err != nil {
+				return nil, fmt.Errorf("failed to decrypt result: %w", err)
+			}
+		}
+		for _, field := range []*string{&hit.Name, &hit.LinkedInURL, &hit.Location, &hit.Connections, &hit.Headline, &hit.Company, &hit.Notes} {
+			if *field, err = es.cipher.decrypt(*field); err != nil {
+				return nil, fmt.Errorf("failed to decrypt result %s: %w", hit.Email, err)
+			}
+		}
+		hit.Timestamp = parseSQLiteTime(createdAt)
+		hit.ClaimedAt = parseSQLiteTime(claimedAt)
+		hit.Tags = models.ParseTags(tags)
+		results = append(results, hit)
+	}
+	return results, rows.Err()
+}
+
+// UpdateResultWorkflow sets the workflow status (models.ResultStatus*) and
+// tags of the results of emails
+func (es *EmailStorage) UpdateResultWorkflow(emails []string, status string, tags []string) error {
+	if _, ok := models.ParseResultStatus(status); !ok {
+		return fmt.Errorf("invalid result status: %q", status)
+	}
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, email := range emails {
+		if _, err := tx.Exec(
+			"UPDATE results SET workflow_status = ?, tags = ? WHERE email = ?",
+			status, nullString(strings.Join(tags, ",")), es.cipher.resultKey(email),
+		); err != nil {
+			return fmt.Errorf("failed to update result %s: %w", email, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateResultNotes sets the notes of the results of emails; empty notes
+// remove them
+func (es *EmailStorage) UpdateResultNotes(emails []string, notes string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	notes, err = es.cipher.encrypt(strings.TrimSpace(notes))
+	if err != nil {
+		return err
+	}
+	for _, email := range emails {
+		if _, err := tx.Exec("UPDATE results SET notes = ? WHERE email = ?", nullString(notes), es.cipher.resultKey(email)); err != nil {
+			return fmt.Errorf("failed to update notes of %s: %w", email, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateResultProfiles replaces the profile fields (name, LinkedIn URL,
+// location, connections) of the stored results of entries, keeping their
+// workflow, notes and campaign; entries without a stored result are skipped.
+// It returns how many results were updated.
+func (es *EmailStorage) UpdateResultProfiles(entries []utils.HitResult) (int, error) {
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return 0, fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updated := 0
+	for _, entry := range entries {
+		values, err := es.encryptFields(entry.Name, utils.NormalizeLinkedInURL(entry.LinkedInURL), entry.Location, entry.Connections)
+		if err != nil {
+			return 0, err
+		}
+		res, err := tx.Exec(
+			"UPDATE results SET name = ?, linkedin_url = ?, location = ?, connections = ? WHERE email = ?",
+			values[0], values[1], values[2], values[3], es.cipher.resultKey(entry.Email),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update result %s: %w", entry.Email, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			updated++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit results: %w", err)
+	}
+	return updated, nil
+}
+
+// AnnotateHits adds what hit files do not hold to entries read from one: the
+// headline, company, workflow status, tags and notes of stored results and the
+// custom input fields
+func (es *EmailStorage) AnnotateHits(entries []utils.HitResult) error {
+	results, err := es.GetResults()
+	if err != nil {
+		return err
+	}
+	fields, err := es.GetAllInputFields()
+	if err != nil {
+		return err
+	}
+
+	byEmail := make(map[string]utils.HitResult, len(results))
+	for _, result := range results {
+		byEmail[strings.ToLower(strings.TrimSpace(result.Email))] = result
+	}
+	utils.AttachInputFields(entries, fields)
+	for i := range entries {
+		if result, ok := byEmail[strings.ToLower(strings.TrimSpace(entries[i].Email))]; ok {
+			entries[i].Headline, entries[i].Company = result.Headline, result.Company
+			entries[i].WorkflowStatus = result.WorkflowStatus
+			entries[i].Tags = result.Tags
+			entries[i].Notes = result.Notes
+		}
+	}
+	return nil
+}
+
+// encryptFields encrypts result column values when encryption is on
+func (es *EmailStorage) encryptFields(values ...string) ([]string, error) {
+	encrypted := make([]string, len(values))
+	for i, value := range values {
+		var err error
+		if encrypted[i], err = es.cipher.encrypt(value); err != nil {
+			return nil, fmt.Errorf("failed to encrypt result: %w", err)
+		}
+	}
+	return encrypted, nil
+}
+
+// CountResults returns the number of stored results
+func (es *EmailStorage) CountResults() (int, error) {
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	var count int
+	if err := es.db.QueryRow("SELECT COUNT(*) FROM results").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count results: %w", err)
+	}
+	return count, nil
+}
+
+// parseSQLiteTime parses a CURRENT_TIMESTAMP value (UTC)
+func parseSQLiteTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"testing"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+func TestUpdateResultProfilesKeepsWorkflow(t *testing.T) {
+	es := newTestStorage(t)
+	if err := es.SaveResult("ada@example.com", models.ProfileData{User: "Ada Byron", Location: "London"}, "spring"); err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+	if err := es.UpdateResultNotes([]string{"ada@example.com"}, "call back"); err != nil {
+		t.Fatalf("UpdateResultNotes: %v", err)
+	}
+
+	n, err := es.UpdateResultProfiles([]utils.HitResult{
+		{Email: "ada@example.com", Name: "Ada Lovelace", Location: "London"},
+		{Email: "unknown@example.com", Name: "Nobody"},
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("UpdateResultProfiles = %d, %v; want 1", n, err)
+	}
+
+	results, err := es.GetResults()
+	if err != nil {
+		t.Fatalf("GetResults: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "Ada Lovelace" || results[0].Notes != "call back" {
+		t.Fatalf("results = %+v, want the new name and the old notes", results)
+	}
+	var campaign string
+	if err := es.db.QueryRow("SELECT campaign FROM results").Scan(&campaign); err != nil || campaign != "spring" {
+		t.Fatalf("campaign = %q, %v; want spring", campaign, err)
+	}
+}
+
+func TestSaveResultExtendedProfile(t *testing.T) {
+	es := newEncryptedTestStorage(t)
+	profile := models.ProfileData{User: "Ada Lovelace", Headline: "Analyst", Company: "Analytical Engines"}
+	if err := es.SaveResult("ada@example.com", profile, ""); err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+
+	var headline string
+	if err := es.db.QueryRow("SELECT headline FROM results").Scan(&headline); err != nil {
+		t.Fatal(err)
+	}
+	if headline == profile.Headline {
+		t.Fatalf("headline stored in plain text: %s", headline)
+	}
+
+	entries := []utils.HitResult{{Email: "ada@example.com", Name: "Ada Lovelace"}}
+	if err := es.AnnotateHits(entries); err != nil {
+		t.Fatalf("AnnotateHits: %v", err)
+	}
+	if entries[0].Headline != profile.Headline || entries[0].Company != profile.Company {
+		t.Fatalf("annotated = %+v, want the headline and company", entries[0])
+	}
+}
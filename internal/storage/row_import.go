@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"linkedin-crawler/internal/models"
+)
+
+// parseEmailRow returns the normalized email of an input row (CSV: first column)
+func parseEmailRow(line string) (string, error) {
+	if strings.Contains(line, ",") {
+		line = strings.TrimSpace(strings.SplitN(line, ",", 2)[0])
+	}
+	if !emailPattern.MatchString(line) {
+		return "", fmt.Errorf("invalid email format, skipped: %s", line)
+	}
+	return strings.ToLower(line), nil
+}
+
+// ImportRows adds input rows as pending, keeping the rows
+// already in the database, so they can be added while a crawl runs. Comments
+// and blank lines are skipped; extra columns under a header row are stored as
+// custom fields. It returns how many rows were new and how many were invalid.
+func (es *EmailStorage) ImportRows(lines []string) (added, invalid int, err error) {
+	lines, fields := splitInputFields(lines)
+	var keys []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := parseEmailRow(line)
+		if err != nil {
+			invalid++
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return 0, invalid, nil
+	}
+
+	if err := es.ensureDB(); err != nil {
+		return 0, invalid, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.Lock()
+	defer es.dbMutex.Unlock()
+
+	if es.isDBClosed {
+		return 0, invalid, fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return 0, invalid, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	added, err = bulkInsert(tx, keys, models.SourceEmail, nil)
+	if err != nil {
+		return 0, invalid, err
+	}
+	if err := saveInputFields(tx, fields); err != nil {
+		return 0, invalid, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, invalid, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return added, invalid, nil
+}
+
+// MaxEmailID returns the highest row id of the emails table (0 when empty)
+func (es *EmailStorage) MaxEmailID() (int, error) {
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return 0, fmt.Errorf("database is closed")
+	}
+
+	var id int
+	if err := es.db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM emails").Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get max email id: %w", err)
+	}
+	return id, nil
+}
+
+// GetPendingEmailsAfter returns the pending emails inserted after row afterID,
+// oldest first, with the highest id seen (afterID when there are none)
+func (es *EmailStorage) GetPendingEmailsAfter(afterID int) ([]string, int, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, afterID, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, afterID, fmt.Errorf("database is closed")
+	}
+
+	rows, err := es.db.Query("SELECT id, email FROM emails WHERE id > ? AND status = ? ORDER BY id", afterID, StatusPending)
+	if err != nil {
+		return nil, afterID, fmt.Errorf("failed to query new pending emails: %w", err)
+	}
+	defer rows.Close()
+
+	var emails []string
+	lastID := afterID
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&lastID, &email); err != nil {
+			return nil, afterID, fmt.Errorf("failed to scan email: %w", err)
+		}
+		emails = append(emails, email)
+	}
+	return emails, lastID, rows.Err()
+}
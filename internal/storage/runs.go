@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// RunRecord is one row of the runs table
+type RunRecord struct {
+	ID             int
+	RunID          string // Tên log của lần chạy: logs/run-<RunID>.log
+	StartedAt      time.Time
+	FinishedAt     time.Time
+	Campaign       string
+	CrawlMode      string
+	MaxConcurrency int64
+	RequestsPerSec float64
+	PacingProfile  string
+	Processed      int // hits + no_info + failed
+	Hits           int
+	NoInfo         int
+	Failed         int
+	Requests       int // Mọi lần gửi request, kể cả retry
+	RateLimited    int // Số response 429
+	LoginAttempts  int
+	TokensObtained int
+	Unfinished     bool // Checkpoint giữa lần chạy: đang chạy, hoặc bị crash nếu không phải lần chạy hiện tại
+}
+
+// Duration returns how long the run took
+func (r RunRecord) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// EmailsPerHour returns processed emails per hour
+func (r RunRecord) EmailsPerHour() float64 {
+	hours := r.Duration().Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return float64(r.Processed) / hours
+}
+
+// HitRate returns the percentage of processed emails that had a profile
+func (r RunRecord) HitRate() float64 {
+	if r.Processed == 0 {
+		return 0
+	}
+	return float64(r.Hits) * 100 / float64(r.Processed)
+}
+
+// TokensPerAccount returns tokens obtained per account login
+func (r RunRecord) TokensPerAccount() float64 {
+	if r.LoginAttempts == 0 {
+		return 0
+	}
+	return float64(r.TokensObtained) / float64(r.LoginAttempts)
+}
+
+// RateLimitRate returns the percentage of requests answered with 429
+func (r RunRecord) RateLimitRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.RateLimited) * 100 / float64(r.Requests)
+}
+
+// RunAverages holds the mean metrics of several runs
+type RunAverages struct {
+	Runs             int
+	EmailsPerHour    float64
+	HitRate          float64
+	TokensPerAccount float64
+	RateLimitRate    float64
+}
+
+// AverageRuns averages the metrics of runs; runs without logins are left
+// out of TokensPerAccount
+func AverageRuns(runs []RunRecord) RunAverages {
+	avg := RunAverages{Runs: len(runs)}
+	if len(runs) == 0 {
+		return avg
+	}
+
+	loginRuns := 0
+	for _, run := range runs {
+		avg.EmailsPerHour += run.EmailsPerHour()
+		avg.HitRate += run.HitRate()
+		avg.RateLimitRate += run.RateLimitRate()
+		if run.LoginAttempts > 0 {
+			avg.TokensPerAccount += run.TokensPerAccount()
+			loginRuns++
+		}
+	}
+	n := float64(len(runs))
+	avg.EmailsPerHour /= n
+	avg.HitRate /= n
+	avg.RateLimitRate /= n
+	if loginRuns > 0 {
+		avg.TokensPerAccount /= float64(loginRuns)
+	}
+	return avg
+}
+
+// SaveRun saves the run, replacing the row with the same RunID: runs are
+// saved as unfinished checkpoints while they crawl, then once more at the end
+func (es *EmailStorage) SaveRun(run RunRecord) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if run.RunID != "" {
+		result, err := es.db.Exec(`
+			UPDATE runs SET finished_at = ?, processed = ?, hits = ?, no_info = ?, failed = ?, requests = ?,
+				rate_limited = ?, login_attempts = ?, tokens_obtained = ?, unfinished = ?
+			WHERE run_id = ?`,
+			run.FinishedAt, run.Processed, run.Hits, run.NoInfo, run.Failed, run.Requests,
+			run.RateLimited, run.LoginAttempts, run.TokensObtained, run.Unfinished, run.RunID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save run: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			return nil
+		}
+	}
+
+	_, err := es.db.Exec(`
+		INSERT INTO runs (run_id, started_at, finished_at, campaign, crawl_mode, max_concurrency, requests_per_sec, pacing_profile,
+			processed, hits, no_info, failed, requests, rate_limited, login_attempts, tokens_obtained, unfinished)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.RunID, run.StartedAt, run.FinishedAt, run.Campaign, run.CrawlMode, run.MaxConcurrency, run.RequestsPerSec, run.PacingProfile,
+		run.Processed, run.Hits, run.NoInfo, run.Failed, run.Requests, run.RateLimited, run.LoginAttempts, run.TokensObtained, run.Unfinished,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	return nil
+}
+
+// GetRuns returns up to limit runs, newest first (limit <= 0 = all)
+func (es *EmailStorage) GetRuns(limit int) ([]RunRecord, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := es.db.Query(`
+		SELECT id, COALESCE(run_id, ''), started_at, finished_at, COALESCE(campaign, ''), COALESCE(crawl_mode, ''),
+			COALESCE(max_concurrency, 0), COALESCE(requests_per_sec, 0), COALESCE(pacing_profile, ''),
+			processed, hits, no_info, failed, requests, rate_limited, login_attempts, tokens_obtained, unfinished
+		FROM runs ORDER BY started_at DESC, id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []RunRecord
+	for rows.Next() {
+		var r RunRecord
+		if err := rows.Scan(&r.ID, &r.RunID, &r.StartedAt, &r.FinishedAt, &r.Campaign, &r.CrawlMode,
+			&r.MaxConcurrency, &r.RequestsPerSec, &r.PacingProfile,
+			&r.Processed, &r.Hits, &r.NoInfo, &r.Failed, &r.Requests, &r.RateLimited, &r.LoginAttempts, &r.TokensObtained, &r.Unfinished); err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// GetSessionReport builds the session report of run runID (the latest run
+// when runID is empty) with the topDomains domains that have the most hits.
+// hitFile, if set, adds country and connection stats to the domains.
+func (es *EmailStorage) GetSessionReport(runID string, topDomains int, hitFile string) (models.SessionReport, error) {
+	runs, err := es.GetRuns(0)
+	if err != nil {
+		return models.SessionReport{}, err
+	}
+	if len(runs) == 0 {
+		return models.SessionReport{}, fmt.Errorf("no runs recorded yet")
+	}
+
+	run := runs[0]
+	if runID != "" {
+		found := false
+		for _, r := range runs {
+			if r.RunID == runID {
+				run, found = r, true
+				break
+			}
+		}
+		if !found {
+			return models.SessionReport{}, fmt.Errorf("run %s not found", runID)
+		}
+	}
+
+	report := models.SessionReport{
+		GeneratedAt:    time.Now(),
+		RunID:          run.RunID,
+		StartedAt:      run.StartedAt,
+		FinishedAt:     run.FinishedAt,
+		Campaign:       run.Campaign,
+		CrawlMode:      run.CrawlMode,
+		MaxConcurrency: run.MaxConcurrency,
+		RequestsPerSec: run.RequestsPerSec,
+		PacingProfile:  run.PacingProfile,
+		Processed:      run.Processed,
+		Hits:           run.Hits,
+		NoInfo:         run.NoInfo,
+		Failed:         run.Failed,
+		Requests:       run.Requests,
+		RateLimited:    run.RateLimited,
+		LoginAttempts:  run.LoginAttempts,
+		TokensObtained: run.TokensObtained,
+	}
+
+	stats, err := es.GetEmailStats()
+	if err != nil {
+		return report, err
+	}
+	report.Pending = stats["pending"]
+
+	domains, err := es.GetDomainStats()
+	if err != nil {
+		return report, err
+	}
+	if len(domains) > topDomains {
+		domains = domains[:topDomains]
+	}
+	if hitFile != "" {
+		if hits, err := utils.ReadAllHitResults(hitFile); err == nil {
+			utils.ApplyHitProfileStats(domains, hits)
+		}
+	}
+	report.TopDomains = domains
+
+	return report, nil
+}
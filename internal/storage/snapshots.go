@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"linkedin-crawler/internal/utils"
+)
+
+// Files inside a snapshot directory
+const (
+	SnapshotDBFile      = "emails.db"
+	SnapshotResultsFile = "results.csv"
+)
+
+// snapshotLayout names snapshot directories so they sort by time
+const snapshotLayout = "20060102-150405"
+
+// Snapshot is one backup directory holding the database and a results export
+type Snapshot struct {
+	Name      string
+	Dir       string
+	CreatedAt time.Time
+	DBSize    int64
+}
+
+// DBPath returns the database file of the snapshot
+func (s Snapshot) DBPath() string {
+	return filepath.Join(s.Dir, SnapshotDBFile)
+}
+
+// CreateSnapshot backs up the database and exports all results as CSV into a
+// new timestamped directory under dir
+func (es *EmailStorage) CreateSnapshot(dir string) (*Snapshot, error) {
+	now := time.Now()
+	snapshot := &Snapshot{
+		Name:      now.Format(snapshotLayout),
+		CreatedAt: now,
+	}
+	snapshot.Dir = filepath.Join(dir, snapshot.Name)
+
+	if err := os.MkdirAll(snapshot.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	if err := es.BackupDB(snapshot.DBPath()); err != nil {
+		os.RemoveAll(snapshot.Dir)
+		return nil, err
+	}
+	snapshot.DBSize = fileSize(snapshot.DBPath())
+
+	results, err := es.GetResults()
+	if err != nil {
+		return snapshot, fmt.Errorf("failed to export results: %w", err)
+	}
+	fields, err := es.GetAllInputFields()
+	if err != nil {
+		return snapshot, fmt.Errorf("failed to export results: %w", err)
+	}
+	utils.AttachInputFields(results, fields)
+	data, err := utils.EncodeHits(results, utils.ExportFormatCSV, now)
+	if err != nil {
+		return snapshot, fmt.Errorf("failed to export results: %w", err)
+	}
+	if err := utils.WriteFileAtomic(filepath.Join(snapshot.Dir, SnapshotResultsFile), data); err != nil {
+		return snapshot, fmt.Errorf("failed to export results: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// ListSnapshots returns the snapshots under dir, newest first. A missing
+// directory has no snapshots.
+func ListSnapshots(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups directory: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		createdAt, err := time.ParseInLocation(snapshotLayout, entry.Name(), time.Local)
+		if err != nil {
+			continue // Không phải thư mục snapshot
+		}
+		snapshot := Snapshot{
+			Name:      entry.Name(),
+			Dir:       filepath.Join(dir, entry.Name()),
+			CreatedAt: createdAt,
+		}
+		snapshot.DBSize = fileSize(snapshot.DBPath())
+		if snapshot.DBSize == 0 {
+			continue // Snapshot dở dang
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name > snapshots[j].Name
+	})
+	return snapshots, nil
+}
+
+// PruneSnapshots keeps the newest keep snapshots under dir and deletes the
+// rest (keep <= 0 keeps all). Returns how many were deleted.
+func PruneSnapshots(dir string, keep int) (int, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+
+	snapshots, err := ListSnapshots(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, snapshot := range snapshots[min(keep, len(snapshots)):] {
+		if err := os.RemoveAll(snapshot.Dir); err != nil {
+			return removed, fmt.Errorf("failed to delete snapshot %s: %w", snapshot.Name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"linkedin-crawler/internal/config"
+	"linkedin-crawler/internal/models"
+	"linkedin-crawler/internal/utils"
+)
+
+// SampleAccounts is the commented accounts file of a new campaign
+const SampleAccounts = `# Microsoft Teams accounts used to obtain tokens
+# Format: email|password (one account per line, # starts a comment)
+# Ví dụ:
+# user1@example.com|password123
+# user2@example.com|mypassword456
+example@domain.com|yourpassword
+`
+
+// sampleEmails is the commented input file of a new campaign
+const sampleEmails = `# Target email addresses
+# One email per line, or a CSV with an "email" header and extra columns
+# (lead id, owner...) that are passed through to exports and webhooks
+example@example.com
+test@test.com
+`
+
+// writeSampleFile creates path with content unless it exists and reports
+// whether it was written
+func writeSampleFile(path, content string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to create sample file %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// CampaignLayout is what InitCampaign created in a campaign folder
+type CampaignLayout struct {
+	Dir     string
+	Created []string // File và thư mục mới tạo
+	Skipped []string // File đã có, giữ nguyên
+}
+
+// CampaignConfig returns cfg with every file path pointing into the campaign
+// folder dir, using the default file names
+func CampaignConfig(cfg models.Config, dir string) models.Config {
+	defaults := config.DefaultConfig()
+	cfg.EmailsFilePath = filepath.Join(dir, defaults.EmailsFilePath)
+	cfg.TokensFilePath = filepath.Join(dir, defaults.TokensFilePath)
+	cfg.AccountsFilePath = filepath.Join(dir, defaults.AccountsFilePath)
+	cfg.OutputFilePath = filepath.Join(dir, defaults.OutputFilePath)
+	cfg.DBPath = filepath.Join(dir, defaults.DBPath)
+	cfg.LogFilePath = filepath.Join(dir, defaults.LogFilePath)
+	cfg.BackupDir = filepath.Join(dir, defaults.BackupDir)
+	cfg.CaptureDir = filepath.Join(dir, defaults.CaptureDir)
+	return cfg
+}
+
+// InitCampaign creates a campaign folder: commented
+// accounts and input files and the backups and logs folders. Existing files
+// are kept unless overwrite is set.
+func InitCampaign(dir string, overwrite bool) (*CampaignLayout, error) {
+	cfg := CampaignConfig(config.DefaultConfig(), dir)
+	layout := &CampaignLayout{Dir: dir}
+
+	for _, d := range []string{dir, cfg.BackupDir, utils.RunLogDir(cfg.LogFilePath)} {
+		if _, err := os.Stat(d); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return layout, fmt.Errorf("failed to create directory %s: %w", d, err)
+		}
+		layout.Created = append(layout.Created, d+string(filepath.Separator))
+	}
+
+	files := []struct {
+		path    string
+		content string
+	}{
+		{cfg.AccountsFilePath, SampleAccounts},
+		{cfg.EmailsFilePath, sampleEmails},
+	}
+	for _, f := range files {
+		if overwrite {
+			os.Remove(f.path)
+		}
+		created, err := writeSampleFile(f.path, f.content)
+		if err != nil {
+			return layout, err
+		}
+		if created {
+			layout.Created = append(layout.Created, f.path)
+		} else {
+			layout.Skipped = append(layout.Skipped, f.path)
+		}
+	}
+	return layout, nil
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+)
+
+// TokenInvalidation is a token that stopped working during a run
+type TokenInvalidation struct {
+	RunID      string
+	TokenHash  string
+	Account    string // "" khi không rõ token lấy từ account nào
+	Cause      string // models.TokenInvalid*
+	HTTPStatus int
+	Requests   int
+	TokenAge   time.Duration // 0 khi không rõ thời điểm lấy token
+}
+
+// GetTokenOrigin returns the account a token was obtained from and when, from
+// the successful login that recorded its fingerprint; ok is false when the
+// token was not obtained by a recorded login
+func (es *EmailStorage) GetTokenOrigin(tokenHash string) (account string, obtainedAt time.Time, ok bool, err error) {
+	if err := es.ensureDB(); err != nil {
+		return "", time.Time{}, false, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return "", time.Time{}, false, fmt.Errorf("database is closed")
+	}
+
+	var createdAt string
+	err = es.db.QueryRow(
+		"SELECT account, created_at FROM login_attempts WHERE token_hash = ? AND success = 1 ORDER BY id DESC LIMIT 1",
+		tokenHash,
+	).Scan(&account, &createdAt)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, false, nil
+	}
+	if err != nil {
+		return "", time.Time{}, false, fmt.Errorf("failed to look up token origin: %w", err)
+	}
+	return account, parseSQLiteTime(createdAt), true, nil
+}
+
+// RecordTokenInvalidation stores an invalidated token and adds it to the
+// token health of its account. It returns how many of the account's tokens
+// were banned so far (0 when the account is unknown).
+func (es *EmailStorage) RecordTokenInvalidation(inv TokenInvalidation) (int, error) {
+	if err := es.ensureDB(); err != nil {
+		return 0, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return 0, fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var age interface{}
+	if inv.TokenAge > 0 {
+		age = int64(inv.TokenAge.Seconds())
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO token_invalidations (run_id, token_hash, account, cause, http_status, requests, token_age_seconds) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		nullString(inv.RunID), inv.TokenHash, nullString(inv.Account), inv.Cause, nullInt(inv.HTTPStatus), inv.Requests, age,
+	); err != nil {
+		return 0, fmt.Errorf("failed to record token invalidation: %w", err)
+	}
+
+	bans := 0
+	if inv.Account != "" {
+		expired, banned := 0, 0
+		if inv.Cause == models.TokenInvalidBanned {
+			banned = 1
+		} else {
+			expired = 1
+		}
+		// Account luôn đã có dòng khi lấy token, upsert cho token lấy trước khi có bảng accounts
+		if err := tx.QueryRow(`
+			INSERT INTO accounts (email, status, token_expiries, token_bans) VALUES (?, ?, ?, ?)
+			ON CONFLICT(email) DO UPDATE SET
+				token_expiries = accounts.token_expiries + excluded.token_expiries,
+				token_bans = accounts.token_bans + excluded.token_bans
+			RETURNING token_bans`,
+			strings.ToLower(strings.TrimSpace(inv.Account)), models.AccountStatusUsed, expired, banned,
+		).Scan(&bans); err != nil {
+			return 0, fmt.Errorf("failed to update account token health: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit token invalidation: %w", err)
+	}
+	return bans, nil
+}
+
+// MarkAccountBanned sets an account to models.AccountStatusBanned without
+// counting a login, so it is no longer selected or imported
+func (es *EmailStorage) MarkAccountBanned(email, detail string) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if _, err := es.db.Exec(
+		"UPDATE accounts SET status = ?, detail = ?, updated_at = CURRENT_TIMESTAMP WHERE email = ?",
+		models.AccountStatusBanned, nullString(detail), strings.ToLower(strings.TrimSpace(email)),
+	); err != nil {
+		return fmt.Errorf("failed to mark account banned: %w", err)
+	}
+	return nil
+}
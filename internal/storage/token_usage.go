@@ -0,0 +1,83 @@
+package storage
+
+import "fmt"
+
+// TokenUsage is what one run sent with one token
+type TokenUsage struct {
+	TokenHash string // utils.TokenFingerprint
+	Requests  int
+	Expired   bool // Token trả 401/424 và bị xoá
+}
+
+// TokenLifetime summarizes how long tokens lasted in earlier runs
+type TokenLifetime struct {
+	ExpiredTokens    int     // Số token đã hết hạn có số liệu
+	RequestsPerToken float64 // Trung bình số request trước khi token hết hạn
+	RequestsPerEmail float64 // Trung bình số request cho mỗi email, kể cả retry
+}
+
+// SaveTokenUsage stores the token usage of run runID
+func (es *EmailStorage) SaveTokenUsage(runID string, usage []TokenUsage) error {
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	tx, err := es.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, u := range usage {
+		if _, err := tx.Exec(
+			"INSERT INTO token_usage (run_id, token_hash, requests, expired) VALUES (?, ?, ?, ?)",
+			nullString(runID), u.TokenHash, u.Requests, u.Expired,
+		); err != nil {
+			return fmt.Errorf("failed to save token usage: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save token usage: %w", err)
+	}
+	return nil
+}
+
+// GetTokenLifetime aggregates token_usage and runs. A token used in several
+// runs counts once, with the requests of all of them.
+func (es *EmailStorage) GetTokenLifetime() (TokenLifetime, error) {
+	var lifetime TokenLifetime
+	if err := es.ensureDB(); err != nil {
+		return lifetime, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if err := es.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(AVG(requests), 0) FROM (
+			SELECT SUM(requests) AS requests FROM token_usage
+			GROUP BY token_hash HAVING MAX(expired) = 1
+		)`,
+	).Scan(&lifetime.ExpiredTokens, &lifetime.RequestsPerToken); err != nil {
+		return lifetime, fmt.Errorf("failed to read token usage: %w", err)
+	}
+
+	var requests, processed int
+	if err := es.db.QueryRow(
+		"SELECT COALESCE(SUM(requests), 0), COALESCE(SUM(processed), 0) FROM runs WHERE processed > 0",
+	).Scan(&requests, &processed); err != nil {
+		return lifetime, fmt.Errorf("failed to read runs: %w", err)
+	}
+	if processed > 0 {
+		lifetime.RequestsPerEmail = float64(requests) / float64(processed)
+	}
+
+	return lifetime, nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TuningProfile is a concurrency/RPS pair approved after a calibration burst
+type TuningProfile struct {
+	Name           string
+	MaxConcurrency int64
+	RequestsPerSec float64
+
+	// Số liệu của lần calibration
+	Emails      int
+	Requests    int
+	RateLimited int
+	AvgLatency  time.Duration
+	RunID       string
+	UpdatedAt   time.Time
+}
+
+// SaveTuningProfile stores p, replacing the profile of the same name
+func (es *EmailStorage) SaveTuningProfile(p TuningProfile) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if err := es.ensureDB(); err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if _, err := es.db.Exec(`
+		INSERT INTO tuning_profiles (name, max_concurrency, requests_per_sec, emails, requests, rate_limited, avg_latency_ms, run_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			max_concurrency = excluded.max_concurrency,
+			requests_per_sec = excluded.requests_per_sec,
+			emails = excluded.emails,
+			requests = excluded.requests,
+			rate_limited = excluded.rate_limited,
+			avg_latency_ms = excluded.avg_latency_ms,
+			run_id = excluded.run_id,
+			updated_at = CURRENT_TIMESTAMP`,
+		p.Name, p.MaxConcurrency, p.RequestsPerSec, p.Emails, p.Requests, p.RateLimited, p.AvgLatency.Milliseconds(), nullString(p.RunID),
+	); err != nil {
+		return fmt.Errorf("failed to save tuning profile: %w", err)
+	}
+	return nil
+}
+
+// GetTuningProfiles returns the saved profiles, most recently updated first
+func (es *EmailStorage) GetTuningProfiles() ([]TuningProfile, error) {
+	return es.queryTuningProfiles("ORDER BY updated_at DESC, name")
+}
+
+// GetTuningProfile returns the profile called name; ok is false when there is none
+func (es *EmailStorage) GetTuningProfile(name string) (TuningProfile, bool, error) {
+	profiles, err := es.queryTuningProfiles("WHERE name = ?", strings.TrimSpace(name))
+	if err != nil || len(profiles) == 0 {
+		return TuningProfile{}, false, err
+	}
+	return profiles[0], true, nil
+}
+
+// queryTuningProfiles selects profiles with the given WHERE/ORDER clause
+func (es *EmailStorage) queryTuningProfiles(clause string, args ...interface{}) ([]TuningProfile, error) {
+	if err := es.ensureDB(); err != nil {
+		return nil, fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	es.dbMutex.RLock()
+	defer es.dbMutex.RUnlock()
+
+	if es.isDBClosed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	rows, err := es.db.Query(`
+		SELECT name, max_concurrency, requests_per_sec, emails, requests, rate_limited, avg_latency_ms,
+			COALESCE(run_id, ''), COALESCE(updated_at, '')
+		FROM tuning_profiles `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tuning profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []TuningProfile
+	for rows.Next() {
+		var p TuningProfile
+		var latencyMs int64
+		var updatedAt string
+		if err := rows.Scan(&p.Name, &p.MaxConcurrency, &p.RequestsPerSec, &p.Emails, &p.Requests, &p.RateLimited,
+			&latencyMs, &p.RunID, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tuning profile: %w", err)
+		}
+		p.AvgLatency = time.Duration(latencyMs) * time.Millisecond
+		p.UpdatedAt = parseSQLiteTime(updatedAt)
+		profiles = append(profiles, p)
+	}
+	return profiles, rows.Err()
+}
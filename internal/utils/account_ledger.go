@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"linkedin-crawler/internal/models"
+)
+
+// FormatAccountLedgerCSV renders the account ledger as CSV, one row per
+// account and run
+func FormatAccountLedgerCSV(entries []models.AccountLedgerEntry) string {
+	var b strings.Builder
+
+	w := csv.NewWriter(&b)
+	w.Write([]string{"account", "run_id", "logins", "tokens", "emails_completed", "tokens_expired", "tokens_banned", "first_login", "last_login", "final_status"})
+	for _, e := range entries {
+		w.Write([]string{
+			e.Account,
+			e.RunID,
+			fmt.Sprintf("%d", e.Logins),
+			fmt.Sprintf("%d", e.Tokens),
+			fmt.Sprintf("%d", e.Emails),
+			fmt.Sprintf("%d", e.TokensExpired),
+			fmt.Sprintf("%d", e.TokensBanned),
+			e.FirstLogin.Format("2006-01-02 15:04:05"),
+			e.LastLogin.Format("2006-01-02 15:04:05"),
+			e.FinalStatus,
+		})
+	}
+	w.Flush()
+
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Console verbosity levels of the CLI
+const (
+	ConsoleQuiet   = -1 // Chỉ lỗi và cảnh báo (dòng bắt đầu bằng ❌ hoặc ⚠️)
+	ConsoleNormal  = 0
+	ConsoleVerbose = 1 // Thêm log từng email
+	ConsoleDebug   = 2 // Thêm từng request: lần thử, HTTP status, token
+)
+
+var consoleLevel int32
+
+// SetConsoleLevel sets how much the CLI prints
+func SetConsoleLevel(level int) {
+	atomic.StoreInt32(&consoleLevel, int32(level))
+}
+
+// ConsoleLevel returns the current console verbosity
+func ConsoleLevel() int {
+	return int(atomic.LoadInt32(&consoleLevel))
+}
+
+// ConsoleAtLeast reports whether messages of level should be printed
+func ConsoleAtLeast(level int) bool {
+	return ConsoleLevel() >= level
+}
+
+// FilterQuietConsole routes stdout through a filter that only lets error and
+// warning lines through. The returned function restores stdout and waits
+// until the filtered output is written.
+func FilterQuietConsole() (func(), error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter console: %w", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if IsAlertLine(scanner.Text()) {
+				fmt.Fprintln(stdout, scanner.Text())
+			}
+		}
+	}()
+
+	return func() {
+		os.Stdout = stdout
+		writer.Close()
+		<-done
+	}, nil
+}
+
+// IsAlertLine reports whether a console line is an error or warning, which
+// the crawler marks with ❌ or ⚠️
+func IsAlertLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "❌") || strings.HasPrefix(line, "⚠️")
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CrawlWindow is a daily time range (e.g. 01:00-06:00) in which crawling is
+// allowed. A range whose end is before its start wraps past midnight.
+type CrawlWindow struct {
+	start    time.Duration // Tính từ 00:00
+	end      time.Duration
+	location *time.Location
+}
+
+// ParseCrawlWindow parses "HH:MM" bounds and an IANA time zone (empty = local).
+// It returns nil, nil when both bounds are empty, i.e. crawling is always allowed.
+func ParseCrawlWindow(start, end, timeZone string) (*CrawlWindow, error) {
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if start == "" && end == "" {
+		return nil, nil
+	}
+	if start == "" || end == "" {
+		return nil, fmt.Errorf("crawl window needs both start and end")
+	}
+
+	startOffset, err := parseClock(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid crawl window start: %w", err)
+	}
+	endOffset, err := parseClock(end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid crawl window end: %w", err)
+	}
+	if startOffset == endOffset {
+		return nil, fmt.Errorf("crawl window start and end must differ")
+	}
+
+	location := time.Local
+	if tz := strings.TrimSpace(timeZone); tz != "" {
+		if location, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("invalid crawl window time zone: %w", err)
+		}
+	}
+
+	return &CrawlWindow{start: startOffset, end: endOffset, location: location}, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether now falls inside the window
+func (cw *CrawlWindow) Contains(now time.Time) bool {
+	if cw == nil {
+		return true
+	}
+	offset := cw.offset(now)
+	if cw.start < cw.end {
+		return offset >= cw.start && offset < cw.end
+	}
+	return offset >= cw.start || offset < cw.end
+}
+
+// NextOpen returns when the window next opens after now
+func (cw *CrawlWindow) NextOpen(now time.Time) time.Time {
+	return cw.next(now, cw.start)
+}
+
+// NextClose returns when the window next closes after now
+func (cw *CrawlWindow) NextClose(now time.Time) time.Time {
+	return cw.next(now, cw.end)
+}
+
+// String formats the window as "01:00-06:00 Asia/Ho_Chi_Minh"
+func (cw *CrawlWindow) String() string {
+	if cw == nil {
+		return "always"
+	}
+	return fmt.Sprintf("%s-%s %s", formatClock(cw.start), formatClock(cw.end), cw.location)
+}
+
+// offset returns the time of day of now in the window's time zone
+func (cw *CrawlWindow) offset(now time.Time) time.Duration {
+	local := now.In(cw.location)
+	return time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+}
+
+// next returns the first time after now whose time of day is offset
+func (cw *CrawlWindow) next(now time.Time, offset time.Duration) time.Time {
+	local := now.In(cw.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, cw.location)
+	candidate := clockOn(midnight, offset)
+	if !candidate.After(now) {
+		candidate = clockOn(midnight.AddDate(0, 0, 1), offset)
+	}
+	return candidate
+}
+
+// clockOn builds the wall-clock time offset on the day of midnight, so DST
+// changes don't shift the window
+func clockOn(midnight time.Time, offset time.Duration) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, midnight.Location())
+}
+
+// formatClock formats an offset from midnight as HH:MM
+func formatClock(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
@@ -15,7 +15,18 @@ type HitResult struct {
 	LinkedInURL string
 	Location    string
 	Connections string
-	Timestamp   time.Time // For tracking when added
+	Source      string            // email hoặc simulated, rỗng với file cũ
+	Timestamp   time.Time         // For tracking when added
+	Fields      map[string]string // Cột thêm của file input (lead id, owner...), không lưu trong hit file
+	Headline    string            // Chỉ có trong database, khi crawl với ExtendedProfile
+	Company     string
+
+	// Workflow do người dùng sửa trong tab Results, chỉ có trong database
+	WorkflowStatus string // models.ResultStatus*, rỗng khi không đọc từ database
+	Tags           []string
+	Notes          string
+	ClaimedBy      string // Reviewer đang giữ kết quả (storage.ClaimResults), rỗng nếu chưa ai claim
+	ClaimedAt      time.Time
 }
 
 // DeduplicateHitFile removes duplicate entries from hit.txt file
@@ -97,7 +108,7 @@ func readHitFile(filePath string) ([]HitResult, error) {
 			continue
 		}
 
-		// Parse line: email|name|linkedin_url|location|connections
+		// Parse line: email|name|linkedin_url|location|connections[|source]
 		parts := strings.Split(line, "|")
 		if len(parts) < 5 {
 			fmt.Printf("⚠️ Line %d: Invalid format, skipping: %s\n", lineNum, line)
@@ -112,6 +123,9 @@ func readHitFile(filePath string) ([]HitResult, error) {
 			Connections: strings.TrimSpace(parts[4]),
 			Timestamp:   time.Now(), // Use current time as default
 		}
+		if len(parts) >= 6 {
+			entry.Source = strings.TrimSpace(parts[5])
+		}
 
 		// Basic validation
 		if entry.Email == "" {
@@ -141,31 +155,47 @@ func writeHitFile(filePath string, entries []HitResult) error {
 		}
 	}
 
-	// Write deduplicated entries
-	file, err := os.Create(filePath)
+	// Write deduplicated entries (temp file + rename)
+	file, err := CreateAtomic(filePath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer file.Abort()
 
 	writer := bufio.NewWriter(file)
-	defer writer.Flush()
 
 	// Write header
 	writer.WriteString("# LinkedIn Profile Results\n")
 	writer.WriteString(fmt.Sprintf("# Generated: %s\n", time.Now().Format("2006-01-02 15:04:05")))
 	writer.WriteString(fmt.Sprintf("# Total entries: %d\n", len(entries)))
-	writer.WriteString("# Format: email|name|linkedin_url|location|connections\n")
+	writer.WriteString("# Format: email|name|linkedin_url|location|connections|source\n")
 	writer.WriteString("\n")
 
 	// Write entries
 	for _, entry := range entries {
-		line := fmt.Sprintf("%s|%s|%s|%s|%s\n",
-			entry.Email, entry.Name, entry.LinkedInURL, entry.Location, entry.Connections)
+		source := entry.Source
+		if source == "" {
+			source = "email"
+		}
+		line := fmt.Sprintf("%s|%s|%s|%s|%s|%s\n",
+			entry.Email, entry.Name, entry.LinkedInURL, entry.Location, entry.Connections, source)
 		writer.WriteString(line)
 	}
 
-	return nil
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return file.Commit()
+}
+
+// ReadHitResults reads all entries of a hit file
+func ReadHitResults(filePath string) ([]HitResult, error) {
+	return readHitFile(filePath)
+}
+
+// WriteHitResults rewrites a hit file (with backup) from entries
+func WriteHitResults(filePath string, entries []HitResult) error {
+	return writeHitFile(filePath, entries)
 }
 
 // copyFile creates a copy of a file
@@ -231,10 +261,8 @@ func GetHitFileStats(filePath string) (map[string]int, error) {
 	return stats, nil
 }
 
-// AutoDeduplicateOnStartup automatically deduplicates hit.txt on application startup
-func AutoDeduplicateOnStartup() {
-	filePath := "hit.txt"
-
+// AutoDeduplicateOnStartup automatically deduplicates the hit file on application startup
+func AutoDeduplicateOnStartup(filePath string) {
 	// Check if file exists and has content
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return // File doesn't exist, nothing to deduplicate
@@ -243,13 +271,13 @@ func AutoDeduplicateOnStartup() {
 	// Get stats before deduplication
 	statsBefore, err := GetHitFileStats(filePath)
 	if err != nil {
-		fmt.Printf("⚠️ Could not analyze hit.txt: %v\n", err)
+		fmt.Printf("⚠️ Could not analyze %s: %v\n", filePath, err)
 		return
 	}
 
 	// Only deduplicate if there are duplicates
 	if statsBefore["duplicates"] > 0 {
-		fmt.Printf("🔄 Auto-deduplicating hit.txt: %d duplicates detected\n", statsBefore["duplicates"])
+		fmt.Printf("🔄 Auto-deduplicating %s: %d duplicates detected\n", filePath, statsBefore["duplicates"])
 
 		err := DeduplicateHitFile(filePath)
 		if err != nil {
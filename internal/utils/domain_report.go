@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+)
+
+// FormatDomainReportCSV renders domain stats as CSV
+func FormatDomainReportCSV(stats []models.DomainStat) string {
+	var b strings.Builder
+
+	w := csv.NewWriter(&b)
+	w.Write([]string{"domain", "total", "success", "failed", "pending", "hits", "hit_rate", "top_country", "avg_connections"})
+	for _, d := range stats {
+		w.Write([]string{
+			d.Domain,
+			fmt.Sprintf("%d", d.Total),
+			fmt.Sprintf("%d", d.Success),
+			fmt.Sprintf("%d", d.Failed),
+			fmt.Sprintf("%d", d.Pending),
+			fmt.Sprintf("%d", d.Hits),
+			fmt.Sprintf("%.1f", d.HitRate()),
+			d.TopCountry,
+			fmt.Sprintf("%.0f", d.AvgConnections),
+		})
+	}
+	w.Flush()
+
+	return b.String()
+}
+
+// ApplyHitProfileStats fills the country and connection columns of stats
+// from the structured fields of hit file entries
+func ApplyHitProfileStats(stats []models.DomainStat, hits []HitResult) {
+	type agg struct {
+		countries   map[string]int
+		connections int
+		counted     int
+	}
+	byDomain := make(map[string]*agg)
+
+	for _, hit := range hits {
+		at := strings.LastIndex(hit.Email, "@")
+		if at < 0 {
+			continue
+		}
+		domain := strings.ToLower(hit.Email[at+1:])
+		a := byDomain[domain]
+		if a == nil {
+			a = &agg{countries: make(map[string]int)}
+			byDomain[domain] = a
+		}
+		if country, _ := NormalizeLocation(hit.Location); country != "" {
+			a.countries[country]++
+		}
+		if n := ParseConnections(hit.Connections); n >= 0 {
+			a.connections += n
+			a.counted++
+		}
+	}
+
+	for i := range stats {
+		a := byDomain[strings.ToLower(stats[i].Domain)]
+		if a == nil {
+			continue
+		}
+		best := 0
+		for country, count := range a.countries {
+			if count > best || (count == best && country < stats[i].TopCountry) {
+				stats[i].TopCountry, best = country, count
+			}
+		}
+		if a.counted > 0 {
+			stats[i].AvgConnections = float64(a.connections) / float64(a.counted)
+		}
+	}
+}
+
+// WriteDomainReportCSV writes domain stats as a CSV file
+func WriteDomainReportCSV(filePath string, stats []models.DomainStat) error {
+	if err := WriteFileAtomic(filePath, []byte(FormatDomainReportCSV(stats))); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// FormatDomainReportMarkdown renders domain stats as a markdown table
+func FormatDomainReportMarkdown(stats []models.DomainStat) string {
+	var b strings.Builder
+
+	totalEmails, totalHits := 0, 0
+	for _, d := range stats {
+		totalEmails += d.Total
+		totalHits += d.Hits
+	}
+
+	b.WriteString("# Domain Summary\n\n")
+	b.WriteString(fmt.Sprintf("Generated: %s  \n", time.Now().Format("2006-01-02 15:04:05")))
+	b.WriteString(fmt.Sprintf("Domains: %d | Emails: %d | Hits: %d\n\n", len(stats), totalEmails, totalHits))
+	b.WriteString("| Domain | Total | Success | Failed | Pending | Hits | Hit rate | Top country | Avg connections |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|---:|---|---:|\n")
+	for _, d := range stats {
+		b.WriteString(fmt.Sprintf("| %s | %d | %d | %d | %d | %d | %.1f%% | %s | %.0f |\n",
+			d.Domain, d.Total, d.Success, d.Failed, d.Pending, d.Hits, d.HitRate(), d.TopCountry, d.AvgConnections))
+	}
+
+	return b.String()
+}
+
+// WriteDomainReportMarkdown writes domain stats as a markdown file
+func WriteDomainReportMarkdown(filePath string, stats []models.DomainStat) error {
+	if err := WriteFileAtomic(filePath, []byte(FormatDomainReportMarkdown(stats))); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AppendNewEmails appends the emails not already listed in the emails file
+// (case-insensitive) and returns how many were added. The file is created
+// when missing.
+func AppendNewEmails(filePath string, emails []string) (int, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read emails file: %w", err)
+	}
+
+	existing := make(map[string]bool)
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		for _, email := range ExtractEmailsFromLine(scanner.Text()) {
+			existing[NormalizeEmail(email)] = true
+		}
+	}
+
+	var added []string
+	for _, email := range emails {
+		email = NormalizeEmail(email)
+		if email == "" || existing[email] {
+			continue
+		}
+		existing[email] = true
+		added = append(added, email)
+	}
+	if len(added) == 0 {
+		return 0, nil
+	}
+
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	data = append(data, strings.Join(added, "\n")+"\n"...)
+	if err := WriteFileAtomic(filePath, data); err != nil {
+		return 0, fmt.Errorf("failed to write emails file: %w", err)
+	}
+	return len(added), nil
+}
+
+// ReplaceEmailInFile replaces the lines of the emails file holding oldEmail
+// (case-insensitive) with newEmail, or removes them when newEmail is empty.
+// Comments and other lines are kept as they are. Returns whether any line
+// matched.
+func ReplaceEmailInFile(filePath, oldEmail, newEmail string) (bool, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read emails file: %w", err)
+	}
+
+	oldEmail = NormalizeEmail(oldEmail)
+	lines := strings.Split(string(data), "\n")
+	kept := make([]string, 0, len(lines))
+	found := false
+	for _, line := range lines {
+		if NormalizeEmail(strings.TrimSuffix(line, "\r")) != oldEmail {
+			kept = append(kept, line)
+			continue
+		}
+		// Nhiều dòng trùng email cũ thì chỉ giữ một dòng mới
+		if newEmail != "" && !found {
+			kept = append(kept, newEmail)
+		}
+		found = true
+	}
+	if !found {
+		return false, nil
+	}
+
+	if err := WriteFileAtomic(filePath, []byte(strings.Join(kept, "\n"))); err != nil {
+		return false, fmt.Errorf("failed to write emails file: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Split modes
+const (
+	SplitModeBalanced = "balanced" // Chia liên tiếp, các shard chênh nhau tối đa 1 dòng
+	SplitModeDomain   = "domain"   // Cùng domain luôn vào cùng shard (hash của domain)
+)
+
+// SplitOptions controls how an email file is sharded
+type SplitOptions struct {
+	Shards   int
+	ByDomain bool
+	OutDir   string
+}
+
+// ShardManifest describes one shard; it is written next to the shard as
+// <shard>.manifest.json
+type ShardManifest struct {
+	Source    string    `json:"source"`
+	Shard     int       `json:"shard"` // Bắt đầu từ 1
+	Shards    int       `json:"shards"`
+	Mode      string    `json:"mode"`
+	File      string    `json:"file"`
+	Count     int       `json:"count"`
+	Domains   int       `json:"domains"`
+	SHA256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SplitReport summarizes a split
+type SplitReport struct {
+	Read       int // Dòng dữ liệu đọc được (bỏ dòng trống, comment)
+	Duplicates int
+	Shards     []ShardManifest
+}
+
+// SplitEmailFile shards the lines of an emails file into opts.Shards files in
+// opts.OutDir, dropping duplicate lines.
+// Each shard gets a manifest and a sha256sum file, so it can be served to
+// `crawl --emails <url>` on another machine.
+func SplitEmailFile(path string, opts SplitOptions) (*SplitReport, error) {
+	if opts.Shards < 2 {
+		return nil, fmt.Errorf("number of shards must be at least 2")
+	}
+	if opts.OutDir == "" {
+		opts.OutDir = "shards"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open emails file: %w", err)
+	}
+	defer file.Close()
+
+	report := &SplitReport{}
+	seen := make(map[string]bool)
+	var lines []string
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		report.Read++
+		key := strings.ToLower(line)
+		if seen[key] {
+			report.Duplicates++
+			continue
+		}
+		seen[key] = true
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read emails file: %w", err)
+	}
+
+	shards := make([][]string, opts.Shards)
+	mode := SplitModeBalanced
+	if opts.ByDomain {
+		mode = SplitModeDomain
+		for _, line := range lines {
+			hash := fnv.New32a()
+			hash.Write([]byte(lineDomain(line)))
+			index := int(hash.Sum32() % uint32(opts.Shards))
+			shards[index] = append(shards[index], line)
+		}
+	} else {
+		size, extra := len(lines)/opts.Shards, len(lines)%opts.Shards
+		start := 0
+		for i := range shards {
+			end := start + size
+			if i < extra {
+				end++
+			}
+			shards[i] = lines[start:end]
+			start = end
+		}
+	}
+
+	if err := os.MkdirAll(opts.OutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	width := len(fmt.Sprint(opts.Shards))
+	now := time.Now()
+	for i, shard := range shards {
+		name := fmt.Sprintf("%s-%0*d-of-%d.txt", base, width, i+1, opts.Shards)
+		shardPath := filepath.Join(opts.OutDir, name)
+
+		content := []byte(strings.Join(shard, "\n") + "\n")
+		if len(shard) == 0 {
+			content = nil
+		}
+		if err := WriteFileAtomic(shardPath, content); err != nil {
+			return report, fmt.Errorf("failed to write shard %s: %w", name, err)
+		}
+
+		sum := sha256.Sum256(content)
+		domains := make(map[string]bool)
+		for _, line := range shard {
+			domains[lineDomain(line)] = true
+		}
+		manifest := ShardManifest{
+			Source:    path,
+			Shard:     i + 1,
+			Shards:    opts.Shards,
+			Mode:      mode,
+			File:      name,
+			Count:     len(shard),
+			Domains:   len(domains),
+			SHA256:    hex.EncodeToString(sum[:]),
+			CreatedAt: now,
+		}
+
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return report, fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		if err := WriteFileAtomic(shardPath+".manifest.json", data); err != nil {
+			return report, fmt.Errorf("failed to write manifest for %s: %w", name, err)
+		}
+		if err := WriteFileAtomic(shardPath+".sha256", []byte(manifest.SHA256+"  "+name+"\n")); err != nil {
+			return report, fmt.Errorf("failed to write checksum for %s: %w", name, err)
+		}
+		report.Shards = append(report.Shards, manifest)
+	}
+
+	return report, nil
+}
+
+// lineDomain returns the lowercased domain of an email line, or the whole
+// line when it has no @
+func lineDomain(line string) string {
+	line = strings.ToLower(line)
+	if at := strings.LastIndex(line, "@"); at >= 0 {
+		domain := line[at+1:]
+		// Dòng CSV: domain kết thúc trước dấu phẩy đầu tiên
+		if comma := strings.Index(domain, ","); comma >= 0 {
+			domain = domain[:comma]
+		}
+		return strings.TrimSpace(domain)
+	}
+	return line
+}
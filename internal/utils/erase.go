@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ErasedMarker replaces the lines of redacted files that mentioned an erased address
+const ErasedMarker = "[erased]"
+
+// AddressPattern returns a case-insensitive pattern matching any of emails as
+// a whole address, so bob@x.com does not match jbob@x.com or bob@x.com.vn
+func AddressPattern(emails []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(emails))
+	for _, email := range emails {
+		if email = strings.TrimSpace(email); email != "" {
+			quoted = append(quoted, regexp.QuoteMeta(email))
+		}
+	}
+	return regexp.MustCompile(`(?i)(^|[^a-z0-9._%+-])(` + strings.Join(quoted, "|") + `)($|[^a-z0-9_.-]|\.$|\.[^a-z0-9])`)
+}
+
+// EraseLines removes the lines of path that match pattern and returns how
+// many were removed; the file is only rewritten when something matched
+func EraseLines(path string, pattern *regexp.Regexp) (int, error) {
+	return rewriteLines(path, func(line string) (string, bool) {
+		if pattern.MatchString(line) {
+			return "", false
+		}
+		return line, true
+	})
+}
+
+// RedactFile replaces the lines of path that match pattern with
+// ErasedMarker, so names and URLs logged next to an address go too, and
+// returns how many lines changed
+func RedactFile(path string, pattern *regexp.Regexp) (int, error) {
+	return rewriteLines(path, func(line string) (string, bool) {
+		if pattern.MatchString(line) {
+			return ErasedMarker, true
+		}
+		return line, true
+	})
+}
+
+// rewriteLines passes every line of path through edit (keep = false drops
+// it) and atomically replaces the file when any line changed
+func rewriteLines(path string, edit func(line string) (string, bool)) (int, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer in.Close()
+
+	out, err := CreateAtomic(path)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Abort()
+	writer := bufio.NewWriter(out)
+
+	changed := 0
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		edited, keep := edit(line)
+		if !keep || edited != line {
+			changed++
+		}
+		if keep {
+			writer.WriteString(edited)
+			writer.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	in.Close()
+	if err := out.Commit(); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// FileMentions reports whether the content of path matches pattern
+func FileMentions(path string, pattern *regexp.Regexp) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && pattern.Match(data)
+}
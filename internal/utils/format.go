@@ -1,9 +1,14 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"strings"
 	"time"
+
+	"linkedin-crawler/internal/models"
 )
 
 // PrintErr prints error message to stderr
@@ -27,3 +32,27 @@ func FormatDuration(d time.Duration) string {
 		return fmt.Sprintf("%dh%dm", h, m)
 	}
 }
+
+// TokenFingerprint returns a short non-reversible id for a token, safe to log
+func TokenFingerprint(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// FormatErrorClassStats formats failed email counts by error class on one
+// line, e.g. "429: 3 | timeout: 12", in models.EmailErrorClasses order
+func FormatErrorClassStats(stats map[string]int) string {
+	var parts []string
+	for _, class := range models.EmailErrorClasses {
+		if stats[class] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", class, stats[class]))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,303 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Export formats supported by EncodeHits
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
+
+// ExportState is the watermark of hits already handed out by "Export new"
+type ExportState struct {
+	LastExportAt time.Time       `json:"last_export_at"`
+	Exports      int             `json:"exports"`
+	Exported     map[string]bool `json:"exported"` // email (lowercase) → đã export
+
+	path string
+}
+
+// hitExportRecord is one JSONL line of an export
+type hitExportRecord struct {
+	Email       string            `json:"email"`
+	Name        string            `json:"name"`
+	LinkedInURL string            `json:"linkedin_url"`
+	Location    string            `json:"location"`
+	Connections string            `json:"connections"`
+	Headline    string            `json:"headline,omitempty"`
+	Company     string            `json:"company,omitempty"`
+	Source      string            `json:"source"`
+	ExportedAt  string            `json:"exported_at"`
+	Status      string            `json:"status,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Notes       string            `json:"notes,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+}
+
+// ExportStatePath returns the watermark file kept next to a hit file
+func ExportStatePath(hitFilePath string) string {
+	dir, base := filepath.Split(hitFilePath)
+	return filepath.Join(dir, "."+base+".export.json")
+}
+
+// LoadExportState reads the watermark for hitFilePath; a missing file is an empty state
+func LoadExportState(hitFilePath string) (*ExportState, error) {
+	state := &ExportState{
+		Exported: make(map[string]bool),
+		path:     ExportStatePath(hitFilePath),
+	}
+
+	data, err := os.ReadFile(state.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse export state %s: %w", state.path, err)
+	}
+	if state.Exported == nil {
+		state.Exported = make(map[string]bool)
+	}
+
+	return state, nil
+}
+
+// NewHits returns the entries not yet covered by the watermark
+func (s *ExportState) NewHits(entries []HitResult) []HitResult {
+	var fresh []HitResult
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		key := strings.ToLower(strings.TrimSpace(entry.Email))
+		if s.Exported[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		fresh = append(fresh, entry)
+	}
+	return fresh
+}
+
+// MarkExported advances the watermark past entries and saves it
+func (s *ExportState) MarkExported(entries []HitResult, at time.Time) error {
+	for _, entry := range entries {
+		s.Exported[strings.ToLower(strings.TrimSpace(entry.Email))] = true
+	}
+	s.LastExportAt = at
+	s.Exports++
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export state: %w", err)
+	}
+	return WriteFileAtomic(s.path, data)
+}
+
+// Reset clears the watermark so the next export includes every hit
+func (s *ExportState) Reset() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to reset export state: %w", err)
+	}
+	s.Exported = make(map[string]bool)
+	s.LastExportAt = time.Time{}
+	s.Exports = 0
+	return nil
+}
+
+// ExportFormatFromPath picks JSONL for .jsonl/.ndjson files and CSV otherwise
+func ExportFormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		return ExportFormatJSONL
+	default:
+		return ExportFormatCSV
+	}
+}
+
+// AttachInputFields sets the custom input fields of each entry from fields,
+// keyed by lowercase email
+func AttachInputFields(entries []HitResult, fields map[string]map[string]string) {
+	for i := range entries {
+		entries[i].Fields = fields[strings.ToLower(strings.TrimSpace(entries[i].Email))]
+	}
+}
+
+// InputFieldNames returns the sorted names of the custom fields of entries
+func InputFieldNames(entries []HitResult) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, entry := range entries {
+		for name := range entry.Fields {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatInputFields renders fields as "name=value; name=value", sorted by name
+func FormatInputFields(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + fields[name]
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FormatTags joins tags for a single export column
+func FormatTags(tags []string) string {
+	return strings.Join(tags, "; ")
+}
+
+// DropNotes clears the notes of entries, for exports without notes
+func DropNotes(entries []HitResult) {
+	for i := range entries {
+		entries[i].Notes = ""
+	}
+}
+
+// EncodeHits renders entries as CSV (with header) or JSONL. Headline and
+// Company columns follow Connections when any entry has them (extended
+// profiles); the workflow status and tags follow the standard columns, then a
+// Notes column when any entry has notes; custom input fields become a
+// "fields" object in JSONL and extra CSV columns.
+func EncodeHits(entries []HitResult, format string, exportedAt time.Time) ([]byte, error) {
+	sorted := append([]HitResult(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Email) < strings.ToLower(sorted[j].Email)
+	})
+	stamp := exportedAt.Format(time.RFC3339)
+
+	var buf bytes.Buffer
+	switch format {
+	case ExportFormatJSONL:
+		encoder := json.NewEncoder(&buf)
+		for _, entry := range sorted {
+			record := hitExportRecord{
+				Email:       entry.Email,
+				Name:        entry.Name,
+				LinkedInURL: entry.LinkedInURL,
+				Location:    entry.Location,
+				Connections: entry.Connections,
+				Headline:    entry.Headline,
+				Company:     entry.Company,
+				Source:      hitSource(entry),
+				ExportedAt:  stamp,
+				Status:      entry.WorkflowStatus,
+				Tags:        entry.Tags,
+				Notes:       entry.Notes,
+				Fields:      entry.Fields,
+			}
+			if err := encoder.Encode(record); err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", entry.Email, err)
+			}
+		}
+	case ExportFormatCSV:
+		fieldNames := InputFieldNames(sorted)
+		withNotes, withDetails := false, false
+		for _, entry := range sorted {
+			withNotes = withNotes || entry.Notes != ""
+			withDetails = withDetails || entry.Headline != "" || entry.Company != ""
+		}
+
+		header := []string{"Email", "Name", "LinkedIn URL", "Location", "Connections"}
+		if withDetails {
+			header = append(header, "Headline", "Company")
+		}
+		header = append(header, "Source", "Exported At", "Status", "Tags")
+		if withNotes {
+			header = append(header, "Notes")
+		}
+		writer := csv.NewWriter(&buf)
+		writer.Write(append(header, fieldNames...))
+		for _, entry := range sorted {
+			record := []string{entry.Email, entry.Name, entry.LinkedInURL, entry.Location, entry.Connections}
+			if withDetails {
+				record = append(record, entry.Headline, entry.Company)
+			}
+			record = append(record, hitSource(entry), stamp, entry.WorkflowStatus, FormatTags(entry.Tags))
+			if withNotes {
+				record = append(record, entry.Notes)
+			}
+			for _, name := range fieldNames {
+				record = append(record, entry.Fields[name])
+			}
+			writer.Write(record)
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write CSV: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown export format: %s", format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ExportNewHits writes hits found since the last export to outPath and
+// advances the watermark. annotate (may be nil) adds what the hit file does
+// not hold: workflow status, tags and custom input fields.
+func ExportNewHits(hitFilePath, outPath string, annotate func(entries []HitResult)) (int, error) {
+	entries, err := ReadAllHitResults(hitFilePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", hitFilePath, err)
+	}
+
+	state, err := LoadExportState(hitFilePath)
+	if err != nil {
+		return 0, err
+	}
+
+	fresh := state.NewHits(entries)
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+
+	if annotate != nil {
+		annotate(fresh)
+	}
+	now := time.Now()
+	data, err := EncodeHits(fresh, ExportFormatFromPath(outPath), now)
+	if err != nil {
+		return 0, err
+	}
+	if err := WriteFileAtomic(outPath, data); err != nil {
+		return 0, err
+	}
+
+	// Chỉ cập nhật watermark sau khi file export đã ghi xong
+	if err := state.MarkExported(fresh, now); err != nil {
+		return len(fresh), err
+	}
+
+	return len(fresh), nil
+}
+
+// hitSource defaults an empty source to "email" like the hit file writer
+func hitSource(entry HitResult) string {
+	if entry.Source == "" {
+		return "email"
+	}
+	return entry.Source
+}
@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MergeOptions controls how result files are merged
+type MergeOptions struct {
+	DedupeByURL bool // Giữ 1 dòng cho mỗi LinkedIn URL (ngoài dedupe theo email)
+}
+
+// MergeFileStat is the per-input part of a merge report
+type MergeFileStat struct {
+	Path  string
+	Read  int
+	Kept  int
+	Error string
+}
+
+// MergeReport summarizes a merge of several result files
+type MergeReport struct {
+	Files           []MergeFileStat
+	TotalRead       int
+	EmailDuplicates int
+	URLDuplicates   int
+	Conflicts       []string // Cùng email nhưng khác LinkedIn URL
+	Output          int
+	OutputPath      string
+}
+
+// ReadResultFile reads a hit.txt style file or a CSV exported from the Results tab
+func ReadResultFile(filePath string) ([]HitResult, error) {
+	if strings.EqualFold(filepath.Ext(filePath), ".csv") {
+		return readResultCSV(filePath)
+	}
+	return readHitFile(filePath)
+}
+
+// readResultCSV parses a CSV with a header row (Email, Name, LinkedIn URL, ...)
+func readResultCSV(filePath string) ([]HitResult, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	// Map header → index, không phân biệt hoa thường / khoảng trắng / gạch dưới
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		key := strings.NewReplacer(" ", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+		columns[key] = i
+	}
+	emailCol, ok := columns["email"]
+	if !ok {
+		return nil, fmt.Errorf("CSV has no email column")
+	}
+
+	field := func(record []string, names ...string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+		}
+		return ""
+	}
+
+	var entries []HitResult
+	for _, record := range records[1:] {
+		if emailCol >= len(record) || strings.TrimSpace(record[emailCol]) == "" {
+			continue
+		}
+		entry := HitResult{
+			Email:       strings.TrimSpace(record[emailCol]),
+			Name:        field(record, "name"),
+			LinkedInURL: field(record, "linkedinurl", "url"),
+			Location:    field(record, "location"),
+			Connections: field(record, "connections"),
+			Source:      field(record, "source"),
+			Timestamp:   time.Now(),
+		}
+		if ts := field(record, "timestamp"); ts != "" {
+			if parsed, err := time.Parse("2006-01-02 15:04:05", ts); err == nil {
+				entry.Timestamp = parsed
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// MergeHitFiles merges result files into outPath, deduplicating by email
+// (and optionally LinkedIn URL), and returns a report of what was kept
+func MergeHitFiles(paths []string, outPath string, opts MergeOptions) (*MergeReport, error) {
+	report := &MergeReport{OutputPath: outPath}
+
+	byEmail := make(map[string]HitResult)
+	origin := make(map[string]int) // email key → index file đã đóng góp entry
+	var order []string
+
+	for fileIndex, path := range paths {
+		stat := MergeFileStat{Path: path}
+		entries, err := ReadResultFile(path)
+		if err != nil {
+			stat.Error = err.Error()
+			report.Files = append(report.Files, stat)
+			continue
+		}
+		stat.Read = len(entries)
+		report.TotalRead += len(entries)
+
+		for _, entry := range entries {
+			entry.LinkedInURL = NormalizeLinkedInURL(entry.LinkedInURL)
+			key := strings.ToLower(strings.TrimSpace(entry.Email))
+
+			existing, exists := byEmail[key]
+			if !exists {
+				byEmail[key] = entry
+				origin[key] = fileIndex
+				order = append(order, key)
+				continue
+			}
+
+			report.EmailDuplicates++
+			if hasLinkedInURL(existing) && hasLinkedInURL(entry) && existing.LinkedInURL != entry.LinkedInURL {
+				report.Conflicts = append(report.Conflicts,
+					fmt.Sprintf("%s: %s vs %s", entry.Email, existing.LinkedInURL, entry.LinkedInURL))
+			}
+			if betterHit(entry, existing) {
+				byEmail[key] = entry
+				origin[key] = fileIndex
+			}
+		}
+		report.Files = append(report.Files, stat)
+	}
+
+	merged := make([]HitResult, 0, len(order))
+	seenURL := make(map[string]bool)
+	for _, key := range order {
+		entry := byEmail[key]
+		if opts.DedupeByURL && hasLinkedInURL(entry) {
+			if seenURL[entry.LinkedInURL] {
+				report.URLDuplicates++
+				continue
+			}
+			seenURL[entry.LinkedInURL] = true
+		}
+		merged = append(merged, entry)
+		report.Files[fileStatIndex(report.Files, paths[origin[key]])].Kept++
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return strings.ToLower(merged[i].Email) < strings.ToLower(merged[j].Email)
+	})
+
+	if err := writeHitFile(outPath, merged); err != nil {
+		return report, fmt.Errorf("failed to write merged file: %w", err)
+	}
+	report.Output = len(merged)
+
+	return report, nil
+}
+
+// FormatMergeReport renders a merge report as markdown
+func FormatMergeReport(report *MergeReport) string {
+	var b strings.Builder
+
+	b.WriteString("# Merge Report\n\n")
+	b.WriteString(fmt.Sprintf("Generated: %s  \n", time.Now().Format("2006-01-02 15:04:05")))
+	b.WriteString(fmt.Sprintf("Output: %s (%d entries)\n\n", report.OutputPath, report.Output))
+	b.WriteString("| File | Read | Kept | Error |\n")
+	b.WriteString("|---|---:|---:|---|\n")
+	for _, f := range report.Files {
+		b.WriteString(fmt.Sprintf("| %s | %d | %d | %s |\n", f.Path, f.Read, f.Kept, f.Error))
+	}
+	b.WriteString(fmt.Sprintf("\nTotal read: %d  \nDuplicate emails: %d  \nDuplicate URLs: %d  \n",
+		report.TotalRead, report.EmailDuplicates, report.URLDuplicates))
+
+	if len(report.Conflicts) > 0 {
+		b.WriteString(fmt.Sprintf("\n## Conflicts (%d)\n\n", len(report.Conflicts)))
+		for _, c := range report.Conflicts {
+			b.WriteString("- " + c + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// hasLinkedInURL reports whether an entry carries a usable profile URL
+func hasLinkedInURL(entry HitResult) bool {
+	return entry.LinkedInURL != "" && entry.LinkedInURL != "N/A"
+}
+
+// betterHit prefers entries with a URL, then with more filled fields, then newer
+func betterHit(candidate, existing HitResult) bool {
+	if hasLinkedInURL(candidate) != hasLinkedInURL(existing) {
+		return hasLinkedInURL(candidate)
+	}
+	if c, e := filledFields(candidate), filledFields(existing); c != e {
+		return c > e
+	}
+	return candidate.Timestamp.After(existing.Timestamp)
+}
+
+// filledFields counts non-empty profile fields
+func filledFields(entry HitResult) int {
+	count := 0
+	for _, v := range []string{entry.Name, entry.LinkedInURL, entry.Location, entry.Connections} {
+		if v != "" && v != "N/A" {
+			count++
+		}
+	}
+	return count
+}
+
+// fileStatIndex finds the report row for path
+func fileStatIndex(files []MergeFileStat, path string) int {
+	for i, f := range files {
+		if f.Path == path {
+			return i
+		}
+	}
+	return 0
+}
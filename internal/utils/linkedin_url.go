@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// NormalizeLinkedInURL returns the canonical form of a LinkedIn profile URL:
+// https scheme, www.linkedin.com host, lowercase path without tracking
+// params, fragment or trailing slash. Non-LinkedIn values are returned trimmed.
+func NormalizeLinkedInURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "N/A" {
+		return raw
+	}
+
+	candidate := raw
+	if !strings.Contains(candidate, "://") {
+		candidate = "https://" + candidate
+	}
+
+	u, err := url.Parse(candidate)
+	if err != nil {
+		return raw
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "linkedin.com" && !strings.HasSuffix(host, ".linkedin.com") {
+		return raw
+	}
+
+	path := strings.ToLower(u.EscapedPath())
+	path = strings.TrimRight(path, "/")
+	if path == "" {
+		return raw
+	}
+
+	// Country subdomain (vn., uk., ...) và query tracking (trk, originalSubdomain, ...) đều bỏ
+	return "https://www.linkedin.com" + path
+}
+
+// MergedHit groups all hit entries that point to the same LinkedIn profile
+type MergedHit struct {
+	CanonicalURL string
+	Name         string
+	Location     string
+	Connections  string
+	Emails       []string
+	Sources      []string
+}
+
+// GroupHitsByLinkedInURL groups entries by canonical LinkedIn URL.
+// Entries without a URL are kept as their own group keyed by email.
+// The most recent non-empty field wins when entries disagree.
+func GroupHitsByLinkedInURL(entries []HitResult) []MergedHit {
+	groups := make(map[string]*MergedHit)
+	var order []string
+
+	for _, entry := range entries {
+		canonical := NormalizeLinkedInURL(entry.LinkedInURL)
+		key := canonical
+		if key == "" || key == "N/A" {
+			key = "email:" + strings.ToLower(strings.TrimSpace(entry.Email))
+		}
+
+		group, exists := groups[key]
+		if !exists {
+			group = &MergedHit{CanonicalURL: canonical}
+			groups[key] = group
+			order = append(order, key)
+		}
+
+		if entry.Name != "" {
+			group.Name = entry.Name
+		}
+		if entry.Location != "" {
+			group.Location = entry.Location
+		}
+		if entry.Connections != "" {
+			group.Connections = entry.Connections
+		}
+		group.Emails = appendUnique(group.Emails, entry.Email)
+		if entry.Source != "" {
+			group.Sources = appendUnique(group.Sources, entry.Source)
+		}
+	}
+
+	merged := make([]MergedHit, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		sort.Strings(group.Emails)
+		merged = append(merged, *group)
+	}
+
+	return merged
+}
+
+// appendUnique appends value if not already present (case-insensitive)
+func appendUnique(values []string, value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return values
+		}
+	}
+	return append(values, value)
+}
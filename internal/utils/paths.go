@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"linkedin-crawler/internal/models"
+)
+
+// unsafePathChars matches characters not allowed in a template value
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExpandPathTemplate replaces {campaign}, {mode}, {date} and {time} in a path template
+func ExpandPathTemplate(template string, cfg models.Config, now time.Time) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+
+	campaign := unsafePathChars.ReplaceAllString(strings.TrimSpace(cfg.Campaign), "_")
+	if campaign == "" {
+		campaign = "default"
+	}
+	mode := cfg.CrawlMode
+	if mode == "" {
+		mode = models.CrawlModeEmail
+	}
+
+	replacer := strings.NewReplacer(
+		"{campaign}", campaign,
+		"{mode}", mode,
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("150405"),
+	)
+	return replacer.Replace(template)
+}
+
+// PathTemplateGlob returns a glob matching every path template expands to for
+// the campaign of cfg, whatever the mode, date and time
+func PathTemplateGlob(template string, cfg models.Config) string {
+	template = strings.NewReplacer("{mode}", "*", "{date}", "*", "{time}", "*").Replace(template)
+	return ExpandPathTemplate(template, cfg, time.Time{})
+}
+
+// ResolveConfigPaths expands templates in every file path of cfg and creates
+// the parent directories so storage can open the files directly
+func ResolveConfigPaths(cfg models.Config) (models.Config, error) {
+	now := time.Now()
+
+	for _, p := range configPaths(&cfg) {
+		if *p == "" {
+			continue
+		}
+		*p = ExpandPathTemplate(*p, cfg, now)
+
+		if dir := filepath.Dir(*p); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return cfg, fmt.Errorf("failed to create directory %s: %w", dir, err)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// WithResolvedPaths returns cfg with the file paths of resolved, a config
+// returned earlier by ResolveConfigPaths, so they stay the same files when
+// {date} or {time} would expand differently now
+func WithResolvedPaths(cfg, resolved models.Config) models.Config {
+	from := configPaths(&resolved)
+	for i, p := range configPaths(&cfg) {
+		*p = *from[i]
+	}
+	return cfg
+}
+
+// configPaths returns the file path fields of cfg that may hold templates
+func configPaths(cfg *models.Config) []*string {
+	return []*string{
+		&cfg.EmailsFilePath,
+		&cfg.TokensFilePath,
+		&cfg.AccountsFilePath,
+		&cfg.OutputFilePath,
+		&cfg.DBPath,
+		&cfg.LogFilePath,
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"linkedin-crawler/internal/models"
+)
+
+// emailPattern matches the email addresses inside a log line
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// PrivateEmail hides email according to mode (see models.LogPrivacyModes):
+// mask keeps the first character and the domain (j***@example.com), hash
+// gives a short stable digest (email#1a2b3c4d5e) so one address can still be
+// followed through a log
+func PrivateEmail(email, mode string) string {
+	switch mode {
+	case models.LogPrivacyMask:
+		at := strings.LastIndex(email, "@")
+		if at <= 0 {
+			return "***"
+		}
+		return email[:1] + "***" + email[at:]
+	case models.LogPrivacyHash:
+		sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+		return "email#" + hex.EncodeToString(sum[:5])
+	default:
+		return email
+	}
+}
+
+// PrivateText applies PrivateEmail to every email address in text
+func PrivateText(text, mode string) string {
+	if mode != models.LogPrivacyMask && mode != models.LogPrivacyHash {
+		return text
+	}
+	return emailPattern.ReplaceAllStringFunc(text, func(email string) string {
+		return PrivateEmail(email, mode)
+	})
+}
+
+// FilterPrivateConsole routes stdout through PrivateText. The returned
+// function restores stdout and waits until the filtered output is written.
+func FilterPrivateConsole(mode string) (func(), error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter console: %w", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			fmt.Fprintln(stdout, PrivateText(scanner.Text(), mode))
+		}
+	}()
+
+	return func() {
+		os.Stdout = stdout
+		writer.Close()
+		<-done
+	}, nil
+}
@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Connection buckets, từ thấp đến cao
+const (
+	ConnectionsUnknown  = "Unknown"
+	Connections0To49    = "0-49"
+	Connections50To199  = "50-199"
+	Connections200To499 = "200-499"
+	Connections500Plus  = "500+"
+)
+
+// ConnectionBuckets lists buckets in display order
+var ConnectionBuckets = []string{
+	Connections0To49,
+	Connections50To199,
+	Connections200To499,
+	Connections500Plus,
+	ConnectionsUnknown,
+}
+
+// Regions used by NormalizeLocation
+const (
+	RegionAPAC     = "APAC"
+	RegionEMEA     = "EMEA"
+	RegionAmericas = "Americas"
+)
+
+// Regions lists known regions in display order
+var Regions = []string{RegionAPAC, RegionEMEA, RegionAmericas}
+
+type countryInfo struct {
+	Country string
+	Region  string
+}
+
+// countryAliases maps lowercase country names/codes to a canonical country
+var countryAliases = map[string]countryInfo{
+	"vietnam":                  {"Vietnam", RegionAPAC},
+	"viet nam":                 {"Vietnam", RegionAPAC},
+	"việt nam":                 {"Vietnam", RegionAPAC},
+	"vn":                       {"Vietnam", RegionAPAC},
+	"singapore":                {"Singapore", RegionAPAC},
+	"thailand":                 {"Thailand", RegionAPAC},
+	"malaysia":                 {"Malaysia", RegionAPAC},
+	"indonesia":                {"Indonesia", RegionAPAC},
+	"philippines":              {"Philippines", RegionAPAC},
+	"japan":                    {"Japan", RegionAPAC},
+	"south korea":              {"South Korea", RegionAPAC},
+	"korea":                    {"South Korea", RegionAPAC},
+	"china":                    {"China", RegionAPAC},
+	"hong kong":                {"Hong Kong", RegionAPAC},
+	"hong kong sar":            {"Hong Kong", RegionAPAC},
+	"taiwan":                   {"Taiwan", RegionAPAC},
+	"india":                    {"India", RegionAPAC},
+	"australia":                {"Australia", RegionAPAC},
+	"new zealand":              {"New Zealand", RegionAPAC},
+	"united kingdom":           {"United Kingdom", RegionEMEA},
+	"uk":                       {"United Kingdom", RegionEMEA},
+	"england":                  {"United Kingdom", RegionEMEA},
+	"ireland":                  {"Ireland", RegionEMEA},
+	"germany":                  {"Germany", RegionEMEA},
+	"france":                   {"France", RegionEMEA},
+	"netherlands":              {"Netherlands", RegionEMEA},
+	"spain":                    {"Spain", RegionEMEA},
+	"italy":                    {"Italy", RegionEMEA},
+	"switzerland":              {"Switzerland", RegionEMEA},
+	"sweden":                   {"Sweden", RegionEMEA},
+	"poland":                   {"Poland", RegionEMEA},
+	"united arab emirates":     {"United Arab Emirates", RegionEMEA},
+	"uae":                      {"United Arab Emirates", RegionEMEA},
+	"israel":                   {"Israel", RegionEMEA},
+	"south africa":             {"South Africa", RegionEMEA},
+	"united states":            {"United States", RegionAmericas},
+	"united states of america": {"United States", RegionAmericas},
+	"usa":                      {"United States", RegionAmericas},
+	"us":                       {"United States", RegionAmericas},
+	"canada":                   {"Canada", RegionAmericas},
+	"mexico":                   {"Mexico", RegionAmericas},
+	"brazil":                   {"Brazil", RegionAmericas},
+	"argentina":                {"Argentina", RegionAmericas},
+}
+
+// cityCountries maps well-known cities/areas (lowercase) to a country alias
+var cityCountries = map[string]string{
+	"hanoi":                  "vietnam",
+	"ha noi":                 "vietnam",
+	"hà nội":                 "vietnam",
+	"ho chi minh city":       "vietnam",
+	"ho chi minh":            "vietnam",
+	"hồ chí minh":            "vietnam",
+	"saigon":                 "vietnam",
+	"da nang":                "vietnam",
+	"đà nẵng":                "vietnam",
+	"bangkok":                "thailand",
+	"kuala lumpur":           "malaysia",
+	"jakarta":                "indonesia",
+	"manila":                 "philippines",
+	"tokyo":                  "japan",
+	"seoul":                  "south korea",
+	"shanghai":               "china",
+	"beijing":                "china",
+	"bangalore":              "india",
+	"bengaluru":              "india",
+	"sydney":                 "australia",
+	"melbourne":              "australia",
+	"london":                 "united kingdom",
+	"greater london":         "united kingdom",
+	"berlin":                 "germany",
+	"paris":                  "france",
+	"amsterdam":              "netherlands",
+	"dubai":                  "united arab emirates",
+	"new york":               "united states",
+	"new york city":          "united states",
+	"san francisco bay area": "united states",
+	"san francisco":          "united states",
+	"seattle":                "united states",
+	"los angeles":            "united states",
+	"toronto":                "canada",
+	"vancouver":              "canada",
+}
+
+// ParseConnections converts a raw connection count ("500+", "1,234",
+// "87 connections") into a number; it returns -1 when unknown
+func ParseConnections(raw string) int {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, "N/A") {
+		return -1
+	}
+
+	var digits strings.Builder
+	for _, r := range raw {
+		if unicode.IsDigit(r) {
+			digits.WriteRune(r)
+		} else if r != ',' && r != '.' && digits.Len() > 0 {
+			break
+		}
+	}
+	if digits.Len() == 0 {
+		return -1
+	}
+
+	n, err := strconv.Atoi(digits.String())
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// ConnectionBucket returns the bucket for a parsed connection count
+func ConnectionBucket(n int) string {
+	switch {
+	case n < 0:
+		return ConnectionsUnknown
+	case n < 50:
+		return Connections0To49
+	case n < 200:
+		return Connections50To199
+	case n < 500:
+		return Connections200To499
+	default:
+		return Connections500Plus
+	}
+}
+
+// NormalizeLocation maps a free-form location ("Hanoi, Vietnam",
+// "San Francisco Bay Area") to a canonical country and region.
+// Unknown locations return empty strings.
+func NormalizeLocation(raw string) (country, region string) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, "N/A") {
+		return "", ""
+	}
+
+	parts := strings.Split(raw, ",")
+	// Phần cuối thường là quốc gia, duyệt ngược để ưu tiên nó
+	for i := len(parts) - 1; i >= 0; i-- {
+		key := strings.ToLower(strings.TrimSpace(parts[i]))
+		if info, ok := countryAliases[key]; ok {
+			return info.Country, info.Region
+		}
+		for _, candidate := range []string{key, trimAreaWords(key)} {
+			if alias, ok := cityCountries[candidate]; ok {
+				info := countryAliases[alias]
+				return info.Country, info.Region
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// trimAreaWords strips "Greater ... Area" style decorations from a city name
+func trimAreaWords(key string) string {
+	key = strings.TrimPrefix(key, "greater ")
+	key = strings.TrimSuffix(key, " area")
+	key = strings.TrimSuffix(key, " metropolitan")
+	return strings.TrimSpace(key)
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RotatingWriter appends to a hit file and, once it reaches maxBytes, moves it
+// aside as hit-0001.txt, hit-0002.txt... and starts a fresh hit file. The
+// active file therefore always keeps the configured path.
+type RotatingWriter struct {
+	path     string
+	maxBytes int64 // 0 = không giới hạn
+	file     *os.File
+	size     int64
+	mutex    sync.Mutex
+}
+
+// NewRotatingWriter opens path for appending; maxBytes <= 0 disables rotation
+func NewRotatingWriter(path string, maxBytes int64) (*RotatingWriter, error) {
+	rw := &RotatingWriter{path: path, maxBytes: maxBytes}
+	if err := rw.open(); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+// open opens the active file in append mode and records its size
+func (rw *RotatingWriter) open() error {
+	file, err := os.OpenFile(rw.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat output file: %w", err)
+	}
+	rw.file = file
+	rw.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first when p would push the file past maxBytes
+func (rw *RotatingWriter) Write(p []byte) (int, error) {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+
+	if rw.file == nil {
+		return 0, os.ErrClosed
+	}
+	if rw.maxBytes > 0 && rw.size > 0 && rw.size+int64(len(p)) > rw.maxBytes {
+		if err := rw.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rw.file.Write(p)
+	rw.size += int64(n)
+	return n, err
+}
+
+// rotate moves the active file to the next free hit-NNNN name and reopens it
+func (rw *RotatingWriter) rotate() error {
+	if err := rw.file.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+	rw.file = nil
+
+	parts, err := RotatedHitFiles(rw.path)
+	if err != nil {
+		return err
+	}
+	target := RotatedHitPath(rw.path, len(parts)+1)
+	if err := os.Rename(rw.path, target); err != nil {
+		return fmt.Errorf("failed to rotate output file: %w", err)
+	}
+	fmt.Printf("📦 Hit file đạt giới hạn, đã chuyển sang %s\n", target)
+
+	return rw.open()
+}
+
+// Sync commits the active file to disk
+func (rw *RotatingWriter) Sync() error {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+	if rw.file == nil {
+		return os.ErrClosed
+	}
+	return rw.file.Sync()
+}
+
+// Close closes the active file
+func (rw *RotatingWriter) Close() error {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+	if rw.file == nil {
+		return nil
+	}
+	err := rw.file.Close()
+	rw.file = nil
+	return err
+}
+
+// RotatedHitPath returns the name of rotated part n of a hit file (hit-0001.txt)
+func RotatedHitPath(path string, n int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%04d%s", strings.TrimSuffix(path, ext), n, ext)
+}
+
+// RotatedHitFiles lists the rotated parts of a hit file in order, oldest first
+func RotatedHitFiles(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	matches, err := filepath.Glob(strings.TrimSuffix(path, ext) + "-[0-9][0-9][0-9][0-9]" + ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rotated hit files: %w", err)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// HitFileParts returns the rotated parts followed by the active hit file
+func HitFileParts(path string) []string {
+	parts, _ := RotatedHitFiles(path)
+	return append(parts, path)
+}
+
+// ReadAllHitResults reads entries from every part of a hit file, oldest first
+func ReadAllHitResults(path string) ([]HitResult, error) {
+	var all []HitResult
+	found := false
+	for _, part := range HitFileParts(path) {
+		entries, err := readHitFile(part)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		found = true
+		all = append(all, entries...)
+	}
+	if !found {
+		return nil, fmt.Errorf("hit file %s does not exist", path)
+	}
+	return all, nil
+}
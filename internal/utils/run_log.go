@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// runLogDirName là thư mục chứa log của từng lần chạy, nằm cạnh crawler log
+const runLogDirName = "logs"
+
+// RunLog is one per-run log file
+type RunLog struct {
+	RunID   string
+	Path    string
+	ModTime time.Time
+	Size    int64
+}
+
+// NewRunID returns an ID for a crawl run based on its start time
+func NewRunID(now time.Time) string {
+	return now.Format("20060102-150405")
+}
+
+// RunLogDir returns the directory holding the per-run logs of logFilePath
+func RunLogDir(logFilePath string) string {
+	return filepath.Join(filepath.Dir(logFilePath), runLogDirName)
+}
+
+// RunLogPath returns the log file of run runID: logs/run-<id>.log next to logFilePath
+func RunLogPath(logFilePath, runID string) string {
+	return filepath.Join(RunLogDir(logFilePath), "run-"+runID+".log")
+}
+
+// ListRunLogs returns the per-run logs next to logFilePath, newest first
+func ListRunLogs(logFilePath string) ([]RunLog, error) {
+	entries, err := os.ReadDir(RunLogDir(logFilePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var logs []RunLog
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "run-") || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, RunLog{
+			RunID:   strings.TrimSuffix(strings.TrimPrefix(name, "run-"), ".log"),
+			Path:    filepath.Join(RunLogDir(logFilePath), name),
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+		})
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].ModTime.After(logs[j].ModTime)
+	})
+	return logs, nil
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"linkedin-crawler/internal/models"
+)
+
+// SessionReportTopDomains là số domain có nhiều hit nhất đưa vào session report
+const SessionReportTopDomains = 10
+
+// sessionReportTemplate là report HTML tự chứa (CSS inline) để gửi thẳng cho khách hàng
+var sessionReportTemplate = template.Must(template.New("session").Funcs(template.FuncMap{
+	"pct": func(v float64) string { return fmt.Sprintf("%.1f%%", v) },
+	"num": func(v float64) string { return fmt.Sprintf("%.0f", v) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Crawl Session Report{{if .RunID}} - {{.RunID}}{{end}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; max-width: 900px; margin: 32px auto; padding: 0 16px; }
+h1 { margin-bottom: 4px; }
+.muted { color: #777; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; margin: 24px 0; }
+.card { flex: 1 1 150px; border: 1px solid #ddd; border-radius: 6px; padding: 12px; }
+.card .value { font-size: 24px; font-weight: bold; }
+table { width: 100%; border-collapse: collapse; margin-bottom: 24px; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #eee; }
+th { background: #f5f5f5; }
+td.n { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Crawl Session Report</h1>
+<div class="muted">{{if .Campaign}}Campaign {{.Campaign}} · {{end}}{{if .RunID}}Run {{.RunID}} · {{end}}Generated {{.GeneratedAt.Format "2006-01-02 15:04"}}</div>
+
+<div class="cards">
+<div class="card"><div class="muted">Processed</div><div class="value">{{.Processed}}</div></div>
+<div class="card"><div class="muted">Profiles found</div><div class="value">{{.Hits}}</div></div>
+<div class="card"><div class="muted">Hit rate</div><div class="value">{{pct .HitRate}}</div></div>
+<div class="card"><div class="muted">Duration</div><div class="value">{{.DurationText}}</div></div>
+</div>
+
+<h2>Totals</h2>
+<table>
+<tr><th>Metric</th><th>Value</th></tr>
+<tr><td>Started</td><td>{{.StartedAt.Format "2006-01-02 15:04:05"}}</td></tr>
+<tr><td>Finished</td><td>{{.FinishedAt.Format "2006-01-02 15:04:05"}}</td></tr>
+<tr><td>Profiles found</td><td>{{.Hits}}</td></tr>
+<tr><td>No profile</td><td>{{.NoInfo}}</td></tr>
+<tr><td>Failed</td><td>{{.Failed}}</td></tr>
+<tr><td>Still pending</td><td>{{.Pending}}</td></tr>
+<tr><td>Emails per hour</td><td>{{num .EmailsPerHour}}</td></tr>
+</table>
+
+<h2>Configuration</h2>
+<table>
+<tr><th>Setting</th><th>Value</th></tr>
+<tr><td>Crawl mode</td><td>{{.CrawlMode}}</td></tr>
+<tr><td>Concurrency</td><td>{{.MaxConcurrency}}</td></tr>
+<tr><td>Requests/sec</td><td>{{printf "%.1f" .RequestsPerSec}}</td></tr>
+<tr><td>Pacing</td><td>{{.PacingProfile}}</td></tr>
+<tr><td>Requests sent</td><td>{{.Requests}} ({{.RateLimited}} rate limited)</td></tr>
+<tr><td>Account logins</td><td>{{.LoginAttempts}} ({{.TokensObtained}} tokens)</td></tr>
+</table>
+{{if .LicenseType}}
+<h2>License</h2>
+<table>
+<tr><th>Item</th><th>Value</th></tr>
+<tr><td>Type</td><td>{{.LicenseType}}</td></tr>
+{{if .LicenseHolder}}<tr><td>Holder</td><td>{{.LicenseHolder}}</td></tr>{{end}}
+<tr><td>Usage</td><td>{{.LicenseUsageText}}</td></tr>
+{{if .LicenseExpires}}<tr><td>Expires</td><td>{{.LicenseExpires}}</td></tr>{{end}}
+</table>
+{{end}}
+{{if .TopDomains}}
+<h2>Top domains</h2>
+<table>
+<tr><th>Domain</th><th>Total</th><th>Profiles</th><th>Hit rate</th><th>Top country</th></tr>
+{{range .TopDomains}}<tr><td>{{.Domain}}</td><td class="n">{{.Total}}</td><td class="n">{{.Hits}}</td><td class="n">{{pct .HitRate}}</td><td>{{.TopCountry}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+// sessionReportView adds the derived values the templates need
+type sessionReportView struct {
+	models.SessionReport
+}
+
+// DurationText returns the run duration for display
+func (v sessionReportView) DurationText() string {
+	return FormatDuration(v.Duration())
+}
+
+// EmailsPerHour returns processed emails per hour
+func (v sessionReportView) EmailsPerHour() float64 {
+	hours := v.Duration().Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return float64(v.Processed) / hours
+}
+
+// LicenseUsageText returns "used / limit" or "used (unlimited)"
+func (v sessionReportView) LicenseUsageText() string {
+	if v.LicenseLimit <= 0 {
+		return fmt.Sprintf("%d emails (unlimited)", v.LicenseUsed)
+	}
+	return fmt.Sprintf("%d / %d emails (%.1f%%)", v.LicenseUsed, v.LicenseLimit, float64(v.LicenseUsed)*100/float64(v.LicenseLimit))
+}
+
+// FormatSessionReportHTML renders the session report as a standalone HTML page
+func FormatSessionReportHTML(report models.SessionReport) (string, error) {
+	var b strings.Builder
+	if err := sessionReportTemplate.Execute(&b, sessionReportView{report}); err != nil {
+		return "", fmt.Errorf("failed to render session report: %w", err)
+	}
+	return b.String(), nil
+}
+
+// FormatSessionReportText renders the session report as plain text lines
+func FormatSessionReportText(report models.SessionReport) []string {
+	v := sessionReportView{report}
+	lines := []string{
+		"Crawl Session Report",
+		fmt.Sprintf("Generated %s", v.GeneratedAt.Format("2006-01-02 15:04")),
+	}
+	if v.Campaign != "" {
+		lines = append(lines, "Campaign: "+v.Campaign)
+	}
+	if v.RunID != "" {
+		lines = append(lines, "Run: "+v.RunID)
+	}
+
+	lines = append(lines, "",
+		"TOTALS",
+		fmt.Sprintf("  Started:          %s", v.StartedAt.Format("2006-01-02 15:04:05")),
+		fmt.Sprintf("  Duration:         %s", v.DurationText()),
+		fmt.Sprintf("  Processed:        %d", v.Processed),
+		fmt.Sprintf("  Profiles found:   %d (hit rate %.1f%%)", v.Hits, v.HitRate()),
+		fmt.Sprintf("  No profile:       %d", v.NoInfo),
+		fmt.Sprintf("  Failed:           %d", v.Failed),
+		fmt.Sprintf("  Still pending:    %d", v.Pending),
+		fmt.Sprintf("  Emails per hour:  %.0f", v.EmailsPerHour()),
+		"",
+		"CONFIGURATION",
+		fmt.Sprintf("  Crawl mode:       %s", v.CrawlMode),
+		fmt.Sprintf("  Concurrency:      %d", v.MaxConcurrency),
+		fmt.Sprintf("  Requests/sec:     %.1f", v.RequestsPerSec),
+		fmt.Sprintf("  Pacing:           %s", v.PacingProfile),
+		fmt.Sprintf("  Requests sent:    %d (%d rate limited)", v.Requests, v.RateLimited),
+		fmt.Sprintf("  Account logins:   %d (%d tokens)", v.LoginAttempts, v.TokensObtained),
+	)
+
+	if v.LicenseType != "" {
+		lines = append(lines, "", "LICENSE", "  Type:             "+v.LicenseType)
+		if v.LicenseHolder != "" {
+			lines = append(lines, "  Holder:           "+v.LicenseHolder)
+		}
+		lines = append(lines, "  Usage:            "+v.LicenseUsageText())
+		if v.LicenseExpires != "" {
+			lines = append(lines, "  Expires:          "+v.LicenseExpires)
+		}
+	}
+
+	if len(v.TopDomains) > 0 {
+		lines = append(lines, "", "TOP DOMAINS",
+			fmt.Sprintf("  %-32s %8s %8s %8s  %s", "Domain", "Total", "Hits", "Rate", "Country"))
+		for _, d := range v.TopDomains {
+			lines = append(lines, fmt.Sprintf("  %-32s %8d %8d %7.1f%%  %s", d.Domain, d.Total, d.Hits, d.HitRate(), d.TopCountry))
+		}
+	}
+	return lines
+}
+
+// PDF layout: A4, Courier 10pt để các cột text thẳng hàng
+const (
+	pdfPageWidth    = 595
+	pdfPageHeight   = 842
+	pdfMargin       = 50
+	pdfFontSize     = 10
+	pdfLineHeight   = 14
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+)
+
+// FormatSessionReportPDF renders the text report as a minimal PDF. Ký tự
+// ngoài Latin-1 được thay bằng "?" vì dùng font chuẩn không nhúng.
+func FormatSessionReportPDF(report models.SessionReport) []byte {
+	lines := FormatSessionReportText(report)
+	var pages [][]string
+	for len(lines) > 0 {
+		n := min(len(lines), pdfLinesPerPage)
+		pages = append(pages, lines[:n])
+		lines = lines[n:]
+	}
+
+	// Object 1: catalog, 2: pages, 3: font, rồi mỗi trang một cặp page + content
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	)
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLineHeight, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", pdfEscape(line))
+		}
+		content.WriteString("ET")
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info << /Producer (linkedin-crawler) /CreationDate (D:%s) >> >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, report.GeneratedAt.Format("20060102150405"), xref)
+	return out.Bytes()
+}
+
+// pdfEscape escapes a PDF string literal and maps runes to Latin-1 bytes
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 256:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// SessionReportFileName returns the default file name of a session report
+func SessionReportFileName(report models.SessionReport, ext string) string {
+	id := report.RunID
+	if id == "" {
+		id = report.GeneratedAt.Format("20060102-150405")
+	}
+	return fmt.Sprintf("session-report-%s.%s", id, ext)
+}
@@ -6,11 +6,11 @@ import (
 	"os/signal"
 	"sync/atomic"
 	"syscall"
-	"time"
 )
 
-// SetupSignalHandling sets up signal handling for graceful shutdown
-func SetupSignalHandling(shutdownRequested *int32, onShutdown func(), sleepDuration time.Duration) {
+// SetupSignalHandling sets up signal handling for graceful shutdown:
+// onShutdown runs until the state is saved, then the process exits
+func SetupSignalHandling(shutdownRequested *int32, onShutdown func()) {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 	go func() {
@@ -21,9 +21,6 @@ func SetupSignalHandling(shutdownRequested *int32, onShutdown func(), sleepDurat
 		if onShutdown != nil {
 			onShutdown()
 		}
-
-		fmt.Printf("💤 Sleep %v trước khi thoát...\n", sleepDuration)
-		time.Sleep(sleepDuration)
 		os.Exit(0)
 	}()
 }
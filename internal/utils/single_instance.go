@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// activateMessage is what a later instance sends to the running one
+const activateMessage = "activate"
+
+// singleInstanceTimeout bounds each connection between two instances
+const singleInstanceTimeout = time.Second
+
+// ErrAlreadyRunning is returned by ListenSingleInstance when another process
+// of the app is running; it was asked to show its window
+var ErrAlreadyRunning = errors.New("another instance is already running")
+
+// SingleInstance is the local socket the first instance of an app listens on;
+// later instances connect to it to hand over instead of starting
+type SingleInstance struct {
+	listener net.Listener
+}
+
+// SingleInstancePath returns the socket path of app in the user config
+// directory. Unix domain sockets also work on Windows 10 and later.
+func SingleInstancePath(app string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	dir := filepath.Join(configDir, "linkedin-crawler")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return filepath.Join(dir, app+".sock"), nil
+}
+
+// ListenSingleInstance listens on path and calls onActivate each time another
+// instance starts. When a live instance already listens it is asked to
+// activate and ErrAlreadyRunning is returned; a socket left by a crashed
+// instance is replaced.
+func ListenSingleInstance(path string, onActivate func()) (*SingleInstance, error) {
+	if conn, err := net.DialTimeout("unix", path, singleInstanceTimeout); err == nil {
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(singleInstanceTimeout))
+		if _, err := conn.Write([]byte(activateMessage + "\n")); err != nil {
+			return nil, fmt.Errorf("failed to reach the running instance: %w", err)
+		}
+		return nil, ErrAlreadyRunning
+	}
+
+	// Không ai nghe: socket (nếu có) là của instance đã crash
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	s := &SingleInstance{listener: listener}
+	go s.serve(onActivate)
+	return s, nil
+}
+
+// serve answers the instances started later until Close
+func (s *SingleInstance) serve(onActivate func()) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetReadDeadline(time.Now().Add(singleInstanceTimeout))
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			if strings.TrimSpace(line) == activateMessage {
+				onActivate()
+			}
+		}()
+	}
+}
+
+// Close stops listening and removes the socket
+func (s *SingleInstance) Close() error {
+	return s.listener.Close()
+}
@@ -26,8 +26,9 @@ func main() {
 		fmt.Println("2. Generate batch license keys")
 		fmt.Println("3. Validate license key")
 		fmt.Println("4. Show license types info")
-		fmt.Println("5. Exit")
-		fmt.Print("\nEnter your choice (1-5): ")
+		fmt.Println("5. Generate clock reset code")
+		fmt.Println("6. Exit")
+		fmt.Print("\nEnter your choice (1-6): ")
 
 		choice, _ := reader.ReadString('\n')
 		choice = strings.TrimSpace(choice)
@@ -42,6 +43,8 @@ func main() {
 		case "4":
 			showLicenseTypesInfo()
 		case "5":
+			generateClockResetCode(reader)
+		case "6":
 			fmt.Println("Goodbye!")
 			return
 		default:
@@ -61,6 +64,10 @@ func generateSingleKey(reader *bufio.Reader) {
 	if licenseType == "" {
 		return
 	}
+	if licenseType == string(licensing.LicenseTypeAddon) {
+		generateAddonKey(reader)
+		return
+	}
 
 	// Get user name
 	fmt.Print("Enter user name (e.g., JOHN): ")
@@ -118,6 +125,10 @@ func generateBatchKeys(reader *bufio.Reader) {
 	if licenseType == "" {
 		return
 	}
+	if licenseType == string(licensing.LicenseTypeAddon) {
+		fmt.Println("❌ Add-on keys are issued per user - use option 1")
+		return
+	}
 
 	// Get validity days
 	validDays := getValidityDays(reader, licenseType)
@@ -172,6 +183,58 @@ func generateBatchKeys(reader *bufio.Reader) {
 	saveBatchToFile(keys, licenseType, validDays, count)
 }
 
+// generateAddonKey generates an extra-quota add-on key for one user
+func generateAddonKey(reader *bufio.Reader) {
+	fmt.Print("Enter extra emails (e.g., 10000): ")
+	quantityStr, _ := reader.ReadString('\n')
+	quantity, err := strconv.Atoi(strings.TrimSpace(quantityStr))
+	if err != nil || quantity <= 0 {
+		fmt.Println("❌ Invalid quantity")
+		return
+	}
+
+	fmt.Print("Enter license owner email: ")
+	email, _ := reader.ReadString('\n')
+	email = strings.TrimSpace(email)
+	if email == "" {
+		fmt.Println("❌ Email cannot be empty")
+		return
+	}
+
+	validDays := getValidityDays(reader, "addon")
+	if validDays <= 0 {
+		return
+	}
+
+	addonKey := licensing.GenerateAddonKey(quantity, email, validDays)
+
+	fmt.Println("\n✅ Add-on Key Generated Successfully!")
+	fmt.Println("====================================")
+	fmt.Printf("Issued to: %s\n", email)
+	fmt.Printf("Extra emails: +%d\n", quantity)
+	fmt.Printf("Expires: %s\n", time.Now().AddDate(0, 0, validDays).Format("2006-01-02"))
+	fmt.Println()
+	fmt.Printf("ADD-ON KEY:\n%s\n", addonKey)
+}
+
+// generateClockResetCode issues today's clock reset code for a customer's license key
+func generateClockResetCode(reader *bufio.Reader) {
+	fmt.Println("\n🕒 Generate Clock Reset Code")
+	fmt.Println("----------------------------")
+
+	fmt.Print("Enter customer's license key: ")
+	licenseKey, _ := reader.ReadString('\n')
+	licenseKey = strings.TrimSpace(licenseKey)
+
+	if _, err := licensing.NewLicenseManager().ValidateLicenseKey(licenseKey); err != nil {
+		fmt.Printf("❌ License validation failed: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\nRESET CODE (valid around %s UTC):\n%s\n",
+		time.Now().UTC().Format("2006-01-02"), licensing.GenerateClockResetCode(licenseKey, time.Now()))
+}
+
 // validateKey validates a license key
 func validateKey(reader *bufio.Reader) {
 	fmt.Println("\n🔍 Validate License Key")
@@ -188,6 +251,20 @@ func validateKey(reader *bufio.Reader) {
 
 	// Validate using license manager
 	lm := licensing.NewLicenseManager()
+	if strings.HasPrefix(strings.ToUpper(licenseKey), "ADDON-") {
+		addon, err := lm.ParseAddonKey(licenseKey)
+		if err != nil {
+			fmt.Printf("❌ Add-on validation failed: %v\n", err)
+			return
+		}
+		fmt.Println("\n✅ Add-on Key Valid!")
+		fmt.Println("====================")
+		fmt.Printf("Issued to: %s\n", addon.UserEmail)
+		fmt.Printf("Extra emails: +%d\n", addon.Emails)
+		fmt.Printf("Expires: %s\n", addon.ExpiresAt.Format("2006-01-02"))
+		return
+	}
+
 	info, err := lm.ValidateLicenseKey(licenseKey)
 
 	if err != nil {
@@ -251,6 +328,11 @@ func showLicenseTypesInfo() {
 	fmt.Println("   • Features: All features + advanced crawling, priority support")
 	fmt.Println("   • Best for: Businesses, large-scale operations")
 
+	fmt.Println("\n➕ ADD-ON Key:")
+	fmt.Println("   • Extra emails stacked on a TRIAL/PERSONAL license")
+	fmt.Println("   • Issued to the license owner's email")
+	fmt.Println("   • Format: ADDON-QUANTITY-EMAIL-EXPIRY-CHECKSUM")
+
 	fmt.Println("\n🔑 License Key Format:")
 	fmt.Println("   TYPE-USERNAME-EMAIL-EXPIRY-CHECKSUM")
 	fmt.Println("   Example: PRO-COMPANY-admin@company.com-20251201-ABC123")
@@ -263,7 +345,8 @@ func getLicenseType(reader *bufio.Reader) string {
 		fmt.Println("1. TRIAL (100 emails, 2 accounts)")
 		fmt.Println("2. PERSONAL (5,000 emails, 10 accounts)")
 		fmt.Println("3. PRO (unlimited)")
-		fmt.Print("Enter choice (1-3): ")
+		fmt.Println("4. ADD-ON (extra emails on top of a license)")
+		fmt.Print("Enter choice (1-4): ")
 
 		choice, _ := reader.ReadString('\n')
 		choice = strings.TrimSpace(choice)
@@ -275,6 +358,8 @@ func getLicenseType(reader *bufio.Reader) string {
 			return "personal"
 		case "3":
 			return "pro"
+		case "4":
+			return "addon"
 		default:
 			fmt.Println("❌ Invalid choice. Please try again.")
 		}
@@ -288,7 +373,7 @@ func getValidityDays(reader *bufio.Reader, licenseType string) int {
 	switch licenseType {
 	case "trial":
 		defaultDays = 30
-	case "personal", "pro":
+	case "personal", "pro", "addon":
 		defaultDays = 365
 	}
 